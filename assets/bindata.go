@@ -1,7 +1,16 @@
 // Code generated for package assets by go-bindata DO NOT EDIT. (@generated)
 // sources:
 // templates/cloudformation/iam_user_osdCcsAdmin.json
+// templates/policies/installer_policy.json
 // templates/policies/osd_scp_policy.json
+// templates/policies/sts_instance_controlplane_permission_policy.json
+// templates/policies/sts_instance_worker_permission_policy.json
+// templates/policies/sts_ocm_cloud_credential_operator_permission_policy.json
+// templates/policies/sts_ocm_ebs_csi_driver_operator_permission_policy.json
+// templates/policies/sts_ocm_image_registry_operator_permission_policy.json
+// templates/policies/sts_ocm_ingress_operator_permission_policy.json
+// templates/policies/sts_ocm_machine_api_operator_permission_policy.json
+// templates/policies/sts_support_permission_policy.json
 package assets
 
 import (
@@ -60,7 +69,9 @@ var _templatesCloudformationIam_user_osdccsadminJson = []byte(`{
       "Type": "AWS::IAM::User",
       "Properties": {
         "ManagedPolicyArns": [
-          "arn:aws:iam::aws:policy/AdministratorAccess"
+          {
+            "Fn::Sub": "arn:${AWS::Partition}:iam::aws:policy/AdministratorAccess"
+          }
         ],
         "UserName": "osdCcsAdmin"
       }
@@ -84,6 +95,252 @@ func templatesCloudformationIam_user_osdccsadminJson() (*asset, error) {
 	return a, nil
 }
 
+var _templatesPoliciesInstaller_policyJson = []byte(`{
+    "Version": "2012-10-17",
+    "Id": "Installer Permissions Policy Document",
+    "Statement": [
+        {
+            "Sid": "Stmt1600000000001",
+            "Effect": "Allow",
+            "Action": [
+                "ec2:AllocateAddress",
+                "ec2:AssociateAddress",
+                "ec2:AttachNetworkInterface",
+                "ec2:AuthorizeSecurityGroupEgress",
+                "ec2:AuthorizeSecurityGroupIngress",
+                "ec2:CopyImage",
+                "ec2:CreateNetworkInterface",
+                "ec2:CreateSecurityGroup",
+                "ec2:CreateTags",
+                "ec2:CreateVolume",
+                "ec2:DeleteSecurityGroup",
+                "ec2:DeleteSnapshot",
+                "ec2:DeleteTags",
+                "ec2:DeregisterImage",
+                "ec2:DescribeAccountAttributes",
+                "ec2:DescribeAddresses",
+                "ec2:DescribeAvailabilityZones",
+                "ec2:DescribeDhcpOptions",
+                "ec2:DescribeImages",
+                "ec2:DescribeInstanceAttribute",
+                "ec2:DescribeInstanceCreditSpecifications",
+                "ec2:DescribeInstances",
+                "ec2:DescribeInternetGateways",
+                "ec2:DescribeKeyPairs",
+                "ec2:DescribeNatGateways",
+                "ec2:DescribeNetworkAcls",
+                "ec2:DescribeNetworkInterfaces",
+                "ec2:DescribePrefixLists",
+                "ec2:DescribeRegions",
+                "ec2:DescribeRouteTables",
+                "ec2:DescribeSecurityGroups",
+                "ec2:DescribeSubnets",
+                "ec2:DescribeTags",
+                "ec2:DescribeVolumes",
+                "ec2:DescribeVpcAttribute",
+                "ec2:DescribeVpcClassicLink",
+                "ec2:DescribeVpcClassicLinkDnsSupport",
+                "ec2:DescribeVpcEndpoints",
+                "ec2:DescribeVpcs",
+                "ec2:GetEbsDefaultKmsKeyId",
+                "ec2:ModifyInstanceAttribute",
+                "ec2:ModifyNetworkInterfaceAttribute",
+                "ec2:ReleaseAddress",
+                "ec2:RevokeSecurityGroupEgress",
+                "ec2:RevokeSecurityGroupIngress",
+                "ec2:RunInstances",
+                "ec2:TerminateInstances"
+            ],
+            "Resource": [
+                "*"
+            ]
+        },
+        {
+            "Sid": "Stmt1600000000002",
+            "Effect": "Allow",
+            "Action": [
+                "ec2:AssociateDhcpOptions",
+                "ec2:AssociateRouteTable",
+                "ec2:AttachInternetGateway",
+                "ec2:CreateDhcpOptions",
+                "ec2:CreateInternetGateway",
+                "ec2:CreateNatGateway",
+                "ec2:CreateRoute",
+                "ec2:CreateRouteTable",
+                "ec2:CreateSubnet",
+                "ec2:CreateVpc",
+                "ec2:CreateVpcEndpoint",
+                "ec2:ModifySubnetAttribute",
+                "ec2:ModifyVpcAttribute",
+                "ec2:DeleteDhcpOptions",
+                "ec2:DeleteInternetGateway",
+                "ec2:DeleteNatGateway",
+                "ec2:DeleteNetworkInterface",
+                "ec2:DeleteRoute",
+                "ec2:DeleteRouteTable",
+                "ec2:DeleteSubnet",
+                "ec2:DeleteVpc",
+                "ec2:DeleteVpcEndpoints",
+                "ec2:DetachInternetGateway",
+                "ec2:DisassociateRouteTable",
+                "ec2:ReplaceRouteTableAssociation"
+            ],
+            "Resource": [
+                "*"
+            ]
+        },
+        {
+            "Sid": "Stmt1600000000003",
+            "Effect": "Allow",
+            "Action": [
+                "elasticloadbalancing:AddTags",
+                "elasticloadbalancing:ApplySecurityGroupsToLoadBalancer",
+                "elasticloadbalancing:AttachLoadBalancerToSubnets",
+                "elasticloadbalancing:ConfigureHealthCheck",
+                "elasticloadbalancing:CreateListener",
+                "elasticloadbalancing:CreateLoadBalancer",
+                "elasticloadbalancing:CreateLoadBalancerListeners",
+                "elasticloadbalancing:CreateTargetGroup",
+                "elasticloadbalancing:DeleteLoadBalancer",
+                "elasticloadbalancing:DeleteTargetGroup",
+                "elasticloadbalancing:DeregisterInstancesFromLoadBalancer",
+                "elasticloadbalancing:DeregisterTargets",
+                "elasticloadbalancing:DescribeInstanceHealth",
+                "elasticloadbalancing:DescribeListeners",
+                "elasticloadbalancing:DescribeLoadBalancerAttributes",
+                "elasticloadbalancing:DescribeLoadBalancers",
+                "elasticloadbalancing:DescribeTags",
+                "elasticloadbalancing:DescribeTargetGroupAttributes",
+                "elasticloadbalancing:DescribeTargetHealth",
+                "elasticloadbalancing:ModifyLoadBalancerAttributes",
+                "elasticloadbalancing:ModifyTargetGroup",
+                "elasticloadbalancing:ModifyTargetGroupAttributes",
+                "elasticloadbalancing:RegisterInstancesWithLoadBalancer",
+                "elasticloadbalancing:RegisterTargets",
+                "elasticloadbalancing:SetLoadBalancerPoliciesOfListener"
+            ],
+            "Resource": [
+                "*"
+            ]
+        },
+        {
+            "Sid": "Stmt1600000000004",
+            "Effect": "Allow",
+            "Action": [
+                "iam:AddRoleToInstanceProfile",
+                "iam:CreateInstanceProfile",
+                "iam:CreateRole",
+                "iam:DeleteInstanceProfile",
+                "iam:DeleteRole",
+                "iam:DeleteRolePolicy",
+                "iam:GetInstanceProfile",
+                "iam:GetRole",
+                "iam:GetRolePolicy",
+                "iam:GetUser",
+                "iam:ListInstanceProfilesForRole",
+                "iam:ListRoles",
+                "iam:ListUsers",
+                "iam:PassRole",
+                "iam:PutRolePolicy",
+                "iam:RemoveRoleFromInstanceProfile",
+                "iam:SimulatePrincipalPolicy",
+                "iam:TagRole"
+            ],
+            "Resource": [
+                "*"
+            ]
+        },
+        {
+            "Sid": "Stmt1600000000005",
+            "Effect": "Allow",
+            "Action": [
+                "route53:ChangeResourceRecordSets",
+                "route53:ChangeTagsForResource",
+                "route53:CreateHostedZone",
+                "route53:DeleteHostedZone",
+                "route53:GetChange",
+                "route53:GetHostedZone",
+                "route53:ListHostedZones",
+                "route53:ListHostedZonesByName",
+                "route53:ListResourceRecordSets",
+                "route53:ListTagsForResource",
+                "route53:UpdateHostedZoneComment"
+            ],
+            "Resource": [
+                "*"
+            ]
+        },
+        {
+            "Sid": "Stmt1600000000006",
+            "Effect": "Allow",
+            "Action": [
+                "s3:CreateBucket",
+                "s3:DeleteBucket",
+                "s3:GetAccelerateConfiguration",
+                "s3:GetBucketAcl",
+                "s3:GetBucketCors",
+                "s3:GetBucketLocation",
+                "s3:GetBucketLogging",
+                "s3:GetBucketObjectLockConfiguration",
+                "s3:GetBucketReplication",
+                "s3:GetBucketRequestPayment",
+                "s3:GetBucketTagging",
+                "s3:GetBucketVersioning",
+                "s3:GetBucketWebsite",
+                "s3:GetEncryptionConfiguration",
+                "s3:GetLifecycleConfiguration",
+                "s3:GetReplicationConfiguration",
+                "s3:ListBucket",
+                "s3:PutBucketAcl",
+                "s3:PutBucketTagging",
+                "s3:PutEncryptionConfiguration",
+                "s3:DeleteObject",
+                "s3:GetObject",
+                "s3:GetObjectAcl",
+                "s3:GetObjectTagging",
+                "s3:GetObjectVersion",
+                "s3:PutObject",
+                "s3:PutObjectAcl",
+                "s3:PutObjectTagging"
+            ],
+            "Resource": [
+                "*"
+            ]
+        },
+        {
+            "Sid": "Stmt1600000000007",
+            "Effect": "Allow",
+            "Action": [
+                "autoscaling:DescribeAutoScalingGroups",
+                "servicequotas:ListAWSDefaultServiceQuotas",
+                "servicequotas:GetServiceQuota",
+                "tag:GetResources",
+                "tag:UntagResources",
+                "sts:GetCallerIdentity"
+            ],
+            "Resource": [
+                "*"
+            ]
+        }
+    ]
+}
+`)
+
+func templatesPoliciesInstaller_policyJsonBytes() ([]byte, error) {
+	return _templatesPoliciesInstaller_policyJson, nil
+}
+
+func templatesPoliciesInstaller_policyJson() (*asset, error) {
+	bytes, err := templatesPoliciesInstaller_policyJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/policies/installer_policy.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _templatesPoliciesOsd_scp_policyJson = []byte(`{
     "Version": "2012-10-17",
     "Id": "OSD SCP Policy Document",
@@ -278,6 +535,358 @@ func templatesPoliciesOsd_scp_policyJson() (*asset, error) {
 	return a, nil
 }
 
+var _templatesPoliciesSts_instance_controlplane_permission_policyJson = []byte(`{
+    "Version": "2012-10-17",
+    "Statement": [
+        {
+            "Effect": "Allow",
+            "Action": [
+                "ec2:AttachVolume",
+                "ec2:AuthorizeSecurityGroupIngress",
+                "ec2:CreateSecurityGroup",
+                "ec2:CreateTags",
+                "ec2:CreateVolume",
+                "ec2:DeleteSecurityGroup",
+                "ec2:DeleteVolume",
+                "ec2:Describe*",
+                "ec2:DetachVolume",
+                "ec2:ModifyInstanceAttribute",
+                "ec2:ModifyVolume",
+                "ec2:RevokeSecurityGroupIngress",
+                "elasticloadbalancing:AddTags",
+                "elasticloadbalancing:AttachLoadBalancerToSubnets",
+                "elasticloadbalancing:ApplySecurityGroupsToLoadBalancer",
+                "elasticloadbalancing:CreateListener",
+                "elasticloadbalancing:CreateLoadBalancer",
+                "elasticloadbalancing:CreateLoadBalancerPolicy",
+                "elasticloadbalancing:CreateLoadBalancerListeners",
+                "elasticloadbalancing:CreateTargetGroup",
+                "elasticloadbalancing:ConfigureHealthCheck",
+                "elasticloadbalancing:DeleteListener",
+                "elasticloadbalancing:DeleteLoadBalancer",
+                "elasticloadbalancing:DeleteLoadBalancerListeners",
+                "elasticloadbalancing:DeleteTargetGroup",
+                "elasticloadbalancing:DeregisterInstancesFromLoadBalancer",
+                "elasticloadbalancing:DeregisterTargets",
+                "elasticloadbalancing:Describe*",
+                "elasticloadbalancing:DetachLoadBalancerFromSubnets",
+                "elasticloadbalancing:ModifyListener",
+                "elasticloadbalancing:ModifyLoadBalancerAttributes",
+                "elasticloadbalancing:ModifyTargetGroup",
+                "elasticloadbalancing:ModifyTargetGroupAttributes",
+                "elasticloadbalancing:RegisterInstancesWithLoadBalancer",
+                "elasticloadbalancing:RegisterTargets",
+                "elasticloadbalancing:SetLoadBalancerPoliciesForBackendServer",
+                "elasticloadbalancing:SetLoadBalancerPoliciesOfListener",
+                "kms:DescribeKey"
+            ],
+            "Resource": "*"
+        }
+    ]
+}
+`)
+
+func templatesPoliciesSts_instance_controlplane_permission_policyJsonBytes() ([]byte, error) {
+	return _templatesPoliciesSts_instance_controlplane_permission_policyJson, nil
+}
+
+func templatesPoliciesSts_instance_controlplane_permission_policyJson() (*asset, error) {
+	bytes, err := templatesPoliciesSts_instance_controlplane_permission_policyJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/policies/sts_instance_controlplane_permission_policy.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesPoliciesSts_instance_worker_permission_policyJson = []byte(`{
+    "Version": "2012-10-17",
+    "Statement": [
+        {
+            "Effect": "Allow",
+            "Action": [
+                "ec2:DescribeInstances",
+                "ec2:DescribeRegions"
+            ],
+            "Resource": "*"
+        }
+    ]
+}
+`)
+
+func templatesPoliciesSts_instance_worker_permission_policyJsonBytes() ([]byte, error) {
+	return _templatesPoliciesSts_instance_worker_permission_policyJson, nil
+}
+
+func templatesPoliciesSts_instance_worker_permission_policyJson() (*asset, error) {
+	bytes, err := templatesPoliciesSts_instance_worker_permission_policyJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/policies/sts_instance_worker_permission_policy.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesPoliciesSts_ocm_cloud_credential_operator_permission_policyJson = []byte(`{
+    "Version": "2012-10-17",
+    "Statement": [
+        {
+            "Effect": "Allow",
+            "Action": [
+                "iam:GetUser",
+                "iam:GetUserPolicy",
+                "iam:ListAccessKeys"
+            ],
+            "Resource": "*"
+        }
+    ]
+}
+`)
+
+func templatesPoliciesSts_ocm_cloud_credential_operator_permission_policyJsonBytes() ([]byte, error) {
+	return _templatesPoliciesSts_ocm_cloud_credential_operator_permission_policyJson, nil
+}
+
+func templatesPoliciesSts_ocm_cloud_credential_operator_permission_policyJson() (*asset, error) {
+	bytes, err := templatesPoliciesSts_ocm_cloud_credential_operator_permission_policyJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/policies/sts_ocm_cloud_credential_operator_permission_policy.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesPoliciesSts_ocm_ebs_csi_driver_operator_permission_policyJson = []byte(`{
+    "Version": "2012-10-17",
+    "Statement": [
+        {
+            "Effect": "Allow",
+            "Action": [
+                "ec2:AttachVolume",
+                "ec2:CreateSnapshot",
+                "ec2:CreateTags",
+                "ec2:CreateVolume",
+                "ec2:DeleteSnapshot",
+                "ec2:DeleteTags",
+                "ec2:DeleteVolume",
+                "ec2:DescribeInstances",
+                "ec2:DescribeSnapshots",
+                "ec2:DescribeTags",
+                "ec2:DescribeVolumes",
+                "ec2:DescribeVolumesModifications",
+                "ec2:DetachVolume",
+                "ec2:ModifyVolume"
+            ],
+            "Resource": "*"
+        }
+    ]
+}
+`)
+
+func templatesPoliciesSts_ocm_ebs_csi_driver_operator_permission_policyJsonBytes() ([]byte, error) {
+	return _templatesPoliciesSts_ocm_ebs_csi_driver_operator_permission_policyJson, nil
+}
+
+func templatesPoliciesSts_ocm_ebs_csi_driver_operator_permission_policyJson() (*asset, error) {
+	bytes, err := templatesPoliciesSts_ocm_ebs_csi_driver_operator_permission_policyJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/policies/sts_ocm_ebs_csi_driver_operator_permission_policy.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesPoliciesSts_ocm_image_registry_operator_permission_policyJson = []byte(`{
+    "Version": "2012-10-17",
+    "Statement": [
+        {
+            "Effect": "Allow",
+            "Action": [
+                "s3:AbortMultipartUpload",
+                "s3:CreateBucket",
+                "s3:DeleteBucket",
+                "s3:DeleteObject",
+                "s3:GetBucketEncryption",
+                "s3:GetBucketLocation",
+                "s3:GetBucketPublicAccessBlock",
+                "s3:GetBucketTagging",
+                "s3:GetLifecycleConfiguration",
+                "s3:GetObject",
+                "s3:ListBucket",
+                "s3:ListBucketMultipartUploads",
+                "s3:PutBucketEncryption",
+                "s3:PutBucketPublicAccessBlock",
+                "s3:PutBucketTagging",
+                "s3:PutLifecycleConfiguration",
+                "s3:PutObject"
+            ],
+            "Resource": "*"
+        }
+    ]
+}
+`)
+
+func templatesPoliciesSts_ocm_image_registry_operator_permission_policyJsonBytes() ([]byte, error) {
+	return _templatesPoliciesSts_ocm_image_registry_operator_permission_policyJson, nil
+}
+
+func templatesPoliciesSts_ocm_image_registry_operator_permission_policyJson() (*asset, error) {
+	bytes, err := templatesPoliciesSts_ocm_image_registry_operator_permission_policyJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/policies/sts_ocm_image_registry_operator_permission_policy.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesPoliciesSts_ocm_ingress_operator_permission_policyJson = []byte(`{
+    "Version": "2012-10-17",
+    "Statement": [
+        {
+            "Effect": "Allow",
+            "Action": [
+                "elasticloadbalancing:DescribeLoadBalancers",
+                "route53:ChangeResourceRecordSets",
+                "route53:ListHostedZones",
+                "tag:GetResources"
+            ],
+            "Resource": "*"
+        }
+    ]
+}
+`)
+
+func templatesPoliciesSts_ocm_ingress_operator_permission_policyJsonBytes() ([]byte, error) {
+	return _templatesPoliciesSts_ocm_ingress_operator_permission_policyJson, nil
+}
+
+func templatesPoliciesSts_ocm_ingress_operator_permission_policyJson() (*asset, error) {
+	bytes, err := templatesPoliciesSts_ocm_ingress_operator_permission_policyJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/policies/sts_ocm_ingress_operator_permission_policy.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesPoliciesSts_ocm_machine_api_operator_permission_policyJson = []byte(`{
+    "Version": "2012-10-17",
+    "Statement": [
+        {
+            "Effect": "Allow",
+            "Action": [
+                "ec2:CreateTags",
+                "ec2:DescribeAvailabilityZones",
+                "ec2:DescribeDhcpOptions",
+                "ec2:DescribeImages",
+                "ec2:DescribeInstances",
+                "ec2:DescribeSecurityGroups",
+                "ec2:DescribeSubnets",
+                "ec2:DescribeVpcs",
+                "ec2:RunInstances",
+                "ec2:TerminateInstances",
+                "elasticloadbalancing:DescribeLoadBalancers",
+                "elasticloadbalancing:DescribeTargetGroups",
+                "elasticloadbalancing:RegisterInstancesWithLoadBalancer",
+                "elasticloadbalancing:RegisterTargets",
+                "iam:PassRole",
+                "iam:CreateServiceLinkedRole",
+                "kms:Decrypt",
+                "kms:Encrypt",
+                "kms:GenerateDataKey",
+                "kms:GenerateDataKeyWithoutPlainText",
+                "kms:DescribeKey",
+                "kms:RevokeGrant",
+                "kms:CreateGrant",
+                "kms:ListGrants"
+            ],
+            "Resource": "*"
+        }
+    ]
+}
+`)
+
+func templatesPoliciesSts_ocm_machine_api_operator_permission_policyJsonBytes() ([]byte, error) {
+	return _templatesPoliciesSts_ocm_machine_api_operator_permission_policyJson, nil
+}
+
+func templatesPoliciesSts_ocm_machine_api_operator_permission_policyJson() (*asset, error) {
+	bytes, err := templatesPoliciesSts_ocm_machine_api_operator_permission_policyJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/policies/sts_ocm_machine_api_operator_permission_policy.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesPoliciesSts_support_permission_policyJson = []byte(`{
+    "Version": "2012-10-17",
+    "Statement": [
+        {
+            "Effect": "Allow",
+            "Action": [
+                "cloudtrail:DescribeTrails",
+                "cloudtrail:LookupEvents",
+                "cloudwatch:GetMetricData",
+                "cloudwatch:GetMetricStatistics",
+                "cloudwatch:ListMetrics",
+                "ec2:DescribeAvailabilityZones",
+                "ec2:DescribeInstanceStatus",
+                "ec2:DescribeInstances",
+                "ec2:DescribeLoadBalancerAttributes",
+                "ec2:DescribeNatGateways",
+                "ec2:DescribeNetworkInterfaces",
+                "ec2:DescribeRouteTables",
+                "ec2:DescribeSecurityGroups",
+                "ec2:DescribeSubnets",
+                "ec2:DescribeVolumes",
+                "ec2:DescribeVpcs",
+                "elasticloadbalancing:DescribeLoadBalancers",
+                "elasticloadbalancing:DescribeTargetHealth",
+                "iam:GetRole",
+                "iam:ListRoles",
+                "route53:GetHostedZone",
+                "route53:ListHostedZones",
+                "route53:ListResourceRecordSets",
+                "s3:GetBucketLocation",
+                "s3:ListAllMyBuckets",
+                "s3:ListBucket"
+            ],
+            "Resource": "*"
+        }
+    ]
+}
+`)
+
+func templatesPoliciesSts_support_permission_policyJsonBytes() ([]byte, error) {
+	return _templatesPoliciesSts_support_permission_policyJson, nil
+}
+
+func templatesPoliciesSts_support_permission_policyJson() (*asset, error) {
+	bytes, err := templatesPoliciesSts_support_permission_policyJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/policies/sts_support_permission_policy.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -330,8 +939,17 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"templates/cloudformation/iam_user_osdCcsAdmin.json": templatesCloudformationIam_user_osdccsadminJson,
-	"templates/policies/osd_scp_policy.json":             templatesPoliciesOsd_scp_policyJson,
+	"templates/cloudformation/iam_user_osdCcsAdmin.json":                          templatesCloudformationIam_user_osdccsadminJson,
+	"templates/policies/installer_policy.json":                                    templatesPoliciesInstaller_policyJson,
+	"templates/policies/osd_scp_policy.json":                                      templatesPoliciesOsd_scp_policyJson,
+	"templates/policies/sts_instance_controlplane_permission_policy.json":         templatesPoliciesSts_instance_controlplane_permission_policyJson,
+	"templates/policies/sts_instance_worker_permission_policy.json":               templatesPoliciesSts_instance_worker_permission_policyJson,
+	"templates/policies/sts_ocm_cloud_credential_operator_permission_policy.json": templatesPoliciesSts_ocm_cloud_credential_operator_permission_policyJson,
+	"templates/policies/sts_ocm_ebs_csi_driver_operator_permission_policy.json":   templatesPoliciesSts_ocm_ebs_csi_driver_operator_permission_policyJson,
+	"templates/policies/sts_ocm_image_registry_operator_permission_policy.json":   templatesPoliciesSts_ocm_image_registry_operator_permission_policyJson,
+	"templates/policies/sts_ocm_ingress_operator_permission_policy.json":          templatesPoliciesSts_ocm_ingress_operator_permission_policyJson,
+	"templates/policies/sts_ocm_machine_api_operator_permission_policy.json":      templatesPoliciesSts_ocm_machine_api_operator_permission_policyJson,
+	"templates/policies/sts_support_permission_policy.json":                       templatesPoliciesSts_support_permission_policyJson,
 }
 
 // AssetDir returns the file names below a certain
@@ -380,7 +998,16 @@ var _bintree = &bintree{nil, map[string]*bintree{
 			"iam_user_osdCcsAdmin.json": &bintree{templatesCloudformationIam_user_osdccsadminJson, map[string]*bintree{}},
 		}},
 		"policies": &bintree{nil, map[string]*bintree{
-			"osd_scp_policy.json": &bintree{templatesPoliciesOsd_scp_policyJson, map[string]*bintree{}},
+			"installer_policy.json":                                    &bintree{templatesPoliciesInstaller_policyJson, map[string]*bintree{}},
+			"osd_scp_policy.json":                                      &bintree{templatesPoliciesOsd_scp_policyJson, map[string]*bintree{}},
+			"sts_instance_controlplane_permission_policy.json":         &bintree{templatesPoliciesSts_instance_controlplane_permission_policyJson, map[string]*bintree{}},
+			"sts_instance_worker_permission_policy.json":               &bintree{templatesPoliciesSts_instance_worker_permission_policyJson, map[string]*bintree{}},
+			"sts_ocm_cloud_credential_operator_permission_policy.json": &bintree{templatesPoliciesSts_ocm_cloud_credential_operator_permission_policyJson, map[string]*bintree{}},
+			"sts_ocm_ebs_csi_driver_operator_permission_policy.json":   &bintree{templatesPoliciesSts_ocm_ebs_csi_driver_operator_permission_policyJson, map[string]*bintree{}},
+			"sts_ocm_image_registry_operator_permission_policy.json":   &bintree{templatesPoliciesSts_ocm_image_registry_operator_permission_policyJson, map[string]*bintree{}},
+			"sts_ocm_ingress_operator_permission_policy.json":          &bintree{templatesPoliciesSts_ocm_ingress_operator_permission_policyJson, map[string]*bintree{}},
+			"sts_ocm_machine_api_operator_permission_policy.json":      &bintree{templatesPoliciesSts_ocm_machine_api_operator_permission_policyJson, map[string]*bintree{}},
+			"sts_support_permission_policy.json":                       &bintree{templatesPoliciesSts_support_permission_policyJson, map[string]*bintree{}},
 		}},
 	}},
 }}
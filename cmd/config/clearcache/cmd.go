@@ -0,0 +1,49 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clearcache
+
+import (
+	"github.com/spf13/cobra"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
+	"github.com/openshift/moactl/pkg/ocm/cache"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "clear-cache",
+	Short: "Remove the cached OCM responses",
+	Long: "Remove the local cache of OCM responses, like the identifiers of clusters and the " +
+		"available versions, of all the profiles. Use the '--no-cache' flag to bypass the cache " +
+		"for a single command instead.",
+	Example: `  # Remove the cached OCM responses
+  rosa config clear-cache`,
+	Args: cobra.NoArgs,
+	Run:  run,
+}
+
+func run(_ *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+
+	err := cache.Clear()
+	if err != nil {
+		reporter.Errorf("%v", err)
+		exit.Exit(rerrors.ExitCodeGeneric)
+	}
+	reporter.Infof("Removed the cached OCM responses")
+}
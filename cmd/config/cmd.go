@@ -0,0 +1,38 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/cmd/config/clearcache"
+	"github.com/openshift/moactl/cmd/config/get"
+	"github.com/openshift/moactl/cmd/config/set"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "config COMMAND",
+	Short: "Manage the settings of the command line client",
+	Long: "Manage the settings of the command line client, like the current profile and the " +
+		"defaults used when a flag isn't given.",
+}
+
+func init() {
+	Cmd.AddCommand(clearcache.Cmd)
+	Cmd.AddCommand(get.Cmd)
+	Cmd.AddCommand(set.Cmd)
+}
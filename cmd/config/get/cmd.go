@@ -0,0 +1,77 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package get
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/config"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "get [KEY]",
+	Short: "Show the value of a setting",
+	Long: fmt.Sprintf("Show the value of a setting of the current profile, or of all the settings "+
+		"if no key is given.\n\nThe valid keys are: %s.", strings.Join(config.Keys, ", ")),
+	Example: `  # Show the current profile
+  rosa config get profile
+
+  # Show the default region of the 'staging' profile
+  rosa config get region --ocm-profile staging`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  run,
+}
+
+func run(_ *cobra.Command, argv []string) {
+	reporter := rprtr.CreateReporterOrExit()
+
+	cfg, err := config.Load()
+	if err != nil {
+		reporter.Errorf("Failed to load config file: %v", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	profile, err := config.CurrentProfile()
+	if err != nil {
+		reporter.Errorf("Failed to get current profile: %v", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	if len(argv) == 1 {
+		value, err := cfg.Get(profile, argv[0])
+		if err != nil {
+			reporter.Errorf("%v", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		fmt.Println(value)
+		return
+	}
+
+	for _, key := range config.Keys {
+		value, err := cfg.Get(profile, key)
+		if err != nil {
+			reporter.Errorf("%v", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		fmt.Printf("%s: %s\n", key, value)
+	}
+}
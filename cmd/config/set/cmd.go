@@ -0,0 +1,96 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package set
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/config"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "set KEY [VALUE]",
+	Short: "Change the value of a setting",
+	Long: fmt.Sprintf("Change the value of a setting. Settings other than the profile are saved "+
+		"for the current profile, if any. If no value is given the setting is removed.\n\n"+
+		"The 'cluster', 'region' and 'output' settings are used by the commands that have the "+
+		"flag with the same name when it isn't given, except that the commands that change or "+
+		"delete clusters, like the 'edit', 'upgrade', 'delete', 'hibernate', 'transfer', "+
+		"'revoke', 'uninstall' and 'regenerate' commands, always require the cluster "+
+		"explicitly. The 'color' setting can be 'auto', 'always' or 'never', and is overridden "+
+		"by the '--color' flag.\n\n"+
+		"The valid keys are: %s.", strings.Join(config.Keys, ", ")),
+	Example: `  # Switch to the 'staging' profile, and log in to it
+  rosa config set profile staging
+  rosa login --env staging
+
+  # Use 'us-west-2' as the default region of the 'staging' profile
+  rosa config set region us-west-2 --ocm-profile staging
+
+  # Don't require '--cluster' for commands about the 'mycluster' cluster
+  rosa config set cluster mycluster
+  rosa list machinepools`,
+	Args: cobra.RangeArgs(1, 2),
+	Run:  run,
+}
+
+func run(_ *cobra.Command, argv []string) {
+	reporter := rprtr.CreateReporterOrExit()
+
+	key := argv[0]
+	value := ""
+	if len(argv) == 2 {
+		value = argv[1]
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		reporter.Errorf("Failed to load config file: %v", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	profile, err := config.CurrentProfile()
+	if err != nil {
+		reporter.Errorf("Failed to get current profile: %v", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	err = cfg.Set(profile, key, value)
+	if err != nil {
+		reporter.Errorf("%v", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	err = config.Save(cfg)
+	if err != nil {
+		reporter.Errorf("Failed to save config file: %v", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	switch {
+	case value == "":
+		reporter.Infof("Removed setting '%s'", key)
+	case profile != "" && key != "profile":
+		reporter.Infof("Set '%s' to '%s' for profile '%s'", key, value, profile)
+	default:
+		reporter.Infof("Set '%s' to '%s'", key, value)
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accountroles
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mode"
+	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	prefix string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "account-roles",
+	Aliases: []string{"account-role", "accountroles", "accountrole"},
+	Short:   "Create account-wide IAM roles for STS clusters",
+	Long: "Create the IAM roles that are shared by all the clusters of the AWS account that use the " +
+		"AWS Security Token Service (STS): the installer and support roles, which are assumed by " +
+		"Red Hat, and the roles of the instance profiles of the control plane and worker nodes.",
+	Example: `  # Create the account roles with the default prefix
+  rosa create account-roles
+
+  # Print the AWS CLI commands that create the account roles with the prefix "MyOrg"
+  rosa create account-roles --prefix=MyOrg --mode=manual`,
+	Run: runner.Command(run, validate, runner.WithAWS()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVar(
+		&args.prefix,
+		"prefix",
+		aws.DefaultRolePrefix,
+		"Prefix of the names of the roles.",
+	)
+
+	mode.AddFlag(flags)
+}
+
+func validate(r *runner.Runtime) error {
+	if args.prefix == "" {
+		return rerrors.ValidationErrorf("Role prefix can't be empty")
+	}
+	err := mode.Validate()
+	if err != nil {
+		return rerrors.ValidationErrorf("%v", err)
+	}
+	return nil
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+
+	specs, err := aws.AccountRoleSpecs(args.prefix, r.Creator.Partition)
+	if err != nil {
+		return err
+	}
+
+	if mode.IsManual() {
+		reporter.Infof("All policy files saved to the current directory")
+		reporter.Infof("Run the following commands to create the account roles:")
+		for _, spec := range specs {
+			trustFile, policyFile, err := aws.WriteRoleFiles(".", spec)
+			if err != nil {
+				return err
+			}
+			for _, command := range aws.CreateRoleCommands(spec, trustFile, policyFile) {
+				fmt.Println(command)
+			}
+		}
+		return nil
+	}
+
+	confirmed, err := confirm.Confirm("create the account roles with prefix '%s' in AWS account '%s'",
+		args.prefix, r.Creator.AccountID)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	for _, spec := range specs {
+		reporter.Debugf("Creating role '%s'", spec.Name)
+		roleARN, err := r.AWSClient.EnsureRole(spec)
+		if err != nil {
+			return err
+		}
+		reporter.Infof("Created role '%s' with ARN '%s'", spec.Name, roleARN)
+	}
+	return nil
+}
@@ -17,131 +17,25 @@ limitations under the License.
 package addon
 
 import (
-	"os"
-
-	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/aws"
-	clusterprovider "github.com/openshift/moactl/pkg/cluster"
-	"github.com/openshift/moactl/pkg/confirm"
-	"github.com/openshift/moactl/pkg/logging"
-	"github.com/openshift/moactl/pkg/ocm"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	installaddon "github.com/openshift/moactl/cmd/install/addon"
 )
 
-var args struct {
-	clusterKey string
-}
-
+// Cmd is kept so that existing scripts that use 'rosa create addon' continue working. It shares the
+// flags and the implementation of 'rosa install addon'.
 var Cmd = &cobra.Command{
-	Use:     "addon",
-	Aliases: []string{"addons", "add-on", "add-ons"},
-	Hidden:  true,
-	Short:   "Install add-ons on cluster",
-	Long:    "Install Red Hat managed add-ons on a cluster",
-	Example: `  # Add the CodeReady Workspaces add-on installation to the cluster
+	Use:        "addon ID",
+	Aliases:    []string{"addons", "add-on", "add-ons"},
+	Hidden:     true,
+	Short:      "Install add-on on cluster",
+	Long:       "Install a Red Hat managed add-on on a cluster.",
+	Deprecated: "use 'rosa install addon' instead.",
+	Example: `  # Install the CodeReady Workspaces add-on on the cluster named "mycluster"
   rosa create addon --cluster=mycluster codeready-workspaces`,
-	Run: run,
+	Run: installaddon.Cmd.Run,
 }
 
 func init() {
-	flags := Cmd.Flags()
-	confirm.AddFlag(flags)
-
-	flags.StringVarP(
-		&args.clusterKey,
-		"cluster",
-		"c",
-		"",
-		"Name or ID of the cluster to add the IdP to (required).",
-	)
-	Cmd.MarkFlagRequired("cluster")
-}
-
-func run(_ *cobra.Command, argv []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
-
-	// Check command line arguments:
-	if len(argv) != 1 {
-		reporter.Errorf("Expected exactly one command line parameters containing the identifier of the add-on.")
-		os.Exit(1)
-	}
-
-	addOnID := argv[0]
-	if addOnID == "" {
-		reporter.Errorf("Add-on ID is required.")
-		os.Exit(1)
-	}
-
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
-	clusterKey := args.clusterKey
-	if !ocm.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
-	}
-
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
-	}
-
-	awsCreator, err := awsClient.GetCreator()
-	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
-	}
-
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
-	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-
-	// Get the client for the OCM collection of clusters:
-	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
-
-	// Try to find the cluster:
-	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
-	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
-	}
-
-	if cluster.State() != cmv1.ClusterStateReady {
-		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
-		os.Exit(1)
-	}
-
-	reporter.Warnf("Once installed, add-ons cannot be uninstalled")
-	if confirm.Confirm("install add-on '%s' on cluster '%s'", addOnID, clusterKey) {
-		reporter.Debugf("Installing add-on '%s' on cluster '%s'", addOnID, clusterKey)
-		err = clusterprovider.InstallAddOn(clustersCollection, clusterKey, awsCreator.ARN, addOnID)
-		if err != nil {
-			reporter.Errorf("Failed to add add-on installation '%s' for cluster '%s': %s", addOnID, clusterKey, err)
-			os.Exit(1)
-		}
-		reporter.Infof("Add-on '%s' is now installing. To check the status run 'rosa list addons -c %s'",
-			addOnID, clusterKey)
-	}
+	Cmd.Flags().AddFlagSet(installaddon.Cmd.Flags())
 }
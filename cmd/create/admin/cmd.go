@@ -17,34 +17,33 @@ limitations under the License.
 package admin
 
 import (
-	"crypto/rand"
-	"math/big"
-	"os"
-
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/pkg/aws"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/admin"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
 )
 
-const (
-	idpName  = "Cluster-Admin"
-	username = "cluster-admin"
-)
-
 var args struct {
 	clusterKey string
+	password   string
 }
 
 var Cmd = &cobra.Command{
 	Use:   "admin",
 	Short: "Creates an admin user to login to the cluster",
-	Long:  "Creates a cluster-admin user with an auto-generated password to login to the cluster",
+	Long: "Creates a cluster-admin user to login to the cluster, with the given password or with " +
+		"an auto-generated one",
 	Example: `  # Create an admin user to login to the cluster
-  rosa create admin --cluster=mycluster`,
+  rosa create admin --cluster=mycluster
+
+  # Create an admin user with a specific password
+  rosa create admin --cluster=mycluster --password=MySecretPassword-1`,
 	Run: run,
 }
 
@@ -59,6 +58,16 @@ func init() {
 		"Name or ID of the cluster to add the IdP to (required).",
 	)
 	Cmd.MarkFlagRequired("cluster")
+
+	flags.StringVarP(
+		&args.password,
+		"password",
+		"p",
+		"",
+		"Password of the admin user. It must be at least 14 characters long, without whitespace, "+
+			"and contain uppercase letters, lowercase letters, and digits or symbols. "+
+			"A random password is generated if not specified.",
+	)
 }
 
 func run(cmd *cobra.Command, _ []string) {
@@ -74,7 +83,7 @@ func run(cmd *cobra.Command, _ []string) {
 				"must contain only letters, digits, dashes and underscores",
 			clusterKey,
 		)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	reporter.Warnf("It is recommended to add an identity provider to login to this cluster. " +
@@ -86,13 +95,13 @@ func run(cmd *cobra.Command, _ []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	awsCreator, err := awsClient.GetCreator()
 	if err != nil {
 		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	// Create the client for the OCM API:
@@ -101,7 +110,7 @@ func run(cmd *cobra.Command, _ []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
 	}
 	defer func() {
 		err = ocmConnection.Close()
@@ -118,110 +127,55 @@ func run(cmd *cobra.Command, _ []string) {
 	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
 	if err != nil {
 		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	if cluster.State() != cmv1.ClusterStateReady {
 		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeConflict)
 	}
 
-	// TODO: Verify that the htpasswd IdP does not already exist
-
-	// TODO: Verify that the user does not already exist
-
-	password, err := generateRandomPassword(23)
-	if err != nil {
-		reporter.Errorf("Failed to generate a random password")
-		os.Exit(1)
-	}
-
-	// Add admin user to the cluster-admins group:
-	reporter.Debugf("Adding '%s' user to cluster '%s'", username, clusterKey)
-	user, err := cmv1.NewUser().ID(username).Build()
+	// Check that the admin doesn't already exist:
+	idp, err := admin.GetIdentityProvider(clustersCollection, cluster.ID())
 	if err != nil {
-		reporter.Errorf("Failed to create user '%s' for cluster '%s'", username, clusterKey)
-		os.Exit(1)
+		reporter.Errorf("Failed to get identity providers for cluster '%s': %v", clusterKey, err)
+		exit.Exit(rerrors.ExitCode(err))
 	}
-	userResp, err := clustersCollection.Cluster(cluster.ID()).
-		Groups().Group("cluster-admins").
-		Users().Add().Body(user).
-		Send()
-	if err != nil {
-		reporter.Errorf("Failed to add user '%s' to cluster '%s': %s",
-			username, clusterKey, userResp.Error().Reason())
-		os.Exit(1)
+	if idp != nil {
+		reporter.Errorf("There is already an admin on cluster '%s'. To change its password run "+
+			"'rosa regenerate admin-password -c %s'", clusterKey, clusterKey)
+		exit.Exit(rerrors.ExitCodeConflict)
 	}
 
-	// Create HTPasswd IDP configuration:
-	reporter.Debugf("Adding '%s' udp to cluster '%s'", idpName, clusterKey)
-	htpasswdIDP := cmv1.NewHTPasswdIdentityProvider().
-		Username(username).
-		Password(password)
-
-	// Create new IDP with HTPasswd provider:
-	idp, err := cmv1.NewIdentityProvider().
-		Type("HTPasswdIdentityProvider"). // FIXME: ocm-api-model has the wrong enum values
-		Name(idpName).
-		MappingMethod(cmv1.IdentityProviderMappingMethod("claim")).
-		Htpasswd(htpasswdIDP).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create '%s' identity provider for cluster '%s'", idpName, clusterKey)
-		os.Exit(1)
+	password := args.password
+	if password != "" {
+		err = admin.ValidatePassword(password)
+		if err != nil {
+			reporter.Errorf("%v", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+	} else {
+		password, err = admin.GeneratePassword()
+		if err != nil {
+			reporter.Errorf("Failed to generate a random password")
+			exit.Exit(rerrors.ExitCodeGeneric)
+		}
 	}
 
-	// Add HTPasswd IDP to cluster:
-	idpResp, err := clustersCollection.Cluster(cluster.ID()).
-		IdentityProviders().
-		Add().
-		Body(idp).
-		Send()
+	reporter.Debugf("Adding '%s' user to cluster '%s'", admin.Username, clusterKey)
+	err = admin.CreateAdmin(clustersCollection, cluster.ID(), password)
 	if err != nil {
-		reporter.Errorf("Failed to add '%s' identity provider to cluster '%s': %s",
-			idpName, clusterKey, idpResp.Error().Reason())
-		os.Exit(1)
+		reporter.Errorf("Failed to create admin on cluster '%s': %v", clusterKey, err)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	reporter.Infof("Admin account has been added to cluster '%s'. "+
 		"It may take up to a minute for the account to become active.", clusterKey)
-	reporter.Infof("Please securely store this generated password. " +
-		"If you lose this password you can delete and recreate the cluster admin user.")
-	reporter.Infof("To login, run the following command:\n"+
-		"   oc login %s \\\n   --username %s \\\n   --password %s", cluster.API().URL(), username, password)
-}
-
-func generateRandomPassword(length int) (string, error) {
-	const (
-		lowerLetters = "abcdefghijkmnopqrstuvwxyz"
-		upperLetters = "ABCDEFGHIJKLMNPQRSTUVWXYZ"
-		digits       = "23456789"
-		all          = lowerLetters + upperLetters + digits
-	)
-	var password string
-	for i := 0; i < length; i++ {
-		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(all))))
-		if err != nil {
-			return "", err
-		}
-		newchar := string(all[n.Int64()])
-		if password == "" {
-			password = newchar
-		}
-		if i < length-1 {
-			n, err = rand.Int(rand.Reader, big.NewInt(int64(len(password)+1)))
-			if err != nil {
-				return "", err
-			}
-			j := n.Int64()
-			password = password[0:j] + newchar + password[j:]
-		}
+	if args.password == "" {
+		reporter.Infof("Please securely store this generated password. " +
+			"If you lose this password you can regenerate it with 'rosa regenerate admin-password'.")
 	}
-
-	pw := []rune(password)
-	for _, replace := range []int{5, 11, 17} {
-		pw[replace] = '-'
-	}
-
-	return string(pw), nil
+	reporter.Infof("To login, run the following command:\n"+
+		"   oc login %s \\\n   --username %s \\\n   --password %s",
+		cluster.API().URL(), admin.Username, password)
 }
@@ -0,0 +1,35 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package breakglass
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/cmd/create/breakglass/kubeconfig"
+)
+
+var Cmd = &cobra.Command{
+	Use:     "break-glass RESOURCE [flags]",
+	Aliases: []string{"breakglass"},
+	Short:   "Create temporary break glass access to a cluster",
+	Long: "Create temporary break glass access to a cluster, for example to reach private and " +
+		"PrivateLink clusters when their identity providers aren't available.",
+}
+
+func init() {
+	Cmd.AddCommand(kubeconfig.Cmd)
+}
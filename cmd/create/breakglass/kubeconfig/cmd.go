@@ -0,0 +1,196 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/kubeconfig"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/breakglass"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	clusterKey string
+	username   string
+	ttl        time.Duration
+	path       string
+	overwrite  bool
+	timeout    time.Duration
+}
+
+var Cmd = &cobra.Command{
+	Use:   "kubeconfig",
+	Short: "Create a temporary kubeconfig file with break glass access to a cluster",
+	Long: "Create a break glass credential for a cluster, wait until it is issued and write the " +
+		"kubeconfig that uses it to a file that only the current user can read. The credential " +
+		"expires after the given time to live, and can be revoked earlier with 'rosa delete " +
+		"break-glass'.",
+	Example: `  # Create a kubeconfig that can be used for 2 hours to access the cluster named "mycluster"
+  rosa create break-glass kubeconfig --cluster=mycluster --ttl=2h
+
+  # Write the kubeconfig to a specific file, replacing it if it exists
+  rosa create break-glass kubeconfig --cluster=mycluster --path=admin.kubeconfig --overwrite`,
+	Run: runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to create the kubeconfig for (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	flags.StringVar(
+		&args.username,
+		"username",
+		"",
+		"Username of the credential. Generated when not given.",
+	)
+
+	flags.DurationVar(
+		&args.ttl,
+		"ttl",
+		breakglass.DefaultExpiration,
+		fmt.Sprintf("Time to live of the credential, between %s and %s.",
+			breakglass.MinExpiration, breakglass.MaxExpiration),
+	)
+
+	flags.StringVar(
+		&args.path,
+		"path",
+		"",
+		"Path of the kubeconfig file. The default is '<cluster name>-break-glass.kubeconfig' in "+
+			"the current directory.",
+	)
+
+	flags.BoolVar(
+		&args.overwrite,
+		"overwrite",
+		false,
+		"Replace the kubeconfig file if it already exists.",
+	)
+
+	flags.DurationVar(
+		&args.timeout,
+		"timeout",
+		breakglass.DefaultIssueTimeout,
+		"Maximum time to wait for the credential to be issued.",
+	)
+}
+
+func validate(r *runner.Runtime) error {
+	if !c.IsValidClusterKey(args.clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			args.clusterKey,
+		)
+	}
+	err := breakglass.ValidateUsername(args.username)
+	if err != nil {
+		return err
+	}
+	return breakglass.ValidateExpiration(args.ttl)
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	clusterKey := args.clusterKey
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(r.OCMConnection.ClustersMgmt().V1().Clusters(), clusterKey,
+		r.Creator.ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+	if cluster.State() != cmv1.ClusterStateReady {
+		return rerrors.ConflictErrorf("Cluster '%s' is not yet ready", clusterKey)
+	}
+
+	// Check the file before creating the credential, so that it isn't created for nothing:
+	path := args.path
+	if path == "" {
+		path = breakglass.DefaultKubeconfigPath(cluster.Name())
+	}
+	if !args.overwrite {
+		_, err = os.Stat(path)
+		if err == nil {
+			return rerrors.ConflictErrorf("Kubeconfig file '%s' already exists, use '--overwrite' "+
+				"to replace it", path)
+		}
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("Failed to check kubeconfig file '%s': %v", path, err)
+		}
+	}
+
+	reporter.Debugf("Creating break glass credential for cluster '%s'", clusterKey)
+	credential, err := breakglass.CreateCredential(ctx, r.OCMConnection, cluster.ID(), args.username,
+		time.Now().Add(args.ttl))
+	if err != nil {
+		return fmt.Errorf("Failed to create break glass credential for cluster '%s': %w", clusterKey,
+			err)
+	}
+
+	reporter.Infof("Waiting for break glass credential '%s' to be issued", credential.ID)
+	credential, err = breakglass.WaitForIssued(ctx, r.OCMConnection, cluster.ID(), credential.ID,
+		breakglass.IssueInterval, args.timeout)
+	if err != nil {
+		return err
+	}
+
+	config, err := kubeconfig.Parse([]byte(credential.Kubeconfig))
+	if err != nil {
+		return fmt.Errorf("Failed to read kubeconfig of break glass credential '%s': %w",
+			credential.ID, err)
+	}
+	err = kubeconfig.Write(path, config, false)
+	if err != nil {
+		return err
+	}
+	// Files that already existed keep their permissions when they are replaced:
+	err = os.Chmod(path, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to change permissions of kubeconfig file '%s': %v", path, err)
+	}
+
+	reporter.Infof("Kubeconfig of break glass credential '%s' with username '%s' written to '%s', "+
+		"it expires on %s", credential.ID, credential.Username, path,
+		credential.ExpirationTimestamp.Format(time.RFC3339))
+	if cluster.API().Listening() == cmv1.ListeningMethodInternal {
+		reporter.Warnf("The API of cluster '%s' is private, the kubeconfig can only be used from "+
+			"inside its VPC or from networks connected to it", clusterKey)
+	}
+	reporter.Infof("To revoke it and remove the file run 'rosa delete break-glass --cluster=%s "+
+		"--path=%s'", clusterKey, path)
+	return nil
+}
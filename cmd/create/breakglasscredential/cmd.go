@@ -0,0 +1,133 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package breakglasscredential
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/breakglass"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	clusterKey string
+	username   string
+	expiration time.Duration
+}
+
+var Cmd = &cobra.Command{
+	Use:     "break-glass-credential",
+	Aliases: []string{"break-glass-credentials", "breakglasscredential", "breakglasscredentials"},
+	Short:   "Create a break glass credential for a cluster",
+	Long: "Create a temporary credential that gives administrator access to a cluster when the " +
+		"identity providers of the cluster aren't available. The credential expires after the " +
+		"given time, and can be revoked earlier with 'rosa revoke break-glass-credentials'.",
+	Example: `  # Create a break glass credential that expires in 4 hours for the cluster named "mycluster"
+  rosa create break-glass-credential --cluster=mycluster --expiration=4h
+
+  # Create a break glass credential with a specific username
+  rosa create break-glass-credential --cluster=mycluster --username=sre-alice`,
+	Run: runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to create the break glass credential for (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	flags.StringVar(
+		&args.username,
+		"username",
+		"",
+		"Username of the credential. Generated when not given.",
+	)
+
+	flags.DurationVar(
+		&args.expiration,
+		"expiration",
+		breakglass.DefaultExpiration,
+		fmt.Sprintf("Time after which the credential can't be used anymore, between %s and %s.",
+			breakglass.MinExpiration, breakglass.MaxExpiration),
+	)
+}
+
+func validate(r *runner.Runtime) error {
+	err := validateClusterKey()
+	if err != nil {
+		return err
+	}
+	err = breakglass.ValidateUsername(args.username)
+	if err != nil {
+		return err
+	}
+	return breakglass.ValidateExpiration(args.expiration)
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	clusterKey := args.clusterKey
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(r.OCMConnection.ClustersMgmt().V1().Clusters(), clusterKey,
+		r.Creator.ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	reporter.Debugf("Creating break glass credential for cluster '%s'", clusterKey)
+	credential, err := breakglass.CreateCredential(ctx, r.OCMConnection, cluster.ID(), args.username,
+		time.Now().Add(args.expiration))
+	if err != nil {
+		return fmt.Errorf("Failed to create break glass credential for cluster '%s': %w", clusterKey,
+			err)
+	}
+
+	reporter.Infof("Created break glass credential '%s' with username '%s' for cluster '%s', it "+
+		"expires on %s", credential.ID, credential.Username, clusterKey,
+		credential.ExpirationTimestamp.Format(time.RFC3339))
+	reporter.Infof("To get the kubeconfig once it is issued run 'rosa describe "+
+		"break-glass-credential %s --cluster=%s --kubeconfig'", credential.ID, clusterKey)
+	return nil
+}
+
+// validateClusterKey checks that the cluster key given by the user is reasonably safe so that there
+// is no risk of SQL injection.
+func validateClusterKey() error {
+	if !c.IsValidClusterKey(args.clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			args.clusterKey,
+		)
+	}
+	return nil
+}
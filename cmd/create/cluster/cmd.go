@@ -17,45 +17,71 @@ limitations under the License.
 package cluster
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
-	"os"
+	"sort"
 	"strings"
 	"time"
 
+	sdk "github.com/openshift-online/ocm-sdk-go"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	clusterdescribe "github.com/openshift/moactl/cmd/describe/cluster"
 	installLogs "github.com/openshift/moactl/cmd/logs/install"
 
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	awssdk "github.com/aws/aws-sdk-go/aws"
 	v "github.com/openshift/moactl/cmd/validations"
 	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mode"
 
 	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/config"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/interactive"
 	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/moactl"
 	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/machinepools"
 	"github.com/openshift/moactl/pkg/ocm/machines"
 	"github.com/openshift/moactl/pkg/ocm/regions"
 	"github.com/openshift/moactl/pkg/ocm/versions"
+	"github.com/openshift/moactl/pkg/operations"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runner"
+	"github.com/openshift/moactl/pkg/timeout"
+	"github.com/openshift/moactl/pkg/verify/quota"
 )
 
 var args struct {
+	// Cluster spec file with the values of the rest of the options
+	file string
+
+	// Named set of values for the options that aren't given in the command line or the spec file
+	preset string
+
+	// Token of an interrupted creation to continue from the last completed step
+	resume string
+
 	// Watch logs during cluster installation
 	watch bool
 
 	// Simulate creating a cluster
 	dryRun bool
 
+	// Show the estimated monthly AWS cost before creating the cluster
+	estimateCost bool
+
 	// Disable SCP checks in the installer
 	disableSCPChecks bool
 
 	// Basic options
 	private            bool
+	privateLink        bool
 	multiAZ            bool
 	expirationDuration time.Duration
 	expirationTime     string
@@ -67,6 +93,17 @@ var args struct {
 	// Scaling options
 	computeMachineType string
 	computeNodes       int
+	autoscaling        bool
+	minReplicas        int
+	maxReplicas        int
+	defaultMPLabels    string
+	tags               string
+
+	// Encryption options
+	enableCustomerManagedKey bool
+	kmsKeyARN                string
+	etcdEncryption           bool
+	fips                     bool
 
 	// Networking options
 	hostPrefix  int
@@ -77,6 +114,12 @@ var args struct {
 	// The Subnet IDs to use when installing the cluster.
 	// SubnetIDs should come in pairs; two per availability zone, one private and one public.
 	subnetIDs []string
+
+	// Cluster-wide proxy options
+	httpProxy                 string
+	httpsProxy                string
+	noProxy                   string
+	additionalTrustBundleFile string
 }
 
 var Cmd = &cobra.Command{
@@ -87,15 +130,49 @@ var Cmd = &cobra.Command{
   rosa create cluster --cluster-name=mycluster
 
   # Create a cluster in the us-east-2 region
-  rosa create cluster --cluster-name=mycluster --region=us-east-2`,
-	Run:              run,
-	PersistentPreRun: v.Validations,
+  rosa create cluster --cluster-name=mycluster --region=us-east-2
+
+  # Create a multizone cluster with autoscaling, using the production preset
+  rosa create cluster --cluster-name=mycluster --preset=production
+
+  # Create a PrivateLink cluster into the private subnets of an existing VPC
+  rosa create cluster --cluster-name=mycluster --private-link --subnet-ids=subnet-1,subnet-2
+
+  # Create a cluster using the options from a spec file
+  rosa create cluster --file=cluster.yaml
+
+  # Create a cluster interactively, using the options from a spec file as defaults
+  rosa create cluster --file=cluster.yaml --interactive
+
+  # Continue the creation of cluster "mycluster" after it was interrupted
+  rosa create cluster --resume=mycluster`,
+	Run:    run,
+	PreRun: v.Validations,
 }
 
 func init() {
 	flags := Cmd.Flags()
 	flags.SortFlags = false
 
+	flags.StringVarP(
+		&args.file,
+		"file",
+		"f",
+		"",
+		"YAML or JSON file containing the cluster spec. Options given in the command line take "+
+			"precedence over the values in the file. Use 'rosa describe cluster --output=spec' to "+
+			"export the spec of an existing cluster.",
+	)
+	flags.StringVar(
+		&args.preset,
+		"preset",
+		"",
+		"Name of a preset with the values of the options for a common use case, like 'minimal' "+
+			"or 'production', or of a custom preset defined in the 'presets' section of the "+
+			"configuration file. Options given in the command line or in the spec file take "+
+			"precedence over the values of the preset.",
+	)
+
 	// Basic options
 	flags.StringVarP(
 		&args.clusterName,
@@ -167,31 +244,64 @@ func init() {
 		"Number of worker nodes to provision per zone. Single zone clusters need at least 2 nodes, "+
 			"multizone clusters need at least 3 nodes.",
 	)
+	flags.BoolVar(
+		&args.autoscaling,
+		"enable-autoscaling",
+		false,
+		"Enable autoscaling of the compute nodes between '--min-replicas' and '--max-replicas', "+
+			"instead of a fixed number of '--compute-nodes'.",
+	)
+	flags.IntVar(
+		&args.minReplicas,
+		"min-replicas",
+		0,
+		"Minimum number of compute nodes when autoscaling is enabled. The default is the number "+
+			"of compute nodes.",
+	)
+	flags.IntVar(
+		&args.maxReplicas,
+		"max-replicas",
+		0,
+		"Maximum number of compute nodes when autoscaling is enabled. The default is the minimum "+
+			"number of compute nodes.",
+	)
+	flags.StringVar(
+		&args.defaultMPLabels,
+		"default-mp-labels",
+		"",
+		"Labels for the default machine pool. Format should be a comma-separated list of 'key=value'. "+
+			"This list will overwrite any modifications made to Node labels on an ongoing basis.",
+	)
 
 	flags.IPNetVar(
 		&args.machineCIDR,
 		"machine-cidr",
 		net.IPNet{},
-		"Block of IP addresses used by OpenShift while installing the cluster, for example \"10.0.0.0/16\".",
+		"Block of IP addresses used by OpenShift while installing the cluster, for example \"10.0.0.0/16\". "+
+			"When installing into an existing VPC it must contain the subnets given in '--subnet-ids'.",
 	)
 	flags.IPNetVar(
 		&args.serviceCIDR,
 		"service-cidr",
 		net.IPNet{},
-		"Block of IP addresses for services, for example \"172.30.0.0/16\".",
+		"Block of IP addresses for services, for example \"172.30.0.0/16\". It must not overlap the "+
+			"machine and pod CIDRs, or the VPC of the subnets given in '--subnet-ids'.",
 	)
 	flags.IPNetVar(
 		&args.podCIDR,
 		"pod-cidr",
 		net.IPNet{},
-		"Block of IP addresses from which Pod IP addresses are allocated, for example \"10.128.0.0/14\".",
+		"Block of IP addresses from which Pod IP addresses are allocated, for example \"10.128.0.0/14\". "+
+			"It must not overlap the machine and service CIDRs, or the VPC of the subnets given in "+
+			"'--subnet-ids'.",
 	)
 	flags.IntVar(
 		&args.hostPrefix,
 		"host-prefix",
 		0,
 		"Subnet prefix length to assign to each individual node. For example, if host prefix is set "+
-			"to \"23\", then each node is assigned a /23 subnet out of the given CIDR.",
+			"to \"23\", then each node is assigned a /23 subnet out of the given CIDR. The pod CIDR "+
+			"must have room for a subnet for each node of the cluster.",
 	)
 	flags.BoolVar(
 		&args.private,
@@ -199,6 +309,14 @@ func init() {
 		false,
 		"Restrict master API endpoint and application routes to direct, private connectivity.",
 	)
+	flags.BoolVar(
+		&args.privateLink,
+		"private-link",
+		false,
+		"Provide private connectivity between the cluster and the SRE managing it through AWS "+
+			"PrivateLink, without any public endpoint. Implies '--private' and requires "+
+			"'--subnet-ids' with only private subnets.",
+	)
 
 	flags.BoolVar(
 		&args.disableSCPChecks,
@@ -207,6 +325,51 @@ func init() {
 		"Indicates if cloud permission checks are disabled when attempting installation of the cluster.",
 	)
 
+	flags.StringVar(
+		&args.tags,
+		"tags",
+		"",
+		"Custom AWS tags added to all the AWS resources created for the cluster. Format should be a "+
+			"comma-separated list of 'key=value', for example: --tags=team=payments,cost-center=1234.",
+	)
+
+	flags.BoolVar(
+		&args.enableCustomerManagedKey,
+		"enable-customer-managed-key",
+		false,
+		"Encrypt the EBS volumes of the nodes with the customer managed KMS key given in "+
+			"'--kms-key-arn' instead of the default AWS managed key.",
+	)
+	flags.StringVar(
+		&args.kmsKeyARN,
+		"kms-key-arn",
+		"",
+		"ARN of the customer managed KMS key used to encrypt the EBS volumes of the nodes. The key "+
+			"must be an enabled symmetric key in the region of the cluster.",
+	)
+	flags.BoolVar(
+		&args.etcdEncryption,
+		"etcd-encryption",
+		false,
+		"Add etcd encryption. By default etcd data is encrypted at rest. This option configures "+
+			"etcd encryption on top of existing storage encryption.",
+	)
+	flags.BoolVar(
+		&args.fips,
+		"fips",
+		false,
+		"Create a cluster that uses FIPS validated cryptographic libraries. FIPS mode also enables "+
+			"the encryption of etcd.",
+	)
+
+	flags.StringVar(
+		&args.resume,
+		"resume",
+		"",
+		"Continue the creation of a cluster that was interrupted or failed, from the last completed "+
+			"step, using the options saved at the time. The token is the name of the cluster.",
+	)
+
 	flags.BoolVar(
 		&args.watch,
 		"watch",
@@ -221,29 +384,86 @@ func init() {
 		"Simulate creating the cluster.",
 	)
 
+	flags.BoolVar(
+		&args.estimateCost,
+		"estimate-cost",
+		false,
+		"Show the estimated monthly cost of the AWS resources of the cluster, using the AWS Pricing "+
+			"API, and ask for confirmation before creating it.",
+	)
+
 	flags.StringSliceVar(
 		&args.subnetIDs,
 		"subnet-ids",
 		nil,
 		"The Subnet IDs to use when installing the cluster. "+
 			"SubnetIDs should come in pairs; two per availability zone, one private and one public. "+
-			"Subnets are comma separated, for example: --subnet-ids=subnet-1,subnet-2."+
+			"All the subnets must belong to the same VPC and span one availability zone, or three "+
+			"for multi-AZ clusters. "+
+			"Subnets are comma separated, for example: --subnet-ids=subnet-1,subnet-2. "+
 			"Leave empty for installer provisioned subnet IDs.",
 	)
+
+	flags.StringVar(
+		&args.httpProxy,
+		"http-proxy",
+		"",
+		"A proxy URL to use for creating HTTP connections outside the cluster. The URL scheme must be http. "+
+			"Requires installing into an existing VPC.",
+	)
+	flags.StringVar(
+		&args.httpsProxy,
+		"https-proxy",
+		"",
+		"A proxy URL to use for creating HTTPS connections outside the cluster. "+
+			"Requires installing into an existing VPC.",
+	)
+	flags.StringVar(
+		&args.noProxy,
+		"no-proxy",
+		"",
+		"A comma-separated list of destination domain names, domains, IP addresses or other network CIDRs "+
+			"to exclude proxying, for example: --no-proxy=.example.com,10.0.0.0/16.",
+	)
+	flags.StringVar(
+		&args.additionalTrustBundleFile,
+		"additional-trust-bundle-file",
+		"",
+		"A file containing a PEM-encoded X.509 certificate bundle that will be added to the nodes' "+
+			"trusted certificate store.",
+	)
+
+	mode.AddFlag(flags)
 }
 
+// masterNodes is the number of control plane nodes of clusters.
+const masterNodes = 3
+
+// createClusterRequestTimeout is the default request timeout of the command.
+const createClusterRequestTimeout = 5 * time.Minute
+
 func run(cmd *cobra.Command, _ []string) {
 	reporter := rprtr.CreateReporterOrExit()
 	logger := logging.CreateLoggerOrExit(reporter)
 	var err error
 
+	err = mode.Validate()
+	if err != nil {
+		reporter.Errorf("%v", err)
+		exit.Exit(rerrors.ExitCodeValidation)
+	}
+
+	// The request that creates the cluster also validates the AWS account, which can take longer
+	// than other requests:
+	timeout.SetCommandDefault(createClusterRequestTimeout)
+
 	// Create the client for the OCM API:
 	ocmConnection, err := ocm.NewConnection().
 		Logger(logger).
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
 	}
 	defer func() {
 		err = ocmConnection.Close()
@@ -253,6 +473,46 @@ func run(cmd *cobra.Command, _ []string) {
 	}()
 	ocmClient := ocmConnection.ClustersMgmt().V1()
 
+	// Continue an interrupted creation with the options that were saved, without asking again:
+	if args.resume != "" {
+		if args.dryRun {
+			reporter.Errorf("Option '--resume' can't be used with '--dry-run'")
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+		state, err := clusterprovider.LoadCreateState(args.resume)
+		if err != nil {
+			reporter.Errorf("%v", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		awsClient, err := aws.NewClient().
+			Region(state.Spec.Region).
+			Logger(logger).
+			Build()
+		if err != nil {
+			reporter.Errorf("Failed to create awsClient: %s", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		reporter.Infof("Resuming the creation of cluster '%s'", state.Token)
+		createCluster(cmd, reporter, logger, ocmConnection, awsClient, state)
+		return
+	}
+
+	// Use the values of the spec file for the options that weren't given in the command line:
+	if args.file != "" {
+		err = applySpecFile(cmd, args.file)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+	}
+	if args.preset != "" {
+		err = applyPreset(cmd, args.preset)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+	}
+
 	if interactive.Enabled() {
 		reporter.Infof("Interactive mode enabled.\n" +
 			"Any optional fields can be left empty and a default will be selected.")
@@ -272,17 +532,18 @@ func run(cmd *cobra.Command, _ []string) {
 			Help:     cmd.Flags().Lookup("cluster-name").Usage,
 			Default:  clusterName,
 			Required: true,
+			Flag:     "cluster-name",
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid cluster name: %s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 	}
 	if !clusterprovider.IsValidClusterName(clusterName) {
 		reporter.Errorf("Cluster name must consist" +
 			" of no more than 15 lowercase alphanumeric characters or '-', " +
 			"start with a letter, and end with an alphanumeric character.")
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	// Multi-AZ:
@@ -295,7 +556,7 @@ func run(cmd *cobra.Command, _ []string) {
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid multi-AZ value: %s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 	}
 
@@ -303,13 +564,13 @@ func run(cmd *cobra.Command, _ []string) {
 	region, err := aws.GetRegion(args.region)
 	if err != nil {
 		reporter.Errorf("Error getting region: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	regionList, regionAZ, err := regions.GetRegionList(ocmClient, multiAZ)
 	if err != nil {
 		reporter.Errorf(fmt.Sprintf("%s", err))
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 	if interactive.Enabled() {
 		region, err = interactive.GetOption(interactive.Input{
@@ -321,22 +582,22 @@ func run(cmd *cobra.Command, _ []string) {
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid AWS region: %s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 	}
 
 	if region == "" {
 		reporter.Errorf("Expected a valid AWS region")
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	} else {
 		if supportsMultiAZ, found := regionAZ[region]; found {
 			if !supportsMultiAZ && multiAZ {
 				reporter.Errorf("Region '%s' does not support multiple availability zones", region)
-				os.Exit(1)
+				exit.Exit(rerrors.ExitCodeValidation)
 			}
 		} else {
 			reporter.Errorf("Region '%s' is not supported for this AWS account", region)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 	}
 
@@ -346,7 +607,7 @@ func run(cmd *cobra.Command, _ []string) {
 	versionList, err := getVersionList(ocmClient, channelGroup)
 	if err != nil {
 		reporter.Errorf(fmt.Sprintf("%s", err))
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 	if interactive.Enabled() {
 		version, err = interactive.GetOption(interactive.Input{
@@ -357,13 +618,61 @@ func run(cmd *cobra.Command, _ []string) {
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid OpenShift version: %s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 	}
+	// The first version of the list is the default one:
+	rawVersion := version
+	if rawVersion == "" && len(versionList) > 0 {
+		rawVersion = versionList[0]
+	}
 	version, err = validateVersion(version, versionList)
 	if err != nil {
 		reporter.Errorf("Expected a valid OpenShift version: %s", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
+	}
+
+	// FIPS mode and encryption of etcd:
+	fips := args.fips
+	if interactive.Enabled() {
+		fips, err = interactive.GetBool(interactive.Input{
+			Question: "Enable FIPS support",
+			Help:     cmd.Flags().Lookup("fips").Usage,
+			Default:  fips,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid FIPS value: %s", err)
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+	}
+	etcdEncryption := args.etcdEncryption
+	if fips && !cmd.Flags().Changed("etcd-encryption") {
+		etcdEncryption = true
+	}
+	if interactive.Enabled() && !fips {
+		etcdEncryption, err = interactive.GetBool(interactive.Input{
+			Question: "Encrypt etcd data",
+			Help:     cmd.Flags().Lookup("etcd-encryption").Usage,
+			Default:  etcdEncryption,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid etcd-encryption value: %s", err)
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+	}
+	if fips {
+		err = clusterprovider.ValidateFIPS(rawVersion, etcdEncryption)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+	}
+	if etcdEncryption {
+		err = clusterprovider.ValidateEtcdEncryption(rawVersion)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
 	}
 
 	// Subnet IDs
@@ -373,7 +682,7 @@ func run(cmd *cobra.Command, _ []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create awsClient: %s", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	subnetIDs := args.subnetIDs
@@ -390,54 +699,49 @@ func run(cmd *cobra.Command, _ []string) {
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid value: %s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 	}
 
 	var availabilityZones []string
+	var subnetCIDRs []string
+	vpcID := ""
 	if useExistingVPC || subnetsProvided {
 		subnets, err := awsClient.GetSubnetIDs()
 		if err != nil {
 			reporter.Errorf("Failed to get the list of subnets: %s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCode(err))
 		}
 
-		mapSubnetToAZ := make(map[string]string)
-		mapAZCreated := make(map[string]bool)
-		options := make([]string, len(subnets))
-		defaultOptions := make([]string, len(subnetIDs))
-
-		// Verify subnets provided exist.
-		if subnetsProvided {
-			for _, subnetArg := range subnetIDs {
-				verifiedSubnet := false
-				for _, subnet := range subnets {
-					if awssdk.StringValue(subnet.SubnetId) == subnetArg {
-						verifiedSubnet = true
-					}
-				}
-				if !verifiedSubnet {
-					reporter.Errorf("Could not find the following subnet provided: %s", subnetArg)
-					os.Exit(1)
-				}
+		// List the subnets grouped by VPC, so that it is easier to pick the subnets of the
+		// same VPC in the interactive prompt:
+		sort.Slice(subnets, func(i, j int) bool {
+			vpcI := awssdk.StringValue(subnets[i].VpcId)
+			vpcJ := awssdk.StringValue(subnets[j].VpcId)
+			if vpcI != vpcJ {
+				return vpcI < vpcJ
 			}
-		}
+			return awssdk.StringValue(subnets[i].AvailabilityZone) <
+				awssdk.StringValue(subnets[j].AvailabilityZone)
+		})
 
+		zones := make(map[string]bool)
+		options := make([]string, len(subnets))
+		defaultOptions := []string{}
 		for i, subnet := range subnets {
 			subnetID := awssdk.StringValue(subnet.SubnetId)
 			availabilityZone := awssdk.StringValue(subnet.AvailabilityZone)
 
 			// Create the options to prompt the user.
-			options[i] = setSubnetOption(subnetID, availabilityZone)
-			if subnetsProvided {
-				for _, subnetArg := range subnetIDs {
-					defaultOptions = append(defaultOptions, setSubnetOption(subnetArg, availabilityZone))
+			options[i] = setSubnetOption(subnetID, awssdk.StringValue(subnet.VpcId), availabilityZone)
+			for _, subnetArg := range subnetIDs {
+				if subnetArg == subnetID {
+					defaultOptions = append(defaultOptions, options[i])
 				}
 			}
-			mapSubnetToAZ[subnetID] = availabilityZone
-			mapAZCreated[availabilityZone] = false
+			zones[availabilityZone] = true
 		}
-		if interactive.Enabled() && len(options) > 0 && (!multiAZ || len(mapAZCreated) >= 3) {
+		if interactive.Enabled() && len(options) > 0 && (!multiAZ || len(zones) >= 3) {
 			subnetIDs, err = interactive.GetMultipleOptions(interactive.Input{
 				Question: "Subnet IDs",
 				Help:     cmd.Flags().Lookup("subnet-ids").Usage,
@@ -447,18 +751,28 @@ func run(cmd *cobra.Command, _ []string) {
 			})
 			if err != nil {
 				reporter.Errorf("Expected valid subnet IDs: %s", err)
-				os.Exit(1)
+				exit.Exit(rerrors.ExitCodeValidation)
 			}
 			for i, subnet := range subnetIDs {
 				subnetIDs[i] = parseSubnet(subnet)
 			}
 		}
 
-		for _, subnet := range subnetIDs {
-			az := mapSubnetToAZ[subnet]
-			if !mapAZCreated[az] {
-				availabilityZones = append(availabilityZones, az)
-				mapAZCreated[az] = true
+		if len(subnetIDs) > 0 {
+			availabilityZones, err = awsClient.ValidateSubnets(subnetIDs, multiAZ)
+			if err != nil {
+				reporter.Errorf("Expected valid subnet IDs: %s", err)
+				exit.Exit(rerrors.ExitCodeValidation)
+			}
+			// Keep the CIDR blocks of the selected subnets and their VPC, to check that they
+			// don't collide with the networking options:
+			for _, subnet := range subnets {
+				for _, subnetID := range subnetIDs {
+					if awssdk.StringValue(subnet.SubnetId) == subnetID {
+						subnetCIDRs = append(subnetCIDRs, awssdk.StringValue(subnet.CidrBlock))
+						vpcID = awssdk.StringValue(subnet.VpcId)
+					}
+				}
 			}
 		}
 	}
@@ -469,24 +783,50 @@ func run(cmd *cobra.Command, _ []string) {
 	computeMachineTypeList, err := machines.GetMachineTypeList(ocmClient)
 	if err != nil {
 		reporter.Errorf(fmt.Sprintf("%s", err))
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 	if interactive.Enabled() {
+		options := getComputeMachineTypeOptions(cmd, reporter, ocmClient, awsClient, multiAZ,
+			computeMachineTypeList)
 		computeMachineType, err = interactive.GetOption(interactive.Input{
 			Question: "Compute nodes instance type",
 			Help:     cmd.Flags().Lookup("compute-machine-type").Usage,
-			Options:  computeMachineTypeList,
+			Options:  options,
 			Default:  computeMachineType,
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid machine type: %s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 	}
 	computeMachineType, err = machines.ValidateMachineType(computeMachineType, computeMachineTypeList)
 	if err != nil {
 		reporter.Errorf("Expected a valid machine type: %s", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
+	}
+
+	// Autoscaling of the compute nodes:
+	autoscaling := args.autoscaling
+	if interactive.Enabled() {
+		autoscaling, err = interactive.GetBool(interactive.Input{
+			Question: "Enable autoscaling",
+			Help:     cmd.Flags().Lookup("enable-autoscaling").Usage,
+			Default:  autoscaling,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid value for enable autoscaling: %s", err)
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+	}
+	if autoscaling && explicitFlag(cmd, "compute-nodes") {
+		reporter.Errorf("Option '--compute-nodes' can't be used with '--enable-autoscaling', " +
+			"use '--min-replicas' and '--max-replicas' instead")
+		exit.Exit(rerrors.ExitCodeValidation)
+	}
+	if !autoscaling && (explicitFlag(cmd, "min-replicas") || explicitFlag(cmd, "max-replicas")) {
+		reporter.Errorf("Options '--min-replicas' and '--max-replicas' require " +
+			"'--enable-autoscaling'")
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	// Compute nodes:
@@ -495,7 +835,7 @@ func run(cmd *cobra.Command, _ []string) {
 	if multiAZ && !cmd.Flags().Changed("compute-nodes") {
 		computeNodes = 3
 	}
-	if interactive.Enabled() {
+	if interactive.Enabled() && !autoscaling {
 		computeNodes, err = interactive.GetInt(interactive.Input{
 			Question: "Compute nodes",
 			Help:     cmd.Flags().Lookup("compute-nodes").Usage,
@@ -503,15 +843,125 @@ func run(cmd *cobra.Command, _ []string) {
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid number of compute nodes: %s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+	}
+	minReplicas := args.minReplicas
+	maxReplicas := args.maxReplicas
+	if autoscaling {
+		if !cmd.Flags().Changed("min-replicas") {
+			minReplicas = computeNodes
+		}
+		if !cmd.Flags().Changed("max-replicas") {
+			maxReplicas = minReplicas
+		}
+		if interactive.Enabled() {
+			minReplicas, err = interactive.GetInt(interactive.Input{
+				Question: "Min replicas",
+				Help:     cmd.Flags().Lookup("min-replicas").Usage,
+				Default:  minReplicas,
+			})
+			if err != nil {
+				reporter.Errorf("Expected a valid number of min replicas: %s", err)
+				exit.Exit(rerrors.ExitCodeValidation)
+			}
+			maxReplicas, err = interactive.GetInt(interactive.Input{
+				Question: "Max replicas",
+				Help:     cmd.Flags().Lookup("max-replicas").Usage,
+				Default:  maxReplicas,
+			})
+			if err != nil {
+				reporter.Errorf("Expected a valid number of max replicas: %s", err)
+				exit.Exit(rerrors.ExitCodeValidation)
+			}
+		}
+		err = clusterprovider.ValidateComputeAutoscaling(multiAZ, minReplicas, maxReplicas)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		// The quota and the cost are checked for the largest size of the cluster:
+		computeNodes = maxReplicas
+	}
+
+	// Default machine pool labels:
+	defaultMPLabels := args.defaultMPLabels
+	if interactive.Enabled() {
+		defaultMPLabels, err = interactive.GetString(interactive.Input{
+			Question: "Default machine pool labels",
+			Help:     cmd.Flags().Lookup("default-mp-labels").Usage,
+			Default:  defaultMPLabels,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid comma-separated list of attributes: %s", err)
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+	}
+	computeLabels, err := machinepools.ParseLabels(defaultMPLabels)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	// Custom AWS tags:
+	tags := args.tags
+	if interactive.Enabled() {
+		tags, err = interactive.GetString(interactive.Input{
+			Question: "Tags",
+			Help:     cmd.Flags().Lookup("tags").Usage,
+			Default:  tags,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid comma-separated list of tags: %s", err)
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+	}
+	tagMap, err := clusterprovider.ParseTags(tags)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	// Encryption of the EBS volumes with a customer managed key:
+	enableCustomerManagedKey := args.enableCustomerManagedKey
+	kmsKeyARN := args.kmsKeyARN
+	if interactive.Enabled() {
+		enableCustomerManagedKey, err = interactive.GetBool(interactive.Input{
+			Question: "Enable customer managed key",
+			Help:     cmd.Flags().Lookup("enable-customer-managed-key").Usage,
+			Default:  enableCustomerManagedKey,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid value for enable-customer-managed-key: %s", err)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 	}
+	if interactive.Enabled() && enableCustomerManagedKey {
+		kmsKeyARN, err = interactive.GetString(interactive.Input{
+			Question: "KMS key ARN",
+			Help:     cmd.Flags().Lookup("kms-key-arn").Usage,
+			Default:  kmsKeyARN,
+			Required: true,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid KMS key ARN: %s", err)
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+	}
+	err = clusterprovider.ValidateCustomerManagedKey(awsClient, enableCustomerManagedKey, kmsKeyARN)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	if !enableCustomerManagedKey {
+		kmsKeyARN = ""
+	}
 
 	// Validate all remaining flags:
 	expiration, err := validateExpiration()
 	if err != nil {
 		reporter.Errorf(fmt.Sprintf("%s", err))
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 	var dMachinecidr *net.IPNet
 	var dPodcidr *net.IPNet
@@ -521,41 +971,41 @@ func run(cmd *cobra.Command, _ []string) {
 	// Machine CIDR:
 	machineCIDR := args.machineCIDR
 	if interactive.Enabled() {
-		machineCIDR, err = interactive.GetIPNet(interactive.Input{
+		machineCIDR, err = interactive.GetCIDR(interactive.Input{
 			Question: "Machine CIDR",
 			Help:     cmd.Flags().Lookup("machine-cidr").Usage,
 			Default:  *dMachinecidr,
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid CIDR value: %s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 	}
 
 	// Service CIDR:
 	serviceCIDR := args.serviceCIDR
 	if interactive.Enabled() {
-		serviceCIDR, err = interactive.GetIPNet(interactive.Input{
+		serviceCIDR, err = interactive.GetCIDR(interactive.Input{
 			Question: "Service CIDR",
 			Help:     cmd.Flags().Lookup("service-cidr").Usage,
 			Default:  *dServicecidr,
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid CIDR value: %s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 	}
 	// Pod CIDR:
 	podCIDR := args.podCIDR
 	if interactive.Enabled() {
-		podCIDR, err = interactive.GetIPNet(interactive.Input{
+		podCIDR, err = interactive.GetCIDR(interactive.Input{
 			Question: "Pod CIDR",
 			Help:     cmd.Flags().Lookup("pod-cidr").Usage,
 			Default:  *dPodcidr,
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid CIDR value: %s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 	}
 
@@ -569,12 +1019,29 @@ func run(cmd *cobra.Command, _ []string) {
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid host prefix value: %s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 	}
 
+	// Check the networking options, using the defaults of OCM for the ones that aren't given:
+	networkHostPrefix := hostPrefix
+	if networkHostPrefix == 0 {
+		networkHostPrefix = dhostPrefix
+	}
+	err = validateNetwork(reporter, awsClient, cidrValue(machineCIDR, dMachinecidr),
+		cidrValue(serviceCIDR, dServicecidr), cidrValue(podCIDR, dPodcidr), networkHostPrefix,
+		computeNodes+quota.InfraNodes(multiAZ)+masterNodes, subnetCIDRs, vpcID)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		exit.Exit(rerrors.ExitCodeValidation)
+	}
+
 	// Cluster privacy:
+	privateLink := args.privateLink
 	private := args.private
+	if privateLink && !cmd.Flags().Changed("private") {
+		private = true
+	}
 	if interactive.Enabled() {
 		private, err = interactive.GetBool(interactive.Input{
 			Question: "Private cluster",
@@ -583,8 +1050,95 @@ func run(cmd *cobra.Command, _ []string) {
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid private value: %s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+	}
+	if interactive.Enabled() && private && len(subnetIDs) > 0 {
+		privateLink, err = interactive.GetBool(interactive.Input{
+			Question: "PrivateLink cluster",
+			Help:     cmd.Flags().Lookup("private-link").Usage,
+			Default:  privateLink,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid private-link value: %s", err)
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+	}
+	if privateLink {
+		err = clusterprovider.ValidatePrivateLink(awsClient, private, subnetIDs)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		reporter.Warnf("The API of a PrivateLink cluster isn't accessible from the internet, " +
+			"only from inside its VPC or from networks connected to it, like peered VPCs, " +
+			"VPNs or AWS Direct Connect")
+	}
+
+	// Cluster-wide proxy:
+	httpProxy := args.httpProxy
+	httpsProxy := args.httpsProxy
+	noProxy := args.noProxy
+	additionalTrustBundleFile := args.additionalTrustBundleFile
+	if len(subnetIDs) == 0 &&
+		(httpProxy != "" || httpsProxy != "" || noProxy != "" || additionalTrustBundleFile != "") {
+		reporter.Errorf("Cluster-wide proxy is only supported when installing into an existing VPC, " +
+			"use the '--subnet-ids' flag to select the subnets")
+		exit.Exit(rerrors.ExitCodeValidation)
+	}
+	if interactive.Enabled() && len(subnetIDs) > 0 {
+		httpProxy, err = interactive.GetString(interactive.Input{
+			Question: "HTTP proxy",
+			Help:     cmd.Flags().Lookup("http-proxy").Usage,
+			Default:  httpProxy,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid HTTP proxy: %s", err)
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+		httpsProxy, err = interactive.GetString(interactive.Input{
+			Question: "HTTPS proxy",
+			Help:     cmd.Flags().Lookup("https-proxy").Usage,
+			Default:  httpsProxy,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid HTTPS proxy: %s", err)
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+		if httpProxy != "" || httpsProxy != "" {
+			noProxy, err = interactive.GetString(interactive.Input{
+				Question: "No proxy",
+				Help:     cmd.Flags().Lookup("no-proxy").Usage,
+				Default:  noProxy,
+			})
+			if err != nil {
+				reporter.Errorf("Expected a valid no-proxy list: %s", err)
+				exit.Exit(rerrors.ExitCodeValidation)
+			}
+		}
+		additionalTrustBundleFile, err = interactive.GetCert(interactive.Input{
+			Question: "Additional trust bundle file path",
+			Help:     cmd.Flags().Lookup("additional-trust-bundle-file").Usage,
+			Default:  additionalTrustBundleFile,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid additional trust bundle file: %s", err)
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+	}
+	err = clusterprovider.ValidateProxy(httpProxy, httpsProxy, noProxy)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	var additionalTrustBundle *string
+	if additionalTrustBundleFile != "" {
+		bundle, err := clusterprovider.ReadAdditionalTrustBundle(additionalTrustBundleFile)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			exit.Exit(rerrors.ExitCode(err))
 		}
+		additionalTrustBundle = &bundle
 	}
 
 	clusterConfig := clusterprovider.Spec{
@@ -596,44 +1150,211 @@ func run(cmd *cobra.Command, _ []string) {
 		Expiration:         expiration,
 		ComputeMachineType: computeMachineType,
 		ComputeNodes:       computeNodes,
+		Autoscaling:        autoscaling,
+		MinReplicas:        minReplicas,
+		MaxReplicas:        maxReplicas,
+		ComputeLabels:      computeLabels,
 		MachineCIDR:        machineCIDR,
 		ServiceCIDR:        serviceCIDR,
 		PodCIDR:            podCIDR,
 		HostPrefix:         hostPrefix,
 		Private:            &private,
+		PrivateLink:        privateLink,
 		DryRun:             &args.dryRun,
 		DisableSCPChecks:   &args.disableSCPChecks,
+		ManualMode:         mode.IsManual(),
 		AvailabilityZones:  availabilityZones,
 		SubnetIds:          subnetIDs,
+		Tags:               tagMap,
+		KMSKeyARN:          kmsKeyARN,
+		EtcdEncryption:     etcdEncryption,
+		FIPS:               fips,
+
+		HTTPProxy:             optionalString(httpProxy),
+		HTTPSProxy:            optionalString(httpsProxy),
+		NoProxy:               optionalString(noProxy),
+		AdditionalTrustBundle: additionalTrustBundle,
 	}
 
-	reporter.Infof("Creating cluster '%s'", clusterName)
-	reporter.Infof("To view a list of clusters and their status, run 'rosa list clusters'")
+	createCluster(cmd, reporter, logger, ocmConnection, awsClient,
+		clusterprovider.NewCreateState(clusterConfig))
+}
 
-	cluster, err := clusterprovider.CreateCluster(ocmClient.Clusters(), clusterConfig)
-	if err != nil {
+// createCluster runs the steps of the creation of the cluster that haven't been completed yet.
+// Unless it is a dry run the state is saved after each step, so that when a step fails or the user
+// interrupts the command the creation can be resumed later. The request that creates the cluster
+// isn't aborted when interrupted, as it would then be impossible to know if the cluster exists.
+func createCluster(cmd *cobra.Command, reporter *rprtr.Object, logger *logrus.Logger,
+	ocmConnection *sdk.Connection, awsClient aws.Client, state *clusterprovider.CreateState) {
+	ocmClient := ocmConnection.ClustersMgmt().V1()
+	clusterConfig := state.Spec
+	clusterName := clusterConfig.Name
+
+	ctx, cancel := runner.WithInterrupt(context.Background())
+	defer cancel()
+
+	save := func() {
 		if args.dryRun {
-			reporter.Errorf("Creating cluster '%s' should fail: %s", clusterName, err)
+			return
+		}
+		err := clusterprovider.SaveCreateState(state)
+		if err != nil {
+			reporter.Warnf("Failed to save the state of the creation of the cluster: %v", err)
+		}
+	}
+	stop := func(err error) {
+		if ctx.Err() != nil {
+			reporter.Errorf("Interrupted: %v", err)
 		} else {
-			reporter.Errorf("Failed to create cluster: %s", err)
+			reporter.Errorf("%v", err)
+		}
+		if !args.dryRun {
+			reporter.Infof("To continue the creation of the cluster, run '%s'", state.ResumeCommand())
+		}
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	checkInterrupt := func() {
+		if ctx.Err() != nil {
+			stop(fmt.Errorf("Creation of cluster '%s' was interrupted", clusterName))
+		}
+	}
+	save()
+
+	quotaOptions := quota.Options{
+		MultiAZ:            clusterConfig.MultiAZ,
+		ComputeNodes:       clusterConfig.ComputeNodes,
+		ComputeMachineType: clusterConfig.ComputeMachineType,
+	}
+	if !state.Done(clusterprovider.CreateStepPreflight) {
+		reporter.Infof("Running pre-flight checks for the AWS account...")
+		err := runPreflightChecks(ctx, reporter, logger, ocmClient, awsClient, quotaOptions)
+		if err != nil {
+			stop(err)
+		}
+		checkInterrupt()
+		state.Complete(clusterprovider.CreateStepPreflight)
+		save()
+	}
+
+	if args.estimateCost {
+		confirmed, err := showCostEstimate(reporter, ocmClient, awsClient, clusterName, quotaOptions,
+			args.dryRun)
+		if err != nil {
+			stop(err)
+		}
+		if !confirmed {
+			exit.Exit(0)
+		}
+	}
+
+	if !state.Done(clusterprovider.CreateStepTerms) {
+		err := checkTerms(ctx, reporter, ocmConnection, !args.dryRun)
+		if err != nil {
+			stop(err)
 		}
-		os.Exit(1)
+		checkInterrupt()
+		state.Complete(clusterprovider.CreateStepTerms)
+		save()
+	}
+
+	// A previous attempt may have been interrupted after the request that creates the cluster was
+	// sent, so when resuming the cluster is only created if it doesn't exist yet:
+	var cluster *cmv1.Cluster
+	if args.resume != "" {
+		cluster = findCreatedCluster(reporter, ocmClient, awsClient, clusterName)
+	}
+	if cluster == nil {
+		// Tell the user why the command doesn't stop, and wait for the request to finish:
+		finished := make(chan struct{})
+		stopped := make(chan struct{})
+		go func() {
+			defer close(stopped)
+			select {
+			case <-ctx.Done():
+				reporter.Warnf("Waiting for the request that creates cluster '%s' to finish",
+					clusterName)
+			case <-finished:
+			}
+		}()
+		client, err := moactl.NewClient().
+			Logger(logger).
+			Connection(ocmConnection).
+			AWSClient(awsClient).
+			Build()
+		if err != nil {
+			reporter.Errorf("%v", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		step := reporter.Start("Creating cluster '%s'", clusterName)
+		cluster, err = client.CreateCluster(context.Background(), clusterConfig)
+		close(finished)
+		<-stopped
+		if err != nil {
+			step.Fail("%v", err)
+			if args.dryRun {
+				reporter.Errorf("Creating cluster '%s' should fail: %s", clusterName, err)
+			} else {
+				reporter.Errorf("Failed to create cluster: %s", err)
+			}
+			// Missing quota or links between accounts are reported by the API as authorization
+			// errors:
+			if rerrors.ExitCode(err) == rerrors.ExitCodeOCMAuth {
+				reporter.Infof("Run 'rosa link ocm-account' to verify that your accounts are ready " +
+					"to create clusters")
+			}
+			// Options rejected by the API need to be changed, so resuming wouldn't help. This
+			// also avoids confusing an existing cluster with the same name with this one:
+			code := rerrors.ExitCode(err)
+			if args.dryRun {
+				exit.Exit(code)
+			}
+			if code == rerrors.ExitCodeValidation || code == rerrors.ExitCodeConflict {
+				err = clusterprovider.DeleteCreateState(state.Token)
+				if err != nil {
+					reporter.Warnf("%v", err)
+				}
+			} else {
+				reporter.Infof("To continue the creation of the cluster, run '%s'",
+					state.ResumeCommand())
+			}
+			exit.Exit(code)
+		}
+		step.Success()
 	}
 
 	if args.dryRun {
 		reporter.Infof(
 			"Creating cluster '%s' should succeed. Run without the '--dry-run' flag to create the cluster.",
 			clusterName)
-		os.Exit(0)
+		exit.Exit(0)
 	}
 
+	// The cluster exists, so there is nothing left to resume:
+	err := clusterprovider.DeleteCreateState(state.Token)
+	if err != nil {
+		reporter.Warnf("%v", err)
+	}
+	interrupted := ctx.Err() != nil
+	cancel()
+
 	reporter.Infof("Cluster '%s' has been created.", clusterName)
+	operations.Record(reporter, &operations.Operation{
+		Type:        operations.CreateCluster,
+		ClusterID:   cluster.ID(),
+		ClusterName: cluster.Name(),
+	})
+	if clusterConfig.ManualMode {
+		reporter.Infof("Run the following command to tag user '%s' with the cluster:",
+			aws.AdminUserName)
+		fmt.Println(aws.TagUserCommand(aws.AdminUserName, cluster.ID(), cluster.Name()))
+	}
+	reporter.Infof("To view a list of clusters and their status, run 'rosa list clusters'")
 	reporter.Infof(
 		"Once the cluster is installed you will need to add an Identity Provider " +
 			"before you can login into the cluster. See 'rosa create idp --help' " +
 			"for more information.")
 
-	if args.watch {
+	if args.watch && !interrupted {
 		installLogs.Cmd.Run(cmd, []string{cluster.ID()})
 	} else {
 		reporter.Infof(
@@ -645,10 +1366,140 @@ func run(cmd *cobra.Command, _ []string) {
 			clusterName,
 		)
 	}
+	if interrupted {
+		return
+	}
 
 	clusterdescribe.Cmd.Run(cmd, []string{cluster.ID()})
 }
 
+// validateNetwork checks that the CIDR blocks are valid, don't overlap, are large enough for the
+// given number of nodes and, when the cluster is installed into an existing VPC, that they don't
+// collide with it. Failing to get the CIDR blocks of the VPC is only reported as a warning.
+func validateNetwork(reporter *rprtr.Object, awsClient aws.Client, machineCIDR string,
+	serviceCIDR string, podCIDR string, hostPrefix int, nodes int, subnetCIDRs []string,
+	vpcID string) error {
+	err := clusterprovider.ValidateCIDRs(machineCIDR, serviceCIDR, podCIDR, hostPrefix)
+	if err != nil {
+		return err
+	}
+	err = clusterprovider.ValidateNetworkSize(machineCIDR, podCIDR, hostPrefix, nodes)
+	if err != nil {
+		return err
+	}
+	if vpcID == "" {
+		return nil
+	}
+	vpcCIDRs, err := awsClient.GetVPCCIDRBlocks(vpcID)
+	if err != nil {
+		reporter.Warnf("Failed to check the CIDR blocks of VPC '%s': %v", vpcID, err)
+		vpcCIDRs = nil
+	}
+	return clusterprovider.ValidateVPCCIDRs(machineCIDR, serviceCIDR, podCIDR, subnetCIDRs, vpcCIDRs)
+}
+
+// cidrValue returns the given CIDR block, or the default one if it wasn't given.
+func cidrValue(block net.IPNet, defaultBlock *net.IPNet) string {
+	if block.IP != nil {
+		return block.String()
+	}
+	if defaultBlock != nil {
+		return defaultBlock.String()
+	}
+	return ""
+}
+
+// findCreatedCluster returns the cluster with the given name if it has already been created by the
+// user, or nil if it doesn't exist.
+func findCreatedCluster(reporter *rprtr.Object, ocmClient *cmv1.Client, awsClient aws.Client,
+	clusterName string) *cmv1.Cluster {
+	creator, err := awsClient.GetCreator()
+	if err != nil {
+		reporter.Errorf("Failed to get AWS creator: %v", err)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
+	}
+	cluster, err := clusterprovider.GetCluster(ocmClient.Clusters(), clusterName, creator.ARN)
+	if rerrors.ExitCode(err) == rerrors.ExitCodeNotFound {
+		return nil
+	}
+	if err != nil {
+		reporter.Errorf("Failed to get cluster '%s': %v", clusterName, err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	reporter.Infof("Cluster '%s' had already been created", clusterName)
+	return cluster
+}
+
+// applySpecFile reads the cluster spec file and sets the flags that weren't explicitly given in the
+// command line to the values of the file, so that they are also used as the defaults of the
+// interactive prompts.
+func applySpecFile(cmd *cobra.Command, path string) error {
+	spec, err := clusterprovider.ReadSpecFile(path)
+	if err != nil {
+		return err
+	}
+	for name, value := range spec.Flags() {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+		// The deprecated '--name' flag is an alias of '--cluster-name':
+		if name == "cluster-name" && cmd.Flags().Changed("name") {
+			continue
+		}
+		err = cmd.Flags().Set(name, value)
+		if err != nil {
+			return fmt.Errorf("Invalid value '%s' for '%s' in cluster spec file '%s': %v",
+				value, name, path, err)
+		}
+	}
+	return nil
+}
+
+// presetFlags contains the names of the flags whose values were set from the preset.
+var presetFlags = map[string]bool{}
+
+// applyPreset uses the values of the preset for the options that weren't given in the command line
+// or in the spec file.
+func applyPreset(cmd *cobra.Command, name string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	preset, err := clusterprovider.GetPreset(name, cfg.Presets)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(preset))
+	for flagName := range preset {
+		names = append(names, flagName)
+	}
+	sort.Strings(names)
+	for _, flagName := range names {
+		value := preset[flagName]
+		flag := cmd.Flags().Lookup(flagName)
+		if flag == nil || flagName == "preset" || flagName == "file" {
+			return rerrors.ValidationErrorf("Unknown option '%s' in preset '%s'", flagName, name)
+		}
+		if flag.Changed {
+			continue
+		}
+		err = cmd.Flags().Set(flagName, value)
+		if err != nil {
+			return rerrors.ValidationErrorf("Invalid value '%s' for '%s' in preset '%s': %v",
+				value, flagName, name, err)
+		}
+		presetFlags[flagName] = true
+	}
+	return nil
+}
+
+// explicitFlag checks if the flag was given in the command line or in the spec file, and not only
+// by the preset.
+func explicitFlag(cmd *cobra.Command, name string) bool {
+	return cmd.Flags().Changed(name) && !presetFlags[name]
+}
+
 // Validate OpenShift versions
 func validateVersion(version string, versionList []string) (string, error) {
 	if version != "" {
@@ -723,14 +1574,60 @@ func parseRFC3339(s string) (time.Time, error) {
 	return time.Parse(time.RFC3339, s)
 }
 
-const subnetTemplate = "%s (%s)"
+// optionalString returns a pointer to the value, or nil if the value is empty.
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+const subnetTemplate = "%s (%s, %s)"
 
 // Creates a subnet options using a predefined template.
-func setSubnetOption(subnet, zone string) string {
-	return fmt.Sprintf(subnetTemplate, subnet, zone)
+func setSubnetOption(subnet, vpc, zone string) string {
+	return fmt.Sprintf(subnetTemplate, subnet, vpc, zone)
 }
 
 // Parses the subnet from the option chosen by the user.
 func parseSubnet(subnetOption string) string {
 	return strings.Split(subnetOption, " ")[0]
 }
+
+// getComputeMachineTypeOptions returns the instance types whose compute nodes fit in the AWS quota of
+// vCPUs, so that they can be offered in interactive mode. If the quota can't be checked all the
+// instance types are returned.
+func getComputeMachineTypeOptions(cmd *cobra.Command, reporter *rprtr.Object, ocmClient *cmv1.Client,
+	awsClient aws.Client, multiAZ bool, machineTypeList []string) []string {
+	computeNodes := args.computeNodes
+	if multiAZ && !cmd.Flags().Changed("compute-nodes") {
+		computeNodes = 3
+	}
+	vcpuQuota, err := quota.GetVCPUQuota(awsClient)
+	if err != nil {
+		reporter.Debugf("Failed to get AWS quota of vCPUs, offering all instance types: %v", err)
+		return machineTypeList
+	}
+	flavour, err := quota.GetFlavour(ocmClient)
+	if err != nil {
+		reporter.Debugf("%v, offering all instance types", err)
+		return machineTypeList
+	}
+	machineTypes, err := machines.GetMachineTypes(ocmClient)
+	if err != nil {
+		reporter.Debugf("Failed to retrieve machine types, offering all instance types: %v", err)
+		return machineTypeList
+	}
+	options := []string{}
+	for _, machineType := range quota.FilterMachineTypes(flavour, machineTypes, quota.Options{
+		MultiAZ:      multiAZ,
+		ComputeNodes: computeNodes,
+	}, vcpuQuota) {
+		options = append(options, machineType.ID())
+	}
+	if len(options) == 0 {
+		reporter.Warnf("None of the instance types fit in the AWS quota of %d vCPUs", vcpuQuota)
+		return machineTypeList
+	}
+	return options
+}
@@ -0,0 +1,66 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the estimate of the monthly AWS cost of the cluster that is shown before it
+// is created, when requested with '--estimate-cost'.
+
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/confirm"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/verify/cost"
+	"github.com/openshift/moactl/pkg/verify/quota"
+)
+
+// showCostEstimate prints the estimated monthly cost of each of the AWS resources of the cluster
+// and asks the user to confirm that the cluster should be created, unless it is a dry run. Failures
+// to get the prices are only reported as a warning, as the estimate is informative.
+func showCostEstimate(reporter *rprtr.Object, ocmClient *cmv1.Client, awsClient aws.Client,
+	clusterName string, options quota.Options, dryRun bool) (bool, error) {
+	step := reporter.Start("Estimating the cost of the AWS resources")
+	estimate, err := cost.GetEstimate(ocmClient, awsClient, options)
+	if err != nil {
+		step.Fail("%v", err)
+		reporter.Warnf("Failed to estimate the cost of cluster '%s': %v", clusterName, err)
+		return true, nil
+	}
+	step.Success()
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "RESOURCE\tINSTANCE TYPE\tCOUNT\tUSD/HOUR\tUSD/MONTH\n")
+	for _, item := range estimate.Items {
+		fmt.Fprintf(writer, "%s\t%s\t%d\t%.4f\t%.2f\n", item.Name, item.InstanceType, item.Count,
+			item.HourlyPrice, item.MonthlyCost())
+	}
+	fmt.Fprintf(writer, "Total\t\t\t\t%.2f\n", estimate.Total())
+	writer.Flush()
+	reporter.Infof("The estimate uses on-demand prices in region '%s' and excludes storage, data "+
+		"transfer and the fees of the service", awsClient.GetRegion())
+
+	if dryRun {
+		return true, nil
+	}
+	return confirm.Confirm("create cluster %s with an estimated AWS cost of %.2f USD per month",
+		clusterName, estimate.Total())
+}
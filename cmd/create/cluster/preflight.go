@@ -0,0 +1,92 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the pre-flight checks that verify that the AWS account is ready before the
+// cluster is created.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/moactl/pkg/aws"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/verify/quota"
+)
+
+// runPreflightChecks checks the credentials, permissions, quota and CloudFormation stack of the
+// AWS account at the same time, and returns the failures of all of them together.
+func runPreflightChecks(ctx context.Context, reporter *rprtr.Object, logger *logrus.Logger,
+	ocmClient *cmv1.Client, awsClient aws.Client, options quota.Options) error {
+	// The CloudFormation stack is always created in the default region:
+	stackClient, err := aws.NewClient().
+		Logger(logger).
+		Region(aws.GetDefaultRegion()).
+		Build()
+	if err != nil {
+		return fmt.Errorf("Failed to create AWS client: %v", err)
+	}
+
+	var creator aws.Creator
+	checks := []aws.PreflightCheck{
+		aws.CreatorPreflightCheck(awsClient, &creator),
+		aws.PermissionsPreflightCheck(awsClient),
+		quotaPreflightCheck(ocmClient, awsClient, options),
+		aws.StackPreflightCheck(stackClient),
+	}
+
+	step := reporter.Start("Running %d pre-flight checks", len(checks))
+	err = aws.RunPreflightChecks(ctx, checks, func(done int, total int) {
+		step.UpdatePercent(done*100/total, "%d of %d checks done", done, total)
+	})
+	if err != nil {
+		step.Fail("%v", err)
+		return err
+	}
+	step.Success()
+	reporter.Infof("Pre-flight checks passed for '%s'", creator.ARN)
+	return nil
+}
+
+// quotaPreflightCheck checks that the AWS service quotas are enough for a cluster with the given
+// options.
+func quotaPreflightCheck(ocmClient *cmv1.Client, awsClient aws.Client,
+	options quota.Options) aws.PreflightCheck {
+	return aws.PreflightCheck{
+		Name: "AWS quota",
+		Run: func(ctx context.Context) error {
+			requirements, err := quota.GetRequirements(ocmClient, options)
+			if err != nil {
+				return err
+			}
+			failed := []string{}
+			for _, result := range quota.Verify(awsClient, requirements) {
+				if !result.Passed {
+					failed = append(failed, fmt.Sprintf("%s: %s", result.Name, result.Details))
+				}
+			}
+			if len(failed) > 0 {
+				return fmt.Errorf("%s. Run 'rosa verify quota' for details", strings.Join(failed, "; "))
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the check that the terms and conditions required to create clusters have
+// been accepted, asking the user to accept them when possible.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/helper"
+	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/ocm/terms"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+)
+
+// Time between reviews of the terms, and maximum time to wait for the user to accept them:
+const (
+	termsPollInterval = 10 * time.Second
+	termsTimeout      = 30 * time.Minute
+)
+
+// checkTerms checks that the terms and conditions have been accepted. When they haven't, it prints
+// the page where they can be accepted and, if waiting is requested and questions can be asked,
+// offers to open it in the browser and waits till they are accepted. Failures to review the terms are only reported as a
+// warning, as the request to create the cluster will then fail with the details.
+func checkTerms(ctx context.Context, reporter *rprtr.Object, connection *sdk.Connection,
+	wait bool) error {
+	review, err := terms.GetReview(ctx, connection)
+	if err != nil {
+		reporter.Warnf("%v", err)
+		return nil
+	}
+	if review.Accepted() {
+		return nil
+	}
+
+	reporter.Warnf("Terms and conditions must be accepted before creating clusters. "+
+		"Review and accept them at:\n\n  %s\n", review.URL())
+	if !wait || interactive.CheckPrompt() != nil {
+		return rerrors.ConflictErrorf("Terms and conditions haven't been accepted, run "+
+			"'rosa verify terms --watch' after accepting them at %s", review.URL())
+	}
+
+	open, err := interactive.GetBool(interactive.Input{
+		Question: "Open the terms and conditions in the browser",
+		Help:     "The cluster will be created once the terms and conditions are accepted.",
+		Default:  true,
+	})
+	if err != nil {
+		return fmt.Errorf("Expected a valid value: %s", err)
+	}
+	if open {
+		err = helper.OpenBrowser(review.URL())
+		if err != nil {
+			reporter.Warnf("%v", err)
+		}
+	}
+
+	reporter.Infof("Waiting for the terms and conditions to be accepted...")
+	err = terms.Wait(ctx, connection, termsPollInterval, termsTimeout)
+	if err != nil {
+		return fmt.Errorf("Failed to wait for terms and conditions: %w", err)
+	}
+	reporter.Infof("Terms and conditions have been accepted")
+	return nil
+}
@@ -19,12 +19,20 @@ package create
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/moactl/cmd/create/accountroles"
 	"github.com/openshift/moactl/cmd/create/addon"
 	"github.com/openshift/moactl/cmd/create/admin"
+	"github.com/openshift/moactl/cmd/create/breakglass"
+	"github.com/openshift/moactl/cmd/create/breakglasscredential"
 	"github.com/openshift/moactl/cmd/create/cluster"
 	"github.com/openshift/moactl/cmd/create/idp"
 	"github.com/openshift/moactl/cmd/create/ingress"
+	"github.com/openshift/moactl/cmd/create/kubeconfig"
 	"github.com/openshift/moactl/cmd/create/machinepool"
+	"github.com/openshift/moactl/cmd/create/notificationcontact"
+	"github.com/openshift/moactl/cmd/create/oidcprovider"
+	"github.com/openshift/moactl/cmd/create/operatorroles"
+	"github.com/openshift/moactl/cmd/create/schedule"
 	"github.com/openshift/moactl/pkg/interactive"
 )
 
@@ -36,12 +44,20 @@ var Cmd = &cobra.Command{
 }
 
 func init() {
+	Cmd.AddCommand(accountroles.Cmd)
 	Cmd.AddCommand(addon.Cmd)
 	Cmd.AddCommand(admin.Cmd)
+	Cmd.AddCommand(breakglass.Cmd)
+	Cmd.AddCommand(breakglasscredential.Cmd)
 	Cmd.AddCommand(cluster.Cmd)
 	Cmd.AddCommand(idp.Cmd)
 	Cmd.AddCommand(ingress.Cmd)
+	Cmd.AddCommand(kubeconfig.Cmd)
 	Cmd.AddCommand(machinepool.Cmd)
+	Cmd.AddCommand(notificationcontact.Cmd)
+	Cmd.AddCommand(oidcprovider.Cmd)
+	Cmd.AddCommand(operatorroles.Cmd)
+	Cmd.AddCommand(schedule.Cmd)
 
 	flags := Cmd.PersistentFlags()
 	interactive.AddFlag(flags)
@@ -18,7 +18,6 @@ package idp
 
 import (
 	"fmt"
-	"os"
 	"regexp"
 	"strconv"
 	"strings"
@@ -27,6 +26,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/pkg/aws"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/interactive"
 	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm"
@@ -280,7 +281,7 @@ func run(cmd *cobra.Command, _ []string) {
 				"must contain only letters, digits, dashes and underscores",
 			clusterKey,
 		)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	// Create the AWS client:
@@ -289,13 +290,13 @@ func run(cmd *cobra.Command, _ []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	awsCreator, err := awsClient.GetCreator()
 	if err != nil {
 		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	// Create the client for the OCM API:
@@ -304,7 +305,7 @@ func run(cmd *cobra.Command, _ []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
 	}
 	defer func() {
 		err = ocmConnection.Close()
@@ -321,12 +322,12 @@ func run(cmd *cobra.Command, _ []string) {
 	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
 	if err != nil {
 		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	if cluster.State() != cmv1.ClusterStateReady {
 		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeConflict)
 	}
 
 	if interactive.Enabled() {
@@ -343,17 +344,18 @@ func run(cmd *cobra.Command, _ []string) {
 		idpType, err = interactive.GetOption(interactive.Input{
 			Question: "Type of identity provider",
 			Options:  validIdps,
+			Flag:     "type",
 			Required: true,
 			Default:  idpType,
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid IdP type: %s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 	}
 	if idpType == "" {
 		reporter.Errorf("Expected a valid IDP type. Options are: %s", strings.Join(validIdps, ","))
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	if idpType != "" {
@@ -365,7 +367,7 @@ func run(cmd *cobra.Command, _ []string) {
 		}
 		if !isValidIdp {
 			reporter.Errorf("Expected a valid IDP type. Options are %s", validIdps)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 	}
 
@@ -379,7 +381,7 @@ func run(cmd *cobra.Command, _ []string) {
 		isValidIdpName := idRE.MatchString(idpName)
 		if !isValidIdpName {
 			reporter.Errorf("Invalid identifier '%s' for 'name'", idpName)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 	}
 	if interactive.Enabled() {
@@ -391,7 +393,7 @@ func run(cmd *cobra.Command, _ []string) {
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid name for the identity provider: %s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 	}
 
@@ -410,7 +412,7 @@ func run(cmd *cobra.Command, _ []string) {
 	}
 	if err != nil {
 		reporter.Errorf("Failed to create IDP for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	reporter.Infof("Configuring IDP for cluster '%s'", clusterKey)
@@ -418,7 +420,7 @@ func run(cmd *cobra.Command, _ []string) {
 	idp, err := idpBuilder.Build()
 	if err != nil {
 		reporter.Errorf("Failed to create IDP for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	res, err := clustersCollection.Cluster(cluster.ID()).
@@ -427,9 +429,9 @@ func run(cmd *cobra.Command, _ []string) {
 		Body(idp).
 		Send()
 	if err != nil {
-		reporter.Debugf(err.Error())
-		reporter.Errorf("Failed to add IDP to cluster '%s': %s", clusterKey, res.Error().Reason())
-		os.Exit(1)
+		err = rerrors.FromOCM(res.Error(), err)
+		reporter.Errorf("Failed to add IDP to cluster '%s': %v", clusterKey, err)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	reporter.Infof(
@@ -485,7 +487,7 @@ func getIdps(reporter *reporter.Object, clusters *cmv1.ClustersClient, cluster *
 	ocmIdps, err := ocm.GetIdentityProviders(clusters, cluster.ID())
 	if err != nil {
 		reporter.Errorf("Failed to get identity providers for cluster '%s': %v", cluster.ID(), err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 	idps := []IdentityProvider{}
 	for _, idp := range ocmIdps {
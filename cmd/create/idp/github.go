@@ -95,6 +95,11 @@ func buildGithubIdp(cmd *cobra.Command,
 	clientID := args.clientID
 	clientSecret := args.clientSecret
 	if clientID == "" || clientSecret == "" {
+		err = interactive.RequireFlags(cmd.Flags(), "client-id", "client-secret")
+		if err != nil {
+			return idpBuilder, err
+		}
+
 		// Create the full URL to automatically generate the GitHub app info
 		registerURLBase := "https://github.com/settings/applications/new"
 
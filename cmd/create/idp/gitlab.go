@@ -36,12 +36,17 @@ func buildGitlabIdp(cmd *cobra.Command,
 	clientSecret := args.clientSecret
 	gitlabURL := args.gitlabURL
 
-	if !cmd.Flags().Changed("host-url") {
+	// The host URL has a default, so only ask for it when questions can be asked:
+	if !cmd.Flags().Changed("host-url") && interactive.CheckPrompt() == nil {
 		gitlabURL, err = interactive.GetString(interactive.Input{
 			Question: "URL",
 			Help:     cmd.Flags().Lookup("host-url").Usage,
 			Default:  gitlabURL,
 			Required: true,
+			Flag:     "host-url",
+			Validators: []interactive.Validator{
+				interactive.URLValidator,
+			},
 		})
 		if err != nil {
 			return idpBuilder, fmt.Errorf("Expected a valid GitLab provider URL: %s", err)
@@ -62,6 +67,11 @@ func buildGitlabIdp(cmd *cobra.Command,
 	}
 
 	if clientID == "" || clientSecret == "" {
+		err = interactive.RequireFlags(cmd.Flags(), "client-id", "client-secret")
+		if err != nil {
+			return idpBuilder, err
+		}
+
 		instructionsURL := fmt.Sprintf("%s/profile/applications", gitlabURL)
 		consoleURL := cluster.Console().URL()
 		oauthURL := strings.Replace(consoleURL, "console-openshift-console", "oauth-openshift", 1)
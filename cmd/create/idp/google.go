@@ -35,6 +35,11 @@ func buildGoogleIdp(cmd *cobra.Command,
 	clientSecret := args.clientSecret
 
 	if clientID == "" || clientSecret == "" {
+		err = interactive.RequireFlags(cmd.Flags(), "client-id", "client-secret")
+		if err != nil {
+			return idpBuilder, err
+		}
+
 		instructionsURL := "https://console.developers.google.com/projectcreate"
 		consoleURL := cluster.Console().URL()
 		oauthURL := strings.Replace(consoleURL, "console-openshift-console", "oauth-openshift", 1)
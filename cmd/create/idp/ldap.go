@@ -36,6 +36,11 @@ func buildLdapIdp(cmd *cobra.Command,
 	ldapIDs := args.ldapIDs
 
 	if ldapURL == "" || ldapIDs == "" {
+		err = interactive.RequireFlags(cmd.Flags(), "url", "id-attributes")
+		if err != nil {
+			return idpBuilder, err
+		}
+
 		instructionsURL := "https://docs.openshift.com/dedicated/4/authentication/" +
 			"identity_providers/configuring-ldap-identity-provider.html"
 		err = interactive.PrintHelp(interactive.Help{
@@ -57,6 +62,9 @@ func buildLdapIdp(cmd *cobra.Command,
 			Help:     cmd.Flags().Lookup("url").Usage,
 			Default:  ldapURL,
 			Required: true,
+			Validators: []interactive.Validator{
+				interactive.URLValidator,
+			},
 		})
 		if err != nil {
 			return idpBuilder, fmt.Errorf("Expected a valid LDAP URL: %s", err)
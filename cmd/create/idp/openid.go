@@ -43,6 +43,11 @@ func buildOpenidIdp(cmd *cobra.Command,
 		(email == "" && name == "" && username == "")
 
 	if isInteractive {
+		err = interactive.RequireFlags(cmd.Flags(), "client-id", "client-secret", "issuer-url")
+		if err != nil {
+			return idpBuilder, err
+		}
+
 		instructionsURL := "https://docs.openshift.com/dedicated/4/authentication/" +
 			"identity_providers/configuring-oidc-identity-provider.html"
 		oauthURL := strings.Replace(cluster.Console().URL(), "console-openshift-console", "oauth-openshift", 1)
@@ -90,6 +95,9 @@ func buildOpenidIdp(cmd *cobra.Command,
 			Help:     cmd.Flags().Lookup("issuer-url").Usage,
 			Default:  issuerURL,
 			Required: true,
+			Validators: []interactive.Validator{
+				interactive.URLValidator,
+			},
 		})
 		if err != nil {
 			return idpBuilder, fmt.Errorf("Expected a valid OpenID Issuer URL: %s", err)
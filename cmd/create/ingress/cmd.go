@@ -18,13 +18,14 @@ package ingress
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/pkg/aws"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/interactive"
 	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm"
@@ -94,7 +95,7 @@ func run(cmd *cobra.Command, _ []string) {
 				"must contain only letters, digits, dashes and underscores",
 			clusterKey,
 		)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	labelMatch := args.labelMatch
@@ -108,14 +109,14 @@ func run(cmd *cobra.Command, _ []string) {
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid comma-separated list of attributes: %s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 	}
 	if labelMatch != "" {
 		routeSelectors, err = getRouteSelector(labelMatch)
 		if err != nil {
 			reporter.Errorf("%s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCode(err))
 		}
 	}
 
@@ -125,13 +126,13 @@ func run(cmd *cobra.Command, _ []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	awsCreator, err := awsClient.GetCreator()
 	if err != nil {
 		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	// Create the client for the OCM API:
@@ -140,7 +141,7 @@ func run(cmd *cobra.Command, _ []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
 	}
 	defer func() {
 		err = ocmConnection.Close()
@@ -157,12 +158,12 @@ func run(cmd *cobra.Command, _ []string) {
 	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
 	if err != nil {
 		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	if cluster.State() != cmv1.ClusterStateReady {
 		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeConflict)
 	}
 
 	ingressBuilder := cmv1.NewIngress()
@@ -181,7 +182,7 @@ func run(cmd *cobra.Command, _ []string) {
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid private value: %s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 		if private {
 			ingressBuilder = ingressBuilder.Listening(cmv1.ListeningMethodInternal)
@@ -196,7 +197,7 @@ func run(cmd *cobra.Command, _ []string) {
 	ingress, err := ingressBuilder.Build()
 	if err != nil {
 		reporter.Errorf("Failed to create ingress for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	res, err := clustersCollection.Cluster(cluster.ID()).
@@ -205,9 +206,9 @@ func run(cmd *cobra.Command, _ []string) {
 		Body(ingress).
 		Send()
 	if err != nil {
-		reporter.Debugf(err.Error())
-		reporter.Errorf("Failed to add ingress to cluster '%s': %s", clusterKey, res.Error().Reason())
-		os.Exit(1)
+		err = rerrors.FromOCM(res.Error(), err)
+		reporter.Errorf("Failed to add ingress to cluster '%s': %v", clusterKey, err)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 }
 
@@ -0,0 +1,207 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"fmt"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
+	"github.com/openshift/moactl/pkg/kubeconfig"
+	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/admin"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+)
+
+var args struct {
+	clusterKey string
+	path       string
+	overwrite  bool
+}
+
+var Cmd = &cobra.Command{
+	Use:   "kubeconfig",
+	Short: "Create a kubeconfig file to access the cluster",
+	Long: "Create a kubeconfig file with the admin credentials of the cluster, merging it into the " +
+		"existing kubeconfig file. If the admin credentials aren't available the command used to " +
+		"login to the cluster is printed instead.",
+	Example: `  # Add the credentials of cluster "mycluster" to the default kubeconfig file
+  rosa create kubeconfig --cluster=mycluster
+
+  # Write the credentials of cluster "mycluster" to a new kubeconfig file
+  rosa create kubeconfig --cluster=mycluster --path=mycluster.kubeconfig --overwrite`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to create the kubeconfig for (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	flags.StringVar(
+		&args.path,
+		"path",
+		"",
+		"Path of the kubeconfig file. The default is the first file of the KUBECONFIG "+
+			"environment variable, or '~/.kube/config' if it isn't set.",
+	)
+
+	flags.BoolVar(
+		&args.overwrite,
+		"overwrite",
+		false,
+		"Replace the kubeconfig file instead of merging the credentials of the cluster into it.",
+	)
+}
+
+func run(_ *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := args.clusterKey
+	if !ocm.IsValidClusterKey(clusterKey) {
+		reporter.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+		exit.Exit(rerrors.ExitCodeValidation)
+	}
+
+	path := args.path
+	if path == "" {
+		var err error
+		path, err = kubeconfig.DefaultPath()
+		if err != nil {
+			reporter.Errorf("Failed to find the default kubeconfig file: %v", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+	}
+
+	// Create the AWS client:
+	awsClient, err := aws.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create AWS client: %v", err)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
+	}
+
+	awsCreator, err := awsClient.GetCreator()
+	if err != nil {
+		reporter.Errorf("Failed to get AWS creator: %v", err)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
+	}
+
+	// Create the client for the OCM API:
+	ocmConnection, err := ocm.NewConnection().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
+	}
+	defer func() {
+		err = ocmConnection.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
+
+	// Get the client for the OCM collection of clusters:
+	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
+	if err != nil {
+		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	if cluster.State() != cmv1.ClusterStateReady {
+		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
+		exit.Exit(rerrors.ExitCodeConflict)
+	}
+
+	reporter.Debugf("Loading credentials of cluster '%s'", clusterKey)
+	credentials, err := clusterprovider.GetCredentials(clustersCollection, cluster.ID())
+	if err != nil {
+		reporter.Errorf("Failed to get credentials of cluster '%s': %v", clusterKey, err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	// Without an admin kubeconfig the user needs to login with a password, which only 'oc login'
+	// can exchange for a token:
+	if credentials == nil || credentials.Kubeconfig() == "" {
+		idp, err := admin.GetIdentityProvider(clustersCollection, cluster.ID())
+		if err != nil {
+			reporter.Errorf("Failed to get '%s' identity provider for cluster '%s': %v",
+				admin.IdpName, clusterKey, err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		if idp == nil {
+			reporter.Errorf("The admin credentials of cluster '%s' aren't available. To create an "+
+				"admin run the following command:\n"+
+				"   rosa create admin -c %s", clusterKey, clusterKey)
+			exit.Exit(rerrors.ExitCodeGeneric)
+		}
+		username := idp.Htpasswd().Username()
+		reporter.Warnf("The admin kubeconfig of cluster '%s' isn't available. To login and add the "+
+			"cluster to '%s', run the following command and enter the password of the admin:\n"+
+			"   KUBECONFIG=%s %s", clusterKey, path, path, clusterprovider.LoginCommand(cluster, username))
+		exit.Exit(rerrors.ExitCodeGeneric)
+	}
+
+	config, err := kubeconfig.Parse([]byte(credentials.Kubeconfig()))
+	if err != nil {
+		reporter.Errorf("Failed to read kubeconfig of cluster '%s': %v", clusterKey, err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	// Use the name of the cluster for the entries, so that they don't collide with the entries
+	// of other clusters:
+	err = config.Rename(cluster.Name(), fmt.Sprintf("admin/%s", cluster.Name()))
+	if err != nil {
+		reporter.Errorf("Failed to read kubeconfig of cluster '%s': %v", clusterKey, err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	err = kubeconfig.Write(path, config, !args.overwrite)
+	if err != nil {
+		reporter.Errorf("%v", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	reporter.Infof("Kubeconfig of cluster '%s' written to '%s', using context '%s'",
+		clusterKey, path, config.CurrentContext)
+}
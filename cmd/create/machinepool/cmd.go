@@ -17,20 +17,24 @@ limitations under the License.
 package machinepool
 
 import (
+	"context"
 	"fmt"
-	"os"
 	"regexp"
-	"strings"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/pkg/aws"
 	c "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/interactive"
 	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/moactl"
 	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/machinepools"
 	"github.com/openshift/moactl/pkg/ocm/machines"
+	"github.com/openshift/moactl/pkg/operations"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
 )
 
@@ -45,6 +49,12 @@ var args struct {
 	replicas     int
 	labels       string
 	taints       string
+
+	enableCustomerManagedKey bool
+	kmsKeyARN                string
+
+	useSpotInstances bool
+	spotMaxPrice     string
 }
 
 var Cmd = &cobra.Command{
@@ -59,7 +69,13 @@ var Cmd = &cobra.Command{
   rosa create machinepool --cluster=mycluster --name=mp-1 --replicas=3 --instance-type=m5.xlarge
 
   # Add a machine pool with labels to a cluster
-  rosa create machinepool -c mycluster --name=mp-1 --replicas=2 --instance-type=r5.2xlarge --labels =foo=bar,bar=baz"`,
+  rosa create machinepool -c mycluster --name=mp-1 --replicas=2 --instance-type=r5.2xlarge --labels=foo=bar,bar=baz
+
+  # Add a machine pool with taints to a cluster
+  rosa create machinepool -c mycluster --name=mp-1 --replicas=2 --taints=dedicated=gpu:NoSchedule
+
+  # Add a machine pool that uses spot instances with a maximum price of 0.5 USD per hour
+  rosa create machinepool -c mycluster --name=mp-1 --replicas=2 --use-spot-instances --spot-max-price=0.5`,
 	Run: run,
 }
 
@@ -108,9 +124,40 @@ func init() {
 		&args.taints,
 		"taints",
 		"",
-		"Taints for machine pool. Format should be a comma-separated list of 'key=value:ScheduleType'. "+
+		"Taints for machine pool. Format should be a comma-separated list of 'key=value:Effect', "+
+			"where the effect is 'NoSchedule', 'PreferNoSchedule' or 'NoExecute'. "+
 			"This list will overwrite any modifications made to Node taints on an ongoing basis.",
 	)
+
+	flags.BoolVar(
+		&args.enableCustomerManagedKey,
+		"enable-customer-managed-key",
+		false,
+		"Encrypt the EBS volumes of the nodes with the customer managed KMS key given in "+
+			"'--kms-key-arn' instead of the key of the cluster.",
+	)
+	flags.StringVar(
+		&args.kmsKeyARN,
+		"kms-key-arn",
+		"",
+		"ARN of the customer managed KMS key used to encrypt the EBS volumes of the nodes. The key "+
+			"must be an enabled symmetric key in the region of the cluster.",
+	)
+
+	flags.BoolVar(
+		&args.useSpotInstances,
+		"use-spot-instances",
+		false,
+		"Use spot instances for the nodes of the machine pool. Spot instances are cheaper, but AWS "+
+			"can interrupt them at any time, so they are only suitable for workloads that tolerate it.",
+	)
+	flags.StringVar(
+		&args.spotMaxPrice,
+		"spot-max-price",
+		c.SpotMaxPriceOnDemand,
+		"Maximum price in USD per hour of the spot instances, or 'on-demand' to use the on-demand "+
+			"price of the instance type.",
+	)
 }
 
 func run(cmd *cobra.Command, _ []string) {
@@ -126,7 +173,7 @@ func run(cmd *cobra.Command, _ []string) {
 				"must contain only letters, digits, dashes and underscores",
 			clusterKey,
 		)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	// Create the AWS client:
@@ -136,13 +183,13 @@ func run(cmd *cobra.Command, _ []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	awsCreator, err := awsClient.GetCreator()
 	if err != nil {
 		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	// Create the client for the OCM API:
@@ -151,7 +198,7 @@ func run(cmd *cobra.Command, _ []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
 	}
 	defer func() {
 		err = ocmConnection.Close()
@@ -168,12 +215,12 @@ func run(cmd *cobra.Command, _ []string) {
 	cluster, err := ocm.GetCluster(ocmClient.Clusters(), clusterKey, awsCreator.ARN)
 	if err != nil {
 		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	if cluster.State() != cmv1.ClusterStateReady {
 		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeConflict)
 	}
 
 	// Machine pool name:
@@ -187,15 +234,16 @@ func run(cmd *cobra.Command, _ []string) {
 			Question: "Machine pool name",
 			Default:  name,
 			Required: true,
+			Flag:     "name",
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid name for the machine pool: %s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 	}
 	if !machinePoolKeyRE.MatchString(name) {
 		reporter.Errorf("Expected a valid name for the machine pool")
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	// Number of replicas:
@@ -206,10 +254,11 @@ func run(cmd *cobra.Command, _ []string) {
 			Help:     cmd.Flags().Lookup("replicas").Usage,
 			Default:  replicas,
 			Required: true,
+			Flag:     "replicas",
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid number of replicas: %s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 	}
 
@@ -218,7 +267,7 @@ func run(cmd *cobra.Command, _ []string) {
 	instanceTypeList, err := machines.GetMachineTypeList(ocmClient)
 	if err != nil {
 		reporter.Errorf(fmt.Sprintf("%s", err))
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 	if interactive.Enabled() {
 		if instanceType == "" {
@@ -233,21 +282,20 @@ func run(cmd *cobra.Command, _ []string) {
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid machine type: %s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 	}
 	if instanceType == "" {
 		reporter.Errorf("Expected a valid machine type")
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 	instanceType, err = machines.ValidateMachineType(instanceType, instanceTypeList)
 	if err != nil {
 		reporter.Errorf("Expected a valid machine type: %s", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	labels := args.labels
-	labelMap := make(map[string]string)
 	if interactive.Enabled() {
 		labels, err = interactive.GetString(interactive.Input{
 			Question: "Labels",
@@ -256,70 +304,144 @@ func run(cmd *cobra.Command, _ []string) {
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid comma-separated list of attributes: %s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 	}
-	if labels != "" {
-		for _, label := range strings.Split(labels, ",") {
-			if !strings.Contains(label, "=") {
-				reporter.Errorf("Expected key=value format for labels")
-				os.Exit(1)
-			}
-			tokens := strings.Split(label, "=")
-			labelMap[strings.TrimSpace(tokens[0])] = strings.TrimSpace(tokens[1])
-		}
+	labelMap, err := machinepools.ParseLabels(labels)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	taints := args.taints
-	taintBuilders := []*cmv1.TaintBuilder{}
 	if interactive.Enabled() {
 		taints, err = interactive.GetString(interactive.Input{
 			Question: "Taints",
 			Help:     cmd.Flags().Lookup("taints").Usage,
-			Default:  labels,
+			Default:  taints,
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid comma-separated list of attributes: %s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 	}
-	if taints != "" {
-		for _, taint := range strings.Split(taints, ",") {
-			if !strings.Contains(taint, "=") || !strings.Contains(taint, ":") {
-				reporter.Errorf("Expected key=value:scheduleType format for taints")
-				os.Exit(1)
-			}
-			tokens := strings.FieldsFunc(taint, Split)
-			taintBuilders = append(taintBuilders, cmv1.NewTaint().Key(tokens[0]).Value(tokens[1]).Effect(tokens[2]))
+	taintBuilders, err := machinepools.ParseTaints(taints)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	// Encryption of the EBS volumes with a customer managed key:
+	enableCustomerManagedKey := args.enableCustomerManagedKey
+	kmsKeyARN := args.kmsKeyARN
+	if interactive.Enabled() {
+		enableCustomerManagedKey, err = interactive.GetBool(interactive.Input{
+			Question: "Enable customer managed key",
+			Help:     cmd.Flags().Lookup("enable-customer-managed-key").Usage,
+			Default:  enableCustomerManagedKey,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid value for enable-customer-managed-key: %s", err)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 	}
+	if interactive.Enabled() && enableCustomerManagedKey {
+		kmsKeyARN, err = interactive.GetString(interactive.Input{
+			Question: "KMS key ARN",
+			Help:     cmd.Flags().Lookup("kms-key-arn").Usage,
+			Default:  kmsKeyARN,
+			Required: true,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid KMS key ARN: %s", err)
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+	}
+	if enableCustomerManagedKey || kmsKeyARN != "" {
+		// The key must be in the region of the cluster, which may not be the default one:
+		regionalClient, err := aws.NewClient().
+			Logger(logger).
+			Region(cluster.Region().ID()).
+			Build()
+		if err != nil {
+			reporter.Errorf("Failed to create AWS client: %v", err)
+			exit.Exit(rerrors.ExitCodeAWSAuth)
+		}
+		err = c.ValidateCustomerManagedKey(regionalClient, enableCustomerManagedKey, kmsKeyARN)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+	}
+	if !enableCustomerManagedKey {
+		kmsKeyARN = ""
+	}
 
-	machinePool, err := cmv1.NewMachinePool().
-		ID(name).
-		Replicas(replicas).
-		InstanceType(instanceType).
-		Labels(labelMap).
-		Taints(taintBuilders...).
-		Build()
+	// Spot instances:
+	useSpotInstances := args.useSpotInstances
+	spotMaxPrice := args.spotMaxPrice
+	if interactive.Enabled() {
+		useSpotInstances, err = interactive.GetBool(interactive.Input{
+			Question: "Use spot instances",
+			Help:     cmd.Flags().Lookup("use-spot-instances").Usage,
+			Default:  useSpotInstances,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid value for use-spot-instances: %s", err)
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+	}
+	if interactive.Enabled() && useSpotInstances {
+		spotMaxPrice, err = interactive.GetString(interactive.Input{
+			Question: "Spot instance max price",
+			Help:     cmd.Flags().Lookup("spot-max-price").Usage,
+			Default:  spotMaxPrice,
+			Required: true,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid spot max price: %s", err)
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+	}
+	spot, err := c.ValidateSpot(name, useSpotInstances, spotMaxPrice)
 	if err != nil {
-		reporter.Errorf("Failed to create machine pool for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		reporter.Errorf("%s", err)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
-	_, err = ocmClient.Clusters().
-		Cluster(cluster.ID()).
-		MachinePools().
-		Add().
-		Body(machinePool).
-		Send()
+	client, err := moactl.NewClient().
+		Logger(logger).
+		Connection(ocmConnection).
+		CreatorARN(awsCreator.ARN).
+		Build()
+	if err != nil {
+		reporter.Errorf("%v", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	_, err = client.CreateMachinePool(context.Background(), moactl.MachinePoolInput{
+		ClusterKey:   clusterKey,
+		ID:           name,
+		InstanceType: instanceType,
+		Replicas:     replicas,
+		Labels:       labelMap,
+		Taints:       taintBuilders,
+		KMSKeyARN:    kmsKeyARN,
+		Spot:         spot,
+	})
 	if err != nil {
-		reporter.Errorf("Failed to add machine pool to cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		reporter.Errorf("%v", err)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	reporter.Infof("Machine pool '%s' created successfully on cluster '%s'", name, clusterKey)
-}
-
-func Split(r rune) bool {
-	return r == '=' || r == ':'
+	operations.Record(reporter, &operations.Operation{
+		Type:        operations.CreateMachinePool,
+		ClusterID:   cluster.ID(),
+		ClusterName: cluster.Name(),
+		Resource:    name,
+	})
+	if spot.Enabled {
+		reporter.Infof("The nodes of machine pool '%s' use spot instances with a maximum price of '%s'",
+			name, spot.FormatMaxPrice())
+	}
 }
@@ -0,0 +1,133 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notificationcontact
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/contacts"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	clusterKey string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "notification-contact USERNAME",
+	Aliases: []string{"notification-contacts", "notificationcontact", "notificationcontacts"},
+	Short:   "Add notification contact to cluster",
+	Long: "Add a Red Hat user to the notification contacts of a cluster. Notification contacts " +
+		"receive the emails about scheduled maintenance and support cases of the cluster, in " +
+		"addition to its owner.",
+	Example: `  # Add the user "alice" to the notification contacts of the cluster named "mycluster"
+  rosa create notification-contact --cluster=mycluster alice`,
+	Run: runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to add the notification contact to (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+}
+
+func validate(r *runner.Runtime) error {
+	if len(r.Args) > 1 || len(r.Args) == 0 && !interactive.Enabled() {
+		return rerrors.ValidationErrorf(
+			"Expected exactly one command line parameter containing the username of the contact")
+	}
+	return validateClusterKey()
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	clusterKey := args.clusterKey
+
+	username := ""
+	if len(r.Args) == 1 {
+		username = r.Args[0]
+	}
+	if username == "" {
+		var err error
+		username, err = interactive.GetString(interactive.Input{
+			Question: "Username",
+			Help:     "Red Hat username of the user that will receive the notifications.",
+			Required: true,
+		})
+		if err != nil {
+			return rerrors.ValidationErrorf("Expected a valid username: %v", err)
+		}
+	}
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(r.OCMConnection.ClustersMgmt().V1().Clusters(), clusterKey,
+		r.Creator.ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+	subscriptionID := cluster.Subscription().ID()
+	if subscriptionID == "" {
+		return fmt.Errorf("Cluster '%s' doesn't have a subscription", clusterKey)
+	}
+
+	reporter.Debugf("Loading notification contacts of cluster '%s'", clusterKey)
+	current, err := contacts.GetContacts(r.OCMConnection, subscriptionID)
+	if err != nil {
+		return fmt.Errorf("Failed to get notification contacts of cluster '%s': %w", clusterKey, err)
+	}
+	if contacts.FindContact(current, username) != nil {
+		return rerrors.ConflictErrorf("User '%s' is already a notification contact of cluster '%s'",
+			username, clusterKey)
+	}
+
+	reporter.Debugf("Adding notification contact '%s' to cluster '%s'", username, clusterKey)
+	_, err = contacts.AddContact(r.OCMConnection, subscriptionID, username)
+	if err != nil {
+		return fmt.Errorf("Failed to add notification contact '%s' to cluster '%s': %w",
+			username, clusterKey, err)
+	}
+	reporter.Infof("Added notification contact '%s' to cluster '%s'", username, clusterKey)
+	return nil
+}
+
+// validate checks that the cluster key given by the user is reasonably safe so that there is no
+// risk of SQL injection.
+func validateClusterKey() error {
+	if !c.IsValidClusterKey(args.clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			args.clusterKey,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oidcprovider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mode"
+	c "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	clusterKey string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "oidc-provider",
+	Aliases: []string{"oidcprovider"},
+	Short:   "Create OIDC provider for an STS cluster",
+	Long: "Create the OpenID Connect provider of a cluster that uses the AWS Security Token " +
+		"Service (STS), which allows the operators of the cluster to assume their IAM roles.",
+	Example: `  # Create the OIDC provider of the cluster named "mycluster"
+  rosa create oidc-provider --cluster=mycluster
+
+  # Print the AWS CLI command that creates the OIDC provider instead
+  rosa create oidc-provider --cluster=mycluster --mode=manual`,
+	Run: runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to create the OIDC provider for (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	mode.AddFlag(flags)
+}
+
+func validate(r *runner.Runtime) error {
+	if !c.IsValidClusterKey(args.clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			args.clusterKey,
+		)
+	}
+	err := mode.Validate()
+	if err != nil {
+		return rerrors.ValidationErrorf("%v", err)
+	}
+	return nil
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	clusterKey := args.clusterKey
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(r.OCMConnection.ClustersMgmt().V1().Clusters(), clusterKey,
+		r.Creator.ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	sts, err := c.GetSTS(r.OCMConnection, cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get STS settings of cluster '%s': %w", clusterKey, err)
+	}
+	if sts == nil || sts.OIDCEndpointURL == "" {
+		return rerrors.ValidationErrorf("Cluster '%s' doesn't use STS", clusterKey)
+	}
+
+	reporter.Debugf("Getting thumbprint of '%s'", sts.OIDCEndpointURL)
+	thumbprint, err := aws.GetThumbprint(sts.OIDCEndpointURL)
+	if err != nil {
+		return err
+	}
+
+	if mode.IsManual() {
+		reporter.Infof("Run the following command to create the OIDC provider:")
+		fmt.Println(aws.CreateOIDCProviderCommand(sts.OIDCEndpointURL, thumbprint))
+		return nil
+	}
+
+	confirmed, err := confirm.Confirm("create the OIDC provider for cluster '%s'", clusterKey)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	providerARN, err := r.AWSClient.EnsureOIDCProvider(sts.OIDCEndpointURL, thumbprint)
+	if err != nil {
+		return err
+	}
+	reporter.Infof("Created OIDC provider with ARN '%s'", providerARN)
+	return nil
+}
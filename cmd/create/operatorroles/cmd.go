@@ -0,0 +1,141 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operatorroles
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mode"
+	c "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	clusterKey string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "operator-roles",
+	Aliases: []string{"operator-role", "operatorroles", "operatorrole"},
+	Short:   "Create operator IAM roles for an STS cluster",
+	Long: "Create the IAM roles assumed by the operators of a cluster that uses the AWS Security " +
+		"Token Service (STS). The roles trust the OpenID Connect provider of the cluster, which " +
+		"can be created with 'rosa create oidc-provider'.",
+	Example: `  # Create the operator roles of the cluster named "mycluster"
+  rosa create operator-roles --cluster=mycluster
+
+  # Print the AWS CLI commands that create the operator roles instead
+  rosa create operator-roles --cluster=mycluster --mode=manual`,
+	Run: runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to create the operator roles for (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	mode.AddFlag(flags)
+}
+
+func validate(r *runner.Runtime) error {
+	if !c.IsValidClusterKey(args.clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			args.clusterKey,
+		)
+	}
+	err := mode.Validate()
+	if err != nil {
+		return rerrors.ValidationErrorf("%v", err)
+	}
+	return nil
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	clusterKey := args.clusterKey
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(r.OCMConnection.ClustersMgmt().V1().Clusters(), clusterKey,
+		r.Creator.ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	sts, err := c.GetSTS(r.OCMConnection, cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get STS settings of cluster '%s': %w", clusterKey, err)
+	}
+	if sts == nil || sts.OIDCEndpointURL == "" {
+		return rerrors.ValidationErrorf("Cluster '%s' doesn't use STS", clusterKey)
+	}
+
+	specs, err := aws.OperatorRoleSpecs(cluster.Name(), cluster.ID(), r.Creator.Partition,
+		r.Creator.AccountID, sts.OIDCEndpointURL)
+	if err != nil {
+		return err
+	}
+
+	if mode.IsManual() {
+		reporter.Infof("All policy files saved to the current directory")
+		reporter.Infof("Run the following commands to create the operator roles:")
+		for _, spec := range specs {
+			trustFile, policyFile, err := aws.WriteRoleFiles(".", spec)
+			if err != nil {
+				return err
+			}
+			for _, command := range aws.CreateRoleCommands(spec, trustFile, policyFile) {
+				fmt.Println(command)
+			}
+		}
+		return nil
+	}
+
+	confirmed, err := confirm.Confirm("create the operator roles for cluster '%s'", clusterKey)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	for _, spec := range specs {
+		reporter.Debugf("Creating role '%s'", spec.Name)
+		roleARN, err := r.AWSClient.EnsureRole(spec)
+		if err != nil {
+			return err
+		}
+		reporter.Infof("Created role '%s' with ARN '%s'", spec.Name, roleARN)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/runner"
+	"github.com/openshift/moactl/pkg/schedules"
+)
+
+var args struct {
+	clusterKey  string
+	machinePool string
+	replicas    int
+	cron        string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "schedule",
+	Aliases: []string{"schedules"},
+	Short:   "Add a scaling schedule to a machine pool",
+	Long: "Add a schedule that scales a machine pool of a cluster to a number of replicas at the " +
+		"times given by a cron expression, for example to scale down development clusters outside " +
+		"business hours.\n\n" +
+		"Schedules are stored in the configuration of the current profile and are applied by the " +
+		"'rosa run schedules' command, which should be run periodically, for example every five " +
+		"minutes from the crontab of the machine. Cron times are in the local time zone of that " +
+		"machine.",
+	Example: `  # Scale the "workers" machine pool of the cluster named "mycluster" to 10 replicas at 8:00
+  # and back to 2 replicas at 18:00, from Monday to Friday
+  rosa create schedule --cluster=mycluster --machinepool=workers --replicas=10 --cron="0 8 * * 1-5"
+  rosa create schedule --cluster=mycluster --machinepool=workers --replicas=2 --cron="0 18 * * 1-5"`,
+	Run: runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to add the schedule to (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	flags.StringVar(
+		&args.machinePool,
+		"machinepool",
+		"",
+		"Name of the machine pool to scale, 'default' for the default machine pool (required).",
+	)
+	Cmd.MarkFlagRequired("machinepool")
+
+	flags.IntVar(
+		&args.replicas,
+		"replicas",
+		0,
+		"Number of replicas of the machine pool at the scheduled times (required).",
+	)
+	Cmd.MarkFlagRequired("replicas")
+
+	flags.StringVar(
+		&args.cron,
+		"cron",
+		"",
+		"Cron expression with the times to scale the machine pool, with the fields minute, hour, "+
+			"day of month, month and day of week, for example '0 8 * * 1-5' (required).",
+	)
+	Cmd.MarkFlagRequired("cron")
+}
+
+func validate(r *runner.Runtime) error {
+	if !c.IsValidClusterKey(args.clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			args.clusterKey,
+		)
+	}
+	if args.machinePool == "" {
+		return rerrors.ValidationErrorf("Machine pool name is required")
+	}
+	if args.replicas < 0 {
+		return rerrors.ValidationErrorf("Number of replicas must be a non-negative number")
+	}
+	_, err := schedules.ParseCron(args.cron)
+	return err
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	clusterKey := args.clusterKey
+	clustersCollection := r.OCMConnection.ClustersMgmt().V1().Clusters()
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, r.Creator.ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	// Check that the machine pool exists and can be scaled to the requested replicas:
+	if args.machinePool == "default" {
+		err = c.ValidateComputeNodeCount(cluster, args.replicas)
+		if err != nil {
+			return err
+		}
+	} else {
+		reporter.Debugf("Loading machine pools for cluster '%s'", clusterKey)
+		machinePools, err := ocm.GetMachinePools(clustersCollection, cluster.ID())
+		if err != nil {
+			return fmt.Errorf("Failed to get machine pools for cluster '%s': %w", clusterKey, err)
+		}
+		found := false
+		for _, machinePool := range machinePools {
+			if machinePool.ID() == args.machinePool {
+				found = true
+			}
+		}
+		if !found {
+			return rerrors.NotFoundErrorf("Machine pool '%s' doesn't exist in cluster '%s'",
+				args.machinePool, clusterKey)
+		}
+	}
+
+	schedule := &schedules.Schedule{
+		ClusterID:   cluster.ID(),
+		ClusterName: cluster.Name(),
+		MachinePool: args.machinePool,
+		Replicas:    args.replicas,
+		Cron:        args.cron,
+	}
+	err = schedules.Add(schedule)
+	if err != nil {
+		return fmt.Errorf("Failed to add schedule to cluster '%s': %w", clusterKey, err)
+	}
+	reporter.Infof("Added schedule '%s' to scale machine pool '%s' of cluster '%s' to %d replicas "+
+		"at '%s'", schedule.ID, args.machinePool, clusterKey, args.replicas, schedule.Cron)
+	reporter.Infof("Schedules are applied by 'rosa run schedules', make sure that it runs " +
+		"periodically, for example every five minutes from cron")
+	return nil
+}
@@ -21,25 +21,50 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"text/tabwriter"
 
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/moactl/pkg/aws"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/addons"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
 )
 
+var args struct {
+	clusterKey string
+}
+
 var Cmd = &cobra.Command{
 	Use:     "addon [ID|NAME]",
 	Aliases: []string{"add-on"},
-	Hidden:  true,
 	Short:   "Show details of an add-on",
-	Long:    "Show details of an add-on",
+	Long: "Show details of an add-on and its parameters. If a cluster is given, also show the " +
+		"state of the installation of the add-on on the cluster and the values of its parameters.",
 	Example: `  # Describe an add-on named "codeready-workspaces"
-  rosa describe addon codeready-workspaces`,
+  rosa describe addon codeready-workspaces
+
+  # Describe the installation of the add-on on the cluster named "mycluster"
+  rosa describe addon codeready-workspaces --cluster=mycluster`,
 	Run: run,
 }
 
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to show the installation of the add-on for.",
+	)
+}
+
 func run(_ *cobra.Command, argv []string) {
 	reporter := rprtr.CreateReporterOrExit()
 	logger := logging.CreateLoggerOrExit(reporter)
@@ -49,17 +74,29 @@ func run(_ *cobra.Command, argv []string) {
 		reporter.Errorf(
 			"Expected exactly one command line argument or flag containing the identifier of the add-on",
 		)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 	addOnID := argv[0]
 
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := args.clusterKey
+	if clusterKey != "" && !ocm.IsValidClusterKey(clusterKey) {
+		reporter.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+		exit.Exit(rerrors.ExitCodeValidation)
+	}
+
 	// Create the client for the OCM API:
 	ocmConnection, err := ocm.NewConnection().
 		Logger(logger).
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
 	}
 	defer func() {
 		err = ocmConnection.Close()
@@ -73,12 +110,45 @@ func run(_ *cobra.Command, argv []string) {
 
 	// Try to find the add-on:
 	reporter.Debugf("Loading add-on '%s'", addOnID)
-	addOn, err := ocm.GetAddOn(addOnsCollection, addOnID)
+	addOn, err := addons.GetAddOn(addOnsCollection, addOnID)
 	if err != nil {
 		reporter.Errorf("Failed to get add-on '%s': %s\n"+
 			"Try running 'rosa list addons' to see all available add-ons.",
 			addOnID, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	// Load the installation of the add-on on the cluster, if requested:
+	var cluster *cmv1.Cluster
+	var addOnInstallation *cmv1.AddOnInstallation
+	if clusterKey != "" {
+		awsClient, err := aws.NewClient().
+			Logger(logger).
+			Build()
+		if err != nil {
+			reporter.Errorf("Failed to create AWS client: %v", err)
+			exit.Exit(rerrors.ExitCodeAWSAuth)
+		}
+
+		awsCreator, err := awsClient.GetCreator()
+		if err != nil {
+			reporter.Errorf("Failed to get AWS creator: %v", err)
+			exit.Exit(rerrors.ExitCodeAWSAuth)
+		}
+
+		clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
+		reporter.Debugf("Loading cluster '%s'", clusterKey)
+		cluster, err = ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
+		if err != nil {
+			reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+
+		addOnInstallation, err = addons.GetAddOnInstallation(clustersCollection, cluster.ID(), addOn.ID())
+		if err != nil {
+			reporter.Errorf("Failed to get add-on '%s' of cluster '%s': %v", addOnID, clusterKey, err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
 	}
 
 	// Print add-on description:
@@ -98,7 +168,49 @@ func run(_ *cobra.Command, argv []string) {
 		addOn.TargetNamespace(),
 		addOn.InstallMode(),
 	)
+	if cluster != nil {
+		fmt.Printf(""+
+			"Cluster:          %s\n"+
+			"State:            %s\n",
+			cluster.Name(),
+			addons.InstallationState(addOnInstallation),
+		)
+	}
 	fmt.Println()
+
+	params := addons.Parameters(addOn)
+	if len(params) == 0 {
+		return
+	}
+
+	// Print the parameters, with their values if the add-on is installed:
+	values := map[string]string{}
+	if addOnInstallation != nil {
+		addOnInstallation.Parameters().Each(func(param *cmv1.AddOnInstallationParameter) bool {
+			values[param.ID()] = param.Value()
+			return true
+		})
+	}
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if addOnInstallation != nil {
+		fmt.Fprintf(writer, "PARAMETER\tTYPE\tREQUIRED\tVALUE\tDESCRIPTION\n")
+	} else {
+		fmt.Fprintf(writer, "PARAMETER\tTYPE\tREQUIRED\tDESCRIPTION\n")
+	}
+	for _, param := range params {
+		required := "no"
+		if param.Required() {
+			required = "yes"
+		}
+		if addOnInstallation != nil {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", param.ID(), param.ValueType(), required,
+				values[param.ID()], param.Description())
+		} else {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", param.ID(), param.ValueType(), required,
+				param.Description())
+		}
+	}
+	writer.Flush()
 }
 
 func wrapText(text string) string {
@@ -17,22 +17,23 @@ limitations under the License.
 package admin
 
 import (
+	"fmt"
 	"os"
+	"text/tabwriter"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/pkg/aws"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/admin"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
 )
 
-const (
-	idpName = "Cluster-Admin"
-	// username = "cluster-admin"
-)
-
 var args struct {
 	clusterKey string
 }
@@ -72,7 +73,7 @@ func run(cmd *cobra.Command, _ []string) {
 				"must contain only letters, digits, dashes and underscores",
 			clusterKey,
 		)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	// Create the AWS client:
@@ -81,13 +82,13 @@ func run(cmd *cobra.Command, _ []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	awsCreator, err := awsClient.GetCreator()
 	if err != nil {
 		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	// Create the client for the OCM API:
@@ -96,7 +97,7 @@ func run(cmd *cobra.Command, _ []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
 	}
 	defer func() {
 		err = ocmConnection.Close()
@@ -113,34 +114,39 @@ func run(cmd *cobra.Command, _ []string) {
 	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
 	if err != nil {
 		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	if cluster.State() != cmv1.ClusterStateReady {
 		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeConflict)
 	}
 
 	// Try to find the htpasswd identity provider:
-	reporter.Debugf("Loading '%s' identity provider", idpName)
-	idps, err := ocm.GetIdentityProviders(clustersCollection, cluster.ID())
+	reporter.Debugf("Loading '%s' identity provider", admin.IdpName)
+	idp, err := admin.GetIdentityProvider(clustersCollection, cluster.ID())
 	if err != nil {
-		reporter.Errorf("Failed to get '%s' identity provider for cluster '%s': %v", idpName, clusterKey, err)
-		os.Exit(1)
-	}
-
-	var idp *cmv1.IdentityProvider
-	for _, item := range idps {
-		if ocm.IdentityProviderType(item) == "htpasswd" {
-			idp = item
-		}
+		reporter.Errorf("Failed to get '%s' identity provider for cluster '%s': %v",
+			admin.IdpName, clusterKey, err)
+		exit.Exit(rerrors.ExitCode(err))
 	}
-	if idp == nil || idp.Htpasswd() == nil {
+	if idp == nil {
 		reporter.Warnf("There is no admin on cluster '%s'. To create it run the following command:\n"+
 			"   rosa create admin -c %s", clusterKey, clusterKey)
-		os.Exit(0)
+		exit.Exit(0)
 	}
 
-	reporter.Infof("There is an admin on cluster '%s'. To login, run the following command:\n"+
-		"   oc login %s --username %s", clusterKey, cluster.API().URL(), idp.Htpasswd().Username())
+	// Print the credentials, except the password which can't be retrieved:
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "Username:\t%s\n", idp.Htpasswd().Username())
+	fmt.Fprintf(writer, "Group:\t%s\n", admin.Group)
+	fmt.Fprintf(writer, "Identity provider:\t%s\n", idp.Name())
+	fmt.Fprintf(writer, "API URL:\t%s\n", cluster.API().URL())
+	fmt.Fprintf(writer, "Console URL:\t%s\n", cluster.Console().URL())
+	writer.Flush()
+
+	reporter.Infof("To login, run the following command:\n"+
+		"   %s", clusterprovider.LoginCommand(cluster, idp.Htpasswd().Username()))
+	reporter.Infof("If you lost the password, you can change it with the following command:\n"+
+		"   rosa regenerate admin-password -c %s", clusterKey)
 }
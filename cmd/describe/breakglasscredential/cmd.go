@@ -0,0 +1,147 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package breakglasscredential
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/breakglass"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+// Regular expression used to make sure that the identifier of the credential given by the user is
+// safe to use in the path of requests:
+var credentialIDRE = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+
+var args struct {
+	clusterKey string
+	kubeconfig bool
+}
+
+var Cmd = &cobra.Command{
+	Use:     "break-glass-credential ID",
+	Aliases: []string{"break-glass-credentials", "breakglasscredential", "breakglasscredentials"},
+	Short:   "Show details of a break glass credential of a cluster",
+	Long: "Show details of a break glass credential of a cluster, or the kubeconfig that uses it " +
+		"once it has been issued.",
+	Example: `  # Describe the break glass credential with ID "1a2b" of the cluster named "mycluster"
+  rosa describe break-glass-credential 1a2b --cluster=mycluster
+
+  # Save the kubeconfig of the break glass credential to a file
+  rosa describe break-glass-credential 1a2b --cluster=mycluster --kubeconfig > kubeconfig`,
+	Args: cobra.ExactArgs(1),
+	Run:  runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster of the break glass credential (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	flags.BoolVar(
+		&args.kubeconfig,
+		"kubeconfig",
+		false,
+		"Print only the kubeconfig that uses the credential.",
+	)
+}
+
+func validate(r *runner.Runtime) error {
+	if !credentialIDRE.MatchString(r.Args[0]) {
+		return rerrors.ValidationErrorf("Break glass credential identifier '%s' isn't valid",
+			r.Args[0])
+	}
+	return validateClusterKey()
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	clusterKey := args.clusterKey
+	credentialID := r.Args[0]
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(r.OCMConnection.ClustersMgmt().V1().Clusters(), clusterKey,
+		r.Creator.ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	reporter.Debugf("Loading break glass credential '%s' of cluster '%s'", credentialID, clusterKey)
+	credential, err := breakglass.GetCredential(ctx, r.OCMConnection, cluster.ID(), credentialID)
+	if err != nil {
+		return fmt.Errorf("Failed to get break glass credential '%s' of cluster '%s': %w",
+			credentialID, clusterKey, err)
+	}
+
+	if args.kubeconfig {
+		if credential.Status != breakglass.StatusIssued || credential.Kubeconfig == "" {
+			return rerrors.ConflictErrorf("Break glass credential '%s' is %s, the kubeconfig is "+
+				"only available while it is issued", credentialID, credential.Status)
+		}
+		fmt.Print(credential.Kubeconfig)
+		return nil
+	}
+
+	revoked := "-"
+	if !credential.RevocationTimestamp.IsZero() {
+		revoked = credential.RevocationTimestamp.Format(time.RFC3339)
+	}
+	fmt.Printf(""+
+		"ID:                         %s\n"+
+		"Cluster ID:                 %s\n"+
+		"Username:                   %s\n"+
+		"Status:                     %s\n"+
+		"Expires:                    %s\n"+
+		"Revoked:                    %s\n",
+		credential.ID,
+		cluster.ID(),
+		credential.Username,
+		credential.Status,
+		credential.ExpirationTimestamp.Format(time.RFC3339),
+		revoked,
+	)
+	return nil
+}
+
+// validateClusterKey checks that the cluster key given by the user is reasonably safe so that there
+// is no risk of SQL injection.
+func validateClusterKey() error {
+	if !c.IsValidClusterKey(args.clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			args.clusterKey,
+		)
+	}
+	return nil
+}
@@ -17,18 +17,30 @@ limitations under the License.
 package cluster
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws/arn"
+	sdk "github.com/openshift-online/ocm-sdk-go"
 	"github.com/spf13/cobra"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/moactl/pkg/aws"
 	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/config"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/admin"
+	"github.com/openshift/moactl/pkg/ocm/machinepools"
 	"github.com/openshift/moactl/pkg/ocm/properties"
+	"github.com/openshift/moactl/pkg/ocm/upgrades"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
 )
 
@@ -39,19 +51,39 @@ const (
 	ProductionEnv = "https://api.openshift.com"
 )
 
+// Output formats supported by the '--output' flag:
+const (
+	specOutput = "spec"
+	jsonOutput = "json"
+)
+
 var args struct {
-	clusterKey string
+	clusterKey  string
+	output      string
+	credentials bool
 }
 
 var Cmd = &cobra.Command{
 	Use:   "cluster [ID|NAME]",
 	Short: "Show details of a cluster",
-	Long:  "Show details of a cluster",
+	Long: "Show details of a cluster, including its machine pools, networking, identity " +
+		"providers, ingresses, scheduled upgrade and the reasons why it is in limited support, " +
+		"if any.",
 	Example: `  # Describe a cluster named "mycluster"
   rosa describe cluster mycluster
 
   # Describe a cluster using the --cluster flag
-  rosa describe cluster --cluster=mycluster`,
+  rosa describe cluster --cluster=mycluster
+
+  # Describe a cluster and show how to login to it
+  rosa describe cluster mycluster --credentials
+
+  # Describe a cluster and its machine pools, identity providers, ingresses and upgrades in JSON
+  rosa describe cluster mycluster --output=json
+
+  # Export the spec of a cluster to create a similar one
+  rosa describe cluster mycluster --output=spec > cluster.yaml
+  rosa create cluster --file=cluster.yaml --cluster-name=othercluster`,
 	Run: run,
 }
 
@@ -65,6 +97,23 @@ func init() {
 		"",
 		"Name or ID of the cluster to describe.",
 	)
+	// The cluster can also be given as the first argument:
+	config.MarkClusterArg(Cmd)
+	flags.StringVarP(
+		&args.output,
+		"output",
+		"o",
+		"",
+		"Output format. Use 'spec' to print the cluster as a YAML spec file that can be used "+
+			"with 'rosa create cluster --file', or 'json' to print the cluster and its resources "+
+			"in JSON format.",
+	)
+	flags.BoolVar(
+		&args.credentials,
+		"credentials",
+		false,
+		"Show the credentials of the cluster admin and the command used to login to the cluster.",
+	)
 }
 
 func run(_ *cobra.Command, argv []string) {
@@ -79,11 +128,17 @@ func run(_ *cobra.Command, argv []string) {
 				"Expected exactly one command line argument or flag containing the name " +
 					"or identifier of the cluster",
 			)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 		clusterKey = argv[0]
 	}
 
+	if args.output != "" && args.output != specOutput && args.output != jsonOutput {
+		reporter.Errorf("Invalid output format '%s', expected '%s' or '%s'", args.output, specOutput,
+			jsonOutput)
+		exit.Exit(rerrors.ExitCodeValidation)
+	}
+
 	// Check that the cluster key (name, identifier or external identifier) given by the user
 	// is reasonably safe so that there is no risk of SQL injection:
 	if !clusterprovider.IsValidClusterKey(clusterKey) {
@@ -92,7 +147,7 @@ func run(_ *cobra.Command, argv []string) {
 				"must contain only letters, digits, dashes and underscores",
 			clusterKey,
 		)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	// Create the AWS client:
@@ -101,13 +156,13 @@ func run(_ *cobra.Command, argv []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	awsCreator, err := awsClient.GetCreator()
 	if err != nil {
 		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	// Create the client for the OCM API:
@@ -117,7 +172,7 @@ func run(_ *cobra.Command, argv []string) {
 
 	if err != nil {
 		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
 	}
 	defer func() {
 		err = ocmConnection.Close()
@@ -134,13 +189,44 @@ func run(_ *cobra.Command, argv []string) {
 	cluster, err := clusterprovider.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
 	if err != nil {
 		reporter.Errorf(fmt.Sprintf("Failed to get cluster '%s': %v", clusterKey, err))
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	if args.output == specOutput {
+		spec, err := clusterprovider.GetSpecFile(ocmConnection, cluster)
+		if err != nil {
+			reporter.Errorf("Failed to get spec of cluster '%s': %v", clusterKey, err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		data, err := spec.Marshal()
+		if err != nil {
+			reporter.Errorf("Failed to marshal spec of cluster '%s': %v", clusterKey, err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		fmt.Print(string(data))
+		return
+	}
+
+	resources, err := loadResources(reporter, ocmConnection, cluster)
+	if err != nil {
+		reporter.Errorf("Failed to get resources of cluster '%s': %v", clusterKey, err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	if args.output == jsonOutput {
+		data, err := marshalReport(cluster, resources)
+		if err != nil {
+			reporter.Errorf("Failed to marshal cluster '%s': %v", clusterKey, err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		fmt.Println(string(data))
+		return
 	}
 
 	creatorARN, err := arn.Parse(cluster.Properties()[properties.CreatorARN])
 	if err != nil {
 		reporter.Errorf("Failed to parse creator ARN for cluster '%s'", clusterKey)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeGeneric)
 	}
 	phase := ""
 
@@ -194,6 +280,58 @@ func run(_ *cobra.Command, argv []string) {
 		cluster.CreationTimestamp().Format("Jan _2 2006 15:04:05 MST"),
 	)
 
+	tags, err := clusterprovider.GetTags(ocmConnection, cluster.ID())
+	if err != nil {
+		reporter.Errorf("Failed to get tags of cluster '%s': %v", clusterKey, err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	if len(tags) > 0 {
+		str = fmt.Sprintf("%s"+
+			"Tags:                       %s\n", str,
+			clusterprovider.FormatTags(tags))
+	}
+	if props := clusterprovider.CustomProperties(cluster); len(props) > 0 {
+		str = fmt.Sprintf("%s"+
+			"Properties:                 %s\n", str,
+			clusterprovider.FormatProperties(props))
+	}
+	fips, err := clusterprovider.IsFIPS(ocmConnection, cluster.ID())
+	if err != nil {
+		reporter.Errorf("Failed to get FIPS mode of cluster '%s': %v", clusterKey, err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	str = fmt.Sprintf("%s"+
+		"FIPS Mode:                  %s\n"+
+		"Etcd Encryption:            %s\n", str,
+		enabledOrDisabled(fips),
+		enabledOrDisabled(cluster.EtcdEncryption()))
+	if cluster.API().Listening() == cmv1.ListeningMethodInternal {
+		privateLink, err := clusterprovider.GetPrivateLink(ocmConnection, cluster.ID())
+		if err != nil {
+			reporter.Errorf("Failed to get PrivateLink configuration of cluster '%s': %v",
+				clusterKey, err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		if privateLink.Enabled {
+			str = fmt.Sprintf("%s"+
+				"PrivateLink:                Yes\n", str)
+			endpoint := privateLink.Endpoint
+			if endpoint.ServiceName != "" {
+				str = fmt.Sprintf("%s"+
+					"PrivateLink Service:        %s\n", str,
+					endpoint.ServiceName)
+			}
+			if endpoint.EndpointID != "" {
+				str = fmt.Sprintf("%s"+
+					"PrivateLink Endpoint:       %s (%s)\n", str,
+					endpoint.EndpointID, endpoint.State)
+			}
+		}
+	}
+	if clusterprovider.IsDeleteProtected(cluster) {
+		str = fmt.Sprintf("%s"+
+			"Delete Protection:          Enabled\n", str)
+	}
 	if detailsPage != "" {
 		str = fmt.Sprintf("%s"+
 			"Details Page:               %s%s\n", str,
@@ -208,9 +346,317 @@ func run(_ *cobra.Command, argv []string) {
 			cluster.Status().ProvisionErrorMessage(),
 		)
 	}
+	// Make limited support hard to miss, before the rest of the description:
+	for _, reason := range resources.limitedSupportReasons {
+		reporter.Warnf("Cluster '%s' is in limited support: %s", clusterKey, reason.Summary)
+	}
+	if len(resources.limitedSupportReasons) > 0 {
+		reporter.Warnf("To see the service logs of the cluster run the following command:\n"+
+			"   rosa list service-logs -c %s", clusterKey)
+		fmt.Println()
+	}
+
 	// Print short cluster description:
 	fmt.Print(str)
 	fmt.Println()
+
+	// Print the resources of the cluster grouped in sections:
+	printResources(cluster, resources)
+
+	if args.credentials {
+		printCredentials(reporter, clustersCollection, cluster, clusterKey)
+	}
+}
+
+// printCredentials prints the credentials of the cluster admin, either the ones stored by OCM or
+// the user created with 'rosa create admin', whose password can't be retrieved.
+func printCredentials(reporter *rprtr.Object, client *cmv1.ClustersClient, cluster *cmv1.Cluster,
+	clusterKey string) {
+	if cluster.State() != cmv1.ClusterStateReady {
+		reporter.Warnf("Cluster '%s' is not yet ready, credentials aren't available", clusterKey)
+		return
+	}
+
+	reporter.Debugf("Loading credentials of cluster '%s'", clusterKey)
+	credentials, err := clusterprovider.GetCredentials(client, cluster.ID())
+	if err != nil {
+		reporter.Errorf("Failed to get credentials of cluster '%s': %v", clusterKey, err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	if credentials != nil && credentials.Admin().User() != "" {
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(writer, "Username:\t%s\n", credentials.Admin().User())
+		fmt.Fprintf(writer, "Password:\t%s\n", credentials.Admin().Password())
+		writer.Flush()
+		fmt.Println()
+		reporter.Infof("To login, run the following command:\n"+
+			"   %s", clusterprovider.LoginCommand(cluster, credentials.Admin().User()))
+		reporter.Infof("To create a kubeconfig file, run the following command:\n"+
+			"   rosa create kubeconfig -c %s", clusterKey)
+		return
+	}
+
+	reporter.Debugf("Loading '%s' identity provider", admin.IdpName)
+	idp, err := admin.GetIdentityProvider(client, cluster.ID())
+	if err != nil {
+		reporter.Errorf("Failed to get '%s' identity provider for cluster '%s': %v",
+			admin.IdpName, clusterKey, err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	if idp == nil {
+		reporter.Warnf("There is no admin on cluster '%s'. To create it run the following command:\n"+
+			"   rosa create admin -c %s", clusterKey, clusterKey)
+		return
+	}
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "Username:\t%s\n", idp.Htpasswd().Username())
+	fmt.Fprintf(writer, "Identity provider:\t%s\n", idp.Name())
+	writer.Flush()
+	fmt.Println()
+	reporter.Infof("To login, run the following command and enter the password of the admin:\n"+
+		"   %s", clusterprovider.LoginCommand(cluster, idp.Htpasswd().Username()))
+	reporter.Infof("If you lost the password, you can change it with the following command:\n"+
+		"   rosa regenerate admin-password -c %s", clusterKey)
+}
+
+// resources contains the resources of a cluster that are described together with the cluster.
+type resources struct {
+	machinePools          []*cmv1.MachinePool
+	identityProviders     []*cmv1.IdentityProvider
+	ingresses             []*cmv1.Ingress
+	scheduledUpgrade      *cmv1.UpgradePolicy
+	upgradeState          *cmv1.UpgradePolicyState
+	limitedSupportReasons []*clusterprovider.LimitedSupportReason
+}
+
+// loadResources loads the machine pools, identity providers, ingresses, scheduled upgrade and
+// limited support reasons of the cluster.
+func loadResources(reporter *rprtr.Object, connection *sdk.Connection,
+	cluster *cmv1.Cluster) (*resources, error) {
+	client := connection.ClustersMgmt().V1()
+	result := &resources{}
+	var err error
+
+	reporter.Debugf("Loading machine pools of cluster '%s'", cluster.ID())
+	result.machinePools, err = ocm.GetMachinePools(client.Clusters(), cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get machine pools: %w", err)
+	}
+
+	reporter.Debugf("Loading identity providers of cluster '%s'", cluster.ID())
+	result.identityProviders, err = ocm.GetIdentityProviders(client.Clusters(), cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get identity providers: %w", err)
+	}
+
+	reporter.Debugf("Loading ingresses of cluster '%s'", cluster.ID())
+	result.ingresses, err = ocm.GetIngresses(client.Clusters(), cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get ingresses: %w", err)
+	}
+
+	reporter.Debugf("Loading scheduled upgrade of cluster '%s'", cluster.ID())
+	result.scheduledUpgrade, err = upgrades.GetScheduledUpgrade(client, cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get scheduled upgrade: %w", err)
+	}
+	if result.scheduledUpgrade != nil {
+		result.upgradeState, err = upgrades.GetUpgradePolicyState(client, cluster.ID(),
+			result.scheduledUpgrade.ID())
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get state of scheduled upgrade: %w", err)
+		}
+	}
+
+	reporter.Debugf("Loading limited support reasons of cluster '%s'", cluster.ID())
+	result.limitedSupportReasons, err = clusterprovider.GetLimitedSupportReasons(connection,
+		cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get limited support reasons: %w", err)
+	}
+
+	return result, nil
+}
+
+// printResources prints the resources of the cluster grouped in sections.
+func printResources(cluster *cmv1.Cluster, resources *resources) {
+	fmt.Println("Machine Pools:")
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "  ID\tREPLICAS\tINSTANCE TYPE\tLABELS\tTAINTS\tAVAILABILITY ZONES\n")
+	fmt.Fprintf(writer, "  %s\t%d\t%s\t%s\t%s\t%s\n",
+		"default",
+		cluster.Nodes().Compute(),
+		cluster.Nodes().ComputeMachineType().ID(),
+		machinepools.FormatLabels(cluster.Nodes().ComputeLabels()),
+		"",
+		strings.Join(cluster.Nodes().AvailabilityZones(), ", "),
+	)
+	for _, machinePool := range resources.machinePools {
+		fmt.Fprintf(writer, "  %s\t%d\t%s\t%s\t%s\t%s\n",
+			machinePool.ID(),
+			machinePool.Replicas(),
+			machinePool.InstanceType(),
+			machinepools.FormatLabels(machinePool.Labels()),
+			machinepools.FormatTaints(machinePool.Taints()),
+			strings.Join(machinePool.AvailabilityZones(), ", "),
+		)
+	}
+	writer.Flush()
+	fmt.Println()
+
+	fmt.Println("Networking:")
+	writer = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "  Machine CIDR:\t%s\n", cluster.Network().MachineCIDR())
+	fmt.Fprintf(writer, "  Service CIDR:\t%s\n", cluster.Network().ServiceCIDR())
+	fmt.Fprintf(writer, "  Pod CIDR:\t%s\n", cluster.Network().PodCIDR())
+	fmt.Fprintf(writer, "  Host Prefix:\t/%d\n", cluster.Network().HostPrefix())
+	fmt.Fprintf(writer, "  Private API:\t%s\n",
+		yesOrNo(cluster.API().Listening() == cmv1.ListeningMethodInternal))
+	if len(cluster.AWS().SubnetIDs()) > 0 {
+		fmt.Fprintf(writer, "  Subnets:\t%s\n", strings.Join(cluster.AWS().SubnetIDs(), ", "))
+	}
+	writer.Flush()
+	fmt.Println()
+
+	fmt.Println("Identity Providers:")
+	if len(resources.identityProviders) == 0 {
+		fmt.Println("  None")
+	} else {
+		writer = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(writer, "  NAME\tTYPE\n")
+		for _, idp := range resources.identityProviders {
+			fmt.Fprintf(writer, "  %s\t%s\n", idp.Name(), ocm.IdentityProviderType(idp))
+		}
+		writer.Flush()
+	}
+	fmt.Println()
+
+	fmt.Println("Ingresses:")
+	if len(resources.ingresses) == 0 {
+		fmt.Println("  None")
+	} else {
+		writer = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(writer, "  ID\tAPPLICATION ROUTER\tPRIVATE\tDEFAULT\n")
+		for _, ingress := range resources.ingresses {
+			fmt.Fprintf(writer, "  %s\thttps://%s\t%s\t%s\n",
+				ingress.ID(),
+				ingress.DNSName(),
+				yesOrNo(ingress.Listening() == cmv1.ListeningMethodInternal),
+				yesOrNo(ingress.Default()),
+			)
+		}
+		writer.Flush()
+	}
+	fmt.Println()
+
+	fmt.Println("Upgrade:")
+	writer = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "  Current Version:\t%s\n", cluster.OpenshiftVersion())
+	if resources.scheduledUpgrade == nil {
+		fmt.Fprintf(writer, "  Scheduled Upgrade:\tNone\n")
+	} else {
+		fmt.Fprintf(writer, "  Scheduled Upgrade:\t%s on %s\n",
+			resources.scheduledUpgrade.Version(),
+			resources.scheduledUpgrade.NextRun().Format("2006-01-02 15:04 MST"))
+		if resources.upgradeState != nil {
+			fmt.Fprintf(writer, "  Upgrade State:\t%s\n", resources.upgradeState.Value())
+		}
+	}
+	writer.Flush()
+	fmt.Println()
+
+	fmt.Println("Limited Support:")
+	if len(resources.limitedSupportReasons) == 0 {
+		fmt.Println("  No, the cluster is fully supported")
+	} else {
+		for _, reason := range resources.limitedSupportReasons {
+			fmt.Printf("  - %s (since %s)\n", reason.Summary,
+				reason.CreationTimestamp.Format(time.RFC3339))
+			if reason.Details != "" {
+				fmt.Printf("    %s\n", reason.Details)
+			}
+		}
+	}
+	fmt.Println()
+}
+
+// report is the JSON document printed by the '--output=json' flag. The OCM objects are marshalled
+// with the SDK, so they have the same format as in the OCM API.
+type report struct {
+	Cluster               json.RawMessage                         `json:"cluster"`
+	MachinePools          json.RawMessage                         `json:"machine_pools"`
+	IdentityProviders     json.RawMessage                         `json:"identity_providers"`
+	Ingresses             json.RawMessage                         `json:"ingresses"`
+	ScheduledUpgrade      json.RawMessage                         `json:"scheduled_upgrade,omitempty"`
+	UpgradeState          string                                  `json:"upgrade_state,omitempty"`
+	LimitedSupportReasons []*clusterprovider.LimitedSupportReason `json:"limited_support_reasons"`
+}
+
+// marshalReport returns the JSON document that describes the cluster and its resources.
+func marshalReport(cluster *cmv1.Cluster, resources *resources) ([]byte, error) {
+	result := &report{
+		LimitedSupportReasons: resources.limitedSupportReasons,
+	}
+	var buffer bytes.Buffer
+	marshal := func(fn func(*bytes.Buffer) error) (json.RawMessage, error) {
+		buffer.Reset()
+		err := fn(&buffer)
+		if err != nil {
+			return nil, err
+		}
+		return append(json.RawMessage{}, buffer.Bytes()...), nil
+	}
+	var err error
+	result.Cluster, err = marshal(func(b *bytes.Buffer) error {
+		return cmv1.MarshalCluster(cluster, b)
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.MachinePools, err = marshal(func(b *bytes.Buffer) error {
+		return cmv1.MarshalMachinePoolList(resources.machinePools, b)
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.IdentityProviders, err = marshal(func(b *bytes.Buffer) error {
+		return cmv1.MarshalIdentityProviderList(resources.identityProviders, b)
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Ingresses, err = marshal(func(b *bytes.Buffer) error {
+		return cmv1.MarshalIngressList(resources.ingresses, b)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resources.scheduledUpgrade != nil {
+		result.ScheduledUpgrade, err = marshal(func(b *bytes.Buffer) error {
+			return cmv1.MarshalUpgradePolicy(resources.scheduledUpgrade, b)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if resources.upgradeState != nil {
+		result.UpgradeState = string(resources.upgradeState.Value())
+	}
+	return json.MarshalIndent(result, "", "  ")
+}
+
+func yesOrNo(value bool) string {
+	if value {
+		return "Yes"
+	}
+	return "No"
+}
+
+func enabledOrDisabled(value bool) string {
+	if value {
+		return "Enabled"
+	}
+	return "Disabled"
 }
 
 func getDetailsLink(environment string) string {
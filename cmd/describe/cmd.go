@@ -21,7 +21,10 @@ import (
 
 	"github.com/openshift/moactl/cmd/describe/addon"
 	"github.com/openshift/moactl/cmd/describe/admin"
+	"github.com/openshift/moactl/cmd/describe/breakglasscredential"
 	"github.com/openshift/moactl/cmd/describe/cluster"
+	"github.com/openshift/moactl/cmd/describe/operation"
+	"github.com/openshift/moactl/cmd/describe/upgrade"
 )
 
 var Cmd = &cobra.Command{
@@ -33,5 +36,8 @@ var Cmd = &cobra.Command{
 func init() {
 	Cmd.AddCommand(addon.Cmd)
 	Cmd.AddCommand(admin.Cmd)
+	Cmd.AddCommand(breakglasscredential.Cmd)
 	Cmd.AddCommand(cluster.Cmd)
+	Cmd.AddCommand(operation.Cmd)
+	Cmd.AddCommand(upgrade.Cmd)
 }
@@ -0,0 +1,201 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/operations"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+// Regular expression used to check the identifier of the operation given by the user:
+var operationIDRE = regexp.MustCompile(`^[0-9]+$`)
+
+var args struct {
+	watch    bool
+	interval time.Duration
+	timeout  time.Duration
+}
+
+var Cmd = &cobra.Command{
+	Use:     "operation ID",
+	Aliases: []string{"operations"},
+	Short:   "Show details of a long running operation",
+	Long: "Show details of a long running operation started by this tool, or wait until it " +
+		"finishes. Operations can be watched again after the command that started them has " +
+		"been closed.",
+	Example: `  # Describe the operation with ID "3"
+  rosa describe operation 3
+
+  # Wait until the operation with ID "3" finishes
+  rosa describe operation 3 --watch`,
+	Args: cobra.ExactArgs(1),
+	Run:  runner.Command(run, validate, runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.BoolVar(
+		&args.watch,
+		"watch",
+		false,
+		"Wait until the operation finishes, printing the changes of its state.",
+	)
+
+	flags.DurationVar(
+		&args.interval,
+		"interval",
+		30*time.Second,
+		"Time between checks of the state of the operation when using '--watch'.",
+	)
+
+	flags.DurationVar(
+		&args.timeout,
+		"timeout",
+		2*time.Hour,
+		"Maximum time to wait for the operation to finish when using '--watch'.",
+	)
+}
+
+func validate(r *runner.Runtime) error {
+	if !operationIDRE.MatchString(r.Args[0]) {
+		return rerrors.ValidationErrorf("Operation identifier '%s' isn't valid", r.Args[0])
+	}
+	if args.interval <= 0 {
+		return rerrors.ValidationErrorf("Interval must be a positive duration")
+	}
+	return nil
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	operationID := r.Args[0]
+	client := r.OCMConnection.ClustersMgmt().V1()
+
+	operation, err := refresh(ctx, client, operationID)
+	if err != nil {
+		return err
+	}
+	printOperation(operation)
+	if !args.watch || operation.Finished() {
+		return result(operation)
+	}
+
+	reporter.Infof("Waiting for operation '%s' to finish...", operationID)
+	deadline := time.Now().Add(args.timeout)
+	details := operation.Details
+	for !operation.Finished() {
+		if time.Now().After(deadline) {
+			return rerrors.TimeoutErrorf("Timed out waiting for operation '%s' to finish, "+
+				"last check: %s", operationID, operation.Details)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Stopped waiting for operation '%s', run 'rosa describe operation "+
+				"%s --watch' to continue: %w", operationID, operationID, ctx.Err())
+		case <-time.After(args.interval):
+		}
+		operation, err = refresh(ctx, client, operationID)
+		if err != nil {
+			return err
+		}
+		if operation.Details != details {
+			reporter.Infof("%s", operation.Details)
+			details = operation.Details
+		}
+	}
+	if operation.State == operations.StateSucceeded {
+		reporter.Infof("Operation '%s' succeeded", operationID)
+	}
+	return result(operation)
+}
+
+// refresh loads the operation, updates its state and saves it if it has changed.
+func refresh(ctx context.Context, client *cmv1.Client,
+	operationID string) (*operations.Operation, error) {
+	list, err := operations.Load()
+	if err != nil {
+		return nil, err
+	}
+	operation, err := operations.Get(list, operationID)
+	if err != nil {
+		return nil, err
+	}
+	if operation.Finished() {
+		return operation, nil
+	}
+	observation, err := operations.Observe(ctx, client, operation)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to check operation '%s': %w", operationID, err)
+	}
+	if operation.Evaluate(observation, time.Now()) {
+		err = operations.Save(list)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return operation, nil
+}
+
+func result(operation *operations.Operation) error {
+	if operation.State == operations.StateFailed {
+		return fmt.Errorf("Operation '%s' failed: %s", operation.ID, operation.Details)
+	}
+	return nil
+}
+
+func printOperation(operation *operations.Operation) {
+	operationID := operation.OperationID
+	if operationID == "" {
+		operationID = "-"
+	}
+	finished := "-"
+	if !operation.FinishedAt.IsZero() {
+		finished = operation.FinishedAt.Local().Format(time.RFC3339)
+	}
+	fmt.Printf(""+
+		"ID:                         %s\n"+
+		"Description:                %s\n"+
+		"Type:                       %s\n"+
+		"Cluster ID:                 %s\n"+
+		"Cluster name:               %s\n"+
+		"OCM operation ID:           %s\n"+
+		"Started:                    %s\n"+
+		"Finished:                   %s\n"+
+		"State:                      %s\n"+
+		"Details:                    %s\n",
+		operation.ID,
+		operation.Description(),
+		operation.Type,
+		operation.ClusterID,
+		operation.ClusterName,
+		operationID,
+		operation.StartedAt.Local().Format(time.RFC3339),
+		finished,
+		operation.State,
+		operation.Details,
+	)
+}
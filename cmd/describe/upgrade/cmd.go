@@ -0,0 +1,177 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
+	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/upgrades"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+)
+
+var args struct {
+	clusterKey string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "upgrade",
+	Aliases: []string{"upgrades"},
+	Short:   "Show details of the scheduled upgrade of a cluster",
+	Long: "Show details of the scheduled upgrade of a cluster, including its state. The command " +
+		"fails if the upgrade has failed.\n\nThe progress of an upgrade that has started isn't " +
+		"reported by OCM, only its state and the description of the state.",
+	Example: `  # Describe the scheduled upgrade of the cluster named "mycluster"
+  rosa describe upgrade --cluster=mycluster`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to describe the upgrade of (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+}
+
+func run(_ *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := args.clusterKey
+	if !ocm.IsValidClusterKey(clusterKey) {
+		reporter.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+		exit.Exit(rerrors.ExitCodeValidation)
+	}
+
+	// Create the client for the OCM API:
+	ocmConnection, err := ocm.NewConnection().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
+	}
+	defer func() {
+		err = ocmConnection.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
+	ocmClient := ocmConnection.ClustersMgmt().V1()
+
+	// Create the AWS client:
+	awsClient, err := aws.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create AWS client: %v", err)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
+	}
+
+	awsCreator, err := awsClient.GetCreator()
+	if err != nil {
+		reporter.Errorf("Failed to get AWS creator: %v", err)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
+	}
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(ocmClient.Clusters(), clusterKey, awsCreator.ARN)
+	if err != nil {
+		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	reporter.Debugf("Loading scheduled upgrade for cluster '%s'", clusterKey)
+	scheduledUpgrade, err := upgrades.GetScheduledUpgrade(ocmClient, cluster.ID())
+	if err != nil {
+		reporter.Errorf("Failed to get scheduled upgrades for cluster '%s': %v", clusterKey, err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	if scheduledUpgrade == nil {
+		reporter.Infof("There is no scheduled upgrade for cluster '%s'", clusterKey)
+		exit.Exit(0)
+	}
+
+	reporter.Debugf("Loading state of upgrade '%s' for cluster '%s'", scheduledUpgrade.ID(), clusterKey)
+	state, err := upgrades.GetUpgradePolicyState(ocmClient, cluster.ID(), scheduledUpgrade.ID())
+	if err != nil {
+		reporter.Errorf("Failed to get state of upgrade for cluster '%s': %v", clusterKey, err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	nodeDrain := "-"
+	if cluster.NodeDrainGracePeriod().Value() > 0 {
+		nodeDrain = fmt.Sprintf("%g %s", cluster.NodeDrainGracePeriod().Value(),
+			cluster.NodeDrainGracePeriod().Unit())
+	}
+	currentVersion := cluster.OpenshiftVersion()
+	if currentVersion == "" {
+		currentVersion = cluster.Version().RawID()
+	}
+	// The state of upgrade policies only contains a value and a description, OCM doesn't report
+	// the progress of the upgrade as a percentage:
+	description := state.Description()
+	if description == "" {
+		description = "-"
+	}
+
+	fmt.Printf(""+
+		"ID:                         %s\n"+
+		"Cluster ID:                 %s\n"+
+		"Current version:            %s\n"+
+		"Version:                    %s\n"+
+		"Schedule type:              %s\n"+
+		"Next run:                   %s\n"+
+		"Node drain grace period:    %s\n"+
+		"State:                      %s\n"+
+		"State description:          %s\n",
+		scheduledUpgrade.ID(),
+		cluster.ID(),
+		currentVersion,
+		scheduledUpgrade.Version(),
+		scheduledUpgrade.ScheduleType(),
+		scheduledUpgrade.NextRun().Format("2006-01-02 15:04 MST"),
+		nodeDrain,
+		state.Value(),
+		description,
+	)
+
+	if state.Value() == upgrades.StateFailed {
+		reporter.Errorf("Upgrade of cluster '%s' to version '%s' failed", clusterKey,
+			scheduledUpgrade.Version())
+		exit.Exit(rerrors.ExitCodeGeneric)
+	}
+}
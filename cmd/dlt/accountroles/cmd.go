@@ -0,0 +1,121 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accountroles
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mode"
+	"github.com/openshift/moactl/pkg/aws/tags"
+	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	prefix string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "account-roles",
+	Aliases: []string{"account-role", "accountroles", "accountrole"},
+	Short:   "Delete account-wide IAM roles for STS clusters",
+	Long: "Delete the account roles with the given prefix created with 'rosa create " +
+		"account-roles'. Make sure that no cluster uses them anymore.",
+	Example: `  # Delete the account roles with the default prefix
+  rosa delete account-roles
+
+  # Print the AWS CLI commands that delete the account roles with the prefix "MyOrg"
+  rosa delete account-roles --prefix=MyOrg --mode=manual`,
+	Run: runner.Command(run, validate, runner.WithAWS()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVar(
+		&args.prefix,
+		"prefix",
+		aws.DefaultRolePrefix,
+		"Prefix of the names of the roles to delete.",
+	)
+
+	mode.AddFlag(flags)
+}
+
+func validate(r *runner.Runtime) error {
+	if args.prefix == "" {
+		return rerrors.ValidationErrorf("Role prefix can't be empty")
+	}
+	err := mode.Validate()
+	if err != nil {
+		return rerrors.ValidationErrorf("%v", err)
+	}
+	return nil
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+
+	reporter.Debugf("Loading account roles with prefix '%s'", args.prefix)
+	allRoles, err := r.AWSClient.ListManagedRoles(args.prefix)
+	if err != nil {
+		return err
+	}
+	roles := []aws.Role{}
+	for _, role := range allRoles {
+		if role.Tags[tags.RolePrefix] == args.prefix && role.Tags[tags.RoleType] != "" {
+			roles = append(roles, role)
+		}
+	}
+	if len(roles) == 0 {
+		reporter.Infof("There are no account roles with prefix '%s'", args.prefix)
+		return nil
+	}
+
+	if mode.IsManual() {
+		reporter.Infof("Run the following commands to delete the account roles:")
+		for _, role := range roles {
+			for _, command := range aws.DeleteRoleCommands(role.Name) {
+				fmt.Println(command)
+			}
+		}
+		return nil
+	}
+
+	confirmed, err := confirm.Confirm("delete the account roles with prefix '%s'", args.prefix)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	for _, role := range roles {
+		reporter.Debugf("Deleting role '%s'", role.Name)
+		err = r.AWSClient.DeleteRole(role.Name)
+		if err != nil {
+			return err
+		}
+		reporter.Infof("Deleted role '%s'", role.Name)
+	}
+	return nil
+}
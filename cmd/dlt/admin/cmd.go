@@ -17,23 +17,19 @@ limitations under the License.
 package admin
 
 import (
-	"os"
-
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/pkg/aws"
 	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/admin"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
 )
 
-const (
-	idpName  = "Cluster-Admin"
-	username = "cluster-admin"
-)
-
 var args struct {
 	clusterKey string
 }
@@ -73,7 +69,7 @@ func run(cmd *cobra.Command, _ []string) {
 				"must contain only letters, digits, dashes and underscores",
 			clusterKey,
 		)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	// Create the AWS client:
@@ -82,13 +78,13 @@ func run(cmd *cobra.Command, _ []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	awsCreator, err := awsClient.GetCreator()
 	if err != nil {
 		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	// Create the client for the OCM API:
@@ -97,7 +93,7 @@ func run(cmd *cobra.Command, _ []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
 	}
 	defer func() {
 		err = ocmConnection.Close()
@@ -114,61 +110,39 @@ func run(cmd *cobra.Command, _ []string) {
 	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
 	if err != nil {
 		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	if cluster.State() != cmv1.ClusterStateReady {
 		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeConflict)
 	}
 
 	// Try to find the htpasswd identity provider:
-	reporter.Debugf("Loading '%s' identity provider", idpName)
-	idps, err := ocm.GetIdentityProviders(clustersCollection, cluster.ID())
+	reporter.Debugf("Loading '%s' identity provider", admin.IdpName)
+	idp, err := admin.GetIdentityProvider(clustersCollection, cluster.ID())
 	if err != nil {
-		reporter.Errorf("Failed to get '%s' identity provider for cluster '%s': %v", idpName, clusterKey, err)
-		os.Exit(1)
-	}
-
-	var idp *cmv1.IdentityProvider
-	for _, item := range idps {
-		if ocm.IdentityProviderType(item) == "htpasswd" {
-			idp = item
-		}
+		reporter.Errorf("Failed to get '%s' identity provider for cluster '%s': %v",
+			admin.IdpName, clusterKey, err)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 	if idp == nil {
-		reporter.Errorf("Failed to get '%s' identity provider for cluster '%s'", idpName, clusterKey)
-		os.Exit(1)
+		reporter.Errorf("There is no admin on cluster '%s'", clusterKey)
+		exit.Exit(rerrors.ExitCodeNotFound)
 	}
 
-	if confirm.Confirm("delete %s user on cluster %s", username, clusterKey) {
-		// Delete htpasswd IdP:
-		reporter.Debugf("Deleting '%s' identity provider on cluster '%s'", idpName, clusterKey)
-		idpResp, err := clustersCollection.
-			Cluster(cluster.ID()).
-			IdentityProviders().
-			IdentityProvider(idp.ID()).
-			Delete().
-			Send()
-		if err != nil {
-			reporter.Errorf("Failed to delete '%s' identity provider on cluster '%s': %s",
-				idpName, clusterKey, idpResp.Error().Reason())
-			os.Exit(1)
-		}
-
-		// Delete admin user from the cluster-admins group:
-		reporter.Debugf("Deleting '%s' user from cluster-admins group on cluster '%s'", username, clusterKey)
-		userResp, err := clustersCollection.Cluster(cluster.ID()).
-			Groups().
-			Group("cluster-admins").
-			Users().
-			User(username).
-			Delete().
-			Send()
+	confirmed, err := confirm.Confirm("delete %s user on cluster %s", admin.Username, clusterKey)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	if confirmed {
+		reporter.Debugf("Deleting '%s' user on cluster '%s'", admin.Username, clusterKey)
+		err = admin.DeleteAdmin(clustersCollection, cluster.ID(), idp)
 		if err != nil {
-			reporter.Errorf("Failed to delete '%s' user from cluster '%s': %s",
-				username, clusterKey, userResp.Error().Reason())
-			os.Exit(1)
+			reporter.Errorf("Failed to delete admin on cluster '%s': %v", clusterKey, err)
+			exit.Exit(rerrors.ExitCode(err))
 		}
+		reporter.Infof("Admin user '%s' has been deleted from cluster '%s'", admin.Username, clusterKey)
 	}
 }
@@ -0,0 +1,135 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package breakglass
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/breakglass"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	clusterKey string
+	path       string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "break-glass",
+	Aliases: []string{"breakglass"},
+	Short:   "Delete the break glass access to a cluster",
+	Long: "Revoke all the active break glass credentials of a cluster and remove the kubeconfig " +
+		"file created by 'rosa create break-glass kubeconfig', if it is given.",
+	Example: `  # Revoke the break glass access to the cluster named "mycluster" and remove its kubeconfig
+  rosa delete break-glass --cluster=mycluster --path=mycluster-break-glass.kubeconfig`,
+	Run: runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to delete the break glass access to (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	flags.StringVar(
+		&args.path,
+		"path",
+		"",
+		"Path of the kubeconfig file to remove once the credentials are revoked.",
+	)
+}
+
+func validate(r *runner.Runtime) error {
+	if !c.IsValidClusterKey(args.clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			args.clusterKey,
+		)
+	}
+	return nil
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	clusterKey := args.clusterKey
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(r.OCMConnection.ClustersMgmt().V1().Clusters(), clusterKey,
+		r.Creator.ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	reporter.Debugf("Loading break glass credentials of cluster '%s'", clusterKey)
+	credentials, err := breakglass.GetCredentials(ctx, r.OCMConnection, cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get break glass credentials of cluster '%s': %w", clusterKey,
+			err)
+	}
+	active := breakglass.FilterActive(credentials)
+
+	if len(active) == 0 {
+		reporter.Infof("There are no active break glass credentials for cluster '%s'", clusterKey)
+	} else {
+		confirmed, err := confirm.Confirm("revoke %d break glass credentials of cluster %s",
+			len(active), clusterKey)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return nil
+		}
+
+		reporter.Debugf("Revoking break glass credentials of cluster '%s'", clusterKey)
+		err = breakglass.RevokeCredentials(ctx, r.OCMConnection, cluster.ID())
+		if err != nil {
+			return fmt.Errorf("Failed to revoke break glass credentials of cluster '%s': %w",
+				clusterKey, err)
+		}
+		for _, credential := range active {
+			reporter.Infof("Revoking break glass credential '%s' with username '%s'", credential.ID,
+				credential.Username)
+		}
+	}
+
+	if args.path != "" {
+		err = os.Remove(args.path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("Failed to remove kubeconfig file '%s': %v", args.path, err)
+		}
+		if err == nil {
+			reporter.Infof("Removed kubeconfig file '%s'", args.path)
+		}
+	}
+	return nil
+}
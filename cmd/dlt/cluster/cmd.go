@@ -17,17 +17,20 @@ limitations under the License.
 package cluster
 
 import (
-	"os"
+	"context"
 
 	"github.com/spf13/cobra"
 
 	uninstallLogs "github.com/openshift/moactl/cmd/logs/uninstall"
 
-	"github.com/openshift/moactl/pkg/aws"
 	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/config"
 	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/logging"
-	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/moactl"
+	"github.com/openshift/moactl/pkg/operations"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
 )
 
@@ -35,6 +38,8 @@ var args struct {
 	// Watch logs during cluster uninstallation
 	watch      bool
 	clusterKey string
+
+	overrideDeleteProtection bool
 }
 
 var Cmd = &cobra.Command{
@@ -45,7 +50,10 @@ var Cmd = &cobra.Command{
   rosa delete cluster mycluster
 
   # Delete a cluster using the --cluster flag
-  rosa delete cluster --cluster=mycluster`,
+  rosa delete cluster --cluster=mycluster
+
+  # Delete a cluster that has delete protection enabled
+  rosa delete cluster --cluster=mycluster --override-delete-protection`,
 	Run: run,
 }
 
@@ -59,6 +67,8 @@ func init() {
 		"",
 		"Name or ID of the cluster to delete.",
 	)
+	// The cluster can also be given as the first argument:
+	config.MarkClusterArg(Cmd)
 
 	flags.BoolVar(
 		&args.watch,
@@ -66,6 +76,13 @@ func init() {
 		false,
 		"Watch cluster uninstallation logs.",
 	)
+
+	flags.BoolVar(
+		&args.overrideDeleteProtection,
+		"override-delete-protection",
+		false,
+		"Delete the cluster even if it has delete protection enabled.",
+	)
 }
 
 func run(cmd *cobra.Command, argv []string) {
@@ -80,65 +97,66 @@ func run(cmd *cobra.Command, argv []string) {
 				"Expected exactly one command line argument or flag containing the name " +
 					"or identifier of the cluster",
 			)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 		clusterKey = argv[0]
 	}
 
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
-	if !clusterprovider.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
-	}
-
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
+	client, err := moactl.NewClient().
 		Logger(logger).
 		Build()
 	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
-	}
-
-	awsCreator, err := awsClient.GetCreator()
-	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
-	}
-
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		reporter.Errorf("%v", err)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 	defer func() {
-		err = ocmConnection.Close()
+		err = client.Close()
 		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
+			reporter.Errorf("%v", err)
 		}
 	}()
 
-	// Get the client for the OCM collection of clusters:
-	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
+	// Check the delete protection before asking for confirmation:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := client.GetCluster(context.Background(), clusterKey)
+	if err != nil {
+		reporter.Errorf("%v", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	err = clusterprovider.ValidateDelete(cluster, args.overrideDeleteProtection)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	if clusterprovider.IsDeleteProtected(cluster) {
+		reporter.Warnf("Cluster '%s' has delete protection enabled, it will be deleted because "+
+			"the protection was overridden", clusterKey)
+	}
 
-	if !confirm.Confirm("delete cluster %s", clusterKey) {
-		os.Exit(0)
+	confirmed, err := confirm.Confirm("delete cluster %s", clusterKey)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	if !confirmed {
+		exit.Exit(0)
 	}
 
-	reporter.Debugf("Deleting cluster '%s'", clusterKey)
-	cluster, err := clusterprovider.DeleteCluster(clustersCollection, clusterKey, awsCreator.ARN)
+	step := reporter.Start("Deleting cluster '%s'", clusterKey)
+	cluster, err = client.DeleteCluster(context.Background(), clusterKey, moactl.DeleteClusterOptions{
+		OverrideDeleteProtection: args.overrideDeleteProtection,
+	})
 	if err != nil {
-		reporter.Errorf("Failed to delete cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		step.Fail("%v", err)
+		reporter.Errorf("%v", err)
+		exit.Exit(rerrors.ExitCode(err))
 	}
+	step.Success()
+	operations.Record(reporter, &operations.Operation{
+		Type:        operations.DeleteCluster,
+		ClusterID:   cluster.ID(),
+		ClusterName: cluster.Name(),
+	})
 
 	if args.watch {
 		uninstallLogs.Cmd.Run(cmd, []string{cluster.ID()})
@@ -19,13 +19,19 @@ package dlt
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/moactl/cmd/dlt/accountroles"
 	"github.com/openshift/moactl/cmd/dlt/admin"
+	"github.com/openshift/moactl/cmd/dlt/breakglass"
 	"github.com/openshift/moactl/cmd/dlt/cluster"
 	"github.com/openshift/moactl/cmd/dlt/idp"
 	"github.com/openshift/moactl/cmd/dlt/ingress"
 	"github.com/openshift/moactl/cmd/dlt/machinepool"
+	"github.com/openshift/moactl/cmd/dlt/notificationcontact"
+	"github.com/openshift/moactl/cmd/dlt/oidcprovider"
+	"github.com/openshift/moactl/cmd/dlt/operatorroles"
+	"github.com/openshift/moactl/cmd/dlt/schedule"
 	"github.com/openshift/moactl/cmd/dlt/upgrade"
-	"github.com/openshift/moactl/pkg/confirm"
+	"github.com/openshift/moactl/pkg/config"
 )
 
 var Cmd = &cobra.Command{
@@ -36,13 +42,19 @@ var Cmd = &cobra.Command{
 }
 
 func init() {
-	flags := Cmd.PersistentFlags()
-	confirm.AddFlag(flags)
+	// The subcommands change or delete clusters, so they don't use the saved default cluster:
+	config.MarkExplicitCluster(Cmd)
 
+	Cmd.AddCommand(accountroles.Cmd)
 	Cmd.AddCommand(admin.Cmd)
+	Cmd.AddCommand(breakglass.Cmd)
 	Cmd.AddCommand(cluster.Cmd)
 	Cmd.AddCommand(idp.Cmd)
 	Cmd.AddCommand(ingress.Cmd)
 	Cmd.AddCommand(machinepool.Cmd)
+	Cmd.AddCommand(notificationcontact.Cmd)
+	Cmd.AddCommand(oidcprovider.Cmd)
+	Cmd.AddCommand(operatorroles.Cmd)
+	Cmd.AddCommand(schedule.Cmd)
 	Cmd.AddCommand(upgrade.Cmd)
 }
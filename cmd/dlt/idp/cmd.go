@@ -17,13 +17,13 @@ limitations under the License.
 package idp
 
 import (
-	"os"
-
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/pkg/aws"
 	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
@@ -66,13 +66,13 @@ func run(_ *cobra.Command, argv []string) {
 			"Expected exactly one command line parameters containing the name " +
 				"of the Identity provider.",
 		)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	idpName := argv[0]
 	if idpName == "" {
 		reporter.Errorf("Identity provider name is required.")
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	// Check that the cluster key (name, identifier or external identifier) given by the user
@@ -84,7 +84,7 @@ func run(_ *cobra.Command, argv []string) {
 				"must contain only letters, digits, dashes and underscores",
 			clusterKey,
 		)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	// Create the AWS client:
@@ -93,13 +93,13 @@ func run(_ *cobra.Command, argv []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	awsCreator, err := awsClient.GetCreator()
 	if err != nil {
 		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	// Create the client for the OCM API:
@@ -108,7 +108,7 @@ func run(_ *cobra.Command, argv []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
 	}
 	defer func() {
 		err = ocmConnection.Close()
@@ -125,7 +125,7 @@ func run(_ *cobra.Command, argv []string) {
 	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
 	if err != nil {
 		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	// Try to find the identity provider:
@@ -133,7 +133,7 @@ func run(_ *cobra.Command, argv []string) {
 	idps, err := ocm.GetIdentityProviders(clustersCollection, cluster.ID())
 	if err != nil {
 		reporter.Errorf("Failed to get identity providers for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	var idp *cmv1.IdentityProvider
@@ -144,10 +144,15 @@ func run(_ *cobra.Command, argv []string) {
 	}
 	if idp == nil {
 		reporter.Errorf("Failed to get identity provider '%s' for cluster '%s'", idpName, clusterKey)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeNotFound)
 	}
 
-	if confirm.Confirm("delete identity provider %s on cluster %s", idpName, clusterKey) {
+	confirmed, err := confirm.Confirm("delete identity provider %s on cluster %s", idpName, clusterKey)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	if confirmed {
 		reporter.Debugf("Deleting identity provider '%s' on cluster '%s'", idpName, clusterKey)
 		res, err := clustersCollection.
 			Cluster(cluster.ID()).
@@ -156,10 +161,10 @@ func run(_ *cobra.Command, argv []string) {
 			Delete().
 			Send()
 		if err != nil {
-			reporter.Debugf(err.Error())
-			reporter.Errorf("Failed to delete identity provider '%s' on cluster '%s': %s",
-				idpName, clusterKey, res.Error().Reason())
-			os.Exit(1)
+			err = rerrors.FromOCM(res.Error(), err)
+			reporter.Errorf("Failed to delete identity provider '%s' on cluster '%s': %v",
+				idpName, clusterKey, err)
+			exit.Exit(rerrors.ExitCode(err))
 		}
 	}
 }
@@ -17,7 +17,6 @@ limitations under the License.
 package ingress
 
 import (
-	"os"
 	"regexp"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
@@ -25,6 +24,8 @@ import (
 
 	"github.com/openshift/moactl/pkg/aws"
 	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
@@ -73,7 +74,7 @@ func run(_ *cobra.Command, argv []string) {
 		reporter.Errorf(
 			"Expected exactly one command line parameter containing the id of the ingress",
 		)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	ingressID := argv[0]
@@ -82,7 +83,7 @@ func run(_ *cobra.Command, argv []string) {
 			"Ingress  identifier '%s' isn't valid: it must contain only four letters or digits",
 			ingressID,
 		)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	// Check that the cluster key (name, identifier or external identifier) given by the user
@@ -94,7 +95,7 @@ func run(_ *cobra.Command, argv []string) {
 				"must contain only letters, digits, dashes and underscores",
 			clusterKey,
 		)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	// Create the AWS client:
@@ -103,13 +104,13 @@ func run(_ *cobra.Command, argv []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	awsCreator, err := awsClient.GetCreator()
 	if err != nil {
 		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	// Create the client for the OCM API:
@@ -118,7 +119,7 @@ func run(_ *cobra.Command, argv []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
 	}
 	defer func() {
 		err = ocmConnection.Close()
@@ -135,7 +136,7 @@ func run(_ *cobra.Command, argv []string) {
 	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
 	if err != nil {
 		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	// Try to find the ingress:
@@ -143,7 +144,7 @@ func run(_ *cobra.Command, argv []string) {
 	ingresses, err := ocm.GetIngresses(clustersCollection, cluster.ID())
 	if err != nil {
 		reporter.Errorf("Failed to get ingresses for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	var ingress *cmv1.Ingress
@@ -160,10 +161,15 @@ func run(_ *cobra.Command, argv []string) {
 	}
 	if ingress == nil {
 		reporter.Errorf("Failed to get ingress '%s' for cluster '%s'", ingressID, clusterKey)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeNotFound)
 	}
 
-	if confirm.Confirm("delete ingress %s on cluster %s", ingressID, clusterKey) {
+	confirmed, err := confirm.Confirm("delete ingress %s on cluster %s", ingressID, clusterKey)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	if confirmed {
 		reporter.Debugf("Deleting ingress '%s' on cluster '%s'", ingress.ID(), clusterKey)
 		res, err := clustersCollection.
 			Cluster(cluster.ID()).
@@ -172,10 +178,10 @@ func run(_ *cobra.Command, argv []string) {
 			Delete().
 			Send()
 		if err != nil {
-			reporter.Debugf(err.Error())
-			reporter.Errorf("Failed to delete ingress '%s' on cluster '%s': %s",
-				ingress.ID(), clusterKey, res.Error().Reason())
-			os.Exit(1)
+			err = rerrors.FromOCM(res.Error(), err)
+			reporter.Errorf("Failed to delete ingress '%s' on cluster '%s': %v",
+				ingress.ID(), clusterKey, err)
+			exit.Exit(rerrors.ExitCode(err))
 		}
 	}
 }
@@ -17,23 +17,20 @@ limitations under the License.
 package machinepool
 
 import (
-	"os"
-	"regexp"
+	"context"
 
-	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/aws"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
 	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/logging"
-	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/moactl"
+	"github.com/openshift/moactl/pkg/operations"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
 )
 
-// Regular expression to used to make sure that the identifier given by the
-// user is safe and that it there is no risk of SQL injection:
-var machinePoolKeyRE = regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
-
 var args struct {
 	clusterKey string
 }
@@ -70,105 +67,61 @@ func run(_ *cobra.Command, argv []string) {
 		reporter.Errorf(
 			"Expected exactly one command line parameter containing the id of the machine pool",
 		)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	machinePoolID := argv[0]
-	if !machinePoolKeyRE.MatchString(machinePoolID) {
-		reporter.Errorf("Expected a valid identifier for the machine pool")
-		os.Exit(1)
-	}
-
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
 	clusterKey := args.clusterKey
-	if !ocm.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
-	}
-
-	if machinePoolID == "default" {
+	if machinePoolID == clusterprovider.DefaultMachinePool {
 		reporter.Errorf("Machine pool '%s' cannot be deleted from cluster '%s'", machinePoolID, clusterKey)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
+	client, err := moactl.NewClient().
 		Logger(logger).
 		Build()
 	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
-	}
-
-	awsCreator, err := awsClient.GetCreator()
-	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
-	}
-
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		reporter.Errorf("%v", err)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 	defer func() {
-		err = ocmConnection.Close()
+		err = client.Close()
 		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
+			reporter.Errorf("%v", err)
 		}
 	}()
 
-	// Get the client for the OCM collection of clusters:
-	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
-
-	// Try to find the cluster:
+	// Try to find the cluster and the machine pool:
 	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
+	cluster, err := client.GetCluster(context.Background(), clusterKey)
 	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		reporter.Errorf("%v", err)
+		exit.Exit(rerrors.ExitCode(err))
 	}
-
-	// Try to find the machine pool:
 	reporter.Debugf("Loading machine pools for cluster '%s'", clusterKey)
-	machinePools, err := ocm.GetMachinePools(clustersCollection, cluster.ID())
+	_, err = client.GetMachinePool(context.Background(), clusterKey, machinePoolID)
 	if err != nil {
-		reporter.Errorf("Failed to get machine pools for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		reporter.Errorf("%v", err)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
-	var machinePool *cmv1.MachinePool
-	for _, item := range machinePools {
-		if item.ID() == machinePoolID {
-			machinePool = item
-		}
-	}
-	if machinePool == nil {
-		reporter.Errorf("Failed to get machine pool '%s' for cluster '%s'", machinePoolID, clusterKey)
-		os.Exit(1)
+	confirmed, err := confirm.Confirm("delete machine pool '%s' on cluster '%s'", machinePoolID, clusterKey)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		exit.Exit(rerrors.ExitCode(err))
 	}
-
-	if confirm.Confirm("delete machine pool '%s' on cluster '%s'", machinePoolID, clusterKey) {
-		reporter.Debugf("Deleting machine pool '%s' on cluster '%s'", machinePool.ID(), clusterKey)
-		res, err := clustersCollection.
-			Cluster(cluster.ID()).
-			MachinePools().
-			MachinePool(machinePool.ID()).
-			Delete().
-			Send()
+	if confirmed {
+		reporter.Debugf("Deleting machine pool '%s' on cluster '%s'", machinePoolID, clusterKey)
+		err = client.DeleteMachinePool(context.Background(), clusterKey, machinePoolID)
 		if err != nil {
-			reporter.Debugf(err.Error())
-			reporter.Errorf("Failed to delete machine pool '%s' on cluster '%s': %s",
-				machinePool.ID(), clusterKey, res.Error().Reason())
-			os.Exit(1)
+			reporter.Errorf("%v", err)
+			exit.Exit(rerrors.ExitCode(err))
 		}
+		operations.Record(reporter, &operations.Operation{
+			Type:        operations.DeleteMachinePool,
+			ClusterID:   cluster.ID(),
+			ClusterName: cluster.Name(),
+			Resource:    machinePoolID,
+		})
 	}
 }
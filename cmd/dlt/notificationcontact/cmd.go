@@ -0,0 +1,127 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notificationcontact
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/contacts"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	clusterKey string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "notification-contact USERNAME",
+	Aliases: []string{"notification-contacts", "notificationcontact", "notificationcontacts"},
+	Short:   "Delete notification contact from cluster",
+	Long: "Remove a user from the notification contacts of a cluster. The user can be given by " +
+		"username, account identifier or email.",
+	Example: `  # Remove the user "alice" from the notification contacts of the cluster named "mycluster"
+  rosa delete notification-contact --cluster=mycluster alice`,
+	Run: runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to delete the notification contact from (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+}
+
+func validate(r *runner.Runtime) error {
+	if len(r.Args) != 1 || r.Args[0] == "" {
+		return rerrors.ValidationErrorf(
+			"Expected exactly one command line parameter containing the username of the contact")
+	}
+	return validateClusterKey()
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	clusterKey := args.clusterKey
+	contactKey := r.Args[0]
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(r.OCMConnection.ClustersMgmt().V1().Clusters(), clusterKey,
+		r.Creator.ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+	subscriptionID := cluster.Subscription().ID()
+	if subscriptionID == "" {
+		return fmt.Errorf("Cluster '%s' doesn't have a subscription", clusterKey)
+	}
+
+	reporter.Debugf("Loading notification contacts of cluster '%s'", clusterKey)
+	current, err := contacts.GetContacts(r.OCMConnection, subscriptionID)
+	if err != nil {
+		return fmt.Errorf("Failed to get notification contacts of cluster '%s': %w", clusterKey, err)
+	}
+	contact := contacts.FindContact(current, contactKey)
+	if contact == nil {
+		return rerrors.NotFoundErrorf("User '%s' isn't a notification contact of cluster '%s'",
+			contactKey, clusterKey)
+	}
+
+	confirmed, err := confirm.Confirm("delete notification contact '%s' from cluster '%s'",
+		contactKey, clusterKey)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	reporter.Debugf("Deleting notification contact '%s' from cluster '%s'", contactKey, clusterKey)
+	err = contacts.RemoveContact(r.OCMConnection, subscriptionID, contact.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to delete notification contact '%s' from cluster '%s': %w",
+			contactKey, clusterKey, err)
+	}
+	reporter.Infof("Deleted notification contact '%s' from cluster '%s'", contactKey, clusterKey)
+	return nil
+}
+
+// validate checks that the cluster key given by the user is reasonably safe so that there is no
+// risk of SQL injection.
+func validateClusterKey() error {
+	if !c.IsValidClusterKey(args.clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			args.clusterKey,
+		)
+	}
+	return nil
+}
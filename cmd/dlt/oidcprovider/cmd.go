@@ -0,0 +1,130 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oidcprovider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mode"
+	c "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	clusterKey string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "oidc-provider",
+	Aliases: []string{"oidcprovider"},
+	Short:   "Delete OIDC provider of an STS cluster",
+	Long:    "Delete the OpenID Connect provider created with 'rosa create oidc-provider'.",
+	Example: `  # Delete the OIDC provider of the cluster named "mycluster"
+  rosa delete oidc-provider --cluster=mycluster
+
+  # Print the AWS CLI command that deletes the OIDC provider instead
+  rosa delete oidc-provider --cluster=mycluster --mode=manual`,
+	Run: runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to delete the OIDC provider of (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	mode.AddFlag(flags)
+}
+
+func validate(r *runner.Runtime) error {
+	if !c.IsValidClusterKey(args.clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			args.clusterKey,
+		)
+	}
+	err := mode.Validate()
+	if err != nil {
+		return rerrors.ValidationErrorf("%v", err)
+	}
+	return nil
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	clusterKey := args.clusterKey
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(r.OCMConnection.ClustersMgmt().V1().Clusters(), clusterKey,
+		r.Creator.ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	sts, err := c.GetSTS(r.OCMConnection, cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get STS settings of cluster '%s': %w", clusterKey, err)
+	}
+	if sts == nil || sts.OIDCEndpointURL == "" {
+		return rerrors.ValidationErrorf("Cluster '%s' doesn't use STS", clusterKey)
+	}
+
+	providerARN, err := r.AWSClient.GetOIDCProviderARN(sts.OIDCEndpointURL)
+	if err != nil {
+		return err
+	}
+	if providerARN == "" {
+		reporter.Infof("There is no OIDC provider for cluster '%s'", clusterKey)
+		return nil
+	}
+
+	if mode.IsManual() {
+		reporter.Infof("Run the following command to delete the OIDC provider:")
+		fmt.Println(aws.DeleteOIDCProviderCommand(providerARN))
+		return nil
+	}
+
+	confirmed, err := confirm.Confirm("delete the OIDC provider of cluster '%s'", clusterKey)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	err = r.AWSClient.DeleteOIDCProvider(providerARN)
+	if err != nil {
+		return err
+	}
+	reporter.Infof("Deleted OIDC provider '%s'", providerARN)
+	return nil
+}
@@ -0,0 +1,152 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operatorroles
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mode"
+	"github.com/openshift/moactl/pkg/aws/tags"
+	c "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	clusterKey string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "operator-roles",
+	Aliases: []string{"operator-role", "operatorroles", "operatorrole"},
+	Short:   "Delete operator IAM roles of an STS cluster",
+	Long: "Delete the operator roles of a cluster created with 'rosa create operator-roles'. " +
+		"The roles of clusters that have already been deleted can be deleted using the name or " +
+		"the identifier that the cluster had.",
+	Example: `  # Delete the operator roles of the cluster named "mycluster"
+  rosa delete operator-roles --cluster=mycluster
+
+  # Print the AWS CLI commands that delete the operator roles instead
+  rosa delete operator-roles --cluster=mycluster --mode=manual`,
+	Run: runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to delete the operator roles of (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	mode.AddFlag(flags)
+}
+
+func validate(r *runner.Runtime) error {
+	if !c.IsValidClusterKey(args.clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			args.clusterKey,
+		)
+	}
+	err := mode.Validate()
+	if err != nil {
+		return rerrors.ValidationErrorf("%v", err)
+	}
+	return nil
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	clusterKey := args.clusterKey
+
+	// Try to find the cluster. When it no longer exists the roles are found using the key as
+	// either the identifier or the name of the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(r.OCMConnection.ClustersMgmt().V1().Clusters(), clusterKey,
+		r.Creator.ARN)
+	if err != nil && rerrors.ExitCode(err) != rerrors.ExitCodeNotFound {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+	namePrefix := ""
+	matches := func(role aws.Role) bool {
+		return role.Tags[tags.ClusterID] == clusterKey ||
+			strings.HasPrefix(role.Name, clusterKey+"-openshift-")
+	}
+	if cluster != nil {
+		namePrefix = cluster.Name()
+		matches = func(role aws.Role) bool {
+			return role.Tags[tags.ClusterID] == cluster.ID()
+		}
+	}
+
+	reporter.Debugf("Loading operator roles of cluster '%s'", clusterKey)
+	allRoles, err := r.AWSClient.ListManagedRoles(namePrefix)
+	if err != nil {
+		return err
+	}
+	roles := []aws.Role{}
+	for _, role := range allRoles {
+		if role.Tags[tags.OperatorNamespace] != "" && matches(role) {
+			roles = append(roles, role)
+		}
+	}
+	if len(roles) == 0 {
+		reporter.Infof("There are no operator roles for cluster '%s'", clusterKey)
+		return nil
+	}
+
+	if mode.IsManual() {
+		reporter.Infof("Run the following commands to delete the operator roles:")
+		for _, role := range roles {
+			for _, command := range aws.DeleteRoleCommands(role.Name) {
+				fmt.Println(command)
+			}
+		}
+		return nil
+	}
+
+	confirmed, err := confirm.Confirm("delete the operator roles of cluster '%s'", clusterKey)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	for _, role := range roles {
+		reporter.Debugf("Deleting role '%s'", role.Name)
+		err = r.AWSClient.DeleteRole(role.Name)
+		if err != nil {
+			return err
+		}
+		reporter.Infof("Deleted role '%s'", role.Name)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/runner"
+	"github.com/openshift/moactl/pkg/schedules"
+)
+
+var args struct {
+	clusterKey string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "schedule ID",
+	Aliases: []string{"schedules"},
+	Short:   "Delete scaling schedule from cluster",
+	Long:    "Delete one of the schedules that scale the machine pools of a cluster.",
+	Example: `  # Delete the schedule with identifier 2 from the cluster named "mycluster"
+  rosa delete schedule --cluster=mycluster 2`,
+	Run: runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to delete the schedule from (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+}
+
+func validate(r *runner.Runtime) error {
+	if len(r.Args) != 1 || r.Args[0] == "" {
+		return rerrors.ValidationErrorf(
+			"Expected exactly one command line parameter containing the identifier of the schedule")
+	}
+	if !c.IsValidClusterKey(args.clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			args.clusterKey,
+		)
+	}
+	return nil
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	clusterKey := args.clusterKey
+	scheduleID := r.Args[0]
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(r.OCMConnection.ClustersMgmt().V1().Clusters(), clusterKey,
+		r.Creator.ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	confirmed, err := confirm.Confirm("delete schedule '%s' from cluster '%s'", scheduleID, clusterKey)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	reporter.Debugf("Deleting schedule '%s' from cluster '%s'", scheduleID, clusterKey)
+	err = schedules.Remove(cluster.ID(), scheduleID)
+	if err != nil {
+		return fmt.Errorf("Failed to delete schedule '%s' from cluster '%s': %w", scheduleID,
+			clusterKey, err)
+	}
+	reporter.Infof("Deleted schedule '%s' from cluster '%s'", scheduleID, clusterKey)
+	return nil
+}
@@ -17,16 +17,17 @@ limitations under the License.
 package upgrade
 
 import (
-	"os"
+	"context"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/aws"
 	c "github.com/openshift/moactl/pkg/cluster"
 	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/logging"
-	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/moactl"
 	"github.com/openshift/moactl/pkg/ocm/upgrades"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
 )
@@ -70,77 +71,63 @@ func run(cmd *cobra.Command, _ []string) {
 				"must contain only letters, digits, dashes and underscores",
 			clusterKey,
 		)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
-	// Create the AWS client:
-	var err error
-	awsClient, err := aws.NewClient().
+	client, err := moactl.NewClient().
 		Logger(logger).
 		Build()
 	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
-	}
-
-	awsCreator, err := awsClient.GetCreator()
-	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
-	}
-
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		reporter.Errorf("%v", err)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 	defer func() {
-		err = ocmConnection.Close()
+		err = client.Close()
 		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
+			reporter.Errorf("%v", err)
 		}
 	}()
 
-	// Get the client for the OCM collection of clusters:
-	ocmClient := ocmConnection.ClustersMgmt().V1()
-
 	// Try to find the cluster:
 	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := ocm.GetCluster(ocmClient.Clusters(), clusterKey, awsCreator.ARN)
+	cluster, err := client.GetCluster(context.Background(), clusterKey)
 	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		reporter.Errorf("%v", err)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	if cluster.State() != cmv1.ClusterStateReady {
 		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeConflict)
 	}
 
-	scheduledUpgrade, err := upgrades.GetScheduledUpgrade(ocmClient, cluster.ID())
+	scheduledUpgrade, err := upgrades.GetScheduledUpgrade(client.Connection().ClustersMgmt().V1(),
+		cluster.ID())
 	if err != nil {
 		reporter.Errorf("Failed to get scheduled upgrades for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 	if scheduledUpgrade == nil {
 		reporter.Warnf("There are no scheduled upgrades on cluster '%s'", clusterKey)
-		os.Exit(0)
+		exit.Exit(0)
 	}
 
-	if confirm.Confirm("cancel scheduled upgrade on cluster %s", clusterKey) {
+	confirmed, err := confirm.Confirm("cancel scheduled upgrade on cluster %s", clusterKey)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	if confirmed {
 		reporter.Debugf("Deleting scheduled upgrade for cluster '%s'", clusterKey)
-		canceled, err := upgrades.CancelUpgrade(ocmClient, cluster.ID())
+		canceled, err := client.CancelUpgrade(context.Background(), clusterKey)
 		if err != nil {
-			reporter.Errorf("Failed to cancel scheduled upgrade on cluster '%s': %v", clusterKey, err)
-			os.Exit(1)
+			reporter.Errorf("%v", err)
+			exit.Exit(rerrors.ExitCode(err))
 		}
 
 		if !canceled {
 			reporter.Warnf("There were no scheduled upgrades on cluster '%s'", clusterKey)
-			os.Exit(0)
+			exit.Exit(0)
 		}
 
 		reporter.Infof("Successfully canceled scheduled upgrade on cluster '%s'", clusterKey)
@@ -17,128 +17,89 @@ limitations under the License.
 package oc
 
 import (
-	"fmt"
-	"io"
-	"net/http"
-	"os"
-	"runtime"
-	"strings"
-
-	"github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/moactl/pkg/download"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
 
 	"github.com/openshift/moactl/cmd/verify/oc"
 )
 
+var args struct {
+	version    string
+	outputDir  string
+	installDir string
+}
+
 var Cmd = &cobra.Command{
 	Use:     "openshift-client",
 	Aliases: []string{"oc", "openshift"},
 	Short:   "Download OpenShift client tools",
-	Long:    "Downloads to latest compatible version of the OpenShift client tools.",
+	Long: "Downloads to latest compatible version of the OpenShift client tools, verifying the " +
+		"checksum published in the mirror.",
 	Example: `  # Download oc client tools
-  rosa download oc`,
+  rosa download oc
+
+  # Download the oc client tools of OpenShift 4.6 and install them in ~/bin
+  rosa download oc --version=stable-4.6 --install-dir=$HOME/bin`,
 	Run: run,
 }
 
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVar(
+		&args.version,
+		"version",
+		"latest",
+		"Version of the client tools, for example '4.6.8', or channel, for example 'stable-4.6'.",
+	)
+
+	flags.StringVar(
+		&args.outputDir,
+		"output-dir",
+		".",
+		"Directory where the archive is downloaded.",
+	)
+
+	flags.StringVar(
+		&args.installDir,
+		"install-dir",
+		"",
+		"Directory where the 'oc' executable is extracted from the archive. When not given the "+
+			"archive is only downloaded.",
+	)
+}
+
 func run(cmd *cobra.Command, argv []string) {
 	reporter := rprtr.CreateReporterOrExit()
 
 	// Verify whether `oc` is installed
 	oc.Cmd.Run(cmd, argv)
 
-	platform := getPlatform()
-	extension := getExtension()
-
-	filename := fmt.Sprintf("openshift-client-%s.%s", platform, extension)
-	downloadURL := fmt.Sprintf("https://mirror.openshift.com/pub/openshift-v4/clients/ocp/latest/%s", filename)
-
-	reporter.Infof("Downloading %s", downloadURL)
-
-	err := download(downloadURL, filename)
+	goos, goarch := download.CurrentPlatform()
+	artifact, err := download.ClientArtifact(args.version, goos, goarch)
 	if err != nil {
 		reporter.Errorf("%s", err)
-		os.Exit(1)
-	}
-
-	reporter.Infof("Successfully downloaded %s", filename)
-}
-
-// Get the platform name used on the oc tarball filename
-func getPlatform() string {
-	if runtime.GOOS == "darwin" {
-		return "mac"
-	}
-	return runtime.GOOS
-}
-
-// Get the extension used for the compressed oc file
-func getExtension() string {
-	if runtime.GOOS == "windows" {
-		return "zip"
-	}
-	return "tar.gz"
-}
-
-// download will download a url to a local file. It's efficient because it will
-// write as it downloads and not load the whole file into memory. We pass an io.TeeReader
-// into Copy() to report progress on the download.
-func download(url string, filename string) error {
-	// Create the file, but give it a tmp file extension, this means we won't overwrite a
-	// file until it's downloaded, but we'll remove the tmp extension once downloaded.
-	out, err := os.Create(filename + ".tmp")
-	if err != nil {
-		return err
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
-	// Get the data
-	// nolint:gosec
-	resp, err := http.Get(url)
+	reporter.Infof("Downloading %s", artifact.URL())
+	path, err := download.Fetch(artifact, args.outputDir)
 	if err != nil {
-		out.Close()
-		return err
-	}
-	defer resp.Body.Close()
-
-	// Create our progress reporter and pass it to be used alongside our writer
-	counter := &WriteCounter{}
-	if _, err = io.Copy(out, io.TeeReader(resp.Body, counter)); err != nil {
-		out.Close()
-		return err
+		reporter.Errorf("%s", err)
+		exit.Exit(rerrors.ExitCode(err))
 	}
-
-	// The progress use the same line so print a new line once it's finished downloading
-	fmt.Print("\n")
-
-	// Close the file without defer so it can happen before Rename()
-	out.Close()
-
-	if err = os.Rename(filename+".tmp", filename); err != nil {
-		return err
+	reporter.Infof("Successfully downloaded %s, checksum verified", path)
+
+	if args.installDir != "" {
+		binary, err := download.Install(artifact, path, args.installDir)
+		if err != nil {
+			reporter.Errorf("Failed to install '%s': %s", artifact.Binary, err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		reporter.Infof("Installed %s, make sure that '%s' is in your PATH", binary, args.installDir)
 	}
-	return nil
-}
-
-// WriteCounter counts the number of bytes written to it. It implements to the io.Writer interface
-// and we can pass this into io.TeeReader() which will report progress on each write cycle.
-type WriteCounter struct {
-	Total uint64
-}
-
-func (wc *WriteCounter) Write(p []byte) (int, error) {
-	n := len(p)
-	wc.Total += uint64(n)
-	wc.PrintProgress()
-	return n, nil
-}
-
-func (wc WriteCounter) PrintProgress() {
-	// Clear the line by using a character return to go back to the start and remove
-	// the remaining characters by filling it with spaces
-	fmt.Printf("\r%s", strings.Repeat(" ", 35))
-
-	// Return again and print current status of download
-	// We use the humanize package to print the bytes in a meaningful way (e.g. 10 MB)
-	fmt.Printf("\rDownloading... %s complete", humanize.Bytes(wc.Total))
 }
@@ -0,0 +1,91 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rosa
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/download"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+)
+
+var args struct {
+	outputDir  string
+	installDir string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "rosa",
+	Short: "Download the latest release of this tool",
+	Long: "Downloads the latest release of this tool for the current platform, verifying the " +
+		"checksum published in the mirror.",
+	Example: `  # Download the latest release
+  rosa download rosa
+
+  # Download the latest release and install it in ~/bin
+  rosa download rosa --install-dir=$HOME/bin`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVar(
+		&args.outputDir,
+		"output-dir",
+		".",
+		"Directory where the archive is downloaded.",
+	)
+
+	flags.StringVar(
+		&args.installDir,
+		"install-dir",
+		"",
+		"Directory where the 'rosa' executable is extracted from the archive. When not given the "+
+			"archive is only downloaded.",
+	)
+}
+
+func run(_ *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+
+	goos, goarch := download.CurrentPlatform()
+	artifact, err := download.ROSAArtifact("latest", goos, goarch)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		exit.Exit(rerrors.ExitCodeValidation)
+	}
+
+	reporter.Infof("Downloading %s", artifact.URL())
+	path, err := download.Fetch(artifact, args.outputDir)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	reporter.Infof("Successfully downloaded %s, checksum verified", path)
+
+	if args.installDir != "" {
+		binary, err := download.Install(artifact, path, args.installDir)
+		if err != nil {
+			reporter.Errorf("Failed to install '%s': %s", artifact.Binary, err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		reporter.Infof("Installed %s, make sure that '%s' is in your PATH", binary, args.installDir)
+	}
+}
@@ -17,20 +17,29 @@ limitations under the License.
 package cluster
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
+	"strings"
 	"time"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/pkg/aws"
 	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/config"
+	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/interactive"
 	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/upgrades"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/verify/quota"
 )
 
 var args struct {
@@ -42,21 +51,70 @@ var args struct {
 
 	// Networking options
 	private bool
+	public  bool
+
+	// Cluster-wide proxy options
+	httpProxy                 string
+	httpsProxy                string
+	noProxy                   string
+	additionalTrustBundleFile string
 
 	// Access control options
 	clusterAdmins bool
+
+	// Delete protection options
+	deleteProtection bool
+
+	// Custom properties options
+	properties []string
+
+	// Upgrade options
+	nodeDrainGracePeriod string
+
+	// Scaling options
+	computeNodes int
+
+	// Generic options
+	settings  []string
+	showPatch bool
 }
 
 var Cmd = &cobra.Command{
 	Use:   "cluster",
 	Short: "Edit cluster",
-	Long:  "Edit cluster.",
+	Long: "Edit cluster. The fields that will change are shown with their current and new " +
+		"values, and the changes are only applied once confirmed, or when '--yes' is given.",
 	Example: `  # Edit a cluster named "mycluster" to make it private
   rosa edit cluster mycluster --private
 
+  # Edit a cluster named "mycluster" to make it public
+  rosa edit cluster mycluster --public
+
   # Enable the cluster-admins group using the --cluster flag
   rosa edit cluster --cluster=mycluster --enable-cluster-admins
 
+  # Protect a cluster named "mycluster" against accidental deletion
+  rosa edit cluster -c mycluster --enable-delete-protection
+
+  # Label a cluster named "mycluster" so that it can be selected by batch commands, and remove
+  # its "team" property
+  rosa edit cluster -c mycluster --properties env=staging,team-
+
+  # Change the node drain grace period used during upgrades
+  rosa edit cluster -c mycluster --node-drain-grace-period=90m
+
+  # Scale the default machine pool of a cluster named "mycluster" to 4 compute nodes
+  rosa edit cluster -c mycluster --compute-nodes=4
+
+  # Configure a cluster-wide proxy
+  rosa edit cluster -c mycluster --http-proxy=http://proxy.example.com:3128 --no-proxy=.example.com
+
+  # Remove the additional trust bundle of a cluster
+  rosa edit cluster -c mycluster --additional-trust-bundle-file ""
+
+  # Change the display name, which doesn't have a dedicated flag, and preview the request
+  rosa edit cluster -c mycluster --set display_name="My cluster" --show-patch
+
   # Edit all options interactively
   rosa edit cluster -c mycluster --interactive`,
 	Run: run,
@@ -73,6 +131,8 @@ func init() {
 		"",
 		"Name or ID of the cluster to edit.",
 	)
+	// The cluster can also be given as the first argument:
+	config.MarkClusterArg(Cmd)
 
 	// Basic options
 	flags.StringVar(
@@ -96,7 +156,43 @@ func init() {
 		&args.private,
 		"private",
 		false,
-		"Restrict master API endpoint to direct, private connectivity.",
+		"Restrict master API endpoint and the default application router to direct, private connectivity.",
+	)
+	flags.BoolVar(
+		&args.public,
+		"public",
+		false,
+		"Make the master API endpoint and the default application router accessible from the internet.",
+	)
+
+	flags.StringVar(
+		&args.httpProxy,
+		"http-proxy",
+		"",
+		"A proxy URL to use for creating HTTP connections outside the cluster. The URL scheme must be http. "+
+			"Use an empty value to remove the HTTP proxy.",
+	)
+	flags.StringVar(
+		&args.httpsProxy,
+		"https-proxy",
+		"",
+		"A proxy URL to use for creating HTTPS connections outside the cluster. "+
+			"Use an empty value to remove the HTTPS proxy.",
+	)
+	flags.StringVar(
+		&args.noProxy,
+		"no-proxy",
+		"",
+		"A comma-separated list of destination domain names, domains, IP addresses or other network CIDRs "+
+			"to exclude proxying, for example: --no-proxy=.example.com,10.0.0.0/16.",
+	)
+	flags.StringVar(
+		&args.additionalTrustBundleFile,
+		"additional-trust-bundle-file",
+		"",
+		"A file containing a PEM-encoded X.509 certificate bundle that will be added to the nodes' "+
+			"trusted certificate store. Use an empty value, like '--additional-trust-bundle-file \"\"', "+
+			"to remove the current bundle.",
 	)
 
 	// Access control options
@@ -106,6 +202,62 @@ func init() {
 		false,
 		"Enable the cluster-admins role for your cluster.",
 	)
+
+	// Delete protection options
+	flags.BoolVar(
+		&args.deleteProtection,
+		"enable-delete-protection",
+		false,
+		"Refuse to delete the cluster unless the '--override-delete-protection' flag is given. "+
+			"Use '--enable-delete-protection=false' to disable it.",
+	)
+
+	// Custom properties options
+	flags.StringSliceVar(
+		&args.properties,
+		"properties",
+		nil,
+		"Set custom properties of the cluster, using 'key=value' format, or remove them, using "+
+			"'key-' format. Can be a comma-separated list or repeated. Properties can be used to "+
+			"select clusters in batch commands, like 'rosa upgrade cluster --selector'.",
+	)
+
+	// Upgrade options
+	flags.StringVar(
+		&args.nodeDrainGracePeriod,
+		"node-drain-grace-period",
+		"",
+		"You may set a grace period for how long Pod Disruption Budget-protected workloads will be "+
+			"respected during upgrades, for example '30 minutes', '2 hours', '90m' or a number of "+
+			"minutes like '90', up to one week.\nAfter this grace period, any workloads protected by "+
+			"Pod Disruption Budgets that have not been successfully drained from a node will be "+
+			"forcibly evicted.",
+	)
+
+	// Scaling options
+	flags.IntVar(
+		&args.computeNodes,
+		"compute-nodes",
+		0,
+		"Number of compute nodes of the default machine pool. Single zone clusters need at least 2 "+
+			"nodes, multizone clusters need at least 3 nodes and a multiple of the number of zones.",
+	)
+
+	// Generic options
+	flags.StringArrayVar(
+		&args.settings,
+		"set",
+		nil,
+		"Change an attribute of the cluster that doesn't have a dedicated flag, using 'path=value' "+
+			"format. Can be repeated. Supported paths: "+
+			strings.Join(clusterprovider.SettingPaths(), ", ")+".",
+	)
+	flags.BoolVar(
+		&args.showPatch,
+		"show-patch",
+		false,
+		"Print the body of the request that updates the cluster instead of sending it.",
+	)
 }
 
 func run(cmd *cobra.Command, argv []string) {
@@ -119,7 +271,7 @@ func run(cmd *cobra.Command, argv []string) {
 				"Expected exactly one command line argument or flag containing the name " +
 					"or identifier of the cluster",
 			)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 		clusterKey = argv[0]
 	}
@@ -132,13 +284,15 @@ func run(cmd *cobra.Command, argv []string) {
 				"must contain only letters, digits, dashes and underscores",
 			clusterKey,
 		)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	isInteractive := interactive.Enabled()
 	if !isInteractive {
 		changedFlags := false
-		for _, flag := range []string{"private", "enable-cluster-admins"} {
+		for _, flag := range []string{"private", "public", "http-proxy", "https-proxy", "no-proxy",
+			"additional-trust-bundle-file", "enable-cluster-admins", "enable-delete-protection",
+			"properties", "node-drain-grace-period", "compute-nodes", "set"} {
 			if cmd.Flags().Changed(flag) {
 				changedFlags = true
 			}
@@ -156,7 +310,7 @@ func run(cmd *cobra.Command, argv []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
 	}
 	defer func() {
 		err = ocmConnection.Close()
@@ -172,27 +326,37 @@ func run(cmd *cobra.Command, argv []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	awsCreator, err := awsClient.GetCreator()
 	if err != nil {
 		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	reporter.Debugf("Loading cluster '%s'", clusterKey)
 	cluster, err := clusterprovider.GetCluster(ocmClient.Clusters(), clusterKey, awsCreator.ARN)
 	if err != nil {
 		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	// Validate flags:
 	expiration, err := validateExpiration()
 	if err != nil {
 		reporter.Errorf(fmt.Sprintf("%s", err))
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	settings, err := clusterprovider.ParseSettings(args.settings)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	setProperties, removeProperties, err := clusterprovider.ParseProperties(args.properties)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	if interactive.Enabled() {
@@ -200,13 +364,21 @@ func run(cmd *cobra.Command, argv []string) {
 			"Any optional fields can be ignored and will not be updated.")
 	}
 
+	if cmd.Flags().Changed("private") && cmd.Flags().Changed("public") {
+		reporter.Errorf("Only one of '--private' or '--public' may be specified")
+		exit.Exit(rerrors.ExitCodeValidation)
+	}
+
 	var private *bool
 	var privateValue bool
 	if cmd.Flags().Changed("private") {
 		privateValue = args.private
 		private = &privateValue
+	} else if cmd.Flags().Changed("public") {
+		privateValue = !args.public
+		private = &privateValue
 	} else if isInteractive {
-		privateValue = cluster.API().Listening() == cmv1.ListeningMethodInternal
+		privateValue = clusterprovider.IsPrivate(cluster)
 	}
 
 	if isInteractive {
@@ -217,7 +389,7 @@ func run(cmd *cobra.Command, argv []string) {
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid private value: %s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 		private = &privateValue
 	}
@@ -239,23 +411,338 @@ func run(cmd *cobra.Command, argv []string) {
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid cluster-admins value: %s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 		clusterAdmins = &clusterAdminsValue
 	}
 
+	var deleteProtection *bool
+	var deleteProtectionValue bool
+	if cmd.Flags().Changed("enable-delete-protection") {
+		deleteProtectionValue = args.deleteProtection
+		deleteProtection = &deleteProtectionValue
+	} else if isInteractive {
+		deleteProtectionValue = clusterprovider.IsDeleteProtected(cluster)
+	}
+
+	if isInteractive {
+		deleteProtectionValue, err = interactive.GetBool(interactive.Input{
+			Question: "Enable delete protection",
+			Help:     cmd.Flags().Lookup("enable-delete-protection").Usage,
+			Default:  deleteProtectionValue,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid delete protection value: %s", err)
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+		deleteProtection = &deleteProtectionValue
+	}
+
+	var nodeDrainGracePeriod *float64
+	nodeDrainGracePeriodValue := args.nodeDrainGracePeriod
+	if !cmd.Flags().Changed("node-drain-grace-period") && isInteractive {
+		nodeDrainGracePeriodValue = upgrades.FormatNodeDrainGracePeriod(cluster.NodeDrainGracePeriod())
+	}
+
+	if isInteractive {
+		nodeDrainGracePeriodValue, err = interactive.GetString(interactive.Input{
+			Question: "Node drain grace period",
+			Help:     cmd.Flags().Lookup("node-drain-grace-period").Usage,
+			Default:  nodeDrainGracePeriodValue,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid node drain grace period: %s", err)
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+	}
+
+	if isInteractive && nodeDrainGracePeriodValue != "" ||
+		cmd.Flags().Changed("node-drain-grace-period") {
+		minutes, err := upgrades.ParseNodeDrainGracePeriod(nodeDrainGracePeriodValue)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		nodeDrainGracePeriod = &minutes
+	}
+
+	// Compute nodes of the default machine pool:
+	currentComputeNodes := cluster.Nodes().Compute()
+	computeNodes := currentComputeNodes
+	if cmd.Flags().Changed("compute-nodes") {
+		computeNodes = args.computeNodes
+	}
+	if isInteractive {
+		computeNodes, err = interactive.GetInt(interactive.Input{
+			Question: "Compute nodes",
+			Help:     cmd.Flags().Lookup("compute-nodes").Usage,
+			Default:  computeNodes,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid number of compute nodes: %s", err)
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+	}
+	computeNodesChanged := computeNodes != currentComputeNodes
+	if computeNodesChanged {
+		err = clusterprovider.ValidateComputeNodes(cluster, computeNodes)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		err = verifyScaling(reporter, logger, ocmClient, cluster, computeNodes)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+	}
+
+	// Cluster-wide proxy:
+	httpProxy := args.httpProxy
+	httpsProxy := args.httpsProxy
+	noProxy := args.noProxy
+	additionalTrustBundleFile := args.additionalTrustBundleFile
+	proxyChanged := false
+	for _, flag := range []string{"http-proxy", "https-proxy", "no-proxy"} {
+		if cmd.Flags().Changed(flag) {
+			proxyChanged = true
+		}
+	}
+	// The current proxy configuration is used for the options that aren't given, so that they are
+	// preserved and validated together with the new ones:
+	if isInteractive || proxyChanged {
+		proxy, err := clusterprovider.GetProxy(ocmConnection, cluster.ID())
+		if err != nil {
+			reporter.Errorf("Failed to get proxy configuration for cluster '%s': %v", clusterKey, err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		if proxy != nil {
+			if !cmd.Flags().Changed("http-proxy") {
+				httpProxy = proxy.HTTPProxy
+			}
+			if !cmd.Flags().Changed("https-proxy") {
+				httpsProxy = proxy.HTTPSProxy
+			}
+			if !cmd.Flags().Changed("no-proxy") {
+				noProxy = proxy.NoProxy
+			}
+		}
+	}
+	if isInteractive {
+		httpProxy, err = interactive.GetString(interactive.Input{
+			Question: "HTTP proxy",
+			Help:     cmd.Flags().Lookup("http-proxy").Usage,
+			Default:  httpProxy,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid HTTP proxy: %s", err)
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+		httpsProxy, err = interactive.GetString(interactive.Input{
+			Question: "HTTPS proxy",
+			Help:     cmd.Flags().Lookup("https-proxy").Usage,
+			Default:  httpsProxy,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid HTTPS proxy: %s", err)
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+		if httpProxy != "" || httpsProxy != "" {
+			noProxy, err = interactive.GetString(interactive.Input{
+				Question: "No proxy",
+				Help:     cmd.Flags().Lookup("no-proxy").Usage,
+				Default:  noProxy,
+			})
+			if err != nil {
+				reporter.Errorf("Expected a valid no-proxy list: %s", err)
+				exit.Exit(rerrors.ExitCodeValidation)
+			}
+		}
+		additionalTrustBundleFile, err = interactive.GetCert(interactive.Input{
+			Question: "Additional trust bundle file path",
+			Help:     cmd.Flags().Lookup("additional-trust-bundle-file").Usage,
+			Default:  additionalTrustBundleFile,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid additional trust bundle file: %s", err)
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+	}
+	err = clusterprovider.ValidateProxy(httpProxy, httpsProxy, noProxy)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	// Changing the visibility may cut the connectivity to the cluster, so make sure that it is
+	// supported and that the user understands the consequences:
+	visibilityChanged := private != nil && *private != clusterprovider.IsPrivate(cluster)
+	if visibilityChanged {
+		err = clusterprovider.ValidateVisibilityChange(cluster)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		if *private {
+			reporter.Warnf("You are choosing to make your cluster API and the default application " +
+				"router private. You will not be able to access your cluster until you connect to " +
+				"its VPC, for example using a VPN, AWS Direct Connect or VPC peering.")
+		} else {
+			reporter.Warnf("You are choosing to make your cluster API and the default application " +
+				"router public. They will be accessible from the internet, although authentication " +
+				"will still be required.")
+		}
+	}
+
 	clusterConfig := clusterprovider.Spec{
-		Expiration:    expiration,
-		Private:       private,
-		ClusterAdmins: clusterAdmins,
+		Expiration:       expiration,
+		Private:          private,
+		ClusterAdmins:    clusterAdmins,
+		DeleteProtection: deleteProtection,
+
+		NodeDrainGracePeriod: nodeDrainGracePeriod,
+
+		Settings: settings,
+	}
+	if len(setProperties) > 0 {
+		clusterConfig.CustomProperties = setProperties
+	}
+	if len(removeProperties) > 0 {
+		clusterConfig.RemoveProperties = removeProperties
+	}
+	if computeNodesChanged {
+		clusterConfig.ComputeNodes = computeNodes
+	}
+
+	// Only the proxy options explicitly given are updated, so that the rest are preserved:
+	if isInteractive || cmd.Flags().Changed("http-proxy") {
+		clusterConfig.HTTPProxy = &httpProxy
+	}
+	if isInteractive || cmd.Flags().Changed("https-proxy") {
+		clusterConfig.HTTPSProxy = &httpsProxy
+	}
+	if isInteractive || cmd.Flags().Changed("no-proxy") {
+		clusterConfig.NoProxy = &noProxy
+	}
+	// An empty file given explicitly removes the current bundle, while an empty answer to the
+	// interactive question keeps it:
+	if additionalTrustBundleFile != "" {
+		additionalTrustBundle, err := clusterprovider.ReadAdditionalTrustBundle(additionalTrustBundleFile)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		clusterConfig.AdditionalTrustBundle = &additionalTrustBundle
+	} else if cmd.Flags().Changed("additional-trust-bundle-file") {
+		noAdditionalTrustBundle := ""
+		clusterConfig.AdditionalTrustBundle = &noAdditionalTrustBundle
+	}
+
+	body, err := clusterprovider.UpdatePatch(cluster, clusterConfig)
+	if err != nil {
+		reporter.Errorf("Failed to create description of cluster: %v", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	// Print the request instead of sending it when the user only wants to preview it:
+	if args.showPatch {
+		var indented bytes.Buffer
+		err = json.Indent(&indented, body, "", "  ")
+		if err != nil {
+			reporter.Errorf("Failed to format description of cluster: %v", err)
+			exit.Exit(1)
+		}
+		fmt.Println(indented.String())
+		return
+	}
+
+	// Show what will change, field by field, and ask for confirmation before sending the request:
+	var current bytes.Buffer
+	err = cmv1.MarshalCluster(cluster, &current)
+	if err != nil {
+		reporter.Errorf("Failed to format description of cluster: %v", err)
+		exit.Exit(1)
+	}
+	changes, err := confirm.Diff(current.Bytes(), body)
+	if err != nil {
+		reporter.Errorf("%v", err)
+		exit.Exit(1)
+	}
+	removed, err := confirm.Removed(current.Bytes(), body, "properties")
+	if err != nil {
+		reporter.Errorf("%v", err)
+		exit.Exit(1)
+	}
+	changes = append(changes, removed...)
+	if len(changes) == 0 && !visibilityChanged {
+		reporter.Infof("Cluster '%s' already has the requested configuration", clusterKey)
+		return
+	}
+	confirmed, err := confirm.ConfirmChanges(changes, "update cluster %s", clusterKey)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	if !confirmed {
+		exit.Exit(0)
 	}
 
 	reporter.Debugf("Updating cluster '%s'", clusterKey)
-	err = clusterprovider.UpdateCluster(ocmClient.Clusters(), clusterKey, awsCreator.ARN, clusterConfig)
+	err = clusterprovider.UpdateCluster(ocmConnection, clusterKey, awsCreator.ARN, clusterConfig)
 	if err != nil {
 		reporter.Errorf("Failed to update cluster: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	if visibilityChanged {
+		reporter.Debugf("Updating default ingress of cluster '%s'", clusterKey)
+		err = clusterprovider.UpdateDefaultIngressVisibility(ocmConnection, cluster.ID(), *private)
+		if err != nil {
+			reporter.Errorf("Failed to update default ingress of cluster '%s': %v", clusterKey, err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+	}
+}
+
+// verifyScaling shows the current and requested number of compute nodes together with the
+// additional vCPUs that they need, and checks that the new nodes fit in the AWS quota of vCPUs of
+// the region of the cluster.
+func verifyScaling(reporter *rprtr.Object, logger *logrus.Logger, ocmClient *cmv1.Client,
+	cluster *cmv1.Cluster, computeNodes int) error {
+	scaling, err := quota.GetScaling(ocmClient, quota.Options{
+		MultiAZ:            cluster.MultiAZ(),
+		ComputeNodes:       cluster.Nodes().Compute(),
+		ComputeMachineType: cluster.Nodes().ComputeMachineType().ID(),
+	}, computeNodes)
+	if err != nil {
+		reporter.Warnf("Failed to estimate the vCPUs needed by the compute nodes: %v", err)
+		return nil
+	}
+	reporter.Infof("Compute nodes: %d current, %d requested (estimated change of %+d vCPUs)",
+		cluster.Nodes().Compute(), computeNodes, scaling.AdditionalVCPUs())
+	if scaling.AdditionalVCPUs() <= 0 {
+		return nil
+	}
+
+	region := cluster.Region().ID()
+	awsClient, err := aws.NewClient().
+		Logger(logger).
+		Region(region).
+		Build()
+	if err != nil {
+		return rerrors.Errorf(rerrors.ExitCodeAWSAuth, "Failed to create AWS client: %v", err)
+	}
+	vcpuQuota, err := quota.GetVCPUQuota(awsClient)
+	if err != nil {
+		reporter.Warnf("Failed to get AWS quota of vCPUs in region '%s': %v", region, err)
+		return nil
+	}
+	if scaling.Requested.VCPUs > vcpuQuota {
+		return rerrors.ValidationErrorf("Cluster needs %d vCPUs with %d compute nodes, but the AWS "+
+			"quota of vCPUs in region '%s' is %d", scaling.Requested.VCPUs, computeNodes, region,
+			vcpuQuota)
 	}
+	return nil
 }
 
 func validateExpiration() (expiration time.Time, err error) {
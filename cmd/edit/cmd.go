@@ -22,6 +22,8 @@ import (
 	"github.com/openshift/moactl/cmd/edit/cluster"
 	"github.com/openshift/moactl/cmd/edit/ingress"
 	"github.com/openshift/moactl/cmd/edit/machinepool"
+	"github.com/openshift/moactl/cmd/edit/upgrade"
+	"github.com/openshift/moactl/pkg/config"
 	"github.com/openshift/moactl/pkg/interactive"
 )
 
@@ -33,10 +35,14 @@ var Cmd = &cobra.Command{
 }
 
 func init() {
+	// The subcommands change or delete clusters, so they don't use the saved default cluster:
+	config.MarkExplicitCluster(Cmd)
+
 	flags := Cmd.PersistentFlags()
 	interactive.AddFlag(flags)
 
 	Cmd.AddCommand(cluster.Cmd)
 	Cmd.AddCommand(ingress.Cmd)
 	Cmd.AddCommand(machinepool.Cmd)
+	Cmd.AddCommand(upgrade.Cmd)
 }
@@ -18,7 +18,6 @@ package ingress
 
 import (
 	"fmt"
-	"os"
 	"regexp"
 	"strings"
 
@@ -27,6 +26,8 @@ import (
 
 	"github.com/openshift/moactl/pkg/aws"
 	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/interactive"
 	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm"
@@ -96,7 +97,7 @@ func run(cmd *cobra.Command, argv []string) {
 		reporter.Errorf(
 			"Expected exactly one command line parameter containing the id of the ingress",
 		)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	ingressID := argv[0]
@@ -105,7 +106,7 @@ func run(cmd *cobra.Command, argv []string) {
 			"Ingress  identifier '%s' isn't valid: it must contain only letters or digits",
 			ingressID,
 		)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	// Check that the cluster key (name, identifier or external identifier) given by the user
@@ -117,7 +118,7 @@ func run(cmd *cobra.Command, argv []string) {
 				"must contain only letters, digits, dashes and underscores",
 			clusterKey,
 		)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	labelMatch := args.labelMatch
@@ -131,14 +132,14 @@ func run(cmd *cobra.Command, argv []string) {
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid comma-separated list of attributes: %s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 	}
 	if labelMatch != "" {
 		routeSelectors, err = getRouteSelector(labelMatch)
 		if err != nil {
 			reporter.Errorf("%s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCode(err))
 		}
 	}
 
@@ -153,7 +154,7 @@ func run(cmd *cobra.Command, argv []string) {
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid private value: %s", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 		private = &privArg
 	}
@@ -164,13 +165,13 @@ func run(cmd *cobra.Command, argv []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	awsCreator, err := awsClient.GetCreator()
 	if err != nil {
 		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	// Create the client for the OCM API:
@@ -179,7 +180,7 @@ func run(cmd *cobra.Command, argv []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
 	}
 	defer func() {
 		err = ocmConnection.Close()
@@ -196,7 +197,7 @@ func run(cmd *cobra.Command, argv []string) {
 	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
 	if err != nil {
 		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	// Edit API endpoint instead of ingresses
@@ -205,13 +206,13 @@ func run(cmd *cobra.Command, argv []string) {
 			Private: private,
 		}
 
-		err = clusterprovider.UpdateCluster(clustersCollection, clusterKey, awsCreator.ARN, clusterConfig)
+		err = clusterprovider.UpdateCluster(ocmConnection, clusterKey, awsCreator.ARN, clusterConfig)
 		if err != nil {
 			reporter.Errorf("Failed to update cluster API on cluster '%s': %v", clusterKey, err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCode(err))
 		}
 
-		os.Exit(0)
+		exit.Exit(0)
 	}
 
 	// Try to find the ingress:
@@ -219,7 +220,7 @@ func run(cmd *cobra.Command, argv []string) {
 	ingresses, err := ocm.GetIngresses(clustersCollection, cluster.ID())
 	if err != nil {
 		reporter.Errorf("Failed to get ingresses for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	var ingress *cmv1.Ingress
@@ -236,7 +237,7 @@ func run(cmd *cobra.Command, argv []string) {
 	}
 	if ingress == nil {
 		reporter.Errorf("Failed to get ingress '%s' for cluster '%s'", ingressID, clusterKey)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeNotFound)
 	}
 
 	ingressBuilder := cmv1.NewIngress().ID(ingress.ID())
@@ -258,7 +259,7 @@ func run(cmd *cobra.Command, argv []string) {
 	ingress, err = ingressBuilder.Build()
 	if err != nil {
 		reporter.Errorf("Failed to create ingress for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	reporter.Debugf("Updating ingress '%s' on cluster '%s'", ingress.ID(), clusterKey)
@@ -270,10 +271,10 @@ func run(cmd *cobra.Command, argv []string) {
 		Body(ingress).
 		Send()
 	if err != nil {
-		reporter.Debugf(err.Error())
-		reporter.Errorf("Failed to update ingress '%s' on cluster '%s': %s",
-			ingress.ID(), clusterKey, res.Error().Reason())
-		os.Exit(1)
+		err = rerrors.FromOCM(res.Error(), err)
+		reporter.Errorf("Failed to update ingress '%s' on cluster '%s': %v",
+			ingress.ID(), clusterKey, err)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 }
 
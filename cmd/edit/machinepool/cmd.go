@@ -17,17 +17,21 @@ limitations under the License.
 package machinepool
 
 import (
-	"os"
 	"regexp"
+	"time"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/pkg/aws"
 	c "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/interactive"
 	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/machinepools"
+	"github.com/openshift/moactl/pkg/operations"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
 )
 
@@ -38,6 +42,16 @@ var machinePoolKeyRE = regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
 var args struct {
 	clusterKey string
 	replicas   int
+	labels     string
+	taints     string
+
+	useSpotInstances bool
+	spotMaxPrice     string
+
+	instanceType         string
+	nodeDrainGracePeriod string
+	watch                bool
+	timeout              time.Duration
 }
 
 var Cmd = &cobra.Command{
@@ -46,7 +60,19 @@ var Cmd = &cobra.Command{
 	Short:   "Edit machine pool",
 	Long:    "Edit the additional machine pool from a cluster.",
 	Example: `  # Set 4 replicas on machine pool 'mp1' on cluster 'mycluster'
-  rosa edit machinepool --replicas=4 --cluster=mycluster mp1`,
+  rosa edit machinepool --replicas=4 --cluster=mycluster mp1
+
+  # Replace the labels and taints of machine pool 'mp1' on cluster 'mycluster'
+  rosa edit machinepool --labels=foo=bar --taints=dedicated=gpu:NoSchedule --cluster=mycluster mp1
+
+  # Replace the labels of the default machine pool on cluster 'mycluster'
+  rosa edit machinepool --labels=foo=bar --cluster=mycluster default
+
+  # Use spot instances with a maximum price of 0.5 USD per hour for new nodes of machine pool 'mp1'
+  rosa edit machinepool --use-spot-instances --spot-max-price=0.5 --cluster=mycluster mp1
+
+  # Replace the nodes of the default machine pool on cluster 'mycluster' with m5.2xlarge instances
+  rosa edit machinepool --instance-type=m5.2xlarge --watch --cluster=mycluster default`,
 	Run: run,
 }
 
@@ -66,7 +92,75 @@ func init() {
 		&args.replicas,
 		"replicas",
 		0,
-		"Count of machines for this machine pool (required).",
+		"Count of machines for this machine pool.",
+	)
+
+	flags.StringVar(
+		&args.labels,
+		"labels",
+		"",
+		"Labels for machine pool. Format should be a comma-separated list of 'key=value'. "+
+			"This list will overwrite any modifications made to Node labels on an ongoing basis.",
+	)
+
+	flags.StringVar(
+		&args.taints,
+		"taints",
+		"",
+		"Taints for machine pool. Format should be a comma-separated list of 'key=value:Effect', "+
+			"where the effect is 'NoSchedule', 'PreferNoSchedule' or 'NoExecute'. "+
+			"This list will overwrite any modifications made to Node taints on an ongoing basis. "+
+			"Taints aren't supported on the default machine pool.",
+	)
+
+	flags.BoolVar(
+		&args.useSpotInstances,
+		"use-spot-instances",
+		false,
+		"Use spot instances for the nodes of the machine pool. Only the nodes created after the "+
+			"change are affected. Spot instances aren't supported on the default machine pool.",
+	)
+	flags.StringVar(
+		&args.spotMaxPrice,
+		"spot-max-price",
+		c.SpotMaxPriceOnDemand,
+		"Maximum price in USD per hour of the spot instances, or 'on-demand' to use the on-demand "+
+			"price of the instance type.",
+	)
+
+	flags.StringVar(
+		&args.instanceType,
+		"instance-type",
+		"",
+		"Instance type of the nodes. The instance type of a machine pool can't be changed, so a "+
+			"new machine pool with the same configuration and this instance type is created, and "+
+			"the old machine pool is removed when the new nodes are running. The default machine "+
+			"pool can't be removed, it is scaled down to the minimum number of nodes instead.",
+	)
+
+	flags.StringVar(
+		&args.nodeDrainGracePeriod,
+		"node-drain-grace-period",
+		"",
+		"Grace period for how long Pod Disruption Budget-protected workloads are respected when "+
+			"the nodes of the old machine pool are drained, for example '30 minutes' or '2 hours'. "+
+			"It is also used by the upgrades of the cluster. Only used with '--instance-type'.",
+	)
+
+	flags.BoolVar(
+		&args.watch,
+		"watch",
+		false,
+		"Report the number of running compute nodes while waiting for the machine pool to be "+
+			"replaced. Only used with '--instance-type'.",
+	)
+
+	flags.DurationVar(
+		&args.timeout,
+		"timeout",
+		time.Hour,
+		"Maximum time to wait for the nodes of the new machine pool to be running, and for the "+
+			"nodes of the old machine pool to be removed. Only used with '--instance-type'.",
 	)
 }
 
@@ -79,13 +173,13 @@ func run(cmd *cobra.Command, argv []string) {
 		reporter.Errorf(
 			"Expected exactly one command line parameter containing the id of the machine pool",
 		)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	machinePoolID := argv[0]
 	if !machinePoolKeyRE.MatchString(machinePoolID) {
 		reporter.Errorf("Expected a valid identifier for the machine pool")
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	// Check that the cluster key (name, identifier or external identifier) given by the user
@@ -97,7 +191,7 @@ func run(cmd *cobra.Command, argv []string) {
 				"must contain only letters, digits, dashes and underscores",
 			clusterKey,
 		)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	// Create the AWS client:
@@ -107,13 +201,13 @@ func run(cmd *cobra.Command, argv []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	awsCreator, err := awsClient.GetCreator()
 	if err != nil {
 		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	// Create the client for the OCM API:
@@ -122,7 +216,7 @@ func run(cmd *cobra.Command, argv []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
 	}
 	defer func() {
 		err = ocmConnection.Close()
@@ -139,34 +233,94 @@ func run(cmd *cobra.Command, argv []string) {
 	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
 	if err != nil {
 		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
+	if cmd.Flags().Changed("instance-type") {
+		err = replaceMachinePool(cmd, reporter, ocmConnection, cluster, machinePoolID, awsCreator.ARN)
+		if err != nil {
+			reporter.Errorf("Failed to replace machine pool '%s' on cluster '%s': %s",
+				machinePoolID, clusterKey, err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		return
+	}
+	for _, flag := range []string{"node-drain-grace-period", "watch", "timeout"} {
+		if cmd.Flags().Changed(flag) {
+			reporter.Errorf("Option '--%s' can only be used with '--instance-type'", flag)
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+	}
+
+	// Validate the labels and taints before doing any change:
+	var labelMap map[string]string
+	if cmd.Flags().Changed("labels") {
+		labelMap, err = machinepools.ParseLabels(args.labels)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+	}
+	var taintBuilders []*cmv1.TaintBuilder
+	if cmd.Flags().Changed("taints") {
+		taintBuilders, err = machinepools.ParseTaints(args.taints)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+	}
+
+	var spot *c.Spot
+	if cmd.Flags().Changed("use-spot-instances") || cmd.Flags().Changed("spot-max-price") {
+		spot, err = c.ValidateSpot(machinePoolID, args.useSpotInstances, args.spotMaxPrice)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+	}
+
+	// Only ask for the number of replicas when nothing else is changed:
+	askReplicas := interactive.Enabled() || cmd.Flags().Changed("replicas") ||
+		(labelMap == nil && taintBuilders == nil && spot == nil)
+
 	var replicas int
 
 	// Editing the default machine pool is a different process
-	if machinePoolID == "default" {
-		replicas, err = getReplicas(cmd)
-		if err != nil {
-			reporter.Errorf("Expected a valid number of replicas: %s", err)
-			os.Exit(1)
+	if machinePoolID == c.DefaultMachinePool {
+		if spot != nil {
+			reporter.Errorf("Spot instances aren't supported on the default machine pool")
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
-		if replicas < 2 {
-			reporter.Errorf("Default machine pool requires at least 2 compute nodes")
-			os.Exit(1)
+		if taintBuilders != nil {
+			reporter.Errorf("Taints aren't supported on the default machine pool")
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+		if askReplicas {
+			replicas, err = getReplicas(cmd)
+			if err != nil {
+				reporter.Errorf("Expected a valid number of replicas: %s", err)
+				exit.Exit(rerrors.ExitCodeValidation)
+			}
+			if replicas < 2 {
+				reporter.Errorf("Default machine pool requires at least 2 compute nodes")
+				exit.Exit(rerrors.ExitCodeValidation)
+			}
 		}
 
-		clusterConfig := c.Spec{ComputeNodes: replicas}
+		clusterConfig := c.Spec{
+			ComputeNodes:  replicas,
+			ComputeLabels: labelMap,
+		}
 
 		reporter.Debugf("Updating machine pool '%s' on cluster '%s'", machinePoolID, clusterKey)
-		err = c.UpdateCluster(clustersCollection, clusterKey, awsCreator.ARN, clusterConfig)
+		err = c.UpdateCluster(ocmConnection, clusterKey, awsCreator.ARN, clusterConfig)
 		if err != nil {
 			reporter.Errorf("Failed to update machine pool '%s' on cluster '%s': %s",
 				machinePoolID, clusterKey, err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCode(err))
 		}
 
-		os.Exit(0)
+		exit.Exit(0)
 	}
 
 	// Try to find the machine pool:
@@ -174,7 +328,7 @@ func run(cmd *cobra.Command, argv []string) {
 	machinePools, err := ocm.GetMachinePools(clustersCollection, cluster.ID())
 	if err != nil {
 		reporter.Errorf("Failed to get machine pools for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	var machinePool *cmv1.MachinePool
@@ -185,38 +339,45 @@ func run(cmd *cobra.Command, argv []string) {
 	}
 	if machinePool == nil {
 		reporter.Errorf("Failed to get machine pool '%s' for cluster '%s'", machinePoolID, clusterKey)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeNotFound)
 	}
 
-	replicas, err = getReplicas(cmd)
-	if err != nil {
-		reporter.Errorf("Expected a valid number of replicas: %s", err)
-		os.Exit(1)
+	machinePoolBuilder := cmv1.NewMachinePool().
+		ID(machinePool.ID())
+	if askReplicas {
+		replicas, err = getReplicas(cmd)
+		if err != nil {
+			reporter.Errorf("Expected a valid number of replicas: %s", err)
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+		machinePoolBuilder = machinePoolBuilder.Replicas(replicas)
+	}
+	if labelMap != nil {
+		machinePoolBuilder = machinePoolBuilder.Labels(labelMap)
+	}
+	if taintBuilders != nil {
+		machinePoolBuilder = machinePoolBuilder.Taints(taintBuilders...)
 	}
 
-	machinePool, err = cmv1.NewMachinePool().
-		ID(machinePool.ID()).
-		Replicas(replicas).
-		Build()
+	machinePool, err = machinePoolBuilder.Build()
 	if err != nil {
 		reporter.Errorf("Failed to create machine pool for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	reporter.Debugf("Updating machine pool '%s' on cluster '%s'", machinePool.ID(), clusterKey)
-	res, err := clustersCollection.
-		Cluster(cluster.ID()).
-		MachinePools().
-		MachinePool(machinePool.ID()).
-		Update().
-		Body(machinePool).
-		Send()
+	err = c.UpdateMachinePool(ocmConnection, cluster.ID(), machinePool, spot)
 	if err != nil {
-		reporter.Debugf(err.Error())
 		reporter.Errorf("Failed to update machine pool '%s' on cluster '%s': %s",
-			machinePool.ID(), clusterKey, res.Error().Reason())
-		os.Exit(1)
+			machinePool.ID(), clusterKey, err)
+		exit.Exit(rerrors.ExitCode(err))
 	}
+	operations.Record(reporter, &operations.Operation{
+		Type:        operations.EditMachinePool,
+		ClusterID:   cluster.ID(),
+		ClusterName: cluster.Name(),
+		Resource:    machinePool.ID(),
+	})
 }
 
 func getReplicas(cmd *cobra.Command) (int, error) {
@@ -228,6 +389,7 @@ func getReplicas(cmd *cobra.Command) (int, error) {
 			Help:     cmd.Flags().Lookup("replicas").Usage,
 			Default:  replicas,
 			Required: true,
+			Flag:     "replicas",
 		})
 	}
 	return replicas, nil
@@ -0,0 +1,209 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinepool
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/machines"
+	"github.com/openshift/moactl/pkg/ocm/upgrades"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+// replacementPollInterval is the time between checks of the compute nodes of the cluster while
+// a machine pool is replaced.
+const replacementPollInterval = 30 * time.Second
+
+// replaceMachinePool changes the instance type of the machine pool. As OCM can't change the
+// instance type of existing machine pools, a new machine pool with the same configuration and the
+// new instance type is created, and when its nodes are running the old machine pool is removed.
+// The default machine pool can't be removed, so it is scaled down to the minimum number of nodes
+// instead.
+func replaceMachinePool(cmd *cobra.Command, reporter *rprtr.Object, connection *sdk.Connection,
+	cluster *cmv1.Cluster, machinePoolID string, creatorARN string) error {
+	for _, flag := range []string{"replicas", "labels", "taints", "use-spot-instances",
+		"spot-max-price"} {
+		if cmd.Flags().Changed(flag) {
+			return rerrors.ValidationErrorf("Option '--instance-type' can't be combined with "+
+				"'--%s', change the machine pool after it is replaced", flag)
+		}
+	}
+	if cluster.State() != cmv1.ClusterStateReady {
+		return rerrors.ConflictErrorf("Cluster '%s' is in state '%s', machine pools can only be "+
+			"replaced when the cluster is ready", cluster.Name(), cluster.State())
+	}
+
+	var nodeDrainGracePeriod *float64
+	if cmd.Flags().Changed("node-drain-grace-period") {
+		minutes, err := upgrades.ParseNodeDrainGracePeriod(args.nodeDrainGracePeriod)
+		if err != nil {
+			return err
+		}
+		nodeDrainGracePeriod = &minutes
+	}
+
+	ocmClient := connection.ClustersMgmt().V1()
+	instanceTypeList, err := machines.GetMachineTypeList(ocmClient)
+	if err != nil {
+		return err
+	}
+	instanceType, err := machines.ValidateMachineType(args.instanceType, instanceTypeList)
+	if err != nil {
+		return rerrors.Wrap(rerrors.ExitCodeValidation, err)
+	}
+
+	machinePools, err := ocm.GetMachinePools(ocmClient.Clusters(), cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get machine pools for cluster '%s': %w", cluster.Name(), err)
+	}
+	var oldMachinePool *cmv1.MachinePool
+	if machinePoolID != c.DefaultMachinePool {
+		for _, item := range machinePools {
+			if item.ID() == machinePoolID {
+				oldMachinePool = item
+			}
+		}
+		if oldMachinePool == nil {
+			return rerrors.NotFoundErrorf("Failed to get machine pool '%s' for cluster '%s'",
+				machinePoolID, cluster.Name())
+		}
+	}
+	newMachinePool, err := c.NewReplacementMachinePool(cluster, oldMachinePool, instanceType)
+	if err != nil {
+		return err
+	}
+	for _, item := range machinePools {
+		if item.ID() == newMachinePool.ID() {
+			return rerrors.ConflictErrorf("Machine pool '%s' already exists, delete it or finish "+
+				"the previous replacement before replacing machine pool '%s'", item.ID(),
+				machinePoolID)
+		}
+	}
+
+	// Number of nodes removed from the old machine pool once the new one is running:
+	removedNodes := cluster.Nodes().Compute() - c.MinComputeNodes(cluster)
+	if oldMachinePool != nil {
+		removedNodes = c.MinMachinePoolReplicas(oldMachinePool)
+	}
+	if removedNodes < 0 {
+		removedNodes = 0
+	}
+
+	confirmed, err := confirm.Confirm("replace machine pool '%s' of cluster '%s' with machine "+
+		"pool '%s' using instance type '%s'", machinePoolID, cluster.Name(), newMachinePool.ID(),
+		instanceType)
+	if err != nil {
+		return rerrors.Wrap(rerrors.ExitCodeValidation, err)
+	}
+	if !confirmed {
+		return nil
+	}
+
+	ctx, cancel := runner.WithInterrupt(context.Background())
+	defer cancel()
+
+	currentNodes, err := c.GetCurrentComputeNodes(ctx, connection, cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get compute nodes of cluster '%s': %w", cluster.Name(), err)
+	}
+	var progress func(current int)
+	if args.watch {
+		progress = func(current int) {
+			reporter.Infof("Cluster '%s' has %d running compute nodes", cluster.Name(), current)
+		}
+	}
+
+	// Create the new machine pool and wait until its nodes are running, so that the workloads
+	// can be moved to them:
+	reporter.Debugf("Creating machine pool '%s' on cluster '%s'", newMachinePool.ID(), cluster.ID())
+	err = c.AddMachinePool(connection, cluster.ID(), newMachinePool, "", nil)
+	if err != nil {
+		return fmt.Errorf("Failed to create machine pool '%s' on cluster '%s': %w",
+			newMachinePool.ID(), cluster.Name(), err)
+	}
+	reporter.Infof("Machine pool '%s' has been created, waiting for its nodes to be running",
+		newMachinePool.ID())
+	addedNodes := c.MinMachinePoolReplicas(newMachinePool)
+	err = c.WaitForComputeNodes(ctx, connection, cluster.ID(), currentNodes,
+		currentNodes+addedNodes, progress, replacementPollInterval, args.timeout)
+	if err != nil {
+		return fmt.Errorf("%w. Machine pool '%s' wasn't removed, run this command again with "+
+			"'--instance-type' to retry or delete machine pool '%s'", err, machinePoolID,
+			newMachinePool.ID())
+	}
+
+	// Workloads protected by pod disruption budgets are evicted from the nodes of the old machine
+	// pool after the grace period of the cluster:
+	if nodeDrainGracePeriod != nil {
+		reporter.Debugf("Updating node drain grace period of cluster '%s'", cluster.ID())
+		err = c.UpdateCluster(connection, cluster.ID(), creatorARN, c.Spec{
+			NodeDrainGracePeriod: nodeDrainGracePeriod,
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to update node drain grace period of cluster '%s': %w",
+				cluster.Name(), err)
+		}
+	}
+
+	if oldMachinePool != nil {
+		reporter.Debugf("Deleting machine pool '%s' on cluster '%s'", machinePoolID, cluster.ID())
+		response, err := ocmClient.Clusters().
+			Cluster(cluster.ID()).
+			MachinePools().
+			MachinePool(machinePoolID).
+			Delete().
+			Send()
+		if err != nil {
+			return fmt.Errorf("Failed to delete machine pool '%s' on cluster '%s': %w",
+				machinePoolID, cluster.Name(), rerrors.FromOCM(response.Error(), err))
+		}
+		reporter.Infof("Machine pool '%s' is being deleted", machinePoolID)
+	} else if removedNodes > 0 {
+		reporter.Debugf("Scaling down default machine pool on cluster '%s'", cluster.ID())
+		err = c.UpdateCluster(connection, cluster.ID(), creatorARN, c.Spec{
+			ComputeNodes: c.MinComputeNodes(cluster),
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to scale down default machine pool on cluster '%s': %w",
+				cluster.Name(), err)
+		}
+		reporter.Infof("The default machine pool can't be removed, it is being scaled down to %d "+
+			"nodes", c.MinComputeNodes(cluster))
+	}
+
+	if removedNodes > 0 {
+		err = c.WaitForComputeNodes(ctx, connection, cluster.ID(), currentNodes+addedNodes,
+			currentNodes+addedNodes-removedNodes, progress, replacementPollInterval, args.timeout)
+		if err != nil {
+			return err
+		}
+	}
+	reporter.Infof("Machine pool '%s' has been replaced by machine pool '%s'", machinePoolID,
+		newMachinePool.ID())
+	return nil
+}
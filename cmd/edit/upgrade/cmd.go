@@ -0,0 +1,393 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/moactl"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/upgrades"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+// Upgrade policies can only be changed before the upgrade starts:
+var editableStates = map[string]bool{
+	"pending":   true,
+	"scheduled": true,
+}
+
+var args struct {
+	clusterKey           string
+	version              string
+	scheduleDate         string
+	scheduleTime         string
+	scheduleTimezone     string
+	nodeDrainGracePeriod string
+	allowVersionGateAck  bool
+}
+
+var Cmd = &cobra.Command{
+	Use:     "upgrade",
+	Aliases: []string{"upgrades"},
+	Short:   "Edit the scheduled upgrade of a cluster",
+	Long: "Edit the version, schedule or node drain grace period of the scheduled upgrade of a " +
+		"cluster, before the upgrade starts.",
+	Example: `  # Interactively edit the scheduled upgrade of the cluster named "mycluster"
+  rosa edit upgrade --cluster=mycluster --interactive
+
+  # Move the scheduled upgrade of a cluster to a different time
+  rosa edit upgrade -c mycluster --schedule-date=2020-12-01 --schedule-time=02:00
+
+  # Move the scheduled upgrade of a cluster to 2:00 in the time zone of Paris
+  rosa edit upgrade -c mycluster --schedule-time=02:00 --schedule-timezone=Europe/Paris`,
+	Run: runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to edit the scheduled upgrade of (required)",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	flags.StringVar(
+		&args.version,
+		"version",
+		"",
+		"Version of OpenShift that the cluster will be upgraded to",
+	)
+
+	flags.StringVar(
+		&args.scheduleDate,
+		"schedule-date",
+		"",
+		"Next date the upgrade should run at the specified time. Format should be 'yyyy-mm-dd'",
+	)
+
+	flags.StringVar(
+		&args.scheduleTime,
+		"schedule-time",
+		"",
+		"Next time the upgrade should run on the specified date. Format should be 'HH:mm'",
+	)
+
+	flags.StringVar(
+		&args.scheduleTimezone,
+		"schedule-timezone",
+		"",
+		"IANA name of the time zone of '--schedule-date' and '--schedule-time', for example "+
+			"'Europe/Paris' or 'America/New_York'. Defaults to UTC. The time is converted to UTC "+
+			"before the upgrade is updated.",
+	)
+
+	flags.StringVar(
+		&args.nodeDrainGracePeriod,
+		"node-drain-grace-period",
+		"",
+		"You may set a grace period for how long Pod Disruption Budget-protected workloads will be "+
+			"respected during upgrades, for example '30 minutes', '2 hours', '90m' or a number of "+
+			"minutes like '90', up to one week.\nAfter this grace period, any workloads protected by "+
+			"Pod Disruption Budgets that have not been successfully drained from a node will be "+
+			"forcibly evicted",
+	)
+
+	flags.BoolVar(
+		&args.allowVersionGateAck,
+		"allow-version-gate-acknowledgement",
+		false,
+		"Acknowledge any version gates required to upgrade to the selected version without prompting.",
+	)
+}
+
+// validate checks the flags that don't need any client, so that mistakes are reported before
+// connecting to AWS and OCM.
+func validate(r *runner.Runtime) error {
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := args.clusterKey
+	if !ocm.IsValidClusterKey(clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+	}
+	_, err := upgrades.LoadTimezone(args.scheduleTimezone)
+	return err
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	flags := r.Cmd.Flags()
+	clusterKey := args.clusterKey
+
+	isInteractive := interactive.Enabled()
+	if !isInteractive {
+		changedFlags := false
+		for _, flag := range []string{"version", "schedule-date", "schedule-time",
+			"node-drain-grace-period"} {
+			if flags.Changed(flag) {
+				changedFlags = true
+			}
+		}
+		if !changedFlags {
+			isInteractive = true
+		}
+	}
+
+	client, err := moactl.NewClient().
+		Logger(r.Logger).
+		Connection(r.OCMConnection).
+		CreatorARN(r.Creator.ARN).
+		Build()
+	if err != nil {
+		return err
+	}
+	ocmClient := r.OCMConnection.ClustersMgmt().V1()
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := client.GetCluster(ctx, clusterKey)
+	if err != nil {
+		return err
+	}
+
+	if cluster.State() != cmv1.ClusterStateReady {
+		return rerrors.ConflictErrorf("Cluster '%s' is not yet ready", clusterKey)
+	}
+
+	scheduledUpgrade, err := upgrades.GetScheduledUpgrade(ocmClient, cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get scheduled upgrades for cluster '%s': %w", clusterKey, err)
+	}
+	if scheduledUpgrade == nil {
+		return rerrors.NotFoundErrorf("There is no scheduled upgrade for cluster '%s', use "+
+			"'rosa upgrade cluster' to schedule one", clusterKey)
+	}
+
+	state, err := upgrades.GetUpgradePolicyState(ocmClient, cluster.ID(), scheduledUpgrade.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get state of upgrade for cluster '%s': %w", clusterKey, err)
+	}
+	if !editableStates[state.Value()] {
+		return rerrors.ConflictErrorf("The upgrade of cluster '%s' is '%s' and can no longer be "+
+			"edited", clusterKey, state.Value())
+	}
+
+	upgradePolicyBuilder := cmv1.NewUpgradePolicy()
+	changes := []confirm.Change{}
+
+	// Version:
+	version := scheduledUpgrade.Version()
+	if flags.Changed("version") {
+		version = args.version
+	}
+	if isInteractive || version != scheduledUpgrade.Version() {
+		availableUpgrades, err := client.AvailableUpgrades(ctx, cluster, "")
+		if err != nil {
+			return err
+		}
+		if len(availableUpgrades) == 0 {
+			return fmt.Errorf("There are no available upgrades")
+		}
+		if isInteractive {
+			version, err = upgrades.PromptVersion(version, availableUpgrades,
+				flags.Lookup("version").Usage)
+			if err != nil {
+				return err
+			}
+		}
+		err = upgrades.ValidateVersion(version, availableUpgrades)
+		if err != nil {
+			return err
+		}
+	}
+	var gates []*upgrades.VersionGate
+	if version != scheduledUpgrade.Version() {
+		gates, err = upgrades.CheckVersionGates(r.OCMConnection, cluster, version,
+			args.allowVersionGateAck)
+		if err != nil {
+			return err
+		}
+		upgradePolicyBuilder.Version(version)
+		changes = append(changes, confirm.Change{
+			Field:  "version",
+			Before: scheduledUpgrade.Version(),
+			After:  version,
+		})
+	}
+
+	// Schedule, using the current schedule for the values that weren't given, in the time zone
+	// selected by the user:
+	location, err := upgrades.LoadTimezone(args.scheduleTimezone)
+	if err != nil {
+		return err
+	}
+	if isInteractive {
+		timezone, err := upgrades.PromptTimezone(args.scheduleTimezone,
+			flags.Lookup("schedule-timezone").Usage)
+		if err != nil {
+			return err
+		}
+		location, err = upgrades.LoadTimezone(timezone)
+		if err != nil {
+			return err
+		}
+	}
+	currentRun := scheduledUpgrade.NextRun().UTC()
+	scheduleDate := currentRun.In(location).Format("2006-01-02")
+	if flags.Changed("schedule-date") {
+		scheduleDate = args.scheduleDate
+	}
+	scheduleTime := currentRun.In(location).Format("15:04")
+	if flags.Changed("schedule-time") {
+		scheduleTime = args.scheduleTime
+	}
+	if isInteractive {
+		scheduleDate, scheduleTime, err = upgrades.PromptSchedule(scheduleDate, scheduleTime,
+			location)
+		if err != nil {
+			return err
+		}
+	}
+	nextRun, err := upgrades.ParseScheduleInLocation(scheduleDate, scheduleTime, location)
+	if err != nil {
+		return err
+	}
+	if !nextRun.Equal(currentRun) {
+		err = upgrades.ValidateNextRun(nextRun, time.Now())
+		if err != nil {
+			return err
+		}
+		upgradePolicyBuilder.NextRun(nextRun)
+		changes = append(changes, confirm.Change{
+			Field:  "next_run",
+			Before: upgrades.FormatSchedule(currentRun, location),
+			After:  upgrades.FormatSchedule(nextRun, location),
+		})
+	}
+
+	// Node drain grace period:
+	currentNodeDrain := upgrades.FormatNodeDrainGracePeriod(cluster.NodeDrainGracePeriod())
+	nodeDrainGracePeriod := currentNodeDrain
+	if flags.Changed("node-drain-grace-period") {
+		nodeDrainGracePeriod = args.nodeDrainGracePeriod
+	}
+	if isInteractive {
+		nodeDrainGracePeriod, err = upgrades.PromptNodeDrainGracePeriod(nodeDrainGracePeriod,
+			flags.Lookup("node-drain-grace-period").Usage)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Parse the node drain grace period before anything is changed, so that an invalid value
+	// doesn't leave the upgrade half edited:
+	var clusterSpec *cmv1.Cluster
+	if nodeDrainGracePeriod != "" && nodeDrainGracePeriod != currentNodeDrain {
+		nodeDrainValue, err := upgrades.ParseNodeDrainGracePeriod(nodeDrainGracePeriod)
+		if err != nil {
+			return err
+		}
+		clusterSpec, err = upgrades.NodeDrainGracePeriodSpec(nodeDrainValue)
+		if err != nil {
+			return fmt.Errorf("Failed to update cluster '%s': %w", clusterKey, err)
+		}
+		fields, err := clusterChanges(cluster, clusterSpec)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, fields...)
+	}
+
+	if len(changes) == 0 {
+		reporter.Infof("No changes to the scheduled upgrade of cluster '%s'", clusterKey)
+		return nil
+	}
+
+	// Show what will change, field by field, and ask for confirmation before sending the requests:
+	confirmed, err := confirm.ConfirmChanges(changes, "edit the scheduled upgrade of cluster %s",
+		clusterKey)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	if version != scheduledUpgrade.Version() || !nextRun.Equal(currentRun) {
+		upgradePolicy, err := upgradePolicyBuilder.Build()
+		if err != nil {
+			return fmt.Errorf("Failed to edit upgrade for cluster '%s': %w", clusterKey, err)
+		}
+		err = upgrades.AckVersionGates(r.OCMConnection, cluster.ID(), gates)
+		if err != nil {
+			return err
+		}
+		reporter.Debugf("Updating upgrade '%s' for cluster '%s'", scheduledUpgrade.ID(), clusterKey)
+		err = upgrades.UpdateUpgradePolicy(ocmClient, cluster.ID(), scheduledUpgrade.ID(),
+			upgradePolicy)
+		if err != nil {
+			return fmt.Errorf("Failed to edit upgrade for cluster '%s': %w", clusterKey, err)
+		}
+	}
+
+	if clusterSpec != nil {
+		_, err = ocmClient.Clusters().
+			Cluster(cluster.ID()).
+			Update().
+			Body(clusterSpec).
+			SendContext(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed to update cluster '%s': %w", clusterKey, err)
+		}
+	}
+
+	reporter.Infof("Updated scheduled upgrade for cluster '%s', it will run on %s", clusterKey,
+		upgrades.FormatSchedule(nextRun, location))
+	return nil
+}
+
+// clusterChanges returns the fields of the cluster that the given update changes.
+func clusterChanges(cluster *cmv1.Cluster, clusterSpec *cmv1.Cluster) ([]confirm.Change, error) {
+	var current, patch bytes.Buffer
+	err := cmv1.MarshalCluster(cluster, &current)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to format description of cluster: %v", err)
+	}
+	err = cmv1.MarshalCluster(clusterSpec, &patch)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to format description of cluster: %v", err)
+	}
+	return confirm.Diff(current.Bytes(), patch.Bytes())
+}
@@ -17,38 +17,50 @@ limitations under the License.
 package user
 
 import (
-	"fmt"
-	"os"
+	"context"
 
-	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/aws"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/moactl"
 	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/users"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runner"
 )
 
 var args struct {
 	clusterKey string
-	username   string
+	usernames  []string
+	usersFile  string
+	groupFile  string
 }
 
 var Cmd = &cobra.Command{
 	Use:     "user ROLE [flags]",
 	Aliases: []string{"role"},
 	Short:   "Grant user access to cluster",
-	Long:    "Grant user access to cluster under a specific role",
+	Long:    "Grant user access to cluster under a specific role, which can be any group of the cluster",
 	Example: `  # Add cluster-admin role to a user
   rosa grant user cluster-admin --user=myusername --cluster=mycluster
 
   # Grant dedicated-admins role to a user
-  rosa grant user dedicated-admin --user=myusername --cluster=mycluster`,
-	Run: run,
-}
+  rosa grant user dedicated-admin --user=myusername --cluster=mycluster
+
+  # Grant dedicated-admins role to several users
+  rosa grant user dedicated-admin --user=user1,user2,user3 --cluster=mycluster
 
-var validRoles = []string{"cluster-admins", "dedicated-admins"}
-var validRolesAliases = []string{"cluster-admin", "dedicated-admin"}
+  # Grant dedicated-admins role to all the users listed in a file, one per line
+  rosa grant user dedicated-admin --users-file=users.txt --cluster=mycluster
+
+  # Grant cluster-admins role to the members of an OpenShift group exported from another cluster
+  oc get group admins -o yaml > group.yaml
+  rosa grant user cluster-admins --from-file=group.yaml --cluster=mycluster`,
+	Run:               run,
+	ValidArgsFunction: runner.Complete(completeRole, runner.WithAWS(), runner.WithOCM()),
+}
 
 func init() {
 	flags := Cmd.Flags()
@@ -62,39 +74,54 @@ func init() {
 	)
 	Cmd.MarkFlagRequired("cluster")
 
-	flags.StringVarP(
-		&args.username,
+	flags.StringSliceVarP(
+		&args.usernames,
 		"user",
 		"u",
+		nil,
+		"Username to grant the role to. Multiple users can be comma separated, for example: "+
+			"--user=user1,user2.",
+	)
+
+	flags.StringVar(
+		&args.usersFile,
+		"users-file",
+		"",
+		"Path to a file containing the usernames to grant the role to, one per line.",
+	)
+
+	flags.StringVar(
+		&args.groupFile,
+		"from-file",
 		"",
-		"Username to grant the role to (required).",
+		"Path to a YAML file containing an OpenShift Group manifest, or a list of them, whose "+
+			"users will be granted the role.",
 	)
-	Cmd.MarkFlagRequired("user")
 }
 
 func run(_ *cobra.Command, argv []string) {
 	reporter := rprtr.CreateReporterOrExit()
 	logger := logging.CreateLoggerOrExit(reporter)
 
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
 	clusterKey := args.clusterKey
-	if !ocm.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
-	}
 
-	username := args.username
-	if !ocm.IsValidUsername(username) {
-		reporter.Errorf(
-			"username '%s' isn't valid: it must contain only letters, digits, dashes and underscores",
-			username,
-		)
-		os.Exit(1)
+	usernames := append([]string{}, args.usernames...)
+	if args.groupFile != "" {
+		members, err := users.ReadGroupFile(args.groupFile)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+		usernames = append(usernames, members...)
+	}
+	usernames, err := users.GetUsernames(usernames, args.usersFile)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	if len(usernames) == 0 {
+		reporter.Errorf("Expected at least one user in the '--user', '--users-file' or '--from-file' flags")
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	if len(argv) != 1 {
@@ -102,84 +129,62 @@ func run(_ *cobra.Command, argv []string) {
 			"Expected exactly one command line argument or flag containing the name " +
 				"of the group or role to grant the user.",
 		)
-		os.Exit(1)
-	}
-	role := argv[0]
-	// Allow role aliases
-	for _, validAlias := range validRolesAliases {
-		if role == validAlias {
-			role = fmt.Sprintf("%ss", role)
-		}
-	}
-	isRoleValid := false
-	// Determine if role is valid
-	for _, validRole := range validRoles {
-		if role == validRole {
-			isRoleValid = true
-		}
-	}
-	if !isRoleValid {
-		reporter.Errorf("Expected at least one of %s", validRoles)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
+	client, err := moactl.NewClient().
 		Logger(logger).
 		Build()
 	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
-	}
-
-	awsCreator, err := awsClient.GetCreator()
-	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
-	}
-
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		reporter.Errorf("%v", err)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 	defer func() {
-		err = ocmConnection.Close()
+		err = client.Close()
 		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
+			reporter.Errorf("%v", err)
 		}
 	}()
 
-	// Get the client for the OCM collection of clusters:
-	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
-
-	// Try to find the cluster:
-	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
+	reporter.Debugf("Adding users to group '%s' in cluster '%s'", argv[0], clusterKey)
+	output, err := client.GrantRole(context.Background(), moactl.UserRoleInput{
+		ClusterKey: clusterKey,
+		Role:       argv[0],
+		Usernames:  usernames,
+	})
 	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		reporter.Errorf("%v", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	role := output.Group
+	for _, result := range output.Results {
+		if result.Err != nil {
+			reporter.Errorf("Failed to grant '%s' to user '%s' in cluster '%s': %v",
+				role, result.Username, clusterKey, result.Err)
+			continue
+		}
+		reporter.Infof("Granted role '%s' to user '%s' on cluster '%s'", role, result.Username, clusterKey)
 	}
 
-	if cluster.State() != cmv1.ClusterStateReady {
-		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
-		os.Exit(1)
+	if failed := output.Failed(); failed > 0 {
+		reporter.Errorf("Failed to grant role '%s' to %d out of %d users", role, failed, len(usernames))
+		exit.Exit(rerrors.ExitCodeGeneric)
 	}
+}
 
-	user, err := cmv1.NewUser().ID(username).Build()
+// completeRole returns the names of the groups of the cluster given in the '--cluster' flag.
+func completeRole(_ context.Context, r *runner.Runtime) ([]string, error) {
+	if len(r.Args) > 0 || args.clusterKey == "" {
+		return nil, nil
+	}
+	clustersCollection := r.OCMConnection.ClustersMgmt().V1().Clusters()
+	cluster, err := ocm.GetCluster(clustersCollection, args.clusterKey, r.Creator.ARN)
 	if err != nil {
-		reporter.Errorf("Failed to create user '%s' for cluster '%s'", username, clusterKey)
-		os.Exit(1)
+		return nil, err
 	}
-
-	reporter.Debugf("Adding user '%s' to group '%s' in cluster '%s'", username, role, clusterKey)
-	res, err := clustersCollection.Cluster(cluster.ID()).Groups().Group(role).Users().Add().Body(user).Send()
+	groups, err := ocm.GetGroups(clustersCollection, cluster.ID())
 	if err != nil {
-		reporter.Debugf(err.Error())
-		reporter.Errorf("Failed to grant '%s' to user '%s' to cluster '%s': %s",
-			role, username, clusterKey, res.Error().Reason())
-		os.Exit(1)
+		return nil, err
 	}
+	return users.GroupIDs(groups), nil
 }
@@ -0,0 +1,157 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/upgrades"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	clusterKey string
+	watch      bool
+	timeout    time.Duration
+}
+
+var Cmd = &cobra.Command{
+	Use:   "cluster [ID|NAME]",
+	Short: "Hibernate cluster",
+	Long: "Hibernate a cluster, shutting down its nodes to reduce costs while it isn't used. " +
+		"Hibernating clusters can be resumed with 'rosa resume cluster'.",
+	Example: `  # Hibernate a cluster named "mycluster"
+  rosa hibernate cluster mycluster
+
+  # Hibernate a cluster and wait until it is hibernating
+  rosa hibernate cluster --cluster=mycluster --watch`,
+	Run: runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to hibernate.",
+	)
+
+	flags.BoolVar(
+		&args.watch,
+		"watch",
+		false,
+		"Wait until the cluster is hibernating.",
+	)
+
+	flags.DurationVar(
+		&args.timeout,
+		"timeout",
+		30*time.Minute,
+		"Maximum time to wait for the cluster to be hibernating when using '--watch'.",
+	)
+}
+
+// validate checks the command line arguments and keeps the key of the cluster.
+func validate(r *runner.Runtime) error {
+	clusterKey := args.clusterKey
+	if clusterKey == "" {
+		if len(r.Args) != 1 {
+			return rerrors.ValidationErrorf(
+				"Expected exactly one command line argument or flag containing the name " +
+					"or identifier of the cluster",
+			)
+		}
+		clusterKey = r.Args[0]
+	}
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	if !c.IsValidClusterKey(clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+	}
+	args.clusterKey = clusterKey
+	return nil
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	clusterKey := args.clusterKey
+
+	// Get the client for the OCM collection of clusters:
+	ocmClient := r.OCMConnection.ClustersMgmt().V1()
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(ocmClient.Clusters(), clusterKey, r.Creator.ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	// Upgrades can't run while the cluster is hibernating:
+	scheduledUpgrade, err := upgrades.GetScheduledUpgrade(ocmClient, cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get scheduled upgrades for cluster '%s': %w", clusterKey, err)
+	}
+	err = c.ValidateHibernation(cluster, scheduledUpgrade)
+	if err != nil {
+		return err
+	}
+
+	confirmed, err := confirm.Confirm("hibernate cluster %s", clusterKey)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	reporter.Debugf("Hibernating cluster '%s'", clusterKey)
+	err = c.HibernateCluster(r.OCMConnection, cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to hibernate cluster '%s': %w", clusterKey, err)
+	}
+
+	if !args.watch {
+		reporter.Infof("Cluster '%s' is powering down. To check its state run "+
+			"'rosa describe cluster -c %s'.", clusterKey, clusterKey)
+		return nil
+	}
+
+	reporter.Infof("Waiting for cluster '%s' to be hibernating...", clusterKey)
+	err = c.WaitForState(ctx, ocmClient.Clusters(), cluster.ID(), c.ClusterStateHibernating,
+		30*time.Second, args.timeout)
+	if err != nil {
+		return err
+	}
+	reporter.Infof("Cluster '%s' is hibernating", clusterKey)
+	return nil
+}
@@ -0,0 +1,172 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the implementation of the '--audit' option, which looks for the AWS
+// resources created by rosa that are no longer used by any cluster.
+
+package initialize
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mode"
+	"github.com/openshift/moactl/pkg/aws/tags"
+	"github.com/openshift/moactl/pkg/confirm"
+	"github.com/openshift/moactl/pkg/ocm"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+)
+
+// runAudit reports the resources left in the AWS account by clusters that no longer exist, and
+// offers to delete them, or prints the commands that delete them in manual mode.
+func runAudit(reporter *rprtr.Object, client aws.Client, clustersCollection *cmv1.ClustersClient) (
+	int, error) {
+	creator, err := client.GetCreator()
+	if err != nil {
+		return 0, fmt.Errorf("Failed to get AWS creator: %v", err)
+	}
+	hasClusters, err := ocm.HasClusters(clustersCollection, creator.ARN)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to check for clusters: %v", err)
+	}
+	found := 0
+
+	// The stack that creates the administrator user is left over when it failed, or when it isn't
+	// used by any cluster:
+	reporter.Infof("Checking CloudFormation stack '%s'...", aws.OsdCcsAdminStackName)
+	_, stackStatus, stackErr := client.CheckStackReadyOrNotExisting(aws.OsdCcsAdminStackName)
+	if stackErr != nil && stackStatus == nil {
+		return found, stackErr
+	}
+	if stackStatus != nil && (stackErr != nil || !hasClusters) {
+		found++
+		if stackErr != nil {
+			reporter.Warnf("CloudFormation stack '%s' is in state '%s'", aws.OsdCcsAdminStackName,
+				*stackStatus)
+		} else {
+			reporter.Warnf("CloudFormation stack '%s' isn't used by any cluster",
+				aws.OsdCcsAdminStackName)
+		}
+		err = deleteStack(reporter, client, *stackStatus)
+		if err != nil {
+			return found, err
+		}
+	}
+
+	// The administrator user should only exist together with its stack:
+	reporter.Infof("Checking user '%s'...", aws.AdminUserName)
+	userTags, err := client.GetUserTags(aws.AdminUserName)
+	if err != nil {
+		return found, err
+	}
+	if userTags != nil && stackStatus == nil {
+		found++
+		reporter.Warnf("User '%s' exists but wasn't created by CloudFormation stack '%s', "+
+			"delete it with the AWS console or the AWS CLI before running 'rosa init' again",
+			aws.AdminUserName, aws.OsdCcsAdminStackName)
+	}
+	if userTags != nil && userTags[tags.ClusterID] != "" {
+		exists, err := ocm.ClusterExists(clustersCollection, userTags[tags.ClusterID])
+		if err != nil {
+			return found, err
+		}
+		if !exists {
+			reporter.Infof("User '%s' is tagged with cluster '%s', which no longer exists",
+				aws.AdminUserName, userTags[tags.ClusterName])
+		}
+	}
+
+	// Operator roles of clusters that no longer exist:
+	reporter.Infof("Checking IAM roles...")
+	roles, err := client.ListManagedRoles("")
+	if err != nil {
+		return found, err
+	}
+	orphans, err := aws.FindOrphanRoles(roles, func(clusterID string) (bool, error) {
+		return ocm.ClusterExists(clustersCollection, clusterID)
+	})
+	if err != nil {
+		return found, err
+	}
+	for _, role := range orphans {
+		found++
+		reporter.Warnf("Role '%s' belongs to cluster '%s', which no longer exists", role.Name,
+			role.Tags[tags.ClusterID])
+		err = deleteRole(reporter, client, role.Name)
+		if err != nil {
+			return found, err
+		}
+	}
+	if !hasClusters {
+		for _, role := range roles {
+			if role.Tags[tags.RoleType] != "" {
+				reporter.Infof("Account role '%s' can be deleted with 'rosa delete account-roles "+
+					"--prefix=%s' if no cluster of the account uses it", role.Name,
+					role.Tags[tags.RolePrefix])
+			}
+		}
+	}
+
+	return found, nil
+}
+
+func deleteStack(reporter *rprtr.Object, client aws.Client, status string) error {
+	if mode.IsManual() {
+		reporter.Infof("Run the following commands to delete it:")
+		for _, command := range aws.DeleteStackCommands(aws.OsdCcsAdminStackName,
+			aws.GetDefaultRegion()) {
+			fmt.Println(command)
+		}
+		return nil
+	}
+	if status == cloudformation.StackStatusDeleteInProgress {
+		return nil
+	}
+	confirmed, err := confirm.Confirm("delete CloudFormation stack '%s'", aws.OsdCcsAdminStackName)
+	if err != nil || !confirmed {
+		return err
+	}
+	err = client.DeleteOsdCcsAdminUser(aws.OsdCcsAdminStackName)
+	if err != nil {
+		return fmt.Errorf("Failed to delete CloudFormation stack '%s': %v", aws.OsdCcsAdminStackName,
+			err)
+	}
+	reporter.Infof("Deleted CloudFormation stack '%s'", aws.OsdCcsAdminStackName)
+	return nil
+}
+
+func deleteRole(reporter *rprtr.Object, client aws.Client, name string) error {
+	if mode.IsManual() {
+		reporter.Infof("Run the following commands to delete it:")
+		for _, command := range aws.DeleteRoleCommands(name) {
+			fmt.Println(command)
+		}
+		return nil
+	}
+	confirmed, err := confirm.Confirm("delete role '%s'", name)
+	if err != nil || !confirmed {
+		return err
+	}
+	err = client.DeleteRole(name)
+	if err != nil {
+		return err
+	}
+	reporter.Infof("Deleted role '%s'", name)
+	return nil
+}
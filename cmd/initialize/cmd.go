@@ -17,9 +17,9 @@ limitations under the License.
 package initialize
 
 import (
-	"os"
+	"fmt"
 
-	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	sdk "github.com/openshift-online/ocm-sdk-go"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/cmd/login"
@@ -28,7 +28,10 @@ import (
 	"github.com/openshift/moactl/cmd/verify/quota"
 
 	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mode"
 	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm"
 	"github.com/openshift/moactl/pkg/ocm/config"
@@ -38,6 +41,7 @@ import (
 var args struct {
 	region      string
 	deleteStack bool
+	audit       bool
 }
 
 var Cmd = &cobra.Command{
@@ -49,7 +53,13 @@ var Cmd = &cobra.Command{
   rosa init
 
   # Configure a new AWS account using pre-existing OCM credentials
-  rosa init --token=$OFFLINE_ACCESS_TOKEN`,
+  rosa init --token=$OFFLINE_ACCESS_TOKEN
+
+  # Print the AWS CLI commands that create the administrator user instead of running them
+  rosa init --mode=manual
+
+  # Find the resources left in the AWS account by clusters that no longer exist
+  rosa init --audit`,
 	Run: run,
 }
 
@@ -72,6 +82,16 @@ func init() {
 		"Deletes stack template applied to your AWS account during the 'init' command.\n",
 	)
 
+	flags.BoolVar(
+		&args.audit,
+		"audit",
+		false,
+		"Looks for resources left in your AWS account by failed installations and deleted "+
+			"clusters, like the CloudFormation stack and IAM roles, and offers to delete them.\n",
+	)
+
+	mode.AddFlag(flags)
+
 	// Force-load all flags from `login` into `init`
 	flags.AddFlagSet(login.Cmd.Flags())
 }
@@ -80,33 +100,50 @@ func run(cmd *cobra.Command, argv []string) {
 	reporter := rprtr.CreateReporterOrExit()
 	logger := logging.CreateLoggerOrExit(reporter)
 
+	err := mode.Validate()
+	if err != nil {
+		reporter.Errorf("%v", err)
+		exit.Exit(rerrors.ExitCodeValidation)
+	}
+	if args.audit && args.deleteStack {
+		reporter.Errorf("Options '--audit' and '--delete-stack' can't be used together")
+		exit.Exit(rerrors.ExitCodeValidation)
+	}
+
 	// Create the AWS client:
 	client, err := aws.NewClient().
 		Logger(logger).
-		Region(aws.DefaultRegion).
+		Region(aws.GetDefaultRegion()).
 		Build()
 	if err != nil {
 		reporter.Errorf("Error creating AWS client: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	// If necessary, call `login` as part of `init`. We do this before
 	// other validations to get the prompt out of the way before performing
-	// longer checks.
-	if cmd.Flags().NFlag() == 0 || (args.deleteStack && cmd.Flags().NFlag() == 1) {
+	// longer checks. Only the flags that aren't login flags can be given
+	// without forcing a new login.
+	initFlags := 0
+	for _, name := range []string{"delete-stack", "audit", "mode"} {
+		if cmd.Flags().Changed(name) {
+			initFlags++
+		}
+	}
+	if cmd.Flags().NFlag() == initFlags {
 		// Verify if user is already logged in:
 		isLoggedIn := false
 		cfg, err := config.Load()
 		if err != nil {
 			reporter.Errorf("Failed to load config file: %v", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCode(err))
 		}
 		if cfg != nil {
 			// Check that credentials in the config file are valid
 			isLoggedIn, err = cfg.Armed()
 			if err != nil {
 				reporter.Errorf("Failed to determine if user is logged in: %v", err)
-				os.Exit(1)
+				exit.Exit(rerrors.ExitCode(err))
 			}
 		}
 
@@ -114,7 +151,7 @@ func run(cmd *cobra.Command, argv []string) {
 			username, err := cfg.GetData("username")
 			if err != nil {
 				reporter.Errorf("Failed to get username: %v", err)
-				os.Exit(1)
+				exit.Exit(rerrors.ExitCode(err))
 			}
 
 			reporter.Infof("Logged in as '%s' on '%s'", username, cfg.URL)
@@ -131,11 +168,11 @@ func run(cmd *cobra.Command, argv []string) {
 	ok, err := client.ValidateCredentials()
 	if err != nil {
 		reporter.Errorf("Error validating AWS credentials: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 	if !ok {
 		reporter.Errorf("AWS credentials are invalid")
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 	reporter.Infof("AWS credentials are valid!")
 
@@ -143,11 +180,24 @@ func run(cmd *cobra.Command, argv []string) {
 	ocmConnection, err := ocm.NewConnection().Logger(logger).Build()
 	if err != nil {
 		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
 	}
 	defer ocmConnection.Close()
 	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
 
+	// Look for leftover resources and exit
+	if args.audit {
+		found, err := runAudit(reporter, client, clustersCollection)
+		if err != nil {
+			reporter.Errorf("%v", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		if found == 0 {
+			reporter.Infof("No leftover resources found in the AWS account")
+		}
+		exit.Exit(0)
+	}
+
 	// Delete CloudFormation stack and exit
 	if args.deleteStack {
 		reporter.Infof("Deleting cluster administrator user '%s'...", aws.AdminUserName)
@@ -156,32 +206,41 @@ func run(cmd *cobra.Command, argv []string) {
 		awsCreator, err := client.GetCreator()
 		if err != nil {
 			reporter.Errorf("Failed to get AWS creator: %v", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeAWSAuth)
 		}
 
 		// Check whether the account has clusters:
 		hasClusters, err := ocm.HasClusters(clustersCollection, awsCreator.ARN)
 		if err != nil {
 			reporter.Errorf("Failed to check for clusters: %v", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCode(err))
 		}
 
 		if hasClusters {
 			reporter.Errorf(
 				"Failed to delete '%s': User still has clusters.",
 				aws.AdminUserName)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeGeneric)
+		}
+
+		if mode.IsManual() {
+			reporter.Infof("Run the following commands to delete user '%s':", aws.AdminUserName)
+			for _, command := range aws.DeleteStackCommands(aws.OsdCcsAdminStackName,
+				aws.GetDefaultRegion()) {
+				fmt.Println(command)
+			}
+			exit.Exit(0)
 		}
 
 		// Delete the CloudFormation stack
 		err = client.DeleteOsdCcsAdminUser(aws.OsdCcsAdminStackName)
 		if err != nil {
 			reporter.Errorf("Failed to delete user '%s': %v", aws.AdminUserName, err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCode(err))
 		}
 
 		reporter.Infof("Admin user '%s' deleted successfully!", aws.AdminUserName)
-		os.Exit(0)
+		exit.Exit(0)
 	}
 
 	// Validate AWS SCP/IAM Permissions
@@ -192,12 +251,24 @@ func run(cmd *cobra.Command, argv []string) {
 	// Call `verify quota` as part of init
 	quota.Cmd.Run(cmd, argv)
 
+	if mode.IsManual() {
+		err = printAdminUserCommands(reporter, client)
+		if err != nil {
+			reporter.Errorf("%v", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		reporter.Infof("Run 'rosa verify permissions' once user '%s' has been created",
+			aws.AdminUserName)
+		oc.Cmd.Run(cmd, argv)
+		return
+	}
+
 	// Ensure that there is an AWS user to create all the resources needed by the cluster:
 	reporter.Infof("Ensuring cluster administrator user '%s'...", aws.AdminUserName)
 	created, err := client.EnsureOsdCcsAdminUser(aws.OsdCcsAdminStackName, aws.AdminUserName)
 	if err != nil {
 		reporter.Errorf("Failed to create user '%s': %v", aws.AdminUserName, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 	if created {
 		reporter.Infof("Admin user '%s' created successfully!", aws.AdminUserName)
@@ -211,13 +282,13 @@ func run(cmd *cobra.Command, argv []string) {
 	isValid, err := client.ValidateSCP(&target)
 	if !isValid {
 		reporter.Errorf("Failed to verify permissions for user '%s': %v", target, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 	reporter.Infof("AWS SCP policies ok")
 
 	// Check whether the user can create a basic cluster
 	reporter.Infof("Validating cluster creation...")
-	err = simulateCluster(clustersCollection, args.region)
+	err = simulateCluster(ocmConnection, args.region)
 	if err != nil {
 		reporter.Warnf("Cluster creation failed. "+
 			"If you create a cluster, it should fail with the following error:\n%s", err)
@@ -228,10 +299,35 @@ func run(cmd *cobra.Command, argv []string) {
 	oc.Cmd.Run(cmd, argv)
 }
 
-func simulateCluster(client *cmv1.ClustersClient, region string) error {
+// printAdminUserCommands writes the CloudFormation template that creates the administrator user to
+// the current directory, and prints the commands that create the stack, or update it if it already
+// exists.
+func printAdminUserCommands(reporter *rprtr.Object, client aws.Client) error {
+	stackReady, _, err := client.CheckStackReadyOrNotExisting(aws.OsdCcsAdminStackName)
+	if err != nil {
+		return err
+	}
+	templateFile, err := aws.WriteCFTemplate(".")
+	if err != nil {
+		return err
+	}
+	commands := aws.CreateStackCommands(aws.OsdCcsAdminStackName, templateFile, aws.GetDefaultRegion())
+	if stackReady {
+		commands = aws.UpdateStackCommands(aws.OsdCcsAdminStackName, templateFile,
+			aws.GetDefaultRegion())
+	}
+	reporter.Infof("CloudFormation template saved to '%s'", templateFile)
+	reporter.Infof("Run the following commands to create user '%s':", aws.AdminUserName)
+	for _, command := range commands {
+		fmt.Println(command)
+	}
+	return nil
+}
+
+func simulateCluster(connection *sdk.Connection, region string) error {
 	dryRun := true
 	if region == "" {
-		region = aws.DefaultRegion
+		region = aws.GetDefaultRegion()
 	}
 	spec := clusterprovider.Spec{
 		Name:   "rosa-init",
@@ -239,7 +335,7 @@ func simulateCluster(client *cmv1.ClustersClient, region string) error {
 		DryRun: &dryRun,
 	}
 
-	_, err := clusterprovider.CreateCluster(client, spec)
+	_, err := clusterprovider.CreateCluster(connection, spec)
 	if err != nil {
 		return err
 	}
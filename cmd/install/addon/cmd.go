@@ -0,0 +1,166 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"fmt"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/addons"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	clusterKey string
+	params     []string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "addon ID",
+	Aliases: []string{"addons", "add-on", "add-ons"},
+	Short:   "Install add-on on cluster",
+	Long: "Install a Red Hat managed add-on on a cluster. The values of the parameters of the " +
+		"add-on can be given with the '--param' flag, and the required parameters that are " +
+		"missing are asked for.",
+	Example: `  # Install the CodeReady Workspaces add-on on the cluster named "mycluster"
+  rosa install addon --cluster=mycluster codeready-workspaces
+
+  # Install an add-on giving the values of its parameters
+  rosa install addon -c mycluster my-addon --param notification-email=me@example.com
+
+  # Install an add-on choosing the values of all its parameters interactively
+  rosa install addon -c mycluster my-addon --interactive`,
+	Run: runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to install the add-on on (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	flags.StringArrayVar(
+		&args.params,
+		addons.ParamsFlag,
+		nil,
+		"Value of a parameter of the add-on, in the 'key=value' format. Can be repeated to give "+
+			"the values of several parameters.",
+	)
+
+	interactive.AddFlag(flags)
+}
+
+// validate checks the command line arguments before connecting to AWS and OCM.
+func validate(r *runner.Runtime) error {
+	if len(r.Args) != 1 || r.Args[0] == "" {
+		return rerrors.ValidationErrorf(
+			"Expected exactly one command line parameter containing the identifier of the add-on")
+	}
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	if !c.IsValidClusterKey(args.clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			args.clusterKey,
+		)
+	}
+
+	_, err := addons.ParseParams(args.params)
+	return err
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	addOnID := r.Args[0]
+	clusterKey := args.clusterKey
+	clustersCollection := r.OCMConnection.ClustersMgmt().V1().Clusters()
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, r.Creator.ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+	if cluster.State() != cmv1.ClusterStateReady {
+		return rerrors.ConflictErrorf("Cluster '%s' is not yet ready", clusterKey)
+	}
+
+	// Try to find the add-on:
+	reporter.Debugf("Loading add-on '%s'", addOnID)
+	addOn, err := addons.GetAddOn(r.OCMConnection.ClustersMgmt().V1().Addons(), addOnID)
+	if err != nil {
+		return fmt.Errorf("Failed to get add-on '%s': %w\n"+
+			"Try running 'rosa list addons -c %s' to see all available add-ons", addOnID, err,
+			clusterKey)
+	}
+
+	addOnInstallation, err := addons.GetAddOnInstallation(clustersCollection, cluster.ID(), addOn.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get add-on '%s' of cluster '%s': %w", addOnID, clusterKey, err)
+	}
+	if addOnInstallation != nil {
+		return rerrors.ConflictErrorf("Add-on '%s' is already installed on cluster '%s', its state "+
+			"is '%s'", addOnID, clusterKey, addons.InstallationState(addOnInstallation))
+	}
+
+	// Get the values of the parameters:
+	params, err := addons.ParseParams(args.params)
+	if err != nil {
+		return err
+	}
+	params, err = addons.PromptParams(addOn, params)
+	if err != nil {
+		return err
+	}
+	err = addons.ValidateParams(addOn, params)
+	if err != nil {
+		return err
+	}
+
+	confirmed, err := confirm.Confirm("install add-on '%s' on cluster '%s'", addOnID, clusterKey)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	reporter.Debugf("Installing add-on '%s' on cluster '%s'", addOnID, clusterKey)
+	err = addons.InstallAddOn(clustersCollection, cluster.ID(), addOn.ID(), params)
+	if err != nil {
+		return fmt.Errorf("Failed to install add-on '%s' on cluster '%s': %w", addOnID, clusterKey, err)
+	}
+	reporter.Infof("Add-on '%s' is now installing. To check the status run 'rosa list addons -c %s'",
+		addOnID, clusterKey)
+	return nil
+}
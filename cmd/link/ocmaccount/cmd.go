@@ -0,0 +1,100 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocmaccount
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
+	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/link"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+)
+
+var Cmd = &cobra.Command{
+	Use:     "ocm-account",
+	Aliases: []string{"ocm-accounts"},
+	Short:   "Verify that the AWS account is linked to the Red Hat account",
+	Long: "Verify that the AWS account is linked to the Red Hat account, that the terms and " +
+		"conditions have been accepted and that the service is enabled, and explain how to fix " +
+		"the requirements that aren't met.",
+	Example: `  # Verify that the accounts are ready to create clusters
+  rosa link ocm-account`,
+	Run: run,
+}
+
+func run(_ *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	// The OCM connection is needed by all the checks:
+	ocmConnection, err := ocm.NewConnection().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		reporter.Infof("Log in to your Red Hat account with 'rosa login'")
+		exit.Exit(rerrors.ExitCodeOCMAuth)
+	}
+	defer func() {
+		err = ocmConnection.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
+
+	// Failures to create the AWS client are reported as a failed check, so that the rest of the
+	// checks still run:
+	awsClient, awsErr := aws.NewClient().
+		Logger(logger).
+		Build()
+
+	reporter.Infof("Verifying accounts...")
+	results := link.Verify(ocmConnection, awsClient, awsErr)
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "CHECK\tRESULT\tDETAILS\n")
+	failed := []link.Result{}
+	for _, result := range results {
+		outcome := "pass"
+		if !result.Passed {
+			outcome = "fail"
+			failed = append(failed, result)
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", result.Name, outcome, result.Details)
+	}
+	writer.Flush()
+
+	if len(failed) > 0 {
+		fmt.Println()
+		for _, result := range failed {
+			if result.Remediation != "" {
+				reporter.Infof("%s: %s", result.Name, result.Remediation)
+			}
+		}
+		reporter.Errorf("%d out of %d checks failed", len(failed), len(results))
+		exit.Exit(rerrors.ExitCodeGeneric)
+	}
+	reporter.Infof("Accounts are ready to create clusters")
+}
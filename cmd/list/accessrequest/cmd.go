@@ -0,0 +1,130 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accessrequest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/accessrequests"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	clusterKey string
+	pending    bool
+}
+
+var Cmd = &cobra.Command{
+	Use:     "access-requests",
+	Aliases: []string{"access-request", "accessrequests", "accessrequest"},
+	Short:   "List the access requests of a cluster",
+	Long: "List the requests of Red Hat SREs to access the resources of a cluster, with their " +
+		"justification, the time of access requested and whether they have been approved.",
+	Example: `  # List the access requests of the cluster named "mycluster"
+  rosa list access-requests --cluster=mycluster
+
+  # List only the access requests waiting for a decision
+  rosa list access-requests --cluster=mycluster --pending`,
+	Run: runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to list the access requests of (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	flags.BoolVar(
+		&args.pending,
+		"pending",
+		false,
+		"List only the access requests that are waiting for a decision.",
+	)
+}
+
+func validate(r *runner.Runtime) error {
+	return validateClusterKey()
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	clusterKey := args.clusterKey
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(r.OCMConnection.ClustersMgmt().V1().Clusters(), clusterKey,
+		r.Creator.ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	reporter.Debugf("Loading access requests of cluster '%s'", clusterKey)
+	list, err := accessrequests.GetAccessRequests(ctx, r.OCMConnection, cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get access requests of cluster '%s': %w", clusterKey, err)
+	}
+	if args.pending {
+		list = accessrequests.FilterPending(list)
+	}
+	if len(list) == 0 {
+		reporter.Infof("There are no access requests for cluster '%s'", clusterKey)
+		return nil
+	}
+
+	// Create the writer that will be used to print the tabulated results:
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "ID\tSTATE\tREQUESTED BY\tDURATION\tDEADLINE\tSUPPORT CASE\tJUSTIFICATION\n")
+	for _, accessRequest := range list {
+		deadline := ""
+		if !accessRequest.Deadline.IsZero() {
+			deadline = accessRequest.Deadline.Format(time.RFC3339)
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", accessRequest.ID, accessRequest.State(),
+			accessRequest.RequestedBy, accessRequest.Duration, deadline, accessRequest.SupportCaseID,
+			accessRequest.Justification)
+	}
+	writer.Flush()
+	return nil
+}
+
+// validateClusterKey checks that the cluster key given by the user is reasonably safe so that there
+// is no risk of SQL injection.
+func validateClusterKey() error {
+	if !c.IsValidClusterKey(args.clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			args.clusterKey,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accountroles
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws/tags"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	prefix string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "account-roles",
+	Aliases: []string{"account-role", "accountroles", "accountrole"},
+	Short:   "List account-wide IAM roles for STS clusters",
+	Long:    "List the account roles created with 'rosa create account-roles'.",
+	Example: `  # List all the account roles
+  rosa list account-roles`,
+	Run: runner.Command(run, runner.WithAWS()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVar(
+		&args.prefix,
+		"prefix",
+		"",
+		"List only the roles with this prefix.",
+	)
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+
+	reporter.Debugf("Loading account roles")
+	roles, err := r.AWSClient.ListManagedRoles(args.prefix)
+	if err != nil {
+		return err
+	}
+
+	// Create the writer that will be used to print the tabulated results:
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	found := false
+	for _, role := range roles {
+		roleType := role.Tags[tags.RoleType]
+		if roleType == "" {
+			continue
+		}
+		if !found {
+			fmt.Fprintf(writer, "ROLE NAME\tROLE TYPE\tROLE ARN\n")
+			found = true
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", role.Name, roleType, role.ARN)
+	}
+	if !found {
+		reporter.Infof("There are no account roles")
+		return nil
+	}
+	writer.Flush()
+	return nil
+}
@@ -25,8 +25,11 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/pkg/aws"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/addons"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
 )
 
@@ -37,9 +40,9 @@ var args struct {
 var Cmd = &cobra.Command{
 	Use:     "addons",
 	Aliases: []string{"addon", "add-ons", "add-on"},
-	Hidden:  true,
 	Short:   "List add-on installations",
-	Long:    "List add-ons installed on a cluster.",
+	Long: "List the add-ons available for a cluster, together with the state of their " +
+		"installation.",
 	Example: `  # List all add-on installations on a cluster named "mycluster"
   rosa list addons --cluster=mycluster`,
 	Run: run,
@@ -71,7 +74,7 @@ func run(_ *cobra.Command, _ []string) {
 				"must contain only letters, digits, dashes and underscores",
 			clusterKey,
 		)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	// Create the client for the OCM API:
@@ -80,7 +83,7 @@ func run(_ *cobra.Command, _ []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
 	}
 	defer func() {
 		err = ocmConnection.Close()
@@ -95,13 +98,13 @@ func run(_ *cobra.Command, _ []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	awsCreator, err := awsClient.GetCreator()
 	if err != nil {
 		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	// Try to find the cluster:
@@ -109,25 +112,25 @@ func run(_ *cobra.Command, _ []string) {
 	cluster, err := ocm.GetCluster(ocmConnection.ClustersMgmt().V1().Clusters(), clusterKey, awsCreator.ARN)
 	if err != nil {
 		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	if cluster.State() != cmv1.ClusterStateReady {
 		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeConflict)
 	}
 
 	// Load any existing Add-Ons for this cluster
 	reporter.Debugf("Loading add-ons installations for cluster '%s'", clusterKey)
-	clusterAddOns, err := ocm.GetClusterAddOns(ocmConnection, cluster.ID())
+	clusterAddOns, err := addons.GetClusterAddOns(ocmConnection, cluster.ID())
 	if err != nil {
 		reporter.Errorf("Failed to get add-ons for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	if len(clusterAddOns) == 0 {
-		reporter.Infof("There are no add-ons installed on cluster '%s'", clusterKey)
-		os.Exit(0)
+		reporter.Infof("There are no add-ons available for cluster '%s'", clusterKey)
+		exit.Exit(0)
 	}
 
 	// Create the writer that will be used to print the tabulated results:
@@ -0,0 +1,130 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package breakglasscredential
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/breakglass"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	clusterKey string
+	all        bool
+}
+
+var Cmd = &cobra.Command{
+	Use:     "break-glass-credentials",
+	Aliases: []string{"break-glass-credential", "breakglasscredentials", "breakglasscredential"},
+	Short:   "List the break glass credentials of a cluster",
+	Long: "List the break glass credentials of a cluster that can still be used, or all of them " +
+		"including the revoked and expired ones.",
+	Example: `  # List the active break glass credentials of the cluster named "mycluster"
+  rosa list break-glass-credentials --cluster=mycluster
+
+  # List all the break glass credentials, including the revoked and expired ones
+  rosa list break-glass-credentials --cluster=mycluster --all`,
+	Run: runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to list the break glass credentials of (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	flags.BoolVar(
+		&args.all,
+		"all",
+		false,
+		"List also the credentials that have been revoked or have expired.",
+	)
+}
+
+func validate(r *runner.Runtime) error {
+	return validateClusterKey()
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	clusterKey := args.clusterKey
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(r.OCMConnection.ClustersMgmt().V1().Clusters(), clusterKey,
+		r.Creator.ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	reporter.Debugf("Loading break glass credentials of cluster '%s'", clusterKey)
+	credentials, err := breakglass.GetCredentials(ctx, r.OCMConnection, cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get break glass credentials of cluster '%s': %w", clusterKey,
+			err)
+	}
+	if !args.all {
+		credentials = breakglass.FilterActive(credentials)
+	}
+	if len(credentials) == 0 {
+		reporter.Infof("There are no break glass credentials for cluster '%s'", clusterKey)
+		return nil
+	}
+
+	// Create the writer that will be used to print the tabulated results:
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "ID\tUSERNAME\tSTATUS\tEXPIRES\tREVOKED\n")
+	for _, credential := range credentials {
+		revoked := ""
+		if !credential.RevocationTimestamp.IsZero() {
+			revoked = credential.RevocationTimestamp.Format(time.RFC3339)
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", credential.ID, credential.Username,
+			credential.Status, credential.ExpirationTimestamp.Format(time.RFC3339), revoked)
+	}
+	writer.Flush()
+	return nil
+}
+
+// validateClusterKey checks that the cluster key given by the user is reasonably safe so that there
+// is no risk of SQL injection.
+func validateClusterKey() error {
+	if !c.IsValidClusterKey(args.clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			args.clusterKey,
+		)
+	}
+	return nil
+}
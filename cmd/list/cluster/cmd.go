@@ -19,19 +19,30 @@ package cluster
 import (
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
 
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/pkg/aws"
 	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/paging"
+	"github.com/openshift/moactl/pkg/ocm/versions"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
 )
 
 var args struct {
-	count int
+	count    int
+	paging   paging.Options
+	state    string
+	region   string
+	nameLike string
+	sortBy   string
 }
 
 var Cmd = &cobra.Command{
@@ -39,8 +50,17 @@ var Cmd = &cobra.Command{
 	Aliases: []string{"cluster"},
 	Short:   "List clusters",
 	Long:    "List clusters.",
-	Example: `  # List all clusters
-  rosa list clusters`,
+	Example: `  # List the first 100 clusters
+  rosa list clusters
+
+  # List all the clusters, printing them as they are loaded
+  rosa list clusters --all
+
+  # List the ready clusters in region us-east-1, newest first
+  rosa list clusters --state=ready --region=us-east-1 --sort-by=-created
+
+  # List the clusters whose name starts with "dev"
+  rosa list clusters --name-like="dev*"`,
 	Run: run,
 }
 
@@ -48,23 +68,74 @@ func init() {
 	flags := Cmd.Flags()
 	flags.SortFlags = false
 
-	// Basic options
+	// Paging options
+	paging.AddFlags(flags, &args.paging)
 	flags.IntVar(
 		&args.count,
 		"count",
-		100,
+		paging.DefaultLimit,
 		"Number of clusters to display.",
 	)
+	flags.MarkDeprecated("count", "use '--limit' instead")
+
+	// Search options
+	flags.StringVar(
+		&args.state,
+		"state",
+		"",
+		"Only list the clusters in the given state, for example 'ready' or 'installing'.",
+	)
+	flags.StringVar(
+		&args.region,
+		"region",
+		"",
+		"Only list the clusters in the given AWS region.",
+	)
+	flags.StringVar(
+		&args.nameLike,
+		"name-like",
+		"",
+		"Only list the clusters whose name contains the given text, or matches it when it contains "+
+			"'*' wildcards.",
+	)
+
+	// Sorting options
+	flags.StringVar(
+		&args.sortBy,
+		"sort-by",
+		"",
+		fmt.Sprintf("Sort the listed clusters by one of %s. Prefix the key with '-' to reverse the "+
+			"order. The clusters are printed once all of them have been loaded.",
+			strings.Join(clusterprovider.SortKeys, ", ")),
+	)
 }
 
-func run(_ *cobra.Command, argv []string) {
+func run(cmd *cobra.Command, argv []string) {
 	reporter := rprtr.CreateReporterOrExit()
 	logger := logging.CreateLoggerOrExit(reporter)
 
 	// Check command line arguments:
 	if len(argv) != 0 {
 		reporter.Errorf("Expected exactly zero command line parameters")
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
+	}
+
+	if cmd.Flags().Changed("count") && !cmd.Flags().Changed("limit") {
+		args.paging.Limit = args.count
+	}
+	err := args.paging.Validate()
+	if err != nil {
+		reporter.Errorf("%v", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	// Check the sort key before sending any request:
+	if args.sortBy != "" {
+		err := clusterprovider.ValidateSortKey(args.sortBy)
+		if err != nil {
+			reporter.Errorf("%v", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
 	}
 
 	// Create the AWS client:
@@ -73,13 +144,13 @@ func run(_ *cobra.Command, argv []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	awsCreator, err := awsClient.GetCreator()
 	if err != nil {
 		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	// Create the client for the OCM API:
@@ -88,7 +159,7 @@ func run(_ *cobra.Command, argv []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
 	}
 	defer func() {
 		err = ocmConnection.Close()
@@ -97,30 +168,75 @@ func run(_ *cobra.Command, argv []string) {
 		}
 	}()
 
-	// Retrieve the list of clusters:
-	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
-	clusters, err := clusterprovider.GetClusters(clustersCollection, awsCreator.ARN, args.count)
+	// Create the writer that will be used to print the tabulated results. It is flushed after
+	// each page, so that the rows are printed as soon as they are loaded:
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	total := 0
+	ocmClient := ocmConnection.ClustersMgmt().V1()
+	printClusters := func(clusters []*cmv1.Cluster) {
+		// Load the nodes and upgrades of all the clusters concurrently:
+		reporter.Debugf("Loading details of %d clusters", len(clusters))
+		details := clusterprovider.GetDetails(ocmClient, clusters)
+
+		if total == 0 {
+			fmt.Fprintf(writer, "ID\tNAME\tSTATE\tVERSION\tREGION\tNODES\tAVAILABLE UPGRADE\n")
+		}
+		total += len(clusters)
+		for i, cluster := range clusters {
+			if details[i].Err != nil {
+				reporter.Warnf("Failed to load details of cluster '%s': %v", cluster.Name(),
+					details[i].Err)
+			}
+			upgrade := ""
+			if len(details[i].AvailableUpgrades) > 0 {
+				upgrade = details[i].AvailableUpgrades[0]
+			}
+			fmt.Fprintf(
+				writer,
+				"%s\t%s\t%s\t%s\t%s\t%d\t%s\n",
+				cluster.ID(),
+				cluster.Name(),
+				cluster.State(),
+				versions.GetRawVersion(cluster.Version()),
+				cluster.Region().ID(),
+				details[i].ComputeNodes,
+				upgrade,
+			)
+		}
+		writer.Flush()
+	}
+
+	// Retrieve the list of clusters. When they need to be sorted they are printed at the end:
+	sorted := []*cmv1.Cluster{}
+	err = clusterprovider.ListClusters(ocmClient.Clusters(), awsCreator.ARN,
+		clusterprovider.ListOptions{
+			State:    args.state,
+			Region:   args.region,
+			NameLike: args.nameLike,
+		},
+		args.paging,
+		func(clusters []*cmv1.Cluster) error {
+			if args.sortBy != "" {
+				sorted = append(sorted, clusters...)
+			} else {
+				printClusters(clusters)
+			}
+			return nil
+		})
 	if err != nil {
 		reporter.Errorf("Failed to get clusters: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
-
-	if len(clusters) == 0 {
-		reporter.Infof("No clusters available")
-		os.Exit(0)
+	if len(sorted) > 0 {
+		err = clusterprovider.SortClusters(sorted, args.sortBy)
+		if err != nil {
+			reporter.Errorf("%v", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		printClusters(sorted)
 	}
 
-	// Create the writer that will be used to print the tabulated results:
-	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(writer, "ID\tNAME\tSTATE\n")
-	for _, cluster := range clusters {
-		fmt.Fprintf(
-			writer,
-			"%s\t%s\t%s\n",
-			cluster.ID(),
-			cluster.Name(),
-			cluster.State(),
-		)
+	if total == 0 {
+		reporter.Infof("No clusters available")
 	}
-	writer.Flush()
 }
@@ -19,12 +19,23 @@ package list
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/moactl/cmd/list/accessrequest"
+	"github.com/openshift/moactl/cmd/list/accountroles"
 	"github.com/openshift/moactl/cmd/list/addon"
+	"github.com/openshift/moactl/cmd/list/breakglasscredential"
 	"github.com/openshift/moactl/cmd/list/cluster"
+	"github.com/openshift/moactl/cmd/list/event"
 	"github.com/openshift/moactl/cmd/list/idp"
 	"github.com/openshift/moactl/cmd/list/ingress"
+	"github.com/openshift/moactl/cmd/list/instancetypes"
 	"github.com/openshift/moactl/cmd/list/machinepool"
+	"github.com/openshift/moactl/cmd/list/notificationcontact"
+	"github.com/openshift/moactl/cmd/list/oidcprovider"
+	"github.com/openshift/moactl/cmd/list/operation"
+	"github.com/openshift/moactl/cmd/list/operatorroles"
 	"github.com/openshift/moactl/cmd/list/region"
+	"github.com/openshift/moactl/cmd/list/schedule"
+	"github.com/openshift/moactl/cmd/list/servicelog"
 	"github.com/openshift/moactl/cmd/list/upgrade"
 	"github.com/openshift/moactl/cmd/list/user"
 	"github.com/openshift/moactl/cmd/list/version"
@@ -37,12 +48,23 @@ var Cmd = &cobra.Command{
 }
 
 func init() {
+	Cmd.AddCommand(accessrequest.Cmd)
+	Cmd.AddCommand(accountroles.Cmd)
 	Cmd.AddCommand(addon.Cmd)
+	Cmd.AddCommand(breakglasscredential.Cmd)
 	Cmd.AddCommand(cluster.Cmd)
+	Cmd.AddCommand(event.Cmd)
 	Cmd.AddCommand(idp.Cmd)
 	Cmd.AddCommand(ingress.Cmd)
+	Cmd.AddCommand(instancetypes.Cmd)
 	Cmd.AddCommand(machinepool.Cmd)
+	Cmd.AddCommand(notificationcontact.Cmd)
+	Cmd.AddCommand(oidcprovider.Cmd)
+	Cmd.AddCommand(operation.Cmd)
+	Cmd.AddCommand(operatorroles.Cmd)
 	Cmd.AddCommand(region.Cmd)
+	Cmd.AddCommand(schedule.Cmd)
+	Cmd.AddCommand(servicelog.Cmd)
 	Cmd.AddCommand(upgrade.Cmd)
 	Cmd.AddCommand(user.Cmd)
 	Cmd.AddCommand(version.Cmd)
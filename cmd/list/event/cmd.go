@@ -0,0 +1,185 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/events"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/paging"
+	"github.com/openshift/moactl/pkg/ocm/servicelogs"
+	"github.com/openshift/moactl/pkg/ocm/upgrades"
+	"github.com/openshift/moactl/pkg/operations"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	clusterKey string
+	since      string
+	output     string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "events",
+	Aliases: []string{"event"},
+	Short:   "List the changes of a cluster",
+	Long: "List the changes of a cluster in chronological order: its creation, the entries of its " +
+		"service log, like upgrades, scaling and identity provider changes, its scheduled " +
+		"upgrades and the operations started on it with this tool.",
+	Example: `  # List what changed on the cluster named "mycluster" during the last week
+  rosa list events --cluster=mycluster
+
+  # List the changes since the first of March in JSON format
+  rosa list events --cluster=mycluster --since=2021-03-01 --output=json`,
+	Args: cobra.NoArgs,
+	Run:  runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to list the events of (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	flags.StringVar(
+		&args.since,
+		"since",
+		"7d",
+		"Show only the events after this time, given as a duration like '12h' or '3d', or as a "+
+			"date like '2006-01-02'.",
+	)
+
+	flags.StringVarP(
+		&args.output,
+		"output",
+		"o",
+		"",
+		"Output format. Use 'json' to print the events in JSON format.",
+	)
+}
+
+func validate(r *runner.Runtime) error {
+	if !c.IsValidClusterKey(args.clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			args.clusterKey,
+		)
+	}
+	_, err := servicelogs.ParseSince(args.since, time.Now())
+	if err != nil {
+		return err
+	}
+	if args.output != "" && args.output != "json" {
+		return rerrors.ValidationErrorf("Invalid output format '%s', expected 'json'", args.output)
+	}
+	return nil
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	clusterKey := args.clusterKey
+	ocmClient := r.OCMConnection.ClustersMgmt().V1()
+
+	since, err := servicelogs.ParseSince(args.since, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(ocmClient.Clusters(), clusterKey, r.Creator.ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+	list := events.FromCluster(cluster)
+
+	reporter.Debugf("Loading service logs of cluster '%s'", clusterKey)
+	err = servicelogs.ListLogEntries(r.OCMConnection.ServiceLogs().V1(),
+		servicelogs.Query(cluster.ExternalID(), nil, since), paging.Options{All: true},
+		func(entries []*slv1.LogEntry) error {
+			list = append(list, events.FromLogEntries(entries)...)
+			return nil
+		})
+	if err != nil {
+		return fmt.Errorf("Failed to get service logs of cluster '%s': %w", clusterKey, err)
+	}
+
+	reporter.Debugf("Loading upgrade policies of cluster '%s'", clusterKey)
+	policies, err := upgrades.GetUpgradePolicies(ocmClient, cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get upgrade policies of cluster '%s': %w", clusterKey, err)
+	}
+	list = append(list, events.FromUpgradePolicies(policies)...)
+
+	// Operations are only recorded on the machine that started them, so failing to load them
+	// doesn't hide the rest of the events:
+	started, err := operations.Load()
+	if err != nil {
+		reporter.Warnf("%v", err)
+	} else {
+		list = append(list, events.FromOperations(started, cluster.ID())...)
+	}
+
+	timeline := events.Timeline(list, since)
+
+	if args.output == "json" {
+		data, err := json.MarshalIndent(timeline, "", "  ")
+		if err != nil {
+			return fmt.Errorf("Failed to marshal events of cluster '%s': %v", clusterKey, err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(timeline) == 0 {
+		reporter.Infof("There are no events for cluster '%s' since %s", clusterKey,
+			since.Local().Format("2006-01-02 15:04 MST"))
+		return nil
+	}
+
+	// Create the writer that will be used to print the tabulated results:
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "TIMESTAMP\tTYPE\tSOURCE\tSUMMARY\n")
+	for _, event := range timeline {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n",
+			event.Timestamp.Local().Format("2006-01-02 15:04 MST"),
+			event.Type,
+			event.Source,
+			event.Summary,
+		)
+	}
+	writer.Flush()
+	return nil
+}
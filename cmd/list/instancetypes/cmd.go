@@ -0,0 +1,173 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancetypes
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
+	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/machines"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/verify/quota"
+)
+
+var args struct {
+	region       string
+	multiAZ      bool
+	computeNodes int
+}
+
+var Cmd = &cobra.Command{
+	Use:     "instance-types",
+	Aliases: []string{"instance-type", "instancetypes", "instancetype"},
+	Short:   "List instance types",
+	Long:    "List the instance types that can be used for the compute nodes of a cluster.",
+	Example: `  # List all instance types
+  rosa list instance-types
+
+  # List the instance types that fit in the AWS quota of a multi-AZ cluster with 6 compute nodes
+  rosa list instance-types --multi-az --compute-nodes=6 --region=us-east-2`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.region,
+		"region",
+		"r",
+		"",
+		"AWS region used to check the quota of vCPUs (overrides the AWS_REGION environment variable).",
+	)
+
+	flags.BoolVar(
+		&args.multiAZ,
+		"multi-az",
+		false,
+		"Check the quota of vCPUs for a cluster deployed to multiple data centers.",
+	)
+
+	flags.IntVar(
+		&args.computeNodes,
+		"compute-nodes",
+		0,
+		"List only the instance types whose compute nodes, together with the rest of the nodes of "+
+			"the cluster, fit in the AWS quota of vCPUs.",
+	)
+}
+
+func run(cmd *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	// Create the client for the OCM API:
+	ocmConnection, err := ocm.NewConnection().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
+	}
+	defer func() {
+		err = ocmConnection.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
+
+	// Get the client for the OCM collection of clusters:
+	ocmClient := ocmConnection.ClustersMgmt().V1()
+
+	reporter.Debugf("Fetching instance types")
+	machineTypes, err := machines.GetMachineTypes(ocmClient)
+	if err != nil {
+		reporter.Errorf("Failed to fetch instance types: %v", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	if args.computeNodes > 0 {
+		machineTypes = filterByQuota(reporter, logger, ocmClient, machineTypes)
+	}
+
+	if len(machineTypes) == 0 {
+		reporter.Warnf("There are no instance types available")
+		exit.Exit(rerrors.ExitCodeGeneric)
+	}
+
+	// Create the writer that will be used to print the tabulated results:
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "ID\tCATEGORY\tCPU_CORES\tMEMORY\n")
+	for _, machineType := range machineTypes {
+		fmt.Fprintf(writer,
+			"%s\t%s\t%d\t%s\n",
+			machineType.ID(),
+			machineType.Category(),
+			int(machineType.CPU().Value()),
+			machines.FormatMemory(machineType.Memory()),
+		)
+	}
+	writer.Flush()
+}
+
+func filterByQuota(reporter *rprtr.Object, logger *logrus.Logger, ocmClient *cmv1.Client,
+	machineTypes []*cmv1.MachineType) []*cmv1.MachineType {
+	// Get AWS region
+	region, err := aws.GetRegion(args.region)
+	if err != nil {
+		reporter.Errorf("Error getting region: %v", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	// Create the AWS client:
+	awsClient, err := aws.NewClient().
+		Logger(logger).
+		Region(region).
+		Build()
+	if err != nil {
+		reporter.Errorf("Error creating AWS client: %v", err)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
+	}
+
+	reporter.Debugf("Fetching AWS quota of vCPUs in region '%s'", region)
+	vcpuQuota, err := quota.GetVCPUQuota(awsClient)
+	if err != nil {
+		reporter.Errorf("Failed to get AWS quota of vCPUs: %v", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	flavour, err := quota.GetFlavour(ocmClient)
+	if err != nil {
+		reporter.Errorf("%v", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	return quota.FilterMachineTypes(flavour, machineTypes, quota.Options{
+		MultiAZ:      args.multiAZ,
+		ComputeNodes: args.computeNodes,
+	}, vcpuQuota)
+}
@@ -0,0 +1,114 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notificationcontact
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/contacts"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	clusterKey string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "notification-contacts",
+	Aliases: []string{"notification-contact", "notificationcontacts", "notificationcontact"},
+	Short:   "List cluster notification contacts",
+	Long: "List the users that receive the emails about scheduled maintenance and support cases " +
+		"of a cluster, in addition to its owner.",
+	Example: `  # List the notification contacts of the cluster named "mycluster"
+  rosa list notification-contacts --cluster=mycluster`,
+	Run: runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to list the notification contacts of (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+}
+
+func validate(r *runner.Runtime) error {
+	return validateClusterKey()
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	clusterKey := args.clusterKey
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(r.OCMConnection.ClustersMgmt().V1().Clusters(), clusterKey,
+		r.Creator.ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+	subscriptionID := cluster.Subscription().ID()
+	if subscriptionID == "" {
+		return fmt.Errorf("Cluster '%s' doesn't have a subscription", clusterKey)
+	}
+
+	reporter.Debugf("Loading notification contacts of cluster '%s'", clusterKey)
+	list, err := contacts.GetContacts(r.OCMConnection, subscriptionID)
+	if err != nil {
+		return fmt.Errorf("Failed to get notification contacts of cluster '%s': %w", clusterKey, err)
+	}
+	if len(list) == 0 {
+		reporter.Infof("There are no notification contacts for cluster '%s'", clusterKey)
+		return nil
+	}
+
+	// Create the writer that will be used to print the tabulated results:
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "USERNAME\tNAME\tEMAIL\n")
+	for _, contact := range list {
+		fmt.Fprintf(writer, "%s\t%s %s\t%s\n",
+			contact.Username(), contact.FirstName(), contact.LastName(), contact.Email())
+	}
+	writer.Flush()
+	return nil
+}
+
+// validate checks that the cluster key given by the user is reasonably safe so that there is no
+// risk of SQL injection.
+func validateClusterKey() error {
+	if !c.IsValidClusterKey(args.clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			args.clusterKey,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oidcprovider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var Cmd = &cobra.Command{
+	Use:     "oidc-providers",
+	Aliases: []string{"oidc-provider", "oidcproviders", "oidcprovider"},
+	Short:   "List OIDC providers",
+	Long:    "List the OpenID Connect providers of the AWS account.",
+	Example: `  # List all the OIDC providers
+  rosa list oidc-providers`,
+	Run: runner.Command(run, runner.WithAWS()),
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+
+	reporter.Debugf("Loading OIDC providers")
+	providers, err := r.AWSClient.ListOIDCProviders()
+	if err != nil {
+		return err
+	}
+	if len(providers) == 0 {
+		reporter.Infof("There are no OIDC providers")
+		return nil
+	}
+
+	// Create the writer that will be used to print the tabulated results:
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "URL\tARN\n")
+	for _, provider := range providers {
+		fmt.Fprintf(writer, "%s\t%s\n", provider.URL, provider.ARN)
+	}
+	writer.Flush()
+	return nil
+}
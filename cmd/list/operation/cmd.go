@@ -0,0 +1,109 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/operations"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	all bool
+}
+
+var Cmd = &cobra.Command{
+	Use:     "operations",
+	Aliases: []string{"operation"},
+	Short:   "List long running operations",
+	Long: "List the long running operations started by this tool, like the creation, deletion and " +
+		"upgrade of clusters and the changes to machine pools, with their current state.",
+	Example: `  # List the operations that haven't finished yet
+  rosa list operations
+
+  # List also the operations that finished during the last week
+  rosa list operations --all`,
+	Args: cobra.NoArgs,
+	Run:  runner.Command(run, runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.BoolVar(
+		&args.all,
+		"all",
+		false,
+		"Include the operations that have already finished.",
+	)
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+
+	list, err := operations.Load()
+	if err != nil {
+		return err
+	}
+
+	// Update the state of the operations that haven't finished yet:
+	reporter.Debugf("Checking %d operations in progress", len(operations.InProgress(list)))
+	changed, errs := operations.Refresh(ctx, r.OCMConnection.ClustersMgmt().V1(), list, time.Now())
+	for _, err := range errs {
+		reporter.Warnf("%v", err)
+	}
+	if changed {
+		err = operations.Save(list)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !args.all {
+		list = operations.InProgress(list)
+	}
+	if len(list) == 0 {
+		reporter.Infof("There are no operations in progress")
+		return nil
+	}
+
+	// Create the writer that will be used to print the tabulated results:
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "ID\tTYPE\tCLUSTER\tRESOURCE\tSTARTED\tSTATE\tDETAILS\n")
+	for _, operation := range list {
+		resource := operation.Resource
+		if operation.Target != "" {
+			resource = operation.Target
+		}
+		if resource == "" {
+			resource = "-"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			operation.ID, operation.Type, operation.ClusterName, resource,
+			operation.StartedAt.Local().Format("2006-01-02 15:04 MST"), operation.State,
+			operation.Details)
+	}
+	writer.Flush()
+	return nil
+}
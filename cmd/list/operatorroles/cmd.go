@@ -0,0 +1,110 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operatorroles
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws/tags"
+	c "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	clusterKey string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "operator-roles",
+	Aliases: []string{"operator-role", "operatorroles", "operatorrole"},
+	Short:   "List operator IAM roles of an STS cluster",
+	Long:    "List the operator roles of a cluster created with 'rosa create operator-roles'.",
+	Example: `  # List the operator roles of the cluster named "mycluster"
+  rosa list operator-roles --cluster=mycluster`,
+	Run: runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to list the operator roles of (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+}
+
+func validate(r *runner.Runtime) error {
+	if !c.IsValidClusterKey(args.clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			args.clusterKey,
+		)
+	}
+	return nil
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	clusterKey := args.clusterKey
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(r.OCMConnection.ClustersMgmt().V1().Clusters(), clusterKey,
+		r.Creator.ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	reporter.Debugf("Loading operator roles of cluster '%s'", clusterKey)
+	roles, err := r.AWSClient.ListManagedRoles(cluster.Name())
+	if err != nil {
+		return err
+	}
+
+	// Create the writer that will be used to print the tabulated results:
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	found := false
+	for _, role := range roles {
+		if role.Tags[tags.ClusterID] != cluster.ID() {
+			continue
+		}
+		if !found {
+			fmt.Fprintf(writer, "ROLE NAME\tNAMESPACE\tOPERATOR\tROLE ARN\n")
+			found = true
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", role.Name, role.Tags[tags.OperatorNamespace],
+			role.Tags[tags.OperatorName], role.ARN)
+	}
+	if !found {
+		reporter.Infof("There are no operator roles for cluster '%s'", clusterKey)
+		return nil
+	}
+	writer.Flush()
+	return nil
+}
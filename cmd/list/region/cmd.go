@@ -23,6 +23,8 @@ import (
 
 	"github.com/spf13/cobra"
 
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm"
 	"github.com/openshift/moactl/pkg/ocm/regions"
@@ -63,7 +65,7 @@ func run(cmd *cobra.Command, _ []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
 	}
 	defer func() {
 		err = ocmConnection.Close()
@@ -80,12 +82,12 @@ func run(cmd *cobra.Command, _ []string) {
 	regions, err := regions.GetRegions(ocmClient)
 	if err != nil {
 		reporter.Errorf("Failed to fetch regions: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	if len(regions) == 0 {
 		reporter.Warnf("There are no regions available for this AWS account")
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeGeneric)
 	}
 
 	// Create the writer that will be used to print the tabulated results:
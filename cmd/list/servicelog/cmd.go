@@ -0,0 +1,182 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicelog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/paging"
+	"github.com/openshift/moactl/pkg/ocm/servicelogs"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	clusterKey string
+	severity   string
+	since      string
+	paging     paging.Options
+}
+
+var Cmd = &cobra.Command{
+	Use:     "service-logs",
+	Aliases: []string{"service-log", "servicelogs", "servicelog"},
+	Short:   "List cluster service logs",
+	Long: "List the entries of the service log of a cluster, newest first, and the reasons why " +
+		"the cluster is in limited support, if any.",
+	Example: `  # List the service log of the cluster named "mycluster"
+  rosa list service-logs --cluster=mycluster
+
+  # List the warnings and errors of the last three days
+  rosa list service-logs --cluster=mycluster --severity=warning --since=3d
+
+  # List all the entries of the service log, not only the latest 100
+  rosa list service-logs --cluster=mycluster --all`,
+	Run: runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to list the service logs of (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	flags.StringVar(
+		&args.severity,
+		"severity",
+		"",
+		fmt.Sprintf("Show only the entries with this severity or a higher one. Valid values are: %s.",
+			strings.Join(servicelogs.Severities, ", ")),
+	)
+
+	flags.StringVar(
+		&args.since,
+		"since",
+		"",
+		"Show only the entries created after this time, given as a duration like '12h' or '3d', "+
+			"or as a date like '2006-01-02'.",
+	)
+
+	paging.AddFlags(flags, &args.paging)
+}
+
+func validate(r *runner.Runtime) error {
+	if !c.IsValidClusterKey(args.clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			args.clusterKey,
+		)
+	}
+	if args.severity != "" {
+		_, err := servicelogs.SeveritiesFrom(args.severity)
+		if err != nil {
+			return err
+		}
+	}
+	if args.since != "" {
+		_, err := servicelogs.ParseSince(args.since, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+	return args.paging.Validate()
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	clusterKey := args.clusterKey
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(r.OCMConnection.ClustersMgmt().V1().Clusters(), clusterKey,
+		r.Creator.ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	reporter.Debugf("Loading limited support reasons of cluster '%s'", clusterKey)
+	reasons, err := c.GetLimitedSupportReasons(r.OCMConnection, cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get limited support reasons of cluster '%s': %w", clusterKey, err)
+	}
+	for _, reason := range reasons {
+		reporter.Warnf("Cluster '%s' is in limited support: %s", clusterKey, reason.Summary)
+	}
+
+	var severities []string
+	if args.severity != "" {
+		severities, err = servicelogs.SeveritiesFrom(args.severity)
+		if err != nil {
+			return err
+		}
+	}
+	var since time.Time
+	if args.since != "" {
+		since, err = servicelogs.ParseSince(args.since, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+
+	// Create the writer that will be used to print the tabulated results. It is flushed after
+	// each page, so that the entries are printed as soon as they are loaded:
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	total := 0
+
+	reporter.Debugf("Loading service logs of cluster '%s'", clusterKey)
+	err = servicelogs.ListLogEntries(r.OCMConnection.ServiceLogs().V1(),
+		servicelogs.Query(cluster.ExternalID(), severities, since), args.paging,
+		func(entries []*slv1.LogEntry) error {
+			if total == 0 {
+				fmt.Fprintf(writer, "TIMESTAMP\tSEVERITY\tSERVICE\tSUMMARY\n")
+			}
+			total += len(entries)
+			for _, entry := range entries {
+				fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n",
+					entry.Timestamp().Local().Format("2006-01-02 15:04 MST"),
+					entry.Severity(),
+					entry.ServiceName(),
+					entry.Summary(),
+				)
+			}
+			return writer.Flush()
+		})
+	if err != nil {
+		return fmt.Errorf("Failed to get service logs of cluster '%s': %w", clusterKey, err)
+	}
+	if total == 0 {
+		reporter.Infof("There are no service logs for cluster '%s'", clusterKey)
+	}
+	return nil
+}
@@ -21,11 +21,16 @@ import (
 	"os"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/pkg/aws"
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm"
 	"github.com/openshift/moactl/pkg/ocm/upgrades"
@@ -34,15 +39,24 @@ import (
 )
 
 var args struct {
-	clusterKey string
+	clusterKey  string
+	allClusters bool
+	before      string
+	state       string
 }
 
 var Cmd = &cobra.Command{
 	Use:     "upgrades",
 	Aliases: []string{"upgrade"},
 	Short:   "List available cluster upgrades",
-	Long:    "List available and scheduled cluster version upgrades",
-	Run:     run,
+	Long: "List available and scheduled cluster version upgrades. With '--all-clusters' it lists " +
+		"the scheduled upgrades of all the clusters of the organization instead.",
+	Example: `  # List the available upgrades of a cluster named "mycluster"
+  rosa list upgrades --cluster=mycluster
+
+  # List the upgrades of all the clusters of the organization scheduled before June
+  rosa list upgrades --all-clusters --before=2021-06-01`,
+	Run: run,
 }
 
 func init() {
@@ -53,15 +67,52 @@ func init() {
 		"cluster",
 		"c",
 		"",
-		"Name or ID of the cluster to list the upgrades of (required).",
+		"Name or ID of the cluster to list the upgrades of (required unless '--all-clusters' is "+
+			"given).",
+	)
+	flags.BoolVar(
+		&args.allClusters,
+		"all-clusters",
+		false,
+		"List the scheduled upgrades of all the clusters of the organization.",
+	)
+	flags.StringVar(
+		&args.before,
+		"before",
+		"",
+		"Only list the upgrades scheduled before this date, like '2021-06-01', or time, like "+
+			"'2021-06-01T10:00:00Z'. Requires '--all-clusters'.",
+	)
+	flags.StringVar(
+		&args.state,
+		"state",
+		"",
+		"Only list the upgrades in this state, like 'pending' or 'scheduled'. Requires "+
+			"'--all-clusters'.",
 	)
-	Cmd.MarkFlagRequired("cluster")
 }
 
 func run(_ *cobra.Command, _ []string) {
 	reporter := rprtr.CreateReporterOrExit()
 	logger := logging.CreateLoggerOrExit(reporter)
 
+	if args.allClusters {
+		if args.clusterKey != "" {
+			reporter.Errorf("Option '--cluster' can't be used with '--all-clusters'")
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+		runAllClusters(reporter, logger)
+		return
+	}
+	if args.clusterKey == "" {
+		reporter.Errorf("Option '--cluster' is required unless '--all-clusters' is given")
+		exit.Exit(rerrors.ExitCodeValidation)
+	}
+	if args.before != "" || args.state != "" {
+		reporter.Errorf("Options '--before' and '--state' require '--all-clusters'")
+		exit.Exit(rerrors.ExitCodeValidation)
+	}
+
 	// Check that the cluster key (name, identifier or external identifier) given by the user
 	// is reasonably safe so that there is no risk of SQL injection:
 	clusterKey := args.clusterKey
@@ -71,7 +122,7 @@ func run(_ *cobra.Command, _ []string) {
 				"must contain only letters, digits, dashes and underscores",
 			clusterKey,
 		)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	// Create the client for the OCM API:
@@ -80,7 +131,7 @@ func run(_ *cobra.Command, _ []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
 	}
 	defer func() {
 		err = ocmConnection.Close()
@@ -96,13 +147,13 @@ func run(_ *cobra.Command, _ []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	awsCreator, err := awsClient.GetCreator()
 	if err != nil {
 		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	// Try to find the cluster:
@@ -110,12 +161,12 @@ func run(_ *cobra.Command, _ []string) {
 	cluster, err := ocm.GetCluster(ocmClient.Clusters(), clusterKey, awsCreator.ARN)
 	if err != nil {
 		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	if cluster.State() != cmv1.ClusterStateReady {
 		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeConflict)
 	}
 
 	// Load available upgrades for this cluster
@@ -123,12 +174,12 @@ func run(_ *cobra.Command, _ []string) {
 	availableUpgrades, err := versions.GetAvailableUpgrades(ocmClient, versions.GetVersionID(cluster))
 	if err != nil {
 		reporter.Errorf("Failed to get available upgrades for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	if len(availableUpgrades) == 0 {
 		reporter.Infof("There are no available upgrades for cluster '%s'", clusterKey)
-		os.Exit(0)
+		exit.Exit(0)
 	}
 
 	latestRev := latestInCurrentMinor(versions.GetVersionID(cluster), availableUpgrades)
@@ -137,7 +188,7 @@ func run(_ *cobra.Command, _ []string) {
 	scheduledUpgrade, err := upgrades.GetScheduledUpgrade(ocmClient, cluster.ID())
 	if err != nil {
 		reporter.Errorf("Failed to get scheduled upgrades for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	// Create the writer that will be used to print the tabulated results:
@@ -156,6 +207,75 @@ func run(_ *cobra.Command, _ []string) {
 	writer.Flush()
 }
 
+// runAllClusters lists the scheduled upgrades of all the clusters of the organization that match
+// the filters, loading the upgrade policies of the clusters concurrently.
+func runAllClusters(reporter *rprtr.Object, logger *logrus.Logger) {
+	filter := clusterprovider.UpgradeFilter{
+		State: args.state,
+	}
+	if args.before != "" {
+		before, err := clusterprovider.ParseBefore(args.before, time.Local)
+		if err != nil {
+			reporter.Errorf("%v", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		filter.Before = before
+	}
+
+	// Create the client for the OCM API:
+	ocmConnection, err := ocm.NewConnection().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
+	}
+	defer func() {
+		err = ocmConnection.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
+	ocmClient := ocmConnection.ClustersMgmt().V1()
+
+	reporter.Debugf("Loading the clusters of the organization")
+	clusters, err := clusterprovider.ListOrganizationClusters(ocmClient.Clusters())
+	if err != nil {
+		reporter.Errorf("Failed to get clusters: %v", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	reporter.Debugf("Loading the upgrade policies of %d clusters", len(clusters))
+	scheduled, errs := clusterprovider.GetScheduledUpgrades(ocmClient, clusters)
+	for _, err := range errs {
+		reporter.Warnf("%v", err)
+	}
+	scheduled = clusterprovider.FilterUpgrades(scheduled, filter)
+	if len(scheduled) == 0 {
+		reporter.Infof("There are no scheduled upgrades")
+		return
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "ID\tNAME\tVERSION\tTARGET VERSION\tNEXT RUN\tSCHEDULE\tSTATE\n")
+	for _, upgrade := range scheduled {
+		schedule := upgrade.Policy.ScheduleType()
+		if cron := upgrade.Policy.Schedule(); cron != "" {
+			schedule = cron
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			upgrade.Cluster.ID(),
+			upgrade.Cluster.Name(),
+			versions.GetRawVersion(upgrade.Cluster.Version()),
+			upgrade.Policy.Version(),
+			upgrade.Policy.NextRun().Local().Format("2006-01-02 15:04 MST"),
+			schedule,
+			upgrade.State,
+		)
+	}
+	writer.Flush()
+}
+
 func latestInCurrentMinor(current string, versions []string) string {
 	currentParts := strings.Split(current, ".")
 	currentRev := currentParts[2]
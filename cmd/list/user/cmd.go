@@ -19,6 +19,7 @@ package user
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 	"text/tabwriter"
 
@@ -26,13 +27,17 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/pkg/aws"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/users"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
 )
 
 var args struct {
 	clusterKey string
+	roles      []string
 }
 
 var Cmd = &cobra.Command{
@@ -41,7 +46,10 @@ var Cmd = &cobra.Command{
 	Short:   "List cluster users",
 	Long:    "List administrative cluster users.",
 	Example: `  # List all users on a cluster named "mycluster"
-  rosa list users --cluster=mycluster`,
+  rosa list users --cluster=mycluster
+
+  # List only the users with the dedicated-admins role
+  rosa list users --cluster=mycluster --role=dedicated-admins`,
 	Run: run,
 }
 
@@ -56,6 +64,14 @@ func init() {
 		"Name or ID of the cluster to list the users of (required).",
 	)
 	Cmd.MarkFlagRequired("cluster")
+
+	flags.StringSliceVar(
+		&args.roles,
+		"role",
+		nil,
+		"Only list the users that have one of the given roles, for example: "+
+			"--role=cluster-admins,dedicated-admins.",
+	)
 }
 
 func run(_ *cobra.Command, _ []string) {
@@ -71,7 +87,7 @@ func run(_ *cobra.Command, _ []string) {
 				"must contain only letters, digits, dashes and underscores",
 			clusterKey,
 		)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	// Create the AWS client:
@@ -80,13 +96,13 @@ func run(_ *cobra.Command, _ []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	awsCreator, err := awsClient.GetCreator()
 	if err != nil {
 		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	// Create the client for the OCM API:
@@ -95,7 +111,7 @@ func run(_ *cobra.Command, _ []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
 	}
 	defer func() {
 		err = ocmConnection.Close()
@@ -112,61 +128,74 @@ func run(_ *cobra.Command, _ []string) {
 	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
 	if err != nil {
 		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	if cluster.State() != cmv1.ClusterStateReady {
 		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeConflict)
+	}
+
+	reporter.Debugf("Loading groups for cluster '%s'", clusterKey)
+	clusterGroups, err := ocm.GetGroups(clustersCollection, cluster.ID())
+	if err != nil {
+		reporter.Errorf("Failed to get groups for cluster '%s': %v", clusterKey, err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	// Only the roles requested by the user are listed, accepting singular aliases. Roles that
+	// aren't groups of the cluster are rejected, instead of silently listing no users:
+	roleFilter := make(map[string]bool, len(args.roles))
+	for _, role := range args.roles {
+		group, err := users.ResolveGroup(role, users.GroupIDs(clusterGroups))
+		if err != nil {
+			reporter.Errorf("%v", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		roleFilter[group] = true
 	}
 
-	var clusterAdmins []*cmv1.User
-	if cluster.ClusterAdminEnabled() {
-		reporter.Debugf("Loading users for cluster '%s'", clusterKey)
-		// Load cluster-admins for this cluster
-		clusterAdmins, err = ocm.GetUsers(clustersCollection, cluster.ID(), "cluster-admins")
+	groups := make(map[string][]string)
+	for _, group := range clusterGroups {
+		if len(roleFilter) > 0 && !roleFilter[group.ID()] {
+			continue
+		}
+		if group.ID() == "cluster-admins" && !cluster.ClusterAdminEnabled() {
+			continue
+		}
+		reporter.Debugf("Loading users of group '%s' for cluster '%s'", group.ID(), clusterKey)
+		groupUsers, err := ocm.GetUsers(clustersCollection, cluster.ID(), group.ID())
 		if err != nil {
-			reporter.Errorf("Failed to get cluster-admins for cluster '%s': %v", clusterKey, err)
-			os.Exit(1)
+			reporter.Errorf("Failed to get %s for cluster '%s': %v", group.ID(), clusterKey, err)
+			exit.Exit(rerrors.ExitCode(err))
 		}
-		// Remove cluster-admin user
-		for i, user := range clusterAdmins {
+		for _, user := range groupUsers {
+			// Skip the cluster-admin user created by 'rosa create admin'
 			if user.ID() == "cluster-admin" {
-				clusterAdmins = append(clusterAdmins[:i], clusterAdmins[i+1:]...)
+				continue
 			}
+			groups[user.ID()] = append(groups[user.ID()], group.ID())
 		}
 	}
 
-	// Load dedicated-admins for this cluster
-	dedicatedAdmins, err := ocm.GetUsers(clustersCollection, cluster.ID(), "dedicated-admins")
-	if err != nil {
-		reporter.Errorf("Failed to get dedicated-admins for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
-	}
-
-	if len(clusterAdmins) == 0 && len(dedicatedAdmins) == 0 {
+	if len(groups) == 0 {
 		reporter.Warnf("There are no users configured for cluster '%s'", clusterKey)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeGeneric)
 	}
 
-	groups := make(map[string][]string)
-	for _, user := range clusterAdmins {
-		groups[user.ID()] = []string{"cluster-admins"}
-	}
-	for _, user := range dedicatedAdmins {
-		if _, ok := groups[user.ID()]; ok {
-			groups[user.ID()] = []string{"cluster-admins", "dedicated-admins"}
-		} else {
-			groups[user.ID()] = []string{"dedicated-admins"}
-		}
+	usernames := make([]string, 0, len(groups))
+	for username := range groups {
+		usernames = append(usernames, username)
 	}
+	sort.Strings(usernames)
 
 	// Create the writer that will be used to print the tabulated results:
 	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 	fmt.Fprintf(writer, "ID\t\tGROUPS\n")
-
-	for u, r := range groups {
-		fmt.Fprintf(writer, "%s\t\t%s\n", u, strings.Join(r, ", "))
-		writer.Flush()
+	for _, username := range usernames {
+		roles := groups[username]
+		sort.Strings(roles)
+		fmt.Fprintf(writer, "%s\t\t%s\n", username, strings.Join(roles, ", "))
 	}
+	writer.Flush()
 }
@@ -23,6 +23,9 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/moactl/pkg/aws"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm"
 	"github.com/openshift/moactl/pkg/ocm/versions"
@@ -31,6 +34,7 @@ import (
 
 var args struct {
 	channelGroup string
+	clusterKey   string
 }
 
 var Cmd = &cobra.Command{
@@ -39,7 +43,13 @@ var Cmd = &cobra.Command{
 	Short:   "List available versions",
 	Long:    "List versions of OpenShift that are available for creating clusters.",
 	Example: `  # List all OpenShift versions
-  rosa list versions`,
+  rosa list versions
+
+  # List the OpenShift versions of the candidate channel group
+  rosa list versions --channel-group=candidate
+
+  # List the OpenShift versions and show which ones cluster "mycluster" can be upgraded to
+  rosa list versions --cluster=mycluster`,
 	Run: run,
 }
 
@@ -49,7 +59,16 @@ func init() {
 		&args.channelGroup,
 		"channel-group",
 		versions.DefaultChannelGroup,
-		"List only versions from the specified channel group",
+		"List only versions from the specified channel group. Use an empty value to list the "+
+			"versions of all the channel groups.",
+	)
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of a cluster. Versions that the cluster can be upgraded to are marked as "+
+			"upgrade targets, and the channel group of the cluster is used by default.",
 	)
 }
 
@@ -57,13 +76,31 @@ func run(cmd *cobra.Command, _ []string) {
 	reporter := rprtr.CreateReporterOrExit()
 	logger := logging.CreateLoggerOrExit(reporter)
 
+	err := versions.ValidateChannelGroup(args.channelGroup)
+	if err != nil {
+		reporter.Errorf("%v", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := args.clusterKey
+	if clusterKey != "" && !ocm.IsValidClusterKey(clusterKey) {
+		reporter.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+		exit.Exit(rerrors.ExitCodeValidation)
+	}
+
 	// Create the client for the OCM API:
 	ocmConnection, err := ocm.NewConnection().
 		Logger(logger).
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
 	}
 	defer func() {
 		err = ocmConnection.Close()
@@ -75,32 +112,111 @@ func run(cmd *cobra.Command, _ []string) {
 	// Get the client for the OCM collection of clusters:
 	ocmClient := ocmConnection.ClustersMgmt().V1()
 
-	// Try to find the cluster:
+	channelGroup := args.channelGroup
+	if channelGroup != "" && cmd.Flags().Changed("channel-group") {
+		err = versions.ValidateAvailableChannelGroup(ocmClient, channelGroup)
+		if err != nil {
+			reporter.Errorf("%v", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+	}
+	var upgradeTargets map[string]bool
+	if clusterKey != "" {
+		// Create the AWS client:
+		awsClient, err := aws.NewClient().
+			Logger(logger).
+			Build()
+		if err != nil {
+			reporter.Errorf("Failed to create AWS client: %v", err)
+			exit.Exit(rerrors.ExitCodeAWSAuth)
+		}
+
+		awsCreator, err := awsClient.GetCreator()
+		if err != nil {
+			reporter.Errorf("Failed to get AWS creator: %v", err)
+			exit.Exit(rerrors.ExitCodeAWSAuth)
+		}
+
+		// Try to find the cluster:
+		reporter.Debugf("Loading cluster '%s'", clusterKey)
+		cluster, err := ocm.GetCluster(ocmClient.Clusters(), clusterKey, awsCreator.ARN)
+		if err != nil {
+			reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+
+		// Use the channel group of the cluster by default. Upgrades to other channel groups are
+		// possible with 'rosa upgrade cluster --channel-group':
+		if !cmd.Flags().Changed("channel-group") {
+			channelGroup = cluster.Version().ChannelGroup()
+		}
+
+		reporter.Debugf("Fetching available upgrades for cluster '%s'", clusterKey)
+		availableUpgrades, err := versions.GetAvailableUpgrades(ocmClient,
+			versions.GetVersionIDInChannelGroup(cluster, channelGroup))
+		if rerrors.ExitCode(err) == rerrors.ExitCodeNotFound {
+			reporter.Warnf("Version %s of cluster '%s' isn't available in channel group '%s'",
+				cluster.OpenshiftVersion(), clusterKey, channelGroup)
+			availableUpgrades, err = []string{}, nil
+		}
+		if err != nil {
+			reporter.Errorf("Failed to find available upgrades: %v", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		upgradeTargets = make(map[string]bool, len(availableUpgrades))
+		for _, availableUpgrade := range availableUpgrades {
+			upgradeTargets[availableUpgrade] = true
+		}
+	}
+
 	reporter.Debugf("Fetching versions")
-	versions, err := versions.GetVersions(ocmClient, args.channelGroup)
+	versionList, err := versions.GetVersions(ocmClient, channelGroup)
 	if err != nil {
 		reporter.Errorf("Failed to fetch versions: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
-	if len(versions) == 0 {
+	if len(versionList) == 0 {
 		reporter.Warnf("There are no OpenShift versions available")
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeGeneric)
 	}
 
 	// Create the writer that will be used to print the tabulated results:
 	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(writer, "ID\t\tDEFAULT\n")
+	if upgradeTargets != nil {
+		fmt.Fprintf(writer, "VERSION\tCHANNEL GROUP\tDEFAULT\tUPGRADE TARGET\n")
+	} else {
+		fmt.Fprintf(writer, "VERSION\tCHANNEL GROUP\tDEFAULT\n")
+	}
 
-	for _, version := range versions {
+	for _, version := range versionList {
 		if !version.Enabled() {
 			continue
 		}
-		fmt.Fprintf(writer,
-			"%s\t\t%t\n",
-			version.ID(),
-			version.Default(),
-		)
+		rawVersion := versions.GetRawVersion(version)
+		if upgradeTargets != nil {
+			fmt.Fprintf(writer,
+				"%s\t%s\t%s\t%s\n",
+				rawVersion,
+				version.ChannelGroup(),
+				yesNo(version.Default()),
+				yesNo(upgradeTargets[rawVersion]),
+			)
+		} else {
+			fmt.Fprintf(writer,
+				"%s\t%s\t%s\n",
+				rawVersion,
+				version.ChannelGroup(),
+				yesNo(version.Default()),
+			)
+		}
 	}
 	writer.Flush()
 }
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
@@ -24,9 +24,13 @@ import (
 	sdk "github.com/openshift-online/ocm-sdk-go"
 	"github.com/spf13/cobra"
 
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/interactive"
 	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/auth"
+	"github.com/openshift/moactl/pkg/ocm/cache"
 	"github.com/openshift/moactl/pkg/ocm/config"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
 )
@@ -42,6 +46,7 @@ var args struct {
 	env          string
 	token        string
 	insecure     bool
+	deviceCode   bool
 }
 
 var Cmd = &cobra.Command{
@@ -54,12 +59,17 @@ var Cmd = &cobra.Command{
 		"\t2. Environment variable (ROSA_TOKEN)\n"+
 		"\t3. Environment variable (OCM_TOKEN)\n"+
 		"\t4. Configuration file\n"+
-		"\t5. Command-line prompt\n", uiTokenPage),
+		"\t5. Command-line prompt\n\n"+
+		"On machines without a browser use the '--use-device-code' flag to log in by approving "+
+		"the request from any other device.", uiTokenPage),
 	Example: `  # Login to the OpenShift staging API with an existing token
   rosa login --env staging --token=$OFFLINE_ACCESS_TOKEN
 
   # Switch environments with an already logged-in account
-  rosa login --env production`,
+  rosa login --env production
+
+  # Login from a headless machine approving the request in a browser elsewhere
+  rosa login --use-device-code`,
 	Run: run,
 }
 
@@ -117,6 +127,13 @@ func init() {
 		"Enables insecure communication with the server. This disables verification of TLS "+
 			"certificates and host names.",
 	)
+	flags.BoolVar(
+		&args.deviceCode,
+		"use-device-code",
+		false,
+		"Log in using the OAuth device code flow. A verification URL and code are displayed, "+
+			"which can be approved from a browser in any other device.",
+	)
 }
 
 func run(cmd *cobra.Command, argv []string) {
@@ -126,21 +143,25 @@ func run(cmd *cobra.Command, argv []string) {
 	// Check mandatory options:
 	if args.env == "" {
 		reporter.Errorf("Option '--env' is mandatory")
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	// Load the configuration file:
 	cfg, err := config.Load()
 	if err != nil {
 		reporter.Errorf("Failed to load config file: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 	if cfg == nil {
 		cfg = new(config.Config)
 	}
 
 	token := args.token
-	haveReqs := token != ""
+	if args.deviceCode && token != "" {
+		reporter.Errorf("Options '--token' and '--use-device-code' are mutually exclusive")
+		exit.Exit(rerrors.ExitCodeValidation)
+	}
+	haveReqs := token != "" || args.deviceCode
 
 	// Verify environment variables:
 	if !haveReqs {
@@ -156,7 +177,7 @@ func run(cmd *cobra.Command, argv []string) {
 		armed, err := cfg.Armed()
 		if err != nil {
 			reporter.Errorf("Failed to verify configuration: %v", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCode(err))
 		}
 		haveReqs = armed
 	}
@@ -167,17 +188,18 @@ func run(cmd *cobra.Command, argv []string) {
 		token, err = interactive.GetPassword(interactive.Input{
 			Question: "Copy the token and paste it here",
 			Required: true,
+			Flag:     "token",
 		})
 		if err != nil {
 			reporter.Errorf("Failed to parse token: %v", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCode(err))
 		}
 		haveReqs = token != ""
 	}
 
 	if !haveReqs {
 		reporter.Errorf("Failed to login to OCM. See 'rosa login --help' for information.")
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeGeneric)
 	}
 
 	// Apply the default OpenID details if not explicitly provided by the user:
@@ -186,6 +208,9 @@ func run(cmd *cobra.Command, argv []string) {
 		tokenURL = args.tokenURL
 	}
 	clientID := sdk.DefaultClientID
+	if args.deviceCode {
+		clientID = auth.DeviceClientID
+	}
 	if args.clientID != "" {
 		clientID = args.clientID
 	}
@@ -211,14 +236,14 @@ func run(cmd *cobra.Command, argv []string) {
 		jwtToken, _, err := parser.ParseUnverified(token, jwt.MapClaims{})
 		if err != nil {
 			reporter.Errorf("Failed to parse token '%s': %v", token, err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCode(err))
 		}
 
 		// Put the token in the place of the configuration that corresponds to its type:
 		typ, err := tokenType(jwtToken)
 		if err != nil {
 			reporter.Errorf("Failed to extract type from 'typ' claim of token '%s': %v", token, err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCode(err))
 		}
 		switch typ {
 		case "Bearer":
@@ -229,13 +254,41 @@ func run(cmd *cobra.Command, argv []string) {
 			cfg.RefreshToken = token
 		case "":
 			reporter.Errorf("Don't know how to handle empty type in token '%s'", token)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeGeneric)
 		default:
 			reporter.Errorf("Don't know how to handle token type '%s' in token '%s'", typ, token)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeGeneric)
 		}
 	}
 
+	if args.deviceCode {
+		flow := &auth.DeviceFlow{
+			TokenURL: tokenURL,
+			ClientID: clientID,
+			Scopes:   args.scopes,
+			Insecure: args.insecure,
+		}
+		code, err := flow.RequestCode()
+		if err != nil {
+			reporter.Errorf("%v", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		verificationURL := code.VerificationURIComplete
+		if verificationURL == "" {
+			verificationURL = code.VerificationURI
+		}
+		fmt.Printf("To login to your Red Hat account, open the following URL in a browser "+
+			"and enter the code '%s':\n\n  %s\n\n", code.UserCode, verificationURL)
+		reporter.Infof("Waiting for the request to be approved...")
+		accessToken, refreshToken, err := flow.PollToken(code)
+		if err != nil {
+			reporter.Errorf("Failed to login to OCM: %v", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		cfg.AccessToken = accessToken
+		cfg.RefreshToken = refreshToken
+	}
+
 	// Create a connection and get the token to verify that the crendentials are correct:
 	connection, err := ocm.NewConnection().
 		Config(cfg).
@@ -243,7 +296,7 @@ func run(cmd *cobra.Command, argv []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
 	}
 	defer func() {
 		err = connection.Close()
@@ -254,7 +307,7 @@ func run(cmd *cobra.Command, argv []string) {
 	accessToken, refreshToken, err := connection.Tokens()
 	if err != nil {
 		reporter.Errorf("Failed to get token: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	// Save the configuration:
@@ -263,13 +316,19 @@ func run(cmd *cobra.Command, argv []string) {
 	err = config.Save(cfg)
 	if err != nil {
 		reporter.Errorf("Failed to save config file: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	// The cached responses may belong to a different account or environment:
+	err = cache.Clear()
+	if err != nil {
+		reporter.Warnf("%v", err)
 	}
 
 	username, err := cfg.GetData("username")
 	if err != nil {
 		reporter.Errorf("Failed to get username: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	reporter.Infof("Logged in as '%s' on '%s'", username, cfg.URL)
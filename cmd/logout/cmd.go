@@ -21,6 +21,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/moactl/pkg/ocm/cache"
 	"github.com/openshift/moactl/pkg/ocm/config"
 )
 
@@ -38,5 +39,11 @@ func run(cmd *cobra.Command, argv []string) error {
 		return fmt.Errorf("Failed to remove config file: %v", err)
 	}
 
+	// Remove the cached responses, as they belong to the account that logged out:
+	err = cache.Clear()
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -18,7 +18,6 @@ package install
 
 import (
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
@@ -29,6 +28,9 @@ import (
 
 	"github.com/openshift/moactl/pkg/aws"
 	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/config"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
@@ -62,6 +64,8 @@ func init() {
 		"",
 		"Name or ID of the cluster to get logs for.",
 	)
+	// The cluster can also be given as the first argument:
+	config.MarkClusterArg(Cmd)
 
 	flags.IntVar(
 		&args.tail,
@@ -95,7 +99,7 @@ func run(cmd *cobra.Command, argv []string) {
 				"Expected exactly one command line argument or flag containing the name " +
 					"or identifier of the cluster",
 			)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 		clusterKey = argv[0]
 	}
@@ -108,7 +112,7 @@ func run(cmd *cobra.Command, argv []string) {
 				"must contain only letters, digits, dashes and underscores",
 			clusterKey,
 		)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	// Create the AWS client:
@@ -117,13 +121,13 @@ func run(cmd *cobra.Command, argv []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	awsCreator, err := awsClient.GetCreator()
 	if err != nil {
 		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	// Create the client for the OCM API:
@@ -132,7 +136,7 @@ func run(cmd *cobra.Command, argv []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
 	}
 	defer func() {
 		err = ocmConnection.Close()
@@ -149,12 +153,12 @@ func run(cmd *cobra.Command, argv []string) {
 	cluster, err := clusterprovider.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
 	if err != nil {
 		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	if cluster.State() == cmv1.ClusterStateReady {
 		reporter.Infof("Cluster '%s' has been successfully installed", clusterKey)
-		os.Exit(0)
+		exit.Exit(0)
 	}
 
 	pendingMessage := fmt.Sprintf(
@@ -167,10 +171,10 @@ func run(cmd *cobra.Command, argv []string) {
 				"Cluster '%s' has been in %s state for too long. Please contact support",
 				clusterKey, cluster.State(),
 			)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeGeneric)
 		}
 		reporter.Warnf(pendingMessage)
-		os.Exit(0)
+		exit.Exit(0)
 	}
 
 	// Get logs from Hive
@@ -180,7 +184,7 @@ func run(cmd *cobra.Command, argv []string) {
 			reporter.Infof(pendingMessage)
 		} else {
 			reporter.Errorf("Failed to get logs for cluster '%s': %v", clusterKey, err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCode(err))
 		}
 	}
 	printLog(logs, nil)
@@ -188,7 +192,7 @@ func run(cmd *cobra.Command, argv []string) {
 	if watch {
 		if cluster.State() == cmv1.ClusterStateReady {
 			reporter.Infof("Cluster '%s' is successfully installed", clusterKey)
-			os.Exit(0)
+			exit.Exit(0)
 		}
 
 		spin := spinner.New(spinner.CharSets[9], 100*time.Millisecond)
@@ -199,7 +203,7 @@ func run(cmd *cobra.Command, argv []string) {
 			state, _ := ocm.GetClusterState(clustersCollection, cluster.ID())
 			if state == cmv1.ClusterStateError {
 				reporter.Errorf("There was an error installing cluster '%s'", clusterKey)
-				os.Exit(1)
+				exit.Exit(rerrors.ExitCodeGeneric)
 			}
 			if state == cmv1.ClusterStateReady {
 				reporter.Infof("Cluster '%s' is now ready", clusterKey)
@@ -211,7 +215,7 @@ func run(cmd *cobra.Command, argv []string) {
 		if err != nil {
 			if errors.GetType(err) != errors.NotFound {
 				reporter.Errorf(fmt.Sprintf("Failed to watch logs for cluster '%s': %v", clusterKey, err))
-				os.Exit(1)
+				exit.Exit(rerrors.ExitCode(err))
 			}
 		}
 		printLog(response, spin)
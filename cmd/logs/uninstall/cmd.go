@@ -18,7 +18,6 @@ package uninstall
 
 import (
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
@@ -29,6 +28,9 @@ import (
 
 	"github.com/openshift/moactl/pkg/aws"
 	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/config"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
@@ -62,6 +64,8 @@ func init() {
 		"",
 		"Name or ID of the cluster to get logs for.",
 	)
+	// The cluster can also be given as the first argument:
+	config.MarkClusterArg(Cmd)
 
 	flags.IntVar(
 		&args.tail,
@@ -95,7 +99,7 @@ func run(cmd *cobra.Command, argv []string) {
 				"Expected exactly one command line argument or flag containing the name " +
 					"or identifier of the cluster",
 			)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeValidation)
 		}
 		clusterKey = argv[0]
 	}
@@ -108,7 +112,7 @@ func run(cmd *cobra.Command, argv []string) {
 				"must contain only letters, digits, dashes and underscores",
 			clusterKey,
 		)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	// Create the AWS client:
@@ -117,13 +121,13 @@ func run(cmd *cobra.Command, argv []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	awsCreator, err := awsClient.GetCreator()
 	if err != nil {
 		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	// Create the client for the OCM API:
@@ -132,7 +136,7 @@ func run(cmd *cobra.Command, argv []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
 	}
 	defer func() {
 		err = ocmConnection.Close()
@@ -149,12 +153,12 @@ func run(cmd *cobra.Command, argv []string) {
 	cluster, err := clusterprovider.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
 	if err != nil {
 		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	if cluster.State() != cmv1.ClusterStateUninstalling && !watch {
 		reporter.Warnf("Cluster '%s' is not currently uninstalling", clusterKey)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeGeneric)
 	}
 
 	// Get logs from Hive
@@ -164,7 +168,7 @@ func run(cmd *cobra.Command, argv []string) {
 			reporter.Warnf("Logs for cluster '%s' are not available", clusterKey)
 		} else {
 			reporter.Errorf("Failed to get logs for cluster '%s': %v", clusterKey, err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCode(err))
 		}
 	}
 	printLog(logs, nil)
@@ -185,7 +189,7 @@ func run(cmd *cobra.Command, argv []string) {
 		if err != nil {
 			if errors.GetType(err) != errors.NotFound {
 				reporter.Errorf(fmt.Sprintf("Failed to watch logs for cluster '%s': %v", clusterKey, err))
-				os.Exit(1)
+				exit.Exit(rerrors.ExitCode(err))
 			}
 		}
 		printLog(response, spin)
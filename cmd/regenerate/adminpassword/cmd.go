@@ -0,0 +1,193 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adminpassword
+
+import (
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
+	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/admin"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+)
+
+var args struct {
+	clusterKey string
+	password   string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "admin-password",
+	Short: "Regenerate the password of the admin user",
+	Long: "Replaces the password of the cluster-admin user with the given password or with an " +
+		"auto-generated one. The previous password stops working as soon as the new one is active.",
+	Example: `  # Generate a new password for the admin user of a cluster named "mycluster"
+  rosa regenerate admin-password --cluster=mycluster
+
+  # Set a specific password for the admin user
+  rosa regenerate admin-password --cluster=mycluster --password=MySecretPassword-1`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster of the admin user (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	flags.StringVarP(
+		&args.password,
+		"password",
+		"p",
+		"",
+		"New password of the admin user. It must be at least 14 characters long, without "+
+			"whitespace, and contain uppercase letters, lowercase letters, and digits or symbols. "+
+			"A random password is generated if not specified.",
+	)
+}
+
+func run(cmd *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := args.clusterKey
+	if !ocm.IsValidClusterKey(clusterKey) {
+		reporter.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+		exit.Exit(rerrors.ExitCodeValidation)
+	}
+
+	// Validate the password before doing any request:
+	password := args.password
+	if password != "" {
+		err := admin.ValidatePassword(password)
+		if err != nil {
+			reporter.Errorf("%v", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+	}
+
+	// Create the AWS client:
+	awsClient, err := aws.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create AWS client: %v", err)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
+	}
+
+	awsCreator, err := awsClient.GetCreator()
+	if err != nil {
+		reporter.Errorf("Failed to get AWS creator: %v", err)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
+	}
+
+	// Create the client for the OCM API:
+	ocmConnection, err := ocm.NewConnection().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
+	}
+	defer func() {
+		err = ocmConnection.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
+
+	// Get the client for the OCM collection of clusters:
+	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
+	if err != nil {
+		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	if cluster.State() != cmv1.ClusterStateReady {
+		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
+		exit.Exit(rerrors.ExitCodeConflict)
+	}
+
+	// Try to find the htpasswd identity provider:
+	reporter.Debugf("Loading '%s' identity provider", admin.IdpName)
+	idp, err := admin.GetIdentityProvider(clustersCollection, cluster.ID())
+	if err != nil {
+		reporter.Errorf("Failed to get '%s' identity provider for cluster '%s': %v",
+			admin.IdpName, clusterKey, err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	if idp == nil {
+		reporter.Errorf("There is no admin on cluster '%s'. To create it run the following command:\n"+
+			"   rosa create admin -c %s", clusterKey, clusterKey)
+		exit.Exit(rerrors.ExitCodeNotFound)
+	}
+
+	confirmed, err := confirm.Confirm("replace the password of the %s user on cluster %s",
+		idp.Htpasswd().Username(), clusterKey)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	if !confirmed {
+		exit.Exit(0)
+	}
+
+	if password == "" {
+		password, err = admin.GeneratePassword()
+		if err != nil {
+			reporter.Errorf("Failed to generate a random password")
+			exit.Exit(rerrors.ExitCodeGeneric)
+		}
+	}
+
+	reporter.Debugf("Updating the password of '%s' on cluster '%s'", idp.Htpasswd().Username(), clusterKey)
+	err = admin.UpdatePassword(clustersCollection, cluster.ID(), idp, password)
+	if err != nil {
+		reporter.Errorf("Failed to update the password of the admin on cluster '%s': %v", clusterKey, err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	reporter.Infof("The password of the admin on cluster '%s' has been replaced. "+
+		"It may take up to a minute for the new password to become active.", clusterKey)
+	if args.password == "" {
+		reporter.Infof("Please securely store this generated password.")
+	}
+	reporter.Infof("To login, run the following command:\n"+
+		"   oc login %s \\\n   --username %s \\\n   --password %s",
+		cluster.API().URL(), idp.Htpasswd().Username(), password)
+}
@@ -0,0 +1,125 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+// Regular expression used to make sure that the identifier of the cluster given by the user is
+// safe to use in the path of requests:
+var clusterIDRE = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+
+var args struct {
+	clusterID string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "cluster [ID]",
+	Short: "Register an existing cluster to be managed by rosa",
+	Long: "Register an existing cluster that wasn't created with rosa, for example from the " +
+		"OpenShift Cluster Manager console, so that it can be managed with the rest of the " +
+		"commands. The cluster must run in the AWS account of the current AWS credentials, and " +
+		"it is associated to the current AWS user.",
+	Example: `  # Register the cluster with identifier "1a2b3c4d5e6f"
+  rosa register cluster 1a2b3c4d5e6f
+
+  # Register a cluster and then list its machine pools
+  rosa register cluster --cluster=1a2b3c4d5e6f
+  rosa list machinepools --cluster=1a2b3c4d5e6f`,
+	Run: runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterID,
+		"cluster",
+		"c",
+		"",
+		"ID of the cluster to register.",
+	)
+}
+
+// validate checks the command line arguments and keeps the identifier of the cluster.
+func validate(r *runner.Runtime) error {
+	clusterID := args.clusterID
+	if clusterID == "" {
+		if len(r.Args) != 1 {
+			return rerrors.ValidationErrorf(
+				"Expected exactly one command line argument or flag containing the identifier " +
+					"of the cluster",
+			)
+		}
+		clusterID = r.Args[0]
+	}
+	if !clusterIDRE.MatchString(clusterID) {
+		return rerrors.ValidationErrorf("Cluster identifier '%s' isn't valid: it must contain "+
+			"only letters and digits", clusterID)
+	}
+	args.clusterID = clusterID
+	return nil
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	clusterID := args.clusterID
+
+	// Clusters that aren't managed by rosa can't be found by name, as they aren't associated
+	// to the AWS user yet, so they are loaded directly:
+	reporter.Debugf("Loading cluster '%s'", clusterID)
+	response, err := r.OCMConnection.ClustersMgmt().V1().Clusters().Cluster(clusterID).Get().
+		SendContext(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterID,
+			rerrors.FromOCM(response.Error(), err))
+	}
+	cluster := response.Body()
+
+	err = c.ValidateRegistration(cluster, r.Creator)
+	if err != nil {
+		return err
+	}
+
+	confirmed, err := confirm.Confirm("register cluster %s (%s) to be managed by %s", clusterID,
+		cluster.Name(), r.Creator.ARN)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	reporter.Debugf("Registering cluster '%s'", clusterID)
+	err = c.RegisterCluster(r.OCMConnection, cluster, r.Creator.ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to register cluster '%s': %w", clusterID, err)
+	}
+	reporter.Infof("Cluster '%s' is now managed by rosa for '%s'", cluster.Name(), r.Creator.ARN)
+	reporter.Infof("To show its details run 'rosa describe cluster %s'", cluster.Name())
+	return nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package breakglasscredential
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/breakglass"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	clusterKey string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "break-glass-credentials",
+	Aliases: []string{"break-glass-credential", "breakglasscredentials", "breakglasscredential"},
+	Short:   "Revoke the break glass credentials of a cluster",
+	Long: "Revoke all the active break glass credentials of a cluster, so that they can't be used " +
+		"anymore before they expire.",
+	Example: `  # Revoke the break glass credentials of the cluster named "mycluster"
+  rosa revoke break-glass-credentials --cluster=mycluster`,
+	Run: runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to revoke the break glass credentials of (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+}
+
+func validate(r *runner.Runtime) error {
+	return validateClusterKey()
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	clusterKey := args.clusterKey
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(r.OCMConnection.ClustersMgmt().V1().Clusters(), clusterKey,
+		r.Creator.ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	reporter.Debugf("Loading break glass credentials of cluster '%s'", clusterKey)
+	credentials, err := breakglass.GetCredentials(ctx, r.OCMConnection, cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get break glass credentials of cluster '%s': %w", clusterKey,
+			err)
+	}
+	active := breakglass.FilterActive(credentials)
+	if len(active) == 0 {
+		reporter.Infof("There are no active break glass credentials for cluster '%s'", clusterKey)
+		return nil
+	}
+
+	confirmed, err := confirm.Confirm("revoke %d break glass credentials of cluster %s",
+		len(active), clusterKey)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	reporter.Debugf("Revoking break glass credentials of cluster '%s'", clusterKey)
+	err = breakglass.RevokeCredentials(ctx, r.OCMConnection, cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to revoke break glass credentials of cluster '%s': %w", clusterKey,
+			err)
+	}
+	for _, credential := range active {
+		reporter.Infof("Revoking break glass credential '%s' with username '%s'", credential.ID,
+			credential.Username)
+	}
+	reporter.Infof("Break glass credentials of cluster '%s' are being revoked, run 'rosa list "+
+		"break-glass-credentials --cluster=%s --all' to check their status", clusterKey, clusterKey)
+	return nil
+}
+
+// validateClusterKey checks that the cluster key given by the user is reasonably safe so that there
+// is no risk of SQL injection.
+func validateClusterKey() error {
+	if !c.IsValidClusterKey(args.clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			args.clusterKey,
+		)
+	}
+	return nil
+}
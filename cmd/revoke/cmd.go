@@ -19,8 +19,9 @@ package revoke
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/moactl/cmd/revoke/breakglasscredential"
 	"github.com/openshift/moactl/cmd/revoke/user"
-	"github.com/openshift/moactl/pkg/confirm"
+	"github.com/openshift/moactl/pkg/config"
 )
 
 var Cmd = &cobra.Command{
@@ -30,8 +31,9 @@ var Cmd = &cobra.Command{
 }
 
 func init() {
-	flags := Cmd.PersistentFlags()
-	confirm.AddFlag(flags)
+	// The subcommands change or delete clusters, so they don't use the saved default cluster:
+	config.MarkExplicitCluster(Cmd)
 
+	Cmd.AddCommand(breakglasscredential.Cmd)
 	Cmd.AddCommand(user.Cmd)
 }
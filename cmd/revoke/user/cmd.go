@@ -17,38 +17,44 @@ limitations under the License.
 package user
 
 import (
-	"fmt"
-	"os"
+	"context"
+	"strings"
 
 	"github.com/spf13/cobra"
 
-	"github.com/openshift/moactl/pkg/aws"
 	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/moactl"
 	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/users"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/runner"
 )
 
 var args struct {
 	clusterKey string
-	username   string
+	usernames  []string
+	usersFile  string
 }
 
 var Cmd = &cobra.Command{
 	Use:     "user ROLE [flags]",
 	Aliases: []string{"role"},
 	Short:   "Revoke role from users",
-	Long:    "Revoke role from cluster user",
+	Long:    "Revoke role from cluster user, which can be any group of the cluster",
 	Example: `  # Revoke cluster-admin role from a user
   rosa revoke user cluster-admins --user=myusername --cluster=mycluster
 
   # Revoke dedicated-admin role from a user
-  rosa revoke user dedicate-admins --user=myusername --cluster=mycluster`,
-	Run: run,
-}
+  rosa revoke user dedicate-admins --user=myusername --cluster=mycluster
 
-var validRoles = []string{"cluster-admins", "dedicated-admins"}
-var validRolesAliases = []string{"cluster-admin", "dedicated-admin"}
+  # Revoke dedicated-admin role from all the users listed in a file, one per line
+  rosa revoke user dedicated-admins --users-file=users.txt --cluster=mycluster`,
+	Run:               run,
+	ValidArgsFunction: runner.Complete(completeRole, runner.WithAWS(), runner.WithOCM()),
+}
 
 func init() {
 	flags := Cmd.Flags()
@@ -62,39 +68,37 @@ func init() {
 	)
 	Cmd.MarkFlagRequired("cluster")
 
-	flags.StringVarP(
-		&args.username,
+	flags.StringSliceVarP(
+		&args.usernames,
 		"user",
 		"u",
+		nil,
+		"Username to revoke the role from. Multiple users can be comma separated, for example: "+
+			"--user=user1,user2.",
+	)
+
+	flags.StringVar(
+		&args.usersFile,
+		"users-file",
 		"",
-		"Username to revoke the role from (required).",
+		"Path to a file containing the usernames to revoke the role from, one per line.",
 	)
-	Cmd.MarkFlagRequired("user")
 }
 
 func run(_ *cobra.Command, argv []string) {
 	reporter := rprtr.CreateReporterOrExit()
 	logger := logging.CreateLoggerOrExit(reporter)
 
-	// Check that the cluster key (name, identifier or external identifier) given by the user
-	// is reasonably safe so that there is no risk of SQL injection:
 	clusterKey := args.clusterKey
-	if !ocm.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
-			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
-				"must contain only letters, digits, dashes and underscores",
-			clusterKey,
-		)
-		os.Exit(1)
-	}
 
-	username := args.username
-	if !ocm.IsValidUsername(username) {
-		reporter.Errorf(
-			"username '%s' isn't valid: it must contain only letters, digits, dashes and underscores",
-			username,
-		)
-		os.Exit(1)
+	usernames, err := users.GetUsernames(args.usernames, args.usersFile)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	if len(usernames) == 0 {
+		reporter.Errorf("Expected at least one user in the '--user' or '--users-file' flags")
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
 	if len(argv) != 1 {
@@ -102,77 +106,78 @@ func run(_ *cobra.Command, argv []string) {
 			"Expected exactly one command line argument or flag containing the name " +
 				"of the group or role to grant the user.",
 		)
-		os.Exit(1)
-	}
-	role := argv[0]
-	// Allow role aliases
-	for _, validAlias := range validRolesAliases {
-		if role == validAlias {
-			role = fmt.Sprintf("%ss", role)
-		}
-	}
-	isRoleValid := false
-	// Determine if role is valid
-	for _, validRole := range validRoles {
-		if role == validRole {
-			isRoleValid = true
-		}
-	}
-	if !isRoleValid {
-		reporter.Errorf("Expected at least one of %s", validRoles)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
-	// Create the AWS client:
-	awsClient, err := aws.NewClient().
+	client, err := moactl.NewClient().
 		Logger(logger).
 		Build()
 	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+		reporter.Errorf("%v", err)
+		exit.Exit(rerrors.ExitCode(err))
 	}
+	defer func() {
+		err = client.Close()
+		if err != nil {
+			reporter.Errorf("%v", err)
+		}
+	}()
 
-	awsCreator, err := awsClient.GetCreator()
+	// Check that the role is one of the groups of the cluster:
+	role, err := client.ResolveRole(context.Background(), clusterKey, argv[0])
 	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
+		reporter.Errorf("%v", err)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
-		Build()
+	confirmed, err := confirm.Confirm("revoke role %s from users %s in cluster %s",
+		role, strings.Join(usernames, ", "), clusterKey)
 	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		reporter.Errorf("%s", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	if !confirmed {
+		exit.Exit(0)
 	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-
-	// Get the client for the OCM collection of clusters:
-	clustersCollection := ocmConnection.ClustersMgmt().V1().Clusters()
 
-	// Try to find the cluster:
-	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, awsCreator.ARN)
+	reporter.Debugf("Removing users from group '%s' in cluster '%s'", role, clusterKey)
+	output, err := client.RevokeRole(context.Background(), moactl.UserRoleInput{
+		ClusterKey: clusterKey,
+		Role:       role,
+		Usernames:  usernames,
+	})
 	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		reporter.Errorf("%v", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	for _, result := range output.Results {
+		if result.Err != nil {
+			reporter.Errorf("Failed to revoke '%s' from user '%s' in cluster '%s': %v",
+				role, result.Username, clusterKey, result.Err)
+			continue
+		}
+		reporter.Infof("Revoked role '%s' from user '%s' on cluster '%s'", role, result.Username, clusterKey)
 	}
 
-	if !confirm.Confirm("revoke role %s from user %s in cluster %s", role, username, clusterKey) {
-		os.Exit(0)
+	if failed := output.Failed(); failed > 0 {
+		reporter.Errorf("Failed to revoke role '%s' from %d out of %d users", role, failed, len(usernames))
+		exit.Exit(rerrors.ExitCodeGeneric)
 	}
+}
 
-	reporter.Debugf("Removing user '%s' from group '%s' in cluster '%s'", username, role, clusterKey)
-	res, err := clustersCollection.Cluster(cluster.ID()).Groups().Group(role).Users().User(username).Delete().Send()
+// completeRole returns the names of the groups of the cluster given in the '--cluster' flag.
+func completeRole(_ context.Context, r *runner.Runtime) ([]string, error) {
+	if len(r.Args) > 0 || args.clusterKey == "" {
+		return nil, nil
+	}
+	clustersCollection := r.OCMConnection.ClustersMgmt().V1().Clusters()
+	cluster, err := ocm.GetCluster(clustersCollection, args.clusterKey, r.Creator.ARN)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := ocm.GetGroups(clustersCollection, cluster.ID())
 	if err != nil {
-		reporter.Debugf(err.Error())
-		reporter.Errorf("Failed to revoke '%s' from user '%s' in cluster '%s': %s",
-			role, username, clusterKey, res.Error().Reason())
-		os.Exit(1)
+		return nil, err
 	}
+	return users.GroupIDs(groups), nil
 }
@@ -17,14 +17,19 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"os/exec"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
 	"github.com/openshift/moactl/cmd/completion"
+	configcmd "github.com/openshift/moactl/cmd/config"
 	"github.com/openshift/moactl/cmd/create"
 	"github.com/openshift/moactl/cmd/describe"
 	"github.com/openshift/moactl/cmd/dlt"
@@ -32,24 +37,65 @@ import (
 	"github.com/openshift/moactl/cmd/download"
 	"github.com/openshift/moactl/cmd/edit"
 	"github.com/openshift/moactl/cmd/grant"
+	"github.com/openshift/moactl/cmd/hibernate"
 	"github.com/openshift/moactl/cmd/initialize"
+	"github.com/openshift/moactl/cmd/install"
+	"github.com/openshift/moactl/cmd/link"
 	"github.com/openshift/moactl/cmd/list"
 	"github.com/openshift/moactl/cmd/login"
 	"github.com/openshift/moactl/cmd/logout"
 	"github.com/openshift/moactl/cmd/logs"
+	"github.com/openshift/moactl/cmd/regenerate"
+	"github.com/openshift/moactl/cmd/register"
+	"github.com/openshift/moactl/cmd/resume"
 	"github.com/openshift/moactl/cmd/revoke"
+	"github.com/openshift/moactl/cmd/run"
+	"github.com/openshift/moactl/cmd/status"
+	"github.com/openshift/moactl/cmd/transfer"
+	"github.com/openshift/moactl/cmd/uninstall"
 	"github.com/openshift/moactl/cmd/upgrade"
+	"github.com/openshift/moactl/cmd/validate"
 	"github.com/openshift/moactl/cmd/verify"
 	"github.com/openshift/moactl/cmd/version"
 	"github.com/openshift/moactl/cmd/whoami"
 
 	"github.com/openshift/moactl/pkg/arguments"
+	"github.com/openshift/moactl/pkg/audit"
+	"github.com/openshift/moactl/pkg/config"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
+	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/metrics"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/servicelogs"
+	"github.com/openshift/moactl/pkg/plugin"
+	"github.com/openshift/moactl/pkg/release"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
 )
 
 var root = &cobra.Command{
 	Use:   "rosa",
 	Short: "Command line tool for ROSA.",
-	Long:  "Command line tool for Red Hat OpenShift Service on AWS.",
+	Long: "Command line tool for Red Hat OpenShift Service on AWS.\n\n" +
+		"The value of any flag can also be given with an environment variable named after it, " +
+		"for example ROSA_CLUSTER for '--cluster' or ROSA_NON_INTERACTIVE for " +
+		"'--non-interactive'. Flags given in the command line take precedence over environment " +
+		"variables, which take precedence over the saved settings.",
+	PersistentPreRun: func(cmd *cobra.Command, argv []string) {
+		// Use the environment variables and then the saved settings for the flags that weren't
+		// given in the command line:
+		err := config.ApplyEnv(cmd, argv)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to apply environment variables: %s\n", err)
+			exit.Exit(rerrors.ExitCodeValidation)
+		}
+		err = config.ApplyDefaults(cmd, argv)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to apply saved settings: %s\n", err)
+			exit.Exit(rerrors.ExitCodeGeneric)
+		}
+		audit.Start(cmd, argv)
+	},
 }
 
 func init() {
@@ -60,10 +106,31 @@ func init() {
 	// Add the command line flags:
 	fs := root.PersistentFlags()
 	arguments.AddDebugFlag(fs)
+	arguments.AddLogLevelFlag(fs)
+	arguments.AddColorFlag(fs)
+	arguments.AddProgressFormatFlag(fs)
+	arguments.AddMaxRetriesFlag(fs)
+	arguments.AddRequestTimeoutFlag(fs)
+	arguments.AddMetricsFlags(fs)
 	arguments.AddProfileFlag(fs)
+	arguments.AddRoleFlags(fs)
+	arguments.AddGovCloudFlag(fs)
+	arguments.AddOCMProfileFlag(fs)
+	arguments.AddOwnerARNFlag(fs)
+	arguments.AddYesFlag(fs)
+	arguments.AddNonInteractiveFlag(fs)
+	arguments.AddNoCacheFlag(fs)
+	arguments.AddAuditFlag(fs)
+
+	// Entries of the audit log are added to the OCM service log when requested:
+	audit.SetSender(servicelogs.NewSender())
+
+	// Errors returned by the OCM API are reported with the details needed to open a support case:
+	rprtr.SetErrorDetails(ocm.ErrorDetails)
 
 	// Register the subcommands:
 	root.AddCommand(completion.Cmd)
+	root.AddCommand(configcmd.Cmd)
 	root.AddCommand(create.Cmd)
 	root.AddCommand(describe.Cmd)
 	root.AddCommand(dlt.Cmd)
@@ -71,24 +138,160 @@ func init() {
 	root.AddCommand(download.Cmd)
 	root.AddCommand(edit.Cmd)
 	root.AddCommand(grant.Cmd)
+	root.AddCommand(hibernate.Cmd)
+	root.AddCommand(link.Cmd)
 	root.AddCommand(list.Cmd)
 	root.AddCommand(initialize.Cmd)
+	root.AddCommand(install.Cmd)
 	root.AddCommand(login.Cmd)
 	root.AddCommand(logout.Cmd)
 	root.AddCommand(logs.Cmd)
+	root.AddCommand(regenerate.Cmd)
+	root.AddCommand(register.Cmd)
+	root.AddCommand(resume.Cmd)
 	root.AddCommand(revoke.Cmd)
+	root.AddCommand(run.Cmd)
+	root.AddCommand(status.Cmd)
+	root.AddCommand(transfer.Cmd)
+	root.AddCommand(uninstall.Cmd)
 	root.AddCommand(upgrade.Cmd)
+	root.AddCommand(validate.Cmd)
 	root.AddCommand(verify.Cmd)
 	root.AddCommand(version.Cmd)
 	root.AddCommand(whoami.Cmd)
 }
 
 func main() {
+	// Commands that aren't built in may be implemented by plugins:
+	runPlugin(os.Args[1:])
+
+	// Look for newer releases while the command runs:
+	printReleaseHint := checkRelease(os.Args[1:])
+
 	// Execute the root command:
 	root.SetArgs(os.Args[1:])
+	metrics.Start(commandName(os.Args[1:]))
 	err := root.Execute()
 	if err != nil {
+		// Errors returned by the root command are caused by wrong flags or arguments:
 		fmt.Fprintf(os.Stderr, "Failed to execute root command: %s\n", err)
-		os.Exit(1)
+		flushAudit(rerrors.ExitCodeValidation, err.Error())
+		flushMetrics(rerrors.ExitCodeValidation)
+		os.Exit(rerrors.ExitCodeValidation)
+	}
+	flushAudit(0, "")
+	flushMetrics(0)
+	printReleaseHint()
+}
+
+// commandName returns the name of the subcommand that will be executed, without the name of the
+// tool, for example 'create cluster'.
+func commandName(argv []string) string {
+	cmd, _, err := root.Find(argv)
+	if err != nil || cmd == root {
+		return ""
+	}
+	return strings.TrimPrefix(cmd.CommandPath(), root.Name()+" ")
+}
+
+// releaseCheckSkipped contains the commands that don't look for newer releases, because they do it
+// themselves or because their output is consumed by other programs.
+var releaseCheckSkipped = map[string]bool{
+	"completion":   true,
+	"docs":         true,
+	"upgrade rosa": true,
+	"version":      true,
+}
+
+// checkRelease starts looking for a newer release of the tool in the background, and returns a
+// function that prints a hint when one is found. The latest release is cached, so the mirror is
+// contacted at most once per day. Nothing is done when the check is disabled or when the standard
+// error isn't a terminal.
+func checkRelease(argv []string) func() {
+	name := commandName(argv)
+	if !release.CheckEnabled() || name == "" || strings.HasPrefix(name, "__") ||
+		releaseCheckSkipped[name] {
+		return func() {}
+	}
+	info, err := os.Stderr.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return func() {}
+	}
+
+	result := make(chan string, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		latest, err := release.LatestVersion(ctx, false)
+		if err == nil && release.IsNewer(latest) {
+			result <- latest
+		}
+		close(result)
+	}()
+	return func() {
+		// Don't delay the exit of commands that finish before the mirror responds:
+		select {
+		case latest, ok := <-result:
+			if ok {
+				fmt.Fprintf(os.Stderr, "%s\n", release.Hint(latest))
+			}
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// runPlugin runs the plugin that implements the given command line, if it isn't a built in command
+// and there is one, and then exits with the exit code of the plugin. It returns without doing
+// anything otherwise.
+func runPlugin(argv []string) {
+	if len(argv) == 0 {
+		return
+	}
+	_, _, err := root.Find(argv)
+	if err == nil {
+		return
+	}
+	path, args, ok := plugin.Find(argv, exec.LookPath)
+	if !ok {
+		return
+	}
+
+	// Pass the OCM connection details to the plugin when the user is logged in, refreshing the
+	// access token if needed so that the plugin doesn't need to do it:
+	var url, token string
+	logger, err := logging.NewLogger().Build()
+	if err == nil {
+		connection, err := ocm.NewConnection().
+			Logger(logger).
+			Build()
+		if err == nil {
+			url = connection.URL()
+			token, _, err = connection.Tokens()
+			if err != nil {
+				token = ""
+			}
+			connection.Close()
+		}
+	}
+
+	code, err := plugin.Run(path, args, plugin.Environment(os.Environ(), url, token))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(rerrors.ExitCodeGeneric)
+	}
+	os.Exit(code)
+}
+
+func flushMetrics(exitStatus int) {
+	err := metrics.Flush(exitStatus)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save metrics: %s\n", err)
+	}
+}
+
+func flushAudit(exitStatus int, message string) {
+	err := audit.Finish(exitStatus, message)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save audit log: %s\n", err)
 	}
 }
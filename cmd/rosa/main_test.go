@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/audit"
+	"github.com/openshift/moactl/pkg/config"
+)
+
+var _ = Describe("Root command", func() {
+	var (
+		dir    string
+		cmd    *cobra.Command
+		run    func(*cobra.Command, []string)
+		preRun func(*cobra.Command, []string)
+		region string
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "rosa-config-")
+		Expect(err).NotTo(HaveOccurred())
+		os.Setenv("ROSA_CONFIG_DIR", dir)
+		cfg := new(config.Config)
+		Expect(cfg.Set("", "region", "us-west-2")).To(Succeed())
+		Expect(config.Save(cfg)).To(Succeed())
+
+		// Replace the implementation of the command, so that nothing is sent to AWS or OCM:
+		cmd, _, err = root.Find([]string{"create", "cluster"})
+		Expect(err).NotTo(HaveOccurred())
+		run = cmd.Run
+		preRun = cmd.PreRun
+		region = ""
+		cmd.PreRun = nil
+		cmd.Run = func(cmd *cobra.Command, _ []string) {
+			region = cmd.Flags().Lookup("region").Value.String()
+		}
+	})
+
+	AfterEach(func() {
+		cmd.Run = run
+		cmd.PreRun = preRun
+		os.Unsetenv("ROSA_CONFIG_DIR")
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("Applies the saved settings to the commands that have their own hooks", func() {
+		Expect(preRun).NotTo(BeNil())
+		root.SetArgs([]string{"create", "cluster", "--cluster-name", "mycluster"})
+		Expect(root.Execute()).To(Succeed())
+		Expect(region).To(Equal("us-west-2"))
+	})
+
+	It("Records the creation of clusters in the audit log", func() {
+		root.SetArgs([]string{"create", "cluster", "--cluster-name", "mycluster"})
+		Expect(root.Execute()).To(Succeed())
+		Expect(audit.Finish(0, "")).To(Succeed())
+		file, err := audit.Location()
+		Expect(err).NotTo(HaveOccurred())
+		data, err := ioutil.ReadFile(file)
+		Expect(err).NotTo(HaveOccurred())
+		entry := &audit.Entry{}
+		Expect(json.Unmarshal(data, entry)).To(Succeed())
+		Expect(entry.Command).To(Equal("create cluster"))
+		Expect(entry.Cluster).To(Equal("mycluster"))
+	})
+
+	It("Isn't shadowed by the hooks of the subcommands", func() {
+		// Cobra only runs the persistent hook of the nearest command that has one:
+		var check func(*cobra.Command)
+		check = func(cmd *cobra.Command) {
+			if cmd != root {
+				Expect(cmd.PersistentPreRun).To(BeNil(), cmd.CommandPath())
+				Expect(cmd.PersistentPreRunE).To(BeNil(), cmd.CommandPath())
+			}
+			for _, child := range cmd.Commands() {
+				check(child)
+			}
+		}
+		check(root)
+	})
+})
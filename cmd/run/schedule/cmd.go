@@ -0,0 +1,158 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/moactl"
+	"github.com/openshift/moactl/pkg/runner"
+	"github.com/openshift/moactl/pkg/schedules"
+)
+
+var args struct {
+	clusterKey string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "schedules",
+	Aliases: []string{"schedule"},
+	Short:   "Scale the machine pools whose schedules are due",
+	Long: "Scale the machine pools whose schedules are due since the last time that this command " +
+		"ran. When several schedules of the same machine pool are due only the last one is " +
+		"applied.\n\n" +
+		"This command is meant to be run periodically, for example every five minutes from the " +
+		"crontab of the machine. Schedules that fail are retried the next time.",
+	Example: `  # Run the due schedules of all the clusters
+  rosa run schedules
+
+  # Crontab entry that runs the due schedules every five minutes
+  */5 * * * * rosa run schedules`,
+	Run: runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to run the schedules of. By default the schedules of all the "+
+			"clusters are run.",
+	)
+}
+
+func validate(r *runner.Runtime) error {
+	if args.clusterKey != "" && !c.IsValidClusterKey(args.clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			args.clusterKey,
+		)
+	}
+	return nil
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+
+	client, err := moactl.NewClient().
+		Logger(r.Logger).
+		Connection(r.OCMConnection).
+		CreatorARN(r.Creator.ARN).
+		Build()
+	if err != nil {
+		return err
+	}
+
+	list, err := schedules.Load()
+	if err != nil {
+		return err
+	}
+	candidates := list
+	if args.clusterKey != "" {
+		reporter.Debugf("Loading cluster '%s'", args.clusterKey)
+		cluster, err := client.GetCluster(ctx, args.clusterKey)
+		if err != nil {
+			return err
+		}
+		candidates = schedules.ForCluster(list, cluster.ID())
+	}
+
+	now := time.Now()
+	due := schedules.Due(candidates, now)
+	if len(due) == 0 {
+		reporter.Infof("There are no schedules due")
+		return nil
+	}
+
+	failed := 0
+	for _, schedule := range due {
+		if ctx.Err() != nil {
+			break
+		}
+		err = apply(ctx, r, client, schedule)
+		if err != nil {
+			reporter.Errorf("Failed to run schedule '%s' of cluster '%s': %v", schedule.ID,
+				schedule.ClusterName, err)
+			failed++
+			continue
+		}
+		schedules.MarkRun(list, schedule, now)
+		reporter.Infof("Scaled machine pool '%s' of cluster '%s' to %d replicas", schedule.MachinePool,
+			schedule.ClusterName, schedule.Replicas)
+	}
+
+	err = schedules.Save(list)
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("Failed to run %d of %d due schedules", failed, len(due))
+	}
+	return nil
+}
+
+// apply scales the machine pool of the schedule to its number of replicas.
+func apply(ctx context.Context, r *runner.Runtime, client *moactl.Client,
+	schedule *schedules.Schedule) error {
+	if schedule.MachinePool != c.DefaultMachinePool {
+		return client.ScaleMachinePool(ctx, schedule.ClusterID, schedule.MachinePool,
+			schedule.Replicas)
+	}
+
+	r.Reporter.Debugf("Loading cluster '%s'", schedule.ClusterID)
+	cluster, err := client.GetCluster(ctx, schedule.ClusterID)
+	if err != nil {
+		return err
+	}
+	err = c.ValidateComputeNodes(cluster, schedule.Replicas)
+	if err != nil {
+		return err
+	}
+	return c.UpdateCluster(r.OCMConnection, cluster.ID(), r.Creator.ARN, c.Spec{
+		ComputeNodes: schedule.Replicas,
+	})
+}
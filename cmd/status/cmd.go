@@ -0,0 +1,126 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
+	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/status"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+)
+
+var args struct {
+	region string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the health of your accounts and clusters",
+	Long: "Show the OCM connectivity, the AWS credentials, permissions and quota, and the state of " +
+		"all your clusters in a single table.",
+	Example: `  # Show the health of your accounts and clusters
+  rosa status
+
+  # Check the AWS quota in a different region
+  rosa status --region=us-west-2`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.region,
+		"region",
+		"r",
+		"",
+		"AWS region in which to check the quota (overrides the AWS_REGION environment variable)",
+	)
+}
+
+func run(_ *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	// Failures to create the clients are reported as part of the status instead of aborting, so
+	// that the rest of the checks still run:
+	var awsClient aws.Client
+	region, awsErr := aws.GetRegion(args.region)
+	if awsErr == nil {
+		awsClient, awsErr = aws.NewClient().
+			Logger(logger).
+			Region(region).
+			Build()
+	}
+
+	ocmConnection, ocmErr := ocm.NewConnection().
+		Logger(logger).
+		Build()
+	if ocmErr == nil {
+		defer func() {
+			err := ocmConnection.Close()
+			if err != nil {
+				reporter.Errorf("Failed to close OCM connection: %v", err)
+			}
+		}()
+	}
+
+	reporter.Infof("Checking status...")
+	summary := status.Collect(ocmConnection, ocmErr, awsClient, awsErr)
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "CHECK\tRESULT\tDETAILS\n")
+	for _, result := range summary.Results {
+		outcome := "pass"
+		if !result.Passed {
+			outcome = "fail"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", result.Name, outcome, result.Details)
+	}
+	writer.Flush()
+
+	if len(summary.Clusters) > 0 {
+		fmt.Println()
+		writer = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(writer, "ID\tNAME\tSTATE\tVERSION\tREGION\n")
+		for _, cluster := range summary.Clusters {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n",
+				cluster.ID(),
+				cluster.Name(),
+				cluster.State(),
+				cluster.OpenshiftVersion(),
+				cluster.Region().ID(),
+			)
+		}
+		writer.Flush()
+	}
+
+	failed := summary.Failed()
+	if failed > 0 {
+		reporter.Errorf("%d out of %d checks failed", failed, len(summary.Results))
+		exit.Exit(rerrors.ExitCodeGeneric)
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/transfers"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+// pollInterval is the time between checks of the transfer when waiting for it to complete.
+const pollInterval = 30 * time.Second
+
+var args struct {
+	clusterKey string
+	newOwner   string
+	watch      bool
+	timeout    time.Duration
+}
+
+var Cmd = &cobra.Command{
+	Use:   "cluster [ID|NAME]",
+	Short: "Transfer the ownership of a cluster",
+	Long: "Transfer the ownership of a cluster to another Red Hat account. The new owner must " +
+		"accept the transfer, and then the owner and the pull secret of the cluster are replaced. " +
+		"Running the command again shows the status of the transfer.",
+	Example: `  # Transfer a cluster named "mycluster" to the user "alice"
+  rosa transfer cluster --cluster=mycluster --new-owner=alice
+
+  # Wait until the new owner accepts the transfer and the owner is replaced
+  rosa transfer cluster --cluster=mycluster --new-owner=alice --watch`,
+	Run: runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to transfer.",
+	)
+
+	flags.StringVar(
+		&args.newOwner,
+		"new-owner",
+		"",
+		"Username of the Red Hat account that will own the cluster.",
+	)
+
+	flags.BoolVar(
+		&args.watch,
+		"watch",
+		false,
+		"Wait until the transfer is completed.",
+	)
+
+	flags.DurationVar(
+		&args.timeout,
+		"timeout",
+		24*time.Hour,
+		"Maximum time to wait for the transfer to be completed when using '--watch'.",
+	)
+}
+
+// validate checks the command line arguments and keeps the key of the cluster.
+func validate(r *runner.Runtime) error {
+	clusterKey := args.clusterKey
+	if clusterKey == "" {
+		if len(r.Args) != 1 {
+			return rerrors.ValidationErrorf(
+				"Expected exactly one command line argument or flag containing the name " +
+					"or identifier of the cluster",
+			)
+		}
+		clusterKey = r.Args[0]
+	}
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	if !c.IsValidClusterKey(clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+	}
+	args.clusterKey = clusterKey
+
+	if args.newOwner == "" {
+		return rerrors.ValidationErrorf("Expected the username of the new owner in the '--new-owner' flag")
+	}
+	return transfers.ValidateUsername(args.newOwner)
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	clusterKey := args.clusterKey
+	newOwner := args.newOwner
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(r.OCMConnection.ClustersMgmt().V1().Clusters(), clusterKey,
+		r.Creator.ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	response, err := r.OCMConnection.AccountsMgmt().V1().CurrentAccount().Get().Send()
+	if err != nil {
+		return fmt.Errorf("Failed to get current account: %w", rerrors.FromOCM(response.Error(), err))
+	}
+	owner := response.Body().Username()
+	if owner == newOwner {
+		return rerrors.ValidationErrorf("Cluster '%s' is already owned by '%s'", clusterKey, owner)
+	}
+
+	// A cluster can only have one transfer at a time, so if there is already one to the same
+	// account it is tracked instead of initiating a new one:
+	reporter.Debugf("Loading transfers of cluster '%s'", clusterKey)
+	transfer, err := transfers.GetActiveTransfer(ctx, r.OCMConnection, cluster.ExternalID())
+	if err != nil {
+		return fmt.Errorf("Failed to get transfers of cluster '%s': %w", clusterKey, err)
+	}
+	if transfer != nil && transfer.Recipient != newOwner {
+		return rerrors.ConflictErrorf("Cluster '%s' is already being transferred to '%s'",
+			clusterKey, transfer.Recipient)
+	}
+	if transfer != nil {
+		reporter.Infof("Transfer of cluster '%s' to '%s' was already initiated, its status is '%s'",
+			clusterKey, newOwner, transfer.Status)
+	} else {
+		reporter.Debugf("Checking account '%s'", newOwner)
+		_, err = transfers.GetRecipient(r.OCMConnection, newOwner)
+		if err != nil {
+			return err
+		}
+
+		confirmed, err := confirm.Confirm("transfer cluster %s to %s", clusterKey, newOwner)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return nil
+		}
+
+		reporter.Debugf("Initiating transfer of cluster '%s' to '%s'", clusterKey, newOwner)
+		transfer, err = transfers.CreateTransfer(ctx, r.OCMConnection, cluster.ExternalID(), owner,
+			newOwner)
+		if err != nil {
+			return fmt.Errorf("Failed to transfer cluster '%s': %w", clusterKey, err)
+		}
+		reporter.Infof("Initiated transfer '%s' of cluster '%s' to '%s'", transfer.ID, clusterKey,
+			newOwner)
+	}
+
+	if !args.watch {
+		if !transfer.ExpirationDate.IsZero() {
+			reporter.Infof("The new owner must accept it before %s",
+				transfer.ExpirationDate.Format(time.RFC3339))
+		}
+		reporter.Infof("To check its status run 'rosa transfer cluster -c %s --new-owner=%s'",
+			clusterKey, newOwner)
+		return nil
+	}
+
+	reporter.Infof("Waiting for transfer '%s' to be completed...", transfer.ID)
+	_, err = transfers.Wait(ctx, r.OCMConnection, transfer.ID, pollInterval, args.timeout)
+	if err != nil {
+		return err
+	}
+	reporter.Infof("Cluster '%s' is now owned by '%s'", clusterKey, newOwner)
+	return nil
+}
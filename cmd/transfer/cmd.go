@@ -0,0 +1,37 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transfer
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/cmd/transfer/cluster"
+	"github.com/openshift/moactl/pkg/config"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "transfer RESOURCE [flags]",
+	Short: "Transfer the ownership of a resource",
+	Long:  "Transfer the ownership of a resource to another Red Hat account",
+}
+
+func init() {
+	// The subcommands change or delete clusters, so they don't use the saved default cluster:
+	config.MarkExplicitCluster(Cmd)
+
+	Cmd.AddCommand(cluster.Cmd)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addon
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/addons"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	clusterKey string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "addon ID",
+	Aliases: []string{"addons", "add-on", "add-ons"},
+	Short:   "Uninstall add-on from cluster",
+	Long:    "Uninstall a Red Hat managed add-on from a cluster.",
+	Example: `  # Uninstall the CodeReady Workspaces add-on from the cluster named "mycluster"
+  rosa uninstall addon --cluster=mycluster codeready-workspaces`,
+	Run: runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to uninstall the add-on from (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+}
+
+// validate checks the command line arguments before connecting to AWS and OCM.
+func validate(r *runner.Runtime) error {
+	if len(r.Args) != 1 || r.Args[0] == "" {
+		return rerrors.ValidationErrorf(
+			"Expected exactly one command line parameter containing the identifier of the add-on")
+	}
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	if !c.IsValidClusterKey(args.clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			args.clusterKey,
+		)
+	}
+	return nil
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	addOnID := r.Args[0]
+	clusterKey := args.clusterKey
+	clustersCollection := r.OCMConnection.ClustersMgmt().V1().Clusters()
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(clustersCollection, clusterKey, r.Creator.ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	addOnInstallation, err := addons.GetAddOnInstallation(clustersCollection, cluster.ID(), addOnID)
+	if err != nil {
+		return fmt.Errorf("Failed to get add-on '%s' of cluster '%s': %w", addOnID, clusterKey, err)
+	}
+	if addOnInstallation == nil {
+		return rerrors.NotFoundErrorf("Add-on '%s' isn't installed on cluster '%s'", addOnID, clusterKey)
+	}
+
+	confirmed, err := confirm.Confirm("uninstall add-on '%s' from cluster '%s'", addOnID, clusterKey)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	reporter.Debugf("Uninstalling add-on '%s' from cluster '%s'", addOnID, clusterKey)
+	err = addons.UninstallAddOn(r.OCMConnection, cluster.ID(), addOnID)
+	if err != nil {
+		return fmt.Errorf("Failed to uninstall add-on '%s' from cluster '%s': %w", addOnID, clusterKey, err)
+	}
+	reporter.Infof("Add-on '%s' is now uninstalling. To check the status run 'rosa list addons -c %s'",
+		addOnID, clusterKey)
+	return nil
+}
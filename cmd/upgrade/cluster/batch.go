@@ -0,0 +1,321 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the implementation of the batch mode of the 'upgrade cluster' command, which
+// schedules the same upgrade on multiple clusters.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/pflag"
+
+	c "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/config"
+	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/moactl"
+	"github.com/openshift/moactl/pkg/ocm/upgrades"
+	"github.com/openshift/moactl/pkg/ocm/versions"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+// defaultConcurrency is the default number of clusters whose upgrade is scheduled at the same time.
+const defaultConcurrency = 5
+
+// batchTarget is a cluster selected in batch mode, together with the result of scheduling the
+// upgrade. The cluster is nil when it couldn't be found.
+type batchTarget struct {
+	key     string
+	cluster *cmv1.Cluster
+	err     error
+}
+
+// isBatch checks if any of the flags that select multiple clusters was given.
+func isBatch() bool {
+	return args.all || len(args.selector) > 0 || args.clustersFile != ""
+}
+
+// batchOptions contains the options of the batch mode parsed from the flags.
+type batchOptions struct {
+	version              string
+	channelGroup         string
+	selector             map[string]string
+	nextRun              time.Time
+	location             *time.Location
+	nodeDrainGracePeriod *float64
+}
+
+// parseBatchOptions checks the flags used in batch mode and parses their values.
+func parseBatchOptions(flags *pflag.FlagSet) (*batchOptions, error) {
+	// The default cluster from the saved settings doesn't conflict with the batch flags:
+	if args.clusterKey != "" && !config.Applied("cluster") {
+		return nil, rerrors.ValidationErrorf("The '--cluster' flag can't be used together with " +
+			"'--all', '--selector' or '--clusters-file'")
+	}
+	if interactive.Enabled() {
+		return nil, rerrors.ValidationErrorf(
+			"Interactive mode isn't supported when upgrading multiple clusters")
+	}
+	if args.version == "" {
+		return nil, rerrors.ValidationErrorf(
+			"Expected the version to upgrade to using the '--version' flag")
+	}
+	if args.concurrency < 1 {
+		return nil, rerrors.ValidationErrorf(
+			"Expected the concurrency to be a positive number, got %d", args.concurrency)
+	}
+	selector, err := c.ParseSelector(args.selector)
+	if err != nil {
+		return nil, err
+	}
+
+	// All the clusters are upgraded at the same time, by default within the next 10 minutes:
+	location, err := upgrades.LoadTimezone(args.scheduleTimezone)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC().Add(time.Minute * 10)
+	if args.scheduleIn != "" {
+		now, err = upgrades.ParseScheduleIn(args.scheduleIn, time.Now())
+		if err != nil {
+			return nil, rerrors.ValidationErrorf("%v", err)
+		}
+	}
+	now = now.In(location)
+	scheduleDate := args.scheduleDate
+	if scheduleDate == "" {
+		scheduleDate = now.Format("2006-01-02")
+	}
+	scheduleTime := args.scheduleTime
+	if scheduleTime == "" {
+		scheduleTime = now.Format("15:04")
+	}
+	nextRun, err := upgrades.ParseScheduleInLocation(scheduleDate, scheduleTime, location)
+	if err != nil {
+		return nil, err
+	}
+	err = upgrades.ValidateNextRun(nextRun, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// The node drain grace period of each cluster is only changed when explicitly requested:
+	var nodeDrainGracePeriod *float64
+	if flags.Changed("node-drain-grace-period") {
+		minutes, err := upgrades.ParseNodeDrainGracePeriod(args.nodeDrainGracePeriod)
+		if err != nil {
+			return nil, err
+		}
+		nodeDrainGracePeriod = &minutes
+	}
+
+	return &batchOptions{
+		version:              args.version,
+		channelGroup:         args.channelGroup,
+		selector:             selector,
+		nextRun:              nextRun,
+		location:             location,
+		nodeDrainGracePeriod: nodeDrainGracePeriod,
+	}, nil
+}
+
+func validateBatch(r *runner.Runtime) error {
+	_, err := parseBatchOptions(r.Cmd.Flags())
+	return err
+}
+
+func runBatch(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	options, err := parseBatchOptions(r.Cmd.Flags())
+	if err != nil {
+		return err
+	}
+	version := options.version
+	nextRun := options.nextRun
+
+	client, err := moactl.NewClient().
+		Logger(r.Logger).
+		Connection(r.OCMConnection).
+		CreatorARN(r.Creator.ARN).
+		Build()
+	if err != nil {
+		return err
+	}
+
+	ocmClient := r.OCMConnection.ClustersMgmt().V1()
+	if options.channelGroup != "" {
+		err = versions.ValidateAvailableChannelGroup(ocmClient, options.channelGroup)
+		if err != nil {
+			return err
+		}
+	}
+	targets, err := selectTargets(ocmClient.Clusters(), r.Creator.ARN, options.selector)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		reporter.Warnf("There are no clusters matching the selection")
+		return nil
+	}
+
+	keys := make([]string, len(targets))
+	for i, target := range targets {
+		keys[i] = target.key
+	}
+	reporter.Infof("Selected clusters: %s", strings.Join(keys, ", "))
+	schedule := upgrades.FormatSchedule(nextRun, options.location)
+	confirmed, err := confirm.Confirm("schedule an upgrade to version %s on %d clusters on %s",
+		version, len(targets), schedule)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	// Schedule the upgrades using a bounded number of workers. The client does the same checks as
+	// for a single cluster, and clusters with hosted control planes are reported as failed. Version
+	// gates are never prompted for, as that isn't possible for many clusters at the same time, so
+	// they need to be acknowledged with the '--allow-version-gate-acknowledgement' flag. When the
+	// command is interrupted the upgrades that are already being scheduled finish, and the rest are
+	// skipped:
+	jobs := make(chan *batchTarget)
+	var wg sync.WaitGroup
+	for i := 0; i < args.concurrency && i < len(targets); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for target := range jobs {
+				reporter.Debugf("Scheduling upgrade for cluster '%s'", target.key)
+				_, target.err = client.ScheduleUpgrade(ctx, moactl.ScheduleUpgradeInput{
+					ClusterKey:              target.key,
+					Cluster:                 target.cluster,
+					ChannelGroup:            options.channelGroup,
+					Version:                 version,
+					NextRun:                 nextRun,
+					NodeDrainGracePeriod:    options.nodeDrainGracePeriod,
+					AcknowledgeVersionGates: args.allowVersionGateAck,
+					Force:                   args.force,
+				})
+			}
+		}()
+	}
+	for _, target := range targets {
+		if target.err != nil {
+			continue
+		}
+		if ctx.Err() != nil {
+			target.err = fmt.Errorf("Skipped because the command was interrupted")
+			continue
+		}
+		jobs <- target
+	}
+	close(jobs)
+	wg.Wait()
+
+	// Print the results:
+	failed := 0
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "CLUSTER\tID\tRESULT\tDETAILS\n")
+	for _, target := range targets {
+		id := ""
+		if target.cluster != nil {
+			id = target.cluster.ID()
+		}
+		result := "scheduled"
+		details := schedule
+		if target.err != nil {
+			failed++
+			result = "failed"
+			details = target.err.Error()
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", target.key, id, result, details)
+	}
+	writer.Flush()
+
+	if failed > 0 {
+		return fmt.Errorf("Failed to schedule upgrade for %d of %d clusters", failed, len(targets))
+	}
+	reporter.Infof("Upgrade successfully scheduled for %d clusters", len(targets))
+	return nil
+}
+
+// selectTargets returns the clusters listed in the clusters file, or all the clusters of the user
+// if there is no file, that match the selector. Clusters from the file that can't be found are
+// returned with an error, so that they are reported together with the rest of the results.
+func selectTargets(client *cmv1.ClustersClient, creatorARN string,
+	selector map[string]string) ([]*batchTarget, error) {
+	targets := []*batchTarget{}
+	if args.clustersFile != "" {
+		clusterKeys, err := c.ReadClustersFile(args.clustersFile)
+		if err != nil {
+			return nil, err
+		}
+		seen := map[string]bool{}
+		for _, clusterKey := range clusterKeys {
+			if seen[clusterKey] {
+				continue
+			}
+			seen[clusterKey] = true
+			cluster, err := c.GetCluster(client, clusterKey, creatorARN)
+			targets = append(targets, &batchTarget{
+				key:     clusterKey,
+				cluster: cluster,
+				err:     err,
+			})
+		}
+	} else {
+		clusters, err := c.GetClusters(client, creatorARN, 100)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get clusters: %v", err)
+		}
+		for _, cluster := range clusters {
+			targets = append(targets, &batchTarget{
+				key:     cluster.Name(),
+				cluster: cluster,
+			})
+		}
+	}
+	if len(selector) == 0 {
+		return targets, nil
+	}
+
+	selected := []*batchTarget{}
+	for _, target := range targets {
+		if target.cluster == nil {
+			selected = append(selected, target)
+			continue
+		}
+		labels, err := c.GetLabels(client, target.cluster.ID())
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get labels of cluster '%s': %v", target.key, err)
+		}
+		if c.MatchesSelector(target.cluster, labels, selector) {
+			selected = append(selected, target)
+		}
+	}
+	return selected, nil
+}
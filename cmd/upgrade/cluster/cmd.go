@@ -17,31 +17,47 @@ limitations under the License.
 package cluster
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"os"
-	"strconv"
-	"strings"
 	"time"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
-	"github.com/openshift/moactl/pkg/aws"
 	c "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/interactive"
-	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/moactl"
 	"github.com/openshift/moactl/pkg/ocm"
 	"github.com/openshift/moactl/pkg/ocm/upgrades"
 	"github.com/openshift/moactl/pkg/ocm/versions"
-	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/operations"
+	"github.com/openshift/moactl/pkg/runner"
 )
 
 var args struct {
 	clusterKey           string
+	controlPlane         bool
+	machinePool          string
 	version              string
+	channelGroup         string
 	scheduleDate         string
 	scheduleTime         string
+	scheduleIn           string
+	scheduleTimezone     string
 	nodeDrainGracePeriod string
+	allowVersionGateAck  bool
+	force                bool
+
+	// Batch options
+	all          bool
+	selector     []string
+	clustersFile string
+	concurrency  int
 }
 
 var Cmd = &cobra.Command{
@@ -52,8 +68,28 @@ var Cmd = &cobra.Command{
   rosa upgrade cluster --cluster=mycluster --interactive
 
   # Schedule a cluster upgrade within the hour
-  rosa upgade cluster -c mycluster --version 4.5.20`,
-	Run: run,
+  rosa upgade cluster -c mycluster --version 4.5.20
+
+  # Move a cluster to the candidate channel group and upgrade it to a release candidate
+  rosa upgrade cluster -c mycluster --channel-group candidate --version 4.6.0-rc.4
+
+  # Schedule a cluster upgrade to run in two hours
+  rosa upgrade cluster -c mycluster --version 4.5.20 --schedule-in 2h
+
+  # Schedule a cluster upgrade at 2:00 in the time zone of Paris
+  rosa upgrade cluster -c mycluster --version 4.5.20 --schedule-date 2020-12-01 \
+    --schedule-time 02:00 --schedule-timezone Europe/Paris
+
+  # Schedule the same upgrade on all the clusters with the 'env=staging' label
+  rosa upgrade cluster --selector env=staging --version 4.5.20
+
+  # Schedule the same upgrade on the clusters listed in a file, one per line
+  rosa upgrade cluster --clusters-file clusters.txt --version 4.5.20
+
+  # Upgrade only the hosted control plane of a cluster, and then one of its machine pools
+  rosa upgrade cluster -c mycluster --control-plane --version 4.12.5
+  rosa upgrade cluster -c mycluster --machinepool mp-1 --version 4.12.5`,
+	Run: runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
 }
 
 func init() {
@@ -65,9 +101,25 @@ func init() {
 		"cluster",
 		"c",
 		"",
-		"Name or ID of the cluster to schedule the upgrade for (required)",
+		"Name or ID of the cluster to schedule the upgrade for. Required unless upgrading multiple "+
+			"clusters with '--all', '--selector' or '--clusters-file'.",
+	)
+
+	flags.BoolVar(
+		&args.controlPlane,
+		"control-plane",
+		false,
+		"Upgrade only the control plane of a cluster with a hosted control plane. The machine "+
+			"pools are upgraded separately with '--machinepool'.",
+	)
+
+	flags.StringVar(
+		&args.machinePool,
+		"machinepool",
+		"",
+		"Upgrade only the given machine pool of a cluster with a hosted control plane. The machine "+
+			"pool can't be upgraded to a newer version than the control plane.",
 	)
-	Cmd.MarkFlagRequired("cluster")
 
 	flags.StringVar(
 		&args.version,
@@ -76,6 +128,15 @@ func init() {
 		"Version of OpenShift that the cluster will be upgraded to",
 	)
 
+	flags.StringVar(
+		&args.channelGroup,
+		"channel-group",
+		"",
+		"Channel group of the version to upgrade to, for example 'fast' or 'candidate'. The "+
+			"cluster is moved to this channel group when the upgrade is scheduled. Defaults to the "+
+			"current channel group of the cluster.",
+	)
+
 	flags.StringVar(
 		&args.scheduleDate,
 		"schedule-date",
@@ -90,286 +151,409 @@ func init() {
 		"Next time the upgrade should run on the specified date. Format should be 'HH:mm'",
 	)
 
+	flags.StringVar(
+		&args.scheduleTimezone,
+		"schedule-timezone",
+		"",
+		"IANA name of the time zone of '--schedule-date' and '--schedule-time', for example "+
+			"'Europe/Paris' or 'America/New_York'. Defaults to UTC. The time is converted to UTC "+
+			"before the upgrade is scheduled.",
+	)
+
+	flags.StringVar(
+		&args.scheduleIn,
+		"schedule-in",
+		"",
+		"Schedule the upgrade to run after the given duration from now, for example '2h' or '90m'. "+
+			"Can't be used with '--schedule-date' or '--schedule-time'.",
+	)
+
 	flags.StringVar(
 		&args.nodeDrainGracePeriod,
 		"node-drain-grace-period",
 		"1 hour",
 		"You may set a grace period for how long Pod Disruption Budget-protected workloads will be "+
-			"respected during upgrades.\nAfter this grace period, any workloads protected by Pod Disruption "+
-			"Budgets that have not been successfully drained from a node will be forcibly evicted",
+			"respected during upgrades, for example '30 minutes', '2 hours', '90m' or a number of "+
+			"minutes like '90', up to one week.\nAfter this grace period, any workloads protected by "+
+			"Pod Disruption Budgets that have not been successfully drained from a node will be "+
+			"forcibly evicted",
+	)
+
+	flags.BoolVar(
+		&args.allowVersionGateAck,
+		"allow-version-gate-acknowledgement",
+		false,
+		"Acknowledge any version gates required to upgrade to the selected version without prompting.",
+	)
+
+	flags.BoolVar(
+		&args.force,
+		"force",
+		false,
+		"Schedule the upgrade even if it overlaps the maintenance window of an automatic upgrade "+
+			"policy of the cluster.",
+	)
+
+	flags.BoolVar(
+		&args.all,
+		"all",
+		false,
+		"Schedule the upgrade on all the clusters that have the selected version available. "+
+			"Clusters with hosted control planes are reported as failed, as their control plane "+
+			"and machine pools are upgraded separately.",
+	)
+
+	flags.StringSliceVar(
+		&args.selector,
+		"selector",
+		nil,
+		"Schedule the upgrade on the clusters that match the given labels or properties, like the "+
+			"ones set with 'rosa edit cluster --properties'. Format should be a comma-separated "+
+			"list of 'key=value'.",
+	)
+
+	flags.StringVar(
+		&args.clustersFile,
+		"clusters-file",
+		"",
+		"Schedule the upgrade on the clusters listed in the given file, one name or ID per line.",
+	)
+
+	flags.IntVar(
+		&args.concurrency,
+		"concurrency",
+		defaultConcurrency,
+		"Maximum number of clusters whose upgrade is scheduled at the same time when upgrading "+
+			"multiple clusters.",
 	)
 }
 
-func run(cmd *cobra.Command, _ []string) {
-	reporter := rprtr.CreateReporterOrExit()
-	logger := logging.CreateLoggerOrExit(reporter)
+// validate checks the flags that don't need any client, so that mistakes are reported before
+// connecting to AWS and OCM.
+func validate(r *runner.Runtime) error {
+	flags := r.Cmd.Flags()
+	if flags.Changed("schedule-in") &&
+		(flags.Changed("schedule-date") || flags.Changed("schedule-time")) {
+		return rerrors.ValidationErrorf("Option '--schedule-in' can't be used with " +
+			"'--schedule-date' or '--schedule-time'")
+	}
+	err := versions.ValidateChannelGroup(args.channelGroup)
+	if err != nil {
+		return rerrors.ValidationErrorf("%v", err)
+	}
+	_, err = upgrades.LoadTimezone(args.scheduleTimezone)
+	if err != nil {
+		return err
+	}
+	if isTarget() {
+		err = validateTarget(r)
+		if err != nil {
+			return err
+		}
+	}
+	if isBatch() {
+		return validateBatch(r)
+	}
 
 	// Check that the cluster key (name, identifier or external identifier) given by the user
 	// is reasonably safe so that there is no risk of SQL injection:
 	clusterKey := args.clusterKey
+	if clusterKey == "" {
+		return rerrors.ValidationErrorf(
+			"Expected the name or identifier of the cluster using the '--cluster' flag")
+	}
 	if !c.IsValidClusterKey(clusterKey) {
-		reporter.Errorf(
+		return rerrors.ValidationErrorf(
 			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
 				"must contain only letters, digits, dashes and underscores",
 			clusterKey,
 		)
-		os.Exit(1)
 	}
+	return nil
+}
 
-	// Create the AWS client:
-	var err error
-	awsClient, err := aws.NewClient().
-		Logger(logger).
-		Build()
-	if err != nil {
-		reporter.Errorf("Failed to create AWS client: %v", err)
-		os.Exit(1)
+func run(ctx context.Context, r *runner.Runtime) error {
+	if isBatch() {
+		return runBatch(ctx, r)
 	}
 
-	awsCreator, err := awsClient.GetCreator()
-	if err != nil {
-		reporter.Errorf("Failed to get AWS creator: %v", err)
-		os.Exit(1)
-	}
+	reporter := r.Reporter
+	flags := r.Cmd.Flags()
+	clusterKey := args.clusterKey
 
-	// Create the client for the OCM API:
-	ocmConnection, err := ocm.NewConnection().
-		Logger(logger).
+	client, err := moactl.NewClient().
+		Logger(r.Logger).
+		Connection(r.OCMConnection).
+		CreatorARN(r.Creator.ARN).
 		Build()
 	if err != nil {
-		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		return err
 	}
-	defer func() {
-		err = ocmConnection.Close()
-		if err != nil {
-			reporter.Errorf("Failed to close OCM connection: %v", err)
-		}
-	}()
-
-	// Get the client for the OCM collection of clusters:
-	ocmClient := ocmConnection.ClustersMgmt().V1()
 
 	// Try to find the cluster:
 	reporter.Debugf("Loading cluster '%s'", clusterKey)
-	cluster, err := ocm.GetCluster(ocmClient.Clusters(), clusterKey, awsCreator.ARN)
+	cluster, err := client.GetCluster(ctx, clusterKey)
 	if err != nil {
-		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return err
 	}
 
 	if cluster.State() != cmv1.ClusterStateReady {
-		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
-		os.Exit(1)
+		return rerrors.ConflictErrorf("Cluster '%s' is not yet ready", clusterKey)
 	}
 
-	scheduledUpgrade, err := upgrades.GetScheduledUpgrade(ocmClient, cluster.ID())
+	// The control plane and the machine pools of clusters with hosted control planes are upgraded
+	// independently:
+	hosted, err := c.IsHostedControlPlane(r.OCMConnection, cluster.ID())
 	if err != nil {
-		reporter.Errorf("Failed to get scheduled upgrades for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
 	}
-	if scheduledUpgrade != nil {
-		reporter.Warnf("There is already a scheduled upgrade to version %s on %s",
-			scheduledUpgrade.Version(),
-			scheduledUpgrade.NextRun().Format("2006-01-02 15:04 MST"),
-		)
-		os.Exit(0)
+	if hosted != isTarget() {
+		if hosted {
+			return rerrors.ValidationErrorf("Cluster '%s' has a hosted control plane, use "+
+				"'--control-plane' or '--machinepool' to select what to upgrade", clusterKey)
+		}
+		return rerrors.ValidationErrorf("Cluster '%s' doesn't have a hosted control plane, the "+
+			"'--control-plane' and '--machinepool' flags can't be used", clusterKey)
+	}
+	if hosted {
+		return runTarget(ctx, r, cluster)
 	}
 
-	version := args.version
-	scheduleDate := args.scheduleDate
-	scheduleTime := args.scheduleTime
-
-	availableUpgrades, err := versions.GetAvailableUpgrades(ocmClient, versions.GetVersionID(cluster))
-	if err != nil {
-		reporter.Errorf("Failed to find available upgrades: %v", err)
-		os.Exit(1)
+	// The client checks the scheduled upgrades, the channel group, the version, the time of the
+	// upgrade and its conflicts with the automatic upgrades. The available upgrades are only
+	// loaded here when the user needs to select one of them:
+	channelGroup := cluster.Version().ChannelGroup()
+	if args.channelGroup != "" {
+		channelGroup = args.channelGroup
 	}
-	if len(availableUpgrades) == 0 {
-		reporter.Warnf("There are no available upgrades")
-		os.Exit(0)
+	version, err := selectClusterVersion(ctx, client, flags, cluster, channelGroup)
+	if err != nil {
+		return err
 	}
-
-	if version == "" || interactive.Enabled() {
-		if version == "" {
-			version = availableUpgrades[0]
-		}
-		version, err = interactive.GetOption(interactive.Input{
-			Question: "Version",
-			Help:     cmd.Flags().Lookup("version").Usage,
-			Options:  availableUpgrades,
-			Default:  version,
-			Required: true,
-		})
-		if err != nil {
-			reporter.Errorf("Expected a valid version to upgrade to: %s", err)
-			os.Exit(1)
-		}
+	if version == "" {
+		reporter.Warnf("There are no available upgrades in channel group '%s'", channelGroup)
+		return nil
 	}
 
-	// Check that the version is valid
-	validVersion := false
-	for _, v := range availableUpgrades {
-		if v == version {
-			validVersion = true
-			break
-		}
+	// Ask the user to agree with the version gates of the selected version. Only the gates that
+	// the user agreed with are acknowledged, and only when the upgrade is scheduled:
+	gates, err := upgrades.CheckVersionGates(r.OCMConnection, cluster, version,
+		args.allowVersionGateAck)
+	if err != nil {
+		return err
 	}
-	if !validVersion {
-		reporter.Errorf("Expected a valid version to upgrade to")
-		os.Exit(1)
+	gateIDs := make([]string, len(gates))
+	for i, gate := range gates {
+		gateIDs[i] = gate.ID
 	}
 
-	// Set the default next run within the next 10 minutes
-	now := time.Now().UTC().Add(time.Minute * 10)
-	if scheduleDate == "" {
-		scheduleDate = now.Format("2006-01-02")
-	}
-	if scheduleTime == "" {
-		scheduleTime = now.Format("15:04")
+	nextRun, location, err := selectNextRun(flags)
+	if err != nil {
+		return err
 	}
 
+	// Determine if the cluster already has a node drain grace period set and use that as the default
+	nodeDrainGracePeriod := upgrades.FormatNodeDrainGracePeriod(cluster.NodeDrainGracePeriod())
+	// If node drain grace period is not set, or the user sent it as a CLI argument, use that instead
+	if nodeDrainGracePeriod == "" || flags.Changed("node-drain-grace-period") {
+		nodeDrainGracePeriod = args.nodeDrainGracePeriod
+	}
 	if interactive.Enabled() {
-		// If datetimes are set, use them in the interactive form, otherwise fallback to 'now'
-		scheduleParsed, err := time.Parse("2006-01-02 15:04", fmt.Sprintf("%s %s", scheduleDate, scheduleTime))
-		if err != nil {
-			scheduleParsed = now
-		}
-		scheduleDate = scheduleParsed.Format("2006-01-02")
-		scheduleTime = scheduleParsed.Format("15:04")
-
-		scheduleDate, err = interactive.GetString(interactive.Input{
-			Question: "Please input desired date in format yyyy-mm-dd",
-			Default:  scheduleDate,
-			Required: true,
-		})
+		nodeDrainGracePeriod, err = upgrades.PromptNodeDrainGracePeriod(nodeDrainGracePeriod,
+			flags.Lookup("node-drain-grace-period").Usage)
 		if err != nil {
-			reporter.Errorf("Expected a valid date: %s", err)
-			os.Exit(1)
-		}
-		_, err = time.Parse("2006-01-02", scheduleDate)
-		if err != nil {
-			reporter.Errorf("Date format '%s' invalid", scheduleDate)
-			os.Exit(1)
+			return err
 		}
+	}
+	nodeDrainValue, err := upgrades.ParseNodeDrainGracePeriod(nodeDrainGracePeriod)
+	if err != nil {
+		return err
+	}
 
-		scheduleTime, err = interactive.GetString(interactive.Input{
-			Question: "Please input desired UTC time in format HH:mm",
-			Default:  scheduleTime,
-			Required: true,
-		})
-		if err != nil {
-			reporter.Errorf("Expected a valid time: %s", err)
-			os.Exit(1)
-		}
-		_, err = time.Parse("15:04", scheduleTime)
-		if err != nil {
-			reporter.Errorf("Time format '%s' invalid", scheduleTime)
-			os.Exit(1)
-		}
+	clusterSpec, err := upgrades.NodeDrainGracePeriodSpec(nodeDrainValue)
+	if err != nil {
+		return fmt.Errorf("Failed to update cluster '%s': %w", clusterKey, err)
 	}
 
-	// Parse next run to time.Time
-	nextRun, err := time.Parse("2006-01-02 15:04", fmt.Sprintf("%s %s", scheduleDate, scheduleTime))
+	schedule := upgrades.FormatSchedule(nextRun, location)
+
+	// Show what will change, field by field, and ask for confirmation before sending the requests:
+	changes, err := upgradeChanges(cluster, version, schedule, channelGroup, clusterSpec)
+	if err != nil {
+		return err
+	}
+	confirmed, err := confirm.ConfirmChanges(changes, "upgrade cluster %s to version %s on %s",
+		clusterKey, version, schedule)
 	if err != nil {
-		reporter.Errorf("Time format invalid: %s", err)
-		os.Exit(1)
+		return err
+	}
+	if !confirmed {
+		return nil
 	}
 
-	upgradePolicyBuilder := cmv1.NewUpgradePolicy().
-		ScheduleType("manual").
-		Version(version).
-		NextRun(nextRun)
+	// The client moves the cluster to the channel group, updates the node drain grace period and
+	// acknowledges the version gates that the user agreed with before scheduling the upgrade. It
+	// fails if other gates appeared since the user was asked:
+	step := reporter.Start("Scheduling upgrade of cluster '%s' to version '%s' on %s", clusterKey,
+		version, schedule)
+	_, err = client.ScheduleUpgrade(ctx, moactl.ScheduleUpgradeInput{
+		ClusterKey:               clusterKey,
+		Cluster:                  cluster,
+		ChannelGroup:             channelGroup,
+		Version:                  version,
+		NextRun:                  nextRun,
+		NodeDrainGracePeriod:     &nodeDrainValue,
+		AcknowledgeVersionGates:  args.allowVersionGateAck,
+		AcknowledgedVersionGates: gateIDs,
+		Force:                    args.force,
+	})
+	var windowErr *upgrades.WindowConflictError
+	if errors.As(err, &windowErr) {
+		step.Fail("%v", err)
+		return fmt.Errorf("%w. Use the '--force' flag to schedule it anyway", err)
+	}
+	if err != nil {
+		step.Fail("%v", err)
+		return err
+	}
+	operationID := ocm.LastOperationID()
+	step.Success()
+
+	reporter.Infof("Upgrade successfully scheduled for cluster '%s' on %s", clusterKey, schedule)
+	operations.Record(reporter, &operations.Operation{
+		Type:        operations.UpgradeCluster,
+		ClusterID:   cluster.ID(),
+		ClusterName: cluster.Name(),
+		Target:      version,
+		OperationID: operationID,
+	})
+	return nil
+}
 
-	nodeDrainGracePeriod := ""
-	// Determine if the cluster already has a node drain grace period set and use that as the default
-	nd := cluster.NodeDrainGracePeriod()
-	if _, ok := nd.GetValue(); ok {
-		// Convert larger times to hours, since the API only stores minutes
-		val := int(nd.Value())
-		unit := nd.Unit()
-		if val >= 60 {
-			val = val / 60
-			if val == 1 {
-				unit = "hour"
-			} else {
-				unit = "hours"
-			}
-		}
-		nodeDrainGracePeriod = fmt.Sprintf("%d %s", val, unit)
+// upgradeChanges returns the changes that scheduling the upgrade makes to the cluster: the version,
+// the channel group if it changes, and the fields of the cluster that are updated.
+func upgradeChanges(cluster *cmv1.Cluster, version string, schedule string, channelGroup string,
+	clusterSpec *cmv1.Cluster) ([]confirm.Change, error) {
+	var current, patch bytes.Buffer
+	err := cmv1.MarshalCluster(cluster, &current)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to format description of cluster: %v", err)
 	}
-	// If node drain grace period is not set, or the user sent it as a CLI argument, use that instead
-	if nodeDrainGracePeriod == "" || cmd.Flags().Changed("node-drain-grace-period") {
-		nodeDrainGracePeriod = args.nodeDrainGracePeriod
+	err = cmv1.MarshalCluster(clusterSpec, &patch)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to format description of cluster: %v", err)
 	}
-	nodeDrainOptions := []string{
-		"15 minutes",
-		"30 minutes",
-		"45 minutes",
-		"1 hour",
-		"2 hours",
-		"4 hours",
-		"8 hours",
+	fields, err := confirm.Diff(current.Bytes(), patch.Bytes())
+	if err != nil {
+		return nil, err
 	}
-	if interactive.Enabled() {
-		nodeDrainGracePeriod, err = interactive.GetOption(interactive.Input{
-			Question: "Node draining",
-			Help:     cmd.Flags().Lookup("node-drain-grace-period").Usage,
-			Options:  nodeDrainOptions,
-			Default:  nodeDrainGracePeriod,
-			Required: true,
+	changes := []confirm.Change{{
+		Field:  "version",
+		Before: cluster.OpenshiftVersion(),
+		After:  fmt.Sprintf("%s on %s", version, schedule),
+	}}
+	if channelGroup != cluster.Version().ChannelGroup() {
+		changes = append(changes, confirm.Change{
+			Field:  "version.channel_group",
+			Before: cluster.Version().ChannelGroup(),
+			After:  channelGroup,
 		})
+	}
+	return append(changes, fields...), nil
+}
+
+// selectClusterVersion returns the version given in the flags, or asks the user to select one of
+// the available upgrades of the cluster in the channel group. It returns an empty version if there
+// are no upgrades to select from. The version given in the flags is checked by the client when the
+// upgrade is scheduled.
+func selectClusterVersion(ctx context.Context, client *moactl.Client, flags *pflag.FlagSet,
+	cluster *cmv1.Cluster, channelGroup string) (string, error) {
+	if args.version != "" && !interactive.Enabled() {
+		return args.version, nil
+	}
+	availableUpgrades, err := client.AvailableUpgrades(ctx, cluster, channelGroup)
+	if err != nil || len(availableUpgrades) == 0 {
+		return "", err
+	}
+	return selectVersion(flags, availableUpgrades)
+}
+
+// selectVersion returns the version given in the flags, or asks the user to select one of the
+// available upgrades, and checks that it is one of them.
+func selectVersion(flags *pflag.FlagSet, availableUpgrades []string) (string, error) {
+	version := args.version
+	if version == "" || interactive.Enabled() {
+		var err error
+		version, err = upgrades.PromptVersion(version, availableUpgrades, flags.Lookup("version").Usage)
 		if err != nil {
-			reporter.Errorf("Expected a valid node drain grace period: %s", err)
-			os.Exit(1)
+			return "", err
 		}
 	}
-	nodeDrainParsed := strings.Split(nodeDrainGracePeriod, " ")
-	nodeDrainValue, err := strconv.ParseFloat(nodeDrainParsed[0], 64)
+
+	// Check that the version is valid
+	err := upgrades.ValidateVersion(version, availableUpgrades)
 	if err != nil {
-		reporter.Errorf("Expected a valid node drain grace period: %s", err)
-		os.Exit(1)
-	}
-	if nodeDrainParsed[1] == "hours" || nodeDrainParsed[1] == "hour" {
-		nodeDrainValue = nodeDrainValue * 60
+		return "", err
 	}
+	return version, nil
+}
 
-	clusterSpec, err := cmv1.NewCluster().
-		NodeDrainGracePeriod(cmv1.NewValue().
-			Value(nodeDrainValue).
-			Unit("minutes")).
-		Build()
+// selectNextRun returns the time of the upgrade given in the flags, or asks the user for it, and
+// the location of the time zone that the user gave it in.
+func selectNextRun(flags *pflag.FlagSet) (time.Time, *time.Location, error) {
+	scheduleDate := args.scheduleDate
+	scheduleTime := args.scheduleTime
+
+	timezone := args.scheduleTimezone
+	if interactive.Enabled() && args.scheduleIn == "" {
+		var err error
+		timezone, err = upgrades.PromptTimezone(timezone, flags.Lookup("schedule-timezone").Usage)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+	}
+	location, err := upgrades.LoadTimezone(timezone)
 	if err != nil {
-		reporter.Errorf("Failed to update cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return time.Time{}, nil, err
 	}
 
-	upgradePolicy, err := upgradePolicyBuilder.Build()
-	if err != nil {
-		reporter.Errorf("Failed to schedule upgrade for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+	// Set the default next run within the next 10 minutes, or after the given duration
+	now := time.Now().UTC().Add(time.Minute * 10)
+	if args.scheduleIn != "" {
+		now, err = upgrades.ParseScheduleIn(args.scheduleIn, time.Now())
+		if err != nil {
+			return time.Time{}, nil, rerrors.ValidationErrorf("%v", err)
+		}
+	}
+	now = now.In(location)
+	if scheduleDate == "" {
+		scheduleDate = now.Format("2006-01-02")
+	}
+	if scheduleTime == "" {
+		scheduleTime = now.Format("15:04")
 	}
 
-	_, err = ocmClient.Clusters().
-		Cluster(cluster.ID()).
-		UpgradePolicies().
-		Add().
-		Body(upgradePolicy).
-		Send()
-	if err != nil {
-		reporter.Errorf("Failed to schedule upgrade for cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+	if interactive.Enabled() {
+		// If datetimes are set, use them in the interactive form, otherwise fallback to 'now'
+		scheduleParsed, err := upgrades.ParseScheduleInLocation(scheduleDate, scheduleTime, location)
+		if err != nil {
+			scheduleParsed = now
+		}
+		scheduleParsed = scheduleParsed.In(location)
+		scheduleDate, scheduleTime, err = upgrades.PromptSchedule(
+			scheduleParsed.Format("2006-01-02"),
+			scheduleParsed.Format("15:04"),
+			location,
+		)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
 	}
 
-	_, err = ocmClient.Clusters().
-		Cluster(cluster.ID()).
-		Update().
-		Body(clusterSpec).
-		Send()
+	// Parse next run to time.Time
+	nextRun, err := upgrades.ParseScheduleInLocation(scheduleDate, scheduleTime, location)
 	if err != nil {
-		reporter.Errorf("Failed to update cluster '%s': %v", clusterKey, err)
-		os.Exit(1)
+		return time.Time{}, nil, err
 	}
-
-	reporter.Infof("Upgrade successfully scheduled for cluster '%s'", clusterKey)
+	return nextRun, location, nil
 }
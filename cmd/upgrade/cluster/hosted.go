@@ -0,0 +1,175 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the upgrade of clusters with hosted control planes, where the control plane
+// and each machine pool are upgraded independently, each with its own upgrade policy.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/upgrades"
+	"github.com/openshift/moactl/pkg/ocm/versions"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+// Regular expression used to make sure that the name of the machine pool given by the user is
+// safe to use in the path of requests:
+var machinePoolKeyRE = regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
+
+// isTarget checks if any of the flags that select the part of a cluster with a hosted control
+// plane to upgrade was given.
+func isTarget() bool {
+	return args.controlPlane || args.machinePool != ""
+}
+
+// validateTarget checks the combination of the flags that select the part of the cluster to
+// upgrade with the rest of the flags.
+func validateTarget(r *runner.Runtime) error {
+	flags := r.Cmd.Flags()
+	if args.controlPlane && args.machinePool != "" {
+		return rerrors.ValidationErrorf("Options '--control-plane' and '--machinepool' can't be " +
+			"used together, the control plane and the machine pools are upgraded separately")
+	}
+	if args.machinePool != "" && !machinePoolKeyRE.MatchString(args.machinePool) {
+		return rerrors.ValidationErrorf("Expected a valid name for the machine pool, got '%s'",
+			args.machinePool)
+	}
+	if isBatch() {
+		return rerrors.ValidationErrorf("Options '--control-plane' and '--machinepool' can't be " +
+			"used when upgrading multiple clusters")
+	}
+	for _, name := range []string{"channel-group", "node-drain-grace-period"} {
+		if flags.Changed(name) {
+			return rerrors.ValidationErrorf("Option '--%s' can't be used with '--control-plane' "+
+				"or '--machinepool'", name)
+		}
+	}
+	return nil
+}
+
+// runTarget schedules the upgrade of the control plane or of one of the machine pools of a cluster
+// with a hosted control plane.
+func runTarget(ctx context.Context, r *runner.Runtime, cluster *cmv1.Cluster) error {
+	reporter := r.Reporter
+	flags := r.Cmd.Flags()
+	clusterKey := args.clusterKey
+	ocmClient := r.OCMConnection.ClustersMgmt().V1()
+	target := upgrades.Target{
+		ClusterID:   cluster.ID(),
+		MachinePool: args.machinePool,
+	}
+
+	upgradePolicies, err := upgrades.GetTargetUpgradePolicies(r.OCMConnection, target)
+	if err != nil {
+		return fmt.Errorf("Failed to get scheduled upgrades of the %s of cluster '%s': %w", target,
+			clusterKey, err)
+	}
+	scheduledUpgrade := upgrades.FindScheduledUpgradeOfType(upgradePolicies, target.UpgradeType())
+	if scheduledUpgrade != nil {
+		reporter.Warnf("There is already a scheduled upgrade of the %s to version %s on %s",
+			target, scheduledUpgrade.Version(),
+			scheduledUpgrade.NextRun().Format("2006-01-02 15:04 MST"),
+		)
+		return nil
+	}
+
+	// Machine pools are upgraded from their own version, up to the version of the control plane:
+	controlPlaneVersion := cluster.OpenshiftVersion()
+	if controlPlaneVersion == "" {
+		controlPlaneVersion = versions.GetRawVersion(cluster.Version())
+	}
+	versionID := versions.GetVersionID(cluster)
+	if target.MachinePool != "" {
+		machinePoolVersion, err := upgrades.GetMachinePoolVersion(r.OCMConnection, target)
+		if err != nil {
+			return fmt.Errorf("Failed to get %s of cluster '%s': %w", target, clusterKey, err)
+		}
+		versionID = versions.CreateVersionID(machinePoolVersion, cluster.Version().ChannelGroup())
+	}
+	availableUpgrades, err := versions.GetAvailableUpgrades(ocmClient, versionID)
+	if err != nil {
+		return fmt.Errorf("Failed to find available upgrades: %w", err)
+	}
+	if target.MachinePool != "" {
+		availableUpgrades = upgrades.FilterMachinePoolUpgrades(availableUpgrades, controlPlaneVersion)
+	}
+	if len(availableUpgrades) == 0 {
+		reporter.Warnf("There are no available upgrades for the %s of cluster '%s'", target,
+			clusterKey)
+		return nil
+	}
+
+	version, err := selectVersion(flags, availableUpgrades)
+	if err != nil {
+		return err
+	}
+
+	// Version gates apply to the cluster, so they are acknowledged when the control plane is
+	// upgraded:
+	var gates []*upgrades.VersionGate
+	if target.MachinePool == "" {
+		gates, err = upgrades.CheckVersionGates(r.OCMConnection, cluster, version,
+			args.allowVersionGateAck)
+		if err != nil {
+			return err
+		}
+	}
+
+	nextRun, location, err := selectNextRun(flags)
+	if err != nil {
+		return err
+	}
+	err = upgrades.ValidateNextRun(nextRun, time.Now())
+	if err != nil {
+		return err
+	}
+	window := upgrades.UpgradeWindow(cluster.NodeDrainGracePeriod().Value())
+	err = upgrades.CheckConflicts(upgradePolicies, nextRun, window)
+	if err != nil {
+		if !args.force {
+			return fmt.Errorf("%w. Use the '--force' flag to schedule it anyway", err)
+		}
+		reporter.Warnf("%v", err)
+	}
+
+	schedule := upgrades.FormatSchedule(nextRun, location)
+	step := reporter.Start("Scheduling upgrade of the %s of cluster '%s' to version '%s' on %s",
+		target, clusterKey, version, schedule)
+	err = upgrades.AckVersionGates(r.OCMConnection, cluster.ID(), gates)
+	if err != nil {
+		step.Fail("%v", err)
+		return err
+	}
+	err = upgrades.ScheduleTargetUpgrade(r.OCMConnection, target, version, nextRun)
+	if err != nil {
+		step.Fail("%v", err)
+		return fmt.Errorf("Failed to schedule upgrade of the %s of cluster '%s': %w", target,
+			clusterKey, err)
+	}
+	step.Success()
+
+	reporter.Infof("Upgrade of the %s successfully scheduled for cluster '%s' on %s", target,
+		clusterKey, schedule)
+	return nil
+}
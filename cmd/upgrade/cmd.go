@@ -20,6 +20,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/cmd/upgrade/cluster"
+	"github.com/openshift/moactl/cmd/upgrade/rosa"
+	"github.com/openshift/moactl/pkg/config"
 	"github.com/openshift/moactl/pkg/interactive"
 )
 
@@ -30,7 +32,11 @@ var Cmd = &cobra.Command{
 }
 
 func init() {
+	// The subcommands change or delete clusters, so they don't use the saved default cluster:
+	config.MarkExplicitCluster(Cmd)
+
 	Cmd.AddCommand(cluster.Cmd)
+	Cmd.AddCommand(rosa.Cmd)
 
 	flags := Cmd.PersistentFlags()
 	interactive.AddFlag(flags)
@@ -0,0 +1,118 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rosa
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/confirm"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/info"
+	"github.com/openshift/moactl/pkg/release"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	version string
+	keyring string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "rosa",
+	Short: "Upgrade this tool to the latest release",
+	Long: "Replace the running executable with the latest release of the tool, or the given one. " +
+		"The checksums of the release must be signed with one of the keys of the keyring.",
+	Example: `  # Upgrade to the latest release
+  rosa upgrade rosa
+
+  # Upgrade to a specific release using the Red Hat keys stored in a different file
+  rosa upgrade rosa --version=1.0.2 --keyring=redhat-release.asc`,
+	Run: runner.Command(run),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVar(
+		&args.version,
+		"version",
+		"",
+		"Release to install. Defaults to the latest release.",
+	)
+
+	flags.StringVar(
+		&args.keyring,
+		"keyring",
+		release.DefaultKeyring,
+		"File that contains the public keys used to verify the signature of the release.",
+	)
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+
+	version := args.version
+	if version == "" {
+		reporter.Debugf("Looking for the latest release")
+		latest, err := release.LatestVersion(ctx, true)
+		if err != nil {
+			return err
+		}
+		if !release.IsNewer(latest) {
+			reporter.Infof("Already running the latest release %s", info.Version)
+			return nil
+		}
+		version = latest
+	}
+
+	keyring, err := ioutil.ReadFile(args.keyring)
+	if err != nil {
+		return rerrors.ValidationErrorf("Failed to read keyring '%s', use the '--keyring' flag to "+
+			"give the file that contains the keys used to sign the releases: %v", args.keyring, err)
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("Failed to find the running executable: %v", err)
+	}
+	executable, err = filepath.EvalSymlinks(executable)
+	if err != nil {
+		return fmt.Errorf("Failed to find the running executable: %v", err)
+	}
+
+	confirmed, err := confirm.Confirm("upgrade '%s' from %s to %s", executable, info.Version, version)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return nil
+	}
+
+	reporter.Infof("Downloading release %s", version)
+	err = release.Update(version, keyring, executable)
+	if err != nil {
+		return fmt.Errorf("Failed to upgrade to %s: %w", version, err)
+	}
+	reporter.Infof("Upgraded '%s' to %s", executable, version)
+	return nil
+}
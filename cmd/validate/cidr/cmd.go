@@ -0,0 +1,101 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cidr
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	machineCIDR string
+	serviceCIDR string
+	podCIDR     string
+	hostPrefix  int
+}
+
+var Cmd = &cobra.Command{
+	Use:     "cidr",
+	Aliases: []string{"cidrs"},
+	Short:   "Validate the networking options of a cluster",
+	Long: "Validate the CIDR blocks and the host prefix given to 'rosa create cluster', checking " +
+		"that they are valid, that they don't overlap and that the pod CIDR block is large " +
+		"enough for the host prefix, without contacting OCM or AWS.",
+	Example: `  # Validate the CIDR blocks of a cluster
+  rosa validate cidr --machine-cidr=10.0.0.0/16 --service-cidr=172.30.0.0/16 \
+    --pod-cidr=10.128.0.0/14 --host-prefix=23`,
+	Run: runner.Command(run, validate),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVar(
+		&args.machineCIDR,
+		"machine-cidr",
+		"",
+		"Block of IP addresses used by OpenShift while installing the cluster, for example \"10.0.0.0/16\".",
+	)
+
+	flags.StringVar(
+		&args.serviceCIDR,
+		"service-cidr",
+		"",
+		"Block of IP addresses for services, for example \"172.30.0.0/16\".",
+	)
+
+	flags.StringVar(
+		&args.podCIDR,
+		"pod-cidr",
+		"",
+		"Block of IP addresses from which Pod IP addresses are allocated, for example \"10.128.0.0/14\".",
+	)
+
+	flags.IntVar(
+		&args.hostPrefix,
+		"host-prefix",
+		0,
+		"Subnet prefix length to assign to each individual node, for example 23.",
+	)
+}
+
+func validate(r *runner.Runtime) error {
+	flags := r.Cmd.Flags()
+	if !flags.Changed("machine-cidr") && !flags.Changed("service-cidr") &&
+		!flags.Changed("pod-cidr") && !flags.Changed("host-prefix") {
+		return rerrors.ValidationErrorf("At least one of '--machine-cidr', '--service-cidr', " +
+			"'--pod-cidr' or '--host-prefix' is required")
+	}
+	if flags.Changed("host-prefix") && args.hostPrefix == 0 {
+		return rerrors.ValidationErrorf("Expected '--host-prefix' to be between 1 and 32, got 0")
+	}
+	return nil
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	err := c.ValidateCIDRs(args.machineCIDR, args.serviceCIDR, args.podCIDR, args.hostPrefix)
+	if err != nil {
+		return rerrors.Wrap(rerrors.ExitCodeValidation, err)
+	}
+	r.Reporter.Infof("Networking options are valid")
+	return nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterspec
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	file string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "cluster-spec",
+	Aliases: []string{"clusterspec"},
+	Short:   "Validate a cluster spec file",
+	Long: "Validate a cluster spec file used with 'rosa create cluster --file', checking the " +
+		"values and their combinations without contacting OCM or AWS. The versions, machine " +
+		"types, subnets and KMS keys are only checked when the cluster is created.",
+	Example: `  # Validate the cluster spec file "mycluster.yaml"
+  rosa validate cluster-spec --file=mycluster.yaml`,
+	Run: runner.Command(run, validate),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.file,
+		"file",
+		"f",
+		"",
+		"YAML or JSON file containing the cluster spec (required).",
+	)
+	Cmd.MarkFlagRequired("file")
+}
+
+func validate(r *runner.Runtime) error {
+	if args.file == "" {
+		return rerrors.ValidationErrorf("Cluster spec file is required")
+	}
+	return nil
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	spec, err := c.ReadSpecFile(args.file)
+	if err != nil {
+		return rerrors.Wrap(rerrors.ExitCodeValidation, err)
+	}
+	err = spec.Lint()
+	if err != nil {
+		return rerrors.ValidationErrorf("Invalid cluster spec file '%s': %v", args.file, err)
+	}
+	r.Reporter.Infof("Cluster spec file '%s' is valid", args.file)
+	return nil
+}
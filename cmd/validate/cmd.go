@@ -0,0 +1,39 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validate
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/cmd/validate/cidr"
+	"github.com/openshift/moactl/cmd/validate/clusterspec"
+	"github.com/openshift/moactl/cmd/validate/schedule"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "validate RESOURCE [flags]",
+	Short: "Validate values without contacting OCM or AWS",
+	Long: "Validate cluster spec files and the values of command line options using the same " +
+		"rules as the commands that use them, without contacting OCM or AWS, so that they can " +
+		"be checked from continuous integration pipelines before the clusters are provisioned.",
+}
+
+func init() {
+	Cmd.AddCommand(cidr.Cmd)
+	Cmd.AddCommand(clusterspec.Cmd)
+	Cmd.AddCommand(schedule.Cmd)
+}
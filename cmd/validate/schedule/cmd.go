@@ -0,0 +1,88 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/runner"
+	"github.com/openshift/moactl/pkg/schedules"
+)
+
+var args struct {
+	cron string
+	runs int
+}
+
+var Cmd = &cobra.Command{
+	Use:     "schedule",
+	Aliases: []string{"cron"},
+	Short:   "Validate the cron expression of a scaling schedule",
+	Long: "Validate a cron expression given to 'rosa create schedule', and print the next times " +
+		"when it runs in the local time zone, without contacting OCM or AWS.",
+	Example: `  # Validate a schedule that runs at 8:00 from Monday to Friday
+  rosa validate schedule --cron="0 8 * * 1-5"`,
+	Run: runner.Command(run, validate),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVar(
+		&args.cron,
+		"cron",
+		"",
+		"Cron expression with the fields minute, hour, day of month, month and day of week, for "+
+			"example '0 8 * * 1-5' (required).",
+	)
+	Cmd.MarkFlagRequired("cron")
+
+	flags.IntVar(
+		&args.runs,
+		"runs",
+		3,
+		"Number of next runs of the schedule to print.",
+	)
+}
+
+func validate(r *runner.Runtime) error {
+	if args.runs < 0 {
+		return rerrors.ValidationErrorf("Number of runs must be a non-negative number")
+	}
+	return nil
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	cron, err := schedules.ParseCron(args.cron)
+	if err != nil {
+		return err
+	}
+	next := cron.Next(time.Now())
+	if next.IsZero() {
+		return rerrors.ValidationErrorf("Cron expression '%s' never runs", args.cron)
+	}
+	r.Reporter.Infof("Cron expression '%s' is valid", args.cron)
+	for i := 0; i < args.runs && !next.IsZero(); i++ {
+		r.Reporter.Infof("Next run: %s", next.Format(time.RFC1123))
+		next = cron.Next(next)
+	}
+	return nil
+}
@@ -1,9 +1,9 @@
 package validations
 
 import (
-	"os"
-
 	"github.com/openshift/moactl/pkg/aws"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/logging"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
 	"github.com/spf13/cobra"
@@ -16,18 +16,18 @@ func Validations(cmd *cobra.Command, _ []string) {
 	// Create the AWS client:
 	client, err := aws.NewClient().
 		Logger(logger).
-		Region(aws.DefaultRegion).
+		Region(aws.GetDefaultRegion()).
 		Build()
 	if err != nil {
 		reporter.Errorf("Error creating AWS client: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
 
 	reporter.Debugf("Validating cloudformation stack exists")
 	stackExist, _, err := client.CheckStackReadyOrNotExisting(aws.OsdCcsAdminStackName)
 	if !stackExist || err != nil {
 		reporter.Errorf("Cloudformation stack does not exist. Run `rosa init` first")
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeNotFound)
 	}
 	reporter.Debugf("cloudformation stack is valid!")
 }
@@ -19,9 +19,12 @@ package verify
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/moactl/cmd/verify/network"
 	"github.com/openshift/moactl/cmd/verify/oc"
 	"github.com/openshift/moactl/cmd/verify/permissions"
 	"github.com/openshift/moactl/cmd/verify/quota"
+	"github.com/openshift/moactl/cmd/verify/terms"
+	"github.com/openshift/moactl/cmd/verify/upgrade"
 )
 
 var Cmd = &cobra.Command{
@@ -45,7 +48,10 @@ func init() {
 		"AWS region in which to run (overrides the AWS_REGION environment variable)",
 	)
 
+	Cmd.AddCommand(network.Cmd)
 	Cmd.AddCommand(oc.Cmd)
 	Cmd.AddCommand(permissions.Cmd)
 	Cmd.AddCommand(quota.Cmd)
+	Cmd.AddCommand(terms.Cmd)
+	Cmd.AddCommand(upgrade.Cmd)
 }
@@ -0,0 +1,148 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
+	"github.com/openshift/moactl/pkg/logging"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/verify/network"
+)
+
+var args struct {
+	subnetIDs []string
+	endpoints []string
+	timeout   time.Duration
+}
+
+var Cmd = &cobra.Command{
+	Use:   "network",
+	Short: "Verify network configuration is ok for cluster install",
+	Long: "Verify that the VPC and subnets used to install a cluster have DNS enabled and a route " +
+		"to the internet, using their configuration in AWS.\n\nThe command also checks the " +
+		"local connectivity of this machine to the endpoints used by clusters. These checks " +
+		"don't say anything about the subnets, and are reported separately.",
+	Example: `  # Verify the network configuration of the subnets of an existing VPC
+  rosa verify network --subnet-ids=subnet-1,subnet-2
+
+  # Verify that the required endpoints are reachable from this machine
+  rosa verify network`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringSliceVar(
+		&args.subnetIDs,
+		"subnet-ids",
+		nil,
+		"The Subnet IDs that will be used to install the cluster. "+
+			"Subnets are comma separated, for example: --subnet-ids=subnet-1,subnet-2.",
+	)
+
+	flags.StringSliceVar(
+		&args.endpoints,
+		"endpoint",
+		nil,
+		"Additional endpoints to check from this machine, as URLs or 'host:port' pairs. "+
+			"Endpoints are comma separated.",
+	)
+
+	flags.DurationVar(
+		&args.timeout,
+		"timeout",
+		5*time.Second,
+		"Maximum time to wait for a connection to each endpoint.",
+	)
+}
+
+func run(cmd *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	// Get AWS region
+	region, err := aws.GetRegion(cmd.Flags().Lookup("region").Value.String())
+	if err != nil {
+		reporter.Errorf("Error getting region: %v", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	// Create the AWS client:
+	client, err := aws.NewClient().
+		Logger(logger).
+		Region(region).
+		Build()
+	if err != nil {
+		reporter.Errorf("Error creating AWS client: %v", err)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
+	}
+
+	failed := 0
+	total := 0
+	if len(args.subnetIDs) > 0 {
+		step := reporter.Start("Validating network configuration of the subnets")
+		results := network.VerifyVPC(client, args.subnetIDs)
+		step.Success()
+		failed += printResults(results)
+		total += len(results)
+	} else {
+		reporter.Infof("No subnets provided, skipping VPC checks")
+	}
+
+	endpoints := append([]string{}, network.DefaultEndpoints...)
+	endpoints = append(endpoints, args.endpoints...)
+
+	step := reporter.Start("Checking local connectivity of this machine")
+	results := network.VerifyEndpoints(endpoints, args.timeout)
+	step.Success()
+	failed += printResults(results)
+	total += len(results)
+
+	if failed > 0 {
+		reporter.Errorf("%d out of %d network checks failed", failed, total)
+		exit.Exit(rerrors.ExitCodeGeneric)
+	}
+	reporter.Infof("Network checks passed")
+}
+
+// printResults prints the results of the checks as a table and returns the number of checks that
+// failed.
+func printResults(results []network.Result) int {
+	failed := 0
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "CHECK\tRESULT\tDETAILS\n")
+	for _, result := range results {
+		status := "pass"
+		if !result.Passed {
+			status = "fail"
+			failed++
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", result.Name, status, result.Details)
+	}
+	writer.Flush()
+	return failed
+}
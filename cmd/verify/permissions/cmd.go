@@ -17,16 +17,26 @@ limitations under the License.
 package permissions
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"os"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/pkg/aws"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/logging"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
 )
 
+var args struct {
+	output string
+}
+
 var Cmd = &cobra.Command{
 	Use:   "permissions",
 	Short: "Verify AWS permissions are ok for cluster install",
@@ -35,10 +45,25 @@ var Cmd = &cobra.Command{
   rosa verify permissions
 
   # Verify AWS permissions in a different region
-  rosa verify permissions --region=us-west-2`,
+  rosa verify permissions --region=us-west-2
+
+  # Print the result of each action in JSON format
+  rosa verify permissions --output=json`,
 	Run: run,
 }
 
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.output,
+		"output",
+		"o",
+		"",
+		"Output format of the per-action report. Use 'json' to print it in JSON format.",
+	)
+}
+
 func run(cmd *cobra.Command, argv []string) {
 	reporter := rprtr.CreateReporterOrExit()
 	logger := logging.CreateLoggerOrExit(reporter)
@@ -47,7 +72,7 @@ func run(cmd *cobra.Command, argv []string) {
 	region, err := aws.GetRegion(cmd.Flags().Lookup("region").Value.String())
 	if err != nil {
 		reporter.Errorf("Error getting region: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	// Create the AWS client:
@@ -57,22 +82,69 @@ func run(cmd *cobra.Command, argv []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Error creating AWS client: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
+	}
+
+	if args.output != "" && args.output != "json" {
+		reporter.Errorf("Invalid output format '%s', expected 'json'", args.output)
+		exit.Exit(rerrors.ExitCodeValidation)
 	}
 
-	reporter.Infof("Validating SCP policies...")
-	ok, err := client.ValidateSCP(nil)
+	var step *rprtr.Step
+	if args.output == "" {
+		step = reporter.Start("Simulating the permissions needed to create a cluster")
+	}
+	results, err := client.SimulatePermissions(context.Background(), nil)
+	if step != nil {
+		if err != nil {
+			step.Fail("%v", err)
+		} else {
+			step.Success()
+		}
+	}
 	if err != nil {
-		reporter.Errorf("Unable to validate SCP policies")
+		reporter.Errorf("Unable to simulate permissions")
 		if strings.Contains(err.Error(), "Throttling: Rate exceeded") {
 			reporter.Errorf("Throttling: Rate exceeded. Please wait 3-5 minutes before retrying.")
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCodeGeneric)
 		}
 		reporter.Errorf("%v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	denied := 0
+	for _, result := range results {
+		if !result.Allowed() {
+			denied++
+		}
+	}
+
+	if args.output == "json" {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			reporter.Errorf("Failed to marshal permissions report: %v", err)
+			exit.Exit(rerrors.ExitCode(err))
+		}
+		fmt.Println(string(data))
+	} else {
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(writer, "ACTION\tPOLICY\tRESULT\tDETAILS\n")
+		for _, result := range results {
+			details := ""
+			if result.DeniedByOrganizations {
+				details = "Denied by a service control policy of the organization"
+			}
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", result.Action, result.Policy, result.Decision, details)
+		}
+		writer.Flush()
+	}
+
+	if denied > 0 {
+		reporter.Errorf("%d out of %d actions are not allowed with the current credentials",
+			denied, len(results))
+		exit.Exit(rerrors.ExitCodeAWSAuth)
 	}
-	if !ok {
-		reporter.Warnf("Failed to validate SCP policies. Will try to continue anyway...")
+	if args.output == "" {
+		reporter.Infof("AWS permissions ok")
 	}
-	reporter.Infof("AWS SCP policies ok")
 }
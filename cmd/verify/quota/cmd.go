@@ -17,27 +17,67 @@ limitations under the License.
 package quota
 
 import (
+	"fmt"
 	"os"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/pkg/aws"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/ocm"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/verify/quota"
 )
 
+var args struct {
+	multiAZ            bool
+	computeNodes       int
+	computeMachineType string
+}
+
 var Cmd = &cobra.Command{
 	Use:   "quota",
 	Short: "Verify AWS quota is ok for cluster install",
-	Long:  "Verify AWS quota needed to create a cluster is configured as expected",
+	Long: "Verify that the AWS service quotas are enough for the resources needed to create a " +
+		"cluster of the given size.",
 	Example: `  # Verify AWS quotas are configured correctly
   rosa verify quota
 
   # Verify AWS quotas in a different region
-  rosa verify quota --region=us-west-2`,
+  rosa verify quota --region=us-west-2
+
+  # Verify AWS quotas for a multi-AZ cluster with 9 compute nodes
+  rosa verify quota --multi-az --compute-nodes=9 --compute-machine-type=m5.2xlarge`,
 	Run: run,
 }
 
+func init() {
+	flags := Cmd.Flags()
+
+	flags.BoolVar(
+		&args.multiAZ,
+		"multi-az",
+		false,
+		"Verify the quotas needed by a cluster deployed to multiple data centers.",
+	)
+	flags.IntVar(
+		&args.computeNodes,
+		"compute-nodes",
+		0,
+		"Number of compute nodes of the cluster. Defaults to 2 for single zone clusters and "+
+			"3 for multizone clusters.",
+	)
+	flags.StringVar(
+		&args.computeMachineType,
+		"compute-machine-type",
+		"",
+		"Instance type of the compute nodes of the cluster.",
+	)
+}
+
 func run(cmd *cobra.Command, argv []string) {
 	reporter := rprtr.CreateReporterOrExit()
 	logger := logging.CreateLoggerOrExit(reporter)
@@ -46,7 +86,7 @@ func run(cmd *cobra.Command, argv []string) {
 	region, err := aws.GetRegion(cmd.Flags().Lookup("region").Value.String())
 	if err != nil {
 		reporter.Errorf("Error getting region: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	// Create the AWS client:
@@ -56,15 +96,69 @@ func run(cmd *cobra.Command, argv []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Error creating AWS client: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeAWSAuth)
+	}
+
+	// Create the client for the OCM API:
+	ocmConnection, err := ocm.NewConnection().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
 	}
+	defer func() {
+		err = ocmConnection.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
 
-	reporter.Infof("Validating AWS quota...")
-	_, err = client.ValidateQuota()
+	computeNodes := args.computeNodes
+	if computeNodes == 0 {
+		computeNodes = 2
+		if args.multiAZ {
+			computeNodes = 3
+		}
+	}
+
+	reporter.Debugf("Fetching the resources needed to create the cluster")
+	requirements, err := quota.GetRequirements(ocmConnection.ClustersMgmt().V1(), quota.Options{
+		MultiAZ:            args.multiAZ,
+		ComputeNodes:       computeNodes,
+		ComputeMachineType: args.computeMachineType,
+	})
 	if err != nil {
+		reporter.Errorf("Failed to get the resources needed to create the cluster: %v", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+
+	step := reporter.Start("Validating AWS quota")
+	results := quota.Verify(client, requirements)
+	step.Success()
+
+	failed := []quota.Result{}
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "QUOTA\tREQUIRED\tAVAILABLE\tRESULT\tDETAILS\n")
+	for _, result := range results {
+		status := "pass"
+		if !result.Passed {
+			status = "fail"
+			failed = append(failed, result)
+		}
+		fmt.Fprintf(writer, "%s\t%d\t%d\t%s\t%s\n",
+			result.Name, result.Required, result.Available, status, result.Details)
+	}
+	writer.Flush()
+
+	if len(failed) > 0 {
 		reporter.Errorf("Insufficient AWS quotas")
-		reporter.Errorf("%v", err)
-		os.Exit(1)
+		reporter.Infof("To request a quota increase run the following commands, " +
+			"or use the Service Quotas console:")
+		for _, result := range failed {
+			fmt.Printf("  %s\n", result.IncreaseCommand(region))
+		}
+		exit.Exit(rerrors.ExitCodeGeneric)
 	}
 	reporter.Infof("AWS quota ok")
 }
@@ -0,0 +1,112 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package terms
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/helper"
+	"github.com/openshift/moactl/pkg/ocm/terms"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+// pollInterval is the time between reviews of the terms when waiting for them to be accepted.
+const pollInterval = 10 * time.Second
+
+var args struct {
+	open    bool
+	watch   bool
+	timeout time.Duration
+}
+
+var Cmd = &cobra.Command{
+	Use:   "terms",
+	Short: "Verify that the terms and conditions have been accepted",
+	Long: "Verify that the terms and conditions required to create clusters have been accepted. " +
+		"Fails and prints the page where they can be accepted otherwise, so it can be used to " +
+		"check the account before creating clusters from automation.",
+	Example: `  # Verify that the terms and conditions have been accepted
+  rosa verify terms
+
+  # Open the terms and conditions in the browser and wait until they are accepted
+  rosa verify terms --open --watch`,
+	Run: runner.Command(run, runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.BoolVar(
+		&args.open,
+		"open",
+		false,
+		"Open the page where the terms and conditions can be accepted in the browser.",
+	)
+
+	flags.BoolVar(
+		&args.watch,
+		"watch",
+		false,
+		"Wait until the terms and conditions are accepted.",
+	)
+
+	flags.DurationVar(
+		&args.timeout,
+		"timeout",
+		30*time.Minute,
+		"Maximum time to wait for the terms and conditions to be accepted when using '--watch'.",
+	)
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+
+	reporter.Debugf("Reviewing terms and conditions")
+	review, err := terms.GetReview(ctx, r.OCMConnection)
+	if err != nil {
+		return err
+	}
+	if review.Accepted() {
+		reporter.Infof("Terms and conditions have been accepted")
+		return nil
+	}
+
+	reporter.Warnf("Terms and conditions must be accepted before creating clusters. "+
+		"Review and accept them at:\n\n  %s\n", review.URL())
+	if args.open {
+		err = helper.OpenBrowser(review.URL())
+		if err != nil {
+			reporter.Warnf("%v", err)
+		}
+	}
+	if !args.watch {
+		return rerrors.ConflictErrorf("Terms and conditions haven't been accepted")
+	}
+
+	reporter.Infof("Waiting for the terms and conditions to be accepted...")
+	err = terms.Wait(ctx, r.OCMConnection, pollInterval, args.timeout)
+	if err != nil {
+		return fmt.Errorf("Failed to wait for terms and conditions: %w", err)
+	}
+	reporter.Infof("Terms and conditions have been accepted")
+	return nil
+}
@@ -0,0 +1,153 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	c "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/upgrades"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var args struct {
+	clusterKey string
+	version    string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Verify that the upgrade of a cluster succeeded",
+	Long: "Verify the health of a cluster after the window of an upgrade: the cluster is ready, " +
+		"the scheduled upgrade isn't failed or stuck, the cluster runs the target version and all " +
+		"the cluster operators are available. The command fails if any of the checks fails, so it " +
+		"can be used as a gate in pipelines.",
+	Example: `  # Verify the upgrade of the cluster named "mycluster"
+  rosa verify upgrade --cluster=mycluster
+
+  # Verify that the cluster named "mycluster" was upgraded to version 4.6.9
+  rosa verify upgrade --cluster=mycluster --version=4.6.9`,
+	Run: runner.Command(run, validate, runner.WithAWS(), runner.WithOCM()),
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to verify (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	flags.StringVar(
+		&args.version,
+		"version",
+		"",
+		"Version of OpenShift that the cluster is expected to run. Defaults to the version of the "+
+			"scheduled upgrade, if there is one.",
+	)
+}
+
+// validate checks that the cluster key is safe to use in queries.
+func validate(r *runner.Runtime) error {
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	if !c.IsValidClusterKey(args.clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			args.clusterKey,
+		)
+	}
+	return nil
+}
+
+func run(ctx context.Context, r *runner.Runtime) error {
+	reporter := r.Reporter
+	clusterKey := args.clusterKey
+	ocmClient := r.OCMConnection.ClustersMgmt().V1()
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocm.GetCluster(ocmClient.Clusters(), clusterKey, r.Creator.ARN)
+	if err != nil {
+		return fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+
+	health := upgrades.Health{
+		ClusterState:   cluster.State(),
+		CurrentVersion: cluster.OpenshiftVersion(),
+		TargetVersion:  args.version,
+		UpgradeWindow:  upgrades.UpgradeWindow(cluster.NodeDrainGracePeriod().Value()),
+	}
+	if health.CurrentVersion == "" {
+		health.CurrentVersion = cluster.Version().RawID()
+	}
+
+	reporter.Debugf("Loading scheduled upgrade for cluster '%s'", clusterKey)
+	scheduledUpgrade, err := upgrades.GetScheduledUpgrade(ocmClient, cluster.ID())
+	if err != nil {
+		return fmt.Errorf("Failed to get scheduled upgrades for cluster '%s': %w", clusterKey, err)
+	}
+	if scheduledUpgrade != nil {
+		reporter.Debugf("Loading state of upgrade '%s' for cluster '%s'", scheduledUpgrade.ID(),
+			clusterKey)
+		state, err := upgrades.GetUpgradePolicyState(ocmClient, cluster.ID(), scheduledUpgrade.ID())
+		if err != nil {
+			return fmt.Errorf("Failed to get state of upgrade for cluster '%s': %w", clusterKey, err)
+		}
+		health.ScheduledUpgrade = scheduledUpgrade
+		health.UpgradeState = state.Value()
+		if health.TargetVersion == "" {
+			health.TargetVersion = scheduledUpgrade.Version()
+		}
+	}
+
+	reporter.Debugf("Loading cluster operators of cluster '%s'", clusterKey)
+	health.Operators, health.OperatorsErr = upgrades.GetClusterOperators(ocmClient, cluster.ID())
+
+	checks := upgrades.CheckHealth(health, time.Now())
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "CHECK\tRESULT\tDETAILS\n")
+	for _, check := range checks {
+		result := "pass"
+		if !check.Passed {
+			result = "fail"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", check.Name, result, check.Details)
+	}
+	writer.Flush()
+
+	failed := upgrades.FailedChecks(checks)
+	if len(failed) > 0 {
+		return rerrors.Errorf(rerrors.ExitCodeGeneric, "Upgrade of cluster '%s' isn't healthy: %d "+
+			"of %d checks failed", clusterKey, len(failed), len(checks))
+	}
+	reporter.Infof("Cluster '%s' is healthy and runs version %s", clusterKey, health.CurrentVersion)
+	return nil
+}
@@ -17,21 +17,58 @@ limitations under the License.
 package version
 
 import (
+	"context"
 	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
 
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/info"
+	"github.com/openshift/moactl/pkg/release"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
 )
 
+var args struct {
+	check bool
+}
+
 var Cmd = &cobra.Command{
 	Use:   "version",
 	Short: "Prints the version of the tool",
 	Long:  "Prints the version number of the tool.",
-	Run:   run,
+	Example: `  # Print the version and check if there is a newer release
+  rosa version --check`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.BoolVar(
+		&args.check,
+		"check",
+		false,
+		"Check if there is a newer release of the tool, even if the automatic check is disabled.",
+	)
 }
 
 func run(cmd *cobra.Command, argv []string) {
 	fmt.Fprintf(os.Stdout, "%s\n", info.Version)
+	if !args.check {
+		return
+	}
+
+	reporter := rprtr.CreateReporterOrExit()
+	latest, err := release.LatestVersion(context.Background(), true)
+	if err != nil {
+		reporter.Errorf("Failed to check for new releases: %v", err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	if release.IsNewer(latest) {
+		reporter.Infof("%s", release.Hint(latest))
+		return
+	}
+	reporter.Infof("This is the latest release")
 }
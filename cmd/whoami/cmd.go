@@ -19,12 +19,16 @@ package whoami
 import (
 	"fmt"
 	"net/http"
-	"os"
+	"sort"
 
 	amsv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
 	"github.com/openshift/moactl/pkg/logging"
 	"github.com/openshift/moactl/pkg/ocm"
 	"github.com/openshift/moactl/pkg/ocm/config"
@@ -34,7 +38,8 @@ import (
 var Cmd = &cobra.Command{
 	Use:   "whoami",
 	Short: "Displays user account information",
-	Long:  "Displays information about your AWS and Red Hat accounts",
+	Long: "Displays information about your AWS and Red Hat accounts, whether the AWS account is " +
+		"linked for ROSA, and the clusters of the AWS account that were created by other ARNs",
 	Example: `  # Displays user information
   rosa whoami`,
 	Run: run,
@@ -50,43 +55,43 @@ func run(_ *cobra.Command, _ []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("failed to create AWS client: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	// Get current AWS account information:
 	awsCreator, err := awsClient.GetCreator()
 	if err != nil {
 		reporter.Errorf("failed to get AWS creator: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	// Get default AWS region:
 	awsRegion, err := aws.GetRegion("")
 	if err != nil {
 		reporter.Errorf("Error getting AWS region: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 
 	// Load the configuration file:
 	cfg, err := config.Load()
 	if err != nil {
 		reporter.Errorf("Failed to load config file: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 	if cfg == nil {
 		reporter.Errorf("User is not logged in to OCM")
-		os.Exit(0)
+		exit.Exit(0)
 	}
 
 	// Verify configuration file:
 	loggedIn, err := cfg.Armed()
 	if err != nil {
 		reporter.Errorf("Failed to verify configuration: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCode(err))
 	}
 	if !loggedIn {
 		reporter.Errorf("User is not logged in to OCM")
-		os.Exit(0)
+		exit.Exit(0)
 	}
 
 	// Create a connection to OCM:
@@ -96,7 +101,7 @@ func run(_ *cobra.Command, _ []string) {
 		Build()
 	if err != nil {
 		reporter.Errorf("Failed to create OCM connection: %v", err)
-		os.Exit(1)
+		exit.Exit(rerrors.ExitCodeOCMAuth)
 	}
 	defer func() {
 		err = connection.Close()
@@ -113,8 +118,9 @@ func run(_ *cobra.Command, _ []string) {
 		if response.Status() == http.StatusNotFound {
 			useTokenData = true
 		} else {
-			reporter.Errorf("Failed to get current account: %s", response.Error().Reason())
-			os.Exit(1)
+			err = rerrors.FromOCM(response.Error(), err)
+			reporter.Errorf("Failed to get current account: %v", err)
+			exit.Exit(rerrors.ExitCode(err))
 		}
 	}
 
@@ -123,7 +129,7 @@ func run(_ *cobra.Command, _ []string) {
 		account, err = getAccountDataFromToken(cfg)
 		if err != nil {
 			reporter.Errorf("Failed to get account data from token: %v", err)
-			os.Exit(1)
+			exit.Exit(rerrors.ExitCode(err))
 		}
 	} else {
 		account = response.Body()
@@ -152,7 +158,75 @@ func run(_ *cobra.Command, _ []string) {
 		account.Organization().Name(),
 		account.Organization().ExternalID(),
 	)
+
+	// Check that the AWS account is linked for ROSA and that the clusters of the account are
+	// visible to the current ARN:
+	linked := isLinked(reporter, logger)
+	counts, err := cluster.CountClustersByCreator(connection.ClustersMgmt().V1().Clusters(),
+		awsCreator.AccountID)
+	if err != nil {
+		reporter.Errorf("Failed to get clusters of AWS account '%s': %v", awsCreator.AccountID, err)
+		exit.Exit(rerrors.ExitCode(err))
+	}
+	fmt.Printf(""+
+		"AWS Account Linked for ROSA:  %s\n"+
+		"Clusters Created by ARN:      %d\n",
+		yesOrNo(linked),
+		counts[awsCreator.ARN],
+	)
 	fmt.Println()
+
+	if !linked {
+		reporter.Warnf("AWS account '%s' isn't linked for ROSA, run 'rosa init' to link it",
+			awsCreator.AccountID)
+	}
+	others := otherCreators(counts, awsCreator.ARN)
+	if len(others) > 0 {
+		reporter.Warnf("AWS account '%s' has clusters created by other ARNs, which aren't "+
+			"visible to '%s':", awsCreator.AccountID, awsCreator.ARN)
+		for _, arn := range others {
+			fmt.Printf("  %s: %d\n", arn, counts[arn])
+		}
+		reporter.Infof("Use the AWS credentials of the ARN that created a cluster to manage it")
+	}
+}
+
+// isLinked checks that the CloudFormation stack that creates the administrator user of ROSA
+// exists and is ready. The stack is always created in the default region.
+func isLinked(reporter *rprtr.Object, logger *logrus.Logger) bool {
+	stackClient, err := aws.NewClient().
+		Logger(logger).
+		Region(aws.GetDefaultRegion()).
+		Build()
+	if err != nil {
+		reporter.Warnf("Failed to create AWS client: %v", err)
+		return false
+	}
+	ready, _, err := stackClient.CheckStackReadyOrNotExisting(aws.OsdCcsAdminStackName)
+	if err != nil {
+		reporter.Warnf("Failed to check stack '%s': %v", aws.OsdCcsAdminStackName, err)
+		return false
+	}
+	return ready
+}
+
+// otherCreators returns the sorted ARNs, other than the given one, that have created clusters.
+func otherCreators(counts map[string]int, creatorARN string) []string {
+	result := []string{}
+	for arn := range counts {
+		if arn != creatorARN {
+			result = append(result, arn)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func yesOrNo(value bool) string {
+	if value {
+		return "Yes"
+	}
+	return "No"
 }
 
 func getAccountDataFromToken(cfg *config.Config) (*amsv1.Account, error) {
@@ -21,8 +21,19 @@ package arguments
 import (
 	"github.com/spf13/pflag"
 
+	"github.com/openshift/moactl/pkg/audit"
+	"github.com/openshift/moactl/pkg/aws/partition"
 	"github.com/openshift/moactl/pkg/aws/profile"
+	"github.com/openshift/moactl/pkg/aws/role"
+	"github.com/openshift/moactl/pkg/config"
+	"github.com/openshift/moactl/pkg/confirm"
 	"github.com/openshift/moactl/pkg/debug"
+	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/metrics"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/cache"
+	"github.com/openshift/moactl/pkg/reporter"
+	"github.com/openshift/moactl/pkg/timeout"
 )
 
 // AddDebugFlag adds the '--debug' flag to the given set of command line flags.
@@ -30,7 +41,79 @@ func AddDebugFlag(fs *pflag.FlagSet) {
 	debug.AddFlag(fs)
 }
 
-// AddProfileFlag adds the '--profile' flag to the given set of command line flags.
+// AddLogLevelFlag adds the '--log-level' flag to the given set of command line flags.
+func AddLogLevelFlag(fs *pflag.FlagSet) {
+	debug.AddLevelFlag(fs)
+}
+
+// AddColorFlag adds the '--color' flag to the given set of command line flags.
+func AddColorFlag(fs *pflag.FlagSet) {
+	reporter.AddColorFlag(fs)
+}
+
+// AddProgressFormatFlag adds the '--progress-format' flag to the given set of command line flags.
+func AddProgressFormatFlag(fs *pflag.FlagSet) {
+	reporter.AddProgressFormatFlag(fs)
+}
+
+// AddProfileFlag adds the '--profile' and '--aws-profile' flags to the given set of command line flags.
 func AddProfileFlag(fs *pflag.FlagSet) {
 	profile.AddFlag(fs)
 }
+
+// AddRoleFlags adds the '--role-arn', '--external-id' and '--mfa-serial' flags to the given set of
+// command line flags.
+func AddRoleFlags(fs *pflag.FlagSet) {
+	role.AddFlags(fs)
+}
+
+// AddGovCloudFlag adds the '--govcloud' flag to the given set of command line flags.
+func AddGovCloudFlag(fs *pflag.FlagSet) {
+	partition.AddFlag(fs)
+}
+
+// AddRequestTimeoutFlag adds the '--request-timeout' flag to the given set of command line flags.
+func AddRequestTimeoutFlag(fs *pflag.FlagSet) {
+	timeout.AddFlag(fs)
+}
+
+// AddMaxRetriesFlag adds the '--max-retries' flag to the given set of command line flags.
+func AddMaxRetriesFlag(fs *pflag.FlagSet) {
+	ocm.AddMaxRetriesFlag(fs)
+}
+
+// AddOwnerARNFlag adds the '--owner-arn' flag to the given set of command line flags.
+func AddOwnerARNFlag(fs *pflag.FlagSet) {
+	ocm.AddOwnerARNFlag(fs)
+}
+
+// AddOCMProfileFlag adds the '--ocm-profile' flag to the given set of command line flags.
+func AddOCMProfileFlag(fs *pflag.FlagSet) {
+	config.AddFlag(fs)
+}
+
+// AddMetricsFlags adds the '--metrics-file' and '--metrics-pushgateway' flags to the given set of
+// command line flags.
+func AddMetricsFlags(fs *pflag.FlagSet) {
+	metrics.AddFlags(fs)
+}
+
+// AddYesFlag adds the '--yes' flag to the given set of command line flags.
+func AddYesFlag(fs *pflag.FlagSet) {
+	confirm.AddFlag(fs)
+}
+
+// AddNonInteractiveFlag adds the '--non-interactive' flag to the given set of command line flags.
+func AddNonInteractiveFlag(fs *pflag.FlagSet) {
+	interactive.AddNonInteractiveFlag(fs)
+}
+
+// AddAuditFlag adds the '--audit-service-log' flag to the given set of command line flags.
+func AddAuditFlag(fs *pflag.FlagSet) {
+	audit.AddFlag(fs)
+}
+
+// AddNoCacheFlag adds the '--no-cache' flag to the given set of command line flags.
+func AddNoCacheFlag(fs *pflag.FlagSet) {
+	cache.AddFlag(fs)
+}
@@ -0,0 +1,268 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to record the commands that change resources, so that
+// teams have a trail of who changed what using the command line tool.
+
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/moactl/pkg/config"
+)
+
+// Results of the audited commands:
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// redacted replaces the values of the flags that may contain secrets.
+const redacted = "REDACTED"
+
+// mutatingVerbs are the first words of the commands that change resources and are audited.
+var mutatingVerbs = map[string]bool{
+	"create":    true,
+	"delete":    true,
+	"edit":      true,
+	"grant":     true,
+	"hibernate": true,
+	"install":   true,
+	"register":  true,
+	"resume":    true,
+	"revoke":    true,
+	"run":       true,
+	"transfer":  true,
+	"uninstall": true,
+	"upgrade":   true,
+}
+
+// secretWords are the words that identify the flags whose values aren't written to the audit log.
+var secretWords = []string{"password", "secret", "token", "key"}
+
+// Entry is one line of the audit log.
+type Entry struct {
+	Timestamp  time.Time         `json:"timestamp"`
+	Command    string            `json:"command"`
+	Cluster    string            `json:"cluster,omitempty"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+	Arguments  []string          `json:"arguments,omitempty"`
+	LocalUser  string            `json:"local_user,omitempty"`
+	AWSUser    string            `json:"aws_user,omitempty"`
+	Result     string            `json:"result"`
+	ExitStatus int               `json:"exit_status"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// Sender publishes an entry of the audit log outside of the local machine.
+type Sender func(entry *Entry) error
+
+// recorder contains the entry of the command that is being executed, if it is audited.
+var recorder = struct {
+	sync.Mutex
+	entry     *Entry
+	sender    Sender
+	finished  bool
+	lastError string
+}{}
+
+// IsMutating returns true if the command with the given path, without the name of the tool, changes
+// resources.
+func IsMutating(commandPath string) bool {
+	words := strings.Fields(commandPath)
+	return len(words) > 0 && mutatingVerbs[words[0]]
+}
+
+// Start records the command that is being executed, if it changes resources. Flags that may contain
+// secrets are redacted.
+func Start(cmd *cobra.Command, argv []string) {
+	command := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	recorder.Lock()
+	defer recorder.Unlock()
+	recorder.entry = nil
+	recorder.finished = false
+	recorder.lastError = ""
+	if !IsMutating(command) {
+		return
+	}
+	entry := &Entry{
+		Timestamp:  time.Now().UTC(),
+		Command:    command,
+		Parameters: map[string]string{},
+		Arguments:  argv,
+	}
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		// Commands that create clusters take the name with the '--cluster-name' flag:
+		if flag.Name == "cluster" || flag.Name == "cluster-name" {
+			entry.Cluster = flag.Value.String()
+		}
+		if isSecret(flag.Name) {
+			entry.Parameters[flag.Name] = redacted
+		} else {
+			entry.Parameters[flag.Name] = flag.Value.String()
+		}
+	})
+	if current, err := user.Current(); err == nil {
+		entry.LocalUser = current.Username
+	}
+	recorder.entry = entry
+}
+
+// SetAWSUser records the ARN of the AWS user that runs the command.
+func SetAWSUser(arn string) {
+	recorder.Lock()
+	defer recorder.Unlock()
+	if recorder.entry != nil {
+		recorder.entry.AWSUser = arn
+	}
+}
+
+// SetSender sets the function used to publish the entries when the '--audit-service-log' flag is
+// given.
+func SetSender(sender Sender) {
+	recorder.Lock()
+	defer recorder.Unlock()
+	recorder.sender = sender
+}
+
+// SetError records the last error reported by the command, which is used as the error message of
+// the entry when the command fails without giving one.
+func SetError(message string) {
+	recorder.Lock()
+	defer recorder.Unlock()
+	recorder.lastError = message
+}
+
+// Finish writes the entry of the command with the given exit status and error message to the audit
+// log, and publishes it if requested. It does nothing if the command isn't audited. It is called
+// when the process exits, and only the first call writes the entry. When the command failed and the
+// message is empty, the last error recorded with SetError is used.
+func Finish(exitStatus int, message string) error {
+	recorder.Lock()
+	defer recorder.Unlock()
+	if recorder.entry == nil || recorder.finished {
+		return nil
+	}
+	recorder.finished = true
+	if message == "" && exitStatus != 0 {
+		message = recorder.lastError
+	}
+	entry := recorder.entry
+	entry.ExitStatus = exitStatus
+	entry.Error = message
+	entry.Result = ResultSuccess
+	if exitStatus != 0 {
+		entry.Result = ResultFailure
+	}
+	err := Append(entry)
+	if err != nil {
+		return err
+	}
+	if serviceLog && recorder.sender != nil && entry.Cluster != "" {
+		err = recorder.sender(entry)
+		if err != nil {
+			return fmt.Errorf("Failed to add audit entry to the service log of cluster '%s': %v",
+				entry.Cluster, err)
+		}
+	}
+	return nil
+}
+
+// Location returns the location of the audit log. It is in the configuration directory, and it is
+// shared by all the profiles.
+func Location() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "audit.log"), nil
+}
+
+// Append adds the entry to the audit log, in JSON format, one entry per line.
+func Append(entry *Entry) error {
+	file, err := Location()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal audit entry: %v", err)
+	}
+	err = os.MkdirAll(filepath.Dir(file), 0700)
+	if err != nil {
+		return fmt.Errorf("Failed to create audit log directory: %v", err)
+	}
+	// #nosec G304
+	output, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to open audit log '%s': %v", file, err)
+	}
+	_, err = output.Write(append(data, '\n'))
+	if err != nil {
+		output.Close()
+		return fmt.Errorf("Failed to write audit log '%s': %v", file, err)
+	}
+	return output.Close()
+}
+
+// Describe returns a human readable description of the entry, used as the summary and description
+// of the service log entries.
+func Describe(entry *Entry) (summary string, description string) {
+	who := entry.AWSUser
+	if who == "" {
+		who = entry.LocalUser
+	}
+	summary = fmt.Sprintf("rosa %s: %s", entry.Command, entry.Result)
+	names := make([]string, 0, len(entry.Parameters))
+	for name := range entry.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parameters := make([]string, len(names))
+	for i, name := range names {
+		parameters[i] = fmt.Sprintf("--%s=%s", name, entry.Parameters[name])
+	}
+	command := strings.Join(append([]string{"rosa", entry.Command}, append(parameters,
+		entry.Arguments...)...), " ")
+	description = fmt.Sprintf("User '%s' ran '%s' with result '%s' and exit status %d.",
+		who, command, entry.Result, entry.ExitStatus)
+	if entry.Error != "" {
+		description += " Error: " + entry.Error
+	}
+	return
+}
+
+func isSecret(name string) bool {
+	name = strings.ToLower(name)
+	for _, word := range secretWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
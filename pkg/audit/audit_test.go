@@ -0,0 +1,161 @@
+package audit_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/moactl/pkg/audit"
+)
+
+var _ = Describe("Audit", func() {
+	var dir string
+	var root *cobra.Command
+	var create *cobra.Command
+	var list *cobra.Command
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "rosa-audit")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Setenv("ROSA_CONFIG_DIR", dir)).To(Succeed())
+
+		root = &cobra.Command{Use: "rosa"}
+		create = &cobra.Command{Use: "idp", Run: func(*cobra.Command, []string) {}}
+		create.Flags().String("cluster", "", "")
+		create.Flags().String("client-secret", "", "")
+		create.Flags().String("type", "", "")
+		createVerb := &cobra.Command{Use: "create"}
+		createVerb.AddCommand(create)
+		list = &cobra.Command{Use: "idps", Run: func(*cobra.Command, []string) {}}
+		listVerb := &cobra.Command{Use: "list"}
+		listVerb.AddCommand(list)
+		root.AddCommand(createVerb, listVerb)
+	})
+
+	AfterEach(func() {
+		Expect(os.Unsetenv("ROSA_CONFIG_DIR")).To(Succeed())
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	readEntries := func() []*audit.Entry {
+		file, err := audit.Location()
+		Expect(err).NotTo(HaveOccurred())
+		input, err := os.Open(file)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		Expect(err).NotTo(HaveOccurred())
+		defer input.Close()
+		entries := []*audit.Entry{}
+		scanner := bufio.NewScanner(input)
+		for scanner.Scan() {
+			entry := &audit.Entry{}
+			Expect(json.Unmarshal(scanner.Bytes(), entry)).To(Succeed())
+			entries = append(entries, entry)
+		}
+		return entries
+	}
+
+	It("Records mutating commands with redacted secrets", func() {
+		Expect(create.Flags().Parse([]string{
+			"--cluster=mycluster", "--client-secret=s3cr3t", "--type=github",
+		})).To(Succeed())
+		audit.Start(create, []string{})
+		audit.SetAWSUser("arn:aws:iam::123456789012:user/alice")
+		Expect(audit.Finish(3, "Not found")).To(Succeed())
+		// Only the first call writes the entry:
+		Expect(audit.Finish(0, "")).To(Succeed())
+
+		entries := readEntries()
+		Expect(entries).To(HaveLen(1))
+		entry := entries[0]
+		Expect(entry.Command).To(Equal("create idp"))
+		Expect(entry.Cluster).To(Equal("mycluster"))
+		Expect(entry.Parameters).To(Equal(map[string]string{
+			"cluster":       "mycluster",
+			"client-secret": "REDACTED",
+			"type":          "github",
+		}))
+		Expect(entry.AWSUser).To(Equal("arn:aws:iam::123456789012:user/alice"))
+		Expect(entry.Result).To(Equal(audit.ResultFailure))
+		Expect(entry.ExitStatus).To(Equal(3))
+		Expect(entry.Error).To(Equal("Not found"))
+	})
+
+	It("Records the name of the cluster that is created", func() {
+		cluster := &cobra.Command{Use: "cluster", Run: func(*cobra.Command, []string) {}}
+		cluster.Flags().String("cluster-name", "", "")
+		create.Parent().AddCommand(cluster)
+		Expect(cluster.Flags().Parse([]string{"--cluster-name=mycluster"})).To(Succeed())
+		audit.Start(cluster, []string{})
+		Expect(audit.Finish(0, "")).To(Succeed())
+
+		entries := readEntries()
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0].Command).To(Equal("create cluster"))
+		Expect(entries[0].Cluster).To(Equal("mycluster"))
+	})
+
+	It("Uses the last reported error when the command fails", func() {
+		Expect(create.Flags().Parse([]string{"--cluster=mycluster"})).To(Succeed())
+		audit.Start(create, []string{})
+		audit.SetError("Failed to close OCM connection")
+		audit.SetError("Cluster 'mycluster' is not yet ready")
+		Expect(audit.Finish(6, "")).To(Succeed())
+
+		entries := readEntries()
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0].Result).To(Equal(audit.ResultFailure))
+		Expect(entries[0].ExitStatus).To(Equal(6))
+		Expect(entries[0].Error).To(Equal("Cluster 'mycluster' is not yet ready"))
+	})
+
+	It("Ignores reported errors when the command succeeds", func() {
+		audit.Start(create, []string{})
+		audit.SetError("Failed to grant role to user 'alice'")
+		Expect(audit.Finish(0, "")).To(Succeed())
+
+		entries := readEntries()
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0].Result).To(Equal(audit.ResultSuccess))
+		Expect(entries[0].Error).To(BeEmpty())
+	})
+
+	It("Doesn't record commands that don't change resources", func() {
+		audit.Start(list, []string{})
+		Expect(audit.Finish(0, "")).To(Succeed())
+		Expect(readEntries()).To(BeEmpty())
+	})
+
+	It("Sends the entries to the service log when requested", func() {
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		audit.AddFlag(flags)
+		Expect(flags.Parse([]string{"--audit-service-log"})).To(Succeed())
+		defer func() {
+			Expect(flags.Parse([]string{"--audit-service-log=false"})).To(Succeed())
+		}()
+		var sent *audit.Entry
+		audit.SetSender(func(entry *audit.Entry) error {
+			sent = entry
+			return nil
+		})
+		defer audit.SetSender(nil)
+
+		Expect(create.Flags().Parse([]string{"--cluster=mycluster"})).To(Succeed())
+		audit.Start(create, []string{})
+		Expect(audit.Finish(0, "")).To(Succeed())
+		Expect(sent).NotTo(BeNil())
+		Expect(sent.Result).To(Equal(audit.ResultSuccess))
+
+		summary, description := audit.Describe(sent)
+		Expect(summary).To(Equal("rosa create idp: success"))
+		Expect(description).To(ContainSubstring("'rosa create idp --cluster=mycluster'"))
+	})
+})
@@ -0,0 +1,42 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to implement the '--audit-service-log' command line option.
+
+package audit
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// AddFlag adds the audit flag to the given set of command line flags.
+func AddFlag(flags *pflag.FlagSet) {
+	flags.BoolVar(
+		&serviceLog,
+		"audit-service-log",
+		false,
+		"In addition to the local audit log, add an entry to the OCM service log of the cluster "+
+			"changed by the command.",
+	)
+}
+
+// ServiceLog returns true if the entries of the audit log are also added to the OCM service log.
+func ServiceLog() bool {
+	return serviceLog
+}
+
+// serviceLog is the value of the '--audit-service-log' command line option.
+var serviceLog bool
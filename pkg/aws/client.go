@@ -17,9 +17,11 @@ limitations under the License.
 package aws
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -27,6 +29,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/client"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
@@ -35,17 +38,25 @@ import (
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
 	"github.com/aws/aws-sdk-go/service/organizations"
 	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+	"github.com/aws/aws-sdk-go/service/pricing"
+	"github.com/aws/aws-sdk-go/service/pricing/pricingiface"
 	"github.com/aws/aws-sdk-go/service/servicequotas"
 	"github.com/aws/aws-sdk-go/service/servicequotas/servicequotasiface"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 	"github.com/sirupsen/logrus"
 
+	"github.com/openshift/moactl/pkg/audit"
 	"github.com/openshift/moactl/pkg/aws/profile"
+	"github.com/openshift/moactl/pkg/aws/role"
 	"github.com/openshift/moactl/pkg/aws/tags"
 	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/metrics"
+	"github.com/openshift/moactl/pkg/timeout"
 )
 
 // Name of the AWS user that will be used to create all the resources of the cluster:
@@ -54,7 +65,8 @@ const (
 	OsdCcsAdminStackName = "osdCcsAdminIAMUser"
 
 	// Since CloudFormation stacks are region-dependent, we hard-code OCM's default region and
-	// then use it to ensure that the user always gets the stack from the same region.
+	// then use it to ensure that the user always gets the stack from the same region. Use
+	// GetDefaultRegion, which also takes the AWS partition into account.
 	DefaultRegion = "us-east-1"
 )
 
@@ -71,8 +83,26 @@ type Client interface {
 	GetCreator() (*Creator, error)
 	TagUser(username string, clusterID string, clusterName string) error
 	ValidateSCP(*string) (bool, error)
+	SimulatePermissions(context.Context, *string) ([]ActionResult, error)
 	GetSubnetIDs() ([]*ec2.Subnet, error)
+	ValidateSubnets(subnetIDs []string, multiAZ bool) ([]string, error)
+	ValidatePrivateSubnets(subnetIDs []string) error
+	GetSubnetVPC(subnetID string) (string, error)
+	GetVPCDNSAttributes(vpcID string) (dnsSupport bool, dnsHostnames bool, err error)
+	GetVPCCIDRBlocks(vpcID string) ([]string, error)
+	GetSubnetEgress(subnetID string) (string, error)
 	ValidateQuota() (bool, error)
+	GetServiceQuotaValue(serviceCode string, quotaCode string) (float64, error)
+	ValidateKMSKey(keyARN string) error
+	EnsureRole(spec RoleSpec) (string, error)
+	ListManagedRoles(namePrefix string) ([]Role, error)
+	DeleteRole(name string) error
+	EnsureOIDCProvider(issuerURL string, thumbprint string) (string, error)
+	GetOIDCProviderARN(issuerURL string) (string, error)
+	ListOIDCProviders() ([]OIDCProvider, error)
+	DeleteOIDCProvider(providerARN string) error
+	GetUserTags(username string) (map[string]string, error)
+	GetHourlyPrice(serviceCode string, filters map[string]string) (float64, error)
 }
 
 // ClientBuilder contains the information and logic needed to build a new AWS client.
@@ -90,8 +120,10 @@ type awsClient struct {
 	stsClient           stsiface.STSAPI
 	cfClient            cloudformationiface.CloudFormationAPI
 	servicequotasClient servicequotasiface.ServiceQuotasAPI
+	kmsClient           kmsiface.KMSAPI
 	awsSession          *session.Session
 	awsAccessKeys       *AccessKey
+	pricingClient       pricingiface.PricingAPI
 }
 
 // NewClient creates a builder that can then be used to configure and build a new AWS client.
@@ -107,6 +139,7 @@ func New(
 	stsClient stsiface.STSAPI,
 	cfClient cloudformationiface.CloudFormationAPI,
 	servicequotasClient servicequotasiface.ServiceQuotasAPI,
+	kmsClient kmsiface.KMSAPI,
 	awsSession *session.Session,
 	awsAccessKeys *AccessKey,
 
@@ -119,8 +152,10 @@ func New(
 		stsClient,
 		cfClient,
 		servicequotasClient,
+		kmsClient,
 		awsSession,
 		awsAccessKeys,
+		nil,
 	}
 }
 
@@ -141,6 +176,32 @@ func (b *ClientBuilder) AccessKeys(value *AccessKey) *ClientBuilder {
 	return b
 }
 
+// assumedRoleCredentials returns the credentials of the role given in the command line, created
+// from the given session. The credentials are shared by all the clients, so that the role is
+// assumed, and the MFA token requested, only once.
+func assumedRoleCredentials(sess *session.Session) *credentials.Credentials {
+	roleCredentialsLock.Lock()
+	defer roleCredentialsLock.Unlock()
+	if roleCredentials == nil {
+		roleCredentials = stscreds.NewCredentials(sess, role.ARN(), func(p *stscreds.AssumeRoleProvider) {
+			p.RoleSessionName = role.SessionName
+			if role.ExternalID() != "" {
+				p.ExternalID = aws.String(role.ExternalID())
+			}
+			if role.MFASerial() != "" {
+				p.SerialNumber = aws.String(role.MFASerial())
+				p.TokenProvider = stscreds.StdinTokenProvider
+			}
+		})
+	}
+	return roleCredentials
+}
+
+var (
+	roleCredentials     *credentials.Credentials
+	roleCredentialsLock sync.Mutex
+)
+
 // Create AWS session with a specific set of credentials
 func (b *ClientBuilder) BuildSessionWithOptionsCredentials(value *AccessKey) (*session.Session, error) {
 	return session.NewSessionWithOptions(session.Options{
@@ -156,6 +217,8 @@ func (b *ClientBuilder) BuildSessionWithOptions() (*session.Session, error) {
 	return session.NewSessionWithOptions(session.Options{
 		SharedConfigState: session.SharedConfigEnable,
 		Profile:           profile.Profile(),
+		// Profiles that assume a role may require an MFA token:
+		AssumeRoleTokenProvider: stscreds.StdinTokenProvider,
 		Config: aws.Config{
 			CredentialsChainVerboseErrors: aws.Bool(true),
 			Region:                        b.region,
@@ -194,6 +257,22 @@ func (b *ClientBuilder) Build() (Client, error) {
 		b.logger.Debugf("Using AWS profile: %s", profile.Profile())
 	}
 
+	// Assume the role given in the command line, unless the client uses explicit access keys:
+	if b.credentials == nil && role.ARN() != "" {
+		err = role.Validate()
+		if err != nil {
+			return nil, err
+		}
+		b.logger.Debugf("Assuming AWS role: %s", role.ARN())
+		sess = sess.Copy(&aws.Config{
+			Credentials: assumedRoleCredentials(sess),
+		})
+		_, err = sess.Config.Credentials.Get()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to assume role '%s': %v", role.ARN(), err)
+		}
+	}
+
 	// Check that the AWS credentials are available:
 	_, err = sess.Config.Credentials.Get()
 	if err != nil {
@@ -201,11 +280,23 @@ func (b *ClientBuilder) Build() (Client, error) {
 		return nil, fmt.Errorf("Failed to find credentials. Check your AWS configuration and try again")
 	}
 
-	// Check that the region is set:
+	// Check that the region is set, and that it is in the partition used:
 	region := aws.StringValue(sess.Config.Region)
 	if region == "" {
 		return nil, fmt.Errorf("Region is not set")
 	}
+	err = ValidatePartitionRegion(region)
+	if err != nil {
+		return nil, err
+	}
+
+	// Limit the time of each request:
+	limiter, err := timeout.NewRoundTripper().
+		Next(http.DefaultTransport).
+		Build()
+	if err != nil {
+		return nil, err
+	}
 
 	// Update session config
 	sess = sess.Copy(&aws.Config{
@@ -218,10 +309,17 @@ func (b *ClientBuilder) Build() (Client, error) {
 		},
 		Logger: logger,
 		HTTPClient: &http.Client{
-			Transport: http.DefaultTransport,
+			Transport: limiter,
 		},
 	})
 
+	if metrics.Enabled() {
+		sess.Config.HTTPClient.Transport = metrics.NewRoundTripper(
+			metrics.AWSAPI,
+			sess.Config.HTTPClient.Transport,
+		)
+	}
+
 	if b.logger.IsLevelEnabled(logrus.DebugLevel) {
 		var dumper http.RoundTripper
 		dumper, err = logging.NewRoundTripper().
@@ -243,7 +341,9 @@ func (b *ClientBuilder) Build() (Client, error) {
 		stsClient:           sts.New(sess),
 		cfClient:            cloudformation.New(sess),
 		servicequotasClient: servicequotas.New(sess),
+		kmsClient:           kms.New(sess),
 		awsSession:          sess,
+		pricingClient:       pricing.New(sess, aws.NewConfig().WithRegion(pricingRegion)),
 	}
 
 	_, root, err := getClientDetails(c)
@@ -278,6 +378,7 @@ func (c *awsClient) GetSubnetIDs() ([]*ec2.Subnet, error) {
 type Creator struct {
 	ARN       string
 	AccountID string
+	Partition string
 }
 
 func (c *awsClient) GetCreator() (*Creator, error) {
@@ -286,6 +387,7 @@ func (c *awsClient) GetCreator() (*Creator, error) {
 		return nil, err
 	}
 	creatorARN := aws.StringValue(getCallerIdentityOutput.Arn)
+	audit.SetAWSUser(creatorARN)
 
 	// Extract the account identifier from the ARN of the user:
 	creatorParsedARN, err := arn.Parse(creatorARN)
@@ -295,6 +397,7 @@ func (c *awsClient) GetCreator() (*Creator, error) {
 	return &Creator{
 		ARN:       creatorARN,
 		AccountID: creatorParsedARN.AccountID,
+		Partition: creatorParsedARN.Partition,
 	}, nil
 }
 
@@ -561,7 +664,7 @@ func (c *awsClient) ValidateAccessKeys(AccessKey *AccessKey) error {
 		// Create the AWS client
 		_, err := NewClient().
 			Logger(logger).
-			Region(DefaultRegion).
+			Region(GetDefaultRegion()).
 			AccessKeys(AccessKey).
 			Build()
 
@@ -691,10 +794,24 @@ func (c *awsClient) ValidateQuota() (bool, error) {
 	return true, nil
 }
 
+// GetServiceQuotaValue returns the current value of the given service quota in the region of the
+// client.
+func (c *awsClient) GetServiceQuotaValue(serviceCode string, quotaCode string) (float64, error) {
+	serviceQuotas, err := ListServiceQuotas(c, serviceCode)
+	if err != nil {
+		return 0, fmt.Errorf("Error listing AWS service quotas: %s %v", serviceCode, err)
+	}
+
+	serviceQuota, err := GetServiceQuota(serviceQuotas, quotaCode)
+	if err != nil || serviceQuota.Value == nil {
+		return 0, fmt.Errorf("Error getting AWS service quota: %s %v", serviceCode, err)
+	}
+
+	return *serviceQuota.Value, nil
+}
+
 // ValidateSCP attempts to validate SCP policies by ensuring we have the correct permissions
 func (c *awsClient) ValidateSCP(target *string) (bool, error) {
-	scpPolicyPath := "templates/policies/osd_scp_policy.json"
-
 	sParams := &SimulateParams{
 		Region: *c.awsSession.Config.Region,
 	}
@@ -704,19 +821,9 @@ func (c *awsClient) ValidateSCP(target *string) (bool, error) {
 	policyDocuments := []PolicyDocument{osdPolicyDocument}
 
 	// Find target user
-	var targetUser *iam.User
-	if target == nil {
-		var err error
-		targetUser, _, err = getClientDetails(c)
-		if err != nil {
-			return false, fmt.Errorf("getClientDetails: %v", err)
-		}
-	} else {
-		targetIamOutput, err := c.iamClient.GetUser(&iam.GetUserInput{UserName: target})
-		if err != nil {
-			return false, fmt.Errorf("iamClient.GetUser: %v", err)
-		}
-		targetUser = targetIamOutput.User
+	targetUser, err := c.getTargetUser(target)
+	if err != nil {
+		return false, err
 	}
 
 	// Validate permissions
@@ -730,3 +837,46 @@ func (c *awsClient) ValidateSCP(target *string) (bool, error) {
 
 	return true, nil
 }
+
+// SimulatePermissions simulates each of the actions of the OSD SCP policy and of the permissions
+// needed by the installer, returning the result for every action instead of failing on the first
+// one that isn't allowed. The simulation stops when the context is cancelled.
+func (c *awsClient) SimulatePermissions(ctx context.Context, target *string) ([]ActionResult,
+	error) {
+	sParams := &SimulateParams{
+		Region: *c.awsSession.Config.Region,
+	}
+
+	targetUser, err := c.getTargetUser(target)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []ActionResult{}
+	for _, path := range []string{scpPolicyPath, installerPolicyPath} {
+		policyResults, err := simulatePolicyDocument(ctx, c, targetUser, readSCPPolicy(path),
+			sParams)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, policyResults...)
+	}
+	return results, nil
+}
+
+// getTargetUser returns the user with the given name, or the user of the current credentials if
+// no name is given.
+func (c *awsClient) getTargetUser(target *string) (*iam.User, error) {
+	if target == nil {
+		targetUser, _, err := getClientDetails(c)
+		if err != nil {
+			return nil, fmt.Errorf("getClientDetails: %v", err)
+		}
+		return targetUser, nil
+	}
+	targetIamOutput, err := c.iamClient.GetUser(&iam.GetUserInput{UserName: target})
+	if err != nil {
+		return nil, fmt.Errorf("iamClient.GetUser: %v", err)
+	}
+	return targetIamOutput.User, nil
+}
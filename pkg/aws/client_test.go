@@ -1,8 +1,13 @@
 package aws_test
 
 import (
+	"context"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
@@ -36,6 +41,7 @@ var _ = Describe("Client", func() {
 			mocks.NewMockSTSAPI(mockCtrl),
 			mockCfAPI,
 			mocks.NewMockServiceQuotasAPI(mockCtrl),
+			mocks.NewMockKMSAPI(mockCtrl),
 			&session.Session{},
 			&aws.AccessKey{},
 		)
@@ -170,4 +176,213 @@ var _ = Describe("Client", func() {
 			})
 		})
 	})
+
+	Context("ValidateSubnets", func() {
+		subnet := func(id, vpc, zone string, tags ...*ec2.Tag) *ec2.Subnet {
+			return &ec2.Subnet{
+				SubnetId:         awssdk.String(id),
+				VpcId:            awssdk.String(vpc),
+				AvailabilityZone: awssdk.String(zone),
+				Tags:             tags,
+			}
+		}
+		var subnets []*ec2.Subnet
+		JustBeforeEach(func() {
+			mockEC2API.EXPECT().DescribeSubnets(gomock.Any()).Return(&ec2.DescribeSubnetsOutput{
+				Subnets: subnets,
+			}, nil)
+		})
+		Context("When the subnets span the required availability zones", func() {
+			BeforeEach(func() {
+				subnets = []*ec2.Subnet{
+					subnet("subnet-1", "vpc-1", "us-east-1a"),
+					subnet("subnet-2", "vpc-1", "us-east-1a"),
+					subnet("subnet-3", "vpc-1", "us-east-1b"),
+					subnet("subnet-4", "vpc-1", "us-east-1c"),
+				}
+			})
+			It("returns the availability zones", func() {
+				zones, err := client.ValidateSubnets(
+					[]string{"subnet-1", "subnet-2", "subnet-3", "subnet-4"}, true)
+
+				Expect(err).NotTo(HaveOccurred())
+				Expect(zones).To(Equal([]string{"us-east-1a", "us-east-1b", "us-east-1c"}))
+			})
+			It("returns an error for a single-AZ cluster", func() {
+				_, err := client.ValidateSubnets(
+					[]string{"subnet-1", "subnet-2", "subnet-3", "subnet-4"}, false)
+
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("span 1 availability zone(s)"))
+			})
+		})
+		Context("When a subnet doesn't exist", func() {
+			BeforeEach(func() {
+				subnets = []*ec2.Subnet{
+					subnet("subnet-1", "vpc-1", "us-east-1a"),
+				}
+			})
+			It("returns an error", func() {
+				_, err := client.ValidateSubnets([]string{"subnet-1", "subnet-2"}, false)
+
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("subnet-2"))
+			})
+		})
+		Context("When the subnets belong to different VPCs", func() {
+			BeforeEach(func() {
+				subnets = []*ec2.Subnet{
+					subnet("subnet-1", "vpc-1", "us-east-1a"),
+					subnet("subnet-2", "vpc-2", "us-east-1a"),
+				}
+			})
+			It("returns an error", func() {
+				_, err := client.ValidateSubnets([]string{"subnet-1", "subnet-2"}, false)
+
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("same VPC"))
+			})
+		})
+		Context("When a subnet is owned by another cluster", func() {
+			BeforeEach(func() {
+				subnets = []*ec2.Subnet{
+					subnet("subnet-1", "vpc-1", "us-east-1a", &ec2.Tag{
+						Key:   awssdk.String("kubernetes.io/cluster/other-abcde"),
+						Value: awssdk.String("owned"),
+					}),
+				}
+			})
+			It("returns an error", func() {
+				_, err := client.ValidateSubnets([]string{"subnet-1"}, false)
+
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("other-abcde"))
+			})
+		})
+	})
+
+	Context("ValidatePrivateSubnets", func() {
+		routeTable := func(routes ...*ec2.Route) *ec2.DescribeRouteTablesOutput {
+			return &ec2.DescribeRouteTablesOutput{
+				RouteTables: []*ec2.RouteTable{{Routes: routes}},
+			}
+		}
+		defaultRoute := func(route *ec2.Route) *ec2.Route {
+			route.DestinationCidrBlock = awssdk.String("0.0.0.0/0")
+			return route
+		}
+		It("accepts subnets that reach the internet through a NAT gateway", func() {
+			mockEC2API.EXPECT().DescribeRouteTables(gomock.Any()).Return(routeTable(
+				defaultRoute(&ec2.Route{NatGatewayId: awssdk.String("nat-1")}),
+			), nil)
+
+			Expect(client.ValidatePrivateSubnets([]string{"subnet-1"})).To(Succeed())
+		})
+		It("rejects subnets that reach the internet through an internet gateway", func() {
+			mockEC2API.EXPECT().DescribeRouteTables(gomock.Any()).Return(routeTable(
+				defaultRoute(&ec2.Route{NatGatewayId: awssdk.String("nat-1")}),
+			), nil)
+			mockEC2API.EXPECT().DescribeRouteTables(gomock.Any()).Return(routeTable(
+				defaultRoute(&ec2.Route{GatewayId: awssdk.String("igw-1")}),
+			), nil)
+
+			err := client.ValidatePrivateSubnets([]string{"subnet-1", "subnet-2"})
+
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("'subnet-2' (through 'igw-1')"))
+			Expect(err.Error()).NotTo(ContainSubstring("subnet-1"))
+		})
+	})
+
+	Context("GetVPCCIDRBlocks", func() {
+		It("returns the associated CIDR blocks", func() {
+			mockEC2API.EXPECT().DescribeVpcs(gomock.Any()).Return(&ec2.DescribeVpcsOutput{
+				Vpcs: []*ec2.Vpc{{
+					CidrBlock: awssdk.String("10.0.0.0/16"),
+					CidrBlockAssociationSet: []*ec2.VpcCidrBlockAssociation{
+						{
+							CidrBlock: awssdk.String("10.0.0.0/16"),
+							CidrBlockState: &ec2.VpcCidrBlockState{
+								State: awssdk.String(ec2.VpcCidrBlockStateCodeAssociated),
+							},
+						},
+						{
+							CidrBlock: awssdk.String("10.1.0.0/16"),
+							CidrBlockState: &ec2.VpcCidrBlockState{
+								State: awssdk.String(ec2.VpcCidrBlockStateCodeDisassociated),
+							},
+						},
+					},
+				}},
+			}, nil)
+			blocks, err := client.GetVPCCIDRBlocks("vpc-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(blocks).To(Equal([]string{"10.0.0.0/16"}))
+		})
+	})
+
+	Context("SimulatePermissions", func() {
+		BeforeEach(func() {
+			client = aws.New(
+				logrus.New(),
+				mockIamAPI,
+				mockEC2API,
+				mocks.NewMockOrganizationsAPI(mockCtrl),
+				mocks.NewMockSTSAPI(mockCtrl),
+				mockCfAPI,
+				mocks.NewMockServiceQuotasAPI(mockCtrl),
+				mocks.NewMockKMSAPI(mockCtrl),
+				&session.Session{Config: &awssdk.Config{Region: awssdk.String("us-east-1")}},
+				&aws.AccessKey{},
+			)
+			mockIamAPI.EXPECT().GetUser(gomock.Any()).Return(&iam.GetUserOutput{
+				User: &iam.User{
+					UserName: awssdk.String("fake-user"),
+					Arn:      awssdk.String("arn:aws:iam::123456789012:user/fake-user"),
+				},
+			}, nil)
+			mockIamAPI.EXPECT().SimulatePrincipalPolicyPagesWithContext(gomock.Any(), gomock.Any(),
+				gomock.Any()).DoAndReturn(
+				func(ctx context.Context, input *iam.SimulatePrincipalPolicyInput,
+					fn func(*iam.SimulatePolicyResponse, bool) bool, opts ...request.Option) error {
+					response := &iam.SimulatePolicyResponse{}
+					for _, action := range input.ActionNames {
+						decision := iam.PolicyEvaluationDecisionTypeAllowed
+						var detail *iam.OrganizationsDecisionDetail
+						if *action == "ec2:RunInstances" {
+							decision = iam.PolicyEvaluationDecisionTypeExplicitDeny
+							detail = &iam.OrganizationsDecisionDetail{
+								AllowedByOrganizations: awssdk.Bool(false),
+							}
+						}
+						response.EvaluationResults = append(response.EvaluationResults,
+							&iam.EvaluationResult{
+								EvalActionName:              action,
+								EvalDecision:                awssdk.String(decision),
+								OrganizationsDecisionDetail: detail,
+							})
+					}
+					fn(response, true)
+					return nil
+				}).Times(2)
+		})
+		It("returns the result of each action", func() {
+			results, err := client.SimulatePermissions(context.Background(), awssdk.String("fake-user"))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).NotTo(BeEmpty())
+			denied := []aws.ActionResult{}
+			for _, result := range results {
+				if !result.Allowed() {
+					denied = append(denied, result)
+				}
+			}
+			Expect(denied).NotTo(BeEmpty())
+			for _, result := range denied {
+				Expect(result.Action).To(Equal("ec2:RunInstances"))
+				Expect(result.Decision).To(Equal(iam.PolicyEvaluationDecisionTypeExplicitDeny))
+				Expect(result.DeniedByOrganizations).To(BeTrue())
+			}
+		})
+	})
 })
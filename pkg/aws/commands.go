@@ -0,0 +1,89 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used by the manual mode to render the AWS CLI commands that
+// are equivalent to the changes that rosa would otherwise make with the AWS API, for environments
+// where rosa can't use credentials that are allowed to change the AWS account.
+
+package aws
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/openshift/moactl/pkg/aws/tags"
+)
+
+// CFTemplateFile is the name of the file where the manual mode writes the CloudFormation template
+// that creates the administrator user.
+const CFTemplateFile = "iam_user_osdCcsAdmin.json"
+
+// WriteCFTemplate writes the CloudFormation template that creates the administrator user to the
+// given directory, and returns the name of the file.
+func WriteCFTemplate(dir string) (string, error) {
+	body, err := readCFTemplate()
+	if err != nil {
+		return "", err
+	}
+	file := filepath.Join(dir, CFTemplateFile)
+	err = ioutil.WriteFile(file, []byte(body), 0600)
+	if err != nil {
+		return "", fmt.Errorf("Failed to write file '%s': %v", file, err)
+	}
+	return file, nil
+}
+
+// CreateStackCommands returns the AWS CLI commands that create the CloudFormation stack from the
+// given template file and wait till it is complete.
+func CreateStackCommands(stackName string, templateFile string, region string) []string {
+	return []string{
+		fmt.Sprintf("aws cloudformation create-stack --stack-name %s --template-body file://%s "+
+			"--capabilities CAPABILITY_IAM CAPABILITY_NAMED_IAM --region %s",
+			stackName, templateFile, region),
+		fmt.Sprintf("aws cloudformation wait stack-create-complete --stack-name %s --region %s",
+			stackName, region),
+	}
+}
+
+// UpdateStackCommands returns the AWS CLI commands that update the CloudFormation stack from the
+// given template file and wait till it is complete.
+func UpdateStackCommands(stackName string, templateFile string, region string) []string {
+	return []string{
+		fmt.Sprintf("aws cloudformation update-stack --stack-name %s --template-body file://%s "+
+			"--capabilities CAPABILITY_IAM CAPABILITY_NAMED_IAM --region %s",
+			stackName, templateFile, region),
+		fmt.Sprintf("aws cloudformation wait stack-update-complete --stack-name %s --region %s",
+			stackName, region),
+	}
+}
+
+// DeleteStackCommands returns the AWS CLI commands that delete the CloudFormation stack and wait
+// till it is deleted.
+func DeleteStackCommands(stackName string, region string) []string {
+	return []string{
+		fmt.Sprintf("aws cloudformation delete-stack --stack-name %s --region %s", stackName, region),
+		fmt.Sprintf("aws cloudformation wait stack-delete-complete --stack-name %s --region %s",
+			stackName, region),
+	}
+}
+
+// TagUserCommand returns the AWS CLI command that adds the tags with the identifier and the name
+// of the cluster to the given user, like TagUser does.
+func TagUserCommand(username string, clusterID string, clusterName string) string {
+	return fmt.Sprintf("aws iam tag-user --user-name %s --tags Key=%s,Value=%s Key=%s,Value=%s",
+		username, tags.ClusterID, clusterID, tags.ClusterName, clusterName)
+}
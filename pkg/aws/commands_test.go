@@ -0,0 +1,45 @@
+package aws_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+)
+
+var _ = Describe("Manual mode commands", func() {
+	It("Writes the CloudFormation template of the administrator user", func() {
+		dir, err := ioutil.TempDir("", "rosa-manual")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		file, err := aws.WriteCFTemplate(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(file).To(Equal(filepath.Join(dir, aws.CFTemplateFile)))
+		data, err := ioutil.ReadFile(file)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring(aws.AdminUserName))
+	})
+
+	It("Creates and deletes the CloudFormation stack", func() {
+		Expect(aws.CreateStackCommands("mystack", "template.json", "us-east-1")).To(Equal([]string{
+			"aws cloudformation create-stack --stack-name mystack --template-body file://template.json " +
+				"--capabilities CAPABILITY_IAM CAPABILITY_NAMED_IAM --region us-east-1",
+			"aws cloudformation wait stack-create-complete --stack-name mystack --region us-east-1",
+		}))
+		Expect(aws.DeleteStackCommands("mystack", "us-east-1")).To(Equal([]string{
+			"aws cloudformation delete-stack --stack-name mystack --region us-east-1",
+			"aws cloudformation wait stack-delete-complete --stack-name mystack --region us-east-1",
+		}))
+	})
+
+	It("Tags the administrator user", func() {
+		Expect(aws.TagUserCommand("osdCcsAdmin", "123", "mycluster")).To(Equal(
+			"aws iam tag-user --user-name osdCcsAdmin --tags Key=rosa_cluster_id,Value=123 " +
+				"Key=rosa_cluster_name,Value=mycluster"))
+	})
+})
@@ -0,0 +1,220 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to check that a customer managed KMS key can be used to
+// encrypt the EBS volumes of the nodes of a cluster.
+
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/kms"
+)
+
+// kmsKeyUsageActions are the actions that the installer and the nodes need to be allowed to perform
+// with the key in order to create encrypted EBS volumes.
+var kmsKeyUsageActions = []string{
+	"kms:CreateGrant",
+	"kms:Decrypt",
+	"kms:DescribeKey",
+	"kms:Encrypt",
+	"kms:GenerateDataKeyWithoutPlaintext",
+	"kms:ReEncryptFrom",
+	"kms:ReEncryptTo",
+}
+
+// kmsKeyPolicy is the part of the policy document of a KMS key that is needed to check which
+// principals can use the key.
+type kmsKeyPolicy struct {
+	Statement []kmsKeyPolicyStatement `json:"Statement"`
+}
+
+type kmsKeyPolicyStatement struct {
+	Effect    string          `json:"Effect"`
+	Principal json.RawMessage `json:"Principal"`
+	Action    json.RawMessage `json:"Action"`
+}
+
+// ValidateKMSKeyARN checks that the given ARN is the ARN of a KMS key in the given region.
+func ValidateKMSKeyARN(keyARN string, region string) error {
+	parsed, err := arn.Parse(keyARN)
+	if err != nil || parsed.Service != "kms" || !strings.HasPrefix(parsed.Resource, "key/") {
+		return fmt.Errorf("Expected a valid KMS key ARN like "+
+			"'arn:%s:kms:%s:123456789012:key/...', got '%s'", PartitionForRegion(region), region, keyARN)
+	}
+	if parsed.Region != region {
+		return fmt.Errorf("KMS key '%s' is in region '%s', expected a key in the region '%s' "+
+			"of the cluster", keyARN, parsed.Region, region)
+	}
+	return nil
+}
+
+// ValidateKMSKey checks that the KMS key with the given ARN exists in the region of the client,
+// that it is an enabled symmetric key, and that its policy allows the account, or the user that
+// creates the resources of the cluster, to use it.
+func (c *awsClient) ValidateKMSKey(keyARN string) error {
+	err := ValidateKMSKeyARN(keyARN, c.GetRegion())
+	if err != nil {
+		return err
+	}
+
+	describeOutput, err := c.kmsClient.DescribeKey(&kms.DescribeKeyInput{
+		KeyId: aws.String(keyARN),
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to find KMS key '%s': %v", keyARN, err)
+	}
+	metadata := describeOutput.KeyMetadata
+	if !aws.BoolValue(metadata.Enabled) ||
+		aws.StringValue(metadata.KeyState) != kms.KeyStateEnabled {
+		return fmt.Errorf("KMS key '%s' is in state '%s', expected an enabled key",
+			keyARN, aws.StringValue(metadata.KeyState))
+	}
+	spec := aws.StringValue(metadata.CustomerMasterKeySpec)
+	if spec != "" && spec != kms.CustomerMasterKeySpecSymmetricDefault {
+		return fmt.Errorf("KMS key '%s' has spec '%s', expected a symmetric key", keyARN, spec)
+	}
+	usage := aws.StringValue(metadata.KeyUsage)
+	if usage != "" && usage != kms.KeyUsageTypeEncryptDecrypt {
+		return fmt.Errorf("KMS key '%s' has usage '%s', expected a key used to encrypt and decrypt",
+			keyARN, usage)
+	}
+
+	policyOutput, err := c.kmsClient.GetKeyPolicy(&kms.GetKeyPolicyInput{
+		KeyId:      metadata.KeyId,
+		PolicyName: aws.String("default"),
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to get policy of KMS key '%s': %v", keyARN, err)
+	}
+	creator, err := c.GetCreator()
+	if err != nil {
+		return err
+	}
+	principals := []string{
+		IAMARN(creator.Partition, creator.AccountID, "root"),
+		IAMARN(creator.Partition, creator.AccountID, "user/"+AdminUserName),
+	}
+	allowed, err := kmsKeyPolicyAllows(aws.StringValue(policyOutput.Policy), principals)
+	if err != nil {
+		return fmt.Errorf("Failed to parse policy of KMS key '%s': %v", keyARN, err)
+	}
+	if !allowed {
+		return fmt.Errorf("Policy of KMS key '%s' doesn't allow the account '%s' or the user '%s' "+
+			"to use the key, the actions %s need to be allowed", keyARN, creator.AccountID,
+			AdminUserName, strings.Join(kmsKeyUsageActions, ", "))
+	}
+	return nil
+}
+
+// kmsKeyPolicyAllows checks if the policy document allows one of the principals to perform all the
+// actions needed to use the key.
+func kmsKeyPolicyAllows(document string, principals []string) (bool, error) {
+	policy := kmsKeyPolicy{}
+	err := json.Unmarshal([]byte(document), &policy)
+	if err != nil {
+		return false, err
+	}
+	for _, principal := range principals {
+		allowed := map[string]bool{}
+		for _, statement := range policy.Statement {
+			if statement.Effect != "Allow" {
+				continue
+			}
+			statementPrincipals, err := policyPrincipals(statement.Principal)
+			if err != nil {
+				return false, err
+			}
+			if !matchesAny(principal, statementPrincipals) {
+				continue
+			}
+			actions, err := stringOrList(statement.Action)
+			if err != nil {
+				return false, err
+			}
+			for _, action := range kmsKeyUsageActions {
+				if matchesAny(action, actions) {
+					allowed[action] = true
+				}
+			}
+		}
+		if len(allowed) == len(kmsKeyUsageActions) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// policyPrincipals returns the AWS principals of a statement, which can be '*', a single ARN, or an
+// object with a list of ARNs.
+func policyPrincipals(value json.RawMessage) ([]string, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+	var wildcard string
+	if json.Unmarshal(value, &wildcard) == nil {
+		return []string{wildcard}, nil
+	}
+	var object struct {
+		AWS json.RawMessage `json:"AWS"`
+	}
+	err := json.Unmarshal(value, &object)
+	if err != nil {
+		return nil, err
+	}
+	return stringOrList(object.AWS)
+}
+
+// stringOrList decodes a policy value that can be either a single string or a list of strings.
+func stringOrList(value json.RawMessage) ([]string, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+	var single string
+	if json.Unmarshal(value, &single) == nil {
+		return []string{single}, nil
+	}
+	var list []string
+	err := json.Unmarshal(value, &list)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// matchesAny checks if the value matches one of the patterns. Patterns can end with a '*' that
+// matches any suffix, and are compared without case as the actions of policies are.
+func matchesAny(value string, patterns []string) bool {
+	value = strings.ToLower(value)
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(pattern)
+		if strings.HasSuffix(pattern, "*") {
+			if strings.HasPrefix(value, strings.TrimSuffix(pattern, "*")) {
+				return true
+			}
+			continue
+		}
+		if value == pattern {
+			return true
+		}
+	}
+	return false
+}
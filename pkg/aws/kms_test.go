@@ -0,0 +1,132 @@
+package aws_test
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mocks"
+)
+
+var _ = Describe("ValidateKMSKey", func() {
+	const (
+		keyARN        = "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+		defaultPolicy = `{
+			"Version": "2012-10-17",
+			"Statement": [{
+				"Sid": "Enable IAM User Permissions",
+				"Effect": "Allow",
+				"Principal": {"AWS": "arn:aws:iam::123456789012:root"},
+				"Action": "kms:*",
+				"Resource": "*"
+			}]
+		}`
+	)
+
+	var (
+		client   aws.Client
+		mockCtrl *gomock.Controller
+		mockKMS  *mocks.MockKMSAPI
+		mockSTS  *mocks.MockSTSAPI
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockKMS = mocks.NewMockKMSAPI(mockCtrl)
+		mockSTS = mocks.NewMockSTSAPI(mockCtrl)
+		client = aws.New(
+			logrus.New(),
+			mocks.NewMockIAMAPI(mockCtrl),
+			mocks.NewMockEC2API(mockCtrl),
+			mocks.NewMockOrganizationsAPI(mockCtrl),
+			mockSTS,
+			mocks.NewMockCloudFormationAPI(mockCtrl),
+			mocks.NewMockServiceQuotasAPI(mockCtrl),
+			mockKMS,
+			&session.Session{Config: &awssdk.Config{Region: awssdk.String("us-east-1")}},
+			&aws.AccessKey{},
+		)
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	describeKey := func(state string, spec string) {
+		mockKMS.EXPECT().DescribeKey(gomock.Any()).Return(&kms.DescribeKeyOutput{
+			KeyMetadata: &kms.KeyMetadata{
+				KeyId:                 awssdk.String("1234abcd-12ab-34cd-56ef-1234567890ab"),
+				Enabled:               awssdk.Bool(state == kms.KeyStateEnabled),
+				KeyState:              awssdk.String(state),
+				CustomerMasterKeySpec: awssdk.String(spec),
+				KeyUsage:              awssdk.String(kms.KeyUsageTypeEncryptDecrypt),
+			},
+		}, nil)
+	}
+
+	keyPolicy := func(policy string) {
+		mockKMS.EXPECT().GetKeyPolicy(gomock.Any()).Return(&kms.GetKeyPolicyOutput{
+			Policy: awssdk.String(policy),
+		}, nil)
+		mockSTS.EXPECT().GetCallerIdentity(gomock.Any()).Return(&sts.GetCallerIdentityOutput{
+			Arn: awssdk.String("arn:aws:iam::123456789012:user/me"),
+		}, nil)
+	}
+
+	It("Accepts an enabled symmetric key with the default policy", func() {
+		describeKey(kms.KeyStateEnabled, kms.CustomerMasterKeySpecSymmetricDefault)
+		keyPolicy(defaultPolicy)
+		Expect(client.ValidateKMSKey(keyARN)).To(Succeed())
+	})
+
+	It("Accepts a policy that allows the admin user", func() {
+		describeKey(kms.KeyStateEnabled, kms.CustomerMasterKeySpecSymmetricDefault)
+		keyPolicy(`{"Statement": [{
+			"Effect": "Allow",
+			"Principal": {"AWS": ["arn:aws:iam::123456789012:user/osdCcsAdmin"]},
+			"Action": ["kms:Encrypt", "kms:Decrypt", "kms:ReEncrypt*", "kms:GenerateDataKey*",
+				"kms:DescribeKey", "kms:CreateGrant"]
+		}]}`)
+		Expect(client.ValidateKMSKey(keyARN)).To(Succeed())
+	})
+
+	It("Rejects keys in other regions", func() {
+		err := client.ValidateKMSKey(
+			"arn:aws:kms:eu-west-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab")
+		Expect(err).To(MatchError(ContainSubstring("region 'us-east-1'")))
+	})
+
+	It("Rejects values that aren't KMS key ARNs", func() {
+		err := client.ValidateKMSKey("arn:aws:iam::123456789012:user/me")
+		Expect(err).To(MatchError(ContainSubstring("valid KMS key ARN")))
+	})
+
+	It("Rejects disabled keys", func() {
+		describeKey(kms.KeyStateDisabled, kms.CustomerMasterKeySpecSymmetricDefault)
+		err := client.ValidateKMSKey(keyARN)
+		Expect(err).To(MatchError(ContainSubstring("expected an enabled key")))
+	})
+
+	It("Rejects asymmetric keys", func() {
+		describeKey(kms.KeyStateEnabled, kms.CustomerMasterKeySpecRsa2048)
+		err := client.ValidateKMSKey(keyARN)
+		Expect(err).To(MatchError(ContainSubstring("expected a symmetric key")))
+	})
+
+	It("Rejects policies that don't allow the account to use the key", func() {
+		describeKey(kms.KeyStateEnabled, kms.CustomerMasterKeySpecSymmetricDefault)
+		keyPolicy(`{"Statement": [{
+			"Effect": "Allow",
+			"Principal": {"AWS": "arn:aws:iam::123456789012:root"},
+			"Action": ["kms:DescribeKey", "kms:Decrypt"]
+		}]}`)
+		err := client.ValidateKMSKey(keyARN)
+		Expect(err).To(MatchError(ContainSubstring("doesn't allow")))
+	})
+})
@@ -0,0 +1,77 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to implement the '--mode' command line option of the commands
+// that create or delete the AWS resources needed by clusters that use the AWS Security Token
+// Service.
+
+package mode
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// Modes of the commands:
+const (
+	// Auto creates or deletes the resources directly with the AWS credentials of the user.
+	Auto = "auto"
+
+	// Manual writes the policy documents to the current directory and prints the AWS CLI commands
+	// that the user needs to run, without changing anything in the AWS account.
+	Manual = "manual"
+)
+
+// Modes is the list of valid modes.
+var Modes = []string{Auto, Manual}
+
+// AddFlag adds the '--mode' flag to the given set of command line flags.
+func AddFlag(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&mode,
+		"mode",
+		Auto,
+		fmt.Sprintf("How to perform the operation. Valid options are:\n"+
+			"%s: Resources are created or deleted directly with the current AWS credentials.\n"+
+			"%s: Policy documents are written to the current directory and the AWS CLI commands "+
+			"needed to apply them are printed.", Auto, Manual),
+	)
+}
+
+// Mode returns the mode given in the command line.
+func Mode() string {
+	return mode
+}
+
+// IsManual checks if the manual mode was requested.
+func IsManual() bool {
+	return mode == Manual
+}
+
+// Validate checks that the mode is one of the valid modes.
+func Validate() error {
+	for _, valid := range Modes {
+		if mode == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("Invalid mode '%s', expected one of: %s", mode, strings.Join(Modes, ", "))
+}
+
+// mode is the value of the flag.
+var mode = Auto
@@ -0,0 +1,145 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// GetSubnetVPC returns the identifier of the VPC that the subnet belongs to.
+func (c *awsClient) GetSubnetVPC(subnetID string) (string, error) {
+	res, err := c.ec2Client.DescribeSubnets(&ec2.DescribeSubnetsInput{
+		SubnetIds: aws.StringSlice([]string{subnetID}),
+	})
+	if err != nil {
+		return "", fmt.Errorf("Failed to describe subnet '%s': %v", subnetID, err)
+	}
+	if len(res.Subnets) == 0 {
+		return "", fmt.Errorf("Could not find subnet '%s'", subnetID)
+	}
+	return aws.StringValue(res.Subnets[0].VpcId), nil
+}
+
+// GetVPCDNSAttributes returns whether DNS resolution and DNS hostnames are enabled for the VPC.
+// Both are required by the installer to resolve the internal names of the cluster.
+func (c *awsClient) GetVPCDNSAttributes(vpcID string) (dnsSupport bool, dnsHostnames bool, err error) {
+	support, err := c.ec2Client.DescribeVpcAttribute(&ec2.DescribeVpcAttributeInput{
+		VpcId:     aws.String(vpcID),
+		Attribute: aws.String(ec2.VpcAttributeNameEnableDnsSupport),
+	})
+	if err != nil {
+		return false, false, fmt.Errorf("Failed to describe attributes of VPC '%s': %v", vpcID, err)
+	}
+	hostnames, err := c.ec2Client.DescribeVpcAttribute(&ec2.DescribeVpcAttributeInput{
+		VpcId:     aws.String(vpcID),
+		Attribute: aws.String(ec2.VpcAttributeNameEnableDnsHostnames),
+	})
+	if err != nil {
+		return false, false, fmt.Errorf("Failed to describe attributes of VPC '%s': %v", vpcID, err)
+	}
+	dnsSupport = support.EnableDnsSupport != nil && aws.BoolValue(support.EnableDnsSupport.Value)
+	dnsHostnames = hostnames.EnableDnsHostnames != nil && aws.BoolValue(hostnames.EnableDnsHostnames.Value)
+	return dnsSupport, dnsHostnames, nil
+}
+
+// GetVPCCIDRBlocks returns the IPv4 CIDR blocks associated to the VPC, including the secondary
+// ones.
+func (c *awsClient) GetVPCCIDRBlocks(vpcID string) ([]string, error) {
+	res, err := c.ec2Client.DescribeVpcs(&ec2.DescribeVpcsInput{
+		VpcIds: aws.StringSlice([]string{vpcID}),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to describe VPC '%s': %v", vpcID, err)
+	}
+	if len(res.Vpcs) == 0 {
+		return nil, fmt.Errorf("Could not find VPC '%s'", vpcID)
+	}
+	blocks := []string{}
+	for _, association := range res.Vpcs[0].CidrBlockAssociationSet {
+		state := association.CidrBlockState
+		if state != nil && aws.StringValue(state.State) != ec2.VpcCidrBlockStateCodeAssociated {
+			continue
+		}
+		blocks = append(blocks, aws.StringValue(association.CidrBlock))
+	}
+	if len(blocks) == 0 && res.Vpcs[0].CidrBlock != nil {
+		blocks = append(blocks, aws.StringValue(res.Vpcs[0].CidrBlock))
+	}
+	return blocks, nil
+}
+
+// GetSubnetEgress returns the identifier of the NAT gateway or internet gateway that the default
+// route of the subnet goes through, or an empty string if the subnet has no way to reach the
+// internet. Subnets without an explicit route table association use the main route table of
+// their VPC.
+func (c *awsClient) GetSubnetEgress(subnetID string) (string, error) {
+	res, err := c.ec2Client.DescribeRouteTables(&ec2.DescribeRouteTablesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("association.subnet-id"),
+				Values: aws.StringSlice([]string{subnetID}),
+			},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("Failed to describe route tables of subnet '%s': %v", subnetID, err)
+	}
+	if len(res.RouteTables) == 0 {
+		vpcID, err := c.GetSubnetVPC(subnetID)
+		if err != nil {
+			return "", err
+		}
+		res, err = c.ec2Client.DescribeRouteTables(&ec2.DescribeRouteTablesInput{
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String("vpc-id"),
+					Values: aws.StringSlice([]string{vpcID}),
+				},
+				{
+					Name:   aws.String("association.main"),
+					Values: aws.StringSlice([]string{"true"}),
+				},
+			},
+		})
+		if err != nil {
+			return "", fmt.Errorf("Failed to describe main route table of VPC '%s': %v", vpcID, err)
+		}
+	}
+	for _, table := range res.RouteTables {
+		for _, route := range table.Routes {
+			if aws.StringValue(route.DestinationCidrBlock) != "0.0.0.0/0" ||
+				aws.StringValue(route.State) == ec2.RouteStateBlackhole {
+				continue
+			}
+			if route.NatGatewayId != nil {
+				return aws.StringValue(route.NatGatewayId), nil
+			}
+			gatewayID := aws.StringValue(route.GatewayId)
+			if strings.HasPrefix(gatewayID, "igw-") {
+				return gatewayID, nil
+			}
+			if route.TransitGatewayId != nil {
+				return aws.StringValue(route.TransitGatewayId), nil
+			}
+		}
+	}
+	return "", nil
+}
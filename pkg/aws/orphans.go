@@ -0,0 +1,75 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to find the AWS resources created by rosa that are no
+// longer used by any cluster, for example after failed installations.
+
+package aws
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+
+	"github.com/openshift/moactl/pkg/aws/tags"
+)
+
+// GetUserTags returns the tags of the given IAM user, or nil if the user doesn't exist.
+func (c *awsClient) GetUserTags(username string) (map[string]string, error) {
+	output, err := c.iamClient.ListUserTags(&iam.ListUserTagsInput{
+		UserName: aws.String(username),
+	})
+	if isNoSuchEntity(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get tags of user '%s': %v", username, err)
+	}
+	userTags := map[string]string{}
+	for _, tag := range output.Tags {
+		userTags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return userTags, nil
+}
+
+// FindOrphanRoles returns the operator roles whose cluster no longer exists, according to the given
+// function. Account roles aren't included, as they aren't tied to a single cluster.
+func FindOrphanRoles(roles []Role, clusterExists func(clusterID string) (bool, error)) ([]Role,
+	error) {
+	exists := map[string]bool{}
+	orphans := []Role{}
+	for _, role := range roles {
+		clusterID := role.Tags[tags.ClusterID]
+		if clusterID == "" {
+			continue
+		}
+		found, checked := exists[clusterID]
+		if !checked {
+			var err error
+			found, err = clusterExists(clusterID)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to check cluster '%s' of role '%s': %v", clusterID,
+					role.Name, err)
+			}
+			exists[clusterID] = found
+		}
+		if !found {
+			orphans = append(orphans, role)
+		}
+	}
+	return orphans, nil
+}
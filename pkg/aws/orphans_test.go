@@ -0,0 +1,38 @@
+package aws_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/tags"
+)
+
+var _ = Describe("FindOrphanRoles", func() {
+	roles := []aws.Role{
+		{Name: "ManagedOpenShift-Installer-Role", Tags: map[string]string{tags.RoleType: "Installer"}},
+		{Name: "old-openshift-ingress-operator", Tags: map[string]string{tags.ClusterID: "old"}},
+		{Name: "old-openshift-machine-api", Tags: map[string]string{tags.ClusterID: "old"}},
+		{Name: "new-openshift-ingress-operator", Tags: map[string]string{tags.ClusterID: "new"}},
+	}
+
+	It("Returns the operator roles of clusters that don't exist", func() {
+		checked := []string{}
+		orphans, err := aws.FindOrphanRoles(roles, func(clusterID string) (bool, error) {
+			checked = append(checked, clusterID)
+			return clusterID == "new", nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(orphans).To(Equal(roles[1:3]))
+		Expect(checked).To(Equal([]string{"old", "new"}))
+	})
+
+	It("Fails when a cluster can't be checked", func() {
+		_, err := aws.FindOrphanRoles(roles, func(clusterID string) (bool, error) {
+			return false, fmt.Errorf("unavailable")
+		})
+		Expect(err).To(MatchError(ContainSubstring("unavailable")))
+	})
+})
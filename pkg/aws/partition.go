@@ -0,0 +1,85 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to support AWS partitions other than the standard one,
+// like AWS GovCloud (US).
+
+package aws
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+
+	"github.com/openshift/moactl/pkg/aws/partition"
+)
+
+// GovCloudDefaultRegion is the region where the CloudFormation stack is created when the AWS
+// GovCloud (US) partition is used.
+const GovCloudDefaultRegion = "us-gov-west-1"
+
+// GetPartition returns the identifier of the AWS partition used, 'aws-us-gov' when the '--govcloud'
+// flag is given and 'aws' otherwise.
+func GetPartition() string {
+	if partition.GovCloud() {
+		return endpoints.AwsUsGovPartitionID
+	}
+	return endpoints.AwsPartitionID
+}
+
+// GetDefaultRegion returns the region where the CloudFormation stack that creates the
+// administrator user is created, for the AWS partition used.
+func GetDefaultRegion() string {
+	if partition.GovCloud() {
+		return GovCloudDefaultRegion
+	}
+	return DefaultRegion
+}
+
+// PartitionForRegion returns the identifier of the AWS partition of the given region. Unknown
+// regions are assumed to be in the standard partition.
+func PartitionForRegion(region string) string {
+	p, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region)
+	if !ok {
+		return endpoints.AwsPartitionID
+	}
+	return p.ID()
+}
+
+// ValidatePartitionRegion checks that the given region is in the AWS partition used.
+func ValidatePartitionRegion(region string) error {
+	regionPartition := PartitionForRegion(region)
+	if regionPartition == GetPartition() {
+		return nil
+	}
+	if regionPartition == endpoints.AwsUsGovPartitionID {
+		return fmt.Errorf("Region '%s' is in the AWS GovCloud (US) partition, use the "+
+			"'--govcloud' flag to use it", region)
+	}
+	return fmt.Errorf("Region '%s' is in the AWS partition '%s', expected a region in '%s'",
+		region, regionPartition, GetPartition())
+}
+
+// IAMARN returns the ARN of the given IAM resource of the given account and partition.
+func IAMARN(partitionID string, accountID string, resource string) string {
+	return arn.ARN{
+		Partition: partitionID,
+		Service:   "iam",
+		AccountID: accountID,
+		Resource:  resource,
+	}.String()
+}
@@ -0,0 +1,41 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to implement the '--govcloud' command line option.
+
+package partition
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// AddFlag adds the GovCloud flag to the given set of command line flags.
+func AddFlag(flags *pflag.FlagSet) {
+	flags.BoolVar(
+		&govCloud,
+		"govcloud",
+		false,
+		"Use the AWS GovCloud (US) partition, for AWS accounts and regions in 'aws-us-gov'.",
+	)
+}
+
+// GovCloud returns a boolean flag that indicates if the AWS GovCloud (US) partition is used.
+func GovCloud() bool {
+	return govCloud
+}
+
+// govCloud is a boolean flag that indicates that the AWS GovCloud (US) partition is used.
+var govCloud bool
@@ -0,0 +1,53 @@
+package aws_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/partition"
+)
+
+var _ = Describe("Partitions", func() {
+	// setGovCloud sets the value of the '--govcloud' flag, which is kept in a package variable:
+	setGovCloud := func(value string) {
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		partition.AddFlag(fs)
+		Expect(fs.Parse([]string{"--govcloud=" + value})).To(Succeed())
+	}
+
+	AfterEach(func() {
+		setGovCloud("false")
+	})
+
+	It("Finds the partition of a region", func() {
+		Expect(aws.PartitionForRegion("us-east-1")).To(Equal("aws"))
+		Expect(aws.PartitionForRegion("us-gov-west-1")).To(Equal("aws-us-gov"))
+		Expect(aws.PartitionForRegion("xx-unknown-1")).To(Equal("aws"))
+	})
+
+	It("Uses the standard partition by default", func() {
+		Expect(aws.GetPartition()).To(Equal("aws"))
+		Expect(aws.GetDefaultRegion()).To(Equal(aws.DefaultRegion))
+		Expect(aws.ValidatePartitionRegion("eu-west-1")).To(Succeed())
+		Expect(aws.ValidatePartitionRegion("us-gov-east-1")).To(
+			MatchError(ContainSubstring("--govcloud")))
+	})
+
+	It("Uses the GovCloud partition with '--govcloud'", func() {
+		setGovCloud("true")
+		Expect(aws.GetPartition()).To(Equal("aws-us-gov"))
+		Expect(aws.GetDefaultRegion()).To(Equal(aws.GovCloudDefaultRegion))
+		Expect(aws.ValidatePartitionRegion("us-gov-east-1")).To(Succeed())
+		Expect(aws.ValidatePartitionRegion("eu-west-1")).To(
+			MatchError(ContainSubstring("expected a region in 'aws-us-gov'")))
+	})
+
+	It("Builds IAM ARNs in the given partition", func() {
+		Expect(aws.IAMARN("aws", "123456789012", "root")).To(
+			Equal("arn:aws:iam::123456789012:root"))
+		Expect(aws.IAMARN("aws-us-gov", "123456789012", "user/osdCcsAdmin")).To(
+			Equal("arn:aws-us-gov:iam::123456789012:user/osdCcsAdmin"))
+	})
+})
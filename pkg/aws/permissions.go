@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -9,6 +10,12 @@ import (
 	"github.com/openshift/moactl/assets"
 )
 
+// Paths of the policy documents whose actions are simulated:
+const (
+	scpPolicyPath       = "templates/policies/osd_scp_policy.json"
+	installerPolicyPath = "templates/policies/installer_policy.json"
+)
+
 // PolicyStatement models an AWS policy statement entry.
 type PolicyStatement struct {
 	Sid string `json:"sid,omitempty"`
@@ -34,12 +41,27 @@ type SimulateParams struct {
 	Region string
 }
 
-// checkPermissionsUsingQueryClient will use queryClient to query whether the credentials in targetClient can perform
-// the actions listed in the statementEntries. queryClient will need iam:GetUser and iam:SimulatePrincipalPolicy
-func checkPermissionsUsingQueryClient(queryClient *awsClient, targetUser *iam.User, policyDocument PolicyDocument,
-	params *SimulateParams) (bool, error) {
-	// Ignoring isRoot here since we only warn the user that its not best practice to use it.
-	// TODO: Add a check for isRoot in the initialize
+// ActionResult is the result of simulating one of the actions of a policy document for a user.
+type ActionResult struct {
+	Action string `json:"action"`
+	Policy string `json:"policy"`
+	// Decision is the result of the simulation: 'allowed', 'explicitDeny' or 'implicitDeny'.
+	Decision string `json:"decision"`
+	// DeniedByOrganizations indicates that the action is denied by a service control policy of
+	// the organization of the account.
+	DeniedByOrganizations bool `json:"denied_by_organizations,omitempty"`
+}
+
+// Allowed checks if the simulation allowed the action.
+func (r ActionResult) Allowed() bool {
+	return r.Decision == iam.PolicyEvaluationDecisionTypeAllowed
+}
+
+// simulatePolicyDocument will use queryClient to simulate whether the credentials of targetUser can
+// perform each of the actions listed in the policy document. queryClient will need
+// iam:SimulatePrincipalPolicy. The simulation stops when the context is cancelled.
+func simulatePolicyDocument(ctx context.Context, queryClient *awsClient, targetUser *iam.User,
+	policyDocument PolicyDocument, params *SimulateParams) ([]ActionResult, error) {
 	allowList := []*string{}
 	for _, statement := range policyDocument.Statement {
 		for _, action := range statement.Action {
@@ -63,28 +85,51 @@ func checkPermissionsUsingQueryClient(queryClient *awsClient, targetUser *iam.Us
 		}
 	}
 
-	// Either all actions are allowed and we'll return 'true', or it's a failure
-	allClear := true
-	// Collect all failed actions
-	var failedActions []string
-
-	err := queryClient.iamClient.SimulatePrincipalPolicyPages(input,
+	results := []ActionResult{}
+	err := queryClient.iamClient.SimulatePrincipalPolicyPagesWithContext(ctx, input,
 		func(response *iam.SimulatePolicyResponse, lastPage bool) bool {
 			for _, result := range response.EvaluationResults {
-				if *result.EvalDecision != "allowed" {
-					// Don't bail out after the first failure, so we can log the full list
-					// of failed/denied actions
-					failedActions = append(failedActions, *result.EvalActionName)
-					allClear = false
+				actionResult := ActionResult{
+					Action:   aws.StringValue(result.EvalActionName),
+					Policy:   policyDocument.ID,
+					Decision: aws.StringValue(result.EvalDecision),
+				}
+				if result.OrganizationsDecisionDetail != nil &&
+					!aws.BoolValue(result.OrganizationsDecisionDetail.AllowedByOrganizations) {
+					actionResult.DeniedByOrganizations = true
 				}
+				results = append(results, actionResult)
 			}
 			return !lastPage
 		})
 	if err != nil {
-		return false, fmt.Errorf("Error simulating policy: %v", err)
+		return nil, fmt.Errorf("Error simulating policy: %v", err)
+	}
+
+	return results, nil
+}
+
+// checkPermissionsUsingQueryClient will use queryClient to query whether the credentials in targetClient can perform
+// the actions listed in the statementEntries. queryClient will need iam:GetUser and iam:SimulatePrincipalPolicy
+func checkPermissionsUsingQueryClient(queryClient *awsClient, targetUser *iam.User, policyDocument PolicyDocument,
+	params *SimulateParams) (bool, error) {
+	// Ignoring isRoot here since we only warn the user that its not best practice to use it.
+	// TODO: Add a check for isRoot in the initialize
+	results, err := simulatePolicyDocument(context.Background(), queryClient, targetUser,
+		policyDocument, params)
+	if err != nil {
+		return false, err
+	}
+
+	// Collect all failed actions, so we can log the full list of failed/denied actions
+	var failedActions []string
+	for _, result := range results {
+		if !result.Allowed() {
+			failedActions = append(failedActions, result.Action)
+		}
 	}
 
-	if !allClear {
+	if len(failedActions) > 0 {
 		return false, fmt.Errorf("Actions not allowed with tested credentials: %v", failedActions)
 	}
 
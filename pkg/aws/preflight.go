@@ -0,0 +1,200 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the pre-flight checks that verify that the AWS account is ready to create a
+// cluster. The checks are independent, so they run concurrently.
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// PreflightCheck is one of the checks that run before creating a cluster.
+type PreflightCheck struct {
+	Name string
+
+	// Fatal checks make the rest of the checks pointless when they fail, for example when the
+	// credentials aren't valid, so the rest are cancelled and only the fatal failure is reported.
+	Fatal bool
+
+	Run func(ctx context.Context) error
+}
+
+// PreflightFailure is the error of one of the checks that failed.
+type PreflightFailure struct {
+	Name string
+	Err  error
+}
+
+// PreflightError is returned when one or more of the checks fail.
+type PreflightError struct {
+	Failures []PreflightFailure
+}
+
+// Error is the implementation of the error interface.
+func (e *PreflightError) Error() string {
+	lines := make([]string, len(e.Failures))
+	for i, failure := range e.Failures {
+		lines[i] = fmt.Sprintf("  - %s: %v", failure.Name, failure.Err)
+	}
+	return fmt.Sprintf("%d of the pre-flight checks failed:\n%s", len(e.Failures),
+		strings.Join(lines, "\n"))
+}
+
+// ExitCode returns the exit code shared by all the failures, or the generic one if they have
+// different exit codes.
+func (e *PreflightError) ExitCode() int {
+	code := rerrors.ExitCodeGeneric
+	for i, failure := range e.Failures {
+		failureCode := rerrors.ExitCode(failure.Err)
+		if i > 0 && failureCode != code {
+			return rerrors.ExitCodeGeneric
+		}
+		code = failureCode
+	}
+	return code
+}
+
+// RunPreflightChecks runs the checks concurrently and waits for all of them to finish, calling the
+// progress function, if any, every time one of them finishes. The failures of all the checks are
+// returned together in a PreflightError, in the same order as the checks.
+func RunPreflightChecks(ctx context.Context, checks []PreflightCheck,
+	progress func(done int, total int)) error {
+	group, groupCtx := errgroup.WithContext(ctx)
+	errs := make([]error, len(checks))
+	var lock sync.Mutex
+	done := 0
+	for i := range checks {
+		i := i
+		check := checks[i]
+		group.Go(func() error {
+			err := groupCtx.Err()
+			if err == nil {
+				err = check.Run(groupCtx)
+			}
+			lock.Lock()
+			errs[i] = err
+			done++
+			if progress != nil {
+				progress(done, len(checks))
+			}
+			lock.Unlock()
+			if err != nil && check.Fatal {
+				return err
+			}
+			return nil
+		})
+	}
+	fatalErr := group.Wait()
+
+	// When the parent context is cancelled the results of the checks are meaningless:
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	failures := []PreflightFailure{}
+	for i, check := range checks {
+		if errs[i] == nil {
+			continue
+		}
+		// After a fatal failure only that one is reported, as the rest were cancelled or are
+		// likely to fail for the same reason:
+		if fatalErr != nil && errs[i] != fatalErr {
+			continue
+		}
+		failures = append(failures, PreflightFailure{
+			Name: check.Name,
+			Err:  errs[i],
+		})
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	return &PreflightError{
+		Failures: failures,
+	}
+}
+
+// CreatorPreflightCheck checks that the credentials are valid, loading the identity of the user
+// into the given creator.
+func CreatorPreflightCheck(client Client, creator *Creator) PreflightCheck {
+	return PreflightCheck{
+		Name:  "AWS credentials",
+		Fatal: true,
+		Run: func(ctx context.Context) error {
+			result, err := client.GetCreator()
+			if err != nil {
+				return rerrors.Errorf(rerrors.ExitCodeAWSAuth, "Failed to get AWS creator: %v", err)
+			}
+			*creator = *result
+			return nil
+		},
+	}
+}
+
+// PermissionsPreflightCheck checks that the user is allowed to perform all the actions needed to
+// create a cluster.
+func PermissionsPreflightCheck(client Client) PreflightCheck {
+	return PreflightCheck{
+		Name: "AWS permissions",
+		Run: func(ctx context.Context) error {
+			results, err := client.SimulatePermissions(ctx, nil)
+			if err != nil {
+				return fmt.Errorf("Failed to simulate permissions: %w", err)
+			}
+			denied := 0
+			for _, result := range results {
+				if !result.Allowed() {
+					denied++
+				}
+			}
+			if denied > 0 {
+				return rerrors.Errorf(rerrors.ExitCodeAWSAuth,
+					"%d of the %d actions needed to create a cluster are denied, run "+
+						"'rosa verify permissions' for details", denied, len(results))
+			}
+			return nil
+		},
+	}
+}
+
+// StackPreflightCheck checks that the CloudFormation stack that creates the administrator user has
+// been successfully applied. The client must use the default region, as that is where the stack
+// is created.
+func StackPreflightCheck(client Client) PreflightCheck {
+	return PreflightCheck{
+		Name: "CloudFormation stack",
+		Run: func(ctx context.Context) error {
+			ready, status, err := client.CheckStackReadyOrNotExisting(OsdCcsAdminStackName)
+			if err != nil {
+				return rerrors.Wrap(rerrors.ExitCodeConflict, err)
+			}
+			if !ready || status == nil {
+				return rerrors.Errorf(rerrors.ExitCodeConflict,
+					"Stack '%s' doesn't exist, run 'rosa init' to create it", OsdCcsAdminStackName)
+			}
+			return nil
+		},
+	}
+}
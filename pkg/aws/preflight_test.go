@@ -0,0 +1,160 @@
+package aws_test
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mocks"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+var _ = Describe("RunPreflightChecks", func() {
+	check := func(name string, err error) aws.PreflightCheck {
+		return aws.PreflightCheck{
+			Name: name,
+			Run: func(ctx context.Context) error {
+				return err
+			},
+		}
+	}
+
+	It("Succeeds when all the checks pass", func() {
+		progress := []int{}
+		err := aws.RunPreflightChecks(context.Background(), []aws.PreflightCheck{
+			check("first", nil),
+			check("second", nil),
+		}, func(done int, total int) {
+			Expect(total).To(Equal(2))
+			progress = append(progress, done)
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(progress).To(Equal([]int{1, 2}))
+	})
+
+	It("Reports all the failures in the order of the checks", func() {
+		err := aws.RunPreflightChecks(context.Background(), []aws.PreflightCheck{
+			check("first", rerrors.ConflictErrorf("busy")),
+			check("second", nil),
+			check("third", rerrors.ConflictErrorf("broken")),
+		}, nil)
+		preflightErr, ok := err.(*aws.PreflightError)
+		Expect(ok).To(BeTrue())
+		Expect(preflightErr.Failures).To(HaveLen(2))
+		Expect(preflightErr.Failures[0].Name).To(Equal("first"))
+		Expect(preflightErr.Failures[1].Name).To(Equal("third"))
+		Expect(err.Error()).To(ContainSubstring("busy"))
+		Expect(err.Error()).To(ContainSubstring("broken"))
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeConflict))
+	})
+
+	It("Uses the generic exit code when the failures are different", func() {
+		err := aws.RunPreflightChecks(context.Background(), []aws.PreflightCheck{
+			check("first", rerrors.ConflictErrorf("busy")),
+			check("second", rerrors.ValidationErrorf("wrong")),
+		}, nil)
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeGeneric))
+	})
+
+	It("Only reports the fatal failure and cancels the rest", func() {
+		fatal := fmt.Errorf("invalid credentials")
+		started := make(chan struct{})
+		err := aws.RunPreflightChecks(context.Background(), []aws.PreflightCheck{
+			{
+				Name: "slow",
+				Run: func(ctx context.Context) error {
+					close(started)
+					<-ctx.Done()
+					return ctx.Err()
+				},
+			},
+			{
+				Name:  "credentials",
+				Fatal: true,
+				Run: func(ctx context.Context) error {
+					<-started
+					return fatal
+				},
+			},
+		}, nil)
+		preflightErr, ok := err.(*aws.PreflightError)
+		Expect(ok).To(BeTrue())
+		Expect(preflightErr.Failures).To(HaveLen(1))
+		Expect(preflightErr.Failures[0].Name).To(Equal("credentials"))
+	})
+
+	It("Returns the error of the cancelled context", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := aws.RunPreflightChecks(ctx, []aws.PreflightCheck{
+			check("first", nil),
+		}, nil)
+		Expect(err).To(Equal(context.Canceled))
+	})
+})
+
+var _ = Describe("StackPreflightCheck", func() {
+	var (
+		mockCtrl  *gomock.Controller
+		mockCfAPI *mocks.MockCloudFormationAPI
+		client    aws.Client
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockCfAPI = mocks.NewMockCloudFormationAPI(mockCtrl)
+		client = aws.New(
+			logrus.New(),
+			mocks.NewMockIAMAPI(mockCtrl),
+			mocks.NewMockEC2API(mockCtrl),
+			mocks.NewMockOrganizationsAPI(mockCtrl),
+			mocks.NewMockSTSAPI(mockCtrl),
+			mockCfAPI,
+			mocks.NewMockServiceQuotasAPI(mockCtrl),
+			mocks.NewMockKMSAPI(mockCtrl),
+			&session.Session{},
+			&aws.AccessKey{},
+		)
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	listStacks := func(status string) {
+		mockCfAPI.EXPECT().ListStacks(gomock.Any()).Return(&cloudformation.ListStacksOutput{
+			StackSummaries: []*cloudformation.StackSummary{
+				{
+					StackName:   awssdk.String(aws.OsdCcsAdminStackName),
+					StackStatus: awssdk.String(status),
+				},
+			},
+		}, nil)
+	}
+
+	It("Passes when the stack has been created", func() {
+		listStacks(cloudformation.StackStatusCreateComplete)
+		Expect(aws.StackPreflightCheck(client).Run(context.Background())).To(Succeed())
+	})
+
+	It("Fails when the stack doesn't exist", func() {
+		listStacks(cloudformation.StackStatusDeleteComplete)
+		err := aws.StackPreflightCheck(client).Run(context.Background())
+		Expect(err).To(MatchError(ContainSubstring("rosa init")))
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeConflict))
+	})
+
+	It("Fails when the stack is in a failed state", func() {
+		listStacks(cloudformation.StackStatusRollbackComplete)
+		err := aws.StackPreflightCheck(client).Run(context.Background())
+		Expect(err).To(MatchError(ContainSubstring(cloudformation.StackStatusRollbackComplete)))
+	})
+})
@@ -0,0 +1,126 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to get the prices of AWS resources from the AWS Pricing
+// API, which are used to estimate the cost of clusters.
+
+package aws
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/service/pricing"
+)
+
+// pricingRegion is the region of the endpoint of the AWS Pricing API, which is only available in
+// a few regions but returns the prices of all of them.
+const pricingRegion = "us-east-1"
+
+// RegionLocation returns the name of the region used by the AWS Pricing API, for example
+// 'US East (N. Virginia)' for 'us-east-1'.
+func RegionLocation(region string) (string, error) {
+	for _, partition := range endpoints.DefaultPartitions() {
+		if value, ok := partition.Regions()[region]; ok {
+			return value.Description(), nil
+		}
+	}
+	return "", fmt.Errorf("Unknown region '%s'", region)
+}
+
+// GetHourlyPrice returns the on-demand price in USD per hour of the product of the given service
+// that matches the filters, in the region of the client.
+func (c *awsClient) GetHourlyPrice(serviceCode string, filters map[string]string) (float64, error) {
+	location, err := RegionLocation(c.GetRegion())
+	if err != nil {
+		return 0, err
+	}
+	input := &pricing.GetProductsInput{
+		ServiceCode: aws.String(serviceCode),
+		Filters: []*pricing.Filter{{
+			Type:  aws.String(pricing.FilterTypeTermMatch),
+			Field: aws.String("location"),
+			Value: aws.String(location),
+		}},
+		MaxResults: aws.Int64(10),
+	}
+	// Sort the filters so that the requests are the same every time:
+	fields := make([]string, 0, len(filters))
+	for field := range filters {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		input.Filters = append(input.Filters, &pricing.Filter{
+			Type:  aws.String(pricing.FilterTypeTermMatch),
+			Field: aws.String(field),
+			Value: aws.String(filters[field]),
+		})
+	}
+	output, err := c.pricingClient.GetProducts(input)
+	if err != nil {
+		return 0, err
+	}
+	price, ok := ParseHourlyPrice(output.PriceList)
+	if !ok {
+		return 0, fmt.Errorf("Price of '%s' product %v not found in region '%s'", serviceCode,
+			filters, c.GetRegion())
+	}
+	return price, nil
+}
+
+// ParseHourlyPrice returns the first non zero on-demand price in USD per hour of the given
+// products, as returned by the AWS Pricing API.
+func ParseHourlyPrice(products []aws.JSONValue) (float64, bool) {
+	for _, product := range products {
+		for _, term := range objectValues(objectValue(objectValue(product, "terms"), "OnDemand")) {
+			for _, dimension := range objectValues(objectValue(term, "priceDimensions")) {
+				if dimension["unit"] != "Hrs" {
+					continue
+				}
+				usd, ok := objectValue(dimension, "pricePerUnit")["USD"].(string)
+				if !ok {
+					continue
+				}
+				price, err := strconv.ParseFloat(usd, 64)
+				if err == nil && price > 0 {
+					return price, true
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+// objectValue returns the JSON object stored in the given field, or nil if it isn't an object.
+func objectValue(object map[string]interface{}, field string) map[string]interface{} {
+	value, _ := object[field].(map[string]interface{})
+	return value
+}
+
+// objectValues returns the values of the given JSON object that are objects themselves.
+func objectValues(object map[string]interface{}) []map[string]interface{} {
+	result := []map[string]interface{}{}
+	for _, value := range object {
+		if nested, ok := value.(map[string]interface{}); ok {
+			result = append(result, nested)
+		}
+	}
+	return result
+}
@@ -0,0 +1,67 @@
+package aws_test
+
+import (
+	"encoding/json"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+)
+
+var _ = Describe("Pricing", func() {
+	product := func(text string) awssdk.JSONValue {
+		value := awssdk.JSONValue{}
+		Expect(json.Unmarshal([]byte(text), &value)).To(Succeed())
+		return value
+	}
+
+	It("Returns the names of the regions used by the pricing API", func() {
+		location, err := aws.RegionLocation("us-east-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(location).To(Equal("US East (N. Virginia)"))
+		_, err = aws.RegionLocation("mars-north-1")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Parses the hourly on-demand price", func() {
+		price, ok := aws.ParseHourlyPrice([]awssdk.JSONValue{product(`{
+			"product": {"attributes": {"instanceType": "m5.xlarge"}},
+			"terms": {
+				"OnDemand": {
+					"ABC.JRTCKXETXF": {
+						"priceDimensions": {
+							"ABC.JRTCKXETXF.6YS6EN2CT7": {
+								"unit": "Hrs",
+								"pricePerUnit": {"USD": "0.1920000000"}
+							}
+						}
+					}
+				}
+			}
+		}`)})
+		Expect(ok).To(BeTrue())
+		Expect(price).To(BeNumerically("~", 0.192))
+	})
+
+	It("Ignores prices that aren't per hour", func() {
+		_, ok := aws.ParseHourlyPrice([]awssdk.JSONValue{product(`{
+			"terms": {
+				"OnDemand": {
+					"ABC": {
+						"priceDimensions": {
+							"ABC.1": {"unit": "GB", "pricePerUnit": {"USD": "0.045"}}
+						}
+					}
+				}
+			}
+		}`)})
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Fails when there are no products", func() {
+		_, ok := aws.ParseHourlyPrice(nil)
+		Expect(ok).To(BeFalse())
+	})
+})
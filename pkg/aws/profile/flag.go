@@ -14,7 +14,8 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// This file contains functions used to implement the '--profile' command line option.
+// This file contains functions used to implement the '--profile' and '--aws-profile' command line
+// options.
 
 package profile
 
@@ -32,6 +33,12 @@ func AddFlag(flags *pflag.FlagSet) {
 		"",
 		"Use a specific AWS profile from your credential file.",
 	)
+	flags.StringVar(
+		&profile,
+		"aws-profile",
+		"",
+		"Same as '--profile'.",
+	)
 }
 
 // Profile returns a string with the name of the AWS profile being used.
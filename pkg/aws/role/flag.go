@@ -0,0 +1,100 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to implement the '--role-arn', '--external-id' and
+// '--mfa-serial' command line options.
+
+package role
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/spf13/pflag"
+)
+
+// SessionName is the name of the sessions of the assumed roles. It is always the same so that the
+// ARN of the user, which identifies the clusters that it created, doesn't change between runs.
+const SessionName = "rosa"
+
+// AddFlags adds the flags used to assume a role to the given set of command line flags.
+func AddFlags(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&roleARN,
+		"role-arn",
+		"",
+		"ARN of an AWS role to assume for all the AWS interactions, for example to manage "+
+			"clusters in a different AWS account.",
+	)
+	flags.StringVar(
+		&externalID,
+		"external-id",
+		"",
+		"External identifier required by the trust policy of the role given with '--role-arn'.",
+	)
+	flags.StringVar(
+		&mfaSerial,
+		"mfa-serial",
+		"",
+		"Serial number or ARN of the MFA device required to assume the role given with "+
+			"'--role-arn'. The token code will be requested when the role is assumed.",
+	)
+}
+
+// ARN returns the ARN of the role to assume, or an empty string if no role should be assumed.
+func ARN() string {
+	return roleARN
+}
+
+// ExternalID returns the external identifier used to assume the role.
+func ExternalID() string {
+	return externalID
+}
+
+// MFASerial returns the serial number of the MFA device used to assume the role.
+func MFASerial() string {
+	return mfaSerial
+}
+
+// Validate checks that the role ARN is valid, and that the external identifier and the MFA device
+// are only given together with a role.
+func Validate() error {
+	if roleARN == "" {
+		if externalID != "" {
+			return fmt.Errorf("Option '--external-id' can only be used with '--role-arn'")
+		}
+		if mfaSerial != "" {
+			return fmt.Errorf("Option '--mfa-serial' can only be used with '--role-arn'")
+		}
+		return nil
+	}
+	parsed, err := arn.Parse(roleARN)
+	if err != nil {
+		return fmt.Errorf("Invalid role ARN '%s': %v", roleARN, err)
+	}
+	if parsed.Service != "iam" || !strings.HasPrefix(parsed.Resource, "role/") {
+		return fmt.Errorf("Invalid role ARN '%s': expected an IAM role", roleARN)
+	}
+	return nil
+}
+
+// roleARN, externalID and mfaSerial are the values of the flags.
+var (
+	roleARN    string
+	externalID string
+	mfaSerial  string
+)
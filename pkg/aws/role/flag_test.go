@@ -0,0 +1,56 @@
+package role_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/moactl/pkg/aws/role"
+)
+
+var _ = Describe("Validate", func() {
+	// parse sets all the flags, as their values are kept in package variables:
+	parse := func(roleARN, externalID, mfaSerial string) {
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		role.AddFlags(fs)
+		err := fs.Parse([]string{
+			"--role-arn=" + roleARN,
+			"--external-id=" + externalID,
+			"--mfa-serial=" + mfaSerial,
+		})
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	It("Accepts no role", func() {
+		parse("", "", "")
+		Expect(role.Validate()).To(Succeed())
+	})
+
+	It("Accepts a role with external identifier and MFA device", func() {
+		parse("arn:aws:iam::123456789012:role/admin", "my-id", "arn:aws:iam::123456789012:mfa/user")
+		Expect(role.Validate()).To(Succeed())
+		Expect(role.ARN()).To(Equal("arn:aws:iam::123456789012:role/admin"))
+		Expect(role.ExternalID()).To(Equal("my-id"))
+		Expect(role.MFASerial()).To(Equal("arn:aws:iam::123456789012:mfa/user"))
+	})
+
+	It("Rejects an external identifier without role", func() {
+		parse("", "my-id", "")
+		Expect(role.Validate()).To(MatchError(ContainSubstring("--external-id")))
+	})
+
+	It("Rejects an MFA device without role", func() {
+		parse("", "", "arn:aws:iam::123456789012:mfa/user")
+		Expect(role.Validate()).To(MatchError(ContainSubstring("--mfa-serial")))
+	})
+
+	It("Rejects an invalid ARN", func() {
+		parse("admin", "", "")
+		Expect(role.Validate()).To(MatchError(ContainSubstring("Invalid role ARN")))
+	})
+
+	It("Rejects an ARN that isn't a role", func() {
+		parse("arn:aws:iam::123456789012:user/admin", "", "")
+		Expect(role.Validate()).To(MatchError(ContainSubstring("expected an IAM role")))
+	})
+})
@@ -0,0 +1,607 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to create and delete the IAM roles and the OpenID Connect
+// provider needed by clusters that use the AWS Security Token Service (STS) instead of long lived
+// credentials.
+
+package aws
+
+import (
+	"crypto/sha1" // #nosec G505
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+
+	"github.com/openshift/moactl/assets"
+	"github.com/openshift/moactl/pkg/aws/tags"
+)
+
+// DefaultRolePrefix is the prefix of the names of the account roles when none is given.
+const DefaultRolePrefix = "ManagedOpenShift"
+
+// redHatAccountID is the AWS account of the Red Hat roles that are allowed to assume the installer
+// and support account roles.
+const redHatAccountID = "710019948333"
+
+// maxRoleNameLength is the maximum length of the name of an IAM role.
+const maxRoleNameLength = 64
+
+// OIDCClientIDs are the audiences accepted by the OpenID Connect provider of a cluster.
+var OIDCClientIDs = []string{"openshift", "sts.amazonaws.com"}
+
+// AccountRole describes one of the roles that are shared by all the STS clusters of an account.
+type AccountRole struct {
+	// Type is the suffix of the name of the role, for example 'Installer'.
+	Type string
+
+	// TrustedRole is the name of the role of the Red Hat account that can assume the role, and
+	// TrustedService the AWS service that can assume it. Only one of them is set.
+	TrustedRole    string
+	TrustedService string
+
+	// PolicyPath is the asset that contains the permission policy of the role.
+	PolicyPath string
+}
+
+// AccountRoles are the roles created by 'rosa create account-roles'.
+var AccountRoles = []AccountRole{
+	{
+		Type:        "Installer",
+		TrustedRole: "RH-Managed-OpenShift-Installer",
+		PolicyPath:  "templates/policies/installer_policy.json",
+	},
+	{
+		Type:        "Support",
+		TrustedRole: "RH-Technical-Support-Access",
+		PolicyPath:  "templates/policies/sts_support_permission_policy.json",
+	},
+	{
+		Type:           "ControlPlane",
+		TrustedService: "ec2.amazonaws.com",
+		PolicyPath:     "templates/policies/sts_instance_controlplane_permission_policy.json",
+	},
+	{
+		Type:           "Worker",
+		TrustedService: "ec2.amazonaws.com",
+		PolicyPath:     "templates/policies/sts_instance_worker_permission_policy.json",
+	},
+}
+
+// OperatorRole describes one of the roles assumed by the operators of an STS cluster through the
+// OpenID Connect provider of the cluster.
+type OperatorRole struct {
+	// Namespace of the operator and Name of the secret that contains its credentials.
+	Namespace string
+	Name      string
+
+	// ServiceAccounts that are allowed to assume the role.
+	ServiceAccounts []string
+
+	// PolicyPath is the asset that contains the permission policy of the role.
+	PolicyPath string
+}
+
+// OperatorRoles are the roles created by 'rosa create operator-roles'.
+var OperatorRoles = []OperatorRole{
+	{
+		Namespace:       "openshift-ingress-operator",
+		Name:            "cloud-credentials",
+		ServiceAccounts: []string{"ingress-operator"},
+		PolicyPath:      "templates/policies/sts_ocm_ingress_operator_permission_policy.json",
+	},
+	{
+		Namespace:       "openshift-image-registry",
+		Name:            "installer-cloud-credentials",
+		ServiceAccounts: []string{"cluster-image-registry-operator", "registry"},
+		PolicyPath:      "templates/policies/sts_ocm_image_registry_operator_permission_policy.json",
+	},
+	{
+		Namespace: "openshift-cluster-csi-drivers",
+		Name:      "ebs-cloud-credentials",
+		ServiceAccounts: []string{
+			"aws-ebs-csi-driver-operator",
+			"aws-ebs-csi-driver-controller-sa",
+		},
+		PolicyPath: "templates/policies/sts_ocm_ebs_csi_driver_operator_permission_policy.json",
+	},
+	{
+		Namespace:       "openshift-machine-api",
+		Name:            "aws-cloud-credentials",
+		ServiceAccounts: []string{"machine-api-controllers"},
+		PolicyPath:      "templates/policies/sts_ocm_machine_api_operator_permission_policy.json",
+	},
+	{
+		Namespace:       "openshift-cloud-credential-operator",
+		Name:            "cloud-credential-operator-iam-ro-creds",
+		ServiceAccounts: []string{"cloud-credential-operator"},
+		PolicyPath:      "templates/policies/sts_ocm_cloud_credential_operator_permission_policy.json",
+	},
+}
+
+// RoleSpec contains everything needed to create an IAM role with its inline permission policy.
+type RoleSpec struct {
+	Name             string
+	TrustPolicy      string
+	PermissionPolicy string
+	Tags             map[string]string
+}
+
+// PolicyName returns the name of the inline permission policy of the role.
+func (s RoleSpec) PolicyName() string {
+	return s.Name + "-Policy"
+}
+
+// Role is an IAM role created by rosa, with its tags.
+type Role struct {
+	Name string
+	ARN  string
+	Tags map[string]string
+}
+
+// OIDCProvider is an OpenID Connect provider of the AWS account.
+type OIDCProvider struct {
+	ARN string
+	URL string
+}
+
+// trustPolicy is the document that describes who can assume a role.
+type trustPolicy struct {
+	Version   string           `json:"Version"`
+	Statement []trustStatement `json:"Statement"`
+}
+
+type trustStatement struct {
+	Effect    string                            `json:"Effect"`
+	Principal map[string]string                 `json:"Principal"`
+	Action    string                            `json:"Action"`
+	Condition map[string]map[string]interface{} `json:"Condition,omitempty"`
+}
+
+// AccountRoleName returns the name of the account role of the given type.
+func AccountRoleName(prefix string, roleType string) string {
+	return truncateRoleName(fmt.Sprintf("%s-%s-Role", prefix, roleType))
+}
+
+// OperatorRoleName returns the name of the role of the given operator of the given cluster.
+func OperatorRoleName(clusterName string, operator OperatorRole) string {
+	return truncateRoleName(fmt.Sprintf("%s-%s-%s", clusterName, operator.Namespace, operator.Name))
+}
+
+func truncateRoleName(name string) string {
+	if len(name) > maxRoleNameLength {
+		return name[:maxRoleNameLength]
+	}
+	return name
+}
+
+// AccountRoleSpecs returns the specifications of the account roles with the given prefix in the
+// given partition.
+func AccountRoleSpecs(prefix string, partitionID string) ([]RoleSpec, error) {
+	specs := make([]RoleSpec, 0, len(AccountRoles))
+	for _, role := range AccountRoles {
+		principal := map[string]string{}
+		if role.TrustedRole != "" {
+			principal["AWS"] = IAMARN(partitionID, redHatAccountID, "role/"+role.TrustedRole)
+		} else {
+			principal["Service"] = role.TrustedService
+		}
+		spec, err := newRoleSpec(AccountRoleName(prefix, role.Type), role.PolicyPath, trustStatement{
+			Effect:    "Allow",
+			Principal: principal,
+			Action:    "sts:AssumeRole",
+		})
+		if err != nil {
+			return nil, err
+		}
+		spec.Tags = map[string]string{
+			tags.RedHatManaged: "true",
+			tags.RolePrefix:    prefix,
+			tags.RoleType:      role.Type,
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// OperatorRoleSpecs returns the specifications of the operator roles of the given cluster, which
+// trust the OpenID Connect provider with the given issuer URL in the given account.
+func OperatorRoleSpecs(clusterName string, clusterID string, partitionID string, accountID string,
+	issuerURL string) ([]RoleSpec, error) {
+	issuer, err := OIDCIssuer(issuerURL)
+	if err != nil {
+		return nil, err
+	}
+	providerARN := IAMARN(partitionID, accountID, "oidc-provider/"+issuer)
+	specs := make([]RoleSpec, 0, len(OperatorRoles))
+	for _, operator := range OperatorRoles {
+		subjects := make([]string, len(operator.ServiceAccounts))
+		for i, serviceAccount := range operator.ServiceAccounts {
+			subjects[i] = fmt.Sprintf("system:serviceaccount:%s:%s", operator.Namespace, serviceAccount)
+		}
+		spec, err := newRoleSpec(OperatorRoleName(clusterName, operator), operator.PolicyPath,
+			trustStatement{
+				Effect:    "Allow",
+				Principal: map[string]string{"Federated": providerARN},
+				Action:    "sts:AssumeRoleWithWebIdentity",
+				Condition: map[string]map[string]interface{}{
+					"StringEquals": {issuer + ":sub": subjects},
+				},
+			})
+		if err != nil {
+			return nil, err
+		}
+		spec.Tags = map[string]string{
+			tags.RedHatManaged:     "true",
+			tags.ClusterID:         clusterID,
+			tags.OperatorNamespace: operator.Namespace,
+			tags.OperatorName:      operator.Name,
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+func newRoleSpec(name string, policyPath string, statement trustStatement) (RoleSpec, error) {
+	trust, err := json.MarshalIndent(trustPolicy{
+		Version:   "2012-10-17",
+		Statement: []trustStatement{statement},
+	}, "", "  ")
+	if err != nil {
+		return RoleSpec{}, fmt.Errorf("Failed to generate trust policy of role '%s': %v", name, err)
+	}
+	permissions, err := assets.Asset(policyPath)
+	if err != nil {
+		return RoleSpec{}, fmt.Errorf("Failed to load permission policy of role '%s': %v", name, err)
+	}
+	return RoleSpec{
+		Name:             name,
+		TrustPolicy:      string(trust),
+		PermissionPolicy: string(permissions),
+	}, nil
+}
+
+// OIDCIssuer returns the issuer of the given OpenID Connect endpoint URL, which is the URL
+// without the scheme, as used in the ARN of the provider and in the conditions of trust policies.
+func OIDCIssuer(issuerURL string) (string, error) {
+	parsed, err := url.ParseRequestURI(issuerURL)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return "", fmt.Errorf("Expected a valid 'https' OIDC endpoint URL, got '%s'", issuerURL)
+	}
+	return parsed.Host + strings.TrimSuffix(parsed.Path, "/"), nil
+}
+
+// GetThumbprint returns the SHA-1 fingerprint of the root certificate authority of the server of
+// the given OpenID Connect endpoint URL, as needed to create the provider.
+func GetThumbprint(issuerURL string) (string, error) {
+	parsed, err := url.ParseRequestURI(issuerURL)
+	if err != nil {
+		return "", fmt.Errorf("Invalid OIDC endpoint URL '%s': %v", issuerURL, err)
+	}
+	host := parsed.Host
+	if parsed.Port() == "" {
+		host += ":443"
+	}
+	connection, err := tls.Dial("tcp", host, &tls.Config{MinVersion: tls.VersionTLS12})
+	if err != nil {
+		return "", fmt.Errorf("Failed to connect to '%s': %v", host, err)
+	}
+	defer connection.Close()
+	certificates := connection.ConnectionState().PeerCertificates
+	if len(certificates) == 0 {
+		return "", fmt.Errorf("Server '%s' didn't send any certificate", host)
+	}
+	// #nosec G401
+	sum := sha1.Sum(certificates[len(certificates)-1].Raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// WriteRoleFiles writes the trust and permission policies of the role to the given directory, and
+// returns the names of the files.
+func WriteRoleFiles(dir string, spec RoleSpec) (trustFile string, policyFile string, err error) {
+	trustFile = filepath.Join(dir, spec.Name+"-trust-policy.json")
+	err = ioutil.WriteFile(trustFile, []byte(spec.TrustPolicy), 0600)
+	if err != nil {
+		return "", "", fmt.Errorf("Failed to write file '%s': %v", trustFile, err)
+	}
+	policyFile = filepath.Join(dir, spec.Name+"-permission-policy.json")
+	err = ioutil.WriteFile(policyFile, []byte(spec.PermissionPolicy), 0600)
+	if err != nil {
+		return "", "", fmt.Errorf("Failed to write file '%s': %v", policyFile, err)
+	}
+	return trustFile, policyFile, nil
+}
+
+// CreateRoleCommands returns the AWS CLI commands that create the role from the policy files
+// written by WriteRoleFiles.
+func CreateRoleCommands(spec RoleSpec, trustFile string, policyFile string) []string {
+	keys := make([]string, 0, len(spec.Tags))
+	for key := range spec.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	tagArgs := make([]string, len(keys))
+	for i, key := range keys {
+		tagArgs[i] = fmt.Sprintf("Key=%s,Value=%s", key, spec.Tags[key])
+	}
+	create := fmt.Sprintf("aws iam create-role --role-name %s --assume-role-policy-document file://%s",
+		spec.Name, trustFile)
+	if len(tagArgs) > 0 {
+		create += " --tags " + strings.Join(tagArgs, " ")
+	}
+	return []string{
+		create,
+		fmt.Sprintf("aws iam put-role-policy --role-name %s --policy-name %s --policy-document file://%s",
+			spec.Name, spec.PolicyName(), policyFile),
+	}
+}
+
+// DeleteRoleCommands returns the AWS CLI commands that delete the role and its inline permission
+// policy.
+func DeleteRoleCommands(roleName string) []string {
+	return []string{
+		fmt.Sprintf("aws iam delete-role-policy --role-name %s --policy-name %s",
+			roleName, RoleSpec{Name: roleName}.PolicyName()),
+		fmt.Sprintf("aws iam delete-role --role-name %s", roleName),
+	}
+}
+
+// CreateOIDCProviderCommand returns the AWS CLI command that creates the OpenID Connect provider.
+func CreateOIDCProviderCommand(issuerURL string, thumbprint string) string {
+	return fmt.Sprintf("aws iam create-open-id-connect-provider --url %s --client-id-list %s "+
+		"--thumbprint-list %s", issuerURL, strings.Join(OIDCClientIDs, " "), thumbprint)
+}
+
+// DeleteOIDCProviderCommand returns the AWS CLI command that deletes the OpenID Connect provider.
+func DeleteOIDCProviderCommand(providerARN string) string {
+	return fmt.Sprintf("aws iam delete-open-id-connect-provider --open-id-connect-provider-arn %s",
+		providerARN)
+}
+
+// EnsureRole creates the role described by the specification, or updates its trust policy if it
+// already exists, and then sets its inline permission policy. It returns the ARN of the role.
+func (c *awsClient) EnsureRole(spec RoleSpec) (string, error) {
+	var roleARN string
+	getOutput, err := c.iamClient.GetRole(&iam.GetRoleInput{
+		RoleName: aws.String(spec.Name),
+	})
+	switch {
+	case isNoSuchEntity(err):
+		roleTags := make([]*iam.Tag, 0, len(spec.Tags))
+		for key, value := range spec.Tags {
+			roleTags = append(roleTags, &iam.Tag{
+				Key:   aws.String(key),
+				Value: aws.String(value),
+			})
+		}
+		sort.Slice(roleTags, func(i, j int) bool {
+			return aws.StringValue(roleTags[i].Key) < aws.StringValue(roleTags[j].Key)
+		})
+		c.logger.Debugf("Creating role '%s'", spec.Name)
+		createOutput, err := c.iamClient.CreateRole(&iam.CreateRoleInput{
+			RoleName:                 aws.String(spec.Name),
+			AssumeRolePolicyDocument: aws.String(spec.TrustPolicy),
+			Tags:                     roleTags,
+		})
+		if err != nil {
+			return "", fmt.Errorf("Failed to create role '%s': %v", spec.Name, err)
+		}
+		roleARN = aws.StringValue(createOutput.Role.Arn)
+	case err != nil:
+		return "", fmt.Errorf("Failed to get role '%s': %v", spec.Name, err)
+	default:
+		c.logger.Debugf("Role '%s' already exists, updating its trust policy", spec.Name)
+		_, err = c.iamClient.UpdateAssumeRolePolicy(&iam.UpdateAssumeRolePolicyInput{
+			RoleName:       aws.String(spec.Name),
+			PolicyDocument: aws.String(spec.TrustPolicy),
+		})
+		if err != nil {
+			return "", fmt.Errorf("Failed to update trust policy of role '%s': %v", spec.Name, err)
+		}
+		roleARN = aws.StringValue(getOutput.Role.Arn)
+	}
+
+	_, err = c.iamClient.PutRolePolicy(&iam.PutRolePolicyInput{
+		RoleName:       aws.String(spec.Name),
+		PolicyName:     aws.String(spec.PolicyName()),
+		PolicyDocument: aws.String(spec.PermissionPolicy),
+	})
+	if err != nil {
+		return "", fmt.Errorf("Failed to set permission policy of role '%s': %v", spec.Name, err)
+	}
+	return roleARN, nil
+}
+
+// ListManagedRoles returns the roles whose names start with the given prefix and that are tagged
+// as managed by Red Hat.
+func (c *awsClient) ListManagedRoles(namePrefix string) ([]Role, error) {
+	names := []string{}
+	roleARNs := map[string]string{}
+	err := c.iamClient.ListRolesPages(&iam.ListRolesInput{},
+		func(output *iam.ListRolesOutput, lastPage bool) bool {
+			for _, role := range output.Roles {
+				name := aws.StringValue(role.RoleName)
+				if strings.HasPrefix(name, namePrefix) {
+					names = append(names, name)
+					roleARNs[name] = aws.StringValue(role.Arn)
+				}
+			}
+			return !lastPage
+		})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list roles: %v", err)
+	}
+
+	roles := []Role{}
+	for _, name := range names {
+		tagsOutput, err := c.iamClient.ListRoleTags(&iam.ListRoleTagsInput{
+			RoleName: aws.String(name),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get tags of role '%s': %v", name, err)
+		}
+		roleTags := map[string]string{}
+		for _, tag := range tagsOutput.Tags {
+			roleTags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+		if roleTags[tags.RedHatManaged] != "true" {
+			continue
+		}
+		roles = append(roles, Role{
+			Name: name,
+			ARN:  roleARNs[name],
+			Tags: roleTags,
+		})
+	}
+	return roles, nil
+}
+
+// DeleteRole deletes the inline policies of the role, detaches its managed policies and then
+// deletes the role.
+func (c *awsClient) DeleteRole(name string) error {
+	policiesOutput, err := c.iamClient.ListRolePolicies(&iam.ListRolePoliciesInput{
+		RoleName: aws.String(name),
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to list policies of role '%s': %v", name, err)
+	}
+	for _, policyName := range policiesOutput.PolicyNames {
+		_, err = c.iamClient.DeleteRolePolicy(&iam.DeleteRolePolicyInput{
+			RoleName:   aws.String(name),
+			PolicyName: policyName,
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to delete policy '%s' of role '%s': %v",
+				aws.StringValue(policyName), name, err)
+		}
+	}
+
+	attachedOutput, err := c.iamClient.ListAttachedRolePolicies(&iam.ListAttachedRolePoliciesInput{
+		RoleName: aws.String(name),
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to list attached policies of role '%s': %v", name, err)
+	}
+	for _, policy := range attachedOutput.AttachedPolicies {
+		_, err = c.iamClient.DetachRolePolicy(&iam.DetachRolePolicyInput{
+			RoleName:  aws.String(name),
+			PolicyArn: policy.PolicyArn,
+		})
+		if err != nil {
+			return fmt.Errorf("Failed to detach policy '%s' from role '%s': %v",
+				aws.StringValue(policy.PolicyArn), name, err)
+		}
+	}
+
+	_, err = c.iamClient.DeleteRole(&iam.DeleteRoleInput{
+		RoleName: aws.String(name),
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to delete role '%s': %v", name, err)
+	}
+	return nil
+}
+
+// EnsureOIDCProvider creates the OpenID Connect provider for the given endpoint URL, unless it
+// already exists. It returns the ARN of the provider.
+func (c *awsClient) EnsureOIDCProvider(issuerURL string, thumbprint string) (string, error) {
+	providerARN, err := c.GetOIDCProviderARN(issuerURL)
+	if err != nil {
+		return "", err
+	}
+	if providerARN != "" {
+		c.logger.Debugf("OIDC provider '%s' already exists", providerARN)
+		return providerARN, nil
+	}
+	output, err := c.iamClient.CreateOpenIDConnectProvider(&iam.CreateOpenIDConnectProviderInput{
+		Url:            aws.String(issuerURL),
+		ClientIDList:   aws.StringSlice(OIDCClientIDs),
+		ThumbprintList: aws.StringSlice([]string{thumbprint}),
+	})
+	if err != nil {
+		return "", fmt.Errorf("Failed to create OIDC provider for '%s': %v", issuerURL, err)
+	}
+	return aws.StringValue(output.OpenIDConnectProviderArn), nil
+}
+
+// GetOIDCProviderARN returns the ARN of the OpenID Connect provider for the given endpoint URL, or
+// an empty string if it doesn't exist.
+func (c *awsClient) GetOIDCProviderARN(issuerURL string) (string, error) {
+	issuer, err := OIDCIssuer(issuerURL)
+	if err != nil {
+		return "", err
+	}
+	output, err := c.iamClient.ListOpenIDConnectProviders(&iam.ListOpenIDConnectProvidersInput{})
+	if err != nil {
+		return "", fmt.Errorf("Failed to list OIDC providers: %v", err)
+	}
+	for _, provider := range output.OpenIDConnectProviderList {
+		providerARN := aws.StringValue(provider.Arn)
+		if strings.HasSuffix(providerARN, ":oidc-provider/"+issuer) {
+			return providerARN, nil
+		}
+	}
+	return "", nil
+}
+
+// ListOIDCProviders returns the OpenID Connect providers of the account.
+func (c *awsClient) ListOIDCProviders() ([]OIDCProvider, error) {
+	output, err := c.iamClient.ListOpenIDConnectProviders(&iam.ListOpenIDConnectProvidersInput{})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list OIDC providers: %v", err)
+	}
+	providers := make([]OIDCProvider, 0, len(output.OpenIDConnectProviderList))
+	for _, provider := range output.OpenIDConnectProviderList {
+		getOutput, err := c.iamClient.GetOpenIDConnectProvider(&iam.GetOpenIDConnectProviderInput{
+			OpenIDConnectProviderArn: provider.Arn,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get OIDC provider '%s': %v",
+				aws.StringValue(provider.Arn), err)
+		}
+		providers = append(providers, OIDCProvider{
+			ARN: aws.StringValue(provider.Arn),
+			URL: aws.StringValue(getOutput.Url),
+		})
+	}
+	return providers, nil
+}
+
+// DeleteOIDCProvider deletes the OpenID Connect provider with the given ARN.
+func (c *awsClient) DeleteOIDCProvider(providerARN string) error {
+	_, err := c.iamClient.DeleteOpenIDConnectProvider(&iam.DeleteOpenIDConnectProviderInput{
+		OpenIDConnectProviderArn: aws.String(providerARN),
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to delete OIDC provider '%s': %v", providerARN, err)
+	}
+	return nil
+}
+
+func isNoSuchEntity(err error) bool {
+	aerr, ok := err.(awserr.Error)
+	return ok && aerr.Code() == iam.ErrCodeNoSuchEntityException
+}
@@ -0,0 +1,172 @@
+package aws_test
+
+import (
+	"encoding/json"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mocks"
+	"github.com/openshift/moactl/pkg/aws/tags"
+)
+
+var _ = Describe("STS roles", func() {
+	const issuerURL = "https://rh-oidc.s3.us-east-1.amazonaws.com/1234abcd"
+
+	type statement struct {
+		Principal map[string]string
+		Action    string
+		Condition map[string]map[string][]string
+	}
+	trustStatement := func(spec aws.RoleSpec) statement {
+		document := struct {
+			Statement []statement
+		}{}
+		Expect(json.Unmarshal([]byte(spec.TrustPolicy), &document)).To(Succeed())
+		Expect(document.Statement).To(HaveLen(1))
+		return document.Statement[0]
+	}
+
+	It("Creates account roles trusted by Red Hat and by EC2", func() {
+		specs, err := aws.AccountRoleSpecs("MyOrg", "aws-us-gov")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(specs).To(HaveLen(len(aws.AccountRoles)))
+
+		installer := specs[0]
+		Expect(installer.Name).To(Equal("MyOrg-Installer-Role"))
+		Expect(installer.PolicyName()).To(Equal("MyOrg-Installer-Role-Policy"))
+		Expect(installer.PermissionPolicy).NotTo(BeEmpty())
+		Expect(installer.Tags).To(HaveKeyWithValue(tags.RolePrefix, "MyOrg"))
+		Expect(installer.Tags).To(HaveKeyWithValue(tags.RoleType, "Installer"))
+		Expect(trustStatement(installer).Principal).To(HaveKeyWithValue("AWS",
+			"arn:aws-us-gov:iam::710019948333:role/RH-Managed-OpenShift-Installer"))
+
+		worker := specs[3]
+		Expect(worker.Name).To(Equal("MyOrg-Worker-Role"))
+		Expect(trustStatement(worker).Principal).To(HaveKeyWithValue("Service", "ec2.amazonaws.com"))
+	})
+
+	It("Creates operator roles trusted by the OIDC provider of the cluster", func() {
+		specs, err := aws.OperatorRoleSpecs("mycluster", "123", "aws", "123456789012", issuerURL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(specs).To(HaveLen(len(aws.OperatorRoles)))
+
+		registry := specs[1]
+		Expect(registry.Name).To(Equal("mycluster-openshift-image-registry-installer-cloud-credentials"))
+		Expect(registry.Tags).To(HaveKeyWithValue(tags.ClusterID, "123"))
+		trust := trustStatement(registry)
+		Expect(trust.Action).To(Equal("sts:AssumeRoleWithWebIdentity"))
+		Expect(trust.Principal).To(HaveKeyWithValue("Federated",
+			"arn:aws:iam::123456789012:oidc-provider/rh-oidc.s3.us-east-1.amazonaws.com/1234abcd"))
+		Expect(trust.Condition["StringEquals"]).To(HaveKeyWithValue(
+			"rh-oidc.s3.us-east-1.amazonaws.com/1234abcd:sub", []string{
+				"system:serviceaccount:openshift-image-registry:cluster-image-registry-operator",
+				"system:serviceaccount:openshift-image-registry:registry",
+			}))
+	})
+
+	It("Truncates long role names", func() {
+		specs, err := aws.OperatorRoleSpecs("a-very-long-name", "123", "aws", "123456789012", issuerURL)
+		Expect(err).NotTo(HaveOccurred())
+		for _, spec := range specs {
+			Expect(len(spec.Name)).To(BeNumerically("<=", 64))
+		}
+	})
+
+	It("Rejects OIDC endpoints that don't use HTTPS", func() {
+		_, err := aws.OperatorRoleSpecs("mycluster", "123", "aws", "123456789012", "http://example.com")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Generates the AWS CLI commands of the manual mode", func() {
+		spec := aws.RoleSpec{
+			Name: "MyOrg-Worker-Role",
+			Tags: map[string]string{"b": "2", "a": "1"},
+		}
+		Expect(aws.CreateRoleCommands(spec, "trust.json", "policy.json")).To(Equal([]string{
+			"aws iam create-role --role-name MyOrg-Worker-Role --assume-role-policy-document " +
+				"file://trust.json --tags Key=a,Value=1 Key=b,Value=2",
+			"aws iam put-role-policy --role-name MyOrg-Worker-Role --policy-name " +
+				"MyOrg-Worker-Role-Policy --policy-document file://policy.json",
+		}))
+		Expect(aws.DeleteRoleCommands("MyOrg-Worker-Role")).To(Equal([]string{
+			"aws iam delete-role-policy --role-name MyOrg-Worker-Role --policy-name MyOrg-Worker-Role-Policy",
+			"aws iam delete-role --role-name MyOrg-Worker-Role",
+		}))
+		Expect(aws.CreateOIDCProviderCommand(issuerURL, "abcd")).To(Equal(
+			"aws iam create-open-id-connect-provider --url " + issuerURL +
+				" --client-id-list openshift sts.amazonaws.com --thumbprint-list abcd"))
+	})
+})
+
+var _ = Describe("EnsureRole", func() {
+	var (
+		client   aws.Client
+		mockCtrl *gomock.Controller
+		mockIAM  *mocks.MockIAMAPI
+		spec     aws.RoleSpec
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockIAM = mocks.NewMockIAMAPI(mockCtrl)
+		client = aws.New(
+			logrus.New(),
+			mockIAM,
+			mocks.NewMockEC2API(mockCtrl),
+			mocks.NewMockOrganizationsAPI(mockCtrl),
+			mocks.NewMockSTSAPI(mockCtrl),
+			mocks.NewMockCloudFormationAPI(mockCtrl),
+			mocks.NewMockServiceQuotasAPI(mockCtrl),
+			mocks.NewMockKMSAPI(mockCtrl),
+			&session.Session{Config: &awssdk.Config{Region: awssdk.String("us-east-1")}},
+			&aws.AccessKey{},
+		)
+		spec = aws.RoleSpec{
+			Name:             "MyOrg-Worker-Role",
+			TrustPolicy:      "{}",
+			PermissionPolicy: "{}",
+			Tags:             map[string]string{tags.RedHatManaged: "true"},
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("Creates the role when it doesn't exist", func() {
+		mockIAM.EXPECT().GetRole(gomock.Any()).Return(nil,
+			awserr.New(iam.ErrCodeNoSuchEntityException, "not found", nil))
+		mockIAM.EXPECT().CreateRole(gomock.Any()).DoAndReturn(
+			func(input *iam.CreateRoleInput) (*iam.CreateRoleOutput, error) {
+				Expect(input.Tags).To(HaveLen(1))
+				return &iam.CreateRoleOutput{Role: &iam.Role{
+					Arn: awssdk.String("arn:aws:iam::123456789012:role/MyOrg-Worker-Role"),
+				}}, nil
+			})
+		mockIAM.EXPECT().PutRolePolicy(gomock.Any()).Return(&iam.PutRolePolicyOutput{}, nil)
+
+		roleARN, err := client.EnsureRole(spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(roleARN).To(Equal("arn:aws:iam::123456789012:role/MyOrg-Worker-Role"))
+	})
+
+	It("Updates the trust policy of an existing role", func() {
+		mockIAM.EXPECT().GetRole(gomock.Any()).Return(&iam.GetRoleOutput{Role: &iam.Role{
+			Arn: awssdk.String("arn:aws:iam::123456789012:role/MyOrg-Worker-Role"),
+		}}, nil)
+		mockIAM.EXPECT().UpdateAssumeRolePolicy(gomock.Any()).Return(
+			&iam.UpdateAssumeRolePolicyOutput{}, nil)
+		mockIAM.EXPECT().PutRolePolicy(gomock.Any()).Return(&iam.PutRolePolicyOutput{}, nil)
+
+		_, err := client.EnsureRole(spec)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,110 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// Subnets tagged with this prefix and the 'owned' value belong to an existing cluster:
+const clusterTagPrefix = "kubernetes.io/cluster/"
+
+// ValidateSubnets checks that the given subnets exist, that all of them belong to the same VPC,
+// that none of them is owned by another cluster, and that they span the number of availability
+// zones required by the cluster: one for single-AZ clusters and three for multi-AZ clusters. It
+// returns the availability zones of the subnets, in the order they were given.
+func (c *awsClient) ValidateSubnets(subnetIDs []string, multiAZ bool) ([]string, error) {
+	if len(subnetIDs) == 0 {
+		return nil, fmt.Errorf("Expected at least one subnet ID")
+	}
+	res, err := c.ec2Client.DescribeSubnets(&ec2.DescribeSubnetsInput{
+		SubnetIds: aws.StringSlice(subnetIDs),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to describe subnets: %v", err)
+	}
+
+	subnets := make(map[string]*ec2.Subnet, len(res.Subnets))
+	for _, subnet := range res.Subnets {
+		subnets[aws.StringValue(subnet.SubnetId)] = subnet
+	}
+
+	vpcID := ""
+	availabilityZones := []string{}
+	seenZones := make(map[string]bool)
+	for _, subnetID := range subnetIDs {
+		subnet, ok := subnets[subnetID]
+		if !ok {
+			return nil, fmt.Errorf("Could not find the following subnet provided: %s", subnetID)
+		}
+		subnetVPC := aws.StringValue(subnet.VpcId)
+		if vpcID == "" {
+			vpcID = subnetVPC
+		} else if subnetVPC != vpcID {
+			return nil, fmt.Errorf("Expected all subnets to belong to the same VPC, "+
+				"but subnet '%s' belongs to '%s' and not to '%s'", subnetID, subnetVPC, vpcID)
+		}
+		for _, tag := range subnet.Tags {
+			key := aws.StringValue(tag.Key)
+			if strings.HasPrefix(key, clusterTagPrefix) && aws.StringValue(tag.Value) == "owned" {
+				return nil, fmt.Errorf("Subnet '%s' is owned by cluster '%s', "+
+					"expected a subnet that isn't managed by another cluster",
+					subnetID, strings.TrimPrefix(key, clusterTagPrefix))
+			}
+		}
+		zone := aws.StringValue(subnet.AvailabilityZone)
+		if !seenZones[zone] {
+			seenZones[zone] = true
+			availabilityZones = append(availabilityZones, zone)
+		}
+	}
+
+	expectedZones := 1
+	if multiAZ {
+		expectedZones = 3
+	}
+	if len(availabilityZones) != expectedZones {
+		return nil, fmt.Errorf("Expected the subnets to span %d availability zone(s), but they span %d: %s",
+			expectedZones, len(availabilityZones), strings.Join(availabilityZones, ", "))
+	}
+
+	return availabilityZones, nil
+}
+
+// ValidatePrivateSubnets checks that none of the given subnets has a default route through an
+// internet gateway, as PrivateLink clusters must only use private subnets.
+func (c *awsClient) ValidatePrivateSubnets(subnetIDs []string) error {
+	public := []string{}
+	for _, subnetID := range subnetIDs {
+		gateway, err := c.GetSubnetEgress(subnetID)
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(gateway, "igw-") {
+			public = append(public, fmt.Sprintf("'%s' (through '%s')", subnetID, gateway))
+		}
+	}
+	if len(public) > 0 {
+		return fmt.Errorf("Expected only private subnets, but the following subnets are public: %s",
+			strings.Join(public, ", "))
+	}
+	return nil
+}
@@ -27,3 +27,21 @@ const ClusterName = prefix + "cluster_name"
 
 // ClusterID is the name of the tag that will contain the identifier of the cluster.
 const ClusterID = prefix + "cluster_id"
+
+// RedHatManaged is the name of the tag added to the IAM roles created for clusters that use the
+// AWS Security Token Service, so that they can be found later.
+const RedHatManaged = "red-hat-managed"
+
+// RolePrefix is the name of the tag that will contain the prefix of the names of the account roles.
+const RolePrefix = prefix + "role_prefix"
+
+// RoleType is the name of the tag that will contain the type of an account role.
+const RoleType = prefix + "role_type"
+
+// OperatorNamespace is the name of the tag that will contain the namespace of the operator that
+// uses an operator role.
+const OperatorNamespace = "operator_namespace"
+
+// OperatorName is the name of the tag that will contain the name of the operator that uses an
+// operator role.
+const OperatorName = "operator_name"
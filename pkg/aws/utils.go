@@ -10,12 +10,16 @@ import (
 	"github.com/aws/aws-sdk-go/service/iam"
 
 	"github.com/openshift/moactl/assets"
+	"github.com/openshift/moactl/pkg/config"
 )
 
 // GetRegion will return a region selected by the user or given as a default to the AWS client.
-// If the region given is empty, it will first attempt to use the default, and, failing that, will
-// prompt for user input.
+// If the region given is empty, it will first attempt to use the region configured for the current
+// profile, then the default, and, failing that, will prompt for user input.
 func GetRegion(region string) (string, error) {
+	if region == "" {
+		region = configuredRegion()
+	}
 	if region == "" {
 		defaultSession, err := session.NewSessionWithOptions(session.Options{
 			SharedConfigState: session.SharedConfigEnable,
@@ -92,3 +96,21 @@ func readCFTemplate() (string, error) {
 
 	return string(cfTemplate), nil
 }
+
+// configuredRegion returns the region saved with 'rosa config set region', if any. Errors reading
+// the configuration aren't fatal here, as the default region can still be used.
+func configuredRegion() string {
+	cfg, err := config.Load()
+	if err != nil {
+		return ""
+	}
+	profile, err := config.CurrentProfile()
+	if err != nil {
+		return ""
+	}
+	region, err := cfg.Get(profile, "region")
+	if err != nil {
+		return ""
+	}
+	return region
+}
@@ -0,0 +1,181 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Some of the attributes of clusters aren't supported yet by the version of the OCM SDK that we
+// use. This file contains the functions used to merge those attributes directly into the JSON
+// documents sent to the clusters management API.
+
+package cluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
+)
+
+const clustersPath = "/api/clusters_mgmt/v1/clusters"
+
+// addCluster sends the request to create the given cluster, including the additional attributes.
+func addCluster(connection *sdk.Connection, spec *cmv1.Cluster, attributes map[string]interface{},
+	dryRun bool) (*cmv1.Cluster, error) {
+	body, err := mergeAttributes(spec, attributes)
+	if err != nil {
+		return nil, err
+	}
+	response, err := connection.Post().
+		Path(clustersPath).
+		Parameter("dryRun", dryRun).
+		Bytes(body).
+		Send()
+	if err != nil {
+		return nil, err
+	}
+	err = responseErr(response)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return nil, nil
+	}
+	return cmv1.UnmarshalCluster(response.Bytes())
+}
+
+// updateCluster sends the request to update the given cluster with the given body, as returned
+// by UpdatePatch.
+func updateCluster(connection *sdk.Connection, clusterID string, body []byte) error {
+	response, err := connection.Patch().
+		Path(fmt.Sprintf("%s/%s", clustersPath, clusterID)).
+		Bytes(body).
+		Send()
+	if err != nil {
+		return err
+	}
+	return responseErr(response)
+}
+
+// getClusterAttributes loads the JSON document of the cluster into the given value, so that the
+// attributes not supported by the SDK can be read.
+func getClusterAttributes(connection *sdk.Connection, clusterID string, value interface{}) error {
+	response, err := connection.Get().
+		Path(fmt.Sprintf("%s/%s", clustersPath, clusterID)).
+		Send()
+	if err != nil {
+		return err
+	}
+	err = responseErr(response)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(response.Bytes(), value)
+}
+
+// addMachinePool sends the request to add the given machine pool to the cluster, including the
+// additional attributes.
+func addMachinePool(connection *sdk.Connection, clusterID string, spec *cmv1.MachinePool,
+	attributes map[string]interface{}) error {
+	var buffer bytes.Buffer
+	err := cmv1.MarshalMachinePool(spec, &buffer)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal machine pool: %v", err)
+	}
+	body, err := mergeDocument(buffer.Bytes(), attributes)
+	if err != nil {
+		return err
+	}
+	response, err := connection.Post().
+		Path(fmt.Sprintf("%s/%s/machine_pools", clustersPath, clusterID)).
+		Bytes(body).
+		Send()
+	if err != nil {
+		return err
+	}
+	return responseErr(response)
+}
+
+// updateMachinePool sends the request to update the given machine pool of the cluster, including
+// the additional attributes.
+func updateMachinePool(connection *sdk.Connection, clusterID string, spec *cmv1.MachinePool,
+	attributes map[string]interface{}) error {
+	var buffer bytes.Buffer
+	err := cmv1.MarshalMachinePool(spec, &buffer)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal machine pool: %v", err)
+	}
+	body, err := mergeDocument(buffer.Bytes(), attributes)
+	if err != nil {
+		return err
+	}
+	response, err := connection.Patch().
+		Path(fmt.Sprintf("%s/%s/machine_pools/%s", clustersPath, clusterID, spec.ID())).
+		Bytes(body).
+		Send()
+	if err != nil {
+		return err
+	}
+	return responseErr(response)
+}
+
+func mergeAttributes(spec *cmv1.Cluster, attributes map[string]interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+	err := cmv1.MarshalCluster(spec, &buffer)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to marshal cluster: %v", err)
+	}
+	return mergeDocument(buffer.Bytes(), attributes)
+}
+
+// mergeDocument merges the attributes into the JSON document generated by the SDK.
+func mergeDocument(data []byte, attributes map[string]interface{}) ([]byte, error) {
+	document := map[string]interface{}{}
+	err := json.Unmarshal(data, &document)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to unmarshal document: %v", err)
+	}
+	mergeInto(document, attributes)
+	return json.Marshal(document)
+}
+
+// mergeInto copies the attributes into the document. Attributes that are objects in both are merged
+// recursively, so that nested attributes don't replace the ones supported by the SDK.
+func mergeInto(document map[string]interface{}, attributes map[string]interface{}) {
+	for key, value := range attributes {
+		nested, ok := value.(map[string]interface{})
+		if ok {
+			current, ok := document[key].(map[string]interface{})
+			if ok {
+				mergeInto(current, nested)
+				continue
+			}
+		}
+		document[key] = value
+	}
+}
+
+func responseErr(response *sdk.Response) error {
+	if response.Status() < http.StatusBadRequest {
+		return nil
+	}
+	res, err := ocmerrors.UnmarshalError(response.Bytes())
+	if err != nil {
+		return fmt.Errorf("Unexpected status code %d", response.Status())
+	}
+	return handleErr(res, res)
+}
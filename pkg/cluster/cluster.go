@@ -23,19 +23,23 @@ import (
 	"regexp"
 	"time"
 
+	sdk "github.com/openshift-online/ocm-sdk-go"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
 
 	"github.com/openshift/moactl/pkg/aws"
+	rerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/info"
 	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/addons"
 	"github.com/openshift/moactl/pkg/ocm/properties"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
 )
 
 // Regular expression to used to make sure that the identifier or name given by the user is
 // safe and that it there is no risk of SQL injection:
-var clusterKeyRE = regexp.MustCompile(`^(\w|-)+$`)
+var clusterKeyRE = regexp.MustCompile(`^(\w|-|\*)+$`)
 
 // Cluster names must be valid DNS-1035 labels, so they must consist of lower case alphanumeric
 // characters or '-', start with an alphabetic character, and end with an alphanumeric character
@@ -54,10 +58,27 @@ type Spec struct {
 	// Scaling config
 	ComputeMachineType string
 	ComputeNodes       int
+	// Autoscaling of the default machine pool, between the minimum and maximum replicas. When it
+	// is enabled the number of compute nodes is ignored.
+	Autoscaling bool
+	MinReplicas int
+	MaxReplicas int
+	// Labels of the nodes of the default machine pool. When nil the labels aren't changed.
+	ComputeLabels map[string]string
 
 	// SubnetIDs
 	SubnetIds []string
 
+	// Custom AWS tags added to all the AWS resources created for the cluster
+	Tags map[string]string
+
+	// ARN of the customer managed KMS key used to encrypt the EBS volumes of the nodes
+	KMSKeyARN string
+
+	// Encryption of etcd and FIPS validated cryptographic libraries
+	EtcdEncryption bool
+	FIPS           bool
+
 	// AvailabilityZones
 	AvailabilityZones []string
 
@@ -68,17 +89,41 @@ type Spec struct {
 	HostPrefix  int
 	Private     *bool
 
+	// Make the API and the applications reachable only through AWS PrivateLink
+	PrivateLink bool
+
+	// Cluster-wide proxy config
+	HTTPProxy             *string
+	HTTPSProxy            *string
+	NoProxy               *string
+	AdditionalTrustBundle *string
+
 	// Properties
 	CustomProperties map[string]string
 
+	// Keys of the custom properties removed when editing the cluster
+	RemoveProperties []string
+
 	// Access control config
 	ClusterAdmins *bool
 
+	// Refuse to delete the cluster unless the protection is overridden
+	DeleteProtection *bool
+
+	// Node drain grace period in minutes
+	NodeDrainGracePeriod *float64
+
+	// Attributes of the cluster given as 'path=value' settings, as returned by ParseSettings
+	Settings map[string]interface{}
+
 	// Simulate creating a cluster but don't actually create it
 	DryRun *bool
 
 	// Disable SCP checks in the installer by setting credentials mode as mint
 	DisableSCPChecks *bool
+
+	// Don't tag the AWS administrator user, the caller prints the AWS CLI command that does it
+	ManualMode bool
 }
 
 func IsValidClusterKey(clusterKey string) bool {
@@ -103,7 +148,33 @@ func HasClusters(client *cmv1.ClustersClient, creatorARN string) (bool, error) {
 	return response.Total() > 0, nil
 }
 
-func CreateCluster(client *cmv1.ClustersClient, config Spec) (*cmv1.Cluster, error) {
+// CountClustersByCreator returns the number of clusters created by each of the ARNs of the given
+// AWS account. It is used to find clusters that aren't visible to the current user because they
+// were created with a different ARN of the same account.
+func CountClustersByCreator(client *cmv1.ClustersClient, accountID string) (map[string]int, error) {
+	query := fmt.Sprintf("properties.%s like 'arn:%%::%s:%%'", properties.CreatorARN, accountID)
+	request := client.List().Search(query)
+	counts := map[string]int{}
+	size := 100
+	page := 1
+	for {
+		response, err := request.Page(page).Size(size).Send()
+		if err != nil {
+			return nil, handleErr(response.Error(), err)
+		}
+		response.Items().Each(func(cluster *cmv1.Cluster) bool {
+			counts[cluster.Properties()[properties.CreatorARN]]++
+			return true
+		})
+		if response.Size() != size {
+			break
+		}
+		page++
+	}
+	return counts, nil
+}
+
+func CreateCluster(connection *sdk.Connection, config Spec) (*cmv1.Cluster, error) {
 	reporter, err := rprtr.New().
 		Build()
 
@@ -120,7 +191,7 @@ func CreateCluster(client *cmv1.ClustersClient, config Spec) (*cmv1.Cluster, err
 	// Create the AWS client:
 	awsClient, err := aws.NewClient().
 		Logger(logger).
-		Region(aws.DefaultRegion).
+		Region(aws.GetDefaultRegion()).
 		Build()
 	if err != nil {
 		return nil, fmt.Errorf("Failed to create AWS client: %v", err)
@@ -131,15 +202,18 @@ func CreateCluster(client *cmv1.ClustersClient, config Spec) (*cmv1.Cluster, err
 		return nil, fmt.Errorf("Unable to create cluster spec: %v", err)
 	}
 
-	cluster, err := client.Add().Parameter("dryRun", *config.DryRun).Body(spec).Send()
+	dryRun := config.DryRun != nil && *config.DryRun
+	clusterObject, err := addCluster(connection, spec, clusterAttributes(config), dryRun)
 	if err != nil {
-		return nil, handleErr(cluster.Error(), err)
+		return nil, err
 	}
-	if config.DryRun != nil && *config.DryRun {
+	if dryRun {
 		return nil, nil
 	}
 
-	clusterObject := cluster.Body()
+	if config.ManualMode {
+		return clusterObject, nil
+	}
 
 	// Add tags to the AWS administrator user containing the identifier and name of the cluster:
 	err = awsClient.TagUser(aws.AdminUserName, clusterObject.ID(), clusterObject.Name())
@@ -174,36 +248,26 @@ func GetClusters(client *cmv1.ClustersClient, creatorARN string, count int) (clu
 	return clusters, nil
 }
 
+// GetCluster finds the cluster with the given name or identifier, created by the given user.
 func GetCluster(client *cmv1.ClustersClient, clusterKey string, creatorARN string) (*cmv1.Cluster, error) {
-	query := fmt.Sprintf(
-		"(id = '%s' or name = '%s') and properties.%s = '%s'",
-		clusterKey, clusterKey, properties.CreatorARN, creatorARN,
-	)
-	response, err := client.List().
-		Search(query).
-		Page(1).
-		Size(1).
-		Send()
-	if err != nil {
-		return nil, handleErr(response.Error(), err)
-	}
-
-	switch response.Total() {
-	case 0:
-		return nil, fmt.Errorf("There is no cluster with identifier or name '%s'", clusterKey)
-	case 1:
-		return response.Items().Slice()[0], nil
-	default:
-		return nil, fmt.Errorf("There are %d clusters with identifier or name '%s'", response.Total(), clusterKey)
-	}
+	return ocm.GetCluster(client, clusterKey, creatorARN)
 }
 
-func UpdateCluster(client *cmv1.ClustersClient, clusterKey string, creatorARN string, config Spec) error {
-	cluster, err := GetCluster(client, clusterKey, creatorARN)
+func UpdateCluster(connection *sdk.Connection, clusterKey string, creatorARN string, config Spec) error {
+	cluster, err := GetCluster(connection.ClustersMgmt().V1().Clusters(), clusterKey, creatorARN)
+	if err != nil {
+		return err
+	}
+	body, err := UpdatePatch(cluster, config)
 	if err != nil {
 		return err
 	}
+	return updateCluster(connection, cluster.ID(), body)
+}
 
+// UpdatePatch returns the JSON document sent to the clusters management API to apply the
+// configuration to the given cluster. Only the attributes set in the configuration are included.
+func UpdatePatch(cluster *cmv1.Cluster, config Spec) ([]byte, error) {
 	clusterBuilder := cmv1.NewCluster()
 
 	// Update expiration timestamp
@@ -211,12 +275,16 @@ func UpdateCluster(client *cmv1.ClustersClient, clusterKey string, creatorARN st
 		clusterBuilder = clusterBuilder.ExpirationTimestamp(config.Expiration)
 	}
 
-	// Scale cluster
-	if config.ComputeNodes != 0 {
-		clusterBuilder = clusterBuilder.Nodes(
-			cmv1.NewClusterNodes().
-				Compute(config.ComputeNodes),
-		)
+	// Scale cluster and change the labels of the default machine pool
+	if config.ComputeNodes != 0 || config.ComputeLabels != nil {
+		clusterNodesBuilder := cmv1.NewClusterNodes()
+		if config.ComputeNodes != 0 {
+			clusterNodesBuilder = clusterNodesBuilder.Compute(config.ComputeNodes)
+		}
+		if config.ComputeLabels != nil {
+			clusterNodesBuilder = clusterNodesBuilder.ComputeLabels(config.ComputeLabels)
+		}
+		clusterBuilder = clusterBuilder.Nodes(clusterNodesBuilder)
 	}
 
 	// Toggle private mode
@@ -239,52 +307,42 @@ func UpdateCluster(client *cmv1.ClustersClient, clusterKey string, creatorARN st
 		clusterBuilder = clusterBuilder.ClusterAdminEnabled(*config.ClusterAdmins)
 	}
 
-	clusterSpec, err := clusterBuilder.Build()
-	if err != nil {
-		return err
+	// Change custom properties and toggle delete protection, which is stored as a property:
+	if config.CustomProperties != nil || config.RemoveProperties != nil ||
+		config.DeleteProtection != nil {
+		props := editProperties(cluster.Properties(), config.CustomProperties,
+			config.RemoveProperties)
+		if config.DeleteProtection != nil {
+			props = deleteProtectionProperties(props, *config.DeleteProtection)
+		}
+		clusterBuilder = clusterBuilder.Properties(props)
 	}
 
-	response, err := client.Cluster(cluster.ID()).Update().Body(clusterSpec).Send()
-	if err != nil {
-		return handleErr(response.Error(), err)
+	// Change the node drain grace period used during upgrades
+	if config.NodeDrainGracePeriod != nil {
+		clusterBuilder = clusterBuilder.NodeDrainGracePeriod(
+			cmv1.NewValue().
+				Value(*config.NodeDrainGracePeriod).
+				Unit("minutes"),
+		)
 	}
 
-	return nil
-}
-
-func DeleteCluster(client *cmv1.ClustersClient, clusterKey string, creatorARN string) (*cmv1.Cluster, error) {
-	cluster, err := GetCluster(client, clusterKey, creatorARN)
+	clusterSpec, err := clusterBuilder.Build()
 	if err != nil {
 		return nil, err
 	}
 
-	response, err := client.Cluster(cluster.ID()).Delete().Send()
-	if err != nil {
-		return nil, handleErr(response.Error(), err)
-	}
-
-	return cluster, nil
+	return mergeAttributes(clusterSpec, clusterAttributes(config))
 }
 
+// Deprecated: use addons.InstallAddOn instead.
 func InstallAddOn(client *cmv1.ClustersClient, clusterKey string, creatorARN string, addOnID string) error {
 	cluster, err := GetCluster(client, clusterKey, creatorARN)
 	if err != nil {
 		return err
 	}
 
-	addOnInstallation, err := cmv1.NewAddOnInstallation().
-		Addon(cmv1.NewAddOn().ID(addOnID)).
-		Build()
-	if err != nil {
-		return err
-	}
-
-	response, err := client.Cluster(cluster.ID()).Addons().Add().Body(addOnInstallation).Send()
-	if err != nil {
-		return handleErr(response.Error(), err)
-	}
-
-	return nil
+	return addons.InstallAddOn(client, cluster.ID(), addOnID, nil)
 }
 
 func createClusterSpec(config Spec, awsClient aws.Client) (*cmv1.Cluster, error) {
@@ -359,7 +417,12 @@ func createClusterSpec(config Spec, awsClient aws.Client) (*cmv1.Cluster, error)
 		clusterBuilder = clusterBuilder.ExpirationTimestamp(config.Expiration)
 	}
 
-	if config.ComputeMachineType != "" || config.ComputeNodes != 0 || len(config.AvailabilityZones) > 0 {
+	if config.EtcdEncryption {
+		clusterBuilder = clusterBuilder.EtcdEncryption(true)
+	}
+
+	if config.ComputeMachineType != "" || config.ComputeNodes != 0 || len(config.AvailabilityZones) > 0 ||
+		len(config.ComputeLabels) > 0 || config.Autoscaling {
 		clusterNodesBuilder := cmv1.NewClusterNodes()
 		if config.ComputeMachineType != "" {
 			clusterNodesBuilder = clusterNodesBuilder.ComputeMachineType(
@@ -368,12 +431,21 @@ func createClusterSpec(config Spec, awsClient aws.Client) (*cmv1.Cluster, error)
 
 			reporter.Debugf("Using machine type '%s'", config.ComputeMachineType)
 		}
-		if config.ComputeNodes != 0 {
+		if config.Autoscaling {
+			clusterNodesBuilder = clusterNodesBuilder.AutoscaleCompute(
+				cmv1.NewMachinePoolAutoscaling().
+					MinReplicas(config.MinReplicas).
+					MaxReplicas(config.MaxReplicas),
+			)
+		} else if config.ComputeNodes != 0 {
 			clusterNodesBuilder = clusterNodesBuilder.Compute(config.ComputeNodes)
 		}
 		if len(config.AvailabilityZones) > 0 {
 			clusterNodesBuilder = clusterNodesBuilder.AvailabilityZones(config.AvailabilityZones...)
 		}
+		if len(config.ComputeLabels) > 0 {
+			clusterNodesBuilder = clusterNodesBuilder.ComputeLabels(config.ComputeLabels)
+		}
 		clusterBuilder = clusterBuilder.Nodes(clusterNodesBuilder)
 	}
 
@@ -437,14 +509,53 @@ func createClusterSpec(config Spec, awsClient aws.Client) (*cmv1.Cluster, error)
 	return clusterSpec, nil
 }
 
+// clusterAttributes returns the attributes of the cluster that aren't supported by the SDK.
+func clusterAttributes(config Spec) map[string]interface{} {
+	attributes := map[string]interface{}{}
+
+	if config.HTTPProxy != nil || config.HTTPSProxy != nil || config.NoProxy != nil {
+		proxy := map[string]string{}
+		if config.HTTPProxy != nil {
+			proxy["http_proxy"] = *config.HTTPProxy
+		}
+		if config.HTTPSProxy != nil {
+			proxy["https_proxy"] = *config.HTTPSProxy
+		}
+		if config.NoProxy != nil {
+			proxy["no_proxy"] = *config.NoProxy
+		}
+		attributes["proxy"] = proxy
+	}
+	if config.AdditionalTrustBundle != nil {
+		attributes["additional_trust_bundle"] = *config.AdditionalTrustBundle
+	}
+	if config.FIPS {
+		attributes["fips"] = true
+	}
+	// The AWS attributes are merged with the ones supported by the SDK:
+	awsAttributes := map[string]interface{}{}
+	if len(config.Tags) > 0 {
+		awsAttributes["tags"] = config.Tags
+	}
+	if config.KMSKeyARN != "" {
+		awsAttributes["kms_key_arn"] = config.KMSKeyARN
+	}
+	if config.PrivateLink {
+		awsAttributes["private_link"] = true
+	}
+	if len(awsAttributes) > 0 {
+		attributes["aws"] = awsAttributes
+	}
+	// Settings given with '--set' are merged last, nested as in the document of the cluster:
+	mergeInto(attributes, config.Settings)
+
+	return attributes
+}
+
 func cidrIsEmpty(cidr net.IPNet) bool {
 	return cidr.String() == "<nil>"
 }
 
 func handleErr(res *ocmerrors.Error, err error) error {
-	msg := res.Reason()
-	if msg == "" {
-		msg = err.Error()
-	}
-	return errors.New(msg)
+	return rerrors.FromOCM(res, err)
 }
@@ -0,0 +1,31 @@
+package cluster_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+func TestCluster(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Cluster Suite")
+}
+
+// newCluster returns a builder for a ready cluster named 'mycluster', that the tests change to
+// describe the cluster that they need.
+func newCluster() *cmv1.ClusterBuilder {
+	return cmv1.NewCluster().
+		ID("1a2b").
+		Name("mycluster").
+		State(cmv1.ClusterStateReady)
+}
+
+// build builds the cluster, failing the test if that isn't possible.
+func build(builder *cmv1.ClusterBuilder) *cmv1.Cluster {
+	cluster, err := builder.Build()
+	Expect(err).NotTo(HaveOccurred())
+	return cluster
+}
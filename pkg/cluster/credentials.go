@@ -0,0 +1,51 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to retrieve the credentials that give access to a cluster.
+
+package cluster
+
+import (
+	"fmt"
+	"net/http"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// GetCredentials returns the admin credentials and the admin kubeconfig of the cluster stored by
+// OCM. It returns nil if OCM doesn't provide them for the cluster, which is the case for clusters
+// whose admin credentials are managed by the user, for example with 'rosa create admin'.
+func GetCredentials(client *cmv1.ClustersClient, clusterID string) (*cmv1.ClusterCredentials, error) {
+	response, err := client.Cluster(clusterID).Credentials().Get().Send()
+	if err != nil {
+		switch response.Status() {
+		case http.StatusForbidden, http.StatusNotFound:
+			return nil, nil
+		}
+		return nil, handleErr(response.Error(), err)
+	}
+	credentials := response.Body()
+	if credentials.Kubeconfig() == "" && credentials.Admin().Password() == "" {
+		return nil, nil
+	}
+	return credentials, nil
+}
+
+// LoginCommand returns the 'oc login' command used to login to the API of the cluster with the
+// given user.
+func LoginCommand(cluster *cmv1.Cluster, username string) string {
+	return fmt.Sprintf("oc login %s --username %s", cluster.API().URL(), username)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to protect clusters against accidental deletion.
+
+package cluster
+
+import (
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/properties"
+)
+
+// IsDeleteProtected checks if the delete protection of the cluster is enabled.
+func IsDeleteProtected(cluster *cmv1.Cluster) bool {
+	return cluster.Properties()[properties.DeleteProtection] == "true"
+}
+
+// ValidateDelete checks that the cluster can be deleted: clusters with delete protection enabled
+// can only be deleted when the protection is explicitly overridden.
+func ValidateDelete(cluster *cmv1.Cluster, overrideDeleteProtection bool) error {
+	if IsDeleteProtected(cluster) && !overrideDeleteProtection {
+		return rerrors.ConflictErrorf("Cluster '%s' has delete protection enabled, disable it with 'rosa edit "+
+			"cluster %s --enable-delete-protection=false' or use the '--override-delete-protection' "+
+			"flag to delete it anyway", cluster.Name(), cluster.Name())
+	}
+	return nil
+}
+
+// deleteProtectionProperties returns a copy of the properties with the delete protection enabled or
+// disabled. The rest of the properties are preserved, as the update replaces all of them.
+func deleteProtectionProperties(current map[string]string, enabled bool) map[string]string {
+	result := make(map[string]string, len(current)+1)
+	for key, value := range current {
+		result[key] = value
+	}
+	if enabled {
+		result[properties.DeleteProtection] = "true"
+	} else {
+		delete(result, properties.DeleteProtection)
+	}
+	return result
+}
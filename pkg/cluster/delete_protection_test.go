@@ -0,0 +1,31 @@
+package cluster_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/cluster"
+	"github.com/openshift/moactl/pkg/ocm/properties"
+)
+
+var _ = Describe("Delete protection", func() {
+	DescribeTable("ValidateDelete",
+		func(props map[string]string, override bool, protected bool, valid bool) {
+			cluster := build(newCluster().Properties(props))
+			Expect(IsDeleteProtected(cluster)).To(Equal(protected))
+			err := ValidateDelete(cluster, override)
+			if valid {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+			}
+		},
+		Entry("Without delete protection",
+			map[string]string{properties.CreatorARN: "arn"}, false, false, true),
+		Entry("With delete protection",
+			map[string]string{properties.DeleteProtection: "true"}, false, true, false),
+		Entry("With delete protection overridden",
+			map[string]string{properties.DeleteProtection: "true"}, true, true, true),
+	)
+})
@@ -0,0 +1,133 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to validate the encryption options of clusters: the
+// customer managed KMS key of the EBS volumes of the nodes, the encryption of etcd and FIPS mode.
+
+package cluster
+
+import (
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/aws"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/versions"
+)
+
+// Oldest OpenShift minor releases that support the encryption of etcd and FIPS mode:
+const (
+	MinEtcdEncryptionVersion = "4.6"
+	MinFIPSVersion           = "4.10"
+)
+
+// ValidateEtcdEncryption checks that the OpenShift version supports the encryption of etcd.
+func ValidateEtcdEncryption(version string) error {
+	supported, err := versions.IsAtLeast(version, MinEtcdEncryptionVersion)
+	if err != nil {
+		return rerrors.Wrap(rerrors.ExitCodeValidation, err)
+	}
+	if !supported {
+		return rerrors.ValidationErrorf("Encryption of etcd requires OpenShift %s or later, "+
+			"got '%s'", MinEtcdEncryptionVersion, version)
+	}
+	return nil
+}
+
+// ValidateFIPS checks that the OpenShift version supports FIPS mode, and that etcd is encrypted, as
+// FIPS mode requires it.
+func ValidateFIPS(version string, etcdEncryption bool) error {
+	supported, err := versions.IsAtLeast(version, MinFIPSVersion)
+	if err != nil {
+		return rerrors.Wrap(rerrors.ExitCodeValidation, err)
+	}
+	if !supported {
+		return rerrors.ValidationErrorf("FIPS mode requires OpenShift %s or later, got '%s'",
+			MinFIPSVersion, version)
+	}
+	if !etcdEncryption {
+		return rerrors.ValidationErrorf("FIPS mode requires the encryption of etcd, it can't be " +
+			"used with '--etcd-encryption=false'")
+	}
+	return nil
+}
+
+// IsFIPS checks if the cluster uses FIPS validated cryptographic libraries.
+func IsFIPS(connection *sdk.Connection, clusterID string) (bool, error) {
+	document := struct {
+		FIPS bool `json:"fips"`
+	}{}
+	err := getClusterAttributes(connection, clusterID, &document)
+	if err != nil {
+		return false, err
+	}
+	return document.FIPS, nil
+}
+
+// ValidateCustomerManagedKey checks the combination of the '--enable-customer-managed-key' and
+// '--kms-key-arn' options and, when a key is given, that it exists and can be used in the region of
+// the AWS client.
+func ValidateCustomerManagedKey(awsClient aws.Client, enabled bool, kmsKeyARN string) error {
+	if kmsKeyARN != "" && !enabled {
+		return rerrors.ValidationErrorf("Option '--kms-key-arn' requires '--enable-customer-managed-key'")
+	}
+	if !enabled {
+		return nil
+	}
+	if kmsKeyARN == "" {
+		return rerrors.ValidationErrorf("Option '--enable-customer-managed-key' requires the ARN " +
+			"of the key in '--kms-key-arn'")
+	}
+	err := awsClient.ValidateKMSKey(kmsKeyARN)
+	if err != nil {
+		return rerrors.Wrap(rerrors.ExitCodeValidation, err)
+	}
+	return nil
+}
+
+// AddMachinePool adds the machine pool to the cluster. When a KMS key is given the EBS volumes of
+// the nodes of the machine pool are encrypted with it instead of the key of the cluster. When spot
+// instances are enabled the nodes use them instead of on-demand instances.
+func AddMachinePool(connection *sdk.Connection, clusterID string, machinePool *cmv1.MachinePool,
+	kmsKeyARN string, spot *Spot) error {
+	awsAttributes := map[string]interface{}{}
+	if kmsKeyARN != "" {
+		awsAttributes["kms_key_arn"] = kmsKeyARN
+	}
+	if spot != nil && spot.Enabled {
+		mergeInto(awsAttributes, spot.attributes())
+	}
+	attributes := map[string]interface{}{}
+	if len(awsAttributes) > 0 {
+		attributes["aws"] = awsAttributes
+	}
+	return addMachinePool(connection, clusterID, machinePool, attributes)
+}
+
+// GetKMSKeyARN returns the ARN of the customer managed KMS key used to encrypt the EBS volumes of
+// the nodes of the cluster, or an empty string if the volumes are encrypted with the default key.
+func GetKMSKeyARN(connection *sdk.Connection, clusterID string) (string, error) {
+	document := struct {
+		AWS struct {
+			KMSKeyARN string `json:"kms_key_arn"`
+		} `json:"aws"`
+	}{}
+	err := getClusterAttributes(connection, clusterID, &document)
+	if err != nil {
+		return "", err
+	}
+	return document.AWS.KMSKeyARN, nil
+}
@@ -0,0 +1,56 @@
+package cluster_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+var _ = Describe("Encryption", func() {
+	Context("ValidateEtcdEncryption", func() {
+		It("accepts supported versions", func() {
+			Expect(ValidateEtcdEncryption("4.6.1")).To(Succeed())
+			Expect(ValidateEtcdEncryption("4.10.0-rc.1-candidate")).To(Succeed())
+		})
+
+		It("rejects older versions", func() {
+			err := ValidateEtcdEncryption("4.5.16")
+			Expect(err).To(MatchError(ContainSubstring("requires OpenShift 4.6 or later")))
+			Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+		})
+	})
+
+	Context("ValidateFIPS", func() {
+		It("accepts supported versions with etcd encryption", func() {
+			Expect(ValidateFIPS("4.10.3", true)).To(Succeed())
+		})
+
+		It("rejects older versions", func() {
+			Expect(ValidateFIPS("4.9.8", true)).To(MatchError(ContainSubstring("4.10 or later")))
+		})
+
+		It("requires etcd encryption", func() {
+			Expect(ValidateFIPS("4.10.3", false)).To(MatchError(ContainSubstring("--etcd-encryption=false")))
+		})
+	})
+
+	Context("ValidateCustomerManagedKey", func() {
+		It("accepts the default key", func() {
+			Expect(ValidateCustomerManagedKey(nil, false, "")).To(Succeed())
+		})
+
+		It("rejects a key without enabling customer managed keys", func() {
+			err := ValidateCustomerManagedKey(nil, false, "arn:aws:kms:us-east-1:123456789012:key/abc")
+			Expect(err).To(MatchError(ContainSubstring("requires '--enable-customer-managed-key'")))
+			Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+		})
+
+		It("rejects enabling customer managed keys without a key", func() {
+			err := ValidateCustomerManagedKey(nil, true, "")
+			Expect(err).To(MatchError(ContainSubstring("'--kms-key-arn'")))
+			Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+		})
+	})
+})
@@ -0,0 +1,115 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to hibernate and resume clusters. The version of the OCM
+// SDK that we use doesn't support hibernation yet, so the requests are sent directly to the
+// clusters management API.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// States of clusters that are being hibernated or resumed, which aren't part of the SDK yet:
+const (
+	ClusterStatePoweringDown cmv1.ClusterState = "powering_down"
+	ClusterStateHibernating  cmv1.ClusterState = "hibernating"
+	ClusterStateResuming     cmv1.ClusterState = "resuming"
+)
+
+// ValidateHibernation checks that the given cluster can be hibernated: it must be ready and it must
+// not have a scheduled upgrade, as the upgrade would fail while the cluster is hibernating.
+func ValidateHibernation(cluster *cmv1.Cluster, scheduledUpgrade *cmv1.UpgradePolicy) error {
+	if cluster.State() != cmv1.ClusterStateReady {
+		return rerrors.ConflictErrorf("Cluster '%s' is in state '%s', only ready clusters can be hibernated",
+			cluster.Name(), cluster.State())
+	}
+	if scheduledUpgrade != nil {
+		return rerrors.ConflictErrorf("Cluster '%s' has a scheduled upgrade to version %s on %s, cancel it "+
+			"before hibernating the cluster", cluster.Name(), scheduledUpgrade.Version(),
+			scheduledUpgrade.NextRun().Format("2006-01-02 15:04 MST"))
+	}
+	return nil
+}
+
+// ValidateResume checks that the given cluster can be resumed: it must be hibernating.
+func ValidateResume(cluster *cmv1.Cluster) error {
+	if cluster.State() != ClusterStateHibernating {
+		return rerrors.ConflictErrorf("Cluster '%s' is in state '%s', only hibernating clusters can be resumed",
+			cluster.Name(), cluster.State())
+	}
+	return nil
+}
+
+// HibernateCluster sends the request to hibernate the cluster.
+func HibernateCluster(connection *sdk.Connection, clusterID string) error {
+	return postClusterAction(connection, clusterID, "hibernate")
+}
+
+// ResumeCluster sends the request to resume a hibernating cluster.
+func ResumeCluster(connection *sdk.Connection, clusterID string) error {
+	return postClusterAction(connection, clusterID, "resume")
+}
+
+// WaitForState polls the cluster until it reaches the given state, until the timeout expires or
+// until the context is cancelled.
+func WaitForState(ctx context.Context, client *cmv1.ClustersClient, clusterID string,
+	state cmv1.ClusterState, interval time.Duration, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		response, err := client.Cluster(clusterID).Get().SendContext(ctx)
+		if ctx.Err() != nil {
+			return fmt.Errorf("Stopped waiting for cluster '%s' to be %s: %w", clusterID, state, ctx.Err())
+		}
+		if err != nil {
+			return handleErr(response.Error(), err)
+		}
+		current := response.Body().State()
+		if current == state {
+			return nil
+		}
+		if current == cmv1.ClusterStateError {
+			return fmt.Errorf("Cluster '%s' is in state '%s'", clusterID, current)
+		}
+		if time.Now().After(deadline) {
+			return rerrors.TimeoutErrorf("Timed out waiting for cluster '%s' to be %s, current state is '%s'",
+				clusterID, state, current)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Stopped waiting for cluster '%s' to be %s: %w", clusterID, state, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
+
+func postClusterAction(connection *sdk.Connection, clusterID string, action string) error {
+	response, err := connection.Post().
+		Path(fmt.Sprintf("%s/%s/%s", clustersPath, clusterID, action)).
+		Send()
+	if err != nil {
+		return err
+	}
+	return responseErr(response)
+}
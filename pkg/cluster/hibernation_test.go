@@ -0,0 +1,53 @@
+package cluster_test
+
+import (
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/cluster"
+)
+
+var _ = Describe("Hibernation", func() {
+	DescribeTable("ValidateHibernation",
+		func(state cmv1.ClusterState, valid bool) {
+			err := ValidateHibernation(build(newCluster().State(state)), nil)
+			if valid {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+			}
+		},
+		Entry("Ready", cmv1.ClusterStateReady, true),
+		Entry("Hibernating", ClusterStateHibernating, false),
+		Entry("Installing", cmv1.ClusterStateInstalling, false),
+	)
+
+	It("Rejects clusters with a scheduled upgrade", func() {
+		upgradePolicy, err := cmv1.NewUpgradePolicy().
+			Version("4.5.20").
+			NextRun(time.Now().Add(time.Hour)).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+
+		err = ValidateHibernation(build(newCluster()), upgradePolicy)
+		Expect(err).To(MatchError(ContainSubstring("4.5.20")))
+	})
+
+	DescribeTable("ValidateResume",
+		func(state cmv1.ClusterState, valid bool) {
+			err := ValidateResume(build(newCluster().State(state)))
+			if valid {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+			}
+		},
+		Entry("Hibernating", ClusterStateHibernating, true),
+		Entry("Ready", cmv1.ClusterStateReady, false),
+	)
+})
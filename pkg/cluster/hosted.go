@@ -0,0 +1,39 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to detect clusters with hosted control planes, which run
+// the control plane outside of the AWS account of the cluster. The SDK doesn't support them yet.
+
+package cluster
+
+import (
+	sdk "github.com/openshift-online/ocm-sdk-go"
+)
+
+// IsHostedControlPlane checks if the control plane of the cluster is hosted, so that it is
+// upgraded independently of the machine pools.
+func IsHostedControlPlane(connection *sdk.Connection, clusterID string) (bool, error) {
+	document := struct {
+		Hypershift struct {
+			Enabled bool `json:"enabled"`
+		} `json:"hypershift"`
+	}{}
+	err := getClusterAttributes(connection, clusterID, &document)
+	if err != nil {
+		return false, err
+	}
+	return document.Hypershift.Enabled, nil
+}
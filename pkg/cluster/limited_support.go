@@ -0,0 +1,70 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to load the reasons why a cluster is in limited support.
+// They aren't supported yet by the version of the OCM SDK that we use.
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// LimitedSupportReason explains why Red Hat support of a cluster is limited.
+type LimitedSupportReason struct {
+	ID                string    `json:"id"`
+	Summary           string    `json:"summary"`
+	Details           string    `json:"details"`
+	DetectionType     string    `json:"detection_type"`
+	CreationTimestamp time.Time `json:"creation_timestamp"`
+}
+
+// GetLimitedSupportReasons returns the reasons why the cluster is in limited support. The list is
+// empty when the cluster is fully supported, or when the OCM environment doesn't track the reasons.
+func GetLimitedSupportReasons(connection *sdk.Connection, clusterID string) (
+	[]*LimitedSupportReason, error) {
+	response, err := connection.Get().
+		Path(fmt.Sprintf("%s/%s/limited_support_reasons", clustersPath, clusterID)).
+		Parameter("size", -1).
+		Send()
+	if err != nil {
+		return nil, err
+	}
+	err = responseErr(response)
+	if rerrors.ExitCode(err) == rerrors.ExitCodeNotFound {
+		return []*LimitedSupportReason{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	document := struct {
+		Items []*LimitedSupportReason `json:"items"`
+	}{}
+	err = json.Unmarshal(response.Bytes(), &document)
+	if err != nil {
+		return nil, err
+	}
+	if document.Items == nil {
+		return []*LimitedSupportReason{}, nil
+	}
+	return document.Items, nil
+}
@@ -0,0 +1,275 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to search, sort and enrich the lists of clusters shown to
+// the user.
+
+package cluster
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/paging"
+	"github.com/openshift/moactl/pkg/ocm/properties"
+	"github.com/openshift/moactl/pkg/ocm/versions"
+)
+
+// MaxWorkers is the maximum number of concurrent requests sent to load the details of clusters.
+const MaxWorkers = 10
+
+// Keys used to sort lists of clusters:
+const (
+	SortByName    = "name"
+	SortByCreated = "created"
+	SortByState   = "state"
+	SortByRegion  = "region"
+	SortByVersion = "version"
+)
+
+// SortKeys are the keys that can be used to sort lists of clusters.
+var SortKeys = []string{SortByName, SortByCreated, SortByState, SortByRegion, SortByVersion}
+
+// States are the states that can be used to search for clusters.
+var States = []cmv1.ClusterState{
+	cmv1.ClusterStateError,
+	cmv1.ClusterStateInstalling,
+	cmv1.ClusterStatePending,
+	cmv1.ClusterStateReady,
+	cmv1.ClusterStateUninstalling,
+	cmv1.ClusterStateUnknown,
+	ClusterStatePoweringDown,
+	ClusterStateHibernating,
+	ClusterStateResuming,
+}
+
+// searchValueRE matches the values that are safe to use in searches, so that there is no risk of
+// SQL injection.
+var searchValueRE = regexp.MustCompile(`^[\w.-]*$`)
+
+// ListOptions are the criteria used to search for clusters. Empty values match any cluster.
+type ListOptions struct {
+	State    string
+	Region   string
+	NameLike string
+}
+
+// SearchQuery returns the search query that finds the clusters created by the given user that
+// match the options.
+func SearchQuery(creatorARN string, options ListOptions) (string, error) {
+	terms := []string{fmt.Sprintf("properties.%s = '%s'", properties.CreatorARN, creatorARN)}
+	if options.State != "" {
+		valid := false
+		names := make([]string, len(States))
+		for i, state := range States {
+			names[i] = string(state)
+			valid = valid || options.State == string(state)
+		}
+		if !valid {
+			return "", rerrors.ValidationErrorf("State '%s' isn't valid, expected one of: %s",
+				options.State, strings.Join(names, ", "))
+		}
+		terms = append(terms, fmt.Sprintf("state = '%s'", options.State))
+	}
+	if options.Region != "" {
+		if !searchValueRE.MatchString(options.Region) {
+			return "", rerrors.ValidationErrorf("Region '%s' isn't valid", options.Region)
+		}
+		terms = append(terms, fmt.Sprintf("region.id = '%s'", options.Region))
+	}
+	if options.NameLike != "" {
+		// Users are used to '*' as wildcard, but the search uses '%':
+		pattern := strings.ReplaceAll(options.NameLike, "*", "%")
+		if !searchValueRE.MatchString(strings.ReplaceAll(pattern, "%", "")) {
+			return "", rerrors.ValidationErrorf("Name pattern '%s' isn't valid: it must contain only "+
+				"letters, digits, dashes, underscores, dots and wildcards", options.NameLike)
+		}
+		if !strings.Contains(pattern, "%") {
+			pattern = "%" + pattern + "%"
+		}
+		terms = append(terms, fmt.Sprintf("name like '%s'", pattern))
+	}
+	return strings.Join(terms, " and "), nil
+}
+
+// ListClusters loads the clusters created by the given user that match the options, one page at a
+// time, and calls the function with the requested clusters of each page as soon as it arrives.
+func ListClusters(client *cmv1.ClustersClient, creatorARN string, options ListOptions,
+	pages paging.Options, fn func(clusters []*cmv1.Cluster) error) error {
+	query, err := SearchQuery(creatorARN, options)
+	if err != nil {
+		return err
+	}
+	request := client.List().Search(query)
+	for pager := pages.Pager(); pager.Next(); {
+		response, err := request.Page(pager.Page()).Size(pager.Size()).Send()
+		if err != nil {
+			return rerrors.FromOCM(response.Error(), err)
+		}
+		clusters := response.Items().Slice()
+		from, to := pager.Select(len(clusters))
+		if from == to {
+			continue
+		}
+		err = fn(clusters[from:to])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateSortKey checks that the clusters can be sorted by the given key, optionally prefixed
+// with '-' to reverse the order.
+func ValidateSortKey(key string) error {
+	key = strings.TrimPrefix(key, "-")
+	for _, valid := range SortKeys {
+		if key == valid {
+			return nil
+		}
+	}
+	return rerrors.ValidationErrorf("Sort key '%s' isn't valid, expected one of: %s", key,
+		strings.Join(SortKeys, ", "))
+}
+
+// SortClusters sorts the clusters by the given key, in reverse order if it is prefixed with '-'.
+// Clusters with the same value keep their order.
+func SortClusters(clusters []*cmv1.Cluster, key string) error {
+	err := ValidateSortKey(key)
+	if err != nil {
+		return err
+	}
+	reverse := strings.HasPrefix(key, "-")
+	var less func(a, b *cmv1.Cluster) bool
+	switch strings.TrimPrefix(key, "-") {
+	case SortByName:
+		less = func(a, b *cmv1.Cluster) bool { return a.Name() < b.Name() }
+	case SortByCreated:
+		less = func(a, b *cmv1.Cluster) bool {
+			return a.CreationTimestamp().Before(b.CreationTimestamp())
+		}
+	case SortByState:
+		less = func(a, b *cmv1.Cluster) bool { return a.State() < b.State() }
+	case SortByRegion:
+		less = func(a, b *cmv1.Cluster) bool { return a.Region().ID() < b.Region().ID() }
+	case SortByVersion:
+		less = func(a, b *cmv1.Cluster) bool {
+			return versions.Compare(versions.GetRawVersion(a.Version()),
+				versions.GetRawVersion(b.Version())) < 0
+		}
+	}
+	sort.SliceStable(clusters, func(i, j int) bool {
+		if reverse {
+			return less(clusters[j], clusters[i])
+		}
+		return less(clusters[i], clusters[j])
+	})
+	return nil
+}
+
+// Details contains the information about a cluster that isn't included in the list of clusters
+// and needs additional requests.
+type Details struct {
+	// Total number of compute nodes, including the ones of the machine pools:
+	ComputeNodes int
+
+	// Versions that the cluster can be upgraded to, latest first:
+	AvailableUpgrades []string
+
+	// Error loading the details, if any:
+	Err error
+}
+
+// GetDetails loads the details of the clusters concurrently, using at most MaxWorkers requests at
+// the same time. The result contains the details of each cluster in the same order. Failures are
+// reported in the details instead of stopping the other requests.
+func GetDetails(client *cmv1.Client, clusters []*cmv1.Cluster) []*Details {
+	// Clusters often have the same version, so the upgrades of each version are loaded only once:
+	versionIDs := []string{}
+	upgrades := map[string][]string{}
+	upgradeErrs := map[string]error{}
+	for _, cluster := range clusters {
+		versionID := versions.GetVersionID(cluster)
+		if _, ok := upgrades[versionID]; !ok && versionID != "" {
+			upgrades[versionID] = nil
+			versionIDs = append(versionIDs, versionID)
+		}
+	}
+	var lock sync.Mutex
+	parallel(len(versionIDs), func(i int) {
+		available, err := versions.GetAvailableUpgrades(client, versionIDs[i])
+		lock.Lock()
+		defer lock.Unlock()
+		upgrades[versionIDs[i]] = available
+		upgradeErrs[versionIDs[i]] = err
+	})
+
+	result := make([]*Details, len(clusters))
+	parallel(len(clusters), func(i int) {
+		cluster := clusters[i]
+		details := &Details{
+			ComputeNodes: cluster.Nodes().Compute(),
+		}
+		versionID := versions.GetVersionID(cluster)
+		details.AvailableUpgrades = upgrades[versionID]
+		details.Err = upgradeErrs[versionID]
+
+		// Machine pools can only be listed once the cluster is ready:
+		if cluster.State() == cmv1.ClusterStateReady {
+			machinePools, err := ocm.GetMachinePools(client.Clusters(), cluster.ID())
+			if err != nil {
+				details.Err = err
+			}
+			for _, machinePool := range machinePools {
+				details.ComputeNodes += machinePool.Replicas()
+			}
+		}
+		result[i] = details
+	})
+	return result
+}
+
+// parallel calls the function for each index from zero to count, using at most MaxWorkers
+// goroutines, and waits till all the calls finish.
+func parallel(count int, fn func(i int)) {
+	indexes := make(chan int)
+	var group sync.WaitGroup
+	workers := MaxWorkers
+	if count < workers {
+		workers = count
+	}
+	for w := 0; w < workers; w++ {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < count; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	group.Wait()
+}
@@ -0,0 +1,111 @@
+package cluster_test
+
+import (
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+var _ = Describe("List", func() {
+	Context("SearchQuery", func() {
+		const creator = "arn:aws:iam::123:user/me"
+
+		It("only searches by creator without options", func() {
+			query, err := SearchQuery(creator, ListOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(query).To(Equal("properties.rosa_creator_arn = 'arn:aws:iam::123:user/me'"))
+		})
+
+		It("adds the state, region and name", func() {
+			query, err := SearchQuery(creator, ListOptions{
+				State:    "ready",
+				Region:   "us-east-1",
+				NameLike: "dev",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(query).To(HaveSuffix(" and state = 'ready' and region.id = 'us-east-1' and " +
+				"name like '%dev%'"))
+		})
+
+		It("replaces the wildcards of the name", func() {
+			query, err := SearchQuery(creator, ListOptions{NameLike: "dev*"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(query).To(HaveSuffix(" and name like 'dev%'"))
+		})
+
+		It("rejects unknown states", func() {
+			_, err := SearchQuery(creator, ListOptions{State: "sleeping"})
+			Expect(err).To(MatchError(ContainSubstring("hibernating")))
+			Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+		})
+
+		It("rejects values that aren't safe", func() {
+			_, err := SearchQuery(creator, ListOptions{Region: "us-east-1' or '1'='1"})
+			Expect(err).To(HaveOccurred())
+			_, err = SearchQuery(creator, ListOptions{NameLike: "x' or name like '"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("SortClusters", func() {
+		var clusters []*cmv1.Cluster
+
+		BeforeEach(func() {
+			sortable := func(name string, created time.Time, region string,
+				version string) *cmv1.Cluster {
+				return build(newCluster().
+					Name(name).
+					CreationTimestamp(created).
+					Region(cmv1.NewCloudRegion().ID(region)).
+					Version(cmv1.NewVersion().ID("openshift-v" + version).RawID(version)))
+			}
+			now := time.Now()
+			clusters = []*cmv1.Cluster{
+				sortable("b", now.Add(-time.Hour), "us-west-2", "4.6.10"),
+				sortable("c", now, "eu-west-1", "4.6.9"),
+				sortable("a", now.Add(-2*time.Hour), "us-east-1", "4.5.16"),
+			}
+		})
+
+		names := func() []string {
+			result := []string{}
+			for _, cluster := range clusters {
+				result = append(result, cluster.Name())
+			}
+			return result
+		}
+
+		It("sorts by name", func() {
+			Expect(SortClusters(clusters, SortByName)).To(Succeed())
+			Expect(names()).To(Equal([]string{"a", "b", "c"}))
+		})
+
+		It("sorts by creation time in reverse order", func() {
+			Expect(SortClusters(clusters, "-"+SortByCreated)).To(Succeed())
+			Expect(names()).To(Equal([]string{"c", "b", "a"}))
+		})
+
+		It("sorts by region", func() {
+			Expect(SortClusters(clusters, SortByRegion)).To(Succeed())
+			Expect(names()).To(Equal([]string{"c", "a", "b"}))
+		})
+
+		It("compares the numbers of the versions", func() {
+			Expect(SortClusters(clusters, SortByVersion)).To(Succeed())
+			Expect(names()).To(Equal([]string{"a", "c", "b"}))
+		})
+
+		It("rejects unknown keys", func() {
+			err := SortClusters(clusters, "size")
+			Expect(err).To(MatchError(ContainSubstring("created")))
+			Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+			Expect(ValidateSortKey("-name")).To(Succeed())
+		})
+	})
+})
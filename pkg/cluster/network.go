@@ -0,0 +1,181 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to validate the networking options of a cluster without
+// contacting OCM or AWS.
+
+package cluster
+
+import (
+	"fmt"
+	"net"
+)
+
+// Defaults used by OCM for the pod CIDR block and the host prefix when they aren't given.
+const (
+	DefaultPodCIDR    = "10.128.0.0/14"
+	DefaultHostPrefix = 23
+)
+
+// ParseCIDR parses the CIDR block given for the option with the given name.
+func ParseCIDR(name string, value string) (*net.IPNet, error) {
+	_, block, err := net.ParseCIDR(value)
+	if err != nil {
+		return nil, fmt.Errorf("Expected '%s' to be a valid CIDR block, got '%s'", name, value)
+	}
+	return block, nil
+}
+
+// ValidateCIDRs checks that the machine, service and pod CIDR blocks are valid and don't overlap,
+// and that the pod CIDR block is large enough for the host prefix. Empty blocks and a zero host
+// prefix aren't checked, as the defaults of OCM are used for them.
+func ValidateCIDRs(machineCIDR string, serviceCIDR string, podCIDR string, hostPrefix int) error {
+	names := []string{"machine_cidr", "service_cidr", "pod_cidr"}
+	blocks := map[string]*net.IPNet{}
+	for i, value := range []string{machineCIDR, serviceCIDR, podCIDR} {
+		if value == "" {
+			continue
+		}
+		block, err := ParseCIDR(names[i], value)
+		if err != nil {
+			return err
+		}
+		blocks[names[i]] = block
+	}
+	for i, name := range names {
+		for _, other := range names[i+1:] {
+			if blocks[name] != nil && blocks[other] != nil && overlap(blocks[name], blocks[other]) {
+				return fmt.Errorf("CIDR blocks '%s' (%s) and '%s' (%s) overlap",
+					name, blocks[name], other, blocks[other])
+			}
+		}
+	}
+	if hostPrefix == 0 {
+		return nil
+	}
+	if hostPrefix < 1 || hostPrefix > 32 {
+		return fmt.Errorf("Expected 'host_prefix' to be between 1 and 32, got %d", hostPrefix)
+	}
+	if blocks["pod_cidr"] != nil {
+		size, _ := blocks["pod_cidr"].Mask.Size()
+		if hostPrefix < size {
+			return fmt.Errorf("Expected 'host_prefix' to be at least the size of 'pod_cidr' "+
+				"(/%d), got %d", size, hostPrefix)
+		}
+	}
+	return nil
+}
+
+// ValidateNetworkSize checks that the machine CIDR block has an address for each of the given
+// number of nodes, and that the pod CIDR block can be split into a block of the size of the host
+// prefix for each of them. An empty machine block isn't checked. When only one of the pod block and
+// the host prefix is given, the default of OCM is used for the other one.
+func ValidateNetworkSize(machineCIDR string, podCIDR string, hostPrefix int, nodes int) error {
+	if machineCIDR != "" {
+		block, err := ParseCIDR("machine_cidr", machineCIDR)
+		if err != nil {
+			return err
+		}
+		ones, bits := block.Mask.Size()
+		if addresses := capacity(bits - ones); addresses < nodes {
+			return fmt.Errorf("CIDR block 'machine_cidr' (%s) has %d addresses, but the cluster "+
+				"can have up to %d nodes", block, addresses, nodes)
+		}
+	}
+	if podCIDR != "" || hostPrefix != 0 {
+		if podCIDR == "" {
+			podCIDR = DefaultPodCIDR
+		}
+		if hostPrefix == 0 {
+			hostPrefix = DefaultHostPrefix
+		}
+		block, err := ParseCIDR("pod_cidr", podCIDR)
+		if err != nil {
+			return err
+		}
+		size, _ := block.Mask.Size()
+		if hostPrefix < size {
+			return fmt.Errorf("Expected 'host_prefix' to be at least the size of 'pod_cidr' "+
+				"(/%d), got %d", size, hostPrefix)
+		}
+		if blocks := capacity(hostPrefix - size); blocks < nodes {
+			return fmt.Errorf("CIDR block 'pod_cidr' (%s) with host prefix %d has room for %d "+
+				"nodes, but the cluster can have up to %d nodes", block, hostPrefix, blocks, nodes)
+		}
+	}
+	return nil
+}
+
+// ValidateVPCCIDRs checks that the machine CIDR block contains the CIDR blocks of the subnets that
+// the cluster is installed into, and that the service and pod CIDR blocks don't overlap the CIDR
+// blocks of their VPC. Empty blocks aren't checked.
+func ValidateVPCCIDRs(machineCIDR string, serviceCIDR string, podCIDR string, subnetCIDRs []string,
+	vpcCIDRs []string) error {
+	if machineCIDR != "" {
+		machine, err := ParseCIDR("machine_cidr", machineCIDR)
+		if err != nil {
+			return err
+		}
+		machineSize, _ := machine.Mask.Size()
+		for _, value := range subnetCIDRs {
+			subnet, err := ParseCIDR("subnet", value)
+			if err != nil {
+				return err
+			}
+			subnetSize, _ := subnet.Mask.Size()
+			if !machine.Contains(subnet.IP) || subnetSize < machineSize {
+				return fmt.Errorf("CIDR block 'machine_cidr' (%s) doesn't contain the CIDR block "+
+					"of subnet %s, it must contain all the subnets of the cluster", machine, subnet)
+			}
+		}
+	}
+	names := []string{"service_cidr", "pod_cidr"}
+	for i, value := range []string{serviceCIDR, podCIDR} {
+		if value == "" {
+			continue
+		}
+		block, err := ParseCIDR(names[i], value)
+		if err != nil {
+			return err
+		}
+		for _, vpcValue := range vpcCIDRs {
+			vpc, err := ParseCIDR("vpc", vpcValue)
+			if err != nil {
+				return err
+			}
+			if overlap(block, vpc) {
+				return fmt.Errorf("CIDR block '%s' (%s) overlaps the CIDR block of the VPC (%s)",
+					names[i], block, vpc)
+			}
+		}
+	}
+	return nil
+}
+
+// capacity returns the number of items that fit in the given number of bits, limited so that it
+// doesn't overflow.
+func capacity(bits int) int {
+	if bits > 30 {
+		bits = 30
+	}
+	return 1 << uint(bits)
+}
+
+// overlap returns true if the given CIDR blocks have addresses in common. As blocks are aligned to
+// their size, that only happens when one of them contains the first address of the other.
+func overlap(a *net.IPNet, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
@@ -0,0 +1,66 @@
+package cluster_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/cluster"
+)
+
+var _ = Describe("Network", func() {
+	DescribeTable("ValidateCIDRs",
+		func(machineCIDR, serviceCIDR, podCIDR string, hostPrefix int, valid bool) {
+			err := ValidateCIDRs(machineCIDR, serviceCIDR, podCIDR, hostPrefix)
+			if valid {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+			}
+		},
+		Entry("Defaults", "", "", "", 0, true),
+		Entry("Valid blocks", "10.0.0.0/16", "172.30.0.0/16", "10.128.0.0/14", 23, true),
+		Entry("Invalid block", "10.0.0.0", "", "", 0, false),
+		Entry("Machine and pod overlap", "10.128.0.0/16", "", "10.128.0.0/14", 0, false),
+		Entry("Service inside machine", "10.0.0.0/8", "10.30.0.0/16", "", 0, false),
+		Entry("Host prefix out of range", "", "", "", 33, false),
+		Entry("Host prefix larger than pod block", "", "", "10.128.0.0/14", 12, false),
+	)
+
+	DescribeTable("ValidateNetworkSize",
+		func(machineCIDR, podCIDR string, hostPrefix int, nodes int, valid bool) {
+			err := ValidateNetworkSize(machineCIDR, podCIDR, hostPrefix, nodes)
+			if valid {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+			}
+		},
+		Entry("Defaults", "", "", 0, 100, true),
+		Entry("Enough room", "10.0.0.0/16", "10.128.0.0/14", 23, 100, true),
+		Entry("Machine block too small", "10.0.0.0/28", "", 0, 20, false),
+		Entry("Pod block too small for the host prefix", "", "10.128.0.0/20", 23, 9, false),
+		Entry("Pod block just large enough", "", "10.128.0.0/20", 23, 8, true),
+		Entry("Pod block too small for the default host prefix", "", "10.128.0.0/20", 0, 9, false),
+		Entry("Host prefix with room in the default pod block", "", "", 26, 1000, true),
+		Entry("Host prefix too small for the default pod block", "", "", 18, 100, false),
+	)
+
+	DescribeTable("ValidateVPCCIDRs",
+		func(machineCIDR, serviceCIDR, podCIDR string, valid bool) {
+			err := ValidateVPCCIDRs(machineCIDR, serviceCIDR, podCIDR,
+				[]string{"10.0.0.0/24", "10.0.1.0/24"}, []string{"10.0.0.0/16"})
+			if valid {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+			}
+		},
+		Entry("Defaults", "", "", "", true),
+		Entry("Valid blocks", "10.0.0.0/16", "172.30.0.0/16", "10.128.0.0/14", true),
+		Entry("Machine block without the subnets", "10.1.0.0/16", "", "", false),
+		Entry("Machine block inside a subnet", "10.0.0.0/25", "", "", false),
+		Entry("Service block inside the VPC", "", "10.0.128.0/17", "", false),
+		Entry("Pod block containing the VPC", "", "", "10.0.0.0/8", false),
+	)
+})
@@ -0,0 +1,82 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the presets of the options of the create cluster command: named sets of
+// values for common use cases that are used when the options aren't given explicitly.
+
+package cluster
+
+import (
+	"sort"
+	"strings"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// Preset maps the names of the options of the create cluster command to their values.
+type Preset map[string]string
+
+// Presets are the presets included in rosa. Custom presets with the same names replace them.
+var Presets = map[string]Preset{
+	// Smallest cluster, for development and testing:
+	"minimal": {
+		"multi-az":             "false",
+		"compute-machine-type": "m5.xlarge",
+		"enable-autoscaling":   "false",
+	},
+	// Highly available cluster that grows with its workload:
+	"production": {
+		"multi-az":             "true",
+		"compute-machine-type": "m5.2xlarge",
+		"enable-autoscaling":   "true",
+		"min-replicas":         "3",
+		"max-replicas":         "9",
+		"machine-cidr":         "10.0.0.0/16",
+		"service-cidr":         "172.30.0.0/16",
+		"pod-cidr":             "10.128.0.0/14",
+		"host-prefix":          "23",
+	},
+}
+
+// PresetNames returns the sorted names of the included presets and the given custom presets.
+func PresetNames(custom map[string]map[string]string) []string {
+	seen := map[string]bool{}
+	names := []string{}
+	for name := range Presets {
+		seen[name] = true
+		names = append(names, name)
+	}
+	for name := range custom {
+		if !seen[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetPreset returns the preset with the given name, looking first in the given custom presets and
+// then in the included ones.
+func GetPreset(name string, custom map[string]map[string]string) (Preset, error) {
+	if preset, ok := custom[name]; ok {
+		return Preset(preset), nil
+	}
+	if preset, ok := Presets[name]; ok {
+		return preset, nil
+	}
+	return nil, rerrors.ValidationErrorf("Unknown preset '%s', expected one of: %s", name,
+		strings.Join(PresetNames(custom), ", "))
+}
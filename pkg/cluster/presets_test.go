@@ -0,0 +1,41 @@
+package cluster_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+var _ = Describe("Presets", func() {
+	custom := map[string]map[string]string{
+		"staging": {"compute-nodes": "4"},
+		"minimal": {"compute-machine-type": "m5.large"},
+	}
+
+	It("returns the included presets", func() {
+		preset, err := GetPreset("production", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(preset).To(HaveKeyWithValue("multi-az", "true"))
+		Expect(preset).To(HaveKeyWithValue("enable-autoscaling", "true"))
+	})
+
+	It("returns custom presets", func() {
+		preset, err := GetPreset("staging", custom)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(preset).To(Equal(Preset{"compute-nodes": "4"}))
+	})
+
+	It("prefers custom presets to the included ones", func() {
+		preset, err := GetPreset("minimal", custom)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(preset).To(Equal(Preset{"compute-machine-type": "m5.large"}))
+	})
+
+	It("rejects unknown presets", func() {
+		_, err := GetPreset("huge", custom)
+		Expect(err).To(MatchError(ContainSubstring("expected one of: minimal, production, staging")))
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+	})
+})
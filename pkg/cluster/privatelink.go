@@ -0,0 +1,72 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to validate and describe PrivateLink clusters, whose API
+// and applications are only reachable through AWS PrivateLink from inside the VPC of the cluster.
+
+package cluster
+
+import (
+	sdk "github.com/openshift-online/ocm-sdk-go"
+
+	"github.com/openshift/moactl/pkg/aws"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// PrivateLink describes the PrivateLink configuration of a cluster and the VPC endpoint service
+// created for its API, which is only available once the cluster has been provisioned.
+type PrivateLink struct {
+	Enabled  bool                `json:"private_link"`
+	Endpoint PrivateLinkEndpoint `json:"private_link_endpoint"`
+}
+
+// PrivateLinkEndpoint describes the VPC endpoint service and VPC endpoint used to reach the API of
+// a PrivateLink cluster.
+type PrivateLinkEndpoint struct {
+	ServiceName string `json:"service_name"`
+	EndpointID  string `json:"vpc_endpoint_id"`
+	State       string `json:"state"`
+}
+
+// ValidatePrivateLink checks that a PrivateLink cluster is installed into an existing VPC and that
+// all the given subnets are private.
+func ValidatePrivateLink(awsClient aws.Client, private bool, subnetIDs []string) error {
+	if !private {
+		return rerrors.ValidationErrorf("PrivateLink clusters must be private, they can't be used " +
+			"with '--private=false'")
+	}
+	if len(subnetIDs) == 0 {
+		return rerrors.ValidationErrorf("PrivateLink clusters must be installed into an existing " +
+			"VPC, use the '--subnet-ids' flag to select the private subnets")
+	}
+	err := awsClient.ValidatePrivateSubnets(subnetIDs)
+	if err != nil {
+		return rerrors.Wrap(rerrors.ExitCodeValidation, err)
+	}
+	return nil
+}
+
+// GetPrivateLink returns the PrivateLink configuration of the cluster.
+func GetPrivateLink(connection *sdk.Connection, clusterID string) (*PrivateLink, error) {
+	document := struct {
+		AWS PrivateLink `json:"aws"`
+	}{}
+	err := getClusterAttributes(connection, clusterID, &document)
+	if err != nil {
+		return nil, err
+	}
+	return &document.AWS, nil
+}
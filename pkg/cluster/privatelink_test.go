@@ -0,0 +1,25 @@
+package cluster_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+var _ = Describe("PrivateLink", func() {
+	Context("ValidatePrivateLink", func() {
+		It("rejects public clusters", func() {
+			err := ValidatePrivateLink(nil, false, []string{"subnet-1"})
+			Expect(err).To(MatchError(ContainSubstring("'--private=false'")))
+			Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+		})
+
+		It("requires subnets", func() {
+			err := ValidatePrivateLink(nil, true, nil)
+			Expect(err).To(MatchError(ContainSubstring("'--subnet-ids'")))
+			Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+		})
+	})
+})
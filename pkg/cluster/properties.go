@@ -0,0 +1,126 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to edit the custom properties of clusters, which can be
+// used to label clusters and to select them in the commands that change groups of clusters.
+
+package cluster
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/properties"
+)
+
+// propertyKeyRE matches the valid keys of custom properties: letters, numbers and the characters
+// '_.-/', starting and ending with a letter or number.
+var propertyKeyRE = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9_./\-]*[a-zA-Z0-9])?$`)
+
+// ParseProperties parses a list of property changes: 'key=value' sets the property and 'key-'
+// removes it. It returns the properties to set and the keys of the properties to remove.
+func ParseProperties(values []string) (map[string]string, []string, error) {
+	set := map[string]string{}
+	remove := []string{}
+	removed := map[string]bool{}
+	for _, value := range values {
+		var key string
+		tokens := strings.SplitN(value, "=", 2)
+		if len(tokens) == 2 {
+			key = strings.TrimSpace(tokens[0])
+		} else if strings.HasSuffix(value, "-") {
+			key = strings.TrimSpace(strings.TrimSuffix(value, "-"))
+		} else {
+			return nil, nil, rerrors.ValidationErrorf("Expected property '%s' to be in 'key=value' "+
+				"format, or in 'key-' format to remove it", value)
+		}
+		err := ValidatePropertyKey(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		_, isSet := set[key]
+		if isSet || removed[key] {
+			return nil, nil, rerrors.ValidationErrorf("Duplicated property key '%s'", key)
+		}
+		if len(tokens) == 2 {
+			set[key] = strings.TrimSpace(tokens[1])
+		} else {
+			removed[key] = true
+			remove = append(remove, key)
+		}
+	}
+	return set, remove, nil
+}
+
+// ValidatePropertyKey checks that the key of a custom property is valid and that it isn't one of
+// the properties used by rosa itself.
+func ValidatePropertyKey(key string) error {
+	if !propertyKeyRE.MatchString(key) {
+		return rerrors.ValidationErrorf("Property key '%s' must contain only letters, numbers and "+
+			"the characters '_.-/', and must start and end with a letter or number", key)
+	}
+	if properties.IsReserved(key) {
+		return rerrors.ValidationErrorf("Property '%s' is reserved and can't be changed", key)
+	}
+	return nil
+}
+
+// CustomProperties returns the properties of the cluster that aren't used by rosa itself.
+func CustomProperties(cluster *cmv1.Cluster) map[string]string {
+	result := map[string]string{}
+	for key, value := range cluster.Properties() {
+		if !properties.IsReserved(key) {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// FormatProperties formats the properties as a comma-separated list of 'key=value', sorted by key.
+func FormatProperties(props map[string]string) string {
+	keys := make([]string, 0, len(props))
+	for key := range props {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	output := make([]string, 0, len(keys))
+	for _, key := range keys {
+		output = append(output, fmt.Sprintf("%s=%s", key, props[key]))
+	}
+	return strings.Join(output, ", ")
+}
+
+// editProperties returns a copy of the properties with the given ones set and removed, as updates
+// replace all the properties of the cluster.
+func editProperties(current map[string]string, set map[string]string,
+	remove []string) map[string]string {
+	result := make(map[string]string, len(current)+len(set))
+	for key, value := range current {
+		result[key] = value
+	}
+	for key, value := range set {
+		result[key] = value
+	}
+	for _, key := range remove {
+		delete(result, key)
+	}
+	return result
+}
@@ -0,0 +1,95 @@
+package cluster_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/properties"
+)
+
+var _ = Describe("Properties", func() {
+	Context("ParseProperties", func() {
+		It("parses the properties to set and to remove", func() {
+			set, remove, err := ParseProperties([]string{"env=staging", "team-", "region/zone= a "})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(set).To(Equal(map[string]string{
+				"env":         "staging",
+				"region/zone": "a",
+			}))
+			Expect(remove).To(Equal([]string{"team"}))
+		})
+
+		It("rejects properties without value", func() {
+			_, _, err := ParseProperties([]string{"env"})
+			Expect(err).To(MatchError(ContainSubstring("key=value")))
+			Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+		})
+
+		It("rejects invalid keys", func() {
+			_, _, err := ParseProperties([]string{"-env=staging"})
+			Expect(err).To(MatchError(ContainSubstring("must contain only")))
+		})
+
+		It("rejects properties used by rosa", func() {
+			_, _, err := ParseProperties([]string{properties.DeleteProtection + "-"})
+			Expect(err).To(MatchError(ContainSubstring("reserved")))
+		})
+
+		It("rejects duplicated keys", func() {
+			_, _, err := ParseProperties([]string{"env=staging", "env-"})
+			Expect(err).To(MatchError(ContainSubstring("Duplicated")))
+		})
+	})
+
+	Context("CustomProperties", func() {
+		It("returns only the properties that aren't used by rosa", func() {
+			cluster, err := cmv1.NewCluster().
+				Properties(map[string]string{
+					properties.CreatorARN: "arn",
+					"env":                 "staging",
+					"team":                "payments",
+				}).
+				Build()
+			Expect(err).NotTo(HaveOccurred())
+			props := CustomProperties(cluster)
+			Expect(props).To(Equal(map[string]string{"env": "staging", "team": "payments"}))
+			Expect(FormatProperties(props)).To(Equal("env=staging, team=payments"))
+		})
+	})
+
+	Context("UpdatePatch", func() {
+		It("preserves the rest of the properties", func() {
+			cluster, err := cmv1.NewCluster().
+				ID("123").
+				Properties(map[string]string{
+					properties.CreatorARN: "arn",
+					"env":                 "dev",
+					"team":                "payments",
+				}).
+				Build()
+			Expect(err).NotTo(HaveOccurred())
+			enabled := true
+			body, err := UpdatePatch(cluster, Spec{
+				CustomProperties: map[string]string{"env": "staging"},
+				RemoveProperties: []string{"team"},
+				DeleteProtection: &enabled,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			var document struct {
+				Properties map[string]string `json:"properties"`
+			}
+			Expect(json.Unmarshal(body, &document)).To(Succeed())
+			Expect(document.Properties).To(Equal(map[string]string{
+				properties.CreatorARN:       "arn",
+				properties.DeleteProtection: "true",
+				"env":                       "staging",
+			}))
+		})
+	})
+})
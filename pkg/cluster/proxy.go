@@ -0,0 +1,150 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+)
+
+// Proxy is the cluster-wide proxy configuration of a cluster.
+type Proxy struct {
+	HTTPProxy  string `json:"http_proxy,omitempty"`
+	HTTPSProxy string `json:"https_proxy,omitempty"`
+	NoProxy    string `json:"no_proxy,omitempty"`
+}
+
+// Domains in the no-proxy list may start with a dot to match all the sub-domains:
+var noProxyDomainRE = regexp.MustCompile(`^\.?([a-z0-9]([-a-z0-9]*[a-z0-9])?\.)*[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// GetProxy returns the cluster-wide proxy configuration of the cluster, or nil if the cluster
+// doesn't use a proxy.
+func GetProxy(connection *sdk.Connection, clusterID string) (*Proxy, error) {
+	document := struct {
+		Proxy *Proxy `json:"proxy"`
+	}{}
+	err := getClusterAttributes(connection, clusterID, &document)
+	if err != nil {
+		return nil, err
+	}
+	return document.Proxy, nil
+}
+
+// ValidateProxy checks the cluster-wide proxy options locally, so that errors are reported before
+// anything is sent to OCM. The HTTP proxy must be an 'http' URL, the HTTPS proxy can be either an
+// 'http' or 'https' URL, and the no-proxy list only makes sense when one of them is given.
+func ValidateProxy(httpProxy string, httpsProxy string, noProxy string) error {
+	err := validateProxyURL(httpProxy, "http")
+	if err != nil {
+		return err
+	}
+	err = validateProxyURL(httpsProxy, "http", "https")
+	if err != nil {
+		return err
+	}
+	err = validateNoProxy(noProxy)
+	if err != nil {
+		return err
+	}
+	if noProxy != "" && httpProxy == "" && httpsProxy == "" {
+		return fmt.Errorf("Expected at least one of '--http-proxy' or '--https-proxy' when using '--no-proxy'")
+	}
+	return nil
+}
+
+func validateProxyURL(value string, schemes ...string) error {
+	if value == "" {
+		return nil
+	}
+	parsed, err := url.ParseRequestURI(value)
+	if err != nil {
+		return fmt.Errorf("Invalid proxy URL '%s': %v", value, err)
+	}
+	for _, scheme := range schemes {
+		if parsed.Scheme == scheme {
+			if parsed.Hostname() == "" {
+				return fmt.Errorf("Invalid proxy URL '%s': a host is required", value)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("Invalid proxy URL '%s': expected scheme to be one of %s",
+		value, strings.Join(schemes, ", "))
+}
+
+// validateNoProxy checks that the value is a comma separated list of domains, IP addresses or
+// CIDR blocks, or the '*' wildcard.
+func validateNoProxy(value string) error {
+	if value == "" || value == "*" {
+		return nil
+	}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if net.ParseIP(entry) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(entry); err == nil {
+			continue
+		}
+		if noProxyDomainRE.MatchString(strings.ToLower(entry)) {
+			continue
+		}
+		return fmt.Errorf("Invalid no-proxy entry '%s': expected a domain, IP address or CIDR block", entry)
+	}
+	return nil
+}
+
+// ReadAdditionalTrustBundle reads the PEM encoded CA bundle from the given file and checks that it
+// only contains valid certificates.
+func ReadAdditionalTrustBundle(path string) (string, error) {
+	// #nosec G304
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("Failed to read additional trust bundle file '%s': %v", path, err)
+	}
+	rest := data
+	count := 0
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return "", fmt.Errorf("Additional trust bundle file '%s' contains a '%s' block, "+
+				"only certificates are allowed", path, block.Type)
+		}
+		_, err = x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return "", fmt.Errorf("Additional trust bundle file '%s' contains an invalid certificate: %v",
+				path, err)
+		}
+		count++
+	}
+	if count == 0 || strings.TrimSpace(string(rest)) != "" {
+		return "", fmt.Errorf("Additional trust bundle file '%s' isn't a valid PEM encoded CA bundle", path)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,64 @@
+package cluster_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/cluster"
+)
+
+var _ = Describe("Proxy", func() {
+	Context("ValidateProxy", func() {
+		It("accepts an empty configuration", func() {
+			Expect(ValidateProxy("", "", "")).To(Succeed())
+		})
+
+		It("accepts valid proxy URLs and no-proxy entries", func() {
+			err := ValidateProxy(
+				"http://proxy.example.com:3128",
+				"https://proxy.example.com:3129",
+				".example.com,10.0.0.0/16,192.168.1.1",
+			)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("rejects an HTTP proxy with the https scheme", func() {
+			Expect(ValidateProxy("https://proxy.example.com", "", "")).NotTo(Succeed())
+		})
+
+		It("rejects a proxy URL without a host", func() {
+			Expect(ValidateProxy("", "http://", "")).NotTo(Succeed())
+		})
+
+		It("rejects invalid no-proxy entries", func() {
+			Expect(ValidateProxy("http://proxy.example.com", "", "example.com,not a domain")).NotTo(Succeed())
+		})
+
+		It("rejects a no-proxy list without a proxy", func() {
+			Expect(ValidateProxy("", "", ".example.com")).NotTo(Succeed())
+		})
+	})
+
+	Context("UpdatePatch", func() {
+		It("sends an empty additional trust bundle to remove it", func() {
+			noBundle := ""
+			body, err := UpdatePatch(build(newCluster()), Spec{
+				AdditionalTrustBundle: &noBundle,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			var document map[string]interface{}
+			Expect(json.Unmarshal(body, &document)).To(Succeed())
+			Expect(document).To(HaveKeyWithValue("additional_trust_bundle", ""))
+		})
+
+		It("leaves the additional trust bundle out when it isn't changed", func() {
+			body, err := UpdatePatch(build(newCluster()), Spec{})
+			Expect(err).NotTo(HaveOccurred())
+			var document map[string]interface{}
+			Expect(json.Unmarshal(body, &document)).To(Succeed())
+			Expect(document).NotTo(HaveKey("additional_trust_bundle"))
+		})
+	})
+})
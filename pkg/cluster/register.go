@@ -0,0 +1,87 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to register clusters that weren't created with rosa, so
+// that they can be managed with the rest of the commands.
+
+package cluster
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/aws"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/info"
+	"github.com/openshift/moactl/pkg/ocm/properties"
+)
+
+// ValidateRegistration checks that the cluster can be registered by the given AWS user: it must be
+// an AWS cluster in the account of the user, and it must not be managed by rosa already.
+func ValidateRegistration(cluster *cmv1.Cluster, creator *aws.Creator) error {
+	if cluster.CloudProvider().ID() != "aws" {
+		return rerrors.ValidationErrorf("Cluster '%s' runs in '%s', only AWS clusters can be "+
+			"registered", cluster.ID(), cluster.CloudProvider().ID())
+	}
+	accountID := cluster.AWS().AccountID()
+	if accountID == "" {
+		return rerrors.ValidationErrorf("Cluster '%s' doesn't run in an AWS account of the "+
+			"customer, only Customer Cloud Subscription clusters can be registered", cluster.ID())
+	}
+	if accountID != creator.AccountID {
+		return rerrors.ValidationErrorf("Cluster '%s' runs in AWS account '%s', but the current "+
+			"AWS credentials belong to account '%s'", cluster.ID(), accountID, creator.AccountID)
+	}
+	if current := cluster.Properties()[properties.CreatorARN]; current != "" {
+		return rerrors.ConflictErrorf("Cluster '%s' is already managed by rosa for '%s'",
+			cluster.ID(), current)
+	}
+	return nil
+}
+
+// RegistrationProperties returns the properties of the cluster with the ones that associate it to
+// the given AWS user. The rest of the properties are preserved, as the update replaces all of them.
+func RegistrationProperties(cluster *cmv1.Cluster, creatorARN string, now time.Time) map[string]string {
+	result := make(map[string]string, len(cluster.Properties())+3)
+	for key, value := range cluster.Properties() {
+		result[key] = value
+	}
+	result[properties.CreatorARN] = creatorARN
+	result[properties.CLIVersion] = info.Version
+	result[properties.Registered] = now.UTC().Format(time.RFC3339)
+	return result
+}
+
+// RegisterCluster associates the cluster to the given AWS user, so that the rest of the commands
+// find it.
+func RegisterCluster(connection *sdk.Connection, cluster *cmv1.Cluster, creatorARN string) error {
+	spec, err := cmv1.NewCluster().
+		Properties(RegistrationProperties(cluster, creatorARN, time.Now())).
+		Build()
+	if err != nil {
+		return err
+	}
+	var buffer bytes.Buffer
+	err = cmv1.MarshalCluster(spec, &buffer)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal cluster: %v", err)
+	}
+	return updateCluster(connection, cluster.ID(), buffer.Bytes())
+}
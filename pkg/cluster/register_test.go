@@ -0,0 +1,62 @@
+package cluster_test
+
+import (
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/aws"
+	. "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/properties"
+)
+
+var _ = Describe("Register", func() {
+	creator := &aws.Creator{
+		ARN:       "arn:aws:iam::123456789012:user/alice",
+		AccountID: "123456789012",
+	}
+	inAccount := func(provider string, accountID string) *cmv1.ClusterBuilder {
+		return newCluster().
+			CloudProvider(cmv1.NewCloudProvider().ID(provider)).
+			AWS(cmv1.NewAWS().AccountID(accountID))
+	}
+
+	DescribeTable("ValidateRegistration",
+		func(cluster *cmv1.ClusterBuilder, code int, message string) {
+			err := ValidateRegistration(build(cluster), creator)
+			Expect(rerrors.ExitCode(err)).To(Equal(code))
+			if message != "" {
+				Expect(err).To(MatchError(ContainSubstring(message)))
+			}
+		},
+		Entry("Cluster in the account of the user", inAccount("aws", "123456789012"), 0, ""),
+		Entry("Cluster in other cloud", inAccount("gcp", ""), rerrors.ExitCodeValidation,
+			"only AWS clusters"),
+		Entry("Cluster in other account", inAccount("aws", "210987654321"),
+			rerrors.ExitCodeValidation, "account '210987654321'"),
+		Entry("Cluster already managed by rosa",
+			inAccount("aws", "123456789012").Properties(map[string]string{
+				properties.CreatorARN: "arn:aws:iam::123456789012:user/bob",
+			}),
+			rerrors.ExitCodeConflict, "user/bob"),
+	)
+
+	Context("RegistrationProperties", func() {
+		It("adds the association and keeps the other properties", func() {
+			cluster := build(inAccount("aws", "123456789012").Properties(map[string]string{
+				"owner": "team-a",
+			}))
+			now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+			result := RegistrationProperties(cluster, creator.ARN, now)
+			Expect(result).To(HaveKeyWithValue("owner", "team-a"))
+			Expect(result).To(HaveKeyWithValue(properties.CreatorARN, creator.ARN))
+			Expect(result).To(HaveKeyWithValue(properties.Registered, "2021-03-01T12:00:00Z"))
+			Expect(result).To(HaveKey(properties.CLIVersion))
+		})
+	})
+})
@@ -0,0 +1,172 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to change the instance type of a machine pool. OCM doesn't
+// allow changing the instance type of existing machine pools, so the machine pool is replaced by a
+// new one with the same configuration and the new instance type.
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// ReplacementMachinePoolID returns the identifier of the machine pool that replaces the given one
+// with nodes of the given instance type, for example 'mp1-m5-2xlarge'.
+func ReplacementMachinePoolID(machinePoolID string, instanceType string) string {
+	return fmt.Sprintf("%s-%s", machinePoolID, strings.ReplaceAll(instanceType, ".", "-"))
+}
+
+// NewReplacementMachinePool returns a machine pool with the same replicas, availability zones,
+// labels and taints as the given one, but with nodes of the given instance type. When the machine
+// pool is nil the configuration of the default machine pool of the cluster is used instead.
+func NewReplacementMachinePool(cluster *cmv1.Cluster, machinePool *cmv1.MachinePool,
+	instanceType string) (*cmv1.MachinePool, error) {
+	if machinePool == nil {
+		currentType := cluster.Nodes().ComputeMachineType().ID()
+		if currentType == instanceType {
+			return nil, rerrors.ValidationErrorf("The default machine pool already uses instance "+
+				"type '%s'", instanceType)
+		}
+		builder := cmv1.NewMachinePool().
+			ID(ReplacementMachinePoolID(DefaultMachinePool, instanceType)).
+			InstanceType(instanceType).
+			Labels(cluster.Nodes().ComputeLabels())
+		if autoscaling, ok := cluster.Nodes().GetAutoscaleCompute(); ok {
+			builder = builder.Autoscaling(cmv1.NewMachinePoolAutoscaling().
+				MinReplicas(autoscaling.MinReplicas()).
+				MaxReplicas(autoscaling.MaxReplicas()))
+		} else {
+			builder = builder.Replicas(cluster.Nodes().Compute())
+		}
+		return builder.Build()
+	}
+
+	if machinePool.InstanceType() == instanceType {
+		return nil, rerrors.ValidationErrorf("Machine pool '%s' already uses instance type '%s'",
+			machinePool.ID(), instanceType)
+	}
+	builder := cmv1.NewMachinePool().
+		ID(ReplacementMachinePoolID(machinePool.ID(), instanceType)).
+		InstanceType(instanceType).
+		AvailabilityZones(machinePool.AvailabilityZones()...).
+		Labels(machinePool.Labels())
+	if autoscaling, ok := machinePool.GetAutoscaling(); ok {
+		builder = builder.Autoscaling(cmv1.NewMachinePoolAutoscaling().
+			MinReplicas(autoscaling.MinReplicas()).
+			MaxReplicas(autoscaling.MaxReplicas()))
+	} else {
+		builder = builder.Replicas(machinePool.Replicas())
+	}
+	taints := make([]*cmv1.TaintBuilder, len(machinePool.Taints()))
+	for i, taint := range machinePool.Taints() {
+		taints[i] = cmv1.NewTaint().
+			Key(taint.Key()).
+			Value(taint.Value()).
+			Effect(taint.Effect())
+	}
+	if len(taints) > 0 {
+		builder = builder.Taints(taints...)
+	}
+	return builder.Build()
+}
+
+// MinMachinePoolReplicas returns the number of nodes that the machine pool has at least, which is
+// the minimum number of replicas when it is autoscaled.
+func MinMachinePoolReplicas(machinePool *cmv1.MachinePool) int {
+	if autoscaling, ok := machinePool.GetAutoscaling(); ok {
+		return autoscaling.MinReplicas()
+	}
+	return machinePool.Replicas()
+}
+
+// MinComputeNodes returns the minimum number of compute nodes of the default machine pool of the
+// cluster.
+func MinComputeNodes(cluster *cmv1.Cluster) int {
+	if cluster.MultiAZ() {
+		return MinMultiAZComputeNodes
+	}
+	return MinSingleAZComputeNodes
+}
+
+// GetCurrentComputeNodes returns the number of compute nodes that are running in the cluster,
+// according to the status reported by OCM. The SDK doesn't support this attribute yet.
+func GetCurrentComputeNodes(ctx context.Context, connection *sdk.Connection,
+	clusterID string) (int, error) {
+	response, err := connection.Get().
+		Path(fmt.Sprintf("%s/%s/status", clustersPath, clusterID)).
+		SendContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	err = responseErr(response)
+	if err != nil {
+		return 0, err
+	}
+	document := struct {
+		CurrentCompute int `json:"current_compute"`
+	}{}
+	err = json.Unmarshal(response.Bytes(), &document)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to parse status of cluster '%s': %v", clusterID, err)
+	}
+	return document.CurrentCompute, nil
+}
+
+// WaitForComputeNodes polls the status of the cluster until the number of running compute nodes
+// reaches the given number: at least that number when nodes are being added, and at most that
+// number when they are being removed. The progress function, if not nil, is called with the
+// current number of nodes after each poll.
+func WaitForComputeNodes(ctx context.Context, connection *sdk.Connection, clusterID string,
+	from int, to int, progress func(current int), interval time.Duration,
+	timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		current, err := GetCurrentComputeNodes(ctx, connection, clusterID)
+		if ctx.Err() != nil {
+			return fmt.Errorf("Stopped waiting for cluster '%s' to have %d compute nodes: %w",
+				clusterID, to, ctx.Err())
+		}
+		if err != nil {
+			return err
+		}
+		if progress != nil {
+			progress(current)
+		}
+		if (to >= from && current >= to) || (to < from && current <= to) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return rerrors.TimeoutErrorf("Timed out waiting for cluster '%s' to have %d compute "+
+				"nodes, it has %d", clusterID, to, current)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Stopped waiting for cluster '%s' to have %d compute nodes: %w",
+				clusterID, to, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
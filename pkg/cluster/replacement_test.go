@@ -0,0 +1,65 @@
+package cluster_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/openshift/moactl/pkg/cluster"
+)
+
+var _ = Describe("Replacement", func() {
+	It("names the replacement machine pool after the instance type", func() {
+		Expect(ReplacementMachinePoolID("mp1", "m5.2xlarge")).To(Equal("mp1-m5-2xlarge"))
+	})
+
+	Context("NewReplacementMachinePool", func() {
+		It("copies the default machine pool", func() {
+			cluster, err := cmv1.NewCluster().
+				MultiAZ(true).
+				Nodes(cmv1.NewClusterNodes().
+					Compute(6).
+					ComputeLabels(map[string]string{"role": "worker"}).
+					ComputeMachineType(cmv1.NewMachineType().ID("m5.xlarge"))).
+				Build()
+			Expect(err).NotTo(HaveOccurred())
+
+			machinePool, err := NewReplacementMachinePool(cluster, nil, "m5.2xlarge")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(machinePool.ID()).To(Equal("default-m5-2xlarge"))
+			Expect(machinePool.InstanceType()).To(Equal("m5.2xlarge"))
+			Expect(machinePool.Replicas()).To(Equal(6))
+			Expect(machinePool.Labels()).To(Equal(map[string]string{"role": "worker"}))
+			Expect(MinComputeNodes(cluster)).To(Equal(3))
+		})
+
+		It("copies an autoscaled machine pool with taints", func() {
+			old, err := cmv1.NewMachinePool().
+				ID("mp1").
+				InstanceType("m5.xlarge").
+				AvailabilityZones("us-east-1a").
+				Autoscaling(cmv1.NewMachinePoolAutoscaling().MinReplicas(2).MaxReplicas(5)).
+				Taints(cmv1.NewTaint().Key("dedicated").Value("gpu").Effect("NoSchedule")).
+				Build()
+			Expect(err).NotTo(HaveOccurred())
+
+			machinePool, err := NewReplacementMachinePool(nil, old, "r5.xlarge")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(machinePool.ID()).To(Equal("mp1-r5-xlarge"))
+			Expect(machinePool.AvailabilityZones()).To(Equal([]string{"us-east-1a"}))
+			Expect(machinePool.Autoscaling().MaxReplicas()).To(Equal(5))
+			Expect(MinMachinePoolReplicas(machinePool)).To(Equal(2))
+			Expect(machinePool.Taints()).To(HaveLen(1))
+			Expect(machinePool.Taints()[0].Effect()).To(Equal("NoSchedule"))
+		})
+
+		It("rejects the current instance type", func() {
+			old, err := cmv1.NewMachinePool().ID("mp1").InstanceType("m5.xlarge").Replicas(2).Build()
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = NewReplacementMachinePool(nil, old, "m5.xlarge")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,160 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the state of the creation of a cluster. It is stored locally, in the
+// configuration directory of the current profile, so that a creation that was interrupted or
+// failed can be resumed with 'rosa create cluster --resume' from the last completed step.
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/openshift/moactl/pkg/config"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// Steps of the creation of a cluster, in the order that they run:
+const (
+	CreateStepPreflight = "preflight"
+	CreateStepTerms     = "terms"
+	CreateStepCluster   = "cluster"
+)
+
+// CreateState is the progress of the creation of a cluster. The token used to resume it is the
+// name of the cluster, as it is unique for the user.
+type CreateState struct {
+	Token     string    `json:"token"`
+	Spec      Spec      `json:"spec"`
+	Completed []string  `json:"completed"`
+	ClusterID string    `json:"cluster_id,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewCreateState returns the state of the creation of a cluster with the given configuration, with
+// no completed steps.
+func NewCreateState(spec Spec) *CreateState {
+	return &CreateState{
+		Token:     spec.Name,
+		Spec:      spec,
+		Completed: []string{},
+	}
+}
+
+// Done returns true if the given step has already been completed.
+func (s *CreateState) Done(step string) bool {
+	for _, completed := range s.Completed {
+		if completed == step {
+			return true
+		}
+	}
+	return false
+}
+
+// Complete records that the given step has been completed.
+func (s *CreateState) Complete(step string) {
+	if !s.Done(step) {
+		s.Completed = append(s.Completed, step)
+	}
+}
+
+// ResumeCommand returns the command that resumes the creation of the cluster.
+func (s *CreateState) ResumeCommand() string {
+	return fmt.Sprintf("rosa create cluster --resume %s", s.Token)
+}
+
+// SaveCreateState stores the state of the creation of a cluster, replacing the previous one.
+func SaveCreateState(state *CreateState) error {
+	file, err := createStateLocation(state.Token)
+	if err != nil {
+		return err
+	}
+	state.UpdatedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(file), 0700)
+	if err != nil {
+		return fmt.Errorf("Failed to create resume directory: %v", err)
+	}
+	err = ioutil.WriteFile(file, data, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to write resume file '%s': %v", file, err)
+	}
+	return nil
+}
+
+// LoadCreateState returns the state of the creation of a cluster stored with the given token.
+func LoadCreateState(token string) (*CreateState, error) {
+	if !clusterNameRE.MatchString(token) {
+		return nil, rerrors.ValidationErrorf("Resume token '%s' isn't valid", token)
+	}
+	file, err := createStateLocation(token)
+	if err != nil {
+		return nil, err
+	}
+	// #nosec G304
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, rerrors.NotFoundErrorf("There is no interrupted creation of cluster '%s' "+
+			"to resume", token)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read resume file '%s': %v", file, err)
+	}
+	state := &CreateState{}
+	err = json.Unmarshal(data, state)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse resume file '%s': %v", file, err)
+	}
+	return state, nil
+}
+
+// DeleteCreateState removes the state of the creation of a cluster, if any.
+func DeleteCreateState(token string) error {
+	file, err := createStateLocation(token)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(file)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Failed to remove resume file '%s': %v", file, err)
+	}
+	return nil
+}
+
+// createStateLocation returns the file that stores the state of the creation of a cluster for the
+// current profile.
+func createStateLocation(token string) (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	profile, err := config.CurrentProfile()
+	if err != nil {
+		return "", err
+	}
+	if profile == "" {
+		profile = "default"
+	}
+	return filepath.Join(dir, "resume", profile, token+".json"), nil
+}
@@ -0,0 +1,83 @@
+package cluster_test
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+var _ = Describe("Resume", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "rosa-resume")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Setenv("ROSA_CONFIG_DIR", dir)).To(Succeed())
+		Expect(os.Setenv("ROSA_PROFILE", "")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Unsetenv("ROSA_CONFIG_DIR")).To(Succeed())
+		Expect(os.Unsetenv("ROSA_PROFILE")).To(Succeed())
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("Stores the options and the completed steps", func() {
+		_, machineCIDR, err := net.ParseCIDR("10.0.0.0/16")
+		Expect(err).NotTo(HaveOccurred())
+		private := true
+		state := NewCreateState(Spec{
+			Name:         "mycluster",
+			Region:       "us-east-1",
+			MultiAZ:      true,
+			ComputeNodes: 9,
+			MachineCIDR:  *machineCIDR,
+			Private:      &private,
+			Tags:         map[string]string{"team": "sre"},
+		})
+		state.Complete(CreateStepPreflight)
+		Expect(SaveCreateState(state)).To(Succeed())
+
+		loaded, err := LoadCreateState("mycluster")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded.Token).To(Equal("mycluster"))
+		Expect(loaded.Done(CreateStepPreflight)).To(BeTrue())
+		Expect(loaded.Done(CreateStepTerms)).To(BeFalse())
+		Expect(loaded.Spec.Region).To(Equal("us-east-1"))
+		Expect(loaded.Spec.MultiAZ).To(BeTrue())
+		Expect(loaded.Spec.ComputeNodes).To(Equal(9))
+		Expect(loaded.Spec.MachineCIDR.String()).To(Equal("10.0.0.0/16"))
+		Expect(*loaded.Spec.Private).To(BeTrue())
+		Expect(loaded.Spec.Tags).To(Equal(map[string]string{"team": "sre"}))
+		Expect(loaded.ResumeCommand()).To(Equal("rosa create cluster --resume mycluster"))
+	})
+
+	It("Records each step once", func() {
+		state := NewCreateState(Spec{Name: "mycluster"})
+		state.Complete(CreateStepPreflight)
+		state.Complete(CreateStepPreflight)
+		Expect(state.Completed).To(Equal([]string{CreateStepPreflight}))
+	})
+
+	It("Fails when there is nothing to resume", func() {
+		_, err := LoadCreateState("mycluster")
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeNotFound))
+
+		Expect(SaveCreateState(NewCreateState(Spec{Name: "mycluster"}))).To(Succeed())
+		Expect(DeleteCreateState("mycluster")).To(Succeed())
+		_, err = LoadCreateState("mycluster")
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeNotFound))
+	})
+
+	It("Rejects tokens that aren't cluster names", func() {
+		_, err := LoadCreateState("../config")
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+	})
+})
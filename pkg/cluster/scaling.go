@@ -0,0 +1,100 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to change the number of compute nodes of the default
+// machine pool of a cluster.
+
+package cluster
+
+import (
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// Minimum number of compute nodes of single and multi zone clusters:
+const (
+	MinSingleAZComputeNodes = 2
+	MinMultiAZComputeNodes  = 3
+)
+
+// ZoneCount returns the number of availability zones that the nodes of the cluster are spread
+// across.
+func ZoneCount(cluster *cmv1.Cluster) int {
+	zones := len(cluster.Nodes().AvailabilityZones())
+	if zones > 0 {
+		return zones
+	}
+	if cluster.MultiAZ() {
+		return 3
+	}
+	return 1
+}
+
+// ValidateComputeNodes checks that the default machine pool of the cluster can be scaled to the
+// given number of compute nodes: the cluster must be ready, and multizone clusters need the same
+// number of nodes in each zone.
+func ValidateComputeNodes(cluster *cmv1.Cluster, computeNodes int) error {
+	if cluster.State() != cmv1.ClusterStateReady {
+		return rerrors.ConflictErrorf("Cluster '%s' is in state '%s', the compute nodes can only be "+
+			"changed when the cluster is ready", cluster.Name(), cluster.State())
+	}
+	return ValidateComputeNodeCount(cluster, computeNodes)
+}
+
+// ValidateComputeAutoscaling checks the limits of the autoscaling of the compute nodes of the
+// default machine pool of a new cluster. Both limits must be valid numbers of compute nodes.
+func ValidateComputeAutoscaling(multiAZ bool, minReplicas int, maxReplicas int) error {
+	cluster, err := cmv1.NewCluster().MultiAZ(multiAZ).Build()
+	if err != nil {
+		return err
+	}
+	err = ValidateComputeNodeCount(cluster, minReplicas)
+	if err != nil {
+		return err
+	}
+	err = ValidateComputeNodeCount(cluster, maxReplicas)
+	if err != nil {
+		return err
+	}
+	if maxReplicas < minReplicas {
+		return rerrors.ValidationErrorf("Maximum number of compute nodes %d must be greater than "+
+			"or equal to the minimum %d", maxReplicas, minReplicas)
+	}
+	return nil
+}
+
+// ValidateComputeNodeCount checks that the number of compute nodes is valid for the default machine
+// pool of the cluster, regardless of the current state of the cluster.
+func ValidateComputeNodeCount(cluster *cmv1.Cluster, computeNodes int) error {
+	if !cluster.MultiAZ() {
+		if computeNodes < MinSingleAZComputeNodes {
+			return rerrors.ValidationErrorf("Single zone clusters need at least %d compute nodes, "+
+				"got %d", MinSingleAZComputeNodes, computeNodes)
+		}
+		return nil
+	}
+	if computeNodes < MinMultiAZComputeNodes {
+		return rerrors.ValidationErrorf("Multizone clusters need at least %d compute nodes, got %d",
+			MinMultiAZComputeNodes, computeNodes)
+	}
+	zones := ZoneCount(cluster)
+	if computeNodes%zones != 0 {
+		return rerrors.ValidationErrorf("Multizone clusters need a number of compute nodes that is a "+
+			"multiple of the %d availability zones, got %d", zones, computeNodes)
+	}
+	return nil
+}
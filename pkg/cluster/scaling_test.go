@@ -0,0 +1,73 @@
+package cluster_test
+
+import (
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+var _ = Describe("Scaling", func() {
+	DescribeTable("ZoneCount",
+		func(multiAZ bool, zones []string, count int) {
+			cluster := build(newCluster().
+				MultiAZ(multiAZ).
+				Nodes(cmv1.NewClusterNodes().AvailabilityZones(zones...)))
+			Expect(ZoneCount(cluster)).To(Equal(count))
+		},
+		Entry("Zones of the nodes", true, []string{"a", "b", "c"}, 3),
+		Entry("Multizone without zones", true, nil, 3),
+		Entry("Single zone without zones", false, nil, 1),
+	)
+
+	DescribeTable("ValidateComputeNodes",
+		func(multiAZ bool, state cmv1.ClusterState, zones []string, nodes int, code int,
+			message string) {
+			cluster := build(newCluster().
+				MultiAZ(multiAZ).
+				State(state).
+				Nodes(cmv1.NewClusterNodes().AvailabilityZones(zones...)))
+			err := ValidateComputeNodes(cluster, nodes)
+			Expect(rerrors.ExitCode(err)).To(Equal(code))
+			if message != "" {
+				Expect(err).To(MatchError(ContainSubstring(message)))
+			}
+		},
+		Entry("Minimum", false, cmv1.ClusterStateReady, nil, 2, 0, ""),
+		Entry("Single zone", false, cmv1.ClusterStateReady, nil, 5, 0, ""),
+		Entry("Multizone", true, cmv1.ClusterStateReady, nil, 6, 0, ""),
+		Entry("Too few nodes", false, cmv1.ClusterStateReady, nil, 1, rerrors.ExitCodeValidation,
+			"at least 2"),
+		Entry("Multizone count that isn't a multiple of the zones", true, cmv1.ClusterStateReady,
+			[]string{"a", "b", "c"}, 4, rerrors.ExitCodeValidation,
+			"multiple of the 3 availability zones"),
+		Entry("Cluster that isn't ready", false, cmv1.ClusterStateInstalling, nil, 3,
+			rerrors.ExitCodeConflict, ""),
+	)
+
+	Context("ValidateComputeAutoscaling", func() {
+		It("accepts valid limits", func() {
+			Expect(ValidateComputeAutoscaling(false, 2, 5)).To(Succeed())
+			Expect(ValidateComputeAutoscaling(true, 3, 9)).To(Succeed())
+		})
+
+		It("rejects limits below the minimum", func() {
+			err := ValidateComputeAutoscaling(false, 1, 5)
+			Expect(err).To(MatchError(ContainSubstring("at least 2 compute nodes")))
+			Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+		})
+
+		It("rejects multizone limits that aren't multiples of the zones", func() {
+			Expect(ValidateComputeAutoscaling(true, 3, 7)).To(MatchError(ContainSubstring("multiple")))
+		})
+
+		It("rejects a maximum lower than the minimum", func() {
+			err := ValidateComputeAutoscaling(false, 4, 3)
+			Expect(err).To(MatchError(ContainSubstring("greater than or equal to the minimum 4")))
+		})
+	})
+})
@@ -0,0 +1,109 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to select groups of clusters, either using the labels and
+// properties of the clusters or a file containing the list of clusters.
+
+package cluster
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// ParseSelector parses a list of 'key=value' pairs into a selector. Clusters match the selector when
+// they have all the given labels or properties.
+func ParseSelector(values []string) (map[string]string, error) {
+	selector := make(map[string]string, len(values))
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || key == "" {
+			return nil, fmt.Errorf("Expected selector '%s' to be in 'key=value' format", value)
+		}
+		selector[key] = strings.TrimSpace(parts[1])
+	}
+	return selector, nil
+}
+
+// MatchesSelector checks if all the keys of the selector are in the given labels or in the
+// properties of the cluster, with the same values. Labels take precedence over properties.
+func MatchesSelector(cluster *cmv1.Cluster, labels map[string]string, selector map[string]string) bool {
+	for key, value := range selector {
+		actual, ok := labels[key]
+		if !ok {
+			actual, ok = cluster.Properties()[key]
+		}
+		if !ok || actual != value {
+			return false
+		}
+	}
+	return true
+}
+
+// GetLabels returns the labels of the external configuration of the cluster.
+func GetLabels(client *cmv1.ClustersClient, clusterID string) (map[string]string, error) {
+	response, err := client.Cluster(clusterID).
+		ExternalConfiguration().
+		Labels().
+		List().
+		Page(1).
+		Size(-1).
+		Send()
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	labels := map[string]string{}
+	response.Items().Each(func(label *cmv1.Label) bool {
+		labels[label.Key()] = label.Value()
+		return true
+	})
+	return labels, nil
+}
+
+// ReadClustersFile reads the names or identifiers of clusters from the given file, which contains
+// one cluster per line. Empty lines and lines starting with '#' are ignored.
+func ReadClustersFile(path string) ([]string, error) {
+	// #nosec G304
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open clusters file '%s': %v", path, err)
+	}
+	defer file.Close()
+
+	clusterKeys := []string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !IsValidClusterKey(line) {
+			return nil, fmt.Errorf("Cluster name, identifier or external identifier '%s' in "+
+				"clusters file '%s' isn't valid", line, path)
+		}
+		clusterKeys = append(clusterKeys, line)
+	}
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read clusters file '%s': %v", path, err)
+	}
+	return clusterKeys, nil
+}
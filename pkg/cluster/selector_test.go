@@ -0,0 +1,60 @@
+package cluster_test
+
+import (
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/cluster"
+)
+
+var _ = Describe("Selector", func() {
+	Context("ParseSelector", func() {
+		It("parses key=value pairs", func() {
+			selector, err := ParseSelector([]string{"env=staging", "team = sre"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(selector).To(Equal(map[string]string{
+				"env":  "staging",
+				"team": "sre",
+			}))
+		})
+
+		It("rejects values without a key", func() {
+			_, err := ParseSelector([]string{"=staging"})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("rejects values without a value", func() {
+			_, err := ParseSelector([]string{"env"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("MatchesSelector", func() {
+		var cluster *cmv1.Cluster
+
+		BeforeEach(func() {
+			var err error
+			cluster, err = cmv1.NewCluster().
+				Properties(map[string]string{"owner": "alice"}).
+				Build()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("matches labels and properties", func() {
+			labels := map[string]string{"env": "staging"}
+			selector := map[string]string{"env": "staging", "owner": "alice"}
+			Expect(MatchesSelector(cluster, labels, selector)).To(BeTrue())
+		})
+
+		It("doesn't match different values", func() {
+			labels := map[string]string{"env": "production"}
+			Expect(MatchesSelector(cluster, labels, map[string]string{"env": "staging"})).To(BeFalse())
+		})
+
+		It("doesn't match missing keys", func() {
+			Expect(MatchesSelector(cluster, nil, map[string]string{"env": "staging"})).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,103 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to parse the generic 'path=value' settings that change
+// attributes of clusters that don't have a dedicated flag yet.
+
+package cluster
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// settingKind is the type of the value of a setting.
+type settingKind int
+
+const (
+	stringSetting settingKind = iota
+	boolSetting
+)
+
+// settings contains the paths of the attributes of the cluster that can be changed with settings,
+// and the type of their values. Only attributes that are safe to change in a running cluster are
+// included. Attributes with a dedicated flag aren't, as the flag also validates the change.
+var settings = map[string]settingKind{
+	"disable_user_workload_monitoring": boolSetting,
+	"display_name":                     stringSetting,
+}
+
+// SettingPaths returns the sorted paths of the attributes that can be changed with settings.
+func SettingPaths() []string {
+	paths := make([]string, 0, len(settings))
+	for path := range settings {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// ParseSettings parses a list of 'path=value' settings, where the path is a dot separated list of
+// the names of the attributes in the JSON document of the cluster. It returns the attributes
+// nested as in that document.
+func ParseSettings(values []string) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+	seen := map[string]bool{}
+	for _, value := range values {
+		tokens := strings.SplitN(value, "=", 2)
+		if len(tokens) != 2 {
+			return nil, rerrors.ValidationErrorf("Expected path=value format for setting '%s'", value)
+		}
+		path := strings.TrimSpace(tokens[0])
+		kind, ok := settings[path]
+		if !ok {
+			return nil, rerrors.ValidationErrorf("Setting '%s' isn't supported, expected one of: %s",
+				path, strings.Join(SettingPaths(), ", "))
+		}
+		if seen[path] {
+			return nil, rerrors.ValidationErrorf("Duplicated setting '%s'", path)
+		}
+		seen[path] = true
+		parsed, err := parseSetting(kind, tokens[1])
+		if err != nil {
+			return nil, rerrors.ValidationErrorf("Invalid value for setting '%s': %v", path, err)
+		}
+
+		// Create the intermediate objects of the path:
+		names := strings.Split(path, ".")
+		object := result
+		for _, name := range names[:len(names)-1] {
+			nested, ok := object[name].(map[string]interface{})
+			if !ok {
+				nested = map[string]interface{}{}
+				object[name] = nested
+			}
+			object = nested
+		}
+		object[names[len(names)-1]] = parsed
+	}
+	return result, nil
+}
+
+func parseSetting(kind settingKind, value string) (interface{}, error) {
+	if kind == boolSetting {
+		return strconv.ParseBool(value)
+	}
+	return value, nil
+}
@@ -0,0 +1,76 @@
+package cluster_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+var _ = Describe("Settings", func() {
+	Context("ParseSettings", func() {
+		It("parses a list of settings", func() {
+			settings, err := ParseSettings([]string{
+				"display_name=My cluster",
+				"disable_user_workload_monitoring=true",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(settings).To(Equal(map[string]interface{}{
+				"display_name":                     "My cluster",
+				"disable_user_workload_monitoring": true,
+			}))
+		})
+
+		It("keeps the equal signs of the value", func() {
+			settings, err := ParseSettings([]string{"display_name=a=b"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(settings).To(HaveKeyWithValue("display_name", "a=b"))
+		})
+
+		It("rejects settings without value", func() {
+			_, err := ParseSettings([]string{"display_name"})
+			Expect(err).To(MatchError(ContainSubstring("path=value")))
+			Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+		})
+
+		It("rejects paths that aren't allowed", func() {
+			_, err := ParseSettings([]string{"aws.account_id=123"})
+			Expect(err).To(MatchError(ContainSubstring("display_name")))
+			Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+		})
+
+		It("rejects values of the wrong type", func() {
+			_, err := ParseSettings([]string{"disable_user_workload_monitoring=maybe"})
+			Expect(err).To(MatchError(ContainSubstring("disable_user_workload_monitoring")))
+		})
+
+		It("rejects duplicated paths", func() {
+			_, err := ParseSettings([]string{"display_name=a", "display_name=b"})
+			Expect(err).To(MatchError(ContainSubstring("Duplicated")))
+		})
+	})
+
+	Context("UpdatePatch", func() {
+		It("merges the settings with the rest of the configuration", func() {
+			cluster, err := cmv1.NewCluster().ID("123").Build()
+			Expect(err).NotTo(HaveOccurred())
+			settings, err := ParseSettings([]string{"display_name=mine"})
+			Expect(err).NotTo(HaveOccurred())
+			admins := true
+			body, err := UpdatePatch(cluster, Spec{
+				ClusterAdmins: &admins,
+				Settings:      settings,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			var document map[string]interface{}
+			Expect(json.Unmarshal(body, &document)).To(Succeed())
+			Expect(document).To(HaveKeyWithValue("display_name", "mine"))
+			Expect(document).To(HaveKeyWithValue("cluster_admin_enabled", true))
+		})
+	})
+})
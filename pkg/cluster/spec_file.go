@@ -0,0 +1,329 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the types and functions used to read and write cluster spec files, which
+// contain the options of the 'create cluster' command in YAML or JSON format.
+
+package cluster
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"gopkg.in/yaml.v2"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/ocm/machinepools"
+)
+
+// SpecFile is the content of a cluster spec file. Each field corresponds to one of the command line
+// flags of the 'create cluster' command. Fields that aren't set keep the default value of the flag.
+type SpecFile struct {
+	// Basic options
+	Name         string `yaml:"name,omitempty"`
+	Region       string `yaml:"region,omitempty"`
+	Version      string `yaml:"version,omitempty"`
+	ChannelGroup string `yaml:"channel_group,omitempty"`
+	MultiAZ      *bool  `yaml:"multi_az,omitempty"`
+
+	// Scaling options
+	ComputeMachineType string `yaml:"compute_machine_type,omitempty"`
+	ComputeNodes       *int   `yaml:"compute_nodes,omitempty"`
+
+	// Labels of the default machine pool
+	DefaultMPLabels map[string]string `yaml:"default_mp_labels,omitempty"`
+
+	// Networking options
+	MachineCIDR string   `yaml:"machine_cidr,omitempty"`
+	ServiceCIDR string   `yaml:"service_cidr,omitempty"`
+	PodCIDR     string   `yaml:"pod_cidr,omitempty"`
+	HostPrefix  *int     `yaml:"host_prefix,omitempty"`
+	Private     *bool    `yaml:"private,omitempty"`
+	SubnetIDs   []string `yaml:"subnet_ids,omitempty"`
+
+	// Cluster-wide proxy options
+	HTTPProxy                 string `yaml:"http_proxy,omitempty"`
+	HTTPSProxy                string `yaml:"https_proxy,omitempty"`
+	NoProxy                   string `yaml:"no_proxy,omitempty"`
+	AdditionalTrustBundleFile string `yaml:"additional_trust_bundle_file,omitempty"`
+
+	// Disable SCP checks in the installer
+	DisableSCPChecks *bool `yaml:"disable_scp_checks,omitempty"`
+
+	// Custom AWS tags
+	Tags map[string]string `yaml:"tags,omitempty"`
+
+	// Encryption options
+	EnableCustomerManagedKey *bool  `yaml:"enable_customer_managed_key,omitempty"`
+	KMSKeyARN                string `yaml:"kms_key_arn,omitempty"`
+	EtcdEncryption           *bool  `yaml:"etcd_encryption,omitempty"`
+	FIPS                     *bool  `yaml:"fips,omitempty"`
+}
+
+// ReadSpecFile reads and validates the cluster spec file with the given path. The file can be in
+// YAML or JSON format, and unknown fields are rejected.
+func ReadSpecFile(path string) (*SpecFile, error) {
+	// #nosec G304
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read cluster spec file '%s': %v", path, err)
+	}
+	spec, err := ParseSpecFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid cluster spec file '%s': %v", path, err)
+	}
+	return spec, nil
+}
+
+// ParseSpecFile parses and validates the content of a cluster spec file.
+func ParseSpecFile(data []byte) (*SpecFile, error) {
+	spec := &SpecFile{}
+	err := yaml.UnmarshalStrict(data, spec)
+	if err != nil {
+		return nil, err
+	}
+	err = spec.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// Validate checks the values of the spec file, so that errors are reported before they are applied
+// to the command line flags.
+func (s *SpecFile) Validate() error {
+	if s.Name != "" && !IsValidClusterName(s.Name) {
+		return fmt.Errorf("Cluster name '%s' must consist of no more than 15 lowercase "+
+			"alphanumeric characters or '-', start with a letter, and end with an alphanumeric "+
+			"character", s.Name)
+	}
+	if s.ComputeNodes != nil && *s.ComputeNodes < 1 {
+		return fmt.Errorf("Expected 'compute_nodes' to be a positive number, got %d", *s.ComputeNodes)
+	}
+	for key, value := range s.DefaultMPLabels {
+		err := machinepools.ValidateLabelKey(key)
+		if err == nil {
+			err = machinepools.ValidateLabelValue(value)
+		}
+		if err != nil {
+			return fmt.Errorf("Invalid label in 'default_mp_labels': %v", err)
+		}
+	}
+	hostPrefix := 0
+	if s.HostPrefix != nil {
+		hostPrefix = *s.HostPrefix
+		if hostPrefix == 0 {
+			return fmt.Errorf("Expected 'host_prefix' to be between 1 and 32, got 0")
+		}
+	}
+	err := ValidateCIDRs(s.MachineCIDR, s.ServiceCIDR, s.PodCIDR, hostPrefix)
+	if err != nil {
+		return err
+	}
+	for _, subnetID := range s.SubnetIDs {
+		if !strings.HasPrefix(subnetID, "subnet-") {
+			return fmt.Errorf("Expected 'subnet_ids' to contain subnet IDs, got '%s'", subnetID)
+		}
+	}
+	for key, value := range s.Tags {
+		err := ValidateTag(key, value)
+		if err != nil {
+			return fmt.Errorf("Invalid tag in 'tags': %v", err)
+		}
+	}
+	if s.KMSKeyARN != "" {
+		// Without a region in the spec file the region of the cluster comes from the command line
+		// or the AWS configuration, so only the format of the ARN can be checked:
+		region := s.Region
+		if parsed, err := arn.Parse(s.KMSKeyARN); err == nil && region == "" {
+			region = parsed.Region
+		}
+		err = aws.ValidateKMSKeyARN(s.KMSKeyARN, region)
+		if err != nil {
+			return fmt.Errorf("Invalid 'kms_key_arn': %v", err)
+		}
+	}
+	return ValidateProxy(s.HTTPProxy, s.HTTPSProxy, s.NoProxy)
+}
+
+// Lint checks the combinations of values that the 'create cluster' command would reject when the
+// spec file is used without additional command line flags. Checks that need OCM or AWS, like the
+// existence of the KMS key or the available versions, aren't performed.
+func (s *SpecFile) Lint() error {
+	err := s.Validate()
+	if err != nil {
+		return err
+	}
+	if s.KMSKeyARN != "" && (s.EnableCustomerManagedKey == nil || !*s.EnableCustomerManagedKey) {
+		return fmt.Errorf("Option 'kms_key_arn' requires 'enable_customer_managed_key'")
+	}
+	if s.EnableCustomerManagedKey != nil && *s.EnableCustomerManagedKey && s.KMSKeyARN == "" {
+		return fmt.Errorf("Option 'enable_customer_managed_key' requires the ARN of the key in " +
+			"'kms_key_arn'")
+	}
+	fips := s.FIPS != nil && *s.FIPS
+	// FIPS mode enables the encryption of etcd unless it is explicitly disabled:
+	etcdEncryption := fips
+	if s.EtcdEncryption != nil {
+		etcdEncryption = *s.EtcdEncryption
+	}
+	if fips && !etcdEncryption {
+		return fmt.Errorf("FIPS mode requires the encryption of etcd, it can't be used with " +
+			"'etcd_encryption: false'")
+	}
+	// Without a version the default version of OCM is used, and it can't be checked offline:
+	if s.Version == "" {
+		return nil
+	}
+	if fips {
+		err = ValidateFIPS(s.Version, etcdEncryption)
+		if err != nil {
+			return err
+		}
+	}
+	if etcdEncryption {
+		err = ValidateEtcdEncryption(s.Version)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flags returns the values of the spec file indexed by the name of the corresponding command line
+// flag of the 'create cluster' command. Only the fields that are set are returned.
+func (s *SpecFile) Flags() map[string]string {
+	flags := map[string]string{}
+	setString := func(name string, value string) {
+		if value != "" {
+			flags[name] = value
+		}
+	}
+	setBool := func(name string, value *bool) {
+		if value != nil {
+			flags[name] = strconv.FormatBool(*value)
+		}
+	}
+	setInt := func(name string, value *int) {
+		if value != nil {
+			flags[name] = strconv.Itoa(*value)
+		}
+	}
+	setString("cluster-name", s.Name)
+	setString("region", s.Region)
+	setString("version", s.Version)
+	setString("channel-group", s.ChannelGroup)
+	setBool("multi-az", s.MultiAZ)
+	setString("compute-machine-type", s.ComputeMachineType)
+	setInt("compute-nodes", s.ComputeNodes)
+	setString("default-mp-labels", machinepools.FormatLabels(s.DefaultMPLabels))
+	setString("machine-cidr", s.MachineCIDR)
+	setString("service-cidr", s.ServiceCIDR)
+	setString("pod-cidr", s.PodCIDR)
+	setInt("host-prefix", s.HostPrefix)
+	setBool("private", s.Private)
+	setString("subnet-ids", strings.Join(s.SubnetIDs, ","))
+	setString("http-proxy", s.HTTPProxy)
+	setString("https-proxy", s.HTTPSProxy)
+	setString("no-proxy", s.NoProxy)
+	setString("additional-trust-bundle-file", s.AdditionalTrustBundleFile)
+	setBool("disable-scp-checks", s.DisableSCPChecks)
+	setString("tags", FormatTags(s.Tags))
+	setBool("enable-customer-managed-key", s.EnableCustomerManagedKey)
+	setString("kms-key-arn", s.KMSKeyARN)
+	setBool("etcd-encryption", s.EtcdEncryption)
+	setBool("fips", s.FIPS)
+	return flags
+}
+
+// Marshal returns the spec file in YAML format.
+func (s *SpecFile) Marshal() ([]byte, error) {
+	return yaml.Marshal(s)
+}
+
+// GetSpecFile exports the configuration of an existing cluster as a spec file that can be used to
+// create a similar cluster.
+func GetSpecFile(connection *sdk.Connection, cluster *cmv1.Cluster) (*SpecFile, error) {
+	multiAZ := cluster.MultiAZ()
+	computeNodes := cluster.Nodes().Compute()
+	private := cluster.API().Listening() == cmv1.ListeningMethodInternal
+	// Versions are given to the 'create cluster' command without the 'openshift-v' prefix of the
+	// identifier:
+	version := strings.TrimPrefix(cluster.Version().ID(), "openshift-v")
+	if version == "" {
+		version = cluster.OpenshiftVersion()
+	}
+	spec := &SpecFile{
+		Name:               cluster.Name(),
+		Region:             cluster.Region().ID(),
+		Version:            version,
+		ChannelGroup:       cluster.Version().ChannelGroup(),
+		MultiAZ:            &multiAZ,
+		ComputeMachineType: cluster.Nodes().ComputeMachineType().ID(),
+		ComputeNodes:       &computeNodes,
+		DefaultMPLabels:    cluster.Nodes().ComputeLabels(),
+		MachineCIDR:        cluster.Network().MachineCIDR(),
+		ServiceCIDR:        cluster.Network().ServiceCIDR(),
+		PodCIDR:            cluster.Network().PodCIDR(),
+		Private:            &private,
+		SubnetIDs:          cluster.AWS().SubnetIDs(),
+	}
+	if hostPrefix, ok := cluster.Network().GetHostPrefix(); ok {
+		spec.HostPrefix = &hostPrefix
+	}
+	proxy, err := GetProxy(connection, cluster.ID())
+	if err != nil {
+		return nil, err
+	}
+	if proxy != nil {
+		spec.HTTPProxy = proxy.HTTPProxy
+		spec.HTTPSProxy = proxy.HTTPSProxy
+		spec.NoProxy = proxy.NoProxy
+	}
+	tags, err := GetTags(connection, cluster.ID())
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) > 0 {
+		spec.Tags = tags
+	}
+	kmsKeyARN, err := GetKMSKeyARN(connection, cluster.ID())
+	if err != nil {
+		return nil, err
+	}
+	if kmsKeyARN != "" {
+		enabled := true
+		spec.EnableCustomerManagedKey = &enabled
+		spec.KMSKeyARN = kmsKeyARN
+	}
+	if cluster.EtcdEncryption() {
+		etcdEncryption := true
+		spec.EtcdEncryption = &etcdEncryption
+	}
+	fips, err := IsFIPS(connection, cluster.ID())
+	if err != nil {
+		return nil, err
+	}
+	if fips {
+		spec.FIPS = &fips
+	}
+	return spec, nil
+}
@@ -0,0 +1,135 @@
+package cluster_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/cluster"
+)
+
+var _ = Describe("Spec file", func() {
+	Context("ParseSpecFile", func() {
+		It("parses a YAML spec and converts it to flags", func() {
+			spec, err := ParseSpecFile([]byte(`
+name: mycluster
+region: us-east-2
+multi_az: true
+compute_nodes: 3
+default_mp_labels:
+  role: worker
+  env: dev
+machine_cidr: 10.0.0.0/16
+host_prefix: 23
+private: false
+subnet_ids:
+- subnet-1
+- subnet-2
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.Flags()).To(Equal(map[string]string{
+				"cluster-name":      "mycluster",
+				"region":            "us-east-2",
+				"multi-az":          "true",
+				"compute-nodes":     "3",
+				"default-mp-labels": "env=dev,role=worker",
+				"machine-cidr":      "10.0.0.0/16",
+				"host-prefix":       "23",
+				"private":           "false",
+				"subnet-ids":        "subnet-1,subnet-2",
+			}))
+		})
+
+		It("parses a JSON spec", func() {
+			spec, err := ParseSpecFile([]byte(`{"name": "mycluster", "version": "4.5.2"}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.Name).To(Equal("mycluster"))
+			Expect(spec.Version).To(Equal("4.5.2"))
+		})
+
+		It("rejects unknown fields", func() {
+			_, err := ParseSpecFile([]byte("name: mycluster\nnodes: 3\n"))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("rejects an invalid cluster name", func() {
+			_, err := ParseSpecFile([]byte("name: MyCluster\n"))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("rejects an invalid CIDR block", func() {
+			_, err := ParseSpecFile([]byte("pod_cidr: 10.128.0.0\n"))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("rejects invalid default machine pool labels", func() {
+			_, err := ParseSpecFile([]byte("default_mp_labels:\n  -role: worker\n"))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("parses and rejects tags", func() {
+			spec, err := ParseSpecFile([]byte("tags:\n  team: payments\n  cost-center: \"1234\"\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.Flags()).To(HaveKeyWithValue("tags", "cost-center=1234,team=payments"))
+
+			_, err = ParseSpecFile([]byte("tags:\n  aws:owner: me\n"))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("rejects a non positive number of compute nodes", func() {
+			_, err := ParseSpecFile([]byte("compute_nodes: 0\n"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Lint", func() {
+		lint := func(data string) error {
+			spec, err := ParseSpecFile([]byte(data))
+			if err != nil {
+				return err
+			}
+			return spec.Lint()
+		}
+
+		It("accepts a valid spec", func() {
+			Expect(lint("name: mycluster\nversion: 4.10.3\nfips: true\n")).To(Succeed())
+		})
+
+		It("rejects overlapping CIDR blocks", func() {
+			Expect(lint("machine_cidr: 10.0.0.0/16\npod_cidr: 10.0.0.0/14\n")).NotTo(Succeed())
+		})
+
+		It("rejects a KMS key without customer managed keys", func() {
+			Expect(lint("region: us-east-1\nkms_key_arn: arn:aws:kms:us-east-1:123456789012:key/abc\n")).
+				NotTo(Succeed())
+		})
+
+		It("rejects a KMS key in a different region", func() {
+			Expect(lint("region: us-east-2\nenable_customer_managed_key: true\n" +
+				"kms_key_arn: arn:aws:kms:us-east-1:123456789012:key/abc\n")).NotTo(Succeed())
+		})
+
+		It("rejects FIPS mode without the encryption of etcd", func() {
+			Expect(lint("fips: true\netcd_encryption: false\n")).NotTo(Succeed())
+		})
+
+		It("rejects FIPS mode in old versions", func() {
+			Expect(lint("version: 4.8.2\nfips: true\n")).NotTo(Succeed())
+		})
+	})
+
+	Context("Marshal", func() {
+		It("round trips the spec", func() {
+			private := true
+			spec := &SpecFile{
+				Name:      "mycluster",
+				Private:   &private,
+				SubnetIDs: []string{"subnet-1"},
+			}
+			data, err := spec.Marshal()
+			Expect(err).NotTo(HaveOccurred())
+			parsed, err := ParseSpecFile(data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed).To(Equal(spec))
+		})
+	})
+})
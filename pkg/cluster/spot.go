@@ -0,0 +1,114 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to validate and configure the AWS spot instances used by
+// the nodes of machine pools, which aren't supported by the SDK.
+
+package cluster
+
+import (
+	"strconv"
+	"strings"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// SpotMaxPriceOnDemand is the value of '--spot-max-price' that limits the price of the spot
+// instances to the on-demand price of the instance type, which is what AWS does when no maximum
+// price is given.
+const SpotMaxPriceOnDemand = "on-demand"
+
+// DefaultMachinePool is the identifier of the machine pool created with the cluster.
+const DefaultMachinePool = "default"
+
+// Spot describes the use of spot instances by the nodes of a machine pool.
+type Spot struct {
+	Enabled bool
+
+	// Maximum hourly price in USD, or nil to use the on-demand price:
+	MaxPrice *float64
+}
+
+// ValidateSpot checks the combination of the '--use-spot-instances' and '--spot-max-price'
+// options for the given machine pool and returns the spot configuration. Spot instances can't be
+// used by the default machine pool, as the cluster needs nodes that aren't interrupted by AWS.
+func ValidateSpot(machinePoolID string, enabled bool, maxPrice string) (*Spot, error) {
+	maxPrice = strings.TrimSpace(maxPrice)
+	if !enabled {
+		if maxPrice != "" && maxPrice != SpotMaxPriceOnDemand {
+			return nil, rerrors.ValidationErrorf("Option '--spot-max-price' requires " +
+				"'--use-spot-instances'")
+		}
+		return &Spot{}, nil
+	}
+	if machinePoolID == DefaultMachinePool {
+		return nil, rerrors.ValidationErrorf("Spot instances aren't supported on the default " +
+			"machine pool")
+	}
+	spot := &Spot{
+		Enabled: true,
+	}
+	if maxPrice == "" || maxPrice == SpotMaxPriceOnDemand {
+		return spot, nil
+	}
+	value, err := strconv.ParseFloat(maxPrice, 64)
+	if err != nil || value <= 0 {
+		return nil, rerrors.ValidationErrorf("Expected a positive price in USD per hour or '%s' "+
+			"for '--spot-max-price', got '%s'", SpotMaxPriceOnDemand, maxPrice)
+	}
+	spot.MaxPrice = &value
+	return spot, nil
+}
+
+// FormatMaxPrice returns the maximum price of the spot instances as it is given in the
+// '--spot-max-price' option.
+func (s *Spot) FormatMaxPrice() string {
+	if s.MaxPrice == nil {
+		return SpotMaxPriceOnDemand
+	}
+	return strconv.FormatFloat(*s.MaxPrice, 'f', -1, 64)
+}
+
+// attributes returns the AWS attributes of the machine pool that configure the spot instances.
+// Disabling spot instances removes the market options.
+func (s *Spot) attributes() map[string]interface{} {
+	if !s.Enabled {
+		return map[string]interface{}{
+			"spot_market_options": nil,
+		}
+	}
+	options := map[string]interface{}{}
+	if s.MaxPrice != nil {
+		options["max_price"] = *s.MaxPrice
+	}
+	return map[string]interface{}{
+		"spot_market_options": options,
+	}
+}
+
+// UpdateMachinePool updates the machine pool of the cluster. When the spot configuration is given
+// the nodes created from then on use it, the existing nodes aren't replaced.
+func UpdateMachinePool(connection *sdk.Connection, clusterID string, machinePool *cmv1.MachinePool,
+	spot *Spot) error {
+	attributes := map[string]interface{}{}
+	if spot != nil {
+		attributes["aws"] = spot.attributes()
+	}
+	return updateMachinePool(connection, clusterID, machinePool, attributes)
+}
@@ -0,0 +1,53 @@
+package cluster_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+var _ = Describe("Spot", func() {
+	Context("ValidateSpot", func() {
+		It("accepts on-demand instances", func() {
+			spot, err := ValidateSpot("mp-1", false, SpotMaxPriceOnDemand)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spot.Enabled).To(BeFalse())
+		})
+
+		It("uses the on-demand price by default", func() {
+			spot, err := ValidateSpot("mp-1", true, SpotMaxPriceOnDemand)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spot.Enabled).To(BeTrue())
+			Expect(spot.MaxPrice).To(BeNil())
+			Expect(spot.FormatMaxPrice()).To(Equal(SpotMaxPriceOnDemand))
+		})
+
+		It("parses the maximum price", func() {
+			spot, err := ValidateSpot("mp-1", true, " 0.25 ")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*spot.MaxPrice).To(Equal(0.25))
+			Expect(spot.FormatMaxPrice()).To(Equal("0.25"))
+		})
+
+		It("rejects invalid prices", func() {
+			_, err := ValidateSpot("mp-1", true, "cheap")
+			Expect(err).To(MatchError(ContainSubstring("'cheap'")))
+			Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+			_, err = ValidateSpot("mp-1", true, "0")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("rejects a price without spot instances", func() {
+			_, err := ValidateSpot("mp-1", false, "0.25")
+			Expect(err).To(MatchError(ContainSubstring("requires '--use-spot-instances'")))
+		})
+
+		It("rejects spot instances on the default machine pool", func() {
+			_, err := ValidateSpot(DefaultMachinePool, true, SpotMaxPriceOnDemand)
+			Expect(err).To(MatchError(ContainSubstring("default machine pool")))
+			Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+		})
+	})
+})
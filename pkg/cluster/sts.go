@@ -0,0 +1,56 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to read the AWS Security Token Service (STS) settings of a
+// cluster. They aren't supported yet by the version of the OCM SDK that we use.
+
+package cluster
+
+import (
+	sdk "github.com/openshift-online/ocm-sdk-go"
+)
+
+// STS contains the roles and the OpenID Connect endpoint of a cluster that uses the AWS Security
+// Token Service instead of long lived credentials.
+type STS struct {
+	RoleARN          string            `json:"role_arn"`
+	SupportRoleARN   string            `json:"support_role_arn,omitempty"`
+	OIDCEndpointURL  string            `json:"oidc_endpoint_url,omitempty"`
+	InstanceIAMRoles *InstanceIAMRoles `json:"instance_iam_roles,omitempty"`
+}
+
+// InstanceIAMRoles contains the roles of the instance profiles of the nodes of an STS cluster.
+type InstanceIAMRoles struct {
+	MasterRoleARN string `json:"master_role_arn,omitempty"`
+	WorkerRoleARN string `json:"worker_role_arn,omitempty"`
+}
+
+// GetSTS returns the STS settings of the cluster, or nil if the cluster doesn't use STS.
+func GetSTS(connection *sdk.Connection, clusterID string) (*STS, error) {
+	document := struct {
+		AWS struct {
+			STS *STS `json:"sts"`
+		} `json:"aws"`
+	}{}
+	err := getClusterAttributes(connection, clusterID, &document)
+	if err != nil {
+		return nil, err
+	}
+	if document.AWS.STS == nil || document.AWS.STS.RoleARN == "" {
+		return nil, nil
+	}
+	return document.AWS.STS, nil
+}
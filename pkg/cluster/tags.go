@@ -0,0 +1,134 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to parse and validate the custom AWS tags that the
+// installer adds to all the AWS resources that it creates for a cluster.
+
+package cluster
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// Limits of the length of the keys and values of AWS tags:
+const (
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+)
+
+// tagRE matches the characters that AWS accepts in the keys and values of tags: letters, numbers,
+// spaces and the characters '_.:/=+-@'.
+var tagRE = regexp.MustCompile(`^[\p{L}\p{Z}\p{N}_.:/=+\-@]*$`)
+
+// reservedTagPrefixes are the prefixes of the tag keys that are used by AWS, by the installer or by
+// OCM, so they can't be used for custom tags.
+var reservedTagPrefixes = []string{
+	"aws:",
+	"kubernetes.io/",
+	"red-hat-",
+}
+
+// ParseTags parses a comma-separated list of 'key=value' AWS tags.
+func ParseTags(tags string) (map[string]string, error) {
+	tagMap := map[string]string{}
+	if strings.TrimSpace(tags) == "" {
+		return tagMap, nil
+	}
+	for _, tag := range strings.Split(tags, ",") {
+		tokens := strings.SplitN(tag, "=", 2)
+		if len(tokens) != 2 {
+			return nil, rerrors.ValidationErrorf("Expected key=value format for tag '%s'", tag)
+		}
+		key := strings.TrimSpace(tokens[0])
+		value := strings.TrimSpace(tokens[1])
+		err := ValidateTag(key, value)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := tagMap[key]; ok {
+			return nil, rerrors.ValidationErrorf("Duplicated tag key '%s'", key)
+		}
+		tagMap[key] = value
+	}
+	return tagMap, nil
+}
+
+// ValidateTag checks the key and value of a tag against the rules of AWS, and checks that the key
+// doesn't use one of the reserved prefixes.
+func ValidateTag(key string, value string) error {
+	if key == "" {
+		return rerrors.ValidationErrorf("Tag key can't be empty")
+	}
+	if utf8.RuneCountInString(key) > maxTagKeyLength {
+		return rerrors.ValidationErrorf("Tag key '%s' is longer than %d characters", key,
+			maxTagKeyLength)
+	}
+	if utf8.RuneCountInString(value) > maxTagValueLength {
+		return rerrors.ValidationErrorf("Value of tag '%s' is longer than %d characters", key,
+			maxTagValueLength)
+	}
+	if !tagRE.MatchString(key) {
+		return rerrors.ValidationErrorf("Tag key '%s' must contain only letters, numbers, spaces "+
+			"and the characters '_.:/=+-@'", key)
+	}
+	if !tagRE.MatchString(value) {
+		return rerrors.ValidationErrorf("Value '%s' of tag '%s' must contain only letters, numbers, "+
+			"spaces and the characters '_.:/=+-@'", value, key)
+	}
+	for _, prefix := range reservedTagPrefixes {
+		if strings.HasPrefix(strings.ToLower(key), prefix) {
+			return rerrors.ValidationErrorf("Tag key '%s' can't start with the reserved prefix '%s'",
+				key, prefix)
+		}
+	}
+	return nil
+}
+
+// FormatTags formats the tags as a comma-separated list of 'key=value', sorted by key.
+func FormatTags(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	output := make([]string, 0, len(keys))
+	for _, key := range keys {
+		output = append(output, fmt.Sprintf("%s=%s", key, tags[key]))
+	}
+	return strings.Join(output, ",")
+}
+
+// GetTags returns the custom AWS tags of the cluster.
+func GetTags(connection *sdk.Connection, clusterID string) (map[string]string, error) {
+	document := struct {
+		AWS struct {
+			Tags map[string]string `json:"tags"`
+		} `json:"aws"`
+	}{}
+	err := getClusterAttributes(connection, clusterID, &document)
+	if err != nil {
+		return nil, err
+	}
+	return document.AWS.Tags, nil
+}
@@ -0,0 +1,69 @@
+package cluster_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+var _ = Describe("Tags", func() {
+	Context("ParseTags", func() {
+		It("parses a list of tags", func() {
+			tags, err := ParseTags("team=payments, cost-center=1234,owner=me@example.com,empty=")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tags).To(Equal(map[string]string{
+				"team":        "payments",
+				"cost-center": "1234",
+				"owner":       "me@example.com",
+				"empty":       "",
+			}))
+		})
+
+		It("accepts an empty list", func() {
+			tags, err := ParseTags("  ")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tags).To(BeEmpty())
+		})
+
+		It("rejects tags without value", func() {
+			_, err := ParseTags("team")
+			Expect(err).To(MatchError(ContainSubstring("key=value")))
+			Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+		})
+
+		It("rejects duplicated keys", func() {
+			_, err := ParseTags("team=a,team=b")
+			Expect(err).To(MatchError(ContainSubstring("Duplicated")))
+		})
+	})
+
+	Context("ValidateTag", func() {
+		It("rejects reserved prefixes", func() {
+			Expect(ValidateTag("aws:createdBy", "me")).NotTo(Succeed())
+			Expect(ValidateTag("AWS:createdBy", "me")).NotTo(Succeed())
+			Expect(ValidateTag("red-hat-managed", "false")).NotTo(Succeed())
+			Expect(ValidateTag("kubernetes.io/cluster/mycluster", "owned")).NotTo(Succeed())
+		})
+
+		It("rejects invalid characters", func() {
+			Expect(ValidateTag("team!", "payments")).NotTo(Succeed())
+			Expect(ValidateTag("team", "pay$")).NotTo(Succeed())
+		})
+
+		It("rejects keys and values that are too long", func() {
+			Expect(ValidateTag(strings.Repeat("k", 129), "value")).NotTo(Succeed())
+			Expect(ValidateTag("key", strings.Repeat("v", 257))).NotTo(Succeed())
+			Expect(ValidateTag(strings.Repeat("k", 128), strings.Repeat("v", 256))).To(Succeed())
+		})
+	})
+
+	Context("FormatTags", func() {
+		It("sorts the tags by key", func() {
+			Expect(FormatTags(map[string]string{"b": "2", "a": "1"})).To(Equal("a=1,b=2"))
+		})
+	})
+})
@@ -0,0 +1,143 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to list the scheduled upgrades of all the clusters of the
+// organization of the user, so that fleet administrators can review them in one place.
+
+package cluster
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/paging"
+	"github.com/openshift/moactl/pkg/ocm/upgrades"
+)
+
+// ScheduledUpgrade is an upgrade policy of one of the clusters of the organization, with its
+// current state.
+type ScheduledUpgrade struct {
+	Cluster *cmv1.Cluster
+	Policy  *cmv1.UpgradePolicy
+	State   string
+}
+
+// UpgradeFilter contains the criteria used to select scheduled upgrades. Empty values match any
+// upgrade.
+type UpgradeFilter struct {
+	// Only upgrades whose next run is before this time:
+	Before time.Time
+
+	// Only upgrades in this state, like 'pending' or 'scheduled':
+	State string
+}
+
+// ListOrganizationClusters loads all the clusters that the user can see, which for organization
+// administrators are all the clusters of the organization, not only the ones created by the user.
+func ListOrganizationClusters(client *cmv1.ClustersClient) ([]*cmv1.Cluster, error) {
+	result := []*cmv1.Cluster{}
+	request := client.List().Order("name asc")
+	for pager := (paging.Options{All: true}).Pager(); pager.Next(); {
+		response, err := request.Page(pager.Page()).Size(pager.Size()).Send()
+		if err != nil {
+			return nil, rerrors.FromOCM(response.Error(), err)
+		}
+		clusters := response.Items().Slice()
+		from, to := pager.Select(len(clusters))
+		result = append(result, clusters[from:to]...)
+	}
+	return result, nil
+}
+
+// GetScheduledUpgrades loads the upgrade policies of the clusters and their states concurrently,
+// using at most MaxWorkers requests at the same time. Failures to load the policies of a cluster
+// are returned together, without stopping the requests for the other clusters.
+func GetScheduledUpgrades(client *cmv1.Client, clusters []*cmv1.Cluster) ([]*ScheduledUpgrade,
+	[]error) {
+	results := make([][]*ScheduledUpgrade, len(clusters))
+	errs := []error{}
+	var lock sync.Mutex
+	parallel(len(clusters), func(i int) {
+		cluster := clusters[i]
+		policies, err := upgrades.GetUpgradePolicies(client, cluster.ID())
+		if err != nil {
+			lock.Lock()
+			defer lock.Unlock()
+			errs = append(errs, fmt.Errorf("Failed to get upgrade policies of cluster '%s': %v",
+				cluster.Name(), err))
+			return
+		}
+		for _, policy := range policies {
+			if policy.UpgradeType() != "OSD" {
+				continue
+			}
+			scheduled := &ScheduledUpgrade{
+				Cluster: cluster,
+				Policy:  policy,
+			}
+			// The state is only informative, so failing to load it doesn't hide the upgrade:
+			state, err := upgrades.GetUpgradePolicyState(client, cluster.ID(), policy.ID())
+			if err == nil {
+				scheduled.State = state.Value()
+			}
+			results[i] = append(results[i], scheduled)
+		}
+	})
+	result := []*ScheduledUpgrade{}
+	for _, clusterUpgrades := range results {
+		result = append(result, clusterUpgrades...)
+	}
+	return result, errs
+}
+
+// FilterUpgrades returns the upgrades that match the filter, sorted by their next run, earliest
+// first.
+func FilterUpgrades(scheduled []*ScheduledUpgrade, filter UpgradeFilter) []*ScheduledUpgrade {
+	result := []*ScheduledUpgrade{}
+	for _, upgrade := range scheduled {
+		if !filter.Before.IsZero() && !upgrade.Policy.NextRun().Before(filter.Before) {
+			continue
+		}
+		if filter.State != "" && upgrade.State != filter.State {
+			continue
+		}
+		result = append(result, upgrade)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Policy.NextRun().Before(result[j].Policy.NextRun())
+	})
+	return result
+}
+
+// ParseBefore parses the value of the '--before' flag, which can be a date like '2021-03-01',
+// meaning the start of that day in the given location, or a time in RFC3339 format.
+func ParseBefore(value string, location *time.Location) (time.Time, error) {
+	date, err := time.ParseInLocation("2006-01-02", value, location)
+	if err == nil {
+		return date, nil
+	}
+	date, err = time.Parse(time.RFC3339, value)
+	if err == nil {
+		return date, nil
+	}
+	return time.Time{}, rerrors.ValidationErrorf("Invalid value '%s' for '--before': expected a "+
+		"date like '2006-01-02' or a time like '2006-01-02T15:04:05Z'", value)
+}
@@ -0,0 +1,79 @@
+package cluster_test
+
+import (
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+var _ = Describe("Upgrades", func() {
+	Context("FilterUpgrades", func() {
+		now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+		var scheduled []*ScheduledUpgrade
+
+		BeforeEach(func() {
+			upgrade := func(name string, nextRun time.Time, state string) *ScheduledUpgrade {
+				policy, err := cmv1.NewUpgradePolicy().NextRun(nextRun).Build()
+				Expect(err).NotTo(HaveOccurred())
+				return &ScheduledUpgrade{
+					Cluster: build(newCluster().Name(name)),
+					Policy:  policy,
+					State:   state,
+				}
+			}
+			scheduled = []*ScheduledUpgrade{
+				upgrade("c", now.AddDate(0, 0, 7), "pending"),
+				upgrade("a", now.AddDate(0, 0, 1), "scheduled"),
+				upgrade("b", now.AddDate(0, 0, 3), "pending"),
+			}
+		})
+
+		names := func(list []*ScheduledUpgrade) []string {
+			result := []string{}
+			for _, upgrade := range list {
+				result = append(result, upgrade.Cluster.Name())
+			}
+			return result
+		}
+
+		It("sorts by next run", func() {
+			Expect(names(FilterUpgrades(scheduled, UpgradeFilter{}))).To(Equal([]string{"a", "b", "c"}))
+		})
+
+		It("selects the upgrades before the given time", func() {
+			result := FilterUpgrades(scheduled, UpgradeFilter{Before: now.AddDate(0, 0, 3)})
+			Expect(names(result)).To(Equal([]string{"a"}))
+		})
+
+		It("selects the upgrades in the given state", func() {
+			result := FilterUpgrades(scheduled, UpgradeFilter{State: "pending"})
+			Expect(names(result)).To(Equal([]string{"b", "c"}))
+		})
+	})
+
+	Context("ParseBefore", func() {
+		It("parses dates in the given location", func() {
+			location := time.FixedZone("CET", 3600)
+			before, err := ParseBefore("2021-03-01", location)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(before).To(BeTemporally("==", time.Date(2021, 2, 28, 23, 0, 0, 0, time.UTC)))
+		})
+
+		It("parses times", func() {
+			before, err := ParseBefore("2021-03-01T10:30:00Z", time.Local)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(before).To(BeTemporally("==", time.Date(2021, 3, 1, 10, 30, 0, 0, time.UTC)))
+		})
+
+		It("rejects other values", func() {
+			_, err := ParseBefore("next week", time.Local)
+			Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+		})
+	})
+})
@@ -0,0 +1,105 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to change the visibility of the API and the default
+// application router of a cluster.
+
+package cluster
+
+import (
+	"fmt"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// visibilityProducts are the identifiers of the products whose clusters support changing the
+// visibility after installation.
+var visibilityProducts = []string{"rosa", "moa"}
+
+// IsPrivate checks if the API of the cluster is restricted to private connectivity.
+func IsPrivate(cluster *cmv1.Cluster) bool {
+	return cluster.API().Listening() == cmv1.ListeningMethodInternal
+}
+
+// ValidateVisibilityChange checks that the visibility of the given cluster can be changed: the
+// product of the cluster must support it and the cluster must be ready.
+func ValidateVisibilityChange(cluster *cmv1.Cluster) error {
+	product := cluster.Product().ID()
+	supported := false
+	for _, id := range visibilityProducts {
+		if product == id {
+			supported = true
+		}
+	}
+	if !supported {
+		return rerrors.ValidationErrorf("Changing the visibility of '%s' clusters isn't supported", product)
+	}
+	if cluster.State() != cmv1.ClusterStateReady {
+		return rerrors.ConflictErrorf("Cluster '%s' is in state '%s', the visibility can only be changed "+
+			"when the cluster is ready", cluster.Name(), cluster.State())
+	}
+	return nil
+}
+
+// UpdateDefaultIngressVisibility changes the listening method of the default application router of
+// the cluster, so that it matches the visibility of the API.
+func UpdateDefaultIngressVisibility(connection *sdk.Connection, clusterID string, private bool) error {
+	ingressesClient := connection.ClustersMgmt().V1().Clusters().Cluster(clusterID).Ingresses()
+	response, err := ingressesClient.List().
+		Page(1).
+		Size(-1).
+		Send()
+	if err != nil {
+		return handleErr(response.Error(), err)
+	}
+	var ingress *cmv1.Ingress
+	response.Items().Each(func(item *cmv1.Ingress) bool {
+		if item.Default() {
+			ingress = item
+			return false
+		}
+		return true
+	})
+	if ingress == nil {
+		return fmt.Errorf("Failed to find the default ingress of cluster '%s'", clusterID)
+	}
+
+	listening := cmv1.ListeningMethodExternal
+	if private {
+		listening = cmv1.ListeningMethodInternal
+	}
+	if ingress.Listening() == listening {
+		return nil
+	}
+	body, err := cmv1.NewIngress().
+		ID(ingress.ID()).
+		Listening(listening).
+		Build()
+	if err != nil {
+		return err
+	}
+	updateResponse, err := ingressesClient.Ingress(ingress.ID()).
+		Update().
+		Body(body).
+		Send()
+	if err != nil {
+		return handleErr(updateResponse.Error(), err)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package cluster_test
+
+import (
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/cluster"
+)
+
+var _ = Describe("Visibility", func() {
+	DescribeTable("ValidateVisibilityChange",
+		func(product string, state cmv1.ClusterState, valid bool) {
+			cluster := build(newCluster().
+				Product(cmv1.NewProduct().ID(product)).
+				State(state))
+			err := ValidateVisibilityChange(cluster)
+			if valid {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+			}
+		},
+		Entry("Ready ROSA cluster", "rosa", cmv1.ClusterStateReady, true),
+		Entry("Other product", "osd", cmv1.ClusterStateReady, false),
+		Entry("Cluster that isn't ready", "rosa", cmv1.ClusterStateInstalling, false),
+	)
+})
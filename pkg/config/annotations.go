@@ -0,0 +1,71 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the annotations that commands use to change how the environment and the
+// saved settings are applied to their flags.
+
+package config
+
+import (
+	"github.com/spf13/cobra"
+)
+
+const (
+	// ClusterArgAnnotation marks the commands that also accept the cluster as their first
+	// positional argument.
+	ClusterArgAnnotation = "rosa/cluster-arg"
+
+	// ExplicitClusterAnnotation marks the commands that delete or change clusters or their
+	// resources. It applies to all the subcommands of the command that has it.
+	ExplicitClusterAnnotation = "rosa/explicit-cluster"
+)
+
+// MarkClusterArg marks the command as accepting the cluster as its first positional argument, so
+// that an argument takes precedence over the environment and the saved default cluster. The
+// arguments of other commands, like the identifier of a machine pool, don't.
+func MarkClusterArg(cmd *cobra.Command) {
+	annotate(cmd, ClusterArgAnnotation)
+}
+
+// MarkExplicitCluster marks the command, and all its subcommands, as deleting or changing clusters
+// or their resources. They don't use the saved default cluster, so that running them without
+// '--cluster' can't change the default cluster by mistake.
+func MarkExplicitCluster(cmd *cobra.Command) {
+	annotate(cmd, ExplicitClusterAnnotation)
+}
+
+func annotate(cmd *cobra.Command, name string) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[name] = "true"
+}
+
+// clusterArgGiven checks if the cluster was given as a positional argument of the command.
+func clusterArgGiven(cmd *cobra.Command, argv []string) bool {
+	return len(argv) > 0 && cmd.Annotations[ClusterArgAnnotation] != ""
+}
+
+// requiresExplicitCluster checks if the command, or one of its parents, needs the cluster to be
+// given explicitly, instead of using the saved default.
+func requiresExplicitCluster(cmd *cobra.Command) bool {
+	for ; cmd != nil; cmd = cmd.Parent() {
+		if cmd.Annotations[ExplicitClusterAnnotation] != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,282 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the types and functions used to manage the settings of the command line
+// client that aren't part of the OCM configuration, like the named profiles.
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// Settings contains the defaults used by the commands when the corresponding flags aren't given.
+type Settings struct {
+	Cluster string `json:"cluster,omitempty"`
+	Region  string `json:"region,omitempty"`
+	Output  string `json:"output,omitempty"`
+	Color   string `json:"color,omitempty"`
+}
+
+// Config is the type used to store the settings of the client. The settings of the current profile
+// take precedence over the global settings.
+type Config struct {
+	Profile  string               `json:"profile,omitempty"`
+	Settings Settings             `json:"settings"`
+	Profiles map[string]*Settings `json:"profiles,omitempty"`
+
+	// Custom presets of the options of the create cluster command, by name:
+	Presets map[string]map[string]string `json:"presets,omitempty"`
+}
+
+// Keys are the names of the settings that can be read and written with the Get and Set methods.
+var Keys = []string{
+	"profile",
+	"cluster",
+	"region",
+	"output",
+	"color",
+}
+
+// ColorModes are the valid values of the 'color' setting.
+var ColorModes = []string{
+	"auto",
+	"always",
+	"never",
+}
+
+var profileNameRE = regexp.MustCompile(`^[a-zA-Z0-9][-_a-zA-Z0-9]*$`)
+
+// Dir returns the directory that contains the configuration of the client. It can be changed with
+// the ROSA_CONFIG_DIR environment variable.
+func Dir() (string, error) {
+	if dir := os.Getenv("ROSA_CONFIG_DIR"); dir != "" {
+		return dir, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "rosa"), nil
+}
+
+// Location returns the location of the configuration file.
+func Location() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.json"), nil
+}
+
+// OCMConfigLocation returns the location of the OCM configuration file of the given profile, which
+// contains the tokens and the URL of the API used by that profile.
+func OCMConfigLocation(profile string) (string, error) {
+	err := ValidateProfileName(profile)
+	if err != nil {
+		return "", err
+	}
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "profiles", profile+".json"), nil
+}
+
+// ValidateProfileName checks that the name of a profile can be safely used as a file name.
+func ValidateProfileName(profile string) error {
+	if !profileNameRE.MatchString(profile) {
+		return fmt.Errorf("Invalid profile name '%s': expected letters, digits, '-' and '_'", profile)
+	}
+	return nil
+}
+
+// Load loads the configuration from the configuration file. If the configuration file doesn't exist
+// it will return an empty configuration object.
+func Load() (*Config, error) {
+	file, err := Location()
+	if err != nil {
+		return nil, err
+	}
+	cfg := new(Config)
+	// #nosec G304
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read config file '%s': %v", file, err)
+	}
+	err = json.Unmarshal(data, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse config file '%s': %v", file, err)
+	}
+	return cfg, nil
+}
+
+// Save saves the given configuration to the configuration file.
+func Save(cfg *Config) error {
+	file, err := Location()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Failed to marshal config: %v", err)
+	}
+	err = os.MkdirAll(filepath.Dir(file), 0700)
+	if err != nil {
+		return fmt.Errorf("Failed to create directory for config file '%s': %v", file, err)
+	}
+	err = ioutil.WriteFile(file, data, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to write file '%s': %v", file, err)
+	}
+	return nil
+}
+
+// CurrentProfile returns the name of the profile in use. The '--ocm-profile' flag takes precedence
+// over the ROSA_PROFILE environment variable, which takes precedence over the profile saved in the
+// configuration file. It returns an empty string if no profile is in use.
+func CurrentProfile() (string, error) {
+	if profile != "" {
+		return profile, nil
+	}
+	if value := os.Getenv("ROSA_PROFILE"); value != "" {
+		return value, nil
+	}
+	cfg, err := Load()
+	if err != nil {
+		return "", err
+	}
+	return cfg.Profile, nil
+}
+
+// ProfileNames returns the sorted names of the profiles that have settings.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns the value of the given setting for the given profile, falling back to the global
+// settings when the profile doesn't set it. For the 'profile' key it returns the given profile,
+// which is the one in use.
+func (c *Config) Get(profile string, key string) (string, error) {
+	if key == "profile" {
+		return profile, nil
+	}
+	settings := []*Settings{}
+	if profile != "" && c.Profiles[profile] != nil {
+		settings = append(settings, c.Profiles[profile])
+	}
+	settings = append(settings, &c.Settings)
+	for _, s := range settings {
+		value, err := s.get(key)
+		if err != nil {
+			return "", err
+		}
+		if value != "" {
+			return value, nil
+		}
+	}
+	return "", nil
+}
+
+// Set changes the value of the given setting for the given profile, or the global settings if the
+// profile is empty. An empty value removes the setting.
+func (c *Config) Set(profile string, key string, value string) error {
+	if key == "profile" {
+		if value != "" {
+			err := ValidateProfileName(value)
+			if err != nil {
+				return err
+			}
+		}
+		c.Profile = value
+		return nil
+	}
+	settings := &c.Settings
+	if profile != "" {
+		err := ValidateProfileName(profile)
+		if err != nil {
+			return err
+		}
+		if c.Profiles == nil {
+			c.Profiles = map[string]*Settings{}
+		}
+		if c.Profiles[profile] == nil {
+			c.Profiles[profile] = new(Settings)
+		}
+		settings = c.Profiles[profile]
+	}
+	return settings.set(key, value)
+}
+
+func (s *Settings) get(key string) (string, error) {
+	switch key {
+	case "cluster":
+		return s.Cluster, nil
+	case "region":
+		return s.Region, nil
+	case "output":
+		return s.Output, nil
+	case "color":
+		return s.Color, nil
+	}
+	return "", unknownKeyErr(key)
+}
+
+func (s *Settings) set(key string, value string) error {
+	switch key {
+	case "cluster":
+		s.Cluster = value
+	case "region":
+		s.Region = value
+	case "output":
+		s.Output = value
+	case "color":
+		if value != "" && !contains(ColorModes, value) {
+			return fmt.Errorf("Invalid color mode '%s', expected one of %v", value, ColorModes)
+		}
+		s.Color = value
+	default:
+		return unknownKeyErr(key)
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func unknownKeyErr(key string) error {
+	return fmt.Errorf("Unknown setting '%s', expected one of %v", key, Keys)
+}
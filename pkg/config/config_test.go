@@ -0,0 +1,46 @@
+package config_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/config"
+)
+
+var _ = Describe("Config", func() {
+	var cfg *Config
+
+	BeforeEach(func() {
+		cfg = new(Config)
+		Expect(cfg.Set("", "region", "us-east-1")).To(Succeed())
+		Expect(cfg.Set("staging", "region", "us-west-2")).To(Succeed())
+	})
+
+	It("Returns the settings of the profile", func() {
+		value, err := cfg.Get("staging", "region")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal("us-west-2"))
+	})
+
+	It("Falls back to the global settings", func() {
+		value, err := cfg.Get("production", "region")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal("us-east-1"))
+	})
+
+	It("Falls back to the global settings when the profile setting is removed", func() {
+		Expect(cfg.Set("staging", "region", "")).To(Succeed())
+		value, err := cfg.Get("staging", "region")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal("us-east-1"))
+	})
+
+	It("Rejects unknown settings", func() {
+		Expect(cfg.Set("", "unknown", "value")).NotTo(Succeed())
+	})
+
+	It("Rejects invalid profile names", func() {
+		Expect(cfg.Set("", "profile", "../other")).NotTo(Succeed())
+		Expect(cfg.Set("../other", "region", "us-east-1")).NotTo(Succeed())
+	})
+})
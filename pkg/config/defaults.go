@@ -0,0 +1,80 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to apply the saved settings as the default values of the
+// command line flags.
+
+package config
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// flagSettings maps the names of the command line flags to the settings that provide their default
+// values.
+var flagSettings = map[string]string{
+	"cluster": "cluster",
+	"region":  "region",
+	"output":  "output",
+}
+
+// applied contains the names of the flags whose values were set from the saved settings.
+var applied = map[string]bool{}
+
+// Applied checks if the value of the given flag was set from the saved settings instead of being
+// given in the command line.
+func Applied(name string) bool {
+	return applied[name]
+}
+
+// ApplyDefaults sets the flags of the command that weren't given in the command line to the values
+// saved in the settings of the current profile. It needs to run before the required flags are
+// checked, so that a default cluster satisfies a required '--cluster' flag.
+func ApplyDefaults(cmd *cobra.Command, argv []string) error {
+	cfg, err := Load()
+	if err != nil {
+		return err
+	}
+	profile, err := CurrentProfile()
+	if err != nil {
+		return err
+	}
+	flags := cmd.Flags()
+	for name, key := range flagSettings {
+		flag := flags.Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+		// Some commands also accept the cluster as a positional argument, and an explicit
+		// argument has to take precedence over the default:
+		if name == "cluster" && (clusterArgGiven(cmd, argv) || requiresExplicitCluster(cmd)) {
+			continue
+		}
+		value, err := cfg.Get(profile, key)
+		if err != nil {
+			return err
+		}
+		if value == "" {
+			continue
+		}
+		err = flags.Set(name, value)
+		if err != nil {
+			return err
+		}
+		applied[name] = true
+	}
+	return nil
+}
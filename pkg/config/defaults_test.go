@@ -0,0 +1,74 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	. "github.com/openshift/moactl/pkg/config"
+)
+
+var _ = Describe("Defaults", func() {
+	var (
+		dir     string
+		root    *cobra.Command
+		cluster string
+	)
+
+	// command creates a command with the given verb and a '--cluster' flag under the root:
+	command := func(verb string) *cobra.Command {
+		verbCmd := &cobra.Command{Use: verb}
+		nounCmd := &cobra.Command{Use: "cluster"}
+		nounCmd.Flags().StringVar(&cluster, "cluster", "", "")
+		verbCmd.AddCommand(nounCmd)
+		root.AddCommand(verbCmd)
+		return nounCmd
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "rosa-config-")
+		Expect(err).NotTo(HaveOccurred())
+		os.Setenv("ROSA_CONFIG_DIR", dir)
+		cfg := new(Config)
+		Expect(cfg.Set("", "cluster", "mycluster")).To(Succeed())
+		Expect(Save(cfg)).To(Succeed())
+		root = &cobra.Command{Use: "rosa"}
+		cluster = ""
+	})
+
+	AfterEach(func() {
+		os.Unsetenv("ROSA_CONFIG_DIR")
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("Uses the saved cluster when the flag isn't given", func() {
+		cmd := command("describe")
+		Expect(ApplyDefaults(cmd, nil)).To(Succeed())
+		Expect(cluster).To(Equal("mycluster"))
+		Expect(Applied("cluster")).To(BeTrue())
+	})
+
+	It("Gives precedence to the cluster given as argument", func() {
+		cmd := command("describe")
+		MarkClusterArg(cmd)
+		Expect(ApplyDefaults(cmd, []string{"other"})).To(Succeed())
+		Expect(cluster).To(BeEmpty())
+	})
+
+	It("Uses the saved cluster when the argument isn't the cluster", func() {
+		cmd := command("describe")
+		Expect(ApplyDefaults(cmd, []string{"mp-1"})).To(Succeed())
+		Expect(cluster).To(Equal("mycluster"))
+	})
+
+	It("Doesn't use the saved cluster for commands that require it explicitly", func() {
+		cmd := command("delete")
+		MarkExplicitCluster(cmd.Parent())
+		Expect(ApplyDefaults(cmd, nil)).To(Succeed())
+		Expect(cluster).To(BeEmpty())
+	})
+})
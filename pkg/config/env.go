@@ -0,0 +1,77 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to set the values of the command line flags from
+// environment variables, so that commands can be configured without long command lines.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// EnvPrefix is the prefix of the environment variables that provide the values of the flags.
+const EnvPrefix = "ROSA_"
+
+// ignoredEnvFlags contains the flags that aren't set from environment variables, either because
+// the variable that corresponds to them already has a different meaning, like ROSA_PROFILE, or
+// because they have their own variable, like AWS_PROFILE.
+var ignoredEnvFlags = map[string]bool{
+	"help":        true,
+	"profile":     true,
+	"aws-profile": true,
+	"ocm-profile": true,
+}
+
+// EnvName returns the name of the environment variable that provides the value of the given flag,
+// for example ROSA_CLUSTER for '--cluster' or ROSA_NON_INTERACTIVE for '--non-interactive'.
+func EnvName(flag string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
+// ApplyEnv sets the flags of the command that weren't given in the command line to the values of
+// the corresponding environment variables. Empty variables are ignored. It needs to run before
+// ApplyDefaults, so that the environment takes precedence over the saved settings.
+func ApplyEnv(cmd *cobra.Command, argv []string) error {
+	flags := cmd.Flags()
+	var err error
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if err != nil || flag.Changed || flag.Deprecated != "" || ignoredEnvFlags[flag.Name] {
+			return
+		}
+		// Some commands also accept the cluster as a positional argument, and an explicit
+		// argument has to take precedence over the environment:
+		if flag.Name == "cluster" && clusterArgGiven(cmd, argv) {
+			return
+		}
+		name := EnvName(flag.Name)
+		value := os.Getenv(name)
+		if value == "" {
+			return
+		}
+		setErr := flags.Set(flag.Name, value)
+		if setErr != nil {
+			err = fmt.Errorf("Invalid value '%s' of environment variable '%s': %v", value, name,
+				setErr)
+		}
+	})
+	return err
+}
@@ -0,0 +1,92 @@
+package config_test
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	. "github.com/openshift/moactl/pkg/config"
+)
+
+var _ = Describe("Environment", func() {
+	var (
+		cmd      *cobra.Command
+		cluster  string
+		replicas int
+		yes      bool
+		profile  string
+	)
+
+	BeforeEach(func() {
+		cmd = &cobra.Command{Use: "test"}
+		cmd.Flags().StringVar(&cluster, "cluster", "", "")
+		cmd.Flags().IntVar(&replicas, "replicas", 0, "")
+		cmd.Flags().BoolVar(&yes, "yes", false, "")
+		cmd.Flags().StringVar(&profile, "profile", "", "")
+		for _, name := range []string{"ROSA_CLUSTER", "ROSA_REPLICAS", "ROSA_YES", "ROSA_PROFILE"} {
+			os.Unsetenv(name)
+		}
+	})
+
+	AfterEach(func() {
+		for _, name := range []string{"ROSA_CLUSTER", "ROSA_REPLICAS", "ROSA_YES", "ROSA_PROFILE"} {
+			os.Unsetenv(name)
+		}
+	})
+
+	It("Names the variables after the flags", func() {
+		Expect(EnvName("cluster")).To(Equal("ROSA_CLUSTER"))
+		Expect(EnvName("non-interactive")).To(Equal("ROSA_NON_INTERACTIVE"))
+	})
+
+	It("Sets the flags from the environment", func() {
+		os.Setenv("ROSA_CLUSTER", "mycluster")
+		os.Setenv("ROSA_REPLICAS", "3")
+		os.Setenv("ROSA_YES", "true")
+		Expect(cmd.ParseFlags(nil)).To(Succeed())
+		Expect(ApplyEnv(cmd, nil)).To(Succeed())
+		Expect(cluster).To(Equal("mycluster"))
+		Expect(replicas).To(Equal(3))
+		Expect(yes).To(BeTrue())
+		Expect(cmd.Flags().Changed("cluster")).To(BeTrue())
+	})
+
+	It("Gives precedence to the command line", func() {
+		os.Setenv("ROSA_CLUSTER", "mycluster")
+		Expect(cmd.ParseFlags([]string{"--cluster=other"})).To(Succeed())
+		Expect(ApplyEnv(cmd, nil)).To(Succeed())
+		Expect(cluster).To(Equal("other"))
+	})
+
+	It("Gives precedence to the cluster given as argument", func() {
+		os.Setenv("ROSA_CLUSTER", "mycluster")
+		MarkClusterArg(cmd)
+		Expect(cmd.ParseFlags(nil)).To(Succeed())
+		Expect(ApplyEnv(cmd, []string{"other"})).To(Succeed())
+		Expect(cluster).To(BeEmpty())
+	})
+
+	It("Sets the cluster when the argument isn't the cluster", func() {
+		os.Setenv("ROSA_CLUSTER", "mycluster")
+		Expect(cmd.ParseFlags(nil)).To(Succeed())
+		Expect(ApplyEnv(cmd, []string{"mp-1"})).To(Succeed())
+		Expect(cluster).To(Equal("mycluster"))
+	})
+
+	It("Ignores variables that have a different meaning", func() {
+		os.Setenv("ROSA_PROFILE", "staging")
+		Expect(cmd.ParseFlags(nil)).To(Succeed())
+		Expect(ApplyEnv(cmd, nil)).To(Succeed())
+		Expect(profile).To(BeEmpty())
+	})
+
+	It("Rejects invalid values", func() {
+		os.Setenv("ROSA_REPLICAS", "many")
+		Expect(cmd.ParseFlags(nil)).To(Succeed())
+		err := ApplyEnv(cmd, nil)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("ROSA_REPLICAS"))
+	})
+})
@@ -0,0 +1,37 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to implement the '--ocm-profile' command line option.
+
+package config
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// AddFlag adds the profile flag to the given set of command line flags.
+func AddFlag(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&profile,
+		"ocm-profile",
+		"",
+		"Use a specific profile from the rosa configuration, with its own OCM credentials, "+
+			"API URL and defaults (overrides the ROSA_PROFILE environment variable).",
+	)
+}
+
+// profile is a string flag that indicates which profile is being used.
+var profile string
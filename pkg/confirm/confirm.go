@@ -14,6 +14,8 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
+// This file contains functions used to implement the '--yes' command line option.
+
 package confirm
 
 import (
@@ -21,9 +23,9 @@ import (
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/spf13/pflag"
-)
 
-var yes bool
+	"github.com/openshift/moactl/pkg/interactive"
+)
 
 // AddFlag adds the --yes flag to the given set of command line flags.
 func AddFlag(flags *pflag.FlagSet) {
@@ -36,14 +38,38 @@ func AddFlag(flags *pflag.FlagSet) {
 	)
 }
 
-func Confirm(q string, v ...interface{}) bool {
+// Yes returns a boolean flag that indicates if operations should be confirmed automatically.
+func Yes() bool {
+	return yes
+}
+
+// Confirm asks the user to confirm the given operation. If the '--yes' flag was given the operation
+// is confirmed without asking. If questions can't be asked, because the standard input isn't a
+// terminal or the '--non-interactive' flag was given, it returns an error instead of waiting for an
+// answer that will never come.
+func Confirm(q string, v ...interface{}) (bool, error) {
 	if yes {
-		return yes
+		return true, nil
+	}
+	operation := fmt.Sprintf(q, v...)
+	err := interactive.CheckPrompt()
+	if err != nil {
+		return false, fmt.Errorf(
+			"Unable to confirm operation to %s: %v, use the '--yes' flag to confirm it automatically",
+			operation, err,
+		)
 	}
+	var answer bool
 	prompt := &survey.Confirm{
-		Message: fmt.Sprintf("Are you sure you want to %s?", fmt.Sprintf(q, v...)),
+		Message: fmt.Sprintf("Are you sure you want to %s?", operation),
 		Default: false,
 	}
-	survey.AskOne(prompt, &yes, survey.WithValidator(survey.Required))
-	return yes
+	err = survey.AskOne(prompt, &answer, survey.WithValidator(survey.Required))
+	if err != nil {
+		return false, err
+	}
+	return answer, nil
 }
+
+// yes is a boolean flag that indicates that operations should be confirmed automatically.
+var yes bool
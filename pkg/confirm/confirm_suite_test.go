@@ -0,0 +1,13 @@
+package confirm_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestConfirm(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Confirm Suite")
+}
@@ -0,0 +1,172 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to show the changes that an update will make to an object
+// before asking the user to confirm it.
+
+package confirm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Change is a field that an update changes, with its current and requested values.
+type Change struct {
+	Field  string
+	Before string
+	After  string
+}
+
+// Diff compares the JSON document sent to update an object with the JSON document of the current
+// object, and returns the fields of the update whose values are different, sorted by name. Nested
+// fields are named with their path, separated by dots.
+func Diff(current []byte, patch []byte) ([]Change, error) {
+	before, err := flatten(current)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse current object: %v", err)
+	}
+	after, err := flatten(patch)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse update: %v", err)
+	}
+	fields := make([]string, 0, len(after))
+	for field := range after {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	changes := []Change{}
+	for _, field := range fields {
+		if before[field] == after[field] {
+			continue
+		}
+		changes = append(changes, Change{
+			Field:  field,
+			Before: before[field],
+			After:  after[field],
+		})
+	}
+	return changes, nil
+}
+
+// Removed returns the fields of the current object inside the given object of the update that the
+// update doesn't contain. Objects like maps of properties are replaced as a whole, so those fields
+// are removed by the update. Nothing is removed when the update doesn't contain the object.
+func Removed(current []byte, patch []byte, path string) ([]Change, error) {
+	before, err := flatten(current)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse current object: %v", err)
+	}
+	after, err := flatten(patch)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse update: %v", err)
+	}
+	prefix := path + "."
+	replaced := false
+	for field := range after {
+		if strings.HasPrefix(field, prefix) {
+			replaced = true
+			break
+		}
+	}
+	changes := []Change{}
+	if !replaced {
+		return changes, nil
+	}
+	fields := []string{}
+	for field := range before {
+		if _, ok := after[field]; !ok && strings.HasPrefix(field, prefix) {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		changes = append(changes, Change{
+			Field:  field,
+			Before: before[field],
+		})
+	}
+	return changes, nil
+}
+
+// FormatChanges returns the changes with one field per line, showing its current and requested
+// values.
+func FormatChanges(changes []Change) string {
+	lines := make([]string, len(changes))
+	for i, change := range changes {
+		lines[i] = fmt.Sprintf("  %s: %s -> %s", change.Field, formatValue(change.Before),
+			formatValue(change.After))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ConfirmChanges prints the changes and asks the user to confirm the operation, like Confirm. The
+// changes are also printed when the operation is confirmed with the '--yes' flag, so that they are
+// recorded in the output of the command.
+func ConfirmChanges(changes []Change, q string, v ...interface{}) (bool, error) {
+	fmt.Printf("The following changes will be applied:\n%s\n", FormatChanges(changes))
+	return Confirm(q, v...)
+}
+
+func formatValue(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
+
+// flatten returns the values of the fields of the JSON document, indexed by their path.
+func flatten(data []byte) (map[string]string, error) {
+	result := map[string]string{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return result, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	err := decoder.Decode(&value)
+	if err != nil {
+		return nil, err
+	}
+	flattenValue("", value, result)
+	return result, nil
+}
+
+func flattenValue(path string, value interface{}, result map[string]string) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, item := range typed {
+			field := key
+			if path != "" {
+				field = path + "." + key
+			}
+			flattenValue(field, item, result)
+		}
+	case string:
+		result[path] = typed
+	case nil:
+		result[path] = ""
+	default:
+		// Numbers, booleans and lists are shown as they are written in the document:
+		data, err := json.Marshal(typed)
+		if err == nil {
+			result[path] = string(data)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package confirm_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/confirm"
+)
+
+var _ = Describe("Preview", func() {
+	current := []byte(`{
+		"kind": "Cluster",
+		"name": "mycluster",
+		"nodes": {"compute": 3},
+		"api": {"listening": "external"},
+		"node_drain_grace_period": {"value": 60, "unit": "minutes"}
+	}`)
+
+	It("Returns the fields that change, sorted by name", func() {
+		changes, err := Diff(current, []byte(`{
+			"kind": "Cluster",
+			"nodes": {"compute": 6},
+			"api": {"listening": "internal"},
+			"node_drain_grace_period": {"value": 60, "unit": "minutes"},
+			"proxy": {"http_proxy": "http://proxy.example.com"}
+		}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(Equal([]Change{
+			{Field: "api.listening", Before: "external", After: "internal"},
+			{Field: "nodes.compute", Before: "3", After: "6"},
+			{Field: "proxy.http_proxy", Before: "", After: "http://proxy.example.com"},
+		}))
+	})
+
+	It("Returns no changes when the values are the same", func() {
+		changes, err := Diff(current, []byte(`{"nodes": {"compute": 3}}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(BeEmpty())
+	})
+
+	It("Fails when the documents aren't valid", func() {
+		_, err := Diff(current, []byte(`{"nodes":`))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Returns the fields removed from objects replaced by the update", func() {
+		withProperties := []byte(`{"properties": {"env": "staging", "team": "a", "rosa_creator_arn": "arn"}}`)
+		changes, err := Removed(withProperties, []byte(`{
+			"properties": {"team": "b", "rosa_creator_arn": "arn"}
+		}`), "properties")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(Equal([]Change{
+			{Field: "properties.env", Before: "staging", After: ""},
+		}))
+
+		changes, err = Removed(withProperties, []byte(`{"nodes": {"compute": 3}}`), "properties")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(BeEmpty())
+	})
+
+	It("Formats the changes with one field per line", func() {
+		Expect(FormatChanges([]Change{
+			{Field: "nodes.compute", Before: "3", After: "6"},
+			{Field: "proxy.http_proxy", Before: "", After: "http://proxy.example.com"},
+		})).To(Equal("" +
+			"  nodes.compute: 3 -> 6\n" +
+			"  proxy.http_proxy: (none) -> http://proxy.example.com"))
+	})
+})
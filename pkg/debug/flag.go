@@ -14,28 +14,75 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-// This file contains functions used to implement the '--debug' command line option.
+// This file contains functions used to implement the '--debug' and '--log-level' command line
+// options.
 
 package debug
 
 import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/pflag"
 )
 
+// Levels are the valid values of the '--log-level' flag.
+var Levels = []string{
+	"error",
+	"warn",
+	"info",
+	"debug",
+	"trace",
+}
+
 // AddFlag adds the debug flag to the given set of command line flags.
 func AddFlag(flags *pflag.FlagSet) {
 	flags.BoolVar(
 		&enabled,
 		"debug",
 		false,
-		"Enable debug mode.",
+		"Enable debug mode, including sanitized traces of the requests sent to the OCM and AWS APIs. "+
+			"Same as '--log-level=debug'.",
 	)
 }
 
-// Enabled retursn a boolean flag that indicates if the debug mode is enabled.
+// AddLevelFlag adds the log level flag to the given set of command line flags.
+func AddLevelFlag(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&level,
+		"log-level",
+		"",
+		fmt.Sprintf("Level of the messages that are printed, one of %v. The default is 'info', or "+
+			"'debug' when the '--debug' flag is given.", Levels),
+	)
+}
+
+// Enabled retursn a boolean flag that indicates if the debug mode is enabled, either with the
+// '--debug' flag or with a log level that includes debug messages.
 func Enabled() bool {
-	return enabled
+	value, err := Level()
+	return err == nil && value >= logrus.DebugLevel
+}
+
+// Level returns the level of the messages that should be printed. It returns an error if the
+// value of the '--log-level' flag isn't valid.
+func Level() (logrus.Level, error) {
+	if level == "" {
+		if enabled {
+			return logrus.DebugLevel, nil
+		}
+		return logrus.InfoLevel, nil
+	}
+	for _, valid := range Levels {
+		if level == valid {
+			return logrus.ParseLevel(level)
+		}
+	}
+	return logrus.InfoLevel, fmt.Errorf("Invalid log level '%s', expected one of %v", level, Levels)
 }
 
 // enabled is a boolean flag that indicates that the debug mode is enabled.
 var enabled bool
+
+// level is the value of the '--log-level' flag.
+var level string
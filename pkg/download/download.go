@@ -0,0 +1,351 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to download the client tools from the mirror, verify their
+// checksums and install them.
+
+package download
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+)
+
+// MirrorURL is the base URL of the mirror that publishes the client tools.
+const MirrorURL = "https://mirror.openshift.com/pub/openshift-v4"
+
+// ChecksumsFile is the name of the file that contains the SHA-256 checksums of the files
+// published in each directory of the mirror.
+const ChecksumsFile = "sha256sum.txt"
+
+// Artifact describes a file published in the mirror and the executable that it contains.
+type Artifact struct {
+	// Directory of the mirror that contains the file and its checksums:
+	BaseURL string
+
+	// Name of the file:
+	File string
+
+	// Name of the executable inside the file:
+	Binary string
+}
+
+// URL returns the address of the file.
+func (a *Artifact) URL() string {
+	return a.BaseURL + "/" + a.File
+}
+
+// ChecksumsURL returns the address of the checksums of the file.
+func (a *Artifact) ChecksumsURL() string {
+	return a.BaseURL + "/" + ChecksumsFile
+}
+
+// ClientArtifact returns the archive of the OpenShift client tools of the given version, like
+// 'latest', 'stable-4.6' or '4.6.8', for the operating system and architecture of the given
+// platform.
+func ClientArtifact(version string, goos string, goarch string) (*Artifact, error) {
+	platform := goos
+	if goos == "darwin" {
+		platform = "mac"
+	}
+	switch goarch {
+	case "amd64":
+	case "arm64", "ppc64le", "s390x":
+		platform = fmt.Sprintf("%s-%s", platform, goarch)
+	default:
+		return nil, fmt.Errorf("Architecture '%s' isn't supported by the OpenShift client", goarch)
+	}
+	return &Artifact{
+		BaseURL: fmt.Sprintf("%s/clients/ocp/%s", MirrorURL, version),
+		File:    fmt.Sprintf("openshift-client-%s.%s", platform, archiveExtension(goos)),
+		Binary:  binaryName("oc", goos),
+	}, nil
+}
+
+// ROSAArtifact returns the archive of the given release of this tool, like 'latest' or '1.0.2', for
+// the operating system and architecture of the given platform.
+func ROSAArtifact(version string, goos string, goarch string) (*Artifact, error) {
+	if goarch != "amd64" {
+		return nil, fmt.Errorf("Architecture '%s' isn't supported by the published releases", goarch)
+	}
+	platform := goos
+	if goos == "darwin" {
+		platform = "macosx"
+	}
+	return &Artifact{
+		BaseURL: fmt.Sprintf("%s/clients/rosa/%s", MirrorURL, version),
+		File:    fmt.Sprintf("rosa-%s.%s", platform, archiveExtension(goos)),
+		Binary:  binaryName("rosa", goos),
+	}, nil
+}
+
+// CurrentPlatform returns the operating system and architecture of the running tool.
+func CurrentPlatform() (string, string) {
+	return runtime.GOOS, runtime.GOARCH
+}
+
+func archiveExtension(goos string) string {
+	if goos == "windows" {
+		return "zip"
+	}
+	return "tar.gz"
+}
+
+func binaryName(name string, goos string) string {
+	if goos == "windows" {
+		return name + ".exe"
+	}
+	return name
+}
+
+// Fetch downloads the artifact to the given directory, verifying that its SHA-256 checksum is the
+// one published in the mirror. The file is only moved to its final location once verified. It
+// returns the path of the downloaded file.
+func Fetch(artifact *Artifact, dir string) (string, error) {
+	expected, err := getChecksum(artifact)
+	if err != nil {
+		return "", err
+	}
+	return FetchVerified(artifact, dir, expected)
+}
+
+// FetchVerified downloads the artifact to the given directory, verifying that its SHA-256 checksum
+// is the given one. It is used when the checksums have already been downloaded and verified by
+// other means, like a signature.
+func FetchVerified(artifact *Artifact, dir string, expected string) (string, error) {
+	target := filepath.Join(dir, artifact.File)
+	out, err := os.Create(target + ".tmp")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(target + ".tmp")
+
+	// nolint:gosec
+	resp, err := http.Get(artifact.URL())
+	if err != nil {
+		out.Close()
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		out.Close()
+		return "", fmt.Errorf("Failed to download '%s': unexpected status code %d", artifact.URL(),
+			resp.StatusCode)
+	}
+
+	// Calculate the checksum while the file is written, reporting the progress:
+	hash := sha256.New()
+	counter := &WriteCounter{}
+	_, err = io.Copy(io.MultiWriter(out, hash), io.TeeReader(resp.Body, counter))
+	// The progress use the same line so print a new line once it's finished downloading
+	fmt.Print("\n")
+	out.Close()
+	if err != nil {
+		return "", err
+	}
+
+	actual := hex.EncodeToString(hash.Sum(nil))
+	if actual != expected {
+		return "", fmt.Errorf("Checksum of '%s' is '%s' but '%s' was expected", artifact.File, actual,
+			expected)
+	}
+
+	err = os.Rename(target+".tmp", target)
+	if err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+// getChecksum downloads the checksums published in the directory of the artifact and returns the
+// one of its file.
+func getChecksum(artifact *Artifact) (string, error) {
+	// nolint:gosec
+	resp, err := http.Get(artifact.ChecksumsURL())
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Failed to download checksums '%s': unexpected status code %d",
+			artifact.ChecksumsURL(), resp.StatusCode)
+	}
+	checksums, err := ParseChecksums(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return artifact.Checksum(checksums)
+}
+
+// Checksum returns the checksum of the file of the artifact from the given checksums.
+func (a *Artifact) Checksum(checksums map[string]string) (string, error) {
+	checksum, ok := checksums[a.File]
+	if !ok {
+		return "", fmt.Errorf("Checksum of '%s' isn't published in '%s'", a.File, a.ChecksumsURL())
+	}
+	return checksum, nil
+}
+
+// ParseChecksums parses the output of the 'sha256sum' command, returning a map from file names to
+// checksums.
+func ParseChecksums(reader io.Reader) (map[string]string, error) {
+	result := map[string]string{}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		// Files read in binary mode are marked with an asterisk:
+		result[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+	}
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read checksums: %v", err)
+	}
+	return result, nil
+}
+
+// Install extracts the executable of the artifact from the downloaded archive to the given
+// directory, replacing the existing one, if any. It returns the path of the executable.
+func Install(artifact *Artifact, archive string, dir string) (string, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return "", fmt.Errorf("Failed to create directory '%s': %v", dir, err)
+	}
+	target := filepath.Join(dir, artifact.Binary)
+	err = Extract(artifact, archive, target)
+	if err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+// Extract extracts the executable of the artifact from the downloaded archive to the given path,
+// replacing the existing file, if any.
+func Extract(artifact *Artifact, archive string, target string) error {
+	if strings.HasSuffix(archive, ".zip") {
+		return extractZip(archive, artifact.Binary, target)
+	}
+	return extractTarGz(archive, artifact.Binary, target)
+}
+
+func extractTarGz(archive string, binary string, target string) error {
+	// #nosec G304
+	file, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("Failed to read '%s': %v", archive, err)
+	}
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to read '%s': %v", archive, err)
+		}
+		if header.Typeflag == tar.TypeReg && path.Base(header.Name) == binary {
+			return writeExecutable(reader, target)
+		}
+	}
+	return fmt.Errorf("Archive '%s' doesn't contain '%s'", archive, binary)
+}
+
+func extractZip(archive string, binary string, target string) error {
+	reader, err := zip.OpenReader(archive)
+	if err != nil {
+		return fmt.Errorf("Failed to read '%s': %v", archive, err)
+	}
+	defer reader.Close()
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() || path.Base(file.Name) != binary {
+			continue
+		}
+		content, err := file.Open()
+		if err != nil {
+			return fmt.Errorf("Failed to read '%s': %v", archive, err)
+		}
+		defer content.Close()
+		return writeExecutable(content, target)
+	}
+	return fmt.Errorf("Archive '%s' doesn't contain '%s'", archive, binary)
+}
+
+// writeExecutable writes the content to a temporary file next to the target and then renames it,
+// so that an executable that is running isn't partially overwritten.
+func writeExecutable(content io.Reader, target string) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(target), filepath.Base(target)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	// #nosec G110
+	_, err = io.Copy(tmp, content)
+	tmp.Close()
+	if err != nil {
+		return fmt.Errorf("Failed to write '%s': %v", target, err)
+	}
+	// #nosec G302
+	err = os.Chmod(tmp.Name(), 0755)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+// WriteCounter counts the number of bytes written to it. It implements to the io.Writer interface
+// and we can pass this into io.TeeReader() which will report progress on each write cycle.
+type WriteCounter struct {
+	Total uint64
+}
+
+func (wc *WriteCounter) Write(p []byte) (int, error) {
+	n := len(p)
+	wc.Total += uint64(n)
+	wc.PrintProgress()
+	return n, nil
+}
+
+func (wc WriteCounter) PrintProgress() {
+	// Clear the line by using a character return to go back to the start and remove
+	// the remaining characters by filling it with spaces
+	fmt.Printf("\r%s", strings.Repeat(" ", 35))
+
+	// Return again and print current status of download
+	// We use the humanize package to print the bytes in a meaningful way (e.g. 10 MB)
+	fmt.Printf("\rDownloading... %s complete", humanize.Bytes(wc.Total))
+}
@@ -0,0 +1,180 @@
+package download_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/download"
+)
+
+var _ = Describe("Download", func() {
+	Context("Artifacts", func() {
+		It("Uses the names of the OpenShift client archives of the mirror", func() {
+			artifact, err := ClientArtifact("latest", "darwin", "amd64")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artifact.URL()).To(Equal(
+				"https://mirror.openshift.com/pub/openshift-v4/clients/ocp/latest/openshift-client-mac.tar.gz"))
+			Expect(artifact.ChecksumsURL()).To(Equal(
+				"https://mirror.openshift.com/pub/openshift-v4/clients/ocp/latest/sha256sum.txt"))
+			Expect(artifact.Binary).To(Equal("oc"))
+
+			artifact, err = ClientArtifact("stable-4.6", "linux", "arm64")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artifact.File).To(Equal("openshift-client-linux-arm64.tar.gz"))
+		})
+
+		It("Uses the names of the archives of the releases of this tool", func() {
+			artifact, err := ROSAArtifact("latest", "windows", "amd64")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(artifact.URL()).To(Equal(
+				"https://mirror.openshift.com/pub/openshift-v4/clients/rosa/latest/rosa-windows.zip"))
+			Expect(artifact.Binary).To(Equal("rosa.exe"))
+		})
+
+		It("Rejects architectures without releases", func() {
+			_, err := ROSAArtifact("latest", "linux", "arm64")
+			Expect(err).To(HaveOccurred())
+			_, err = ClientArtifact("latest", "linux", "386")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	It("Parses the output of sha256sum", func() {
+		checksums, err := ParseChecksums(strings.NewReader(
+			"ABC123  openshift-client-linux.tar.gz\ndef456 *rosa-windows.zip\n\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checksums).To(Equal(map[string]string{
+			"openshift-client-linux.tar.gz": "abc123",
+			"rosa-windows.zip":              "def456",
+		}))
+	})
+
+	Context("Fetch and install", func() {
+		var (
+			dir      string
+			server   *httptest.Server
+			archive  []byte
+			checksum string
+		)
+
+		tarGz := func(name string, content string) []byte {
+			buffer := &bytes.Buffer{}
+			gz := gzip.NewWriter(buffer)
+			writer := tar.NewWriter(gz)
+			Expect(writer.WriteHeader(&tar.Header{
+				Name:     name,
+				Mode:     0755,
+				Size:     int64(len(content)),
+				Typeflag: tar.TypeReg,
+			})).To(Succeed())
+			_, err := writer.Write([]byte(content))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(writer.Close()).To(Succeed())
+			Expect(gz.Close()).To(Succeed())
+			return buffer.Bytes()
+		}
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "download-")
+			Expect(err).NotTo(HaveOccurred())
+			archive = tarGz("oc", "#!/bin/sh\n")
+			sum := sha256.Sum256(archive)
+			checksum = hex.EncodeToString(sum[:])
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/sha256sum.txt":
+					fmt.Fprintf(w, "%s  openshift-client-linux.tar.gz\n", checksum)
+				case "/openshift-client-linux.tar.gz":
+					_, _ = w.Write(archive)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		artifact := func() *Artifact {
+			return &Artifact{
+				BaseURL: server.URL,
+				File:    "openshift-client-linux.tar.gz",
+				Binary:  "oc",
+			}
+		}
+
+		It("Downloads the archive and installs the executable", func() {
+			path, err := Fetch(artifact(), dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(path).To(Equal(filepath.Join(dir, "openshift-client-linux.tar.gz")))
+
+			binary, err := Install(artifact(), path, filepath.Join(dir, "bin"))
+			Expect(err).NotTo(HaveOccurred())
+			content, err := ioutil.ReadFile(binary)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("#!/bin/sh\n"))
+			info, err := os.Stat(binary)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0755)))
+		})
+
+		It("Rejects archives with the wrong checksum", func() {
+			checksum = strings.Repeat("0", 64)
+			_, err := Fetch(artifact(), dir)
+			Expect(err).To(MatchError(ContainSubstring("but '" + checksum + "' was expected")))
+			files, err := ioutil.ReadDir(dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(BeEmpty())
+		})
+
+		It("Fails when the checksum isn't published", func() {
+			other := artifact()
+			other.File = "openshift-client-windows.zip"
+			_, err := Fetch(other, dir)
+			Expect(err).To(MatchError(ContainSubstring("isn't published")))
+		})
+
+		It("Installs executables from zip archives", func() {
+			path := filepath.Join(dir, "rosa-windows.zip")
+			file, err := os.Create(path)
+			Expect(err).NotTo(HaveOccurred())
+			writer := zip.NewWriter(file)
+			entry, err := writer.Create("rosa.exe")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = entry.Write([]byte("binary"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(writer.Close()).To(Succeed())
+			Expect(file.Close()).To(Succeed())
+
+			binary, err := Install(&Artifact{File: "rosa-windows.zip", Binary: "rosa.exe"}, path, dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(binary).To(Equal(filepath.Join(dir, "rosa.exe")))
+		})
+
+		It("Fails when the archive doesn't contain the executable", func() {
+			path, err := Fetch(artifact(), dir)
+			Expect(err).NotTo(HaveOccurred())
+			other := artifact()
+			other.Binary = "kubectl"
+			_, err = Install(other, path, dir)
+			Expect(err).To(MatchError(ContainSubstring("doesn't contain 'kubectl'")))
+		})
+	})
+})
@@ -0,0 +1,219 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the errors and exit codes used by the commands, so that scripts can
+// distinguish the kind of failure using the exit code.
+
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
+)
+
+// Exit codes of the commands:
+const (
+	// ExitCodeGeneric is used for failures that don't have a more specific exit code.
+	ExitCodeGeneric = 1
+
+	// ExitCodeValidation is used when the flags or arguments given by the user aren't valid.
+	ExitCodeValidation = 2
+
+	// ExitCodeNotFound is used when a cluster or other resource doesn't exist.
+	ExitCodeNotFound = 3
+
+	// ExitCodeAWSAuth is used when the AWS credentials are missing, invalid or don't have the
+	// required permissions.
+	ExitCodeAWSAuth = 4
+
+	// ExitCodeOCMAuth is used when the user isn't logged in to OCM, the token has expired, or the
+	// user isn't allowed to perform the operation.
+	ExitCodeOCMAuth = 5
+
+	// ExitCodeConflict is used when the operation isn't possible in the current state of the
+	// resource, for example because the cluster isn't ready or the resource already exists.
+	ExitCodeConflict = 6
+
+	// ExitCodeTimeout is used when an operation didn't complete in the allowed time.
+	ExitCodeTimeout = 7
+)
+
+// exitCoder is implemented by the errors that know the exit code that should be used by the
+// command that fails because of them.
+type exitCoder interface {
+	ExitCode() int
+}
+
+// Error is an error with the exit code that should be used by the command that fails because of
+// it.
+type Error struct {
+	code  int
+	msg   string
+	cause error
+}
+
+// Error is the implementation of the error interface.
+func (e *Error) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the error that caused this one, if any.
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// ExitCode returns the exit code associated to the error.
+func (e *Error) ExitCode() int {
+	return e.code
+}
+
+// Errorf creates a new error with the given exit code and message.
+func Errorf(code int, format string, args ...interface{}) error {
+	return &Error{
+		code: code,
+		msg:  fmt.Sprintf(format, args...),
+	}
+}
+
+// Wrap associates the given exit code to an existing error, preserving its message.
+func Wrap(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{
+		code:  code,
+		msg:   err.Error(),
+		cause: err,
+	}
+}
+
+// ValidationErrorf creates a new error for flags or arguments that aren't valid.
+func ValidationErrorf(format string, args ...interface{}) error {
+	return Errorf(ExitCodeValidation, format, args...)
+}
+
+// NotFoundErrorf creates a new error for resources that don't exist.
+func NotFoundErrorf(format string, args ...interface{}) error {
+	return Errorf(ExitCodeNotFound, format, args...)
+}
+
+// ConflictErrorf creates a new error for operations that aren't possible in the current state of
+// the resource.
+func ConflictErrorf(format string, args ...interface{}) error {
+	return Errorf(ExitCodeConflict, format, args...)
+}
+
+// TimeoutErrorf creates a new error for operations that didn't complete in time.
+func TimeoutErrorf(format string, args ...interface{}) error {
+	return Errorf(ExitCodeTimeout, format, args...)
+}
+
+// FromOCM converts the error returned by the OCM API into an error with the exit code that
+// corresponds to the HTTP status of the response. The message is the reason given by the API, or
+// the message of the error if there is no reason.
+func FromOCM(res *ocmerrors.Error, err error) error {
+	msg := res.Reason()
+	if msg == "" {
+		msg = err.Error()
+	}
+	// Without a response the request wasn't sent or didn't complete, so the exit code depends on
+	// the error, for example if it is a timeout:
+	code := exitCodeForOCMError(res)
+	if res == nil {
+		code = ExitCode(err)
+	}
+	return &Error{
+		code:  code,
+		msg:   msg,
+		cause: err,
+	}
+}
+
+// ExitCode returns the exit code that should be used when a command fails because of the given
+// error. Errors that don't carry an exit code are classified using the OCM and AWS error details
+// and checking if they are timeouts, and ExitCodeGeneric is returned when that isn't possible.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var coder exitCoder
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+	var ocmErr *ocmerrors.Error
+	if errors.As(err, &ocmErr) {
+		return exitCodeForOCMError(ocmErr)
+	}
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		// Errors sending the request keep the original error, which may be a timeout:
+		if awsErr.OrigErr() != nil && ExitCode(awsErr.OrigErr()) == ExitCodeTimeout {
+			return ExitCodeTimeout
+		}
+		return exitCodeForAWSCode(awsErr.Code())
+	}
+	var timeoutErr interface{ Timeout() bool }
+	if errors.As(err, &timeoutErr) && timeoutErr.Timeout() {
+		return ExitCodeTimeout
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ExitCodeTimeout
+	}
+	return ExitCodeGeneric
+}
+
+// exitCodeForOCMError returns the exit code that corresponds to the HTTP status of the OCM error,
+// which is used as its identifier.
+func exitCodeForOCMError(err *ocmerrors.Error) int {
+	status, _ := strconv.Atoi(err.ID())
+	switch status {
+	case http.StatusBadRequest:
+		return ExitCodeValidation
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ExitCodeOCMAuth
+	case http.StatusNotFound:
+		return ExitCodeNotFound
+	case http.StatusConflict:
+		return ExitCodeConflict
+	case http.StatusGatewayTimeout, http.StatusRequestTimeout:
+		return ExitCodeTimeout
+	default:
+		return ExitCodeGeneric
+	}
+}
+
+func exitCodeForAWSCode(code string) int {
+	switch code {
+	case "NoCredentialProviders", "ExpiredToken", "ExpiredTokenException", "InvalidClientTokenId",
+		"UnrecognizedClientException", "SignatureDoesNotMatch", "AccessDenied",
+		"AccessDeniedException", "UnauthorizedOperation":
+		return ExitCodeAWSAuth
+	case "NoSuchEntity", "NotFoundException", "NoSuchBucket":
+		return ExitCodeNotFound
+	case "EntityAlreadyExists", "AlreadyExistsException":
+		return ExitCodeConflict
+	case "RequestCanceled":
+		return ExitCodeTimeout
+	default:
+		return ExitCodeGeneric
+	}
+}
@@ -0,0 +1,87 @@
+package errors_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+var _ = Describe("Exit codes", func() {
+	// The entries of the table are created before the specs run, so build errors can't be
+	// checked here; the builder only fails for invalid content, which these aren't:
+	ocmError := func(status string) *ocmerrors.Error {
+		err, _ := ocmerrors.NewError().
+			ID(status).
+			Reason("Something went wrong").
+			Build()
+		return err
+	}
+
+	DescribeTable("Classifies errors",
+		func(err error, expected int) {
+			Expect(rerrors.ExitCode(err)).To(Equal(expected))
+		},
+		Entry("No error", nil, 0),
+		Entry("Plain error", errors.New("failed"), rerrors.ExitCodeGeneric),
+		Entry("Validation error", rerrors.ValidationErrorf("invalid"), rerrors.ExitCodeValidation),
+		Entry("Wrapped error",
+			fmt.Errorf("context: %w", rerrors.NotFoundErrorf("missing")), rerrors.ExitCodeNotFound),
+		Entry("Timeout error", rerrors.TimeoutErrorf("timed out"), rerrors.ExitCodeTimeout),
+		Entry("OCM unauthorized", ocmError("401"), rerrors.ExitCodeOCMAuth),
+		Entry("OCM not found", ocmError("404"), rerrors.ExitCodeNotFound),
+		Entry("OCM conflict", ocmError("409"), rerrors.ExitCodeConflict),
+		Entry("OCM server error", ocmError("500"), rerrors.ExitCodeGeneric),
+		Entry("AWS expired token",
+			awserr.New("ExpiredToken", "The security token included in the request is expired", nil),
+			rerrors.ExitCodeAWSAuth),
+		Entry("AWS missing entity",
+			awserr.New("NoSuchEntity", "The user doesn't exist", nil),
+			rerrors.ExitCodeNotFound),
+		Entry("AWS request timeout",
+			awserr.New("RequestError", "send request failed",
+				&url.Error{Op: "Post", URL: "https://sts.amazonaws.com", Err: timeoutError{}}),
+			rerrors.ExitCodeTimeout),
+		Entry("Network timeout",
+			fmt.Errorf("can't send request: %w",
+				&url.Error{Op: "Get", URL: "https://api.openshift.com", Err: timeoutError{}}),
+			rerrors.ExitCodeTimeout),
+		Entry("Context deadline", fmt.Errorf("failed: %w", context.DeadlineExceeded),
+			rerrors.ExitCodeTimeout),
+	)
+
+	It("Uses the exit code of the error when there is no OCM response", func() {
+		err := fmt.Errorf("can't send request: %w", rerrors.TimeoutErrorf("timed out"))
+		converted := rerrors.FromOCM(nil, err)
+		Expect(converted).To(MatchError(err.Error()))
+		Expect(rerrors.ExitCode(converted)).To(Equal(rerrors.ExitCodeTimeout))
+	})
+
+	It("Preserves the reason of OCM errors", func() {
+		err := ocmError("403")
+		converted := rerrors.FromOCM(err, err)
+		Expect(converted).To(MatchError("Something went wrong"))
+		Expect(rerrors.ExitCode(converted)).To(Equal(rerrors.ExitCodeOCMAuth))
+	})
+
+	It("Preserves the message of wrapped errors", func() {
+		err := rerrors.Wrap(rerrors.ExitCodeConflict, errors.New("already exists"))
+		Expect(err).To(MatchError("already exists"))
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeConflict))
+	})
+})
+
+// timeoutError is an error that reports a timeout, like the errors of the net package.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
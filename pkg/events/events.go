@@ -0,0 +1,194 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to build the timeline of the changes of a cluster, merging
+// the events recorded by OCM, the scheduled upgrades and the operations started by this tool.
+
+package events
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+
+	"github.com/openshift/moactl/pkg/operations"
+)
+
+// Types of events, used to group the changes of a cluster:
+const (
+	TypeCluster = "cluster"
+	TypeUpgrade = "upgrade"
+	TypeScaling = "scaling"
+	TypeIDP     = "idp"
+	TypeOther   = "other"
+)
+
+// Sources of events:
+const (
+	SourceCluster       = "cluster"
+	SourceServiceLog    = "service-log"
+	SourceUpgradePolicy = "upgrade-policy"
+	SourceOperation     = "operation"
+)
+
+// Event is a change of a cluster.
+type Event struct {
+	Timestamp time.Time `json:"timestamp"`
+	Type      string    `json:"type"`
+	Source    string    `json:"source"`
+	Severity  string    `json:"severity,omitempty"`
+	Summary   string    `json:"summary"`
+	Details   string    `json:"details,omitempty"`
+}
+
+// typeKeywords are the words that identify the type of an event from its description. They are
+// checked in order, so more specific words go first.
+var typeKeywords = []struct {
+	keyword   string
+	eventType string
+}{
+	{"identity provider", TypeIDP},
+	{"idp", TypeIDP},
+	{"upgrade", TypeUpgrade},
+	{"machine pool", TypeScaling},
+	{"machinepool", TypeScaling},
+	{"autoscal", TypeScaling},
+	{"scal", TypeScaling},
+	{"replicas", TypeScaling},
+	{"compute nodes", TypeScaling},
+	{"install", TypeCluster},
+	{"hibernat", TypeCluster},
+	{"cluster", TypeCluster},
+}
+
+// Classify returns the type of the event with the given description.
+func Classify(text string) string {
+	text = strings.ToLower(text)
+	for _, item := range typeKeywords {
+		if strings.Contains(text, item.keyword) {
+			return item.eventType
+		}
+	}
+	return TypeOther
+}
+
+// FromCluster returns the events that are part of the description of the cluster itself.
+func FromCluster(cluster *cmv1.Cluster) []*Event {
+	result := []*Event{}
+	if created := cluster.CreationTimestamp(); !created.IsZero() {
+		result = append(result, &Event{
+			Timestamp: created,
+			Type:      TypeCluster,
+			Source:    SourceCluster,
+			Summary:   fmt.Sprintf("Cluster '%s' created", cluster.Name()),
+			Details:   fmt.Sprintf("Region %s, version %s", cluster.Region().ID(), cluster.Version().RawID()),
+		})
+	}
+	return result
+}
+
+// FromLogEntries returns the events that correspond to the entries of the service log of the
+// cluster.
+func FromLogEntries(entries []*slv1.LogEntry) []*Event {
+	result := make([]*Event, len(entries))
+	for i, entry := range entries {
+		result[i] = &Event{
+			Timestamp: entry.Timestamp(),
+			Type:      Classify(entry.Summary() + " " + entry.Description()),
+			Source:    SourceServiceLog,
+			Severity:  string(entry.Severity()),
+			Summary:   entry.Summary(),
+			Details:   entry.Description(),
+		}
+	}
+	return result
+}
+
+// FromUpgradePolicies returns the events of the upgrades that are scheduled for the cluster, at the
+// time when they will run.
+func FromUpgradePolicies(policies []*cmv1.UpgradePolicy) []*Event {
+	result := []*Event{}
+	for _, policy := range policies {
+		if policy.UpgradeType() != "OSD" || policy.NextRun().IsZero() {
+			continue
+		}
+		details := "Runs once"
+		if policy.ScheduleType() == "automatic" {
+			details = fmt.Sprintf("Recurring with schedule '%s'", policy.Schedule())
+		}
+		result = append(result, &Event{
+			Timestamp: policy.NextRun(),
+			Type:      TypeUpgrade,
+			Source:    SourceUpgradePolicy,
+			Summary:   fmt.Sprintf("Upgrade to version %s scheduled", policy.Version()),
+			Details:   details,
+		})
+	}
+	return result
+}
+
+// FromOperations returns the events of the start and the end of the operations of the cluster
+// with the given identifier.
+func FromOperations(list []*operations.Operation, clusterID string) []*Event {
+	result := []*Event{}
+	for _, operation := range list {
+		if operation.ClusterID != clusterID {
+			continue
+		}
+		eventType := Classify(operation.Type)
+		details := ""
+		if operation.OperationID != "" {
+			details = fmt.Sprintf("OCM operation ID %s", operation.OperationID)
+		}
+		result = append(result, &Event{
+			Timestamp: operation.StartedAt,
+			Type:      eventType,
+			Source:    SourceOperation,
+			Summary:   fmt.Sprintf("%s started", operation.Description()),
+			Details:   details,
+		})
+		if operation.Finished() {
+			result = append(result, &Event{
+				Timestamp: operation.FinishedAt,
+				Type:      eventType,
+				Source:    SourceOperation,
+				Summary:   fmt.Sprintf("%s %s", operation.Description(), operation.State),
+				Details:   operation.Details,
+			})
+		}
+	}
+	return result
+}
+
+// Timeline returns the events that happened, or are scheduled to happen, at the given time or
+// after it, oldest first. Events with the same time keep their relative order.
+func Timeline(list []*Event, since time.Time) []*Event {
+	result := []*Event{}
+	for _, event := range list {
+		if event.Timestamp.Before(since) {
+			continue
+		}
+		result = append(result, event)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
+	return result
+}
@@ -0,0 +1,136 @@
+package events_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+
+	"github.com/openshift/moactl/pkg/events"
+	"github.com/openshift/moactl/pkg/operations"
+)
+
+var _ = Describe("Events", func() {
+	now := time.Date(2021, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	DescribeTable("Classifies events by their description",
+		func(text string, expected string) {
+			Expect(events.Classify(text)).To(Equal(expected))
+		},
+		Entry("Upgrade", "Cluster upgrade to 4.6.8 completed", events.TypeUpgrade),
+		Entry("Identity provider", "rosa create idp: success", events.TypeIDP),
+		Entry("Machine pool", "create-machinepool", events.TypeScaling),
+		Entry("Scaling", "Cluster scaled to 6 compute nodes", events.TypeScaling),
+		Entry("Cluster", "Cluster has been hibernated", events.TypeCluster),
+		Entry("Other", "Certificate renewed", events.TypeOther),
+	)
+
+	It("Returns the creation of the cluster", func() {
+		cluster, err := cmv1.NewCluster().
+			Name("mycluster").
+			CreationTimestamp(now).
+			Region(cmv1.NewCloudRegion().ID("us-east-1")).
+			Version(cmv1.NewVersion().RawID("4.6.8")).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events.FromCluster(cluster)).To(Equal([]*events.Event{{
+			Timestamp: now,
+			Type:      events.TypeCluster,
+			Source:    events.SourceCluster,
+			Summary:   "Cluster 'mycluster' created",
+			Details:   "Region us-east-1, version 4.6.8",
+		}}))
+	})
+
+	It("Returns the entries of the service log", func() {
+		entry, err := slv1.NewLogEntry().
+			Timestamp(now).
+			Severity(slv1.SeverityWarning).
+			Summary("Cluster upgrade failed").
+			Description("Nodes couldn't be drained").
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events.FromLogEntries([]*slv1.LogEntry{entry})).To(Equal([]*events.Event{{
+			Timestamp: now,
+			Type:      events.TypeUpgrade,
+			Source:    events.SourceServiceLog,
+			Severity:  string(slv1.SeverityWarning),
+			Summary:   "Cluster upgrade failed",
+			Details:   "Nodes couldn't be drained",
+		}}))
+	})
+
+	It("Returns the scheduled upgrades", func() {
+		manual, err := cmv1.NewUpgradePolicy().
+			UpgradeType("OSD").
+			ScheduleType("manual").
+			Version("4.6.9").
+			NextRun(now).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+		automatic, err := cmv1.NewUpgradePolicy().
+			UpgradeType("OSD").
+			ScheduleType("automatic").
+			Schedule("0 2 * * 0").
+			NextRun(now.Add(time.Hour)).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+		other, err := cmv1.NewUpgradePolicy().UpgradeType("ADDON").NextRun(now).Build()
+		Expect(err).NotTo(HaveOccurred())
+		result := events.FromUpgradePolicies([]*cmv1.UpgradePolicy{manual, automatic, other})
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Summary).To(Equal("Upgrade to version 4.6.9 scheduled"))
+		Expect(result[0].Details).To(Equal("Runs once"))
+		Expect(result[1].Details).To(Equal("Recurring with schedule '0 2 * * 0'"))
+	})
+
+	It("Returns the start and end of the operations of the cluster", func() {
+		list := []*operations.Operation{
+			{
+				ID: "1", Type: operations.EditMachinePool, ClusterID: "123", ClusterName: "mycluster",
+				Resource: "workers", OperationID: "abc", State: operations.StateSucceeded,
+				Details: "Machine pool has 3 replicas", StartedAt: now,
+				FinishedAt: now.Add(time.Minute),
+			},
+			{
+				ID: "2", Type: operations.DeleteCluster, ClusterID: "456", ClusterName: "other",
+				State: operations.StateInProgress, StartedAt: now,
+			},
+		}
+		Expect(events.FromOperations(list, "123")).To(Equal([]*events.Event{
+			{
+				Timestamp: now,
+				Type:      events.TypeScaling,
+				Source:    events.SourceOperation,
+				Summary:   "Edit machine pool 'workers' of cluster 'mycluster' started",
+				Details:   "OCM operation ID abc",
+			},
+			{
+				Timestamp: now.Add(time.Minute),
+				Type:      events.TypeScaling,
+				Source:    events.SourceOperation,
+				Summary:   "Edit machine pool 'workers' of cluster 'mycluster' succeeded",
+				Details:   "Machine pool has 3 replicas",
+			},
+		}))
+	})
+
+	It("Sorts the events and removes the ones before the given time", func() {
+		list := []*events.Event{
+			{Timestamp: now.Add(2 * time.Hour), Summary: "c"},
+			{Timestamp: now.Add(-time.Hour), Summary: "old"},
+			{Timestamp: now, Summary: "a"},
+			{Timestamp: now, Summary: "b"},
+		}
+		timeline := events.Timeline(list, now)
+		summaries := make([]string, len(timeline))
+		for i, event := range timeline {
+			summaries[i] = event.Summary
+		}
+		Expect(summaries).To(Equal([]string{"a", "b", "c"}))
+	})
+})
@@ -0,0 +1,42 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the function that commands use to exit the process, so that the audit log
+// entry and the metrics of the command are saved once and with the real exit code.
+
+package exit
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/openshift/moactl/pkg/audit"
+	"github.com/openshift/moactl/pkg/metrics"
+)
+
+// Exit saves the audit log entry and the metrics of the command with the given exit code and then
+// exits the process. It must be used instead of os.Exit by the commands.
+func Exit(code int) {
+	err := audit.Finish(code, "")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save audit log: %s\n", err)
+	}
+	err = metrics.Flush(code)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save metrics: %s\n", err)
+	}
+	os.Exit(code)
+}
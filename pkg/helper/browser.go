@@ -0,0 +1,48 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the function used to open pages in the browser of the user.
+
+package helper
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// OpenBrowser opens the given URL in the default browser of the user. It returns an error if the
+// operating system doesn't provide a way to do it, for example in machines without a desktop.
+func OpenBrowser(url string) error {
+	var command string
+	var args []string
+	switch runtime.GOOS {
+	case "darwin":
+		command = "open"
+	case "windows":
+		command = "rundll32"
+		args = []string{"url.dll,FileProtocolHandler"}
+	default:
+		command = "xdg-open"
+	}
+	args = append(args, url)
+	// #nosec G204
+	err := exec.Command(command, args...).Start()
+	if err != nil {
+		return fmt.Errorf("Failed to open '%s' in the browser: %v", url, err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to parse and format the options that are durations stored
+// by the API as a number of minutes, like the node drain grace period of upgrades.
+
+package helper
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DurationOption describes a command line option that contains a duration stored as a number of
+// minutes.
+type DurationOption struct {
+	// Name of the option used in error messages, for example 'node drain grace period'.
+	Name string
+
+	// Range of values accepted by the API, in minutes.
+	Min float64
+	Max float64
+}
+
+// Parse parses the value of the option, returning the number of minutes. The value can be given
+// as a number of minutes or hours with the unit, like '30 minutes' or '2 hours', as a Go duration,
+// like '90m' or '1h30m', or as a plain number of minutes, like '90'.
+func (o DurationOption) Parse(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	minutes, err := parseMinutes(value)
+	if err != nil {
+		return 0, fmt.Errorf("Expected a valid %s: %v", o.Name, err)
+	}
+	if minutes < o.Min || minutes > o.Max {
+		return 0, fmt.Errorf("Expected the %s to be between %s and %s, got '%s'", o.Name,
+			FormatMinutes(o.Min), FormatMinutes(o.Max), value)
+	}
+	return minutes, nil
+}
+
+func parseMinutes(value string) (float64, error) {
+	if value == "" {
+		return 0, fmt.Errorf("value is empty")
+	}
+	duration, err := time.ParseDuration(value)
+	if err == nil {
+		return duration.Minutes(), nil
+	}
+	parts := strings.Fields(value)
+	if len(parts) > 2 {
+		return 0, fmt.Errorf("'%s' isn't a duration", value)
+	}
+	minutes, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("'%s' isn't a number", parts[0])
+	}
+	if len(parts) == 1 {
+		return minutes, nil
+	}
+	switch parts[1] {
+	case "minute", "minutes":
+	case "hour", "hours":
+		minutes = minutes * 60
+	default:
+		return 0, fmt.Errorf("expected the unit to be 'minutes' or 'hours', got '%s'", parts[1])
+	}
+	return minutes, nil
+}
+
+// FormatMinutes converts a number of minutes to the friendly format accepted by Parse, using hours
+// when the value is a whole number of hours, for example '2 hours' or '90 minutes'.
+func FormatMinutes(minutes float64) string {
+	value := minutes
+	unit := "minute"
+	if minutes >= 60 && math.Mod(minutes, 60) == 0 {
+		value = minutes / 60
+		unit = "hour"
+	}
+	if value != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%s %s", strconv.FormatFloat(value, 'f', -1, 64), unit)
+}
@@ -0,0 +1,58 @@
+package helper_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/helper"
+)
+
+var _ = Describe("Duration option", func() {
+	option := helper.DurationOption{
+		Name: "grace period",
+		Min:  0,
+		Max:  600,
+	}
+
+	DescribeTable("Parses valid values",
+		func(value string, expected float64) {
+			minutes, err := option.Parse(value)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(minutes).To(Equal(expected))
+		},
+		Entry("Minutes", "30 minutes", 30.0),
+		Entry("One hour", "1 hour", 60.0),
+		Entry("Hours", "2 hours", 120.0),
+		Entry("Fraction of hours", "1.5 hours", 90.0),
+		Entry("Plain number of minutes", "90", 90.0),
+		Entry("Go duration", "1h30m", 90.0),
+		Entry("Surrounding spaces", " 90m ", 90.0),
+		Entry("Lower bound", "0", 0.0),
+		Entry("Upper bound", "10 hours", 600.0),
+	)
+
+	DescribeTable("Rejects invalid values",
+		func(value string, message string) {
+			_, err := option.Parse(value)
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("Empty", "", "Expected a valid grace period"),
+		Entry("Unknown unit", "2 days", "expected the unit to be"),
+		Entry("Not a number", "many hours", "isn't a number"),
+		Entry("Too many words", "1 hour 30 minutes", "isn't a duration"),
+		Entry("Below range", "-1h", "between 0 minutes and 10 hours"),
+		Entry("Above range", "11 hours", "between 0 minutes and 10 hours"),
+	)
+
+	DescribeTable("Formats values",
+		func(minutes float64, expected string) {
+			Expect(helper.FormatMinutes(minutes)).To(Equal(expected))
+		},
+		Entry("One minute", 1.0, "1 minute"),
+		Entry("Minutes", 45.0, "45 minutes"),
+		Entry("One hour", 60.0, "1 hour"),
+		Entry("Hours", 120.0, "2 hours"),
+		Entry("Not a whole number of hours", 90.0, "90 minutes"),
+	)
+})
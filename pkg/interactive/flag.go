@@ -33,6 +33,17 @@ func AddFlag(flags *pflag.FlagSet) {
 	)
 }
 
+// AddNonInteractiveFlag adds the non-interactive flag to the given set of command line flags.
+func AddNonInteractiveFlag(flags *pflag.FlagSet) {
+	flags.BoolVar(
+		&nonInteractive,
+		"non-interactive",
+		false,
+		"Never ask questions. Fail with an error listing the flags that need to be provided instead. "+
+			"This is the default when the standard input isn't a terminal.",
+	)
+}
+
 // Enabled retursn a boolean flag that indicates if the interactive mode is enabled.
 func Enabled() bool {
 	return enabled
@@ -45,3 +56,6 @@ func Enable() {
 
 // enabled is a boolean flag that indicates that the interactive mode is enabled.
 var enabled bool
+
+// nonInteractive is a boolean flag that indicates that questions should never be asked.
+var nonInteractive bool
@@ -22,6 +22,7 @@ import (
 	"os"
 	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/AlecAivazis/survey/v2/core"
@@ -29,15 +30,23 @@ import (
 )
 
 type Input struct {
-	Question string
-	Help     string
-	Options  []string
-	Default  interface{}
-	Required bool
+	Question   string
+	Help       string
+	Options    []string
+	Default    interface{}
+	Required   bool
+	Validators []Validator
+	// Flag is the name of the command line flag that can be used instead of answering the
+	// question, reported to the user when questions can't be asked.
+	Flag string
 }
 
 // Gets user input from the command line
 func GetInput(q string) (a string, err error) {
+	err = checkInput(Input{Question: q})
+	if err != nil {
+		return
+	}
 	prompt := &survey.Input{
 		Message: fmt.Sprintf("%s:", q),
 	}
@@ -47,6 +56,10 @@ func GetInput(q string) (a string, err error) {
 
 // Gets string input from the command line
 func GetString(input Input) (a string, err error) {
+	err = checkInput(input)
+	if err != nil {
+		return
+	}
 	dflt, ok := input.Default.(string)
 	if !ok {
 		dflt = ""
@@ -60,16 +73,16 @@ func GetString(input Input) (a string, err error) {
 		Help:    input.Help,
 		Default: dflt,
 	}
-	if input.Required {
-		err = survey.AskOne(prompt, &a, survey.WithValidator(survey.Required))
-		return
-	}
-	err = survey.AskOne(prompt, &a)
+	err = askOne(prompt, &a, input)
 	return
 }
 
 // Gets int number input from the command line
 func GetInt(input Input) (a int, err error) {
+	err = checkInput(input)
+	if err != nil {
+		return
+	}
 	dflt, ok := input.Default.(int)
 	if !ok {
 		dflt = 0
@@ -87,12 +100,8 @@ func GetInt(input Input) (a int, err error) {
 		Help:    input.Help,
 		Default: dfltStr,
 	}
-	if input.Required {
-		err = survey.AskOne(prompt, &a, survey.WithValidator(survey.Required))
-		return
-	}
 	var str string
-	err = survey.AskOne(prompt, &str)
+	err = askOne(prompt, &str, input, IntValidator)
 	if err != nil {
 		return
 	}
@@ -108,8 +117,11 @@ func parseInt(str string) (num int, err error) {
 
 // Asks for multiple options selection
 func GetMultipleOptions(input Input) ([]string, error) {
-	var err error
 	res := make([]string, 0)
+	err := checkInput(input)
+	if err != nil {
+		return res, err
+	}
 	dflt, ok := input.Default.([]string)
 	if !ok {
 		dflt = []string{}
@@ -125,16 +137,16 @@ func GetMultipleOptions(input Input) ([]string, error) {
 		Default: dflt,
 	}
 
-	if input.Required {
-		err = survey.AskOne(prompt, &res, survey.WithValidator(survey.Required))
-		return res, err
-	}
-	err = survey.AskOne(prompt, &res)
+	err = askOne(prompt, &res, input)
 	return res, err
 }
 
 // Asks for option selection in the command line
 func GetOption(input Input) (a string, err error) {
+	err = checkInput(input)
+	if err != nil {
+		return
+	}
 	dflt, ok := input.Default.(string)
 	if !ok {
 		dflt = ""
@@ -149,16 +161,16 @@ func GetOption(input Input) (a string, err error) {
 		Options: input.Options,
 		Default: dflt,
 	}
-	if input.Required {
-		err = survey.AskOne(prompt, &a, survey.WithValidator(survey.Required))
-		return
-	}
-	err = survey.AskOne(prompt, &a)
+	err = askOne(prompt, &a, input)
 	return
 }
 
 // Asks for true/false value in the command line
 func GetBool(input Input) (a bool, err error) {
+	err = checkInput(input)
+	if err != nil {
+		return
+	}
 	dflt, ok := input.Default.(bool)
 	if !ok {
 		dflt = false
@@ -172,16 +184,16 @@ func GetBool(input Input) (a bool, err error) {
 		Help:    input.Help,
 		Default: dflt,
 	}
-	if input.Required {
-		err = survey.AskOne(prompt, &a, survey.WithValidator(survey.Required))
-		return
-	}
-	err = survey.AskOne(prompt, &a)
+	err = askOne(prompt, &a, input)
 	return
 }
 
 // Asks for CIDR value in the command line
-func GetIPNet(input Input) (a net.IPNet, err error) {
+func GetCIDR(input Input) (a net.IPNet, err error) {
+	err = checkInput(input)
+	if err != nil {
+		return
+	}
 	dflt, ok := input.Default.(net.IPNet)
 	if !ok {
 		dflt = net.IPNet{}
@@ -199,16 +211,11 @@ func GetIPNet(input Input) (a net.IPNet, err error) {
 		Help:    input.Help,
 		Default: dfltStr,
 	}
-	if input.Required {
-		err = survey.AskOne(prompt, &a, survey.WithValidator(survey.Required))
-		return
-	}
 	var str string
-	err = survey.AskOne(prompt, &str)
+	err = askOne(prompt, &str, input, CIDRValidator)
 	if err != nil {
 		return
 	}
-
 	if str == "" {
 		return
 	}
@@ -222,8 +229,80 @@ func GetIPNet(input Input) (a net.IPNet, err error) {
 	return
 }
 
+// Asks for a date or time value with the given layout in the command line
+func GetDate(input Input, layout string) (a time.Time, err error) {
+	err = checkInput(input)
+	if err != nil {
+		return
+	}
+	dflt, ok := input.Default.(time.Time)
+	if !ok {
+		dflt = time.Time{}
+	}
+	dfltStr := ""
+	if !dflt.IsZero() {
+		dfltStr = dflt.Format(layout)
+	}
+	question := input.Question
+	if !input.Required && dfltStr == "" {
+		question = fmt.Sprintf("%s (optional)", question)
+	}
+	prompt := &survey.Input{
+		Message: fmt.Sprintf("%s:", question),
+		Help:    input.Help,
+		Default: dfltStr,
+	}
+	var str string
+	err = askOne(prompt, &str, input, DateValidator(layout))
+	if err != nil {
+		return
+	}
+	if str == "" {
+		return
+	}
+	return time.Parse(layout, str)
+}
+
+// Asks for a duration value, like '90m' or '2h', in the command line
+func GetDuration(input Input) (a time.Duration, err error) {
+	err = checkInput(input)
+	if err != nil {
+		return
+	}
+	dflt, ok := input.Default.(time.Duration)
+	if !ok {
+		dflt = 0
+	}
+	dfltStr := ""
+	if dflt != 0 {
+		dfltStr = dflt.String()
+	}
+	question := input.Question
+	if !input.Required && dfltStr == "" {
+		question = fmt.Sprintf("%s (optional)", question)
+	}
+	prompt := &survey.Input{
+		Message: fmt.Sprintf("%s:", question),
+		Help:    input.Help,
+		Default: dfltStr,
+	}
+	var str string
+	err = askOne(prompt, &str, input, DurationValidator)
+	if err != nil {
+		return
+	}
+	if str == "" {
+		return
+	}
+	return time.ParseDuration(str)
+}
+
 // Gets password input from the command line
 func GetPassword(input Input) (a string, err error) {
+	err = checkInput(input)
+	if err != nil {
+		return
+	}
 	question := input.Question
 	if !input.Required {
 		question = fmt.Sprintf("%s (optional)", question)
@@ -232,16 +311,16 @@ func GetPassword(input Input) (a string, err error) {
 		Message: fmt.Sprintf("%s:", question),
 		Help:    input.Help,
 	}
-	if input.Required {
-		err = survey.AskOne(prompt, &a, survey.WithValidator(survey.Required))
-		return
-	}
-	err = survey.AskOne(prompt, &a)
+	err = askOne(prompt, &a, input)
 	return
 }
 
 // Gets path to certificate file from the command line
 func GetCert(input Input) (a string, err error) {
+	err = checkInput(input)
+	if err != nil {
+		return
+	}
 	dflt, ok := input.Default.(string)
 	if !ok {
 		dflt = ""
@@ -255,12 +334,23 @@ func GetCert(input Input) (a string, err error) {
 		Help:    input.Help,
 		Default: dflt,
 	}
+	err = askOne(prompt, &a, input, certValidator)
+	return
+}
+
+// askOne asks the question of the prompt, checking that the answer is present if the input is
+// required, and validating it with the given validators and the validators of the input. Invalid
+// answers are rejected and the question is asked again.
+func askOne(prompt survey.Prompt, response interface{}, input Input, validators ...Validator) error {
+	opts := []survey.AskOpt{}
 	if input.Required {
-		err = survey.AskOne(prompt, &a, survey.WithValidator(survey.Required), survey.WithValidator(certValidator))
-		return
+		opts = append(opts, survey.WithValidator(survey.Required))
 	}
-	err = survey.AskOne(prompt, &a, survey.WithValidator(certValidator))
-	return
+	validators = append(validators, input.Validators...)
+	for _, validator := range validators {
+		opts = append(opts, survey.WithValidator(survey.Validator(validator)))
+	}
+	return survey.AskOne(prompt, response, opts...)
 }
 
 // certValidator validates whether the given filepath is a valid cert file
@@ -0,0 +1,13 @@
+package interactive_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestInteractive(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Interactive Suite")
+}
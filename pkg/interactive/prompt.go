@@ -0,0 +1,113 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to check if questions can be asked to the user, so that
+// commands running without a terminal, for example in CI, fail immediately instead of waiting for
+// answers that will never come.
+
+package interactive
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// MissingFlagsError is the error returned when values need to be asked to the user but that isn't
+// possible. It contains the names of the flags that need to be provided in the command line.
+type MissingFlagsError struct {
+	Reason string
+	Flags  []string
+}
+
+// Error is the implementation of the error interface.
+func (e *MissingFlagsError) Error() string {
+	flags := make([]string, len(e.Flags))
+	for i, flag := range e.Flags {
+		flags[i] = "--" + flag
+	}
+	return fmt.Sprintf("Unable to ask for missing values because %s, the following flags are "+
+		"required: %s", e.Reason, strings.Join(flags, ", "))
+}
+
+// ExitCode returns the exit code used by the commands that fail because of the missing flags.
+func (e *MissingFlagsError) ExitCode() int {
+	return rerrors.ExitCodeValidation
+}
+
+// CheckPrompt returns an error explaining why questions can't be asked to the user, or nil if they
+// can.
+func CheckPrompt() error {
+	if nonInteractive {
+		return errors.New("the '--non-interactive' flag was given")
+	}
+	if !isTerminal(os.Stdin) {
+		return errors.New("standard input is not a terminal")
+	}
+	return nil
+}
+
+// RequireFlags checks that the given flags were provided in the command line when questions can't
+// be asked to the user. It returns a MissingFlagsError listing the flags that are missing, or nil
+// if none are missing or if questions can be asked.
+func RequireFlags(flags *pflag.FlagSet, names ...string) error {
+	err := CheckPrompt()
+	if err == nil {
+		return nil
+	}
+	missing := []string{}
+	for _, name := range names {
+		if !flags.Changed(name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return &MissingFlagsError{
+		Reason: err.Error(),
+		Flags:  missing,
+	}
+}
+
+// checkInput returns an error if the question of the input can't be asked to the user.
+func checkInput(input Input) error {
+	err := CheckPrompt()
+	if err == nil {
+		return nil
+	}
+	if input.Flag == "" {
+		return fmt.Errorf("Unable to ask for '%s' because %v", input.Question, err)
+	}
+	return &MissingFlagsError{
+		Reason: err.Error(),
+		Flags:  []string{input.Flag},
+	}
+}
+
+// isTerminal checks if the given file is a character device, which is what terminals are.
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
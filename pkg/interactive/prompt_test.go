@@ -0,0 +1,53 @@
+package interactive_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/spf13/pflag"
+
+	. "github.com/openshift/moactl/pkg/interactive"
+)
+
+var _ = Describe("Non-interactive mode", func() {
+	var flags *pflag.FlagSet
+
+	BeforeEach(func() {
+		flags = pflag.NewFlagSet("test", pflag.ContinueOnError)
+		AddNonInteractiveFlag(flags)
+		flags.String("name", "", "")
+		flags.Int("replicas", 0, "")
+		Expect(flags.Parse([]string{"--non-interactive", "--name=mypool"})).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(flags.Set("non-interactive", "false")).To(Succeed())
+	})
+
+	It("Can't prompt", func() {
+		Expect(CheckPrompt()).To(MatchError("the '--non-interactive' flag was given"))
+	})
+
+	It("Lists the missing flags", func() {
+		err := RequireFlags(flags, "name", "replicas")
+		Expect(err).To(BeAssignableToTypeOf(&MissingFlagsError{}))
+		Expect(err.(*MissingFlagsError).Flags).To(Equal([]string{"replicas"}))
+		Expect(err.Error()).To(ContainSubstring("--replicas"))
+	})
+
+	It("Accepts flags that were provided", func() {
+		Expect(RequireFlags(flags, "name")).To(Succeed())
+	})
+
+	It("Fails instead of asking questions", func() {
+		_, err := GetString(Input{
+			Question: "Machine pool name",
+			Required: true,
+			Flag:     "name",
+		})
+		Expect(err).To(Equal(&MissingFlagsError{
+			Reason: "the '--non-interactive' flag was given",
+			Flags:  []string{"name"},
+		}))
+	})
+})
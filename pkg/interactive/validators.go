@@ -0,0 +1,119 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the validators that can be used to check the answers given by the user in
+// interactive mode, so that the question is asked again when the answer isn't valid.
+
+package interactive
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"time"
+)
+
+// Validator checks the answer given by the user. Empty answers should be accepted, as the
+// required check is done separately using the 'Required' field of the input.
+type Validator func(interface{}) error
+
+// RegExpValidator returns a validator that checks that the answer matches the given regular
+// expression.
+func RegExpValidator(pattern string) Validator {
+	re := regexp.MustCompile(pattern)
+	return func(val interface{}) error {
+		return validateString(val, func(s string) error {
+			if !re.MatchString(s) {
+				return fmt.Errorf("'%s' doesn't match the expected format '%s'", s, pattern)
+			}
+			return nil
+		})
+	}
+}
+
+// DateValidator returns a validator that checks that the answer is a date or time with the given
+// layout, as used by 'time.Parse'.
+func DateValidator(layout string) Validator {
+	return func(val interface{}) error {
+		return validateString(val, func(s string) error {
+			_, err := time.Parse(layout, s)
+			if err != nil {
+				return fmt.Errorf("'%s' doesn't have the expected format '%s'", s, layout)
+			}
+			return nil
+		})
+	}
+}
+
+// CIDRValidator checks that the answer is a CIDR notation IP address and prefix length, like
+// '10.0.0.0/16'.
+func CIDRValidator(val interface{}) error {
+	return validateString(val, func(s string) error {
+		_, _, err := net.ParseCIDR(s)
+		if err != nil {
+			return fmt.Errorf("'%s' isn't a valid CIDR", s)
+		}
+		return nil
+	})
+}
+
+// URLValidator checks that the answer is an absolute URL.
+func URLValidator(val interface{}) error {
+	return validateString(val, func(s string) error {
+		parsed, err := url.ParseRequestURI(s)
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return fmt.Errorf("'%s' isn't a valid URL", s)
+		}
+		return nil
+	})
+}
+
+// DurationValidator checks that the answer is a duration, like '90m' or '2h'.
+func DurationValidator(val interface{}) error {
+	return validateString(val, func(s string) error {
+		_, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("'%s' isn't a valid duration", s)
+		}
+		return nil
+	})
+}
+
+// IntValidator checks that the answer is an integer number.
+func IntValidator(val interface{}) error {
+	return validateString(val, func(s string) error {
+		_, err := parseInt(s)
+		if err != nil {
+			return fmt.Errorf("'%s' isn't a valid number", s)
+		}
+		return nil
+	})
+}
+
+func validateString(val interface{}, validate func(string) error) error {
+	if val == nil {
+		return nil
+	}
+	s, ok := val.(string)
+	if !ok {
+		return fmt.Errorf("can only validate strings, got %v", val)
+	}
+	if s == "" {
+		return nil
+	}
+	return validate(s)
+}
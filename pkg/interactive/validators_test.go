@@ -0,0 +1,40 @@
+package interactive_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/interactive"
+)
+
+var _ = Describe("Validators", func() {
+	DescribeTable("Accept valid answers",
+		func(validator Validator, answer interface{}) {
+			Expect(validator(answer)).To(Succeed())
+		},
+		Entry("Empty answer", CIDRValidator, ""),
+		Entry("Missing answer", CIDRValidator, nil),
+		Entry("Regular expression", RegExpValidator(`^[a-z]+$`), "mycluster"),
+		Entry("Date", DateValidator("2006-01-02"), "2020-11-05"),
+		Entry("Time", DateValidator("15:04"), "23:30"),
+		Entry("CIDR", CIDRValidator, "10.0.0.0/16"),
+		Entry("URL", URLValidator, "https://example.com/path"),
+		Entry("Duration", DurationValidator, "1h30m"),
+		Entry("Integer", IntValidator, "3"),
+	)
+
+	DescribeTable("Reject invalid answers",
+		func(validator Validator, answer interface{}) {
+			Expect(validator(answer)).NotTo(Succeed())
+		},
+		Entry("Not a string", CIDRValidator, 3),
+		Entry("Regular expression", RegExpValidator(`^[a-z]+$`), "my_cluster"),
+		Entry("Date", DateValidator("2006-01-02"), "05/11/2020"),
+		Entry("Time", DateValidator("15:04"), "25:00"),
+		Entry("CIDR", CIDRValidator, "10.0.0.0"),
+		Entry("URL without scheme", URLValidator, "example.com"),
+		Entry("Duration", DurationValidator, "2 hours"),
+		Entry("Integer", IntValidator, "three"),
+	)
+})
@@ -0,0 +1,196 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to read, merge and write kubeconfig files.
+
+package kubeconfig
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"gopkg.in/yaml.v2"
+)
+
+// Config is a kubeconfig file. Only the names of the entries and the references between them are
+// interpreted, the rest of the content is preserved as is.
+type Config struct {
+	APIVersion     string                 `yaml:"apiVersion"`
+	Kind           string                 `yaml:"kind"`
+	Preferences    map[string]interface{} `yaml:"preferences"`
+	Clusters       []*ClusterEntry        `yaml:"clusters"`
+	Users          []*UserEntry           `yaml:"users"`
+	Contexts       []*ContextEntry        `yaml:"contexts"`
+	CurrentContext string                 `yaml:"current-context"`
+	Extensions     []interface{}          `yaml:"extensions,omitempty"`
+}
+
+// ClusterEntry is a named cluster of a kubeconfig file.
+type ClusterEntry struct {
+	Name    string        `yaml:"name"`
+	Cluster yaml.MapSlice `yaml:"cluster"`
+}
+
+// UserEntry is a named user of a kubeconfig file.
+type UserEntry struct {
+	Name string        `yaml:"name"`
+	User yaml.MapSlice `yaml:"user"`
+}
+
+// ContextEntry is a named context of a kubeconfig file.
+type ContextEntry struct {
+	Name    string  `yaml:"name"`
+	Context Context `yaml:"context"`
+}
+
+// Context is a reference to the cluster and user of a kubeconfig file used together.
+type Context struct {
+	Cluster    string        `yaml:"cluster"`
+	User       string        `yaml:"user"`
+	Namespace  string        `yaml:"namespace,omitempty"`
+	Extensions []interface{} `yaml:"extensions,omitempty"`
+}
+
+// Parse parses the content of a kubeconfig file.
+func Parse(data []byte) (*Config, error) {
+	config := &Config{}
+	err := yaml.Unmarshal(data, config)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse kubeconfig: %v", err)
+	}
+	return config, nil
+}
+
+// Marshal returns the content of the kubeconfig file.
+func (c *Config) Marshal() ([]byte, error) {
+	return yaml.Marshal(c)
+}
+
+// Rename changes the names of the only cluster, user and context of the kubeconfig, so that it
+// doesn't collide with the kubeconfigs of other clusters when merged. The context is renamed to
+// the given name, the cluster to the same name and the user to the given user name. It fails if
+// the kubeconfig doesn't contain exactly one cluster, user and context.
+func (c *Config) Rename(name string, user string) error {
+	if len(c.Clusters) != 1 || len(c.Users) != 1 || len(c.Contexts) != 1 {
+		return errors.New("Expected kubeconfig to contain exactly one cluster, user and context")
+	}
+	c.Clusters[0].Name = name
+	c.Users[0].Name = user
+	c.Contexts[0].Name = name
+	c.Contexts[0].Context.Cluster = name
+	c.Contexts[0].Context.User = user
+	c.CurrentContext = name
+	return nil
+}
+
+// Merge adds the clusters, users and contexts of the other kubeconfig, replacing the existing
+// entries that have the same names, and switches to the current context of the other kubeconfig.
+func (c *Config) Merge(other *Config) {
+	for _, entry := range other.Clusters {
+		replaced := false
+		for i, existing := range c.Clusters {
+			if existing.Name == entry.Name {
+				c.Clusters[i] = entry
+				replaced = true
+			}
+		}
+		if !replaced {
+			c.Clusters = append(c.Clusters, entry)
+		}
+	}
+	for _, entry := range other.Users {
+		replaced := false
+		for i, existing := range c.Users {
+			if existing.Name == entry.Name {
+				c.Users[i] = entry
+				replaced = true
+			}
+		}
+		if !replaced {
+			c.Users = append(c.Users, entry)
+		}
+	}
+	for _, entry := range other.Contexts {
+		replaced := false
+		for i, existing := range c.Contexts {
+			if existing.Name == entry.Name {
+				c.Contexts[i] = entry
+				replaced = true
+			}
+		}
+		if !replaced {
+			c.Contexts = append(c.Contexts, entry)
+		}
+	}
+	if c.APIVersion == "" {
+		c.APIVersion = other.APIVersion
+	}
+	if c.Kind == "" {
+		c.Kind = other.Kind
+	}
+	if other.CurrentContext != "" {
+		c.CurrentContext = other.CurrentContext
+	}
+}
+
+// DefaultPath returns the path of the kubeconfig file used by default: the first file of the
+// KUBECONFIG environment variable, or '~/.kube/config' if it isn't set.
+func DefaultPath() (string, error) {
+	if value := os.Getenv("KUBECONFIG"); value != "" {
+		return filepath.SplitList(value)[0], nil
+	}
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".kube", "config"), nil
+}
+
+// Write saves the kubeconfig to the given path. If merge is true and the file already exists the
+// kubeconfig is merged into it, otherwise the file is replaced.
+func Write(path string, config *Config, merge bool) error {
+	if merge {
+		data, err := ioutil.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("Failed to read kubeconfig file '%s': %v", path, err)
+		}
+		if err == nil {
+			existing, err := Parse(data)
+			if err != nil {
+				return fmt.Errorf("Failed to read kubeconfig file '%s': %v", path, err)
+			}
+			existing.Merge(config)
+			config = existing
+		}
+	}
+	data, err := config.Marshal()
+	if err != nil {
+		return fmt.Errorf("Failed to marshal kubeconfig: %v", err)
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return fmt.Errorf("Failed to create directory for kubeconfig file '%s': %v", path, err)
+	}
+	err = ioutil.WriteFile(path, data, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to write kubeconfig file '%s': %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package kubeconfig_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/kubeconfig"
+)
+
+const clusterKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: mycluster
+  cluster:
+    server: https://api.mycluster.example.com:6443
+    certificate-authority-data: Y2VydGlmaWNhdGU=
+users:
+- name: admin
+  user:
+    client-certificate-data: Y2VydGlmaWNhdGU=
+    client-key-data: a2V5
+contexts:
+- name: admin
+  context:
+    cluster: mycluster
+    user: admin
+current-context: admin
+`
+
+const existingKubeconfig = `apiVersion: v1
+kind: Config
+preferences: {}
+clusters:
+- name: other
+  cluster:
+    server: https://api.other.example.com:6443
+users:
+- name: other-user
+  user:
+    token: secret
+contexts:
+- name: other
+  context:
+    cluster: other
+    user: other-user
+    namespace: default
+current-context: other
+`
+
+var _ = Describe("Kubeconfig", func() {
+	var config *kubeconfig.Config
+
+	BeforeEach(func() {
+		var err error
+		config, err = kubeconfig.Parse([]byte(clusterKubeconfig))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.Rename("mycluster", "admin/mycluster")).To(Succeed())
+	})
+
+	It("Renames the entries", func() {
+		Expect(config.Clusters[0].Name).To(Equal("mycluster"))
+		Expect(config.Users[0].Name).To(Equal("admin/mycluster"))
+		Expect(config.Contexts[0].Name).To(Equal("mycluster"))
+		Expect(config.Contexts[0].Context.Cluster).To(Equal("mycluster"))
+		Expect(config.Contexts[0].Context.User).To(Equal("admin/mycluster"))
+		Expect(config.CurrentContext).To(Equal("mycluster"))
+	})
+
+	It("Merges into an existing kubeconfig", func() {
+		existing, err := kubeconfig.Parse([]byte(existingKubeconfig))
+		Expect(err).NotTo(HaveOccurred())
+		existing.Merge(config)
+		Expect(existing.Clusters).To(HaveLen(2))
+		Expect(existing.Users).To(HaveLen(2))
+		Expect(existing.Contexts).To(HaveLen(2))
+		Expect(existing.Contexts[0].Context.Namespace).To(Equal("default"))
+		Expect(existing.CurrentContext).To(Equal("mycluster"))
+
+		// Merging again replaces the entries instead of duplicating them:
+		existing.Merge(config)
+		Expect(existing.Clusters).To(HaveLen(2))
+		Expect(existing.Users).To(HaveLen(2))
+		Expect(existing.Contexts).To(HaveLen(2))
+	})
+
+	Context("Write", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "kubeconfig")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("Creates the file and its directory", func() {
+			path := filepath.Join(dir, ".kube", "config")
+			Expect(kubeconfig.Write(path, config, true)).To(Succeed())
+			data, err := ioutil.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			written, err := kubeconfig.Parse(data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(written.Clusters).To(HaveLen(1))
+			Expect(written.Clusters[0].Cluster[0].Key).To(Equal("server"))
+		})
+
+		It("Merges into the existing file", func() {
+			path := filepath.Join(dir, "config")
+			Expect(ioutil.WriteFile(path, []byte(existingKubeconfig), 0600)).To(Succeed())
+			Expect(kubeconfig.Write(path, config, true)).To(Succeed())
+			data, err := ioutil.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			written, err := kubeconfig.Parse(data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(written.Contexts).To(HaveLen(2))
+			Expect(written.CurrentContext).To(Equal("mycluster"))
+		})
+
+		It("Replaces the existing file when not merging", func() {
+			path := filepath.Join(dir, "config")
+			Expect(ioutil.WriteFile(path, []byte(existingKubeconfig), 0600)).To(Succeed())
+			Expect(kubeconfig.Write(path, config, false)).To(Succeed())
+			data, err := ioutil.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			written, err := kubeconfig.Parse(data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(written.Contexts).To(HaveLen(1))
+		})
+	})
+})
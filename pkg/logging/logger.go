@@ -19,11 +19,10 @@ limitations under the License.
 package logging
 
 import (
-	"os"
-
 	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/moactl/pkg/debug"
+	"github.com/openshift/moactl/pkg/exit"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
 )
 
@@ -47,10 +46,12 @@ func (b *LoggerBuilder) Build() (result *logrus.Logger, err error) {
 		FullTimestamp: true,
 	})
 
-	// Enable the debug level if needed:
-	if debug.Enabled() {
-		result.SetLevel(logrus.DebugLevel)
+	// Set the level given in the command line:
+	level, err := debug.Level()
+	if err != nil {
+		return nil, err
 	}
+	result.SetLevel(level)
 
 	return
 }
@@ -62,7 +63,7 @@ func CreateLoggerOrExit(reporter *rprtr.Object) *logrus.Logger {
 	logger, err := NewLogger().Build()
 	if err != nil {
 		reporter.Errorf("Failed to create logger: %v", err)
-		os.Exit(1)
+		exit.Exit(1)
 	}
 	return logger
 }
@@ -72,8 +72,11 @@ func (b *OCMLoggerBuilder) Build() (result *OCMLogger, err error) {
 	return
 }
 
+// DebugEnabled always returns false, because the SDK uses it to decide if it dumps the details of
+// requests and responses. Those are dumped by the round tripper of this package instead, so that
+// OCM and AWS traces have the same format. Debug messages are still sent to the log.
 func (l *OCMLogger) DebugEnabled() bool {
-	return l.logger.IsLevelEnabled(logrus.DebugLevel)
+	return false
 }
 
 func (l *OCMLogger) InfoEnabled() bool {
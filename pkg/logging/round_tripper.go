@@ -27,8 +27,10 @@ import (
 	"mime"
 	"net/http"
 	"net/url"
+	"regexp"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"gitlab.com/c0b/go-ordered-json"
@@ -96,8 +98,11 @@ func (b *RoundTripperBuilder) Build() (result *RoundTripper, err error) {
 		return
 	}
 
-	// Copy the set of redactedReplacement fields:
+	// Copy the set of redactedReplacement fields, including the ones that are always redacted:
 	redact := make(map[string]bool)
+	for _, key := range defaultRedactedFields {
+		redact[key] = true
+	}
 	for key, value := range b.redact {
 		redact[key] = value
 	}
@@ -133,10 +138,14 @@ func (d *RoundTripper) RoundTrip(request *http.Request) (response *http.Response
 	}
 
 	// Call the next round tripper:
+	start := time.Now()
 	response, err = d.next.RoundTrip(request)
+	duration := time.Since(start)
 	if err != nil {
+		d.logger.Debugf("Request failed after %s: %v", duration, err)
 		return
 	}
+	d.logger.Debugf("Response received after %s", duration)
 
 	// Read the complete response body in memory, in order to send it the log, and replace it
 	// with a reader that reads it from memory:
@@ -174,7 +183,7 @@ func (d *RoundTripper) dumpRequest(request *http.Request, body []byte) {
 	for _, name := range names {
 		values := header[name]
 		for _, value := range values {
-			if strings.ToLower(name) == "authorization" {
+			if sensitiveHeaders[strings.ToLower(name)] {
 				d.logger.Debugf("Request header '%s' is omitted", name)
 			} else {
 				d.logger.Debugf("Request header '%s' is '%s'", name, value)
@@ -200,7 +209,11 @@ func (d *RoundTripper) dumpResponse(response *http.Response, body []byte) {
 	for _, name := range names {
 		values := header[name]
 		for _, value := range values {
-			d.logger.Debugf("Response header '%s' is '%s'", name, value)
+			if sensitiveHeaders[strings.ToLower(name)] {
+				d.logger.Debugf("Response header '%s' is omitted", name)
+			} else {
+				d.logger.Debugf("Response header '%s' is '%s'", name, value)
+			}
 		}
 	}
 	if body != nil {
@@ -230,6 +243,8 @@ func (d *RoundTripper) dumpBody(what string, header http.Header, body []byte) {
 		d.dumpForm(what, body)
 	case "application/json", "application/x-amz-json-1.0", "application/x-amz-json-1.1":
 		d.dumpJSON(what, body)
+	case "text/xml", "application/xml":
+		d.dumpXML(what, body)
 	default:
 		d.dumpBytes(what, body)
 	}
@@ -309,6 +324,19 @@ func (d *RoundTripper) dumpJSON(what string, data []byte) {
 	}
 }
 
+// dumpXML sends to the log the given XML document, as used by most of the AWS APIs, replacing the
+// content of the elements that contain security sensitive data.
+func (d *RoundTripper) dumpXML(what string, data []byte) {
+	redacted := xmlElementRE.ReplaceAllFunc(data, func(element []byte) []byte {
+		match := xmlElementRE.FindSubmatch(element)
+		if !d.redact[string(match[1])] {
+			return element
+		}
+		return []byte(fmt.Sprintf("<%s>%s</%s>", match[1], redactedReplacement, match[1]))
+	})
+	d.dumpBytes(what, redacted)
+}
+
 // dumpBytes dump the given data as an array of bytes.
 func (d *RoundTripper) dumpBytes(what string, data []byte) {
 	size := len(data)
@@ -322,7 +350,8 @@ func (d *RoundTripper) dumpBytes(what string, data []byte) {
 	}
 }
 
-// redactSensitive replaces sensitive fields within a response with redactionStr.
+// redactSensitive replaces sensitive fields within a response with redactionStr, including the
+// fields of nested objects.
 func (d *RoundTripper) redactSensitive(body *ordered.OrderedMap) {
 	iterator := body.EntriesIter()
 	for {
@@ -332,9 +361,46 @@ func (d *RoundTripper) redactSensitive(body *ordered.OrderedMap) {
 		}
 		if d.redact[pair.Key] {
 			body.Set(pair.Key, redactedReplacement)
+			continue
 		}
+		d.redactValue(pair.Value)
 	}
 }
 
+func (d *RoundTripper) redactValue(value interface{}) {
+	switch typed := value.(type) {
+	case *ordered.OrderedMap:
+		d.redactSensitive(typed)
+	case []interface{}:
+		for _, item := range typed {
+			d.redactValue(item)
+		}
+	}
+}
+
+// sensitiveHeaders are the lower case names of the headers whose values are never sent to the log:
+var sensitiveHeaders = map[string]bool{
+	"authorization":        true,
+	"cookie":               true,
+	"proxy-authorization":  true,
+	"set-cookie":           true,
+	"x-amz-security-token": true,
+}
+
+// defaultRedactedFields are the names of the fields of request and response bodies that contain
+// credentials, and are always redacted:
+var defaultRedactedFields = []string{
+	"access_token",
+	"client_secret",
+	"id_token",
+	"password",
+	"refresh_token",
+	"SecretAccessKey",
+	"SessionToken",
+}
+
+// xmlElementRE matches XML elements that contain only text, like '<Name>value</Name>':
+var xmlElementRE = regexp.MustCompile(`<([A-Za-z][A-Za-z0-9]*)>([^<]*)</([A-Za-z][A-Za-z0-9]*)>`)
+
 // String that replaces redactedReplacement fields in messages sent to the log:
 const redactedReplacement = "***"
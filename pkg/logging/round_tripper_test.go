@@ -0,0 +1,69 @@
+package logging_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+
+	. "github.com/openshift/moactl/pkg/logging"
+)
+
+var _ = Describe("RoundTripper", func() {
+	var (
+		output *bytes.Buffer
+		client *http.Client
+	)
+
+	send := func(contentType string, body string) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", contentType)
+			w.Header().Set("Set-Cookie", "session=secret-cookie")
+			_, _ = w.Write([]byte(body))
+		}))
+		defer server.Close()
+		request, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		Expect(err).NotTo(HaveOccurred())
+		request.Header.Set("Authorization", "Bearer secret-token")
+		response, err := client.Do(request)
+		Expect(err).NotTo(HaveOccurred())
+		_ = response.Body.Close()
+	}
+
+	BeforeEach(func() {
+		output = &bytes.Buffer{}
+		logger := logrus.New()
+		logger.SetOutput(output)
+		logger.SetLevel(logrus.DebugLevel)
+		dumper, err := NewRoundTripper().
+			Logger(logger).
+			Next(http.DefaultTransport).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+		client = &http.Client{Transport: dumper}
+	})
+
+	It("Omits sensitive headers and reports the duration", func() {
+		send("text/plain", "hello")
+		Expect(output.String()).NotTo(ContainSubstring("secret-token"))
+		Expect(output.String()).NotTo(ContainSubstring("secret-cookie"))
+		Expect(output.String()).To(ContainSubstring("Response received after"))
+	})
+
+	It("Redacts nested JSON fields", func() {
+		send("application/json", `{"items":[{"refresh_token":"secret-refresh","id":"123"}]}`)
+		Expect(output.String()).NotTo(ContainSubstring("secret-refresh"))
+		Expect(output.String()).To(ContainSubstring("123"))
+	})
+
+	It("Redacts XML elements", func() {
+		send("text/xml", "<AccessKey><AccessKeyId>AKIA</AccessKeyId>"+
+			"<SecretAccessKey>secret-key</SecretAccessKey></AccessKey>")
+		Expect(output.String()).NotTo(ContainSubstring("secret-key"))
+		Expect(strings.Count(output.String(), "AKIA")).To(Equal(1))
+	})
+})
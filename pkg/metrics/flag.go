@@ -0,0 +1,54 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to implement the '--metrics-file' and '--metrics-pushgateway'
+// command line options.
+
+package metrics
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// AddFlags adds the metrics flags to the given set of command line flags.
+func AddFlags(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&file,
+		"metrics-file",
+		"",
+		"Write a JSON report with the command name, duration, API call counts and exit status "+
+			"to the given file.",
+	)
+	flags.StringVar(
+		&pushgateway,
+		"metrics-pushgateway",
+		"",
+		"Push the command metrics to the Prometheus Pushgateway with the given URL, "+
+			"for example 'http://pushgateway.example.com:9091'.",
+	)
+}
+
+// Enabled returns a boolean flag that indicates if metrics are collected. Metrics are opt-in, they
+// are only collected when a metrics file or Pushgateway is given.
+func Enabled() bool {
+	return file != "" || pushgateway != ""
+}
+
+// file is the value of the '--metrics-file' command line option.
+var file string
+
+// pushgateway is the value of the '--metrics-pushgateway' command line option.
+var pushgateway string
@@ -0,0 +1,127 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to record the metrics of the execution of a command and to
+// write them to a local file or push them to a Prometheus Pushgateway.
+
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+)
+
+// Names of the APIs whose calls are counted:
+const (
+	OCMAPI = "ocm"
+	AWSAPI = "aws"
+)
+
+// Report contains the metrics of the execution of a command.
+type Report struct {
+	Command    string         `json:"command"`
+	StartTime  time.Time      `json:"start_time"`
+	Duration   float64        `json:"duration_seconds"`
+	APICalls   map[string]int `json:"api_calls"`
+	ExitStatus int            `json:"exit_status"`
+}
+
+// recorder collects the metrics of the command that is being executed.
+var recorder = struct {
+	sync.Mutex
+	command  string
+	start    time.Time
+	apiCalls map[string]int
+}{
+	start:    time.Now(),
+	apiCalls: map[string]int{},
+}
+
+// Start records the name of the command that is being executed and the time when it started.
+func Start(command string) {
+	recorder.Lock()
+	defer recorder.Unlock()
+	recorder.command = command
+	recorder.start = time.Now()
+	recorder.apiCalls = map[string]int{}
+}
+
+// CountAPICall increments the number of calls sent to the given API.
+func CountAPICall(api string) {
+	recorder.Lock()
+	defer recorder.Unlock()
+	recorder.apiCalls[api]++
+}
+
+// Snapshot returns the report of the command with the metrics collected so far and the given exit
+// status.
+func Snapshot(exitStatus int) *Report {
+	recorder.Lock()
+	defer recorder.Unlock()
+	apiCalls := map[string]int{
+		OCMAPI: 0,
+		AWSAPI: 0,
+	}
+	for api, count := range recorder.apiCalls {
+		apiCalls[api] = count
+	}
+	return &Report{
+		Command:    recorder.command,
+		StartTime:  recorder.start.UTC(),
+		Duration:   time.Since(recorder.start).Seconds(),
+		APICalls:   apiCalls,
+		ExitStatus: exitStatus,
+	}
+}
+
+// Flush writes the report of the command with the given exit status to the metrics file and pushes
+// it to the Pushgateway, if they have been configured. It does nothing if metrics aren't enabled.
+// It is called once, when the process exits.
+func Flush(exitStatus int) error {
+	if !Enabled() {
+		return nil
+	}
+	report := Snapshot(exitStatus)
+	if file != "" {
+		err := WriteFile(file, report)
+		if err != nil {
+			return err
+		}
+	}
+	if pushgateway != "" {
+		err := Push(pushgateway, report)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteFile writes the report to the given file in JSON format.
+func WriteFile(path string, report *Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Failed to marshal metrics report: %v", err)
+	}
+	err = ioutil.WriteFile(path, append(data, '\n'), 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to write metrics file '%s': %v", path, err)
+	}
+	return nil
+}
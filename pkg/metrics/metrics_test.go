@@ -0,0 +1,90 @@
+package metrics_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/metrics"
+)
+
+var _ = Describe("Metrics", func() {
+	BeforeEach(func() {
+		Start("create cluster")
+	})
+
+	It("Counts the API calls", func() {
+		CountAPICall(OCMAPI)
+		CountAPICall(OCMAPI)
+		CountAPICall(AWSAPI)
+		report := Snapshot(0)
+		Expect(report.Command).To(Equal("create cluster"))
+		Expect(report.APICalls).To(Equal(map[string]int{
+			OCMAPI: 2,
+			AWSAPI: 1,
+		}))
+		Expect(report.ExitStatus).To(BeZero())
+	})
+
+	It("Formats the report for the Pushgateway", func() {
+		report := &Report{
+			Command:    "create cluster",
+			StartTime:  time.Unix(1600000000, 0),
+			Duration:   1.5,
+			APICalls:   map[string]int{OCMAPI: 3, AWSAPI: 2},
+			ExitStatus: 1,
+		}
+		text := Format(report)
+		Expect(text).To(ContainSubstring("rosa_command_duration_seconds 1.5\n"))
+		Expect(text).To(ContainSubstring("rosa_command_exit_status 1\n"))
+		Expect(text).To(ContainSubstring("rosa_command_timestamp_seconds 1600000000\n"))
+		Expect(text).To(ContainSubstring("rosa_api_calls{api=\"aws\"} 2\nrosa_api_calls{api=\"ocm\"} 3\n"))
+	})
+
+	It("Writes the report to a file", func() {
+		dir, err := ioutil.TempDir("", "metrics")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "metrics.json")
+
+		Expect(WriteFile(path, Snapshot(1))).To(Succeed())
+		data, err := ioutil.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		report := &Report{}
+		Expect(json.Unmarshal(data, report)).To(Succeed())
+		Expect(report.Command).To(Equal("create cluster"))
+		Expect(report.ExitStatus).To(Equal(1))
+	})
+
+	It("Pushes the report grouped by command", func() {
+		var method, path, body string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method = r.Method
+			path = r.URL.Path
+			data, _ := ioutil.ReadAll(r.Body)
+			body = string(data)
+		}))
+		defer server.Close()
+
+		Expect(Push(server.URL, Snapshot(0))).To(Succeed())
+		Expect(method).To(Equal(http.MethodPut))
+		Expect(path).To(Equal("/metrics/job/rosa/command/create_cluster"))
+		Expect(body).To(ContainSubstring("rosa_command_exit_status 0\n"))
+	})
+
+	It("Fails if the Pushgateway rejects the report", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		Expect(Push(server.URL, Snapshot(0))).NotTo(Succeed())
+	})
+})
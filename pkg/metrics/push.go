@@ -0,0 +1,101 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to push the metrics of a command to a Prometheus
+// Pushgateway, using the text exposition format.
+
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// pushJob is the name of the job used to group the metrics in the Pushgateway.
+const pushJob = "rosa"
+
+// pushTimeout is the maximum time to wait for the Pushgateway to accept the metrics.
+const pushTimeout = 10 * time.Second
+
+// Push sends the report to the Pushgateway with the given URL. The metrics are grouped by job and
+// command, so that the metrics of different commands don't replace each other.
+func Push(gateway string, report *Report) error {
+	parsed, err := url.Parse(gateway)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return fmt.Errorf("Invalid Pushgateway URL '%s'", gateway)
+	}
+	address := fmt.Sprintf("%s/metrics/job/%s/command/%s",
+		strings.TrimRight(gateway, "/"), pushJob, url.PathEscape(groupingKey(report.Command)))
+
+	request, err := http.NewRequest(http.MethodPut, address, bytes.NewBufferString(Format(report)))
+	if err != nil {
+		return fmt.Errorf("Failed to create Pushgateway request: %v", err)
+	}
+	request.Header.Set("Content-Type", "text/plain; version=0.0.4")
+	client := &http.Client{
+		Timeout: pushTimeout,
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("Failed to push metrics to '%s': %v", gateway, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("Failed to push metrics to '%s': unexpected status code %d",
+			gateway, response.StatusCode)
+	}
+	return nil
+}
+
+// Format returns the metrics of the report in the Prometheus text exposition format. The command
+// isn't included as a label because it is part of the grouping key.
+func Format(report *Report) string {
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "# HELP rosa_command_duration_seconds Duration of the command.\n")
+	fmt.Fprintf(&buffer, "# TYPE rosa_command_duration_seconds gauge\n")
+	fmt.Fprintf(&buffer, "rosa_command_duration_seconds %g\n", report.Duration)
+	fmt.Fprintf(&buffer, "# HELP rosa_command_exit_status Exit status of the command.\n")
+	fmt.Fprintf(&buffer, "# TYPE rosa_command_exit_status gauge\n")
+	fmt.Fprintf(&buffer, "rosa_command_exit_status %d\n", report.ExitStatus)
+	fmt.Fprintf(&buffer, "# HELP rosa_command_timestamp_seconds Time when the command started.\n")
+	fmt.Fprintf(&buffer, "# TYPE rosa_command_timestamp_seconds gauge\n")
+	fmt.Fprintf(&buffer, "rosa_command_timestamp_seconds %d\n", report.StartTime.Unix())
+	fmt.Fprintf(&buffer, "# HELP rosa_api_calls Number of API calls sent by the command.\n")
+	fmt.Fprintf(&buffer, "# TYPE rosa_api_calls gauge\n")
+	apis := make([]string, 0, len(report.APICalls))
+	for api := range report.APICalls {
+		apis = append(apis, api)
+	}
+	sort.Strings(apis)
+	for _, api := range apis {
+		fmt.Fprintf(&buffer, "rosa_api_calls{api=%q} %d\n", api, report.APICalls[api])
+	}
+	return buffer.String()
+}
+
+// groupingKey converts the command name, for example 'create cluster', into a value that can be
+// used in the path of the Pushgateway, for example 'create_cluster'.
+func groupingKey(command string) string {
+	if command == "" {
+		return "rosa"
+	}
+	return strings.Join(strings.Fields(command), "_")
+}
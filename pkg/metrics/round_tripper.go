@@ -0,0 +1,49 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains an implementation of the http.RoundTripper interface that counts the requests
+// sent to an API.
+
+package metrics
+
+import (
+	"net/http"
+)
+
+// RoundTripper is a round tripper that counts the requests sent to an API. Don't create instances
+// of this type directly; use the NewRoundTripper function instead.
+type RoundTripper struct {
+	api  string
+	next http.RoundTripper
+}
+
+// NewRoundTripper creates a round tripper that counts the requests sent to the given API and then
+// passes them to the next round tripper.
+func NewRoundTripper(api string, next http.RoundTripper) *RoundTripper {
+	return &RoundTripper{
+		api:  api,
+		next: next,
+	}
+}
+
+// Make sure that we implement the http.RoundTripper interface:
+var _ http.RoundTripper = &RoundTripper{}
+
+// RoundTrip is the implementation of the http.RoundTripper interface.
+func (t *RoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	CountAPICall(t.api)
+	return t.next.RoundTrip(request)
+}
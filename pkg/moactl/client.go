@@ -0,0 +1,218 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package moactl is the client used to manage clusters from Go programs, like operators, without
+// running the command line tool and parsing its output. The commands of the tool use the same
+// client, so both behave the same way. Functions never exit the process and never prompt the user:
+// failures are returned as errors that can be inspected with the functions of the
+// 'github.com/openshift/moactl/pkg/errors' package, for example:
+//
+//	client, err := moactl.NewClient().Build()
+//	if err != nil {
+//		return err
+//	}
+//	defer client.Close()
+//	_, err = client.DeleteCluster(ctx, "mycluster", moactl.DeleteClusterOptions{})
+//	if errors.ExitCode(err) == errors.ExitCodeNotFound {
+//		// The cluster has already been deleted.
+//	}
+package moactl
+
+import (
+	"context"
+	"fmt"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/moactl/pkg/aws"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/ocm"
+)
+
+// ClientBuilder contains the information needed to create a client. Don't create instances of this
+// type directly; use the NewClient function instead.
+type ClientBuilder struct {
+	logger     *logrus.Logger
+	connection *sdk.Connection
+	awsClient  aws.Client
+	creatorARN string
+}
+
+// Client manages the clusters created by an AWS user. Don't create instances of this type
+// directly; use the NewClient function instead.
+type Client struct {
+	connection *sdk.Connection
+	creatorARN string
+
+	// Close the connection to OCM only if the client created it:
+	ownsConnection bool
+}
+
+// NewClient creates a builder that can then be used to configure and build a client.
+func NewClient() *ClientBuilder {
+	return &ClientBuilder{}
+}
+
+// Logger sets the logger used by the connections to OCM and AWS that the client creates. It is
+// optional, by default messages are written to the standard error.
+func (b *ClientBuilder) Logger(value *logrus.Logger) *ClientBuilder {
+	b.logger = value
+	return b
+}
+
+// Connection sets the connection to OCM. It is optional, by default a connection is created with
+// the credentials saved by 'rosa login'. Connections given to the builder aren't closed by the
+// client.
+func (b *ClientBuilder) Connection(value *sdk.Connection) *ClientBuilder {
+	b.connection = value
+	return b
+}
+
+// AWSClient sets the AWS client used to find the user that owns the clusters. It is optional, by
+// default a client is created with the AWS credentials of the environment.
+func (b *ClientBuilder) AWSClient(value aws.Client) *ClientBuilder {
+	b.awsClient = value
+	return b
+}
+
+// CreatorARN sets the ARN of the AWS user that owns the clusters, so that it doesn't need to be
+// requested to AWS.
+func (b *ClientBuilder) CreatorARN(value string) *ClientBuilder {
+	b.creatorARN = value
+	return b
+}
+
+// Build creates the client, and the connections to OCM and AWS that weren't given to the builder.
+func (b *ClientBuilder) Build() (*Client, error) {
+	logger := b.logger
+	if logger == nil {
+		var err error
+		logger, err = logging.NewLogger().Build()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create logger: %v", err)
+		}
+	}
+
+	creatorARN := b.creatorARN
+	if creatorARN == "" {
+		awsClient := b.awsClient
+		if awsClient == nil {
+			var err error
+			awsClient, err = aws.NewClient().
+				Logger(logger).
+				Build()
+			if err != nil {
+				return nil, rerrors.Errorf(rerrors.ExitCodeAWSAuth, "Failed to create AWS client: %v",
+					err)
+			}
+		}
+		creator, err := awsClient.GetCreator()
+		if err != nil {
+			return nil, rerrors.Errorf(rerrors.ExitCodeAWSAuth, "Failed to get AWS creator: %v", err)
+		}
+		creatorARN = creator.ARN
+	}
+
+	client := &Client{
+		connection: b.connection,
+		creatorARN: creatorARN,
+	}
+	if client.connection == nil {
+		connection, err := ocm.NewConnection().
+			Logger(logger).
+			Build()
+		if err != nil {
+			return nil, rerrors.Errorf(rerrors.ExitCodeOCMAuth, "Failed to create OCM connection: %v",
+				err)
+		}
+		client.connection = connection
+		client.ownsConnection = true
+	}
+	return client, nil
+}
+
+// Close releases the connection to OCM, if the client created it.
+func (c *Client) Close() error {
+	if !c.ownsConnection || c.connection == nil {
+		return nil
+	}
+	err := c.connection.Close()
+	if err != nil {
+		return fmt.Errorf("Failed to close OCM connection: %v", err)
+	}
+	c.connection = nil
+	return nil
+}
+
+// Connection returns the connection to OCM used by the client.
+func (c *Client) Connection() *sdk.Connection {
+	return c.connection
+}
+
+// CreatorARN returns the ARN of the AWS user that owns the clusters managed by the client.
+func (c *Client) CreatorARN() string {
+	return c.creatorARN
+}
+
+// clusters returns the client of the OCM collection of clusters.
+func (c *Client) clusters() *cmv1.ClustersClient {
+	return c.connection.ClustersMgmt().V1().Clusters()
+}
+
+// GetCluster returns the cluster with the given name, identifier or external identifier.
+func (c *Client) GetCluster(ctx context.Context, clusterKey string) (*cmv1.Cluster, error) {
+	err := validateClusterKey(clusterKey)
+	if err != nil {
+		return nil, err
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	cluster, err := ocm.GetCluster(c.clusters(), clusterKey, c.creatorARN)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get cluster '%s': %w", clusterKey, err)
+	}
+	return cluster, nil
+}
+
+// readyCluster returns the cluster with the given key, failing with a conflict error if it isn't
+// ready.
+func (c *Client) readyCluster(ctx context.Context, clusterKey string) (*cmv1.Cluster, error) {
+	cluster, err := c.GetCluster(ctx, clusterKey)
+	if err != nil {
+		return nil, err
+	}
+	if cluster.State() != cmv1.ClusterStateReady {
+		return nil, rerrors.ConflictErrorf("Cluster '%s' is not yet ready", clusterKey)
+	}
+	return cluster, nil
+}
+
+// validateClusterKey checks that the cluster key (name, identifier or external identifier) is
+// reasonably safe so that there is no risk of SQL injection.
+func validateClusterKey(clusterKey string) error {
+	if !ocm.IsValidClusterKey(clusterKey) {
+		return rerrors.ValidationErrorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+	}
+	return nil
+}
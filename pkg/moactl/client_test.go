@@ -0,0 +1,136 @@
+package moactl_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	. "github.com/openshift/moactl/pkg/moactl"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		server     *httptest.Server
+		requests   int
+		connection *sdk.Connection
+		client     *Client
+		ctx        context.Context
+	)
+
+	BeforeEach(func() {
+		// Validation errors must be returned before anything is sent to OCM:
+		requests = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"typ": "Bearer",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}).SignedString([]byte("secret"))
+		Expect(err).NotTo(HaveOccurred())
+		connection, err = sdk.NewConnectionBuilder().
+			URL(server.URL).
+			Tokens(token).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+		client, err = NewClient().
+			Connection(connection).
+			CreatorARN("arn:aws:iam::123456789012:user/myuser").
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		Expect(requests).To(BeZero())
+		Expect(client.Close()).To(Succeed())
+		Expect(connection.Close()).To(Succeed())
+		server.Close()
+	})
+
+	It("Uses the given connection and creator", func() {
+		Expect(client.Connection()).To(BeIdenticalTo(connection))
+		Expect(client.CreatorARN()).To(Equal("arn:aws:iam::123456789012:user/myuser"))
+	})
+
+	It("Rejects invalid cluster keys", func() {
+		_, err := client.GetCluster(ctx, "my cluster")
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+	})
+
+	It("Doesn't send requests when the context is cancelled", func() {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := client.GetCluster(cancelled, "mycluster")
+		Expect(err).To(MatchError(context.Canceled))
+	})
+
+	It("Rejects invalid cluster names", func() {
+		_, err := client.CreateCluster(ctx, clusterprovider.Spec{
+			Name: "My_Cluster",
+		})
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+	})
+
+	It("Rejects invalid usernames", func() {
+		_, err := client.GrantRole(ctx, UserRoleInput{
+			ClusterKey: "mycluster",
+			Role:       "dedicated-admins",
+			Usernames:  []string{"my:user"},
+		})
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+	})
+
+	It("Requires at least one user", func() {
+		_, err := client.RevokeRole(ctx, UserRoleInput{
+			ClusterKey: "mycluster",
+			Role:       "dedicated-admins",
+		})
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+	})
+
+	It("Rejects invalid machine pools", func() {
+		_, err := client.CreateMachinePool(ctx, MachinePoolInput{
+			ClusterKey: "mycluster",
+			ID:         "MP_1",
+		})
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+		_, err = client.CreateMachinePool(ctx, MachinePoolInput{
+			ClusterKey: "mycluster",
+			ID:         "mp-1",
+			Replicas:   -1,
+		})
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+	})
+
+	It("Doesn't load again the cluster given to schedule an upgrade", func() {
+		cluster, err := cmv1.NewCluster().
+			ID("123").
+			Name("mycluster").
+			State(cmv1.ClusterStateInstalling).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.ScheduleUpgrade(ctx, ScheduleUpgradeInput{
+			ClusterKey: "mycluster",
+			Cluster:    cluster,
+			Version:    "4.7.1",
+			NextRun:    time.Now().Add(time.Hour),
+		})
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeConflict))
+	})
+
+	It("Doesn't delete the default machine pool", func() {
+		err := client.DeleteMachinePool(ctx, "mycluster", "default")
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+	})
+})
@@ -0,0 +1,71 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the methods of the client that create and delete clusters.
+
+package moactl
+
+import (
+	"context"
+	"fmt"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// DeleteClusterOptions are the options of the deletion of a cluster.
+type DeleteClusterOptions struct {
+	// Delete the cluster even if its delete protection is enabled:
+	OverrideDeleteProtection bool
+}
+
+// CreateCluster creates a cluster with the given specification and returns it. The cluster is
+// returned as soon as OCM accepts it, while it is still being installed. The context is only
+// checked before sending the request, as interrupting it could leave the cluster half created.
+func (c *Client) CreateCluster(ctx context.Context, spec clusterprovider.Spec) (*cmv1.Cluster, error) {
+	if !clusterprovider.IsValidClusterName(spec.Name) {
+		return nil, rerrors.ValidationErrorf("Cluster name must consist of no more than 15 " +
+			"lowercase alphanumeric characters or '-', start with a letter, and end with an " +
+			"alphanumeric character.")
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return clusterprovider.CreateCluster(c.connection, spec)
+}
+
+// DeleteCluster starts the uninstallation of the cluster with the given name or identifier and
+// returns it. It fails with a conflict error if the delete protection of the cluster is enabled and
+// it isn't overridden.
+func (c *Client) DeleteCluster(ctx context.Context, clusterKey string,
+	options DeleteClusterOptions) (*cmv1.Cluster, error) {
+	cluster, err := c.GetCluster(ctx, clusterKey)
+	if err != nil {
+		return nil, err
+	}
+	err = clusterprovider.ValidateDelete(cluster, options.OverrideDeleteProtection)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.clusters().Cluster(cluster.ID()).Delete().SendContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to delete cluster '%s': %w",
+			clusterKey, rerrors.FromOCM(response.Error(), err))
+	}
+	return cluster, nil
+}
@@ -0,0 +1,179 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions of the client that manage the machine pools of a cluster.
+
+package moactl
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+)
+
+// Regular expression used to check the identifiers of machine pools:
+var machinePoolIDRE = regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
+
+// MachinePoolInput contains the details of a machine pool to add to a cluster.
+type MachinePoolInput struct {
+	ClusterKey   string
+	ID           string
+	InstanceType string
+	Replicas     int
+	Labels       map[string]string
+	Taints       []*cmv1.TaintBuilder
+
+	// KMSKeyARN is the customer managed key used to encrypt the EBS volumes of the nodes. It is
+	// optional, by default the volumes are encrypted with the key of the cluster.
+	KMSKeyARN string
+
+	// Spot is the spot instances configuration of the nodes. It is optional, by default the nodes
+	// use on-demand instances.
+	Spot *clusterprovider.Spot
+}
+
+// ListMachinePools returns the machine pools of the cluster, other than the default one.
+func (c *Client) ListMachinePools(ctx context.Context, clusterKey string) ([]*cmv1.MachinePool, error) {
+	cluster, err := c.GetCluster(ctx, clusterKey)
+	if err != nil {
+		return nil, err
+	}
+	machinePools, err := ocm.GetMachinePools(c.clusters(), cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get machine pools for cluster '%s': %w", clusterKey, err)
+	}
+	return machinePools, nil
+}
+
+// GetMachinePool returns the machine pool of the cluster with the given identifier, failing with a
+// not found error if it doesn't exist.
+func (c *Client) GetMachinePool(ctx context.Context, clusterKey string,
+	machinePoolID string) (*cmv1.MachinePool, error) {
+	err := validateMachinePoolID(machinePoolID)
+	if err != nil {
+		return nil, err
+	}
+	machinePools, err := c.ListMachinePools(ctx, clusterKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, machinePool := range machinePools {
+		if machinePool.ID() == machinePoolID {
+			return machinePool, nil
+		}
+	}
+	return nil, rerrors.NotFoundErrorf("Failed to get machine pool '%s' for cluster '%s'",
+		machinePoolID, clusterKey)
+}
+
+// CreateMachinePool adds a machine pool to the cluster, which must be ready.
+func (c *Client) CreateMachinePool(ctx context.Context, input MachinePoolInput) (*cmv1.MachinePool,
+	error) {
+	err := validateMachinePoolID(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	if input.Replicas < 0 {
+		return nil, rerrors.ValidationErrorf("Number of replicas of machine pool '%s' can't be "+
+			"negative", input.ID)
+	}
+	cluster, err := c.readyCluster(ctx, input.ClusterKey)
+	if err != nil {
+		return nil, err
+	}
+	machinePool, err := cmv1.NewMachinePool().
+		ID(input.ID).
+		Replicas(input.Replicas).
+		InstanceType(input.InstanceType).
+		Labels(input.Labels).
+		Taints(input.Taints...).
+		Build()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create machine pool for cluster '%s': %v", input.ClusterKey,
+			err)
+	}
+	err = clusterprovider.AddMachinePool(c.connection, cluster.ID(), machinePool, input.KMSKeyARN,
+		input.Spot)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to add machine pool to cluster '%s': %w", input.ClusterKey, err)
+	}
+	return machinePool, nil
+}
+
+// ScaleMachinePool changes the number of replicas of a machine pool other than the default one.
+func (c *Client) ScaleMachinePool(ctx context.Context, clusterKey string, machinePoolID string,
+	replicas int) error {
+	err := validateMachinePoolID(machinePoolID)
+	if err != nil {
+		return err
+	}
+	if replicas < 0 {
+		return rerrors.ValidationErrorf("Number of replicas of machine pool '%s' can't be negative",
+			machinePoolID)
+	}
+	cluster, err := c.readyCluster(ctx, clusterKey)
+	if err != nil {
+		return err
+	}
+	err = ocm.ScaleMachinePool(c.clusters(), cluster.ID(), machinePoolID, replicas)
+	if err != nil {
+		return fmt.Errorf("Failed to scale machine pool '%s' on cluster '%s': %w", machinePoolID,
+			clusterKey, err)
+	}
+	return nil
+}
+
+// DeleteMachinePool deletes a machine pool other than the default one from the cluster.
+func (c *Client) DeleteMachinePool(ctx context.Context, clusterKey string, machinePoolID string) error {
+	err := validateMachinePoolID(machinePoolID)
+	if err != nil {
+		return err
+	}
+	if machinePoolID == clusterprovider.DefaultMachinePool {
+		return rerrors.ValidationErrorf("Machine pool '%s' cannot be deleted from cluster '%s'",
+			machinePoolID, clusterKey)
+	}
+	cluster, err := c.GetCluster(ctx, clusterKey)
+	if err != nil {
+		return err
+	}
+	response, err := c.clusters().
+		Cluster(cluster.ID()).
+		MachinePools().
+		MachinePool(machinePoolID).
+		Delete().
+		SendContext(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed to delete machine pool '%s' on cluster '%s': %w", machinePoolID,
+			clusterKey, rerrors.FromOCM(response.Error(), err))
+	}
+	return nil
+}
+
+// validateMachinePoolID checks that the identifier of a machine pool is valid.
+func validateMachinePoolID(machinePoolID string) error {
+	if !machinePoolIDRE.MatchString(machinePoolID) {
+		return rerrors.ValidationErrorf("Expected a valid identifier for the machine pool, got '%s'",
+			machinePoolID)
+	}
+	return nil
+}
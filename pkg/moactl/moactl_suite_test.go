@@ -0,0 +1,13 @@
+package moactl_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestMoactl(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Moactl Suite")
+}
@@ -0,0 +1,268 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the methods of the client that schedule and cancel the upgrades of clusters.
+
+package moactl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	clusterprovider "github.com/openshift/moactl/pkg/cluster"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/upgrades"
+	"github.com/openshift/moactl/pkg/ocm/versions"
+)
+
+// ScheduleUpgradeInput describes the upgrade of a cluster.
+type ScheduleUpgradeInput struct {
+	// Name or identifier of the cluster:
+	ClusterKey string
+
+	// Cluster already loaded by the caller. When it is set the cluster isn't loaded again, and the
+	// cluster key is only used in messages:
+	Cluster *cmv1.Cluster
+
+	// Channel group that the cluster is moved to before the upgrade is scheduled, or empty to keep
+	// the current one:
+	ChannelGroup string
+
+	// Version to upgrade to, which must be one of the available upgrades of the cluster in its
+	// channel group:
+	Version string
+
+	// Time when the upgrade starts:
+	NextRun time.Time
+
+	// Node drain grace period in minutes, or nil to keep the current one:
+	NodeDrainGracePeriod *float64
+
+	// Acknowledge all the version gates of the version instead of failing when they aren't:
+	AcknowledgeVersionGates bool
+
+	// Identifiers of the version gates that the user agreed with. They are acknowledged, and any
+	// other gate that isn't acknowledged yet fails the upgrade unless AcknowledgeVersionGates is
+	// set:
+	AcknowledgedVersionGates []string
+
+	// Schedule the upgrade even if it overlaps the maintenance window of an automatic upgrade:
+	Force bool
+}
+
+// AvailableUpgrades returns the versions that the cluster can be upgraded to in the given channel
+// group, or in its current channel group if it is empty.
+func (c *Client) AvailableUpgrades(ctx context.Context, cluster *cmv1.Cluster,
+	channelGroup string) ([]string, error) {
+	if channelGroup == "" {
+		channelGroup = cluster.Version().ChannelGroup()
+	}
+	availableUpgrades, err := versions.GetAvailableUpgrades(c.connection.ClustersMgmt().V1(),
+		versions.GetVersionIDInChannelGroup(cluster, channelGroup))
+	if rerrors.ExitCode(err) == rerrors.ExitCodeNotFound {
+		return nil, rerrors.NotFoundErrorf("Version %s of cluster '%s' isn't available in channel "+
+			"group '%s'", cluster.OpenshiftVersion(), cluster.Name(), channelGroup)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to find available upgrades of cluster '%s': %w",
+			cluster.Name(), err)
+	}
+	return availableUpgrades, nil
+}
+
+// ScheduleUpgrade schedules the upgrade of a cluster and returns the upgrade policy that runs it.
+// It fails with a validation error if the cluster has a hosted control plane, and with a conflict
+// error if the cluster already has a scheduled upgrade, if the version gates of the version aren't
+// acknowledged, or if the upgrade overlaps the maintenance window of an automatic upgrade and it
+// isn't forced. All the checks are done before anything is changed.
+func (c *Client) ScheduleUpgrade(ctx context.Context,
+	input ScheduleUpgradeInput) (*cmv1.UpgradePolicy, error) {
+	ocmClient := c.connection.ClustersMgmt().V1()
+	cluster := input.Cluster
+	if cluster == nil {
+		var err error
+		cluster, err = c.readyCluster(ctx, input.ClusterKey)
+		if err != nil {
+			return nil, err
+		}
+	} else if cluster.State() != cmv1.ClusterStateReady {
+		return nil, rerrors.ConflictErrorf("Cluster '%s' is not yet ready", input.ClusterKey)
+	}
+
+	// The control plane and the machine pools of clusters with hosted control planes are upgraded
+	// independently, not with upgrade policies:
+	hosted, err := clusterprovider.IsHostedControlPlane(c.connection, cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get cluster '%s': %w", input.ClusterKey, err)
+	}
+	if hosted {
+		return nil, rerrors.ValidationErrorf("Cluster '%s' has a hosted control plane, its control "+
+			"plane and machine pools are upgraded separately", input.ClusterKey)
+	}
+
+	upgradePolicies, err := upgrades.GetUpgradePolicies(ocmClient, cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get scheduled upgrades for cluster '%s': %w",
+			input.ClusterKey, err)
+	}
+	if scheduled := upgrades.FindScheduledUpgrade(upgradePolicies); scheduled != nil {
+		return nil, rerrors.ConflictErrorf("There is already a scheduled upgrade of cluster '%s' "+
+			"to version %s on %s", input.ClusterKey, scheduled.Version(),
+			scheduled.NextRun().Format("2006-01-02 15:04 MST"))
+	}
+
+	channelGroup := cluster.Version().ChannelGroup()
+	if input.ChannelGroup != "" && input.ChannelGroup != channelGroup {
+		err = versions.ValidateAvailableChannelGroup(ocmClient, input.ChannelGroup)
+		if err != nil {
+			return nil, err
+		}
+		channelGroup = input.ChannelGroup
+	}
+	availableUpgrades, err := c.AvailableUpgrades(ctx, cluster, channelGroup)
+	if err != nil {
+		return nil, err
+	}
+	err = upgrades.ValidateVersion(input.Version, availableUpgrades)
+	if err != nil {
+		return nil, rerrors.ValidationErrorf("Version '%s' isn't an available upgrade of cluster "+
+			"'%s' in channel group '%s', expected one of: %s", input.Version, input.ClusterKey,
+			channelGroup, strings.Join(availableUpgrades, ", "))
+	}
+
+	nodeDrainMinutes := cluster.NodeDrainGracePeriod().Value()
+	if input.NodeDrainGracePeriod != nil {
+		nodeDrainMinutes = *input.NodeDrainGracePeriod
+	}
+	err = upgrades.ValidateNextRun(input.NextRun, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	err = upgrades.CheckConflicts(upgradePolicies, input.NextRun,
+		upgrades.UpgradeWindow(nodeDrainMinutes))
+	if err != nil && !input.Force {
+		return nil, err
+	}
+
+	gates, err := c.missingVersionGates(cluster, input.Version, input.AcknowledgeVersionGates,
+		input.AcknowledgedVersionGates)
+	if err != nil {
+		return nil, err
+	}
+
+	if channelGroup != cluster.Version().ChannelGroup() {
+		clusterSpec, err := versions.ChannelGroupSpec(channelGroup)
+		if err != nil {
+			return nil, err
+		}
+		response, err := ocmClient.Clusters().
+			Cluster(cluster.ID()).
+			Update().
+			Body(clusterSpec).
+			SendContext(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to move cluster '%s' to channel group '%s': %w",
+				input.ClusterKey, channelGroup, rerrors.FromOCM(response.Error(), err))
+		}
+	}
+
+	// The node drain grace period is updated before the upgrade is scheduled, so that the last
+	// operation sent to OCM is the one that schedules the upgrade:
+	if input.NodeDrainGracePeriod != nil {
+		clusterSpec, err := upgrades.NodeDrainGracePeriodSpec(*input.NodeDrainGracePeriod)
+		if err != nil {
+			return nil, err
+		}
+		response, err := ocmClient.Clusters().
+			Cluster(cluster.ID()).
+			Update().
+			Body(clusterSpec).
+			SendContext(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to update cluster '%s': %w",
+				input.ClusterKey, rerrors.FromOCM(response.Error(), err))
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	err = upgrades.AckVersionGates(c.connection, cluster.ID(), gates)
+	if err != nil {
+		return nil, err
+	}
+	err = upgrades.ScheduleUpgrade(ocmClient, cluster.ID(), input.Version, input.NextRun)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to schedule upgrade for cluster '%s': %w", input.ClusterKey,
+			err)
+	}
+
+	scheduled, err := upgrades.GetScheduledUpgrade(ocmClient, cluster.ID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get scheduled upgrade for cluster '%s': %w",
+			input.ClusterKey, err)
+	}
+	return scheduled, nil
+}
+
+// CancelUpgrade cancels the scheduled upgrade of the cluster. It returns false if the cluster
+// doesn't have a scheduled upgrade.
+func (c *Client) CancelUpgrade(ctx context.Context, clusterKey string) (bool, error) {
+	cluster, err := c.GetCluster(ctx, clusterKey)
+	if err != nil {
+		return false, err
+	}
+	canceled, err := upgrades.CancelUpgrade(c.connection.ClustersMgmt().V1(), cluster.ID())
+	if err != nil {
+		return false, fmt.Errorf("Failed to cancel scheduled upgrade on cluster '%s': %w",
+			clusterKey, err)
+	}
+	return canceled, nil
+}
+
+// missingVersionGates returns the version gates of the upgrade of the cluster to the given version
+// that aren't acknowledged yet, or fails if some of them aren't in the list of gates that the user
+// agreed with and acknowledging all of them isn't allowed. Unlike the command line, the user is
+// never asked.
+func (c *Client) missingVersionGates(cluster *cmv1.Cluster, version string, acknowledge bool,
+	acknowledged []string) ([]*upgrades.VersionGate, error) {
+	missingGates, err := upgrades.GetMissingGateAgreements(c.connection, cluster, version)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to check version gates for version %s: %v", version, err)
+	}
+	if acknowledge {
+		return missingGates, nil
+	}
+	agreed := map[string]bool{}
+	for _, id := range acknowledged {
+		agreed[id] = true
+	}
+	var labels []string
+	for _, gate := range missingGates {
+		if !agreed[gate.ID] {
+			labels = append(labels, gate.Label)
+		}
+	}
+	if len(labels) > 0 {
+		return nil, rerrors.ConflictErrorf("Upgrading to version %s requires acknowledging the "+
+			"following version gates: %s", version, strings.Join(labels, ", "))
+	}
+	return missingGates, nil
+}
@@ -0,0 +1,165 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions of the client that manage the users of the groups of a cluster.
+
+package moactl
+
+import (
+	"context"
+	"fmt"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	"github.com/openshift/moactl/pkg/ocm/users"
+)
+
+// UserRoleInput contains the users to grant or revoke a role of a cluster.
+type UserRoleInput struct {
+	ClusterKey string
+
+	// Role is the name of one of the groups of the cluster. The singular form of the name is also
+	// accepted, for example 'cluster-admin' for 'cluster-admins'.
+	Role string
+
+	Usernames []string
+}
+
+// UserRoleResult is the outcome of granting or revoking a role to one of the users.
+type UserRoleResult struct {
+	Username string
+	Err      error
+}
+
+// UserRoleOutput contains the group that the role resolved to and the outcome for each user, in
+// the same order as the input.
+type UserRoleOutput struct {
+	Group   string
+	Results []UserRoleResult
+}
+
+// Failed returns the number of users that the role couldn't be granted or revoked to.
+func (o *UserRoleOutput) Failed() int {
+	failed := 0
+	for _, result := range o.Results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// ResolveRole returns the identifier of the group of the cluster that corresponds to the given
+// role. It fails with a validation error if the cluster doesn't have that group.
+func (c *Client) ResolveRole(ctx context.Context, clusterKey string, role string) (string, error) {
+	cluster, err := c.GetCluster(ctx, clusterKey)
+	if err != nil {
+		return "", err
+	}
+	return c.resolveRole(cluster, clusterKey, role)
+}
+
+// GrantRole adds the users to the group of the cluster that corresponds to the role. The cluster
+// must be ready. Failures to add individual users don't stop the rest, and are reported in the
+// results of the output.
+func (c *Client) GrantRole(ctx context.Context, input UserRoleInput) (*UserRoleOutput, error) {
+	err := validateUsernames(input.Usernames)
+	if err != nil {
+		return nil, err
+	}
+	cluster, err := c.readyCluster(ctx, input.ClusterKey)
+	if err != nil {
+		return nil, err
+	}
+	group, err := c.resolveRole(cluster, input.ClusterKey, input.Role)
+	if err != nil {
+		return nil, err
+	}
+	output := &UserRoleOutput{
+		Group: group,
+	}
+	for _, username := range input.Usernames {
+		if ctx.Err() != nil {
+			return output, ctx.Err()
+		}
+		err = users.AddUser(c.clusters(), cluster.ID(), group, username)
+		output.Results = append(output.Results, UserRoleResult{
+			Username: username,
+			Err:      err,
+		})
+	}
+	return output, nil
+}
+
+// RevokeRole removes the users from the group of the cluster that corresponds to the role.
+// Failures to remove individual users don't stop the rest, and are reported in the results of the
+// output.
+func (c *Client) RevokeRole(ctx context.Context, input UserRoleInput) (*UserRoleOutput, error) {
+	err := validateUsernames(input.Usernames)
+	if err != nil {
+		return nil, err
+	}
+	cluster, err := c.GetCluster(ctx, input.ClusterKey)
+	if err != nil {
+		return nil, err
+	}
+	group, err := c.resolveRole(cluster, input.ClusterKey, input.Role)
+	if err != nil {
+		return nil, err
+	}
+	output := &UserRoleOutput{
+		Group: group,
+	}
+	for _, username := range input.Usernames {
+		if ctx.Err() != nil {
+			return output, ctx.Err()
+		}
+		err = users.RemoveUser(c.clusters(), cluster.ID(), group, username)
+		output.Results = append(output.Results, UserRoleResult{
+			Username: username,
+			Err:      err,
+		})
+	}
+	return output, nil
+}
+
+// resolveRole returns the identifier of the group of the cluster that corresponds to the role.
+func (c *Client) resolveRole(cluster *cmv1.Cluster, clusterKey string, role string) (string, error) {
+	groups, err := ocm.GetGroups(c.clusters(), cluster.ID())
+	if err != nil {
+		return "", fmt.Errorf("Failed to get groups of cluster '%s': %w", clusterKey, err)
+	}
+	return users.ResolveGroup(role, users.GroupIDs(groups))
+}
+
+// validateUsernames checks that there is at least one username and that all of them are valid.
+func validateUsernames(usernames []string) error {
+	if len(usernames) == 0 {
+		return rerrors.ValidationErrorf("Expected at least one user")
+	}
+	for _, username := range usernames {
+		if !ocm.IsValidUsername(username) {
+			return rerrors.ValidationErrorf(
+				"username '%s' isn't valid: it must contain only letters, digits, dashes and "+
+					"underscores",
+				username,
+			)
+		}
+	}
+	return nil
+}
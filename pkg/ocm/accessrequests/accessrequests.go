@@ -0,0 +1,125 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to list the requests of Red Hat SREs to access the
+// resources of a cluster, so that the owner can review them.
+
+package accessrequests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// accessRequestsPath is the path of the collection of access requests. The version of the SDK that
+// we use doesn't support access requests yet, so the requests are sent directly.
+const accessRequestsPath = "/api/access_transparency/v1/access_requests"
+
+// States of an access request:
+const (
+	StatePending  = "Pending"
+	StateApproved = "Approved"
+	StateDenied   = "Denied"
+	StateExpired  = "Expired"
+)
+
+// AccessRequest is the request of a Red Hat SRE to access the resources of a cluster for a limited
+// time, usually to work on a support case.
+type AccessRequest struct {
+	ID            string    `json:"id"`
+	ClusterID     string    `json:"cluster_id"`
+	RequestedBy   string    `json:"requested_by"`
+	Justification string    `json:"justification"`
+	SupportCaseID string    `json:"support_case_id"`
+	Duration      string    `json:"duration"`
+	Deadline      time.Time `json:"deadline"`
+	CreatedAt     time.Time `json:"created_at"`
+	Status        struct {
+		State string `json:"state"`
+	} `json:"status"`
+}
+
+// State returns the state of the access request.
+func (a *AccessRequest) State() string {
+	return a.Status.State
+}
+
+// GetAccessRequests returns the access requests of the cluster with the given identifier, the most
+// recent first.
+func GetAccessRequests(ctx context.Context, connection *sdk.Connection,
+	clusterID string) ([]*AccessRequest, error) {
+	response, err := connection.Get().
+		Path(accessRequestsPath).
+		Parameter("search", fmt.Sprintf("cluster_id = '%s'", clusterID)).
+		Parameter("order", "created_at desc").
+		Parameter("size", -1).
+		SendContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = checkResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	return UnmarshalAccessRequests(response.Bytes())
+}
+
+// FilterPending returns the access requests that are waiting for a decision.
+func FilterPending(accessRequests []*AccessRequest) []*AccessRequest {
+	result := []*AccessRequest{}
+	for _, accessRequest := range accessRequests {
+		if accessRequest.State() == StatePending {
+			result = append(result, accessRequest)
+		}
+	}
+	return result
+}
+
+// UnmarshalAccessRequests parses the body of a response that contains a list of access requests.
+func UnmarshalAccessRequests(data []byte) ([]*AccessRequest, error) {
+	var list struct {
+		Items []*AccessRequest `json:"items"`
+	}
+	err := json.Unmarshal(data, &list)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse access requests: %v", err)
+	}
+	if list.Items == nil {
+		return []*AccessRequest{}, nil
+	}
+	return list.Items, nil
+}
+
+// checkResponse converts the error returned by the OCM API, if any, so that it can be reported with
+// the right exit code.
+func checkResponse(response *sdk.Response) error {
+	if response.Status() < http.StatusBadRequest {
+		return nil
+	}
+	res, err := ocmerrors.UnmarshalError(response.Bytes())
+	if err != nil {
+		return fmt.Errorf("Unexpected status code %d", response.Status())
+	}
+	return rerrors.FromOCM(res, res)
+}
@@ -0,0 +1,13 @@
+package accessrequests_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestAccessRequests(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Access Requests Suite")
+}
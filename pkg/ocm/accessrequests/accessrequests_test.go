@@ -0,0 +1,52 @@
+package accessrequests_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/ocm/accessrequests"
+)
+
+var _ = Describe("Access requests", func() {
+	It("Parses the list of access requests", func() {
+		list, err := accessrequests.UnmarshalAccessRequests([]byte(`{
+			"kind": "AccessRequestList",
+			"items": [
+				{
+					"id": "1a2b",
+					"cluster_id": "123",
+					"requested_by": "sre@redhat.com",
+					"justification": "Investigate failing ingress",
+					"support_case_id": "0301",
+					"duration": "8h",
+					"deadline": "2021-03-01T12:00:00Z",
+					"status": {"state": "Pending"}
+				},
+				{
+					"id": "3c4d",
+					"cluster_id": "123",
+					"requested_by": "sre@redhat.com",
+					"duration": "1h",
+					"status": {"state": "Approved"}
+				}
+			]
+		}`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(list).To(HaveLen(2))
+		Expect(list[0].RequestedBy).To(Equal("sre@redhat.com"))
+		Expect(list[0].Deadline).To(Equal(time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)))
+		Expect(list[1].State()).To(Equal(accessrequests.StateApproved))
+
+		pending := accessrequests.FilterPending(list)
+		Expect(pending).To(HaveLen(1))
+		Expect(pending[0].ID).To(Equal("1a2b"))
+	})
+
+	It("Parses an empty list", func() {
+		list, err := accessrequests.UnmarshalAccessRequests([]byte(`{"kind": "AccessRequestList"}`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(list).To(BeEmpty())
+	})
+})
@@ -0,0 +1,225 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to find, install and uninstall the add-ons of clusters.
+
+package addons
+
+import (
+	"fmt"
+	"net/http"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	amsv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// NotInstalled is the state of the add-ons that aren't installed on the cluster.
+const NotInstalled = "not installed"
+
+// clustersPath is the path of the collection of clusters, used for the requests that the version
+// of the SDK that we use doesn't support yet.
+const clustersPath = "/api/clusters_mgmt/v1/clusters"
+
+// ClusterAddOn is an add-on that is available for a cluster, together with the state of its
+// installation.
+type ClusterAddOn struct {
+	ID        string
+	Name      string
+	State     string
+	Available bool
+}
+
+// GetAddOn loads the add-on with the given identifier.
+func GetAddOn(client *cmv1.AddOnsClient, id string) (*cmv1.AddOn, error) {
+	response, err := client.Addon(id).Get().Send()
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Body(), nil
+}
+
+// GetClusterAddOns returns the enabled add-ons that the organization of the user has quota for,
+// with the state of their installation on the given cluster.
+func GetClusterAddOns(connection *sdk.Connection, clusterID string) ([]*ClusterAddOn, error) {
+	// Get organization ID (used to get add-on quotas)
+	acctResponse, err := connection.AccountsMgmt().V1().CurrentAccount().
+		Get().
+		Send()
+	if err != nil {
+		return nil, handleErr(acctResponse.Error(), err)
+	}
+	organization := acctResponse.Body().Organization().ID()
+
+	// Get a list of add-on quotas for the current organization
+	resourceQuotasResponse, err := connection.AccountsMgmt().V1().Organizations().
+		Organization(organization).
+		ResourceQuota().
+		List().
+		Search("resource_type='addon'").
+		Page(1).
+		Size(-1).
+		Send()
+	if err != nil {
+		return nil, handleErr(resourceQuotasResponse.Error(), err)
+	}
+	resourceQuotas := resourceQuotasResponse.Items()
+
+	// Get complete list of enabled add-ons
+	addOnsResponse, err := connection.ClustersMgmt().V1().Addons().
+		List().
+		Search("enabled='t'").
+		Page(1).
+		Size(-1).
+		Send()
+	if err != nil {
+		return nil, handleErr(addOnsResponse.Error(), err)
+	}
+	addOns := addOnsResponse.Items()
+
+	// Get add-ons already installed on cluster
+	addOnInstallations, err := GetAddOnInstallations(connection.ClustersMgmt().V1().Clusters(), clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	var clusterAddOns []*ClusterAddOn
+
+	// Populate add-on installations with all add-on metadata
+	addOns.Each(func(addOn *cmv1.AddOn) bool {
+		clusterAddOn := ClusterAddOn{
+			ID:        addOn.ID(),
+			Name:      addOn.Name(),
+			State:     NotInstalled,
+			Available: addOn.ResourceCost() == 0,
+		}
+
+		// Only display add-ons for which the org has quota
+		resourceQuotas.Each(func(resourceQuota *amsv1.ResourceQuota) bool {
+			if addOn.ResourceName() == resourceQuota.ResourceName() {
+				clusterAddOn.Available = float64(resourceQuota.Allowed()) >= addOn.ResourceCost()
+			}
+			return true
+		})
+
+		// Get the state of add-on installations on the cluster
+		for _, addOnInstallation := range addOnInstallations {
+			if addOn.ID() == addOnInstallation.Addon().ID() {
+				clusterAddOn.State = InstallationState(addOnInstallation)
+			}
+		}
+
+		// Only display add-ons that meet the above criteria
+		if clusterAddOn.Available {
+			clusterAddOns = append(clusterAddOns, &clusterAddOn)
+		}
+		return true
+	})
+
+	return clusterAddOns, nil
+}
+
+// GetAddOnInstallations returns the add-ons installed on the cluster.
+func GetAddOnInstallations(client *cmv1.ClustersClient, clusterID string) ([]*cmv1.AddOnInstallation,
+	error) {
+	response, err := client.Cluster(clusterID).
+		Addons().
+		List().
+		Page(1).
+		Size(-1).
+		Send()
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Items().Slice(), nil
+}
+
+// GetAddOnInstallation returns the installation of the add-on on the cluster, or nil if the add-on
+// isn't installed.
+func GetAddOnInstallation(client *cmv1.ClustersClient, clusterID string,
+	addOnID string) (*cmv1.AddOnInstallation, error) {
+	response, err := client.Cluster(clusterID).Addons().Addoninstallation(addOnID).Get().Send()
+	if response != nil && response.Status() == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Body(), nil
+}
+
+// InstallationState returns the state of the installation of an add-on. Installations that have
+// just been requested don't have a state yet, so they are reported as installing.
+func InstallationState(addOnInstallation *cmv1.AddOnInstallation) string {
+	if addOnInstallation == nil {
+		return NotInstalled
+	}
+	state := string(addOnInstallation.State())
+	if state == "" {
+		state = string(cmv1.AddOnInstallationStateInstalling)
+	}
+	return state
+}
+
+// InstallAddOn requests the installation of the add-on on the cluster, with the given values of
+// its parameters.
+func InstallAddOn(client *cmv1.ClustersClient, clusterID string, addOnID string,
+	params map[string]string) error {
+	installationParams := []*cmv1.AddOnInstallationParameterBuilder{}
+	for _, id := range sortedKeys(params) {
+		installationParams = append(installationParams,
+			cmv1.NewAddOnInstallationParameter().ID(id).Value(params[id]))
+	}
+	addOnInstallation, err := cmv1.NewAddOnInstallation().
+		Addon(cmv1.NewAddOn().ID(addOnID)).
+		Parameters(cmv1.NewAddOnInstallationParameterList().Items(installationParams...)).
+		Build()
+	if err != nil {
+		return err
+	}
+
+	response, err := client.Cluster(clusterID).Addons().Add().Body(addOnInstallation).Send()
+	if err != nil {
+		return handleErr(response.Error(), err)
+	}
+	return nil
+}
+
+// UninstallAddOn requests the removal of the add-on from the cluster. The version of the SDK that
+// we use doesn't support deleting add-on installations yet, so the request is sent directly.
+func UninstallAddOn(connection *sdk.Connection, clusterID string, addOnID string) error {
+	response, err := connection.Delete().
+		Path(fmt.Sprintf("%s/%s/addons/%s", clustersPath, clusterID, addOnID)).
+		Send()
+	if err != nil {
+		return err
+	}
+	if response.Status() < http.StatusBadRequest {
+		return nil
+	}
+	res, err := ocmerrors.UnmarshalError(response.Bytes())
+	if err != nil {
+		return fmt.Errorf("Unexpected status code %d", response.Status())
+	}
+	return handleErr(res, res)
+}
+
+func handleErr(res *ocmerrors.Error, err error) error {
+	return rerrors.FromOCM(res, err)
+}
@@ -0,0 +1,13 @@
+package addons_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestAddOns(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Add-ons Suite")
+}
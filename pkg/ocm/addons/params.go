@@ -0,0 +1,183 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to parse, validate and prompt for the values of the
+// parameters of add-ons.
+
+package addons
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/interactive"
+)
+
+// ParamsFlag is the name of the command line flag used to give the values of the parameters.
+const ParamsFlag = "param"
+
+// ParseParams parses the values of the '--param' flag, which have the 'key=value' format.
+func ParseParams(values []string) (map[string]string, error) {
+	params := map[string]string{}
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, rerrors.ValidationErrorf(
+				"Invalid parameter '%s': expected the 'key=value' format", value)
+		}
+		if _, ok := params[parts[0]]; ok {
+			return nil, rerrors.ValidationErrorf("Parameter '%s' is given more than once", parts[0])
+		}
+		params[parts[0]] = parts[1]
+	}
+	return params, nil
+}
+
+// Parameters returns the enabled parameters of the add-on.
+func Parameters(addOn *cmv1.AddOn) []*cmv1.AddOnParameter {
+	result := []*cmv1.AddOnParameter{}
+	addOn.Parameters().Each(func(param *cmv1.AddOnParameter) bool {
+		if param.Enabled() {
+			result = append(result, param)
+		}
+		return true
+	})
+	return result
+}
+
+// ValidateParams checks that the values are given for all the required parameters of the add-on,
+// that there are no values for unknown parameters, and that the values are valid.
+func ValidateParams(addOn *cmv1.AddOn, params map[string]string) error {
+	known := map[string]*cmv1.AddOnParameter{}
+	missing := []string{}
+	for _, param := range Parameters(addOn) {
+		known[param.ID()] = param
+		if _, ok := params[param.ID()]; !ok && param.Required() {
+			missing = append(missing, param.ID())
+		}
+	}
+	for _, id := range sortedKeys(params) {
+		param, ok := known[id]
+		if !ok {
+			return rerrors.ValidationErrorf("Add-on '%s' has no parameter '%s'", addOn.ID(), id)
+		}
+		err := validateValue(param, params[id])
+		if err != nil {
+			return err
+		}
+	}
+	if len(missing) > 0 {
+		return rerrors.ValidationErrorf("Add-on '%s' requires the parameters %s, use '--%s key=value'",
+			addOn.ID(), strings.Join(missing, ", "), ParamsFlag)
+	}
+	return nil
+}
+
+// PromptParams asks for the values of the parameters of the add-on, using the given values as
+// defaults. Only the required parameters that don't have a value are asked for, unless the
+// interactive mode is enabled.
+func PromptParams(addOn *cmv1.AddOn, params map[string]string) (map[string]string, error) {
+	result := map[string]string{}
+	for id, value := range params {
+		result[id] = value
+	}
+	for _, param := range Parameters(addOn) {
+		value, ok := result[param.ID()]
+		if ok && !interactive.Enabled() {
+			continue
+		}
+		if !param.Required() && !interactive.Enabled() {
+			continue
+		}
+		input := interactive.Input{
+			Question: param.Name(),
+			Help:     param.Description(),
+			Default:  value,
+			Required: param.Required(),
+			Flag:     ParamsFlag,
+		}
+		if param.ValueType() == "boolean" {
+			defaultValue, _ := strconv.ParseBool(value)
+			input.Default = defaultValue
+			answer, err := interactive.GetBool(input)
+			if err != nil {
+				return nil, err
+			}
+			result[param.ID()] = strconv.FormatBool(answer)
+			continue
+		}
+		if _, err := regexp.Compile(param.Validation()); err == nil && param.Validation() != "" {
+			input.Validators = []interactive.Validator{
+				interactive.RegExpValidator(param.Validation()),
+			}
+		}
+		answer, err := interactive.GetString(input)
+		if err != nil {
+			return nil, err
+		}
+		if answer == "" {
+			delete(result, param.ID())
+			continue
+		}
+		result[param.ID()] = answer
+	}
+	return result, nil
+}
+
+// validateValue checks that the value matches the type and the validation expression of the
+// parameter.
+func validateValue(param *cmv1.AddOnParameter, value string) error {
+	switch param.ValueType() {
+	case "boolean":
+		_, err := strconv.ParseBool(value)
+		if err != nil {
+			return rerrors.ValidationErrorf("Parameter '%s' must be 'true' or 'false', got '%s'",
+				param.ID(), value)
+		}
+	case "number":
+		_, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return rerrors.ValidationErrorf("Parameter '%s' must be a number, got '%s'", param.ID(), value)
+		}
+	}
+	if param.Validation() == "" {
+		return nil
+	}
+	re, err := regexp.Compile(param.Validation())
+	if err != nil {
+		return fmt.Errorf("Invalid validation of parameter '%s': %v", param.ID(), err)
+	}
+	if !re.MatchString(value) {
+		return rerrors.ValidationErrorf("Value '%s' of parameter '%s' doesn't match '%s'",
+			value, param.ID(), param.Validation())
+	}
+	return nil
+}
+
+func sortedKeys(params map[string]string) []string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,121 @@
+package addons_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/addons"
+)
+
+var _ = Describe("ParseParams", func() {
+	It("Parses key and value pairs", func() {
+		params, err := addons.ParseParams([]string{"email=me@example.com", "filter=a=b", "empty="})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(params).To(Equal(map[string]string{
+			"email":  "me@example.com",
+			"filter": "a=b",
+			"empty":  "",
+		}))
+	})
+
+	It("Rejects values without key", func() {
+		_, err := addons.ParseParams([]string{"=value"})
+		Expect(err).To(HaveOccurred())
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+	})
+
+	It("Rejects values without equals sign", func() {
+		_, err := addons.ParseParams([]string{"email"})
+		Expect(err).To(MatchError(ContainSubstring("key=value")))
+	})
+
+	It("Rejects duplicated keys", func() {
+		_, err := addons.ParseParams([]string{"email=a", "email=b"})
+		Expect(err).To(MatchError(ContainSubstring("more than once")))
+	})
+})
+
+var _ = Describe("ValidateParams", func() {
+	var addOn *cmv1.AddOn
+
+	BeforeEach(func() {
+		var err error
+		addOn, err = cmv1.NewAddOn().
+			ID("my-addon").
+			Parameters(cmv1.NewAddOnParameterList().Items(
+				cmv1.NewAddOnParameter().
+					ID("email").
+					ValueType("string").
+					Validation("^[^@]+@[^@]+$").
+					Required(true).
+					Enabled(true),
+				cmv1.NewAddOnParameter().
+					ID("replicas").
+					ValueType("number").
+					Enabled(true),
+				cmv1.NewAddOnParameter().
+					ID("debug").
+					ValueType("boolean").
+					Enabled(true),
+				cmv1.NewAddOnParameter().
+					ID("legacy").
+					ValueType("string").
+					Enabled(false),
+			)).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Returns only the enabled parameters", func() {
+		ids := []string{}
+		for _, param := range addons.Parameters(addOn) {
+			ids = append(ids, param.ID())
+		}
+		Expect(ids).To(Equal([]string{"email", "replicas", "debug"}))
+	})
+
+	It("Accepts valid values", func() {
+		err := addons.ValidateParams(addOn, map[string]string{
+			"email":    "me@example.com",
+			"replicas": "3",
+			"debug":    "true",
+		})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Rejects missing required parameters", func() {
+		err := addons.ValidateParams(addOn, map[string]string{"replicas": "3"})
+		Expect(err).To(MatchError(ContainSubstring("requires the parameters email")))
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+	})
+
+	It("Rejects unknown parameters", func() {
+		err := addons.ValidateParams(addOn, map[string]string{
+			"email":  "me@example.com",
+			"legacy": "x",
+		})
+		Expect(err).To(MatchError(ContainSubstring("has no parameter 'legacy'")))
+	})
+
+	It("Rejects values of the wrong type", func() {
+		err := addons.ValidateParams(addOn, map[string]string{
+			"email":    "me@example.com",
+			"replicas": "many",
+		})
+		Expect(err).To(MatchError(ContainSubstring("must be a number")))
+
+		err = addons.ValidateParams(addOn, map[string]string{
+			"email": "me@example.com",
+			"debug": "maybe",
+		})
+		Expect(err).To(MatchError(ContainSubstring("must be 'true' or 'false'")))
+	})
+
+	It("Rejects values that don't match the validation", func() {
+		err := addons.ValidateParams(addOn, map[string]string{"email": "nobody"})
+		Expect(err).To(MatchError(ContainSubstring("doesn't match")))
+	})
+})
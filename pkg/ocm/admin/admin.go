@@ -0,0 +1,239 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to manage the cluster-admin user, which is created using
+// an htpasswd identity provider.
+
+package admin
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"unicode"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// Names of the identity provider, the user and the group used for the cluster admin:
+const (
+	IdpName  = "Cluster-Admin"
+	Username = "cluster-admin"
+	Group    = "cluster-admins"
+)
+
+// MinPasswordLength is the minimum length of the password of the cluster admin.
+const MinPasswordLength = 14
+
+// generatedPasswordLength is the length of the passwords generated for the cluster admin.
+const generatedPasswordLength = 23
+
+// ValidatePassword checks that the password of the cluster admin has at least MinPasswordLength
+// characters, no whitespace, and contains uppercase letters, lowercase letters, and digits or
+// symbols.
+func ValidatePassword(password string) error {
+	if len(password) < MinPasswordLength {
+		return fmt.Errorf("Password must be at least %d characters long", MinPasswordLength)
+	}
+	var hasUpper, hasLower, hasDigitOrSymbol bool
+	for _, char := range password {
+		switch {
+		case char > unicode.MaxASCII:
+			return errors.New("Password must only contain ASCII characters")
+		case unicode.IsSpace(char):
+			return errors.New("Password must not contain whitespace")
+		case unicode.IsUpper(char):
+			hasUpper = true
+		case unicode.IsLower(char):
+			hasLower = true
+		default:
+			hasDigitOrSymbol = true
+		}
+	}
+	if !hasUpper || !hasLower || !hasDigitOrSymbol {
+		return errors.New("Password must contain uppercase letters, lowercase letters, " +
+			"and digits or symbols")
+	}
+	return nil
+}
+
+// GeneratePassword generates a random password that satisfies the rules of ValidatePassword.
+func GeneratePassword() (string, error) {
+	for {
+		password, err := generateRandomPassword(generatedPasswordLength)
+		if err != nil {
+			return "", err
+		}
+		if ValidatePassword(password) == nil {
+			return password, nil
+		}
+	}
+}
+
+func generateRandomPassword(length int) (string, error) {
+	const (
+		lowerLetters = "abcdefghijkmnopqrstuvwxyz"
+		upperLetters = "ABCDEFGHIJKLMNPQRSTUVWXYZ"
+		digits       = "23456789"
+		all          = lowerLetters + upperLetters + digits
+	)
+	var password string
+	for i := 0; i < length; i++ {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(all))))
+		if err != nil {
+			return "", err
+		}
+		newchar := string(all[n.Int64()])
+		if password == "" {
+			password = newchar
+		}
+		if i < length-1 {
+			n, err = rand.Int(rand.Reader, big.NewInt(int64(len(password)+1)))
+			if err != nil {
+				return "", err
+			}
+			j := n.Int64()
+			password = password[0:j] + newchar + password[j:]
+		}
+	}
+
+	pw := []rune(password)
+	for _, replace := range []int{5, 11, 17} {
+		pw[replace] = '-'
+	}
+
+	return string(pw), nil
+}
+
+// GetIdentityProvider returns the htpasswd identity provider of the cluster admin, or nil if the
+// cluster doesn't have an admin.
+func GetIdentityProvider(client *cmv1.ClustersClient, clusterID string) (*cmv1.IdentityProvider, error) {
+	response, err := client.Cluster(clusterID).IdentityProviders().List().
+		Page(1).
+		Size(-1).
+		Send()
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	var idp *cmv1.IdentityProvider
+	response.Items().Each(func(item *cmv1.IdentityProvider) bool {
+		if item.Type() == "HTPasswdIdentityProvider" && item.Htpasswd() != nil {
+			idp = item
+			return false
+		}
+		return true
+	})
+	return idp, nil
+}
+
+// CreateAdmin adds the cluster admin to the cluster-admins group and creates the htpasswd identity
+// provider used to login with the given password.
+func CreateAdmin(client *cmv1.ClustersClient, clusterID string, password string) error {
+	user, err := cmv1.NewUser().ID(Username).Build()
+	if err != nil {
+		return fmt.Errorf("Failed to create user '%s': %v", Username, err)
+	}
+	userResponse, err := client.Cluster(clusterID).
+		Groups().Group(Group).
+		Users().Add().Body(user).
+		Send()
+	if err != nil {
+		return fmt.Errorf("Failed to add user '%s': %v", Username, handleErr(userResponse.Error(), err))
+	}
+
+	idp, err := cmv1.NewIdentityProvider().
+		Type("HTPasswdIdentityProvider"). // FIXME: ocm-api-model has the wrong enum values
+		Name(IdpName).
+		MappingMethod(cmv1.IdentityProviderMappingMethod("claim")).
+		Htpasswd(cmv1.NewHTPasswdIdentityProvider().
+			Username(Username).
+			Password(password)).
+		Build()
+	if err != nil {
+		return fmt.Errorf("Failed to create '%s' identity provider: %v", IdpName, err)
+	}
+	idpResponse, err := client.Cluster(clusterID).
+		IdentityProviders().
+		Add().
+		Body(idp).
+		Send()
+	if err != nil {
+		return fmt.Errorf("Failed to add '%s' identity provider: %v", IdpName,
+			handleErr(idpResponse.Error(), err))
+	}
+	return nil
+}
+
+// UpdatePassword replaces the password of the cluster admin in a single update of the identity
+// provider, so that there is no time when the admin can't login.
+func UpdatePassword(client *cmv1.ClustersClient, clusterID string, idp *cmv1.IdentityProvider,
+	password string) error {
+	body, err := cmv1.NewIdentityProvider().
+		Type(idp.Type()).
+		Htpasswd(cmv1.NewHTPasswdIdentityProvider().
+			Username(idp.Htpasswd().Username()).
+			Password(password)).
+		Build()
+	if err != nil {
+		return fmt.Errorf("Failed to create '%s' identity provider: %v", idp.Name(), err)
+	}
+	response, err := client.Cluster(clusterID).
+		IdentityProviders().
+		IdentityProvider(idp.ID()).
+		Update().
+		Body(body).
+		Send()
+	if err != nil {
+		return fmt.Errorf("Failed to update '%s' identity provider: %v", idp.Name(),
+			handleErr(response.Error(), err))
+	}
+	return nil
+}
+
+// DeleteAdmin deletes the identity provider of the cluster admin and removes the user from the
+// cluster-admins group.
+func DeleteAdmin(client *cmv1.ClustersClient, clusterID string, idp *cmv1.IdentityProvider) error {
+	idpResponse, err := client.Cluster(clusterID).
+		IdentityProviders().
+		IdentityProvider(idp.ID()).
+		Delete().
+		Send()
+	if err != nil {
+		return fmt.Errorf("Failed to delete '%s' identity provider: %v", idp.Name(),
+			handleErr(idpResponse.Error(), err))
+	}
+
+	userResponse, err := client.Cluster(clusterID).
+		Groups().
+		Group(Group).
+		Users().
+		User(Username).
+		Delete().
+		Send()
+	if err != nil {
+		return fmt.Errorf("Failed to delete '%s' user from '%s' group: %v", Username, Group,
+			handleErr(userResponse.Error(), err))
+	}
+	return nil
+}
+
+func handleErr(res *ocmerrors.Error, err error) error {
+	return rerrors.FromOCM(res, err)
+}
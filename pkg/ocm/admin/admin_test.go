@@ -0,0 +1,47 @@
+package admin_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/ocm/admin"
+)
+
+var _ = Describe("Admin", func() {
+	Context("ValidatePassword", func() {
+		It("accepts passwords that follow the complexity rules", func() {
+			Expect(ValidatePassword("Correct-Horse-Battery-1")).To(Succeed())
+			Expect(ValidatePassword("correctHorse#battery")).To(Succeed())
+		})
+
+		It("rejects short passwords", func() {
+			Expect(ValidatePassword("Short-Pass1")).NotTo(Succeed())
+		})
+
+		It("rejects passwords with whitespace", func() {
+			Expect(ValidatePassword("Correct Horse Battery 1")).NotTo(Succeed())
+		})
+
+		It("rejects passwords without uppercase letters", func() {
+			Expect(ValidatePassword("correct-horse-battery-1")).NotTo(Succeed())
+		})
+
+		It("rejects passwords without lowercase letters", func() {
+			Expect(ValidatePassword("CORRECT-HORSE-BATTERY-1")).NotTo(Succeed())
+		})
+
+		It("rejects passwords without digits or symbols", func() {
+			Expect(ValidatePassword("CorrectHorseBattery")).NotTo(Succeed())
+		})
+	})
+
+	Context("GeneratePassword", func() {
+		It("generates valid passwords", func() {
+			for i := 0; i < 100; i++ {
+				password, err := GeneratePassword()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ValidatePassword(password)).To(Succeed())
+			}
+		})
+	})
+})
@@ -0,0 +1,177 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the implementation of the OAuth device authorization grant (RFC 8628), used
+// to log in from machines that don't have a browser.
+
+package auth
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DeviceClientID is the OpenID client that is allowed to use the device authorization grant.
+const DeviceClientID = "ocm-cli"
+
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// DeviceCode contains the codes returned by the authorization server when the device flow starts.
+type DeviceCode struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// DeviceFlow contains the details needed to request device codes and exchange them for tokens.
+type DeviceFlow struct {
+	TokenURL string
+	ClientID string
+	Scopes   []string
+	Insecure bool
+
+	// Sleep waits between the polls of the token endpoint. It is time.Sleep when it isn't set.
+	Sleep func(time.Duration)
+}
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// DeviceURL returns the device authorization endpoint that corresponds to the given token
+// endpoint. Keycloak, used by the Red Hat SSO, publishes both in the same OpenID Connect path.
+func DeviceURL(tokenURL string) string {
+	return strings.TrimSuffix(tokenURL, "/token") + "/auth/device"
+}
+
+// RequestCode starts the device flow, returning the codes that the user needs to enter in the
+// verification page.
+func (f *DeviceFlow) RequestCode() (*DeviceCode, error) {
+	form := url.Values{}
+	form.Set("client_id", f.ClientID)
+	if len(f.Scopes) > 0 {
+		form.Set("scope", strings.Join(f.Scopes, " "))
+	}
+	body, status, err := f.post(DeviceURL(f.TokenURL), form)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to request device code: %v", err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("Failed to request device code: %s", errorDescription(body, status))
+	}
+	code := new(DeviceCode)
+	err = json.Unmarshal(body, code)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse device code response: %v", err)
+	}
+	if code.Interval <= 0 {
+		code.Interval = 5
+	}
+	return code, nil
+}
+
+// PollToken waits till the user approves the request in the verification page, and then returns
+// the access and refresh tokens.
+func (f *DeviceFlow) PollToken(code *DeviceCode) (accessToken string, refreshToken string, err error) {
+	interval := time.Duration(code.Interval) * time.Second
+	deadline := time.Now().Add(time.Duration(code.ExpiresIn) * time.Second)
+	form := url.Values{}
+	form.Set("grant_type", deviceCodeGrantType)
+	form.Set("client_id", f.ClientID)
+	form.Set("device_code", code.DeviceCode)
+	sleep := f.Sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+	for {
+		if code.ExpiresIn > 0 && time.Now().After(deadline) {
+			return "", "", fmt.Errorf("Device code expired before the request was approved")
+		}
+		sleep(interval)
+
+		body, status, err := f.post(f.TokenURL, form)
+		if err != nil {
+			return "", "", fmt.Errorf("Failed to request token: %v", err)
+		}
+		response := new(tokenResponse)
+		err = json.Unmarshal(body, response)
+		if err != nil {
+			return "", "", fmt.Errorf("Failed to parse token response: %v", err)
+		}
+		if status == http.StatusOK {
+			return response.AccessToken, response.RefreshToken, nil
+		}
+		switch response.Error {
+		case "authorization_pending":
+			continue
+		case "slow_down":
+			interval += 5 * time.Second
+			continue
+		case "expired_token":
+			return "", "", fmt.Errorf("Device code expired before the request was approved")
+		case "access_denied":
+			return "", "", fmt.Errorf("The request was denied")
+		default:
+			return "", "", fmt.Errorf("Failed to request token: %s", errorDescription(body, status))
+		}
+	}
+}
+
+func (f *DeviceFlow) post(address string, form url.Values) ([]byte, int, error) {
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+	}
+	if f.Insecure {
+		client.Transport = &http.Transport{
+			// #nosec G402
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}
+	}
+	response, err := client.PostForm(address, form)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	return body, response.StatusCode, nil
+}
+
+func errorDescription(body []byte, status int) string {
+	response := new(tokenResponse)
+	err := json.Unmarshal(body, response)
+	if err != nil || response.Error == "" {
+		return fmt.Sprintf("unexpected status code %d", status)
+	}
+	if response.ErrorDescription != "" {
+		return response.ErrorDescription
+	}
+	return response.Error
+}
@@ -0,0 +1,135 @@
+package auth_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/ocm/auth"
+)
+
+var _ = Describe("Device flow", func() {
+	var (
+		server    *httptest.Server
+		responses []string
+		forms     []map[string]string
+		sleeps    []time.Duration
+		flow      *DeviceFlow
+		code      *DeviceCode
+	)
+
+	BeforeEach(func() {
+		responses = nil
+		forms = nil
+		sleeps = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.ParseForm()).To(Succeed())
+			forms = append(forms, map[string]string{
+				"path":        r.URL.Path,
+				"grant_type":  r.PostForm.Get("grant_type"),
+				"device_code": r.PostForm.Get("device_code"),
+			})
+			if r.URL.Path == "/protocol/openid-connect/auth/device" {
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(&DeviceCode{
+					DeviceCode:      "mydevicecode",
+					UserCode:        "ABCD-EFGH",
+					VerificationURI: "https://sso.example.com/device",
+					ExpiresIn:       600,
+				})
+				return
+			}
+			// Respond with the next token endpoint error, or with the tokens when there are none
+			// left:
+			w.Header().Set("Content-Type", "application/json")
+			if len(responses) == 0 {
+				_, _ = w.Write([]byte(`{"access_token": "myaccess", "refresh_token": "myrefresh"}`))
+				return
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": "` + responses[0] + `"}`))
+			responses = responses[1:]
+		}))
+		flow = &DeviceFlow{
+			TokenURL: server.URL + "/protocol/openid-connect/token",
+			ClientID: DeviceClientID,
+			Sleep: func(d time.Duration) {
+				sleeps = append(sleeps, d)
+			},
+		}
+		code = &DeviceCode{
+			DeviceCode: "mydevicecode",
+			ExpiresIn:  600,
+			Interval:   1,
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Derives the device URL from the token URL", func() {
+		realm := "https://sso.redhat.com/auth/realms/redhat-external/protocol/openid-connect"
+		Expect(DeviceURL(realm + "/token")).To(Equal(realm + "/auth/device"))
+	})
+
+	It("Requests the code from the device URL", func() {
+		code, err := flow.RequestCode()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(forms).To(HaveLen(1))
+		Expect(forms[0]["path"]).To(Equal("/protocol/openid-connect/auth/device"))
+		Expect(code.UserCode).To(Equal("ABCD-EFGH"))
+		Expect(code.Interval).To(Equal(5))
+	})
+
+	It("Keeps polling while the authorization is pending", func() {
+		responses = []string{"authorization_pending", "authorization_pending"}
+		accessToken, refreshToken, err := flow.PollToken(code)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(accessToken).To(Equal("myaccess"))
+		Expect(refreshToken).To(Equal("myrefresh"))
+		Expect(sleeps).To(Equal([]time.Duration{time.Second, time.Second, time.Second}))
+		Expect(forms).To(HaveLen(3))
+		for _, form := range forms {
+			Expect(form["path"]).To(Equal("/protocol/openid-connect/token"))
+			Expect(form["grant_type"]).To(Equal("urn:ietf:params:oauth:grant-type:device_code"))
+			Expect(form["device_code"]).To(Equal("mydevicecode"))
+		}
+	})
+
+	It("Increases the interval when asked to slow down", func() {
+		responses = []string{"slow_down", "authorization_pending", "slow_down"}
+		_, _, err := flow.PollToken(code)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sleeps).To(Equal([]time.Duration{
+			time.Second,
+			6 * time.Second,
+			6 * time.Second,
+			11 * time.Second,
+		}))
+	})
+
+	It("Fails when the device code expires", func() {
+		responses = []string{"authorization_pending", "expired_token"}
+		_, _, err := flow.PollToken(code)
+		Expect(err).To(MatchError(ContainSubstring("Device code expired")))
+		Expect(forms).To(HaveLen(2))
+	})
+
+	It("Fails when the request is denied", func() {
+		responses = []string{"access_denied"}
+		_, _, err := flow.PollToken(code)
+		Expect(err).To(MatchError(ContainSubstring("The request was denied")))
+		Expect(forms).To(HaveLen(1))
+	})
+
+	It("Fails on unknown errors", func() {
+		responses = []string{"invalid_grant"}
+		_, _, err := flow.PollToken(code)
+		Expect(err).To(MatchError(ContainSubstring("invalid_grant")))
+	})
+})
@@ -0,0 +1,272 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to manage the break glass credentials of a cluster:
+// temporary credentials that give administrator access to the cluster when the usual identity
+// providers aren't available.
+
+package breakglass
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// Limits and default of the time that a credential can be used:
+const (
+	MinExpiration     = 10 * time.Minute
+	MaxExpiration     = 24 * time.Hour
+	DefaultExpiration = 24 * time.Hour
+)
+
+// Statuses of a credential:
+const (
+	StatusCreated            = "created"
+	StatusIssued             = "issued"
+	StatusAwaitingRevocation = "awaiting_revocation"
+	StatusRevoked            = "revoked"
+	StatusExpired            = "expired"
+	StatusFailed             = "failed"
+)
+
+// Interval and default timeout of the polling of credentials until they are issued:
+const (
+	IssueInterval       = 5 * time.Second
+	DefaultIssueTimeout = 5 * time.Minute
+)
+
+// Usernames can only contain letters, digits, dashes and dots:
+var usernameRE = regexp.MustCompile(`^[a-zA-Z0-9][-.a-zA-Z0-9]*$`)
+
+// maxUsernameLength is the maximum length of the username of a credential.
+const maxUsernameLength = 35
+
+// Credential is a temporary credential that gives administrator access to a cluster.
+type Credential struct {
+	ID                  string    `json:"id"`
+	Username            string    `json:"username"`
+	Status              string    `json:"status"`
+	ExpirationTimestamp time.Time `json:"expiration_timestamp"`
+	RevocationTimestamp time.Time `json:"revocation_timestamp"`
+	Kubeconfig          string    `json:"kubeconfig"`
+}
+
+// Active returns true if the credential has been or is being issued and can still be used.
+func (c *Credential) Active() bool {
+	return c.Status == StatusCreated || c.Status == StatusIssued
+}
+
+// ValidateExpiration checks that the time that a credential can be used is within the limits.
+func ValidateExpiration(expiration time.Duration) error {
+	if expiration < MinExpiration || expiration > MaxExpiration {
+		return rerrors.ValidationErrorf("Expiration of break glass credentials must be between %s "+
+			"and %s, got %s", MinExpiration, MaxExpiration, expiration)
+	}
+	return nil
+}
+
+// ValidateUsername checks the username of a credential. It is optional, OCM generates one when it
+// is empty.
+func ValidateUsername(username string) error {
+	if username == "" {
+		return nil
+	}
+	if len(username) > maxUsernameLength || !usernameRE.MatchString(username) {
+		return rerrors.ValidationErrorf("Username '%s' isn't valid: it must be at most %d "+
+			"characters and contain only letters, digits, dashes and dots", username,
+			maxUsernameLength)
+	}
+	return nil
+}
+
+// CreateCredential requests a new credential for the cluster that can be used until the given
+// time. The kubeconfig isn't available until the status of the credential is 'issued'.
+func CreateCredential(ctx context.Context, connection *sdk.Connection, clusterID string,
+	username string, expiration time.Time) (*Credential, error) {
+	request := map[string]interface{}{
+		"expiration_timestamp": expiration.UTC(),
+	}
+	if username != "" {
+		request["username"] = username
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	response, err := connection.Post().
+		Path(credentialsPath(clusterID)).
+		Bytes(body).
+		SendContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = checkResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	credential := &Credential{}
+	err = json.Unmarshal(response.Bytes(), credential)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse break glass credential: %v", err)
+	}
+	return credential, nil
+}
+
+// GetCredentials returns all the credentials of the cluster, including the revoked and expired
+// ones.
+func GetCredentials(ctx context.Context, connection *sdk.Connection,
+	clusterID string) ([]*Credential, error) {
+	response, err := connection.Get().
+		Path(credentialsPath(clusterID)).
+		Parameter("size", -1).
+		SendContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = checkResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	return UnmarshalCredentials(response.Bytes())
+}
+
+// GetCredential returns the credential of the cluster with the given identifier.
+func GetCredential(ctx context.Context, connection *sdk.Connection, clusterID string,
+	credentialID string) (*Credential, error) {
+	response, err := connection.Get().
+		Path(fmt.Sprintf("%s/%s", credentialsPath(clusterID), credentialID)).
+		SendContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = checkResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	credential := &Credential{}
+	err = json.Unmarshal(response.Bytes(), credential)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse break glass credential: %v", err)
+	}
+	return credential, nil
+}
+
+// WaitForIssued polls the credential until it has been issued, and returns it with its kubeconfig.
+// It fails if the credential ends in any other status, or if it isn't issued before the timeout.
+func WaitForIssued(ctx context.Context, connection *sdk.Connection, clusterID string,
+	credentialID string, interval time.Duration, timeout time.Duration) (*Credential, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		credential, err := GetCredential(ctx, connection, clusterID, credentialID)
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("Stopped waiting for break glass credential '%s' to be issued: %w",
+				credentialID, ctx.Err())
+		}
+		if err != nil {
+			return nil, err
+		}
+		if credential.Status == StatusIssued && credential.Kubeconfig != "" {
+			return credential, nil
+		}
+		if credential.Status != StatusCreated && credential.Status != StatusIssued {
+			return nil, rerrors.ConflictErrorf("Break glass credential '%s' is %s, expected it to "+
+				"be issued", credentialID, credential.Status)
+		}
+		if time.Now().After(deadline) {
+			return nil, rerrors.TimeoutErrorf("Timed out waiting for break glass credential '%s' "+
+				"to be issued", credentialID)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("Stopped waiting for break glass credential '%s' to be issued: %w",
+				credentialID, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
+
+// DefaultKubeconfigPath returns the file where the kubeconfig of a break glass credential of the
+// cluster is written when the user doesn't give one.
+func DefaultKubeconfigPath(clusterName string) string {
+	return fmt.Sprintf("%s-break-glass.kubeconfig", clusterName)
+}
+
+// RevokeCredentials revokes all the active credentials of the cluster. OCM doesn't support
+// revoking a single credential, as the certificate authority that signs them is replaced.
+func RevokeCredentials(ctx context.Context, connection *sdk.Connection, clusterID string) error {
+	response, err := connection.Delete().
+		Path(credentialsPath(clusterID)).
+		SendContext(ctx)
+	if err != nil {
+		return err
+	}
+	return checkResponse(response)
+}
+
+// FilterActive returns the credentials that can still be used.
+func FilterActive(credentials []*Credential) []*Credential {
+	result := []*Credential{}
+	for _, credential := range credentials {
+		if credential.Active() {
+			result = append(result, credential)
+		}
+	}
+	return result
+}
+
+// UnmarshalCredentials parses the body of a response that contains a list of credentials.
+func UnmarshalCredentials(data []byte) ([]*Credential, error) {
+	var list struct {
+		Items []*Credential `json:"items"`
+	}
+	err := json.Unmarshal(data, &list)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse break glass credentials: %v", err)
+	}
+	if list.Items == nil {
+		return []*Credential{}, nil
+	}
+	return list.Items, nil
+}
+
+// credentialsPath returns the path of the collection of credentials of the cluster. The version of
+// the SDK that we use doesn't support break glass credentials yet, so the requests are sent
+// directly.
+func credentialsPath(clusterID string) string {
+	return fmt.Sprintf("/api/clusters_mgmt/v1/clusters/%s/break_glass_credentials", clusterID)
+}
+
+// checkResponse converts the error returned by the OCM API, if any, so that it can be reported with
+// the right exit code.
+func checkResponse(response *sdk.Response) error {
+	if response.Status() < http.StatusBadRequest {
+		return nil
+	}
+	res, err := ocmerrors.UnmarshalError(response.Bytes())
+	if err != nil {
+		return fmt.Errorf("Unexpected status code %d", response.Status())
+	}
+	return rerrors.FromOCM(res, res)
+}
@@ -0,0 +1,13 @@
+package breakglass_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestBreakGlass(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Break Glass Suite")
+}
@@ -0,0 +1,70 @@
+package breakglass_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/breakglass"
+)
+
+var _ = Describe("Break glass credentials", func() {
+	It("Parses the list of credentials", func() {
+		list, err := breakglass.UnmarshalCredentials([]byte(`{
+			"kind": "BreakGlassCredentialList",
+			"items": [
+				{
+					"id": "1a2b",
+					"username": "alice",
+					"status": "issued",
+					"expiration_timestamp": "2021-03-01T12:00:00Z"
+				},
+				{
+					"id": "3c4d",
+					"username": "bob",
+					"status": "revoked",
+					"expiration_timestamp": "2021-03-01T10:00:00Z",
+					"revocation_timestamp": "2021-03-01T09:00:00Z"
+				}
+			]
+		}`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(list).To(HaveLen(2))
+		Expect(list[0].Username).To(Equal("alice"))
+		Expect(list[0].ExpirationTimestamp).To(Equal(time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)))
+		Expect(list[1].RevocationTimestamp.IsZero()).To(BeFalse())
+
+		active := breakglass.FilterActive(list)
+		Expect(active).To(HaveLen(1))
+		Expect(active[0].ID).To(Equal("1a2b"))
+	})
+
+	It("Parses an empty list", func() {
+		list, err := breakglass.UnmarshalCredentials([]byte(`{"kind": "BreakGlassCredentialList"}`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(list).To(BeEmpty())
+	})
+
+	It("Validates the expiration", func() {
+		Expect(breakglass.ValidateExpiration(breakglass.DefaultExpiration)).To(Succeed())
+		Expect(breakglass.ValidateExpiration(10 * time.Minute)).To(Succeed())
+		err := breakglass.ValidateExpiration(5 * time.Minute)
+		Expect(err).To(MatchError(ContainSubstring("between 10m0s and 24h0m0s")))
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+		Expect(breakglass.ValidateExpiration(48 * time.Hour)).ToNot(Succeed())
+	})
+
+	It("Returns the default path of the kubeconfig", func() {
+		Expect(breakglass.DefaultKubeconfigPath("mycluster")).To(Equal("mycluster-break-glass.kubeconfig"))
+	})
+
+	It("Validates the username", func() {
+		Expect(breakglass.ValidateUsername("")).To(Succeed())
+		Expect(breakglass.ValidateUsername("sre-alice.1")).To(Succeed())
+		Expect(breakglass.ValidateUsername("alice'")).ToNot(Succeed())
+		Expect(breakglass.ValidateUsername("-alice")).ToNot(Succeed())
+		Expect(breakglass.ValidateUsername("a123456789012345678901234567890123456")).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,167 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains a small on-disk cache of OCM responses that rarely change, like the
+// identifiers of clusters and the lists of versions, so that commands don't need to request them
+// again every time they run.
+
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/openshift/moactl/pkg/config"
+)
+
+// Times to live of the cached responses:
+const (
+	ClusterIDTTL     = 10 * time.Minute
+	VersionsTTL      = 5 * time.Minute
+	LatestReleaseTTL = 24 * time.Hour
+)
+
+// entry is the content of a cache file.
+type entry struct {
+	Expires time.Time `json:"expires"`
+	Value   []byte    `json:"value"`
+}
+
+// Dir returns the directory that contains the cache of all the profiles.
+func Dir() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cache"), nil
+}
+
+// ClusterIDKey returns the key used to cache the identifier of the cluster with the given name or
+// identifier, created by the given user.
+func ClusterIDKey(clusterKey string, creatorARN string) string {
+	return fmt.Sprintf("cluster-id:%s:%s", creatorARN, clusterKey)
+}
+
+// VersionsKey returns the key used to cache the versions of the given channel group.
+func VersionsKey(channelGroup string) string {
+	return fmt.Sprintf("versions:%s", channelGroup)
+}
+
+// LatestReleaseKey returns the key used to cache the latest release of the tool.
+func LatestReleaseKey() string {
+	return "release:latest"
+}
+
+// Get returns the value stored with the given key, and false if there is no value, if it has
+// expired or if the cache is disabled.
+func Get(key string) ([]byte, bool) {
+	if disabled {
+		return nil, false
+	}
+	file, err := location(key)
+	if err != nil {
+		return nil, false
+	}
+	// #nosec G304
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, false
+	}
+	var value entry
+	err = json.Unmarshal(data, &value)
+	if err != nil || time.Now().After(value.Expires) {
+		return nil, false
+	}
+	return value.Value, true
+}
+
+// Set stores the value with the given key for the given time. Nothing is stored if the cache is
+// disabled.
+func Set(key string, value []byte, ttl time.Duration) error {
+	if disabled {
+		return nil
+	}
+	file, err := location(key)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(&entry{
+		Expires: time.Now().Add(ttl),
+		Value:   value,
+	})
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(file), 0700)
+	if err != nil {
+		return fmt.Errorf("Failed to create cache directory: %v", err)
+	}
+	err = ioutil.WriteFile(file, data, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to write cache file '%s': %v", file, err)
+	}
+	return nil
+}
+
+// Delete removes the value stored with the given key, if any.
+func Delete(key string) error {
+	file, err := location(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(file)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Failed to remove cache file '%s': %v", file, err)
+	}
+	return nil
+}
+
+// Clear removes the cache of all the profiles.
+func Clear() error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+	err = os.RemoveAll(dir)
+	if err != nil {
+		return fmt.Errorf("Failed to remove cache directory '%s': %v", dir, err)
+	}
+	return nil
+}
+
+// location returns the file that stores the value of the given key for the current profile. Keys
+// are hashed as they may contain characters that aren't valid in file names.
+func location(key string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	profile, err := config.CurrentProfile()
+	if err != nil {
+		return "", err
+	}
+	if profile == "" {
+		profile = "default"
+	}
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(dir, profile, hex.EncodeToString(sum[:])+".json"), nil
+}
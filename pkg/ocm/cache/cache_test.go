@@ -0,0 +1,80 @@
+package cache_test
+
+import (
+	"io/ioutil"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/moactl/pkg/ocm/cache"
+)
+
+var _ = Describe("Cache", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "rosa-cache")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Setenv("ROSA_CONFIG_DIR", dir)).To(Succeed())
+		Expect(os.Setenv("ROSA_PROFILE", "")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Unsetenv("ROSA_CONFIG_DIR")).To(Succeed())
+		Expect(os.Unsetenv("ROSA_PROFILE")).To(Succeed())
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("Returns the stored value", func() {
+		key := cache.ClusterIDKey("mycluster", "arn:aws:iam::123456789012:user/alice")
+		Expect(cache.Set(key, []byte("123"), time.Minute)).To(Succeed())
+		value, ok := cache.Get(key)
+		Expect(ok).To(BeTrue())
+		Expect(string(value)).To(Equal("123"))
+	})
+
+	It("Doesn't return expired values", func() {
+		Expect(cache.Set("key", []byte("value"), -time.Second)).To(Succeed())
+		_, ok := cache.Get("key")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Keeps the values of each profile separate", func() {
+		Expect(cache.Set("key", []byte("value"), time.Minute)).To(Succeed())
+		Expect(os.Setenv("ROSA_PROFILE", "staging")).To(Succeed())
+		_, ok := cache.Get("key")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Removes a single value", func() {
+		Expect(cache.Set("key", []byte("value"), time.Minute)).To(Succeed())
+		Expect(cache.Delete("key")).To(Succeed())
+		_, ok := cache.Get("key")
+		Expect(ok).To(BeFalse())
+		Expect(cache.Delete("key")).To(Succeed())
+	})
+
+	It("Removes all the values", func() {
+		Expect(cache.Set("key", []byte("value"), time.Minute)).To(Succeed())
+		Expect(cache.Clear()).To(Succeed())
+		_, ok := cache.Get("key")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("Ignores the cache when disabled with the flag", func() {
+		Expect(cache.Set("key", []byte("value"), time.Minute)).To(Succeed())
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		cache.AddFlag(flags)
+		Expect(flags.Parse([]string{"--no-cache"})).To(Succeed())
+		defer func() {
+			Expect(flags.Set("no-cache", "false")).To(Succeed())
+		}()
+		Expect(cache.Disabled()).To(BeTrue())
+		_, ok := cache.Get("key")
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -0,0 +1,42 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to implement the '--no-cache' command line option.
+
+package cache
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// AddFlag adds the '--no-cache' flag to the given set of command line flags.
+func AddFlag(flags *pflag.FlagSet) {
+	flags.BoolVar(
+		&disabled,
+		"no-cache",
+		false,
+		"Don't use the local cache of OCM responses, like the identifiers of clusters and the "+
+			"available versions.",
+	)
+}
+
+// Disabled returns true if the cache shouldn't be used.
+func Disabled() bool {
+	return disabled
+}
+
+// disabled is a boolean flag that indicates that the cache shouldn't be used.
+var disabled bool
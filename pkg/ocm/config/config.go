@@ -32,6 +32,7 @@ import (
 	"github.com/mitchellh/go-homedir"
 	sdk "github.com/openshift-online/ocm-sdk-go"
 
+	rosaconfig "github.com/openshift/moactl/pkg/config"
 	"github.com/openshift/moactl/pkg/debug"
 )
 
@@ -96,6 +97,10 @@ func Save(cfg *Config) error {
 	if err != nil {
 		return fmt.Errorf("Failed to marshal config: %v", err)
 	}
+	err = os.MkdirAll(filepath.Dir(file), 0700)
+	if err != nil {
+		return fmt.Errorf("Failed to create directory for config file '%s': %v", file, err)
+	}
 	err = ioutil.WriteFile(file, data, 0600)
 	if err != nil {
 		return fmt.Errorf("Failed to write file '%s': %v", file, err)
@@ -120,8 +125,16 @@ func Remove() error {
 	return nil
 }
 
-// Location returns the location of the configuration file.
+// Location returns the location of the configuration file. When a profile is in use each profile
+// has its own configuration file, so that switching profiles doesn't require logging in again.
 func Location() (path string, err error) {
+	profile, err := rosaconfig.CurrentProfile()
+	if err != nil {
+		return "", err
+	}
+	if profile != "" {
+		return rosaconfig.OCMConfigLocation(profile)
+	}
 	if ocmconfig := os.Getenv("OCM_CONFIG"); ocmconfig != "" {
 		path = ocmconfig
 	} else {
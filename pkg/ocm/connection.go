@@ -18,12 +18,15 @@ package ocm
 
 import (
 	"fmt"
+	"net/http"
 
 	sdk "github.com/openshift-online/ocm-sdk-go"
 	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/metrics"
 	"github.com/openshift/moactl/pkg/ocm/config"
+	"github.com/openshift/moactl/pkg/timeout"
 )
 
 // ConnectionBuilder contains the information and logic needed to build a connection to OCM. Don't
@@ -109,11 +112,55 @@ func (b *ConnectionBuilder) Build() (result *sdk.Connection, err error) {
 	}
 	builder.Insecure(b.cfg.Insecure)
 
+	// Limit the time of each request, dump the details of requests and responses in debug mode,
+	// and retry the requests that fail because of transient errors or timeouts. Each attempt is
+	// limited, dumped and counted separately:
+	var wrapErr error
+	builder.TransportWrapper(func(next http.RoundTripper) http.RoundTripper {
+		var limiter *timeout.RoundTripper
+		limiter, wrapErr = timeout.NewRoundTripper().
+			Next(next).
+			Build()
+		if wrapErr != nil {
+			return next
+		}
+		next = limiter
+		if metrics.Enabled() {
+			next = metrics.NewRoundTripper(metrics.OCMAPI, next)
+		}
+		next = NewOperationIDRoundTripper(next)
+		if b.logger.IsLevelEnabled(logrus.DebugLevel) {
+			var dumper *logging.RoundTripper
+			dumper, wrapErr = logging.NewRoundTripper().
+				Logger(b.logger).
+				Next(next).
+				Build()
+			if wrapErr != nil {
+				return next
+			}
+			next = dumper
+		}
+		var retrier *RetryRoundTripper
+		retrier, wrapErr = NewRetryRoundTripper().
+			Logger(b.logger).
+			MaxRetries(maxRetries).
+			Next(next).
+			Build()
+		if wrapErr != nil {
+			return next
+		}
+		return retrier
+	})
+
 	// Create the connection:
 	result, err = builder.Build()
 	if err != nil {
 		return
 	}
+	if wrapErr != nil {
+		err = wrapErr
+		return
+	}
 
 	return
 }
@@ -0,0 +1,135 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// This file contains the functions used to manage the notification contacts of clusters, the
+// users that receive the emails about scheduled maintenance and support cases of a cluster in
+// addition to its owner.
+
+package contacts
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	amsv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
+	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// subscriptionsPath is the path of the collection of subscriptions. The version of the SDK that we
+// use doesn't support notification contacts yet, so the requests are sent directly.
+const subscriptionsPath = "/api/accounts_mgmt/v1/subscriptions"
+
+// GetContacts returns the notification contacts of the subscription of a cluster.
+func GetContacts(connection *sdk.Connection, subscriptionID string) ([]*amsv1.Account, error) {
+	response, err := connection.Get().
+		Path(contactsPath(subscriptionID)).
+		Parameter("size", -1).
+		Send()
+	if err != nil {
+		return nil, err
+	}
+	err = checkResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	return UnmarshalContacts(response.Bytes())
+}
+
+// AddContact adds the user with the given username to the notification contacts of the
+// subscription of a cluster.
+func AddContact(connection *sdk.Connection, subscriptionID string, username string) (*amsv1.Account,
+	error) {
+	body, err := json.Marshal(map[string]string{
+		"account_username": username,
+	})
+	if err != nil {
+		return nil, err
+	}
+	response, err := connection.Post().
+		Path(contactsPath(subscriptionID)).
+		Bytes(body).
+		Send()
+	if err != nil {
+		return nil, err
+	}
+	err = checkResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	return amsv1.UnmarshalAccount(response.Bytes())
+}
+
+// RemoveContact removes the account with the given identifier from the notification contacts of
+// the subscription of a cluster.
+func RemoveContact(connection *sdk.Connection, subscriptionID string, accountID string) error {
+	response, err := connection.Delete().
+		Path(fmt.Sprintf("%s/%s", contactsPath(subscriptionID), accountID)).
+		Send()
+	if err != nil {
+		return err
+	}
+	return checkResponse(response)
+}
+
+// FindContact returns the contact whose username, identifier or email matches the given key, or
+// nil if there is no such contact.
+func FindContact(contacts []*amsv1.Account, key string) *amsv1.Account {
+	for _, contact := range contacts {
+		if contact.Username() == key || contact.ID() == key || contact.Email() == key {
+			return contact
+		}
+	}
+	return nil
+}
+
+// UnmarshalContacts parses the body of a response that contains a list of notification contacts.
+func UnmarshalContacts(data []byte) ([]*amsv1.Account, error) {
+	var list struct {
+		Items json.RawMessage `json:"items"`
+	}
+	err := json.Unmarshal(data, &list)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse notification contacts: %v", err)
+	}
+	if len(list.Items) == 0 {
+		return []*amsv1.Account{}, nil
+	}
+	contacts, err := amsv1.UnmarshalAccountList([]byte(list.Items))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse notification contacts: %v", err)
+	}
+	return contacts, nil
+}
+
+func contactsPath(subscriptionID string) string {
+	return fmt.Sprintf("%s/%s/notification_contacts", subscriptionsPath, subscriptionID)
+}
+
+// checkResponse converts the error returned by the OCM API, if any, so that it can be reported with
+// the right exit code.
+func checkResponse(response *sdk.Response) error {
+	if response.Status() < http.StatusBadRequest {
+		return nil
+	}
+	res, err := ocmerrors.UnmarshalError(response.Bytes())
+	if err != nil {
+		return fmt.Errorf("Unexpected status code %d", response.Status())
+	}
+	return rerrors.FromOCM(res, res)
+}
@@ -0,0 +1,45 @@
+package contacts_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/moactl/pkg/ocm/contacts"
+)
+
+var _ = Describe("Contacts", func() {
+	It("Parses the list of contacts", func() {
+		list, err := contacts.UnmarshalContacts([]byte(`{
+			"kind": "AccountList",
+			"page": 1,
+			"size": 2,
+			"total": 2,
+			"items": [
+				{"id": "1a2b", "username": "alice", "email": "alice@example.com"},
+				{"id": "3c4d", "username": "bob", "email": "bob@example.com"}
+			]
+		}`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(list).To(HaveLen(2))
+		Expect(list[0].Username()).To(Equal("alice"))
+		Expect(list[1].Email()).To(Equal("bob@example.com"))
+	})
+
+	It("Parses an empty list of contacts", func() {
+		list, err := contacts.UnmarshalContacts([]byte(`{"kind": "AccountList", "total": 0}`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(list).To(BeEmpty())
+	})
+
+	It("Finds contacts by username, identifier and email", func() {
+		list, err := contacts.UnmarshalContacts([]byte(`{"items": [
+			{"id": "1a2b", "username": "alice", "email": "alice@example.com"},
+			{"id": "3c4d", "username": "bob", "email": "bob@example.com"}
+		]}`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(contacts.FindContact(list, "bob").ID()).To(Equal("3c4d"))
+		Expect(contacts.FindContact(list, "1a2b").Username()).To(Equal("alice"))
+		Expect(contacts.FindContact(list, "bob@example.com").Username()).To(Equal("bob"))
+		Expect(contacts.FindContact(list, "carol")).To(BeNil())
+	})
+})
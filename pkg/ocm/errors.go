@@ -0,0 +1,72 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to report the details of the errors returned by the OCM
+// API, which are needed by support to find the request that failed.
+
+package ocm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
+
+	"github.com/openshift/moactl/pkg/debug"
+)
+
+// SupportCaseURL is the page used to open a support case.
+const SupportCaseURL = "https://access.redhat.com/support/cases/#/case/new"
+
+// ErrorDetails returns the code and the operation identifier of the OCM error that caused the given
+// error, and how to use them to open a support case. When debug mode is enabled it also includes
+// the complete error returned by the API. It returns an empty string if the error wasn't returned
+// by the OCM API.
+func ErrorDetails(err error) string {
+	var ocmErr *ocmerrors.Error
+	if !errors.As(err, &ocmErr) || ocmErr == nil {
+		return ""
+	}
+	lines := []string{}
+	if code := ocmErr.Code(); code != "" {
+		lines = append(lines, fmt.Sprintf("Code: %s", code))
+	}
+	if operationID := ocmErr.OperationID(); operationID != "" {
+		lines = append(lines,
+			fmt.Sprintf("Operation ID: %s", operationID),
+			fmt.Sprintf("Include the operation ID when opening a support case at %s", SupportCaseURL),
+		)
+	}
+	if debug.Enabled() {
+		var buffer bytes.Buffer
+		if ocmerrors.MarshalError(ocmErr, &buffer) == nil {
+			lines = append(lines, fmt.Sprintf("Response: %s", strings.TrimSpace(buffer.String())))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FormatError returns the message of the error followed by the details of the OCM error that
+// caused it, if any.
+func FormatError(err error) string {
+	details := ErrorDetails(err)
+	if details == "" {
+		return err.Error()
+	}
+	return fmt.Sprintf("%s\n%s", err.Error(), details)
+}
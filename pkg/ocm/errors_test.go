@@ -0,0 +1,66 @@
+package ocm_test
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/moactl/pkg/debug"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	. "github.com/openshift/moactl/pkg/ocm"
+)
+
+var _ = Describe("Error details", func() {
+	var err error
+
+	// setDebug sets the '--debug' flag, as its value is kept in a package variable:
+	setDebug := func(enabled bool) {
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		debug.AddFlag(fs)
+		Expect(fs.Parse([]string{fmt.Sprintf("--debug=%t", enabled)})).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		ocmErr, buildErr := ocmerrors.NewError().
+			ID("400").
+			Code("CLUSTERS-MGMT-400").
+			Reason("Cluster name is invalid").
+			OperationID("a1b2c3").
+			Build()
+		Expect(buildErr).ToNot(HaveOccurred())
+		err = fmt.Errorf("Failed to create cluster: %w", rerrors.FromOCM(ocmErr, ocmErr))
+	})
+
+	AfterEach(func() {
+		setDebug(false)
+	})
+
+	It("Includes the code, the operation ID and the support hint", func() {
+		Expect(ErrorDetails(err)).To(Equal("" +
+			"Code: CLUSTERS-MGMT-400\n" +
+			"Operation ID: a1b2c3\n" +
+			"Include the operation ID when opening a support case at " + SupportCaseURL))
+	})
+
+	It("Includes the complete error in debug mode", func() {
+		setDebug(true)
+		details := ErrorDetails(err)
+		Expect(details).To(ContainSubstring("Response: {"))
+		Expect(details).To(ContainSubstring(`"operation_id": "a1b2c3"`))
+	})
+
+	It("Formats the message followed by the details", func() {
+		Expect(FormatError(err)).To(HavePrefix("Failed to create cluster: Cluster name is invalid\n" +
+			"Code: CLUSTERS-MGMT-400\n"))
+	})
+
+	It("Has no details for other errors", func() {
+		Expect(ErrorDetails(errors.New("boom"))).To(BeEmpty())
+		Expect(FormatError(errors.New("boom"))).To(Equal("boom"))
+	})
+})
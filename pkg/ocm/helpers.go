@@ -17,22 +17,26 @@ limitations under the License.
 package ocm
 
 import (
-	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"regexp"
+	"strings"
 
 	sdk "github.com/openshift-online/ocm-sdk-go"
-	amsv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
 
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/interactive"
+	"github.com/openshift/moactl/pkg/ocm/addons"
+	"github.com/openshift/moactl/pkg/ocm/cache"
 	"github.com/openshift/moactl/pkg/ocm/properties"
 )
 
 // Regular expression to used to make sure that the identifier or name given by the user is
 // safe and that it there is no risk of SQL injection:
-var clusterKeyRE = regexp.MustCompile(`^(\w|-)+$`)
+var clusterKeyRE = regexp.MustCompile(`^(\w|-|\*)+$`)
 var badUsernameRE = regexp.MustCompile(`^(~|\.?\.|cluster-admin|.*[:\/%].*)$`)
 
 func IsValidClusterKey(clusterKey string) bool {
@@ -57,28 +61,162 @@ func HasClusters(client *cmv1.ClustersClient, creatorARN string) (bool, error) {
 	return response.Total() > 0, nil
 }
 
+// GetCluster finds the cluster created by the given user with the given identifier, name or
+// external identifier, or with the given domain prefix if none matches. The key may contain '*'
+// wildcards to match the name of the cluster. When the '--owner-arn' flag is used the clusters
+// created by that user are searched instead. If several clusters match the user is asked to
+// select one, or an error is returned when questions can't be asked.
 func GetCluster(client *cmv1.ClustersClient, clusterKey string, creatorARN string) (*cmv1.Cluster, error) {
-	query := fmt.Sprintf(
-		"(id = '%s' or name = '%s') and properties.%s = '%s'",
-		clusterKey, clusterKey, properties.CreatorARN, creatorARN,
+	err := ValidateOwnerARN()
+	if err != nil {
+		return nil, rerrors.ValidationErrorf("%v", err)
+	}
+	creatorARN = OwnerARN(creatorARN)
+	wildcard := strings.Contains(clusterKey, "*")
+
+	// Use the identifier found by a previous command, as getting the cluster directly is faster
+	// than searching for it. The cluster may have been deleted and replaced by another one with
+	// the same name, so the cached identifier is discarded if it doesn't match anymore:
+	cacheKey := cache.ClusterIDKey(clusterKey, creatorARN)
+	if !wildcard {
+		if clusterID, ok := cache.Get(cacheKey); ok {
+			cluster := getCachedCluster(client, string(clusterID), clusterKey, creatorARN)
+			if cluster != nil {
+				return cluster, nil
+			}
+			// Failing to update the cache shouldn't make the command fail:
+			_ = cache.Delete(cacheKey)
+		}
+	}
+
+	clusters, total, err := searchClusters(client, ClusterSearch(clusterKey, creatorARN))
+	if err != nil {
+		return nil, err
+	}
+	if total == 1 && !wildcard {
+		_ = cache.Set(cacheKey, []byte(clusters[0].ID()), cache.ClusterIDTTL)
+	}
+	if total == 0 && !wildcard {
+		// Older versions of the API don't support searching by domain prefix, and reject the
+		// query. That is the same as not finding any cluster:
+		clusters, total, err = searchClusters(client, DomainPrefixSearch(clusterKey, creatorARN))
+		if rerrors.ExitCode(err) == rerrors.ExitCodeValidation {
+			clusters, total, err = nil, 0, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch total {
+	case 0:
+		return nil, rerrors.NotFoundErrorf("There is no cluster with identifier or name '%s'", clusterKey)
+	case 1:
+		return clusters[0], nil
+	default:
+		return selectCluster(clusterKey, clusters, total)
+	}
+}
+
+// ClusterSearch returns the search query used to find the clusters created by the given user that
+// have the given identifier, name or external identifier. Keys that contain '*' wildcards only
+// match the name of the cluster.
+func ClusterSearch(clusterKey string, creatorARN string) string {
+	if strings.Contains(clusterKey, "*") {
+		return fmt.Sprintf(
+			"name like '%s' and properties.%s = '%s'",
+			strings.ReplaceAll(clusterKey, "*", "%"), properties.CreatorARN, creatorARN,
+		)
+	}
+	return fmt.Sprintf(
+		"(id = '%s' or name = '%s' or external_id = '%s') and properties.%s = '%s'",
+		clusterKey, clusterKey, clusterKey, properties.CreatorARN, creatorARN,
+	)
+}
+
+// DomainPrefixSearch returns the search query used to find the clusters created by the given user
+// that have the given domain prefix.
+func DomainPrefixSearch(clusterKey string, creatorARN string) string {
+	return fmt.Sprintf(
+		"domain_prefix = '%s' and properties.%s = '%s'",
+		clusterKey, properties.CreatorARN, creatorARN,
 	)
+}
+
+// maxClusterMatches is the maximum number of matching clusters offered to the user.
+const maxClusterMatches = 50
+
+// searchClusters returns the first page of clusters that match the query, and the total number
+// of matching clusters.
+func searchClusters(client *cmv1.ClustersClient, query string) ([]*cmv1.Cluster, int, error) {
 	response, err := client.List().
 		Search(query).
+		Order("name asc").
 		Page(1).
-		Size(1).
+		Size(maxClusterMatches).
 		Send()
 	if err != nil {
-		return nil, handleErr(response.Error(), err)
+		return nil, 0, handleErr(response.Error(), err)
 	}
+	return response.Items().Slice(), response.Total(), nil
+}
 
-	switch response.Total() {
-	case 0:
-		return nil, fmt.Errorf("There is no cluster with identifier or name '%s'", clusterKey)
-	case 1:
-		return response.Items().Slice()[0], nil
-	default:
-		return nil, fmt.Errorf("There are %d clusters with identifier or name '%s'", response.Total(), clusterKey)
+// selectCluster asks the user to select one of the clusters that match the key. The selection
+// isn't cached, as the same key would be ambiguous again in the next command.
+func selectCluster(clusterKey string, clusters []*cmv1.Cluster, total int) (*cmv1.Cluster, error) {
+	options := make([]string, len(clusters))
+	for i, cluster := range clusters {
+		options[i] = ClusterOption(cluster)
+	}
+	if interactive.CheckPrompt() != nil || total > len(clusters) {
+		ids := make([]string, len(clusters))
+		for i, cluster := range clusters {
+			ids[i] = cluster.ID()
+		}
+		return nil, rerrors.ValidationErrorf(
+			"There are %d clusters with identifier or name '%s': %s. Use the identifier of the "+
+				"cluster instead", total, clusterKey, strings.Join(ids, ", "))
+	}
+	selected, err := interactive.GetOption(interactive.Input{
+		Question: "Cluster",
+		Help:     fmt.Sprintf("There are %d clusters that match '%s'.", total, clusterKey),
+		Options:  options,
+		Required: true,
+	})
+	if err != nil {
+		return nil, rerrors.ValidationErrorf("Expected a valid cluster: %v", err)
+	}
+	for i, option := range options {
+		if option == selected {
+			return clusters[i], nil
+		}
 	}
+	return nil, rerrors.ValidationErrorf("Expected a valid cluster, got '%s'", selected)
+}
+
+// ClusterOption returns the text used to describe the cluster when the user is asked to select
+// one of several matching clusters.
+func ClusterOption(cluster *cmv1.Cluster) string {
+	return fmt.Sprintf("%s (%s)", cluster.ID(), cluster.Name())
+}
+
+// getCachedCluster gets the cluster with the cached identifier, and returns nil if it can't be
+// retrieved or if it isn't the cluster that the user asked for anymore.
+func getCachedCluster(client *cmv1.ClustersClient, clusterID string, clusterKey string,
+	creatorARN string) *cmv1.Cluster {
+	response, err := client.Cluster(clusterID).Get().Send()
+	if err != nil {
+		return nil
+	}
+	cluster := response.Body()
+	if cluster.ID() != clusterKey && cluster.Name() != clusterKey &&
+		cluster.ExternalID() != clusterKey {
+		return nil
+	}
+	if cluster.Properties()[properties.CreatorARN] != creatorARN {
+		return nil
+	}
+	return cluster
 }
 
 func GetIdentityProviders(client *cmv1.ClustersClient, clusterID string) ([]*cmv1.IdentityProvider, error) {
@@ -126,9 +264,9 @@ func GetIngresses(client *cmv1.ClustersClient, clusterID string) ([]*cmv1.Ingres
 	return response.Items().Slice(), nil
 }
 
-func GetUsers(client *cmv1.ClustersClient, clusterID string, group string) ([]*cmv1.User, error) {
-	usersClient := client.Cluster(clusterID).Groups().Group(group).Users()
-	response, err := usersClient.List().
+func GetGroups(client *cmv1.ClustersClient, clusterID string) ([]*cmv1.Group, error) {
+	groupsClient := client.Cluster(clusterID).Groups()
+	response, err := groupsClient.List().
 		Page(1).
 		Size(-1).
 		Send()
@@ -139,109 +277,32 @@ func GetUsers(client *cmv1.ClustersClient, clusterID string, group string) ([]*c
 	return response.Items().Slice(), nil
 }
 
-func GetAddOn(client *cmv1.AddOnsClient, id string) (*cmv1.AddOn, error) {
-	response, err := client.Addon(id).Get().Send()
-	if err != nil {
-		return nil, handleErr(response.Error(), err)
-	}
-	return response.Body(), nil
-}
-
-type ClusterAddOn struct {
-	ID        string
-	Name      string
-	State     string
-	Available bool
-}
-
-// Get all add-ons available for a cluster
-func GetClusterAddOns(connection *sdk.Connection, clusterID string) ([]*ClusterAddOn, error) {
-	// Get organization ID (used to get add-on quotas)
-	acctResponse, err := connection.AccountsMgmt().V1().CurrentAccount().
-		Get().
-		Send()
-	if err != nil {
-		return nil, handleErr(acctResponse.Error(), err)
-	}
-	organization := acctResponse.Body().Organization().ID()
-
-	// Get a list of add-on quotas for the current organization
-	resourceQuotasResponse, err := connection.AccountsMgmt().V1().Organizations().
-		Organization(organization).
-		ResourceQuota().
-		List().
-		Search("resource_type='addon'").
-		Page(1).
-		Size(-1).
-		Send()
-	if err != nil {
-		return nil, handleErr(resourceQuotasResponse.Error(), err)
-	}
-	resourceQuotas := resourceQuotasResponse.Items()
-
-	// Get complete list of enabled add-ons
-	addOnsResponse, err := connection.ClustersMgmt().V1().Addons().
-		List().
-		Search("enabled='t'").
-		Page(1).
-		Size(-1).
-		Send()
-	if err != nil {
-		return nil, handleErr(addOnsResponse.Error(), err)
-	}
-	addOns := addOnsResponse.Items()
-
-	// Get add-ons already installed on cluster
-	addOnInstallationsResponse, err := connection.ClustersMgmt().V1().Clusters().
-		Cluster(clusterID).
-		Addons().
-		List().
+func GetUsers(client *cmv1.ClustersClient, clusterID string, group string) ([]*cmv1.User, error) {
+	usersClient := client.Cluster(clusterID).Groups().Group(group).Users()
+	response, err := usersClient.List().
 		Page(1).
 		Size(-1).
 		Send()
 	if err != nil {
-		return nil, handleErr(addOnInstallationsResponse.Error(), err)
+		return nil, handleErr(response.Error(), err)
 	}
-	addOnInstallations := addOnInstallationsResponse.Items()
-
-	var clusterAddOns []*ClusterAddOn
 
-	// Populate add-on installations with all add-on metadata
-	addOns.Each(func(addOn *cmv1.AddOn) bool {
-		clusterAddOn := ClusterAddOn{
-			ID:        addOn.ID(),
-			Name:      addOn.Name(),
-			State:     "not installed",
-			Available: addOn.ResourceCost() == 0,
-		}
+	return response.Items().Slice(), nil
+}
 
-		// Only display add-ons for which the org has quota
-		resourceQuotas.Each(func(resourceQuota *amsv1.ResourceQuota) bool {
-			if addOn.ResourceName() == resourceQuota.ResourceName() {
-				clusterAddOn.Available = float64(resourceQuota.Allowed()) >= addOn.ResourceCost()
-			}
-			return true
-		})
-
-		// Get the state of add-on installations on the cluster
-		addOnInstallations.Each(func(addOnInstallation *cmv1.AddOnInstallation) bool {
-			if addOn.ID() == addOnInstallation.Addon().ID() {
-				clusterAddOn.State = string(addOnInstallation.State())
-				if clusterAddOn.State == "" {
-					clusterAddOn.State = string(cmv1.AddOnInstallationStateInstalling)
-				}
-			}
-			return true
-		})
+// Deprecated: use addons.GetAddOn instead.
+func GetAddOn(client *cmv1.AddOnsClient, id string) (*cmv1.AddOn, error) {
+	return addons.GetAddOn(client, id)
+}
 
-		// Only display add-ons that meet the above criteria
-		if clusterAddOn.Available {
-			clusterAddOns = append(clusterAddOns, &clusterAddOn)
-		}
-		return true
-	})
+// Deprecated: use addons.ClusterAddOn instead.
+type ClusterAddOn = addons.ClusterAddOn
 
-	return clusterAddOns, nil
+// Get all add-ons available for a cluster
+//
+// Deprecated: use addons.GetClusterAddOns instead.
+func GetClusterAddOns(connection *sdk.Connection, clusterID string) ([]*ClusterAddOn, error) {
+	return addons.GetClusterAddOns(connection, clusterID)
 }
 
 func GetClusterState(client *cmv1.ClustersClient, clusterID string) (cmv1.ClusterState, error) {
@@ -252,6 +313,19 @@ func GetClusterState(client *cmv1.ClustersClient, clusterID string) (cmv1.Cluste
 	return response.Body().State(), nil
 }
 
+// ClusterExists checks if OCM still has a cluster with the given identifier. Clusters that are
+// being uninstalled still exist.
+func ClusterExists(client *cmv1.ClustersClient, clusterID string) (bool, error) {
+	response, err := client.Cluster(clusterID).Get().Send()
+	if response != nil && response.Status() == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, handleErr(response.Error(), err)
+	}
+	return true, nil
+}
+
 func GetMachinePools(client *cmv1.ClustersClient, clusterID string) ([]*cmv1.MachinePool, error) {
 	response, err := client.Cluster(clusterID).MachinePools().
 		List().
@@ -265,12 +339,30 @@ func GetMachinePools(client *cmv1.ClustersClient, clusterID string) ([]*cmv1.Mac
 	return response.Items().Slice(), nil
 }
 
-func handleErr(res *ocmerrors.Error, err error) error {
-	msg := res.Reason()
-	if msg == "" {
-		msg = err.Error()
+// ScaleMachinePool changes the number of replicas of a machine pool other than the default one.
+func ScaleMachinePool(client *cmv1.ClustersClient, clusterID string, machinePoolID string,
+	replicas int) error {
+	machinePool, err := cmv1.NewMachinePool().
+		ID(machinePoolID).
+		Replicas(replicas).
+		Build()
+	if err != nil {
+		return err
 	}
-	return errors.New(msg)
+	response, err := client.Cluster(clusterID).
+		MachinePools().
+		MachinePool(machinePoolID).
+		Update().
+		Body(machinePool).
+		Send()
+	if err != nil {
+		return handleErr(response.Error(), err)
+	}
+	return nil
+}
+
+func handleErr(res *ocmerrors.Error, err error) error {
+	return rerrors.FromOCM(res, err)
 }
 
 func GetDefaultClusterFlavors(ocmClient *cmv1.Client) (dMachinecidr *net.IPNet, dPodcidr *net.IPNet,
@@ -0,0 +1,54 @@
+package ocm_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/openshift/moactl/pkg/ocm"
+)
+
+var _ = Describe("Cluster search", func() {
+	const creatorARN = "arn:aws:iam::123456789012:user/alice"
+
+	DescribeTable("Validates the cluster key",
+		func(clusterKey string, expected bool) {
+			Expect(IsValidClusterKey(clusterKey)).To(Equal(expected))
+		},
+		Entry("Name", "my-cluster", true),
+		Entry("Identifier", "1a2b3c4d5e6f", true),
+		Entry("Wildcard", "my-*", true),
+		Entry("Quote", "my'cluster", false),
+		Entry("Percent", "my%", false),
+		Entry("Space", "my cluster", false),
+	)
+
+	It("Searches by identifier, name and external identifier", func() {
+		Expect(ClusterSearch("my-cluster", creatorARN)).To(Equal(
+			"(id = 'my-cluster' or name = 'my-cluster' or external_id = 'my-cluster') and " +
+				"properties.rosa_creator_arn = '" + creatorARN + "'"))
+	})
+
+	It("Searches names that match the wildcards", func() {
+		Expect(ClusterSearch("dev-*-east", creatorARN)).To(Equal(
+			"name like 'dev-%-east' and properties.rosa_creator_arn = '" + creatorARN + "'"))
+	})
+
+	It("Searches by domain prefix", func() {
+		Expect(DomainPrefixSearch("mycluster", creatorARN)).To(Equal(
+			"domain_prefix = 'mycluster' and properties.rosa_creator_arn = '" + creatorARN + "'"))
+	})
+
+	It("Describes the cluster with its identifier and name", func() {
+		cluster, err := cmv1.NewCluster().ID("1a2b3c").Name("my-cluster").Build()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ClusterOption(cluster)).To(Equal("1a2b3c (my-cluster)"))
+	})
+
+	It("Uses the creator ARN when no owner is given", func() {
+		Expect(OwnerARN(creatorARN)).To(Equal(creatorARN))
+		Expect(ValidateOwnerARN()).To(Succeed())
+	})
+})
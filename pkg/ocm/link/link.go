@@ -0,0 +1,215 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to check that the Red Hat account of the user is linked to
+// the AWS account and meets the requirements to create clusters, so that problems can be fixed
+// before 'create cluster' fails.
+
+package link
+
+import (
+	"context"
+	"fmt"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	amsv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
+	azv1 "github.com/openshift-online/ocm-sdk-go/authorizations/v1"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/ocm/terms"
+)
+
+// enableURL is the page of the AWS console where the service is enabled for the AWS account.
+const enableURL = "https://console.aws.amazon.com/rosa/"
+
+// Result is the outcome of a single check, with the steps that fix it when it doesn't pass.
+type Result struct {
+	Name        string
+	Passed      bool
+	Details     string
+	Remediation string
+}
+
+// Verify runs all the checks. The AWS client is optional: when it couldn't be created the given
+// error is reported as a failed check. The checks that depend on a previous one that failed are
+// skipped.
+func Verify(connection *sdk.Connection, awsClient aws.Client, awsClientErr error) []Result {
+	results := []Result{}
+
+	response, err := connection.AccountsMgmt().V1().CurrentAccount().Get().Send()
+	if err != nil {
+		return append(results, Result{
+			Name:        "Red Hat account",
+			Details:     fmt.Sprintf("Failed to get current account: %v", err),
+			Remediation: "Log in again with 'rosa login'",
+		})
+	}
+	account := response.Body()
+	results = append(results, CheckAccount(account))
+
+	review, err := terms.GetReview(context.Background(), connection)
+	if err != nil {
+		results = append(results, Result{Name: "Terms and conditions", Details: err.Error()})
+	} else {
+		results = append(results, CheckTerms(review))
+	}
+
+	restricted, err := getExportControl(connection, account.Username())
+	if err != nil {
+		results = append(results, Result{Name: "Export control", Details: err.Error()})
+	} else {
+		results = append(results, CheckExportControl(restricted))
+	}
+
+	quotas, err := GetClusterQuotas(connection, account.Organization().ID())
+	if err != nil {
+		results = append(results, Result{Name: "Service enablement", Details: err.Error()})
+	} else {
+		results = append(results, CheckQuota(quotas))
+	}
+
+	var creator *aws.Creator
+	if awsClient != nil {
+		creator, awsClientErr = awsClient.GetCreator()
+	}
+	if awsClientErr != nil {
+		return append(results, Result{
+			Name:        "AWS account",
+			Details:     awsClientErr.Error(),
+			Remediation: "Configure the AWS credentials with 'aws configure'",
+		})
+	}
+	results = append(results, Result{
+		Name:    "AWS account",
+		Passed:  true,
+		Details: fmt.Sprintf("Using account '%s' as '%s'", creator.AccountID, creator.ARN),
+	})
+
+	tags, err := awsClient.GetUserTags(aws.AdminUserName)
+	if err != nil {
+		results = append(results, Result{Name: "AWS admin user", Details: err.Error()})
+	} else {
+		results = append(results, CheckAdminUser(tags != nil))
+	}
+
+	return results
+}
+
+// CheckAccount checks that the account belongs to an organization, as clusters and quota are
+// owned by organizations.
+func CheckAccount(account *amsv1.Account) Result {
+	result := Result{Name: "Red Hat account"}
+	if account.Organization() == nil || account.Organization().ID() == "" {
+		result.Details = fmt.Sprintf("Account '%s' doesn't belong to an organization", account.Username())
+		result.Remediation = fmt.Sprintf("Complete the registration of the account at %s",
+			terms.DefaultURL)
+		return result
+	}
+	result.Passed = true
+	result.Details = fmt.Sprintf("Logged in as '%s' of organization '%s'", account.Username(),
+		account.Organization().ID())
+	return result
+}
+
+// CheckTerms checks that the user has accepted the terms and conditions required to create
+// clusters.
+func CheckTerms(review *terms.Review) Result {
+	result := Result{Name: "Terms and conditions"}
+	if !review.Accepted() {
+		result.Details = "Terms and conditions haven't been accepted"
+		result.Remediation = fmt.Sprintf("Accept the terms and conditions at %s", review.URL())
+		return result
+	}
+	result.Passed = true
+	result.Details = "Terms and conditions accepted"
+	return result
+}
+
+// CheckExportControl checks that the user isn't restricted by export control regulations.
+func CheckExportControl(restricted bool) Result {
+	result := Result{Name: "Export control"}
+	if restricted {
+		result.Details = "Account is restricted by export control regulations"
+		result.Remediation = "Contact Red Hat customer service"
+		return result
+	}
+	result.Passed = true
+	result.Details = "Account isn't restricted"
+	return result
+}
+
+// CheckQuota checks that the organization has quota for clusters that run in the AWS account of
+// the customer, which is granted when the service is enabled in the AWS console.
+func CheckQuota(quotas []*amsv1.ResourceQuota) Result {
+	result := Result{Name: "Service enablement"}
+	for _, quota := range quotas {
+		if quota.BYOC() && quota.Allowed() > 0 {
+			result.Passed = true
+			result.Details = fmt.Sprintf("Organization has quota '%s'", quota.SKU())
+			return result
+		}
+	}
+	result.Details = "Organization doesn't have quota for clusters in AWS accounts of customers"
+	result.Remediation = fmt.Sprintf("Enable the service in the AWS console at %s and then link the "+
+		"AWS account to the Red Hat account when requested", enableURL)
+	return result
+}
+
+// CheckAdminUser checks that the IAM user used by the installer has been created by 'rosa init'.
+func CheckAdminUser(exists bool) Result {
+	result := Result{Name: "AWS admin user"}
+	if !exists {
+		result.Details = fmt.Sprintf("User '%s' doesn't exist", aws.AdminUserName)
+		result.Remediation = "Create it with 'rosa init'"
+		return result
+	}
+	result.Passed = true
+	result.Details = fmt.Sprintf("User '%s' exists", aws.AdminUserName)
+	return result
+}
+
+func getExportControl(connection *sdk.Connection, username string) (bool, error) {
+	request, err := azv1.NewExportControlReviewRequest().
+		AccountUsername(username).
+		Build()
+	if err != nil {
+		return false, err
+	}
+	response, err := connection.Authorizations().V1().ExportControlReview().Post().
+		Request(request).
+		Send()
+	if err != nil {
+		return false, fmt.Errorf("Failed to review export control: %v", err)
+	}
+	return response.Response().Restricted(), nil
+}
+
+// GetClusterQuotas returns the quotas of clusters in AWS of the given organization.
+func GetClusterQuotas(connection *sdk.Connection, organization string) ([]*amsv1.ResourceQuota,
+	error) {
+	response, err := connection.AccountsMgmt().V1().Organizations().
+		Organization(organization).
+		ResourceQuota().
+		List().
+		Search("resource_type='cluster.aws'").
+		Page(1).
+		Size(-1).
+		Send()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get quota of organization '%s': %v", organization, err)
+	}
+	return response.Items().Slice(), nil
+}
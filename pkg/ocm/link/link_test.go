@@ -0,0 +1,67 @@
+package link_test
+
+import (
+	amsv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/ocm/link"
+	"github.com/openshift/moactl/pkg/ocm/terms"
+)
+
+var _ = Describe("Link", func() {
+	It("Fails when the account doesn't belong to an organization", func() {
+		account, err := amsv1.NewAccount().Username("alice").Build()
+		Expect(err).NotTo(HaveOccurred())
+		result := CheckAccount(account)
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Remediation).To(ContainSubstring("Complete the registration"))
+	})
+
+	It("Passes when the account belongs to an organization", func() {
+		account, err := amsv1.NewAccount().
+			Username("alice").
+			Organization(amsv1.NewOrganization().ID("123")).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+		result := CheckAccount(account)
+		Expect(result.Passed).To(BeTrue())
+		Expect(result.Details).To(Equal("Logged in as 'alice' of organization '123'"))
+	})
+
+	It("Points to the page where the terms can be accepted", func() {
+		result := CheckTerms(&terms.Review{
+			TermsRequired: true,
+			RedirectURL:   "https://example.com/terms",
+		})
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Remediation).To(Equal("Accept the terms and conditions at https://example.com/terms"))
+	})
+
+	It("Passes when the terms have been accepted", func() {
+		Expect(CheckTerms(&terms.Review{TermsAvailable: true}).Passed).To(BeTrue())
+	})
+
+	It("Fails when the account is restricted by export control", func() {
+		Expect(CheckExportControl(true).Passed).To(BeFalse())
+	})
+
+	It("Requires quota for clusters in AWS accounts of customers", func() {
+		build := func(byoc bool, allowed int) *amsv1.ResourceQuota {
+			quota, err := amsv1.NewResourceQuota().SKU("MW00530").BYOC(byoc).Allowed(allowed).Build()
+			Expect(err).NotTo(HaveOccurred())
+			return quota
+		}
+		Expect(CheckQuota(nil).Remediation).To(ContainSubstring("Enable the service in the AWS console"))
+		Expect(CheckQuota([]*amsv1.ResourceQuota{build(false, 10)}).Passed).To(BeFalse())
+		Expect(CheckQuota([]*amsv1.ResourceQuota{build(true, 0)}).Passed).To(BeFalse())
+		Expect(CheckQuota([]*amsv1.ResourceQuota{build(false, 10), build(true, 5)}).Passed).To(BeTrue())
+	})
+
+	It("Asks to run init when the admin user doesn't exist", func() {
+		result := CheckAdminUser(false)
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Remediation).To(Equal("Create it with 'rosa init'"))
+	})
+})
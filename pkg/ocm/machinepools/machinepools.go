@@ -0,0 +1,178 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to parse and validate the labels and taints of machine
+// pools, using the same syntax rules as Kubernetes.
+
+package machinepools
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// Maximum lengths of the parts of label keys and values:
+const (
+	maxNameLength   = 63
+	maxPrefixLength = 253
+)
+
+// Label names and values must begin and end with an alphanumeric character, and can contain
+// dashes, underscores and dots in between:
+var labelNameRE = regexp.MustCompile(`^([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]$`)
+
+// Label key prefixes must be DNS subdomains:
+var labelPrefixRE = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+// TaintEffects are the valid effects of taints.
+var TaintEffects = []string{"NoSchedule", "PreferNoSchedule", "NoExecute"}
+
+// ValidateLabelKey checks that the key is a valid Kubernetes label key: a name with an optional DNS
+// subdomain prefix separated by a slash, for example 'example.com/my-label'.
+func ValidateLabelKey(key string) error {
+	name := key
+	if i := strings.Index(key, "/"); i >= 0 {
+		prefix := key[:i]
+		name = key[i+1:]
+		if len(prefix) > maxPrefixLength || !labelPrefixRE.MatchString(prefix) {
+			return fmt.Errorf("Invalid key '%s': prefix must be a DNS subdomain of at most %d "+
+				"characters", key, maxPrefixLength)
+		}
+	}
+	if len(name) > maxNameLength || !labelNameRE.MatchString(name) {
+		return fmt.Errorf("Invalid key '%s': name must be at most %d characters, begin and end "+
+			"with an alphanumeric character, and contain only alphanumeric characters, '-', '_' "+
+			"or '.'", key, maxNameLength)
+	}
+	return nil
+}
+
+// ValidateLabelValue checks that the value is a valid Kubernetes label value, which can be empty.
+func ValidateLabelValue(value string) error {
+	if value == "" {
+		return nil
+	}
+	if len(value) > maxNameLength || !labelNameRE.MatchString(value) {
+		return fmt.Errorf("Invalid value '%s': value must be at most %d characters, begin and end "+
+			"with an alphanumeric character, and contain only alphanumeric characters, '-', '_' "+
+			"or '.'", value, maxNameLength)
+	}
+	return nil
+}
+
+// ParseLabels parses a comma-separated list of 'key=value' labels.
+func ParseLabels(labels string) (map[string]string, error) {
+	labelMap := map[string]string{}
+	if strings.TrimSpace(labels) == "" {
+		return labelMap, nil
+	}
+	for _, label := range strings.Split(labels, ",") {
+		tokens := strings.SplitN(label, "=", 2)
+		if len(tokens) != 2 {
+			return nil, fmt.Errorf("Expected key=value format for label '%s'", label)
+		}
+		key := strings.TrimSpace(tokens[0])
+		value := strings.TrimSpace(tokens[1])
+		err := ValidateLabelKey(key)
+		if err != nil {
+			return nil, err
+		}
+		err = ValidateLabelValue(value)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := labelMap[key]; ok {
+			return nil, fmt.Errorf("Duplicated label key '%s'", key)
+		}
+		labelMap[key] = value
+	}
+	return labelMap, nil
+}
+
+// ParseTaints parses a comma-separated list of 'key=value:Effect' taints. The value is optional, so
+// 'key:Effect' is also accepted.
+func ParseTaints(taints string) ([]*cmv1.TaintBuilder, error) {
+	taintBuilders := []*cmv1.TaintBuilder{}
+	if strings.TrimSpace(taints) == "" {
+		return taintBuilders, nil
+	}
+	for _, taint := range strings.Split(taints, ",") {
+		i := strings.LastIndex(taint, ":")
+		if i < 0 {
+			return nil, fmt.Errorf("Expected key=value:Effect format for taint '%s'", taint)
+		}
+		keyValue := taint[:i]
+		effect := strings.TrimSpace(taint[i+1:])
+		key := keyValue
+		value := ""
+		if j := strings.Index(keyValue, "="); j >= 0 {
+			key = keyValue[:j]
+			value = keyValue[j+1:]
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		err := ValidateLabelKey(key)
+		if err != nil {
+			return nil, err
+		}
+		err = ValidateLabelValue(value)
+		if err != nil {
+			return nil, err
+		}
+		if !isValidEffect(effect) {
+			return nil, fmt.Errorf("Invalid effect '%s' for taint '%s', expected one of: %s",
+				effect, key, strings.Join(TaintEffects, ", "))
+		}
+		taintBuilders = append(taintBuilders, cmv1.NewTaint().Key(key).Value(value).Effect(effect))
+	}
+	return taintBuilders, nil
+}
+
+// FormatLabels formats the labels as a comma-separated list of 'key=value', sorted by key.
+func FormatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	output := make([]string, 0, len(keys))
+	for _, key := range keys {
+		output = append(output, fmt.Sprintf("%s=%s", key, labels[key]))
+	}
+	return strings.Join(output, ",")
+}
+
+// FormatTaints formats the taints as a comma-separated list of 'key=value:Effect'.
+func FormatTaints(taints []*cmv1.Taint) string {
+	output := make([]string, 0, len(taints))
+	for _, taint := range taints {
+		output = append(output, fmt.Sprintf("%s=%s:%s", taint.Key(), taint.Value(), taint.Effect()))
+	}
+	return strings.Join(output, ",")
+}
+
+func isValidEffect(effect string) bool {
+	for _, valid := range TaintEffects {
+		if effect == valid {
+			return true
+		}
+	}
+	return false
+}
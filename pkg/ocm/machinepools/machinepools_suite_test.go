@@ -0,0 +1,13 @@
+package machinepools_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestMachinePools(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Machine Pools Suite")
+}
@@ -0,0 +1,96 @@
+package machinepools_test
+
+import (
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/ocm/machinepools"
+)
+
+var _ = Describe("Machine pools", func() {
+	Context("ParseLabels", func() {
+		It("parses valid labels", func() {
+			labels, err := ParseLabels("foo=bar, example.com/role=infra,empty=")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(labels).To(Equal(map[string]string{
+				"foo":              "bar",
+				"example.com/role": "infra",
+				"empty":            "",
+			}))
+		})
+
+		It("returns no labels for an empty string", func() {
+			labels, err := ParseLabels("")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(labels).To(BeEmpty())
+		})
+
+		It("rejects labels without a value", func() {
+			_, err := ParseLabels("foo")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("rejects invalid keys", func() {
+			_, err := ParseLabels("-foo=bar")
+			Expect(err).To(HaveOccurred())
+			_, err = ParseLabels("Example.com/foo=bar")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("rejects invalid values", func() {
+			_, err := ParseLabels("foo=bar baz")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("rejects duplicated keys", func() {
+			_, err := ParseLabels("foo=bar,foo=baz")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("ParseTaints", func() {
+		build := func(builders []*cmv1.TaintBuilder) []*cmv1.Taint {
+			taints := []*cmv1.Taint{}
+			for _, builder := range builders {
+				taint, err := builder.Build()
+				Expect(err).NotTo(HaveOccurred())
+				taints = append(taints, taint)
+			}
+			return taints
+		}
+
+		It("parses valid taints", func() {
+			builders, err := ParseTaints("dedicated=gpu:NoSchedule,example.com/spot:PreferNoSchedule")
+			Expect(err).NotTo(HaveOccurred())
+			taints := build(builders)
+			Expect(taints).To(HaveLen(2))
+			Expect(taints[0].Key()).To(Equal("dedicated"))
+			Expect(taints[0].Value()).To(Equal("gpu"))
+			Expect(taints[0].Effect()).To(Equal("NoSchedule"))
+			Expect(taints[1].Key()).To(Equal("example.com/spot"))
+			Expect(taints[1].Value()).To(BeEmpty())
+			Expect(taints[1].Effect()).To(Equal("PreferNoSchedule"))
+			Expect(FormatTaints(taints)).To(Equal(
+				"dedicated=gpu:NoSchedule,example.com/spot=:PreferNoSchedule"))
+		})
+
+		It("rejects taints without an effect", func() {
+			_, err := ParseTaints("dedicated=gpu")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("rejects invalid effects", func() {
+			_, err := ParseTaints("dedicated=gpu:NoWay")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("NoWay"))
+		})
+	})
+
+	Context("FormatLabels", func() {
+		It("sorts the labels by key", func() {
+			Expect(FormatLabels(map[string]string{"b": "2", "a": "1"})).To(Equal("a=1,b=2"))
+		})
+	})
+})
@@ -17,11 +17,12 @@ limitations under the License.
 package machines
 
 import (
-	"errors"
 	"fmt"
 	"strings"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
 )
 
 func GetMachineTypes(client *cmv1.Client) (machineTypes []*cmv1.MachineType, err error) {
@@ -37,11 +38,7 @@ func GetMachineTypes(client *cmv1.Client) (machineTypes []*cmv1.MachineType, err
 			Size(size).
 			Send()
 		if err != nil {
-			errMsg := response.Error().Reason()
-			if errMsg == "" {
-				errMsg = err.Error()
-			}
-			return nil, errors.New(errMsg)
+			return nil, rerrors.FromOCM(response.Error(), err)
 		}
 		machineTypes = append(machineTypes, response.Items().Slice()...)
 		if response.Size() < size {
@@ -85,3 +82,11 @@ func GetMachineTypeList(client *cmv1.Client) (machineTypeList []string, err erro
 
 	return
 }
+
+// FormatMemory formats the memory of a machine type, converting it to GiB when it is in bytes.
+func FormatMemory(memory *cmv1.Value) string {
+	if memory.Unit() == "B" {
+		return fmt.Sprintf("%.1f GiB", memory.Value()/(1<<30))
+	}
+	return fmt.Sprintf("%g %s", memory.Value(), memory.Unit())
+}
@@ -0,0 +1,75 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains an implementation of the http.RoundTripper interface that remembers the
+// operation identifier that the OCM API assigns to requests that change resources, so that the
+// operations started by the tool can be correlated with the logs of the API.
+
+package ocm
+
+import (
+	"net/http"
+	"sync"
+)
+
+// OperationIDHeader is the response header that contains the identifier that the OCM API assigns
+// to each request.
+const OperationIDHeader = "X-Operation-Id"
+
+var (
+	lastOperationIDLock sync.Mutex
+	lastOperationID     string
+)
+
+// LastOperationID returns the operation identifier of the last request sent by this process that
+// changed a resource, or an empty string if there is none.
+func LastOperationID() string {
+	lastOperationIDLock.Lock()
+	defer lastOperationIDLock.Unlock()
+	return lastOperationID
+}
+
+// OperationIDRoundTripper is a round tripper that remembers the operation identifiers returned by
+// the OCM API. Don't create instances of this type directly; use the NewOperationIDRoundTripper
+// function instead.
+type OperationIDRoundTripper struct {
+	next http.RoundTripper
+}
+
+// NewOperationIDRoundTripper creates a round tripper that passes requests to the next round tripper
+// and remembers the operation identifiers of the responses to requests that aren't reads.
+func NewOperationIDRoundTripper(next http.RoundTripper) *OperationIDRoundTripper {
+	return &OperationIDRoundTripper{
+		next: next,
+	}
+}
+
+// Make sure that we implement the http.RoundTripper interface:
+var _ http.RoundTripper = &OperationIDRoundTripper{}
+
+// RoundTrip is the implementation of the http.RoundTripper interface.
+func (t *OperationIDRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	response, err := t.next.RoundTrip(request)
+	if err != nil || request.Method == http.MethodGet || request.Method == http.MethodHead {
+		return response, err
+	}
+	if operationID := response.Header.Get(OperationIDHeader); operationID != "" {
+		lastOperationIDLock.Lock()
+		lastOperationID = operationID
+		lastOperationIDLock.Unlock()
+	}
+	return response, err
+}
@@ -0,0 +1,48 @@
+package ocm_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/ocm"
+)
+
+var _ = Describe("OperationIDRoundTripper", func() {
+	var (
+		server *httptest.Server
+		client *http.Client
+	)
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(OperationIDHeader, strings.ToLower(r.Method)+"-id")
+			w.WriteHeader(http.StatusOK)
+		}))
+		client = &http.Client{Transport: NewOperationIDRoundTripper(http.DefaultTransport)}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Remembers the operation identifier of requests that change resources", func() {
+		response, err := client.Post(server.URL, "application/json", strings.NewReader("{}"))
+		Expect(err).NotTo(HaveOccurred())
+		response.Body.Close()
+		Expect(LastOperationID()).To(Equal("post-id"))
+	})
+
+	It("Ignores the operation identifier of reads", func() {
+		response, err := client.Post(server.URL, "application/json", strings.NewReader("{}"))
+		Expect(err).NotTo(HaveOccurred())
+		response.Body.Close()
+		response, err = client.Get(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		response.Body.Close()
+		Expect(LastOperationID()).To(Equal("post-id"))
+	})
+})
@@ -0,0 +1,63 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to implement the '--owner-arn' command line option.
+
+package ocm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/spf13/pflag"
+)
+
+// AddOwnerARNFlag adds the '--owner-arn' flag to the given set of command line flags.
+func AddOwnerARNFlag(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&ownerARN,
+		"owner-arn",
+		"",
+		"ARN of the AWS user or role that created the cluster, used instead of the ARN of the "+
+			"current user to find the cluster. Allows organization administrators to manage "+
+			"clusters created by other members of the organization.",
+	)
+}
+
+// OwnerARN returns the ARN given with the '--owner-arn' flag, or the given creator ARN if the flag
+// wasn't used.
+func OwnerARN(creatorARN string) string {
+	if ownerARN != "" {
+		return ownerARN
+	}
+	return creatorARN
+}
+
+// ValidateOwnerARN checks that the value of the '--owner-arn' flag, if any, is a valid ARN that
+// can be safely used in a search query.
+func ValidateOwnerARN() error {
+	if ownerARN == "" {
+		return nil
+	}
+	if strings.ContainsAny(ownerARN, "'\"%") || !arn.IsARN(ownerARN) {
+		return fmt.Errorf("Invalid owner ARN '%s'", ownerARN)
+	}
+	return nil
+}
+
+// ownerARN is the value of the '--owner-arn' flag.
+var ownerARN string
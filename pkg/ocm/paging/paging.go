@@ -0,0 +1,139 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the types and functions used by list commands to load large collections one
+// page at a time, so that rows can be printed as soon as each page arrives.
+
+package paging
+
+import (
+	"github.com/spf13/pflag"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// DefaultLimit is the number of items shown by list commands unless '--limit' or '--all' is given.
+const DefaultLimit = 100
+
+// PageSize is the number of items requested in each page.
+const PageSize = 100
+
+// Options are the items of the collection that the user wants to see.
+type Options struct {
+	// Maximum number of items, ignored when all the items are requested:
+	Limit int
+
+	// Number of the page of items to show, starting with one, where each page contains 'Limit'
+	// items:
+	Page int
+
+	// Show all the items, starting with the first one:
+	All bool
+}
+
+// AddFlags adds the '--limit', '--page' and '--all' flags to the given set of command line flags.
+func AddFlags(flags *pflag.FlagSet, options *Options) {
+	flags.IntVar(
+		&options.Limit,
+		"limit",
+		DefaultLimit,
+		"Maximum number of items to show.",
+	)
+	flags.IntVar(
+		&options.Page,
+		"page",
+		1,
+		"Number of the page of items to show, where each page contains '--limit' items.",
+	)
+	flags.BoolVar(
+		&options.All,
+		"all",
+		false,
+		"Show all the items, ignoring '--limit' and '--page'.",
+	)
+}
+
+// Validate checks that the options are valid.
+func (o Options) Validate() error {
+	if o.All {
+		return nil
+	}
+	if o.Limit < 1 {
+		return rerrors.ValidationErrorf("Limit must be at least 1, use '--all' to show all the items")
+	}
+	if o.Page < 1 {
+		return rerrors.ValidationErrorf("Page must be at least 1")
+	}
+	return nil
+}
+
+// Pager calculates the pages that need to be loaded to get the items requested by the user, and
+// which items of each page are part of the result.
+type Pager struct {
+	page      int
+	skip      int
+	remaining int
+	done      bool
+}
+
+// Pager returns a pager that starts with the first page containing requested items.
+func (o Options) Pager() *Pager {
+	if o.All {
+		return &Pager{page: 1, remaining: -1}
+	}
+	offset := (o.Page - 1) * o.Limit
+	return &Pager{
+		page:      offset/PageSize + 1,
+		skip:      offset % PageSize,
+		remaining: o.Limit,
+	}
+}
+
+// Next returns true if there are more pages to load.
+func (p *Pager) Next() bool {
+	return !p.done
+}
+
+// Page returns the number of the page to load, starting with one.
+func (p *Pager) Page() int {
+	return p.page
+}
+
+// Size returns the size of the page to load.
+func (p *Pager) Size() int {
+	return PageSize
+}
+
+// Select receives the number of items of the page that was loaded and returns the range of those
+// items that are part of the result, from the first one to the last one, excluding it. It moves to
+// the next page, if any.
+func (p *Pager) Select(count int) (from int, to int) {
+	from = p.skip
+	if from > count {
+		from = count
+	}
+	to = count
+	if p.remaining >= 0 && to-from > p.remaining {
+		to = from + p.remaining
+	}
+	if p.remaining >= 0 {
+		p.remaining -= to - from
+	}
+	p.skip = 0
+	p.page++
+	p.done = count < PageSize || p.remaining == 0
+	return
+}
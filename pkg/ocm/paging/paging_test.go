@@ -0,0 +1,86 @@
+package paging_test
+
+import (
+	"github.com/spf13/pflag"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	. "github.com/openshift/moactl/pkg/ocm/paging"
+)
+
+var _ = Describe("Paging", func() {
+	// load simulates a collection with the given number of items, and returns the indexes of the
+	// items selected by the pager and the pages loaded.
+	load := func(options Options, total int) (items []int, pages []int) {
+		for pager := options.Pager(); pager.Next(); {
+			page := pager.Page()
+			pages = append(pages, page)
+			first := (page - 1) * pager.Size()
+			count := total - first
+			if count > pager.Size() {
+				count = pager.Size()
+			}
+			if count < 0 {
+				count = 0
+			}
+			from, to := pager.Select(count)
+			for i := from; i < to; i++ {
+				items = append(items, first+i)
+			}
+		}
+		return
+	}
+
+	It("Loads only the first page by default", func() {
+		items, pages := load(Options{Limit: DefaultLimit, Page: 1}, 250)
+		Expect(items).To(HaveLen(DefaultLimit))
+		Expect(items[0]).To(Equal(0))
+		Expect(pages).To(Equal([]int{1}))
+	})
+
+	It("Loads all the pages", func() {
+		items, pages := load(Options{All: true}, 250)
+		Expect(items).To(HaveLen(250))
+		Expect(items[249]).To(Equal(249))
+		Expect(pages).To(Equal([]int{1, 2, 3}))
+	})
+
+	It("Loads several pages to reach the limit", func() {
+		items, pages := load(Options{Limit: 150, Page: 1}, 1000)
+		Expect(items).To(HaveLen(150))
+		Expect(pages).To(Equal([]int{1, 2}))
+	})
+
+	It("Skips the items of the previous pages of the user", func() {
+		items, pages := load(Options{Limit: 30, Page: 4}, 1000)
+		Expect(items).To(HaveLen(30))
+		Expect(items[0]).To(Equal(90))
+		Expect(items[29]).To(Equal(119))
+		Expect(pages).To(Equal([]int{1, 2}))
+	})
+
+	It("Stops at the end of the collection", func() {
+		items, _ := load(Options{Limit: 50, Page: 2}, 70)
+		Expect(items).To(HaveLen(20))
+		items, _ = load(Options{Limit: 50, Page: 3}, 70)
+		Expect(items).To(BeEmpty())
+	})
+
+	It("Adds the flags", func() {
+		options := Options{}
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		AddFlags(flags, &options)
+		Expect(flags.Parse([]string{"--limit=5", "--page=2"})).To(Succeed())
+		Expect(options).To(Equal(Options{Limit: 5, Page: 2}))
+	})
+
+	It("Rejects invalid limits and pages", func() {
+		err := Options{Limit: 0, Page: 1}.Validate()
+		Expect(err).To(HaveOccurred())
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+		Expect(Options{Limit: 10, Page: 0}.Validate()).NotTo(Succeed())
+		Expect(Options{All: true}.Validate()).To(Succeed())
+	})
+})
@@ -19,6 +19,10 @@ limitations under the License.
 
 package properties
 
+import (
+	"strings"
+)
+
 // Prefix used by all the property names:
 const prefix = "rosa_"
 
@@ -27,3 +31,16 @@ const prefix = "rosa_"
 const CreatorARN = prefix + "creator_arn"
 
 const CLIVersion = prefix + "cli_version"
+
+// DeleteProtection is the name of the property that indicates that the cluster can't be deleted
+// unless the protection is explicitly overridden:
+const DeleteProtection = prefix + "delete_protection"
+
+// Registered is the name of the property that contains the time when a cluster that wasn't created
+// with rosa was registered to be managed by it:
+const Registered = prefix + "registered"
+
+// IsReserved checks if the property is used by rosa itself, so that users can't change it.
+func IsReserved(name string) bool {
+	return strings.HasPrefix(name, prefix)
+}
@@ -17,12 +17,12 @@ limitations under the License.
 package regions
 
 import (
-	"errors"
 	"fmt"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 
 	"github.com/openshift/moactl/pkg/aws"
+	rerrors "github.com/openshift/moactl/pkg/errors"
 	"github.com/openshift/moactl/pkg/logging"
 	rprtr "github.com/openshift/moactl/pkg/reporter"
 )
@@ -40,7 +40,7 @@ func GetRegions(client *cmv1.Client) (regions []*cmv1.CloudRegion, err error) {
 
 	awsClient, err := aws.NewClient().
 		Logger(logger).
-		Region(aws.DefaultRegion).
+		Region(aws.GetDefaultRegion()).
 		Build()
 	if err != nil {
 		return nil, fmt.Errorf("Error creating AWS client: %v", err)
@@ -73,11 +73,7 @@ func GetRegions(client *cmv1.Client) (regions []*cmv1.CloudRegion, err error) {
 			Body(awsCredentials).
 			Send()
 		if err != nil {
-			errMsg := response.Error().Reason()
-			if errMsg == "" {
-				errMsg = err.Error()
-			}
-			return nil, errors.New(errMsg)
+			return nil, rerrors.FromOCM(response.Error(), err)
 		}
 		regions = append(regions, response.Items().Slice()...)
 		if response.Size() < size {
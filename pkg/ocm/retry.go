@@ -0,0 +1,280 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains an implementation of the http.RoundTripper interface that retries requests
+// that fail because of transient errors or because of rate limiting.
+
+package ocm
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+)
+
+// Default values of the retry parameters:
+const (
+	DefaultMaxRetries   = 3
+	defaultBackoff      = 1 * time.Second
+	defaultMaxBackoff   = 30 * time.Second
+	maxRetryAfterDelay  = 60 * time.Second
+	retryAfterHeaderKey = "Retry-After"
+)
+
+// maxRetries is the value of the '--max-retries' command line option.
+var maxRetries = DefaultMaxRetries
+
+// AddMaxRetriesFlag adds the '--max-retries' flag to the given set of command line flags.
+func AddMaxRetriesFlag(flags *pflag.FlagSet) {
+	flags.IntVar(
+		&maxRetries,
+		"max-retries",
+		DefaultMaxRetries,
+		"Maximum number of times that requests to the OCM API are retried when they fail "+
+			"because of transient errors or rate limiting. Use zero to disable retries.",
+	)
+}
+
+// RetryRoundTripperBuilder contains the information and logic needed to build a round tripper that
+// retries failed requests. Don't create instances of this type directly; use the
+// NewRetryRoundTripper function instead.
+type RetryRoundTripperBuilder struct {
+	logger     *logrus.Logger
+	maxRetries int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	next       http.RoundTripper
+}
+
+// RetryRoundTripper is a round tripper that retries requests that fail with transient errors,
+// waiting an exponentially increasing and randomized amount of time between attempts. Don't
+// create instances of this type directly; use the NewRetryRoundTripper function instead.
+type RetryRoundTripper struct {
+	logger     *logrus.Logger
+	maxRetries int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	next       http.RoundTripper
+}
+
+// Make sure that we implement the http.RoundTripper interface:
+var _ http.RoundTripper = &RetryRoundTripper{}
+
+// NewRetryRoundTripper creates a builder that can then be used to create a round tripper that
+// retries failed requests.
+func NewRetryRoundTripper() *RetryRoundTripperBuilder {
+	return &RetryRoundTripperBuilder{
+		maxRetries: DefaultMaxRetries,
+		backoff:    defaultBackoff,
+		maxBackoff: defaultMaxBackoff,
+	}
+}
+
+// Logger sets the logger that the round tripper will use to report retries. This is mandatory.
+func (b *RetryRoundTripperBuilder) Logger(value *logrus.Logger) *RetryRoundTripperBuilder {
+	b.logger = value
+	return b
+}
+
+// MaxRetries sets the maximum number of times that a request is retried. The default is three.
+func (b *RetryRoundTripperBuilder) MaxRetries(value int) *RetryRoundTripperBuilder {
+	b.maxRetries = value
+	return b
+}
+
+// Backoff sets the initial and maximum delays between attempts. The delay is doubled after each
+// attempt, till it reaches the maximum. The default is one and thirty seconds.
+func (b *RetryRoundTripperBuilder) Backoff(initial, max time.Duration) *RetryRoundTripperBuilder {
+	b.backoff = initial
+	b.maxBackoff = max
+	return b
+}
+
+// Next sets the next round tripper, the one that actually sends the requests.
+func (b *RetryRoundTripperBuilder) Next(value http.RoundTripper) *RetryRoundTripperBuilder {
+	b.next = value
+	return b
+}
+
+// Build uses the information stored in the builder to create a new round tripper that retries
+// failed requests.
+func (b *RetryRoundTripperBuilder) Build() (result *RetryRoundTripper, err error) {
+	// Check parameters:
+	if b.logger == nil {
+		err = fmt.Errorf("Logger is mandatory")
+		return
+	}
+	if b.next == nil {
+		err = fmt.Errorf("Next handler is mandatory")
+		return
+	}
+	if b.maxRetries < 0 {
+		err = fmt.Errorf("Maximum number of retries must be zero or positive, but it is %d",
+			b.maxRetries)
+		return
+	}
+
+	// Create and populate the object:
+	result = &RetryRoundTripper{
+		logger:     b.logger,
+		maxRetries: b.maxRetries,
+		backoff:    b.backoff,
+		maxBackoff: b.maxBackoff,
+		next:       b.next,
+	}
+
+	return
+}
+
+// RoundTrip is the implementation of the http.RoundTripper interface.
+func (r *RetryRoundTripper) RoundTrip(request *http.Request) (response *http.Response, err error) {
+	// Read the complete body in memory, so that it can be sent again in each attempt:
+	var body []byte
+	if request.Body != nil {
+		body, err = ioutil.ReadAll(request.Body)
+		if err != nil {
+			return
+		}
+		err = request.Body.Close()
+		if err != nil {
+			return
+		}
+	}
+
+	backoff := r.backoff
+	for attempt := 0; ; attempt++ {
+		if body != nil {
+			request.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		// Nothing of the request has been sent till a connection is obtained, so connection
+		// errors before that can be retried for all the methods:
+		connected := false
+		trace := &httptrace.ClientTrace{
+			GotConn: func(httptrace.GotConnInfo) {
+				connected = true
+			},
+		}
+		response, err = r.next.RoundTrip(
+			request.WithContext(httptrace.WithClientTrace(request.Context(), trace)),
+		)
+		if attempt >= r.maxRetries || !retriable(request, response, err, connected) {
+			return
+		}
+
+		delay := jitter(backoff)
+		if response != nil {
+			if retryAfter, ok := parseRetryAfter(response.Header.Get(retryAfterHeaderKey)); ok {
+				delay = retryAfter
+			}
+			r.logger.Debugf("Request '%s %s' failed with status code %d, retrying in %s",
+				request.Method, request.URL, response.StatusCode, delay)
+			// Discard the body of the failed response so that the connection can be reused:
+			_, _ = ioutil.ReadAll(response.Body)
+			_ = response.Body.Close()
+		} else {
+			r.logger.Debugf("Request '%s %s' failed with error '%v', retrying in %s",
+				request.Method, request.URL, err, delay)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-request.Context().Done():
+			timer.Stop()
+			return nil, request.Context().Err()
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if backoff > r.maxBackoff {
+			backoff = r.maxBackoff
+		}
+	}
+}
+
+// retriable checks if the request can be sent again. Rate limited requests and requests rejected
+// because the service is unavailable haven't been processed by the server, so they are always
+// retried, and so are connection errors that happen before anything is sent. Gateway errors, other
+// server errors and other connection errors are only retried for idempotent methods, as the server
+// may have processed the request already.
+func retriable(request *http.Request, response *http.Response, err error, connected bool) bool {
+	if request.Context().Err() != nil {
+		return false
+	}
+	if err != nil {
+		return !connected || idempotent(request.Method)
+	}
+	switch response.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusServiceUnavailable:
+		return true
+	case http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusGatewayTimeout:
+		return idempotent(request.Method)
+	}
+	return false
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// jitter returns a random delay between half and the totality of the given delay, so that clients
+// that failed at the same time don't retry at the same time.
+func jitter(delay time.Duration) time.Duration {
+	half := int64(delay / 2)
+	if half <= 0 {
+		return delay
+	}
+	// #nosec G404
+	return time.Duration(half + rand.Int63n(half))
+}
+
+// parseRetryAfter parses the value of the 'Retry-After' header, which can be a number of seconds
+// or a date. Long delays are truncated so that the command doesn't appear to hang.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	var delay time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		delay = time.Until(date)
+	} else {
+		return 0, false
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	if delay > maxRetryAfterDelay {
+		delay = maxRetryAfterDelay
+	}
+	return delay, true
+}
@@ -0,0 +1,173 @@
+package ocm_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+
+	. "github.com/openshift/moactl/pkg/ocm"
+)
+
+var _ = Describe("RetryRoundTripper", func() {
+	var (
+		server   *httptest.Server
+		statuses []int
+		bodies   []string
+		client   *http.Client
+	)
+
+	BeforeEach(func() {
+		bodies = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			status := statuses[0]
+			if len(statuses) > 1 {
+				statuses = statuses[1:]
+			}
+			if status == http.StatusTooManyRequests {
+				w.Header().Set("Retry-After", "0")
+			}
+			w.WriteHeader(status)
+		}))
+		retrier, err := NewRetryRoundTripper().
+			Logger(logrus.New()).
+			MaxRetries(2).
+			Backoff(time.Millisecond, 2*time.Millisecond).
+			Next(http.DefaultTransport).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+		client = &http.Client{Transport: retrier}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Retries rate limited requests with the same body", func() {
+		statuses = []int{http.StatusTooManyRequests, http.StatusOK}
+		response, err := client.Post(server.URL, "application/json", strings.NewReader("{}"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+		Expect(bodies).To(Equal([]string{"{}", "{}"}))
+	})
+
+	It("Gives up after the maximum number of retries", func() {
+		statuses = []int{http.StatusServiceUnavailable}
+		response, err := client.Get(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusServiceUnavailable))
+		Expect(bodies).To(HaveLen(3))
+	})
+
+	It("Doesn't retry internal server errors of non idempotent requests", func() {
+		statuses = []int{http.StatusInternalServerError, http.StatusOK}
+		response, err := client.Post(server.URL, "application/json", strings.NewReader("{}"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusInternalServerError))
+		Expect(bodies).To(HaveLen(1))
+	})
+
+	It("Retries internal server errors of idempotent requests", func() {
+		statuses = []int{http.StatusInternalServerError, http.StatusOK}
+		response, err := client.Get(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+		Expect(bodies).To(HaveLen(2))
+	})
+
+	It("Doesn't retry gateway errors of non idempotent requests", func() {
+		statuses = []int{http.StatusBadGateway, http.StatusOK}
+		response, err := client.Post(server.URL, "application/json", strings.NewReader("{}"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusBadGateway))
+		Expect(bodies).To(HaveLen(1))
+	})
+
+	It("Retries gateway errors of idempotent requests", func() {
+		statuses = []int{http.StatusGatewayTimeout, http.StatusOK}
+		response, err := client.Get(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+		Expect(bodies).To(HaveLen(2))
+	})
+
+	It("Doesn't retry client errors", func() {
+		statuses = []int{http.StatusBadRequest, http.StatusOK}
+		response, err := client.Get(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+		Expect(bodies).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("RetryRoundTripper connection errors", func() {
+	var (
+		attempts int
+		client   *http.Client
+	)
+
+	BeforeEach(func() {
+		attempts = 0
+		retrier, err := NewRetryRoundTripper().
+			Logger(logrus.New()).
+			MaxRetries(2).
+			Backoff(time.Millisecond, 2*time.Millisecond).
+			Next(roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+				attempts++
+				return http.DefaultTransport.RoundTrip(request)
+			})).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+		client = &http.Client{Transport: retrier}
+	})
+
+	It("Retries non idempotent requests that couldn't connect", func() {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+		_, err := client.Post(server.URL, "application/json", strings.NewReader("{}"))
+		Expect(err).To(HaveOccurred())
+		Expect(attempts).To(Equal(3))
+	})
+
+	Context("When the connection is closed after the request is sent", func() {
+		var server *httptest.Server
+
+		BeforeEach(func() {
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				conn, _, err := w.(http.Hijacker).Hijack()
+				Expect(err).NotTo(HaveOccurred())
+				_ = conn.Close()
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("Doesn't retry non idempotent requests", func() {
+			_, err := client.Post(server.URL, "application/json", strings.NewReader("{}"))
+			Expect(err).To(HaveOccurred())
+			Expect(attempts).To(Equal(1))
+		})
+
+		It("Retries idempotent requests", func() {
+			_, err := client.Get(server.URL)
+			Expect(err).To(HaveOccurred())
+			Expect(attempts).To(Equal(3))
+		})
+	})
+})
+
+// roundTripperFunc is an adapter that allows the use of ordinary functions as round trippers.
+type roundTripperFunc func(request *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
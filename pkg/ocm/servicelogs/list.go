@@ -0,0 +1,117 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to list the entries of the OCM service log of a cluster.
+
+package servicelogs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/paging"
+)
+
+// Severities contains the severities of the service log entries, from the lowest to the highest.
+var Severities = []string{
+	string(slv1.SeverityDebug),
+	string(slv1.SeverityInfo),
+	string(slv1.SeverityWarning),
+	string(slv1.SeverityError),
+	string(slv1.SeverityFatal),
+}
+
+// SeveritiesFrom returns the severities that are equal or higher than the given one.
+func SeveritiesFrom(minimum string) ([]string, error) {
+	for i, severity := range Severities {
+		if strings.EqualFold(severity, minimum) {
+			return Severities[i:], nil
+		}
+	}
+	return nil, rerrors.ValidationErrorf("Invalid severity '%s', expected one of: %s", minimum,
+		strings.Join(Severities, ", "))
+}
+
+// ParseSince parses the value of the '--since' flag, which can be a duration like '12h' or '3d',
+// or a date like '2021-03-01', and returns the corresponding time.
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	if strings.HasSuffix(value, "d") {
+		days := 0
+		_, err := fmt.Sscanf(value, "%dd", &days)
+		if err == nil && fmt.Sprintf("%dd", days) == value && days > 0 {
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+	duration, err := time.ParseDuration(value)
+	if err == nil && duration > 0 {
+		return now.Add(-duration), nil
+	}
+	date, err := time.ParseInLocation("2006-01-02", value, now.Location())
+	if err == nil {
+		return date, nil
+	}
+	return time.Time{}, rerrors.ValidationErrorf("Invalid value '%s' for '--since': expected a "+
+		"positive duration like '12h' or '3d', or a date like '2006-01-02'", value)
+}
+
+// Query returns the search query that selects the entries of the cluster with the given external
+// identifier, with one of the given severities and created after the given time. The severities and
+// the time are optional.
+func Query(clusterUUID string, severities []string, since time.Time) string {
+	query := fmt.Sprintf("cluster_uuid = '%s'", clusterUUID)
+	if len(severities) > 0 && len(severities) < len(Severities) {
+		quoted := make([]string, len(severities))
+		for i, severity := range severities {
+			quoted[i] = fmt.Sprintf("'%s'", severity)
+		}
+		query += fmt.Sprintf(" and severity in (%s)", strings.Join(quoted, ", "))
+	}
+	if !since.IsZero() {
+		query += fmt.Sprintf(" and timestamp >= '%s'", since.UTC().Format(time.RFC3339))
+	}
+	return query
+}
+
+// ListLogEntries loads the entries of the service log that match the query, newest first, one page
+// at a time, and calls the function with the requested entries of each page as soon as it arrives.
+func ListLogEntries(client *slv1.Client, query string, pages paging.Options,
+	fn func(entries []*slv1.LogEntry) error) error {
+	for pager := pages.Pager(); pager.Next(); {
+		response, err := client.ClusterLogs().List().
+			Search(query).
+			Order("timestamp desc").
+			Page(pager.Page()).
+			Size(pager.Size()).
+			Send()
+		if err != nil {
+			return rerrors.FromOCM(response.Error(), err)
+		}
+		entries := response.Items().Slice()
+		from, to := pager.Select(len(entries))
+		if from == to {
+			continue
+		}
+		err = fn(entries[from:to])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
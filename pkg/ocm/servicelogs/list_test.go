@@ -0,0 +1,54 @@
+package servicelogs_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/servicelogs"
+)
+
+var _ = Describe("List", func() {
+	now := time.Date(2021, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+	It("Returns the severities equal or higher than the minimum", func() {
+		severities, err := servicelogs.SeveritiesFrom("Warning")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(severities).To(Equal([]string{"warning", "error", "fatal"}))
+
+		_, err = servicelogs.SeveritiesFrom("critical")
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+	})
+
+	DescribeTable("ParseSince",
+		func(value string, expected time.Time) {
+			since, err := servicelogs.ParseSince(value, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(since).To(Equal(expected))
+		},
+		Entry("Hours", "12h", time.Date(2021, time.March, 10, 0, 0, 0, 0, time.UTC)),
+		Entry("Days", "3d", time.Date(2021, time.March, 7, 12, 0, 0, 0, time.UTC)),
+		Entry("Date", "2021-03-01", time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)),
+	)
+
+	DescribeTable("Invalid values of since",
+		func(value string) {
+			_, err := servicelogs.ParseSince(value, now)
+			Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+		},
+		Entry("Negative", "-3h"),
+		Entry("Zero days", "0d"),
+		Entry("Garbage", "yesterday"),
+	)
+
+	It("Builds the search query", func() {
+		Expect(servicelogs.Query("abc", servicelogs.Severities, time.Time{})).To(Equal(
+			"cluster_uuid = 'abc'"))
+		Expect(servicelogs.Query("abc", []string{"error", "fatal"}, now)).To(Equal(
+			"cluster_uuid = 'abc' and severity in ('error', 'fatal') and " +
+				"timestamp >= '2021-03-10T12:00:00Z'"))
+	})
+})
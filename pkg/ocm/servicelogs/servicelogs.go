@@ -0,0 +1,82 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to add the entries of the audit log to the OCM service log
+// of the clusters.
+
+package servicelogs
+
+import (
+	"fmt"
+
+	slv1 "github.com/openshift-online/ocm-sdk-go/servicelogs/v1"
+
+	"github.com/openshift/moactl/pkg/audit"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/ocm"
+)
+
+// ServiceName is the name of the service used in the entries added to the service log.
+const ServiceName = "rosa-cli"
+
+// NewSender returns an audit sender that adds the entries to the service log of the cluster that
+// they refer to, using a new connection to the OCM API.
+func NewSender() audit.Sender {
+	return func(entry *audit.Entry) error {
+		if entry.AWSUser == "" {
+			return fmt.Errorf("The AWS user that owns the cluster is unknown")
+		}
+		logger, err := logging.NewLogger().Build()
+		if err != nil {
+			return err
+		}
+		connection, err := ocm.NewConnection().
+			Logger(logger).
+			Build()
+		if err != nil {
+			return err
+		}
+		defer connection.Close()
+
+		cluster, err := ocm.GetCluster(connection.ClustersMgmt().V1().Clusters(), entry.Cluster,
+			entry.AWSUser)
+		if err != nil {
+			return err
+		}
+		summary, description := audit.Describe(entry)
+		severity := slv1.SeverityInfo
+		if entry.Result != audit.ResultSuccess {
+			severity = slv1.SeverityWarning
+		}
+		logEntry, err := slv1.NewLogEntry().
+			ClusterUUID(cluster.ExternalID()).
+			ServiceName(ServiceName).
+			Severity(severity).
+			Summary(summary).
+			Description(description).
+			Timestamp(entry.Timestamp).
+			Build()
+		if err != nil {
+			return err
+		}
+		response, err := connection.ServiceLogs().V1().ClusterLogs().Add().Body(logEntry).Send()
+		if err != nil {
+			return rerrors.FromOCM(response.Error(), err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,13 @@
+package servicelogs_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestServiceLogs(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Service Logs Suite")
+}
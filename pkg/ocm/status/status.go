@@ -0,0 +1,154 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to aggregate the health of the OCM account, the AWS
+// account and the clusters of the user in a single summary.
+
+package status
+
+import (
+	"fmt"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/cluster"
+)
+
+// Result is the outcome of a single check.
+type Result struct {
+	Name    string
+	Passed  bool
+	Details string
+}
+
+// Summary contains the results of all the checks and the clusters owned by the user.
+type Summary struct {
+	Results  []Result
+	Clusters []*cmv1.Cluster
+}
+
+// Failed returns the number of checks that didn't pass.
+func (s *Summary) Failed() int {
+	failed := 0
+	for _, result := range s.Results {
+		if !result.Passed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Collect runs all the checks and loads the clusters of the user. The connection and the AWS client
+// are optional: when they couldn't be created the given errors are reported as failed checks, and
+// the checks that depend on them are skipped.
+func Collect(connection *sdk.Connection, connectionErr error, awsClient aws.Client,
+	awsClientErr error) *Summary {
+	summary := &Summary{}
+
+	var account string
+	if connection != nil {
+		account, connectionErr = CheckOCM(connection)
+	}
+	if connectionErr != nil {
+		summary.add("OCM connectivity", false, connectionErr.Error())
+	} else {
+		summary.add("OCM connectivity", true, fmt.Sprintf("Logged in as '%s' on %s", account, connection.URL()))
+	}
+
+	var creator *aws.Creator
+	if awsClient != nil {
+		creator, awsClientErr = awsClient.GetCreator()
+	}
+	if awsClientErr != nil {
+		summary.add("AWS credentials", false, awsClientErr.Error())
+	} else {
+		summary.add("AWS credentials", true, fmt.Sprintf("Using '%s'", creator.ARN))
+		summary.Results = append(summary.Results, CheckAWSPermissions(awsClient), CheckAWSQuota(awsClient))
+	}
+
+	if connection != nil && connectionErr == nil && creator != nil {
+		clusters, err := cluster.GetClusters(connection.ClustersMgmt().V1().Clusters(), creator.ARN, 100)
+		if err != nil {
+			summary.add("Clusters", false, fmt.Sprintf("Failed to get clusters: %v", err))
+		} else {
+			summary.Clusters = clusters
+			summary.Results = append(summary.Results, CheckClusters(clusters))
+		}
+	}
+	return summary
+}
+
+// CheckOCM checks that the OCM API is reachable with the current credentials, returning the name of
+// the account of the user.
+func CheckOCM(connection *sdk.Connection) (string, error) {
+	response, err := connection.AccountsMgmt().V1().CurrentAccount().Get().Send()
+	if err != nil {
+		return "", fmt.Errorf("Failed to get current account: %v", err)
+	}
+	return response.Body().Username(), nil
+}
+
+// CheckAWSPermissions checks that the AWS account has the permissions needed to create clusters.
+func CheckAWSPermissions(client aws.Client) Result {
+	ok, err := client.ValidateSCP(nil)
+	if err != nil {
+		return Result{Name: "AWS permissions", Details: err.Error()}
+	}
+	if !ok {
+		return Result{Name: "AWS permissions", Details: "Failed to validate SCP policies"}
+	}
+	return Result{Name: "AWS permissions", Passed: true, Details: "SCP policies ok"}
+}
+
+// CheckAWSQuota checks that the AWS account has enough quota to create a cluster.
+func CheckAWSQuota(client aws.Client) Result {
+	_, err := client.ValidateQuota()
+	if err != nil {
+		return Result{Name: "AWS quota", Details: err.Error()}
+	}
+	return Result{Name: "AWS quota", Passed: true, Details: "Quota ok"}
+}
+
+// CheckClusters checks that none of the clusters is in the error state.
+func CheckClusters(clusters []*cmv1.Cluster) Result {
+	failed := 0
+	for _, c := range clusters {
+		if c.State() == cmv1.ClusterStateError {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return Result{
+			Name:    "Clusters",
+			Details: fmt.Sprintf("%d out of %d clusters are in error state", failed, len(clusters)),
+		}
+	}
+	return Result{
+		Name:    "Clusters",
+		Passed:  true,
+		Details: fmt.Sprintf("%d clusters, none in error state", len(clusters)),
+	}
+}
+
+func (s *Summary) add(name string, passed bool, details string) {
+	s.Results = append(s.Results, Result{
+		Name:    name,
+		Passed:  passed,
+		Details: details,
+	})
+}
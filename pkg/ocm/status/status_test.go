@@ -0,0 +1,45 @@
+package status_test
+
+import (
+	"errors"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/ocm/status"
+)
+
+var _ = Describe("Status", func() {
+	build := func(state cmv1.ClusterState) *cmv1.Cluster {
+		cluster, err := cmv1.NewCluster().State(state).Build()
+		Expect(err).NotTo(HaveOccurred())
+		return cluster
+	}
+
+	It("Passes when no cluster is in error state", func() {
+		result := CheckClusters([]*cmv1.Cluster{
+			build(cmv1.ClusterStateReady),
+			build(cmv1.ClusterStateInstalling),
+		})
+		Expect(result.Passed).To(BeTrue())
+	})
+
+	It("Fails when a cluster is in error state", func() {
+		result := CheckClusters([]*cmv1.Cluster{
+			build(cmv1.ClusterStateReady),
+			build(cmv1.ClusterStateError),
+		})
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Details).To(Equal("1 out of 2 clusters are in error state"))
+	})
+
+	It("Reports the clients that couldn't be created", func() {
+		summary := Collect(nil, errors.New("Not logged in"), nil, errors.New("No credentials"))
+		Expect(summary.Failed()).To(Equal(2))
+		Expect(summary.Results[0].Details).To(Equal("Not logged in"))
+		Expect(summary.Results[1].Details).To(Equal("No credentials"))
+		Expect(summary.Clusters).To(BeEmpty())
+	})
+})
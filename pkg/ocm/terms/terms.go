@@ -0,0 +1,117 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to check if the user needs to accept terms and conditions
+// before creating clusters, and to wait till they are accepted.
+
+package terms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// reviewPath is the path of the terms review of the current user, which the version of the SDK
+// that we use doesn't support yet.
+const reviewPath = "/api/authorizations/v1/self_terms_review"
+
+// DefaultURL is the page where the user can accept the terms when the review doesn't return a
+// more specific one.
+const DefaultURL = "https://cloud.redhat.com/openshift"
+
+// Review is the response of the terms review of the current user.
+type Review struct {
+	TermsAvailable bool   `json:"terms_available"`
+	TermsRequired  bool   `json:"terms_required"`
+	RedirectURL    string `json:"redirect_url"`
+}
+
+// Accepted returns true if the user doesn't need to accept any terms before creating clusters.
+func (r *Review) Accepted() bool {
+	return !r.TermsRequired
+}
+
+// URL returns the page where the user can review and accept the terms.
+func (r *Review) URL() string {
+	if r.RedirectURL == "" {
+		return DefaultURL
+	}
+	return r.RedirectURL
+}
+
+// GetReview asks the OCM API if the user needs to accept terms and conditions before creating
+// clusters.
+func GetReview(ctx context.Context, connection *sdk.Connection) (*Review, error) {
+	body, err := json.Marshal(map[string]string{
+		"event_code": "register",
+		"site_code":  "OCM",
+	})
+	if err != nil {
+		return nil, err
+	}
+	response, err := connection.Post().
+		Path(reviewPath).
+		Bytes(body).
+		SendContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to review terms and conditions: %v", err)
+	}
+	if response.Status() >= http.StatusBadRequest {
+		return nil, fmt.Errorf("Failed to review terms and conditions: unexpected status code %d",
+			response.Status())
+	}
+	review := &Review{}
+	err = json.Unmarshal(response.Bytes(), review)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse terms review: %v", err)
+	}
+	return review, nil
+}
+
+// Wait reviews the terms every interval till the user accepts them, the timeout expires or the
+// context is cancelled.
+func Wait(ctx context.Context, connection *sdk.Connection, interval time.Duration,
+	timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		review, err := GetReview(ctx, connection)
+		if ctx.Err() != nil {
+			return fmt.Errorf("Stopped waiting for terms and conditions to be accepted: %w", ctx.Err())
+		}
+		if err != nil {
+			return err
+		}
+		if review.Accepted() {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return rerrors.TimeoutErrorf("Timed out waiting for terms and conditions to be accepted at %s",
+				review.URL())
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Stopped waiting for terms and conditions to be accepted: %w", ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
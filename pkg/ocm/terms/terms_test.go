@@ -0,0 +1,30 @@
+package terms_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/ocm/terms"
+)
+
+var _ = Describe("Review", func() {
+	It("Is accepted when no terms are required", func() {
+		review := &Review{TermsAvailable: true}
+		Expect(review.Accepted()).To(BeTrue())
+	})
+
+	It("Isn't accepted when terms are required", func() {
+		review := &Review{TermsAvailable: true, TermsRequired: true}
+		Expect(review.Accepted()).To(BeFalse())
+	})
+
+	It("Returns the page returned by the review", func() {
+		review := &Review{TermsRequired: true, RedirectURL: "https://example.com/terms"}
+		Expect(review.URL()).To(Equal("https://example.com/terms"))
+	})
+
+	It("Returns the default page when the review doesn't return one", func() {
+		review := &Review{TermsRequired: true}
+		Expect(review.URL()).To(Equal(DefaultURL))
+	})
+})
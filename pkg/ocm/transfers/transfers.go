@@ -0,0 +1,253 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to transfer the ownership of a cluster to another Red Hat
+// account. The transfer is initiated by the current owner, and when the recipient accepts it OCM
+// replaces the owner of the subscription and the pull secret of the cluster.
+
+package transfers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	amsv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
+	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/link"
+)
+
+// transfersPath is the path of the collection of cluster transfers. The version of the SDK that we
+// use doesn't support transfers yet, so the requests are sent directly.
+const transfersPath = "/api/accounts_mgmt/v1/cluster_transfers"
+
+// Statuses of a transfer:
+const (
+	StatusPending   = "Pending"
+	StatusAccepted  = "Accepted"
+	StatusCompleted = "Completed"
+	StatusDeclined  = "Declined"
+	StatusRescinded = "Rescinded"
+)
+
+// Transfer is the request to change the owner of a cluster.
+type Transfer struct {
+	ID             string    `json:"id"`
+	ClusterUUID    string    `json:"cluster_uuid"`
+	Owner          string    `json:"owner"`
+	Recipient      string    `json:"recipient"`
+	Status         string    `json:"status"`
+	ExpirationDate time.Time `json:"expiration_date"`
+}
+
+// Done returns true if the transfer won't change anymore, either because the owner has been
+// replaced or because it has been declined or rescinded.
+func (t *Transfer) Done() bool {
+	return t.Status == StatusCompleted || t.Status == StatusDeclined || t.Status == StatusRescinded
+}
+
+// Active returns true if the transfer is waiting for the recipient or for the owner to be
+// replaced.
+func (t *Transfer) Active() bool {
+	return t.Status == StatusPending || t.Status == StatusAccepted
+}
+
+// GetRecipient finds the account with the given username, and checks that its organization has
+// an AWS account linked, as otherwise it wouldn't be able to own the cluster.
+func GetRecipient(connection *sdk.Connection, username string) (*amsv1.Account, error) {
+	response, err := connection.AccountsMgmt().V1().Accounts().List().
+		Search(fmt.Sprintf("username = '%s'", username)).
+		Size(1).
+		Send()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to find account '%s': %w", username, rerrors.FromOCM(
+			response.Error(), err))
+	}
+	if response.Items().Len() == 0 {
+		return nil, rerrors.NotFoundErrorf("Account '%s' doesn't exist", username)
+	}
+	account := response.Items().Get(0)
+	if account.Organization() == nil || account.Organization().ID() == "" {
+		return nil, rerrors.ValidationErrorf("Account '%s' doesn't belong to an organization", username)
+	}
+	quotas, err := link.GetClusterQuotas(connection, account.Organization().ID())
+	if err != nil {
+		return nil, err
+	}
+	result := link.CheckQuota(quotas)
+	if !result.Passed {
+		return nil, rerrors.ValidationErrorf("Account '%s' can't own the cluster, as it doesn't have "+
+			"an AWS account linked: %s", username, result.Details)
+	}
+	return account, nil
+}
+
+// ValidateUsername checks that the username is reasonably safe to use in a search, so that there
+// is no risk of SQL injection.
+func ValidateUsername(username string) error {
+	if username == "" {
+		return rerrors.ValidationErrorf("Username can't be empty")
+	}
+	for _, char := range username {
+		if char == '\'' || char == '"' || char == '\\' {
+			return rerrors.ValidationErrorf("Username '%s' isn't valid", username)
+		}
+	}
+	return nil
+}
+
+// GetActiveTransfer returns the transfer of the cluster with the given external identifier that
+// hasn't finished yet, or nil if there is none.
+func GetActiveTransfer(ctx context.Context, connection *sdk.Connection,
+	clusterUUID string) (*Transfer, error) {
+	response, err := connection.Get().
+		Path(transfersPath).
+		Parameter("search", fmt.Sprintf("cluster_uuid = '%s'", clusterUUID)).
+		Parameter("size", -1).
+		SendContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = checkResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	transfers, err := UnmarshalTransfers(response.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	for _, transfer := range transfers {
+		if transfer.Active() {
+			return transfer, nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateTransfer initiates the transfer of the cluster with the given external identifier from the
+// owner to the recipient.
+func CreateTransfer(ctx context.Context, connection *sdk.Connection, clusterUUID string,
+	owner string, recipient string) (*Transfer, error) {
+	body, err := json.Marshal(map[string]string{
+		"cluster_uuid": clusterUUID,
+		"owner":        owner,
+		"recipient":    recipient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	response, err := connection.Post().
+		Path(transfersPath).
+		Bytes(body).
+		SendContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = checkResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalTransfer(response.Bytes())
+}
+
+// GetTransfer loads the transfer with the given identifier.
+func GetTransfer(ctx context.Context, connection *sdk.Connection, id string) (*Transfer, error) {
+	response, err := connection.Get().
+		Path(fmt.Sprintf("%s/%s", transfersPath, id)).
+		SendContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = checkResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalTransfer(response.Bytes())
+}
+
+// Wait loads the transfer every interval till it is done, the timeout expires or the context is
+// cancelled. It fails if the transfer is declined or rescinded.
+func Wait(ctx context.Context, connection *sdk.Connection, id string, interval time.Duration,
+	timeout time.Duration) (*Transfer, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		transfer, err := GetTransfer(ctx, connection, id)
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("Stopped waiting for transfer '%s': %w", id, ctx.Err())
+		}
+		if err != nil {
+			return nil, err
+		}
+		if transfer.Done() {
+			if transfer.Status != StatusCompleted {
+				return transfer, rerrors.ConflictErrorf("Transfer '%s' has been %s", id,
+					transfer.Status)
+			}
+			return transfer, nil
+		}
+		if time.Now().After(deadline) {
+			return transfer, rerrors.TimeoutErrorf("Timed out waiting for transfer '%s', its status "+
+				"is '%s'", id, transfer.Status)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("Stopped waiting for transfer '%s': %w", id, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
+
+// UnmarshalTransfers parses the body of a response that contains a list of transfers.
+func UnmarshalTransfers(data []byte) ([]*Transfer, error) {
+	var list struct {
+		Items []*Transfer `json:"items"`
+	}
+	err := json.Unmarshal(data, &list)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse cluster transfers: %v", err)
+	}
+	if list.Items == nil {
+		return []*Transfer{}, nil
+	}
+	return list.Items, nil
+}
+
+func unmarshalTransfer(data []byte) (*Transfer, error) {
+	transfer := &Transfer{}
+	err := json.Unmarshal(data, transfer)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse cluster transfer: %v", err)
+	}
+	return transfer, nil
+}
+
+// checkResponse converts the error returned by the OCM API, if any, so that it can be reported with
+// the right exit code.
+func checkResponse(response *sdk.Response) error {
+	if response.Status() < http.StatusBadRequest {
+		return nil
+	}
+	res, err := ocmerrors.UnmarshalError(response.Bytes())
+	if err != nil {
+		return fmt.Errorf("Unexpected status code %d", response.Status())
+	}
+	return rerrors.FromOCM(res, res)
+}
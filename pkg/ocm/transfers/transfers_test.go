@@ -0,0 +1,59 @@
+package transfers_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	. "github.com/openshift/moactl/pkg/ocm/transfers"
+)
+
+var _ = Describe("Transfer", func() {
+	DescribeTable("Status",
+		func(status string, active bool, done bool) {
+			transfer := &Transfer{Status: status}
+			Expect(transfer.Active()).To(Equal(active))
+			Expect(transfer.Done()).To(Equal(done))
+		},
+		Entry("Pending", StatusPending, true, false),
+		Entry("Accepted", StatusAccepted, true, false),
+		Entry("Completed", StatusCompleted, false, true),
+		Entry("Declined", StatusDeclined, false, true),
+		Entry("Rescinded", StatusRescinded, false, true),
+	)
+
+	It("Parses a list of transfers", func() {
+		transfers, err := UnmarshalTransfers([]byte(`{
+			"kind": "ClusterTransferList",
+			"items": [{
+				"id": "123",
+				"cluster_uuid": "abc",
+				"owner": "alice",
+				"recipient": "bob",
+				"status": "Pending",
+				"expiration_date": "2020-10-01T00:00:00Z"
+			}]
+		}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(transfers).To(HaveLen(1))
+		Expect(transfers[0].ID).To(Equal("123"))
+		Expect(transfers[0].Recipient).To(Equal("bob"))
+		Expect(transfers[0].Active()).To(BeTrue())
+		Expect(transfers[0].ExpirationDate.Year()).To(Equal(2020))
+	})
+
+	It("Parses an empty list of transfers", func() {
+		transfers, err := UnmarshalTransfers([]byte(`{"kind": "ClusterTransferList"}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(transfers).To(BeEmpty())
+	})
+
+	It("Rejects usernames that aren't safe to search", func() {
+		Expect(ValidateUsername("bob")).To(Succeed())
+		err := ValidateUsername("bob' or '1'='1")
+		Expect(err).To(HaveOccurred())
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+		Expect(ValidateUsername("")).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,213 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to check and acknowledge the version gates that OCM
+// requires before upgrading a cluster to a new minor version.
+
+package upgrades
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
+
+	"github.com/openshift/moactl/pkg/confirm"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+)
+
+// VersionGate is a condition that the user needs to acknowledge before upgrading a cluster to a
+// version that matches the version prefix of the gate, for example API removals.
+type VersionGate struct {
+	ID                 string `json:"id"`
+	VersionRawIDPrefix string `json:"version_raw_id_prefix"`
+	Label              string `json:"label"`
+	Value              string `json:"value"`
+	WarningMessage     string `json:"warning_message"`
+	Description        string `json:"description"`
+	DocumentationURL   string `json:"documentation_url"`
+}
+
+type versionGateList struct {
+	Items []*VersionGate `json:"items"`
+}
+
+type versionGateAgreement struct {
+	VersionGate *VersionGate `json:"version_gate"`
+}
+
+type versionGateAgreementList struct {
+	Items []*versionGateAgreement `json:"items"`
+}
+
+const versionGatesPath = "/api/clusters_mgmt/v1/version_gates"
+
+// GetMissingGateAgreements returns the version gates that apply when upgrading the given cluster to
+// the given version and that haven't yet been acknowledged for the cluster.
+func GetMissingGateAgreements(connection *sdk.Connection, cluster *cmv1.Cluster,
+	version string) ([]*VersionGate, error) {
+	gates := &versionGateList{}
+	err := getJSON(connection, versionGatesPath, gates)
+	if err != nil {
+		return nil, err
+	}
+
+	agreements := &versionGateAgreementList{}
+	err = getJSON(connection, gateAgreementsPath(cluster.ID()), agreements)
+	if err != nil {
+		return nil, err
+	}
+	agreed := make(map[string]bool, len(agreements.Items))
+	for _, agreement := range agreements.Items {
+		if agreement.VersionGate != nil {
+			agreed[agreement.VersionGate.ID] = true
+		}
+	}
+
+	currentVersion := cluster.OpenshiftVersion()
+	if currentVersion == "" {
+		currentVersion = cluster.Version().RawID()
+	}
+
+	missing := []*VersionGate{}
+	for _, gate := range gates.Items {
+		if agreed[gate.ID] || !gate.appliesTo(currentVersion, version) {
+			continue
+		}
+		missing = append(missing, gate)
+	}
+	return missing, nil
+}
+
+// AckVersionGate records the agreement of the user with the given version gate for the cluster.
+func AckVersionGate(connection *sdk.Connection, clusterID string, gateID string) error {
+	body, err := json.Marshal(&versionGateAgreement{
+		VersionGate: &VersionGate{
+			ID: gateID,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	response, err := connection.Post().
+		Path(gateAgreementsPath(clusterID)).
+		Bytes(body).
+		Send()
+	if err != nil {
+		return err
+	}
+	return responseErr(response)
+}
+
+// AckVersionGates records the agreement of the user with all the given version gates for the
+// cluster.
+func AckVersionGates(connection *sdk.Connection, clusterID string, gates []*VersionGate) error {
+	for _, gate := range gates {
+		err := AckVersionGate(connection, clusterID, gate.ID)
+		if err != nil {
+			return fmt.Errorf("Failed to acknowledge version gate '%s': %v", gate.Label, err)
+		}
+	}
+	return nil
+}
+
+// CheckVersionGates finds the version gates that apply to the upgrade of the cluster to the given
+// version and aren't acknowledged yet, and asks the user to agree with them unless the acknowledge
+// flag is set. Nothing is sent to OCM: the returned gates need to be acknowledged with
+// AckVersionGates right before the upgrade is scheduled, so that they stay unacknowledged if the
+// user doesn't confirm the upgrade or if it can't be scheduled.
+func CheckVersionGates(connection *sdk.Connection, cluster *cmv1.Cluster, version string,
+	acknowledge bool) ([]*VersionGate, error) {
+	reporter, err := rprtr.New().Build()
+	if err != nil {
+		return nil, fmt.Errorf("Unable to create reporter: %v", err)
+	}
+
+	missingGates, err := GetMissingGateAgreements(connection, cluster, version)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to check version gates for version %s: %v", version, err)
+	}
+
+	for _, gate := range missingGates {
+		reporter.Warnf("Version gate '%s': %s", gate.Label, gate.WarningMessage)
+		if gate.Description != "" {
+			reporter.Infof("%s", gate.Description)
+		}
+		if gate.DocumentationURL != "" {
+			reporter.Infof("For more information see %s", gate.DocumentationURL)
+		}
+		if !acknowledge {
+			acknowledged, err := confirm.Confirm("acknowledge version gate '%s'", gate.Label)
+			if err != nil || !acknowledged {
+				return nil, fmt.Errorf(
+					"Upgrading to version %s requires acknowledging the following version gates: %s. "+
+						"Use the '--allow-version-gate-acknowledgement' flag to acknowledge them",
+					version, gateLabels(missingGates),
+				)
+			}
+		}
+	}
+	return missingGates, nil
+}
+
+func gateLabels(gates []*VersionGate) string {
+	labels := make([]string, len(gates))
+	for i, gate := range gates {
+		labels[i] = gate.Label
+	}
+	return strings.Join(labels, ", ")
+}
+
+// appliesTo checks if the gate needs to be acknowledged when upgrading from the current version to
+// the target version. Gates only apply when crossing into the version prefix of the gate.
+func (g *VersionGate) appliesTo(currentVersion string, targetVersion string) bool {
+	prefix := g.VersionRawIDPrefix + "."
+	return strings.HasPrefix(targetVersion, prefix) && !strings.HasPrefix(currentVersion, prefix)
+}
+
+func gateAgreementsPath(clusterID string) string {
+	return fmt.Sprintf("/api/clusters_mgmt/v1/clusters/%s/gate_agreements", clusterID)
+}
+
+func getJSON(connection *sdk.Connection, path string, value interface{}) error {
+	response, err := connection.Get().
+		Path(path).
+		Parameter("size", -1).
+		Send()
+	if err != nil {
+		return err
+	}
+	err = responseErr(response)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(response.Bytes(), value)
+}
+
+func responseErr(response *sdk.Response) error {
+	if response.Status() < http.StatusBadRequest {
+		return nil
+	}
+	res, err := ocmerrors.UnmarshalError(response.Bytes())
+	if err != nil {
+		return fmt.Errorf("Unexpected status code %d", response.Status())
+	}
+	return handleErr(res, res)
+}
@@ -0,0 +1,112 @@
+package upgrades_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/openshift/moactl/pkg/ocm/upgrades"
+)
+
+var _ = Describe("Version gates", func() {
+	var (
+		server     *httptest.Server
+		connection *sdk.Connection
+		cluster    *cmv1.Cluster
+		agreements string
+		posts      int
+	)
+
+	BeforeEach(func() {
+		agreements = `{"items": []}`
+		posts = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch {
+			case r.Method == http.MethodPost:
+				posts++
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{}`))
+			case r.URL.Path == "/api/clusters_mgmt/v1/version_gates":
+				_, _ = w.Write([]byte(`{"items": [
+					{"id": "g47", "version_raw_id_prefix": "4.7", "label": "api-removals-4.7"},
+					{"id": "g48", "version_raw_id_prefix": "4.8", "label": "api-removals-4.8"},
+					{"id": "g49", "version_raw_id_prefix": "4.9", "label": "api-removals-4.9"}
+				]}`))
+			case r.URL.Path == "/api/clusters_mgmt/v1/clusters/123/gate_agreements":
+				_, _ = w.Write([]byte(agreements))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"typ": "Bearer",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}).SignedString([]byte("secret"))
+		Expect(err).NotTo(HaveOccurred())
+		connection, err = sdk.NewConnectionBuilder().
+			URL(server.URL).
+			Tokens(token).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+		cluster, err = cmv1.NewCluster().
+			ID("123").
+			OpenshiftVersion("4.7.10").
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(connection.Close()).To(Succeed())
+		server.Close()
+	})
+
+	gateIDs := func(gates []*VersionGate) []string {
+		ids := []string{}
+		for _, gate := range gates {
+			ids = append(ids, gate.ID)
+		}
+		return ids
+	}
+
+	It("Applies the gates of the target minor version", func() {
+		gates, err := GetMissingGateAgreements(connection, cluster, "4.8.2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gateIDs(gates)).To(Equal([]string{"g48"}))
+	})
+
+	It("Doesn't apply the gates of the current minor version", func() {
+		gates, err := GetMissingGateAgreements(connection, cluster, "4.7.12")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gates).To(BeEmpty())
+	})
+
+	It("Doesn't match versions that only share a prefix of the minor version", func() {
+		gates, err := GetMissingGateAgreements(connection, cluster, "4.70.1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gates).To(BeEmpty())
+	})
+
+	It("Skips the gates that are already acknowledged", func() {
+		agreements = `{"items": [{"version_gate": {"id": "g48"}}]}`
+		gates, err := GetMissingGateAgreements(connection, cluster, "4.8.2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gates).To(BeEmpty())
+	})
+
+	It("Doesn't acknowledge the gates when checking them", func() {
+		gates, err := CheckVersionGates(connection, cluster, "4.8.2", true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gateIDs(gates)).To(Equal([]string{"g48"}))
+		Expect(posts).To(BeZero())
+
+		Expect(AckVersionGates(connection, cluster.ID(), gates)).To(Succeed())
+		Expect(posts).To(Equal(1))
+	})
+})
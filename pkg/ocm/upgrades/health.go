@@ -0,0 +1,188 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to check the health of a cluster after the window of an
+// upgrade, so that pipelines can detect upgrades that are stuck or have failed.
+
+package upgrades
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// States of upgrade policies:
+const (
+	StateCompleted = "completed"
+	StateFailed    = "failed"
+)
+
+// Health contains the information loaded from OCM that is used to check the outcome of an upgrade
+// of a cluster.
+type Health struct {
+	ClusterState   cmv1.ClusterState
+	CurrentVersion string
+
+	// Version that the cluster is expected to run, empty if it isn't known:
+	TargetVersion string
+
+	// Manual upgrade policy of the cluster and its state, if there is one:
+	ScheduledUpgrade *cmv1.UpgradePolicy
+	UpgradeState     string
+	UpgradeWindow    time.Duration
+
+	// Cluster operators reported by the cluster, or the error loading them:
+	Operators    []*cmv1.ClusterOperatorInfo
+	OperatorsErr error
+}
+
+// Check is the result of one of the checks of the health of a cluster after an upgrade.
+type Check struct {
+	Name    string
+	Passed  bool
+	Details string
+}
+
+// GetClusterOperators returns the state of the cluster operators reported by the cluster.
+func GetClusterOperators(client *cmv1.Client, clusterID string) ([]*cmv1.ClusterOperatorInfo, error) {
+	response, err := client.Clusters().
+		Cluster(clusterID).
+		MetricQueries().
+		ClusterOperators().
+		Get().
+		Send()
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Body().Operators(), nil
+}
+
+// CheckHealth checks that the cluster is ready, that there is no upgrade failed or still pending at
+// the given time, that the cluster runs the target version and that all the cluster operators are
+// available.
+func CheckHealth(health Health, now time.Time) []Check {
+	return []Check{
+		checkClusterState(health),
+		checkUpgradePolicy(health, now),
+		checkVersion(health),
+		checkOperators(health),
+	}
+}
+
+// FailedChecks returns the checks that didn't pass.
+func FailedChecks(checks []Check) []Check {
+	failed := []Check{}
+	for _, check := range checks {
+		if !check.Passed {
+			failed = append(failed, check)
+		}
+	}
+	return failed
+}
+
+func checkClusterState(health Health) Check {
+	return Check{
+		Name:    "Cluster state",
+		Passed:  health.ClusterState == cmv1.ClusterStateReady,
+		Details: fmt.Sprintf("Cluster is %s", health.ClusterState),
+	}
+}
+
+func checkUpgradePolicy(health Health, now time.Time) Check {
+	check := Check{
+		Name: "Scheduled upgrade",
+	}
+	policy := health.ScheduledUpgrade
+	if policy == nil {
+		check.Passed = true
+		check.Details = "No upgrade pending"
+		return check
+	}
+	nextRun := policy.NextRun()
+	switch {
+	case health.UpgradeState == StateCompleted:
+		check.Passed = true
+		check.Details = fmt.Sprintf("Upgrade to %s completed", policy.Version())
+	case health.UpgradeState == StateFailed:
+		check.Details = fmt.Sprintf("Upgrade to %s failed", policy.Version())
+	case nextRun.After(now):
+		check.Details = fmt.Sprintf("Upgrade to %s is scheduled on %s and hasn't started yet",
+			policy.Version(), nextRun.UTC().Format(scheduleFormat))
+	case nextRun.Add(health.UpgradeWindow).Before(now):
+		check.Details = fmt.Sprintf("Upgrade to %s started on %s is still %s after the expected "+
+			"window of %s, it may be stuck", policy.Version(), nextRun.UTC().Format(scheduleFormat),
+			health.UpgradeState, health.UpgradeWindow)
+	default:
+		check.Details = fmt.Sprintf("Upgrade to %s started on %s is still %s",
+			policy.Version(), nextRun.UTC().Format(scheduleFormat), health.UpgradeState)
+	}
+	return check
+}
+
+func checkVersion(health Health) Check {
+	check := Check{
+		Name: "Version",
+	}
+	if health.TargetVersion == "" {
+		check.Passed = true
+		check.Details = fmt.Sprintf("Cluster runs %s, no target version", health.CurrentVersion)
+		return check
+	}
+	check.Passed = health.CurrentVersion == health.TargetVersion
+	check.Details = fmt.Sprintf("Cluster runs %s, expected %s", health.CurrentVersion,
+		health.TargetVersion)
+	return check
+}
+
+func checkOperators(health Health) Check {
+	check := Check{
+		Name: "Cluster operators",
+	}
+	if health.OperatorsErr != nil {
+		check.Details = fmt.Sprintf("Failed to get cluster operators: %v", health.OperatorsErr)
+		return check
+	}
+	if len(health.Operators) == 0 {
+		check.Details = "Cluster doesn't report its cluster operators"
+		return check
+	}
+	problems := []string{}
+	for _, operator := range health.Operators {
+		switch {
+		case operator.Condition() != cmv1.ClusterOperatorStateAvailable:
+			problem := fmt.Sprintf("%s is %s", operator.Name(), operator.Condition())
+			if operator.Reason() != "" {
+				problem = fmt.Sprintf("%s (%s)", problem, operator.Reason())
+			}
+			problems = append(problems, problem)
+		case health.TargetVersion != "" && operator.Version() != "" &&
+			operator.Version() != health.TargetVersion:
+			problems = append(problems, fmt.Sprintf("%s runs %s", operator.Name(), operator.Version()))
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		check.Details = strings.Join(problems, ", ")
+		return check
+	}
+	check.Passed = true
+	check.Details = fmt.Sprintf("All %d cluster operators are available", len(health.Operators))
+	return check
+}
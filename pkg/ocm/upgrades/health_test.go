@@ -0,0 +1,126 @@
+package upgrades_test
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/openshift/moactl/pkg/ocm/upgrades"
+)
+
+var _ = Describe("Health", func() {
+	now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	policy := func(nextRun time.Time) *cmv1.UpgradePolicy {
+		result, err := cmv1.NewUpgradePolicy().
+			ID("policy").
+			ScheduleType("manual").
+			UpgradeType("OSD").
+			Version("4.6.9").
+			NextRun(nextRun).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+		return result
+	}
+
+	operator := func(name string, condition cmv1.ClusterOperatorState, version string) *cmv1.ClusterOperatorInfo {
+		result, err := cmv1.NewClusterOperatorInfo().
+			Name(name).
+			Condition(condition).
+			Version(version).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+		return result
+	}
+
+	healthy := func() Health {
+		return Health{
+			ClusterState:   cmv1.ClusterStateReady,
+			CurrentVersion: "4.6.9",
+			TargetVersion:  "4.6.9",
+			UpgradeWindow:  2 * time.Hour,
+			Operators: []*cmv1.ClusterOperatorInfo{
+				operator("authentication", cmv1.ClusterOperatorStateAvailable, "4.6.9"),
+				operator("dns", cmv1.ClusterOperatorStateAvailable, "4.6.9"),
+			},
+		}
+	}
+
+	failed := func(health Health) []string {
+		names := []string{}
+		for _, check := range FailedChecks(CheckHealth(health, now)) {
+			names = append(names, check.Name)
+		}
+		return names
+	}
+
+	It("passes when the cluster runs the target version", func() {
+		Expect(failed(healthy())).To(BeEmpty())
+	})
+
+	It("fails when the cluster isn't ready", func() {
+		health := healthy()
+		health.ClusterState = cmv1.ClusterStateError
+		Expect(failed(health)).To(Equal([]string{"Cluster state"}))
+	})
+
+	It("fails when the cluster runs another version", func() {
+		health := healthy()
+		health.CurrentVersion = "4.6.8"
+		Expect(failed(health)).To(Equal([]string{"Version"}))
+	})
+
+	It("fails when the upgrade failed", func() {
+		health := healthy()
+		health.ScheduledUpgrade = policy(now.Add(-time.Hour))
+		health.UpgradeState = StateFailed
+		checks := FailedChecks(CheckHealth(health, now))
+		Expect(checks).To(HaveLen(1))
+		Expect(checks[0].Details).To(Equal("Upgrade to 4.6.9 failed"))
+	})
+
+	It("passes when the upgrade completed", func() {
+		health := healthy()
+		health.ScheduledUpgrade = policy(now.Add(-time.Hour))
+		health.UpgradeState = StateCompleted
+		Expect(failed(health)).To(BeEmpty())
+	})
+
+	It("reports upgrades that haven't started", func() {
+		health := healthy()
+		health.ScheduledUpgrade = policy(now.Add(time.Hour))
+		health.UpgradeState = "scheduled"
+		checks := FailedChecks(CheckHealth(health, now))
+		Expect(checks).To(HaveLen(1))
+		Expect(checks[0].Details).To(ContainSubstring("hasn't started yet"))
+	})
+
+	It("reports upgrades that are stuck after the window", func() {
+		health := healthy()
+		health.ScheduledUpgrade = policy(now.Add(-3 * time.Hour))
+		health.UpgradeState = "started"
+		checks := FailedChecks(CheckHealth(health, now))
+		Expect(checks).To(HaveLen(1))
+		Expect(checks[0].Details).To(ContainSubstring("it may be stuck"))
+	})
+
+	It("reports operators that aren't available or run another version", func() {
+		health := healthy()
+		health.Operators = append(health.Operators,
+			operator("ingress", cmv1.ClusterOperatorStateDegraded, "4.6.9"),
+			operator("network", cmv1.ClusterOperatorStateAvailable, "4.6.8"))
+		checks := FailedChecks(CheckHealth(health, now))
+		Expect(checks).To(HaveLen(1))
+		Expect(checks[0].Details).To(Equal("ingress is degraded, network runs 4.6.8"))
+	})
+
+	It("fails when the cluster operators can't be loaded", func() {
+		health := healthy()
+		health.Operators = nil
+		health.OperatorsErr = errors.New("not found")
+		Expect(failed(health)).To(Equal([]string{"Cluster operators"}))
+	})
+})
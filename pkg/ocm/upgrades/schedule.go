@@ -0,0 +1,271 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to ask and parse the version, schedule and node drain grace
+// period of upgrades, shared by the commands that create and edit upgrade policies.
+
+package upgrades
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/helper"
+	"github.com/openshift/moactl/pkg/interactive"
+)
+
+// Layouts of the date and time of the schedule of upgrades:
+const (
+	scheduleDateLayout = "2006-01-02"
+	scheduleTimeLayout = "15:04"
+)
+
+// NodeDrainGracePeriod is the node drain grace period option, which the API accepts up to one
+// week.
+var NodeDrainGracePeriod = helper.DurationOption{
+	Name: "node drain grace period",
+	Min:  0,
+	Max:  7 * 24 * 60,
+}
+
+// NodeDrainOptions are the node drain grace periods offered in interactive mode.
+var NodeDrainOptions = []string{
+	"15 minutes",
+	"30 minutes",
+	"45 minutes",
+	"1 hour",
+	"2 hours",
+	"4 hours",
+	"8 hours",
+}
+
+// PromptVersion asks the user to select the version to upgrade to among the available upgrades,
+// using the given version as the default, or the first available upgrade if it is empty.
+func PromptVersion(version string, availableUpgrades []string, help string) (string, error) {
+	if version == "" {
+		version = availableUpgrades[0]
+	}
+	version, err := interactive.GetOption(interactive.Input{
+		Question: "Version",
+		Help:     help,
+		Options:  availableUpgrades,
+		Default:  version,
+		Required: true,
+		Flag:     "version",
+	})
+	if err != nil {
+		return "", fmt.Errorf("Expected a valid version to upgrade to: %s", err)
+	}
+	return version, nil
+}
+
+// ValidateVersion checks that the version is one of the available upgrades.
+func ValidateVersion(version string, availableUpgrades []string) error {
+	for _, v := range availableUpgrades {
+		if v == version {
+			return nil
+		}
+	}
+	return fmt.Errorf("Expected a valid version to upgrade to")
+}
+
+// TimezoneOptions are the time zones offered in interactive mode, in addition to the one given in
+// the command line. Any IANA time zone name can be given with the '--schedule-timezone' flag.
+var TimezoneOptions = []string{
+	"UTC",
+	"America/New_York",
+	"America/Chicago",
+	"America/Denver",
+	"America/Los_Angeles",
+	"America/Sao_Paulo",
+	"Europe/London",
+	"Europe/Paris",
+	"Europe/Berlin",
+	"Asia/Kolkata",
+	"Asia/Singapore",
+	"Asia/Shanghai",
+	"Asia/Tokyo",
+	"Australia/Sydney",
+}
+
+// LoadTimezone returns the location with the given IANA time zone name, like 'Europe/Paris'. An
+// empty name means UTC.
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, rerrors.ValidationErrorf("Expected a valid IANA time zone name like "+
+			"'Europe/Paris', got '%s'", name)
+	}
+	return location, nil
+}
+
+// PromptTimezone asks the user to select the time zone of the date and time of the upgrade, using
+// the given time zone as the default.
+func PromptTimezone(timezone string, help string) (string, error) {
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	options := TimezoneOptions
+	if !contains(options, timezone) {
+		options = append([]string{timezone}, options...)
+	}
+	timezone, err := interactive.GetOption(interactive.Input{
+		Question: "Time zone of the schedule",
+		Help:     help,
+		Options:  options,
+		Default:  timezone,
+		Required: true,
+		Flag:     "schedule-timezone",
+	})
+	if err != nil {
+		return "", fmt.Errorf("Expected a valid time zone: %s", err)
+	}
+	return timezone, nil
+}
+
+// PromptSchedule asks the user the date and time of the upgrade in the given location, using the
+// given values as the defaults. Answers that don't have the right format are rejected and asked
+// again.
+func PromptSchedule(scheduleDate string, scheduleTime string, location *time.Location) (string,
+	string, error) {
+	dflt, _ := time.ParseInLocation(scheduleDateLayout+" "+scheduleTimeLayout,
+		fmt.Sprintf("%s %s", scheduleDate, scheduleTime), location)
+	dateValue, err := interactive.GetDate(interactive.Input{
+		Question: "Please input desired date in format yyyy-mm-dd",
+		Default:  dflt,
+		Required: true,
+	}, scheduleDateLayout)
+	if err != nil {
+		return "", "", fmt.Errorf("Expected a valid date: %s", err)
+	}
+
+	timeValue, err := interactive.GetDate(interactive.Input{
+		Question: fmt.Sprintf("Please input desired %s time in format HH:mm", location),
+		Default:  dflt,
+		Required: true,
+	}, scheduleTimeLayout)
+	if err != nil {
+		return "", "", fmt.Errorf("Expected a valid time: %s", err)
+	}
+	return dateValue.Format(scheduleDateLayout), timeValue.Format(scheduleTimeLayout), nil
+}
+
+// ParseSchedule returns the time of the upgrade with the given UTC date and time.
+func ParseSchedule(scheduleDate string, scheduleTime string) (time.Time, error) {
+	return ParseScheduleInLocation(scheduleDate, scheduleTime, time.UTC)
+}
+
+// ParseScheduleInLocation returns the time of the upgrade with the given date and time in the
+// given location, converted to UTC as that is what the API expects.
+func ParseScheduleInLocation(scheduleDate string, scheduleTime string,
+	location *time.Location) (time.Time, error) {
+	nextRun, err := time.ParseInLocation(scheduleDateLayout+" "+scheduleTimeLayout,
+		fmt.Sprintf("%s %s", scheduleDate, scheduleTime), location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Time format invalid: %s", err)
+	}
+	return nextRun.UTC(), nil
+}
+
+// FormatSchedule returns the time of the upgrade in UTC and, when the location isn't UTC, also in
+// the given location, for example '2020-12-01 01:00 UTC (2020-12-01 02:00 CET, Europe/Paris)'.
+func FormatSchedule(nextRun time.Time, location *time.Location) string {
+	layout := scheduleDateLayout + " " + scheduleTimeLayout + " MST"
+	result := nextRun.UTC().Format(layout)
+	if location == nil || location == time.UTC || location.String() == "UTC" {
+		return result
+	}
+	return fmt.Sprintf("%s (%s, %s)", result, nextRun.In(location).Format(layout), location)
+}
+
+// ParseScheduleIn returns the time of an upgrade that runs after the given duration, like '2h' or
+// '90m', from the given current time. The result is rounded up to the next minute, as that is the
+// precision of the date and time of the schedule.
+func ParseScheduleIn(value string, now time.Time) (time.Time, error) {
+	delay, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Expected a valid duration like '2h' or '90m': %s", err)
+	}
+	nextRun := now.UTC().Add(delay)
+	if truncated := nextRun.Truncate(time.Minute); !truncated.Equal(nextRun) {
+		nextRun = truncated.Add(time.Minute)
+	}
+	return nextRun, nil
+}
+
+// PromptNodeDrainGracePeriod asks the user to select the node drain grace period, using the given
+// value as the default. The value is offered as an additional option when it isn't one of the
+// usual ones.
+func PromptNodeDrainGracePeriod(value string, help string) (string, error) {
+	options := NodeDrainOptions
+	if value != "" && !contains(options, value) {
+		options = append([]string{value}, options...)
+	}
+	value, err := interactive.GetOption(interactive.Input{
+		Question: "Node draining",
+		Help:     help,
+		Options:  options,
+		Default:  value,
+		Required: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("Expected a valid node drain grace period: %s", err)
+	}
+	return value, nil
+}
+
+// FormatNodeDrainGracePeriod converts the node drain grace period of a cluster to the format used
+// by the command line, for example '2 hours'. It returns an empty string if the cluster doesn't
+// have a grace period.
+func FormatNodeDrainGracePeriod(nodeDrain *cmv1.Value) string {
+	minutes, ok := nodeDrain.GetValue()
+	if !ok {
+		return ""
+	}
+	return helper.FormatMinutes(minutes)
+}
+
+// ParseNodeDrainGracePeriod parses a node drain grace period, returning the number of minutes. The
+// value can be given as a number of minutes or hours, like '30 minutes' or '2 hours', as a Go
+// duration, like '90m' or '2h', or as a plain number of minutes, like '90'.
+func ParseNodeDrainGracePeriod(value string) (float64, error) {
+	return NodeDrainGracePeriod.Parse(value)
+}
+
+// NodeDrainGracePeriodSpec returns the cluster spec used to update the node drain grace period.
+func NodeDrainGracePeriodSpec(minutes float64) (*cmv1.Cluster, error) {
+	return cmv1.NewCluster().
+		NodeDrainGracePeriod(cmv1.NewValue().
+			Value(minutes).
+			Unit("minutes")).
+		Build()
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
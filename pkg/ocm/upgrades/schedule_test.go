@@ -0,0 +1,108 @@
+package upgrades_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/ocm/upgrades"
+)
+
+var _ = Describe("Node drain grace period", func() {
+	DescribeTable("Parses valid values",
+		func(value string, expected float64) {
+			minutes, err := ParseNodeDrainGracePeriod(value)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(minutes).To(Equal(expected))
+		},
+		Entry("Minutes", "30 minutes", 30.0),
+		Entry("One hour", "1 hour", 60.0),
+		Entry("Hours", "2 hours", 120.0),
+		Entry("Go duration in minutes", "90m", 90.0),
+		Entry("Go duration in hours", "2h", 120.0),
+		Entry("Go duration with hours and minutes", "1h30m", 90.0),
+		Entry("Plain number of minutes", "30", 30.0),
+		Entry("One week", "168 hours", 10080.0),
+	)
+
+	DescribeTable("Rejects invalid values",
+		func(value string) {
+			_, err := ParseNodeDrainGracePeriod(value)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("Empty", ""),
+		Entry("More than one week", "169h"),
+		Entry("Unknown unit", "2 days"),
+		Entry("Negative", "-1h"),
+		Entry("Not a number", "many hours"),
+	)
+})
+
+var _ = Describe("Relative schedule", func() {
+	now := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	DescribeTable("Parses valid durations",
+		func(value string, expected time.Time) {
+			nextRun, err := ParseScheduleIn(value, now)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(nextRun).To(Equal(expected))
+		},
+		Entry("Hours", "2h", now.Add(2*time.Hour)),
+		Entry("Minutes", "90m", now.Add(90*time.Minute)),
+		Entry("Rounded up to the next minute", "10m30s", now.Add(11*time.Minute)),
+	)
+
+	It("Rejects invalid durations", func() {
+		_, err := ParseScheduleIn("tomorrow", now)
+		Expect(err).To(MatchError(ContainSubstring("Expected a valid duration")))
+	})
+
+	It("Converts the time to UTC", func() {
+		local := now.In(time.FixedZone("CET", 3600))
+		nextRun, err := ParseScheduleIn("1h", local)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(nextRun.Location()).To(Equal(time.UTC))
+		Expect(nextRun).To(Equal(now.Add(time.Hour)))
+	})
+})
+
+var _ = Describe("Schedule time zone", func() {
+	It("Uses UTC by default", func() {
+		location, err := LoadTimezone("")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(location).To(Equal(time.UTC))
+	})
+
+	It("Rejects unknown time zones", func() {
+		_, err := LoadTimezone("Mars/Olympus_Mons")
+		Expect(err).To(MatchError(ContainSubstring("IANA time zone")))
+	})
+
+	It("Converts the schedule to UTC", func() {
+		location, err := LoadTimezone("Europe/Paris")
+		Expect(err).ToNot(HaveOccurred())
+		nextRun, err := ParseScheduleInLocation("2020-12-01", "02:00", location)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(nextRun.Location()).To(Equal(time.UTC))
+		Expect(nextRun).To(Equal(time.Date(2020, 12, 1, 1, 0, 0, 0, time.UTC)))
+	})
+
+	It("Takes daylight saving time into account", func() {
+		location, err := LoadTimezone("Europe/Paris")
+		Expect(err).ToNot(HaveOccurred())
+		nextRun, err := ParseScheduleInLocation("2020-07-01", "02:00", location)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(nextRun).To(Equal(time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC)))
+	})
+
+	It("Formats the schedule in UTC and in the time zone", func() {
+		location, err := LoadTimezone("Europe/Paris")
+		Expect(err).ToNot(HaveOccurred())
+		nextRun := time.Date(2020, 12, 1, 1, 0, 0, 0, time.UTC)
+		Expect(FormatSchedule(nextRun, time.UTC)).To(Equal("2020-12-01 01:00 UTC"))
+		Expect(FormatSchedule(nextRun, location)).To(Equal(
+			"2020-12-01 01:00 UTC (2020-12-01 02:00 CET, Europe/Paris)"))
+	})
+})
@@ -0,0 +1,143 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to schedule the upgrades of clusters with hosted control
+// planes, where the control plane and each machine pool have their own upgrade policies and are
+// upgraded independently. The SDK doesn't support these policies yet.
+
+package upgrades
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/ocm/versions"
+)
+
+// Upgrade types of the policies of the control plane and the machine pools of hosted clusters:
+const (
+	UpgradeTypeControlPlane = "ControlPlane"
+	UpgradeTypeNodePool     = "NodePool"
+)
+
+// Target is the part of a cluster with a hosted control plane that an upgrade applies to: the
+// control plane, or one of the machine pools.
+type Target struct {
+	ClusterID string
+
+	// Name of the machine pool, empty for the control plane:
+	MachinePool string
+}
+
+// String returns the description of the target used in messages.
+func (t Target) String() string {
+	if t.MachinePool == "" {
+		return "control plane"
+	}
+	return fmt.Sprintf("machine pool '%s'", t.MachinePool)
+}
+
+// UpgradeType returns the type of the upgrade policies of the target.
+func (t Target) UpgradeType() string {
+	if t.MachinePool == "" {
+		return UpgradeTypeControlPlane
+	}
+	return UpgradeTypeNodePool
+}
+
+func (t Target) path() string {
+	if t.MachinePool == "" {
+		return fmt.Sprintf("/api/clusters_mgmt/v1/clusters/%s/control_plane", t.ClusterID)
+	}
+	return fmt.Sprintf("/api/clusters_mgmt/v1/clusters/%s/node_pools/%s", t.ClusterID,
+		t.MachinePool)
+}
+
+// GetTargetUpgradePolicies returns the upgrade policies of the target.
+func GetTargetUpgradePolicies(connection *sdk.Connection, target Target) ([]*cmv1.UpgradePolicy,
+	error) {
+	document := struct {
+		Items json.RawMessage `json:"items"`
+	}{}
+	err := getJSON(connection, target.path()+"/upgrade_policies", &document)
+	if err != nil {
+		return nil, err
+	}
+	if len(document.Items) == 0 {
+		return nil, nil
+	}
+	return cmv1.UnmarshalUpgradePolicyList([]byte(document.Items))
+}
+
+// GetMachinePoolVersion returns the version of OpenShift of the nodes of the machine pool of the
+// target.
+func GetMachinePoolVersion(connection *sdk.Connection, target Target) (string, error) {
+	document := struct {
+		Version struct {
+			RawID string `json:"raw_id"`
+		} `json:"version"`
+	}{}
+	err := getJSON(connection, target.path(), &document)
+	if err != nil {
+		return "", err
+	}
+	return document.Version.RawID, nil
+}
+
+// FilterMachinePoolUpgrades returns the available upgrades that a machine pool can use: the
+// nodes can't run a newer version than the control plane.
+func FilterMachinePoolUpgrades(availableUpgrades []string, controlPlaneVersion string) []string {
+	result := []string{}
+	for _, version := range availableUpgrades {
+		if versions.Compare(version, controlPlaneVersion) <= 0 {
+			result = append(result, version)
+		}
+	}
+	return result
+}
+
+// ScheduleTargetUpgrade adds a manual upgrade policy that upgrades the target to the given version
+// at the given time.
+func ScheduleTargetUpgrade(connection *sdk.Connection, target Target, version string,
+	nextRun time.Time) error {
+	upgradePolicy, err := cmv1.NewUpgradePolicy().
+		ScheduleType("manual").
+		UpgradeType(target.UpgradeType()).
+		Version(version).
+		NextRun(nextRun).
+		Build()
+	if err != nil {
+		return err
+	}
+	var buffer bytes.Buffer
+	err = cmv1.MarshalUpgradePolicy(upgradePolicy, &buffer)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal upgrade policy: %v", err)
+	}
+	response, err := connection.Post().
+		Path(target.path() + "/upgrade_policies").
+		Bytes(buffer.Bytes()).
+		Send()
+	if err != nil {
+		return err
+	}
+	return responseErr(response)
+}
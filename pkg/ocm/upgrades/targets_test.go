@@ -0,0 +1,48 @@
+package upgrades_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/openshift/moactl/pkg/ocm/upgrades"
+)
+
+var _ = Describe("Targets", func() {
+	It("describes the control plane", func() {
+		target := Target{ClusterID: "123"}
+		Expect(target.String()).To(Equal("control plane"))
+		Expect(target.UpgradeType()).To(Equal(UpgradeTypeControlPlane))
+	})
+
+	It("describes machine pools", func() {
+		target := Target{ClusterID: "123", MachinePool: "mp-1"}
+		Expect(target.String()).To(Equal("machine pool 'mp-1'"))
+		Expect(target.UpgradeType()).To(Equal(UpgradeTypeNodePool))
+	})
+
+	It("limits machine pool upgrades to the version of the control plane", func() {
+		Expect(FilterMachinePoolUpgrades([]string{"4.12.7", "4.12.5", "4.12.3"}, "4.12.5")).To(
+			Equal([]string{"4.12.5", "4.12.3"}))
+		Expect(FilterMachinePoolUpgrades([]string{"4.13.0"}, "4.12.5")).To(BeEmpty())
+	})
+
+	It("finds the scheduled upgrade of the given type", func() {
+		policy := func(id string, upgradeType string) *cmv1.UpgradePolicy {
+			result, err := cmv1.NewUpgradePolicy().
+				ID(id).
+				ScheduleType("manual").
+				UpgradeType(upgradeType).
+				Build()
+			Expect(err).NotTo(HaveOccurred())
+			return result
+		}
+		policies := []*cmv1.UpgradePolicy{
+			policy("osd", "OSD"),
+			policy("pool", UpgradeTypeNodePool),
+		}
+		Expect(FindScheduledUpgradeOfType(policies, UpgradeTypeNodePool).ID()).To(Equal("pool"))
+		Expect(FindScheduledUpgradeOfType(policies, UpgradeTypeControlPlane)).To(BeNil())
+		Expect(FindScheduledUpgrade(policies).ID()).To(Equal("osd"))
+	})
+})
@@ -17,10 +17,12 @@ limitations under the License.
 package upgrades
 
 import (
-	"errors"
+	"time"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
 )
 
 func GetUpgradePolicies(client *cmv1.Client, clusterID string) (upgradePolicies []*cmv1.UpgradePolicy, err error) {
@@ -49,14 +51,64 @@ func GetScheduledUpgrade(client *cmv1.Client, clusterID string) (*cmv1.UpgradePo
 	if err != nil {
 		return nil, err
 	}
+	return FindScheduledUpgrade(upgradePolicies), nil
+}
 
-	for _, upgradePolicy := range upgradePolicies {
-		if upgradePolicy.ScheduleType() == "manual" && upgradePolicy.UpgradeType() == "OSD" {
-			return upgradePolicy, nil
-		}
+// GetUpgradePolicyState returns the state of the given upgrade policy, which tells if the upgrade
+// is pending, in progress, completed or failed.
+func GetUpgradePolicyState(client *cmv1.Client, clusterID string,
+	upgradePolicyID string) (*cmv1.UpgradePolicyState, error) {
+	response, err := client.Clusters().
+		Cluster(clusterID).
+		UpgradePolicies().
+		UpgradePolicy(upgradePolicyID).
+		State().
+		Get().
+		Send()
+	if err != nil {
+		return nil, handleErr(response.Error(), err)
+	}
+	return response.Body(), nil
+}
+
+// ScheduleUpgrade adds a manual upgrade policy that upgrades the cluster to the given version at the
+// given time.
+func ScheduleUpgrade(client *cmv1.Client, clusterID string, version string, nextRun time.Time) error {
+	upgradePolicy, err := cmv1.NewUpgradePolicy().
+		ScheduleType("manual").
+		Version(version).
+		NextRun(nextRun).
+		Build()
+	if err != nil {
+		return err
+	}
+	response, err := client.Clusters().
+		Cluster(clusterID).
+		UpgradePolicies().
+		Add().
+		Body(upgradePolicy).
+		Send()
+	if err != nil {
+		return handleErr(response.Error(), err)
 	}
+	return nil
+}
 
-	return nil, nil
+// UpdateUpgradePolicy updates the given upgrade policy of the cluster with the attributes of the
+// given policy.
+func UpdateUpgradePolicy(client *cmv1.Client, clusterID string, upgradePolicyID string,
+	upgradePolicy *cmv1.UpgradePolicy) error {
+	response, err := client.Clusters().
+		Cluster(clusterID).
+		UpgradePolicies().
+		UpgradePolicy(upgradePolicyID).
+		Update().
+		Body(upgradePolicy).
+		Send()
+	if err != nil {
+		return handleErr(response.Error(), err)
+	}
+	return nil
 }
 
 func CancelUpgrade(client *cmv1.Client, clusterID string) (bool, error) {
@@ -79,9 +131,5 @@ func CancelUpgrade(client *cmv1.Client, clusterID string) (bool, error) {
 }
 
 func handleErr(res *ocmerrors.Error, err error) error {
-	msg := res.Reason()
-	if msg == "" {
-		msg = err.Error()
-	}
-	return errors.New(msg)
+	return rerrors.FromOCM(res, err)
 }
@@ -0,0 +1,13 @@
+package upgrades_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestUpgrades(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Upgrades Suite")
+}
@@ -0,0 +1,141 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to check that the schedule of an upgrade doesn't conflict
+// with the maintenance windows of the automatic upgrade policies of the cluster.
+
+package upgrades
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// EstimatedUpgradeDuration is the time that an upgrade is expected to take in addition to the node
+// drain grace period. It is used to find upgrades whose maintenance windows overlap.
+const EstimatedUpgradeDuration = time.Hour
+
+// MinScheduleDelay is the minimum time between now and the schedule of an upgrade, so that the
+// upgrade policy is created before it needs to run.
+const MinScheduleDelay = 5 * time.Minute
+
+// scheduleFormat is the format used to show the time of upgrades in messages.
+const scheduleFormat = "2006-01-02 15:04 MST"
+
+// UpgradeWindow returns the time that an upgrade is expected to take with the given node drain
+// grace period, in minutes.
+func UpgradeWindow(nodeDrainMinutes float64) time.Duration {
+	return EstimatedUpgradeDuration + time.Duration(nodeDrainMinutes*float64(time.Minute))
+}
+
+// ValidateNextRun checks that the upgrade is scheduled at least MinScheduleDelay after the given
+// current time. The date and time of the schedule are always in UTC, regardless of the time zone
+// of the user.
+func ValidateNextRun(nextRun time.Time, now time.Time) error {
+	if nextRun.Before(now) {
+		return rerrors.ValidationErrorf("Upgrade scheduled on %s is in the past, the current time "+
+			"is %s. The date and time of the schedule are in UTC",
+			nextRun.UTC().Format(scheduleFormat), now.UTC().Format(scheduleFormat))
+	}
+	if nextRun.Before(now.Add(MinScheduleDelay)) {
+		return rerrors.ValidationErrorf("Upgrade scheduled on %s is less than %s in the future, "+
+			"the current time is %s. The date and time of the schedule are in UTC",
+			nextRun.UTC().Format(scheduleFormat), formatDelay(MinScheduleDelay),
+			now.UTC().Format(scheduleFormat))
+	}
+	return nil
+}
+
+// formatDelay formats a number of whole minutes, like '5 minutes'.
+func formatDelay(delay time.Duration) string {
+	return fmt.Sprintf("%d minutes", int(delay.Minutes()))
+}
+
+// FindScheduledUpgrade returns the manual upgrade policy of the given policies, or nil if there is
+// none.
+func FindScheduledUpgrade(upgradePolicies []*cmv1.UpgradePolicy) *cmv1.UpgradePolicy {
+	return FindScheduledUpgradeOfType(upgradePolicies, "OSD")
+}
+
+// FindScheduledUpgradeOfType returns the manual upgrade policy of the given type, or nil if there
+// is none.
+func FindScheduledUpgradeOfType(upgradePolicies []*cmv1.UpgradePolicy,
+	upgradeType string) *cmv1.UpgradePolicy {
+	for _, upgradePolicy := range upgradePolicies {
+		if upgradePolicy.ScheduleType() == "manual" && upgradePolicy.UpgradeType() == upgradeType {
+			return upgradePolicy
+		}
+	}
+	return nil
+}
+
+// FindConflicts returns the automatic upgrade policies whose next run overlaps the window of an
+// upgrade that starts at the given time. Both upgrades are assumed to take the given time.
+func FindConflicts(upgradePolicies []*cmv1.UpgradePolicy, nextRun time.Time,
+	window time.Duration) []*cmv1.UpgradePolicy {
+	conflicts := []*cmv1.UpgradePolicy{}
+	for _, upgradePolicy := range upgradePolicies {
+		if upgradePolicy.ScheduleType() != "automatic" {
+			continue
+		}
+		policyRun, ok := upgradePolicy.GetNextRun()
+		if !ok {
+			continue
+		}
+		distance := policyRun.Sub(nextRun)
+		if distance < 0 {
+			distance = -distance
+		}
+		if distance < window {
+			conflicts = append(conflicts, upgradePolicy)
+		}
+	}
+	return conflicts
+}
+
+// WindowConflictError is the error returned by CheckConflicts, so that callers can tell it apart from
+// the other conflicts, for example to suggest forcing the upgrade.
+type WindowConflictError struct {
+	error
+}
+
+// Unwrap returns the conflict error, which carries the exit code.
+func (e *WindowConflictError) Unwrap() error {
+	return e.error
+}
+
+// CheckConflicts returns a conflict error if an upgrade that starts at the given time overlaps the
+// window of one of the automatic upgrade policies.
+func CheckConflicts(upgradePolicies []*cmv1.UpgradePolicy, nextRun time.Time,
+	window time.Duration) error {
+	conflicts := FindConflicts(upgradePolicies, nextRun, window)
+	if len(conflicts) == 0 {
+		return nil
+	}
+	runs := make([]string, len(conflicts))
+	for i, conflict := range conflicts {
+		runs[i] = fmt.Sprintf("'%s' on %s", conflict.Schedule(),
+			conflict.NextRun().UTC().Format(scheduleFormat))
+	}
+	return &WindowConflictError{rerrors.ConflictErrorf("Upgrade scheduled on %s overlaps the "+
+		"maintenance window of the automatic upgrade policy %s, as upgrades are expected to take %s",
+		nextRun.UTC().Format(scheduleFormat), strings.Join(runs, ", "), window)}
+}
@@ -0,0 +1,77 @@
+package upgrades_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	. "github.com/openshift/moactl/pkg/ocm/upgrades"
+)
+
+var _ = Describe("Maintenance windows", func() {
+	nextRun := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	policy := func(scheduleType string, run time.Time) *cmv1.UpgradePolicy {
+		result, err := cmv1.NewUpgradePolicy().
+			ID("policy").
+			ScheduleType(scheduleType).
+			Schedule("0 10 * * 1").
+			UpgradeType("OSD").
+			NextRun(run).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		return result
+	}
+
+	It("Calculates the window from the node drain grace period", func() {
+		Expect(UpgradeWindow(0)).To(Equal(time.Hour))
+		Expect(UpgradeWindow(90)).To(Equal(150 * time.Minute))
+	})
+
+	It("Rejects schedules in the past", func() {
+		err := ValidateNextRun(nextRun, nextRun.Add(time.Minute))
+		Expect(err).To(MatchError(ContainSubstring("is in the past")))
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+		Expect(ValidateNextRun(nextRun, nextRun.Add(-MinScheduleDelay))).To(Succeed())
+	})
+
+	It("Rejects schedules that are too soon", func() {
+		err := ValidateNextRun(nextRun, nextRun.Add(-time.Minute))
+		Expect(err).To(MatchError(ContainSubstring("is less than 5 minutes in the future")))
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+	})
+
+	It("Finds the manual upgrade policy", func() {
+		manual := policy("manual", nextRun)
+		Expect(FindScheduledUpgrade([]*cmv1.UpgradePolicy{policy("automatic", nextRun), manual})).
+			To(BeIdenticalTo(manual))
+		Expect(FindScheduledUpgrade([]*cmv1.UpgradePolicy{policy("automatic", nextRun)})).To(BeNil())
+	})
+
+	It("Finds automatic policies whose window overlaps the upgrade", func() {
+		policies := []*cmv1.UpgradePolicy{
+			policy("automatic", nextRun.Add(-90*time.Minute)),
+			policy("automatic", nextRun.Add(30*time.Minute)),
+			policy("automatic", nextRun.Add(3*time.Hour)),
+			policy("manual", nextRun),
+		}
+		conflicts := FindConflicts(policies, nextRun, 2*time.Hour)
+		Expect(conflicts).To(HaveLen(2))
+		Expect(conflicts[0].NextRun()).To(Equal(nextRun.Add(-90 * time.Minute)))
+		Expect(conflicts[1].NextRun()).To(Equal(nextRun.Add(30 * time.Minute)))
+	})
+
+	It("Returns a conflict error for overlapping windows", func() {
+		policies := []*cmv1.UpgradePolicy{policy("automatic", nextRun.Add(30*time.Minute))}
+		err := CheckConflicts(policies, nextRun, time.Hour)
+		Expect(err).To(MatchError(ContainSubstring(
+			"overlaps the maintenance window of the automatic upgrade policy " +
+				"'0 10 * * 1' on 2021-03-01 10:30 UTC")))
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeConflict))
+		Expect(err).To(BeAssignableToTypeOf(&WindowConflictError{}))
+		Expect(CheckConflicts(policies, nextRun, 30*time.Minute)).To(Succeed())
+	})
+})
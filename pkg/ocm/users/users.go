@@ -0,0 +1,192 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package users
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
+	"gopkg.in/yaml.v2"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// GetUsernames combines the usernames given in the command line with the usernames read from the
+// given file, which contains one username per line. Empty lines and lines starting with '#' are
+// ignored, as are duplicated usernames.
+func GetUsernames(usernames []string, usersFile string) ([]string, error) {
+	all := []string{}
+	all = append(all, usernames...)
+
+	if usersFile != "" {
+		fileUsernames, err := readUsersFile(usersFile)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, fileUsernames...)
+	}
+
+	result := []string{}
+	seen := make(map[string]bool, len(all))
+	for _, username := range all {
+		username = strings.TrimSpace(username)
+		if username == "" || seen[username] {
+			continue
+		}
+		seen[username] = true
+		result = append(result, username)
+	}
+	return result, nil
+}
+
+func readUsersFile(path string) (usernames []string, err error) {
+	// #nosec G304
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open users file '%s': %v", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		usernames = append(usernames, line)
+	}
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read users file '%s': %v", path, err)
+	}
+	return usernames, nil
+}
+
+// group is the part of an OpenShift Group manifest, or of a list of them, that contains the users.
+type group struct {
+	Kind  string   `yaml:"kind"`
+	Users []string `yaml:"users"`
+	Items []group  `yaml:"items"`
+}
+
+// ReadGroupFile returns the members of the OpenShift groups defined in the given file. The file can
+// contain a single Group manifest or a list of them, like the output of 'oc get groups -o yaml', so
+// that the membership of groups can be copied from existing clusters.
+func ReadGroupFile(path string) ([]string, error) {
+	// #nosec G304
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read group file '%s': %v", path, err)
+	}
+	var document group
+	err = yaml.Unmarshal(data, &document)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse group file '%s': %v", path, err)
+	}
+	usernames, err := groupMembers(document)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid group file '%s': %v", path, err)
+	}
+	return usernames, nil
+}
+
+func groupMembers(document group) ([]string, error) {
+	switch document.Kind {
+	case "Group":
+		return document.Users, nil
+	case "List", "GroupList":
+		usernames := []string{}
+		for _, item := range document.Items {
+			if item.Kind != "" && item.Kind != "Group" {
+				return nil, fmt.Errorf("expected only items of kind 'Group', got '%s'", item.Kind)
+			}
+			usernames = append(usernames, item.Users...)
+		}
+		return usernames, nil
+	default:
+		return nil, fmt.Errorf("expected kind 'Group', 'GroupList' or 'List', got '%s'", document.Kind)
+	}
+}
+
+// GroupIDs returns the sorted identifiers of the given groups of a cluster.
+func GroupIDs(groups []*cmv1.Group) []string {
+	result := make([]string, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, group.ID())
+	}
+	sort.Strings(result)
+	return result
+}
+
+// ResolveGroup returns the identifier of the group that corresponds to the role given by the user.
+// The singular form of the name of a group is also accepted, for example 'cluster-admin' for
+// 'cluster-admins'. It fails with a validation error if the cluster doesn't have that group.
+func ResolveGroup(role string, groupIDs []string) (string, error) {
+	for _, candidate := range []string{role, role + "s"} {
+		for _, groupID := range groupIDs {
+			if candidate == groupID {
+				return groupID, nil
+			}
+		}
+	}
+	return "", rerrors.ValidationErrorf("Group '%s' doesn't exist in the cluster, expected one of: %s",
+		role, strings.Join(groupIDs, ", "))
+}
+
+// AddUser adds the user to the given group of the cluster.
+func AddUser(client *cmv1.ClustersClient, clusterID string, group string, username string) error {
+	user, err := cmv1.NewUser().ID(username).Build()
+	if err != nil {
+		return err
+	}
+	response, err := client.Cluster(clusterID).
+		Groups().
+		Group(group).
+		Users().
+		Add().
+		Body(user).
+		Send()
+	if err != nil {
+		return handleErr(response.Error(), err)
+	}
+	return nil
+}
+
+// RemoveUser removes the user from the given group of the cluster.
+func RemoveUser(client *cmv1.ClustersClient, clusterID string, group string, username string) error {
+	response, err := client.Cluster(clusterID).
+		Groups().
+		Group(group).
+		Users().
+		User(username).
+		Delete().
+		Send()
+	if err != nil {
+		return handleErr(response.Error(), err)
+	}
+	return nil
+}
+
+func handleErr(res *ocmerrors.Error, err error) error {
+	return rerrors.FromOCM(res, err)
+}
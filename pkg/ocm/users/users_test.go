@@ -0,0 +1,107 @@
+package users_test
+
+import (
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	. "github.com/openshift/moactl/pkg/ocm/users"
+)
+
+var _ = Describe("Users", func() {
+	Context("GetUsernames", func() {
+		var usersFile string
+
+		BeforeEach(func() {
+			file, err := ioutil.TempFile("", "users")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = file.WriteString("# Team members\nuser2\n\n  user3  \nuser1\n")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(file.Close()).To(Succeed())
+			usersFile = file.Name()
+		})
+		AfterEach(func() {
+			os.Remove(usersFile)
+		})
+
+		It("returns the usernames given in the command line", func() {
+			usernames, err := GetUsernames([]string{"user1", "user2"}, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(usernames).To(Equal([]string{"user1", "user2"}))
+		})
+
+		It("combines the usernames from the file skipping comments and duplicates", func() {
+			usernames, err := GetUsernames([]string{"user1"}, usersFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(usernames).To(Equal([]string{"user1", "user2", "user3"}))
+		})
+
+		It("fails when the file doesn't exist", func() {
+			_, err := GetUsernames(nil, usersFile+"-missing")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("ReadGroupFile", func() {
+		writeFile := func(content string) string {
+			file, err := ioutil.TempFile("", "group")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = file.WriteString(content)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(file.Close()).To(Succeed())
+			return file.Name()
+		}
+
+		It("reads the users of a group", func() {
+			path := writeFile("apiVersion: user.openshift.io/v1\nkind: Group\n" +
+				"metadata:\n  name: admins\nusers:\n- user1\n- user2\n")
+			defer os.Remove(path)
+			usernames, err := ReadGroupFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(usernames).To(Equal([]string{"user1", "user2"}))
+		})
+
+		It("reads the users of a list of groups", func() {
+			path := writeFile("apiVersion: v1\nkind: List\nitems:\n" +
+				"- kind: Group\n  metadata:\n    name: admins\n  users:\n  - user1\n" +
+				"- kind: Group\n  metadata:\n    name: devs\n  users:\n  - user2\n")
+			defer os.Remove(path)
+			usernames, err := ReadGroupFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(usernames).To(Equal([]string{"user1", "user2"}))
+		})
+
+		It("rejects manifests that aren't groups", func() {
+			path := writeFile("apiVersion: v1\nkind: ConfigMap\n")
+			defer os.Remove(path)
+			_, err := ReadGroupFile(path)
+			Expect(err).To(MatchError(ContainSubstring("expected kind 'Group'")))
+		})
+	})
+
+	Context("ResolveGroup", func() {
+		groupIDs := []string{"cluster-admins", "dedicated-admins", "dedicated-readers"}
+
+		It("accepts any group of the cluster", func() {
+			group, err := ResolveGroup("dedicated-readers", groupIDs)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(group).To(Equal("dedicated-readers"))
+		})
+
+		It("accepts the singular form of the name of a group", func() {
+			group, err := ResolveGroup("cluster-admin", groupIDs)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(group).To(Equal("cluster-admins"))
+		})
+
+		It("rejects groups that the cluster doesn't have", func() {
+			_, err := ResolveGroup("auditors", groupIDs)
+			Expect(err).To(MatchError("Group 'auditors' doesn't exist in the cluster, expected one of: " +
+				"cluster-admins, dedicated-admins, dedicated-readers"))
+			Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+		})
+	})
+})
@@ -17,16 +17,131 @@ limitations under the License.
 package versions
 
 import (
-	"errors"
+	"bytes"
 	"fmt"
+	"strconv"
+	"strings"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/cache"
 )
 
 const DefaultChannelGroup = "stable"
 
+// ChannelGroups are the channel groups that versions can belong to.
+var ChannelGroups = []string{"stable", "candidate", "fast", "nightly"}
+
+// ValidateChannelGroup checks that the channel group is one of the known channel groups. An empty
+// channel group is also valid, and means all the channel groups.
+func ValidateChannelGroup(channelGroup string) error {
+	if channelGroup == "" {
+		return nil
+	}
+	for _, valid := range ChannelGroups {
+		if channelGroup == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("Invalid channel group '%s', expected one of: %s", channelGroup,
+		strings.Join(ChannelGroups, ", "))
+}
+
+// GetRawVersion returns the version without the 'openshift-v' prefix and the channel group suffix
+// of the identifier, for example '4.5.16'.
+func GetRawVersion(version *cmv1.Version) string {
+	if version.RawID() != "" {
+		return version.RawID()
+	}
+	rawID := strings.TrimPrefix(version.ID(), "openshift-v")
+	if version.ChannelGroup() != "" && version.ChannelGroup() != DefaultChannelGroup {
+		rawID = strings.TrimSuffix(rawID, "-"+version.ChannelGroup())
+	}
+	return rawID
+}
+
+// IsAtLeast checks if the minor release of the version, for example '4.6' for '4.6.0-rc.4', is
+// the given minimum minor release or a later one.
+func IsAtLeast(version string, minimum string) (bool, error) {
+	major, minor, err := parseMinor(version)
+	if err != nil {
+		return false, err
+	}
+	minMajor, minMinor, err := parseMinor(minimum)
+	if err != nil {
+		return false, err
+	}
+	if major != minMajor {
+		return major > minMajor, nil
+	}
+	return minor >= minMinor, nil
+}
+
+// Compare compares two versions, for example '4.5.10' and '4.6.0-rc.4', returning a negative number
+// if the first one is lower, zero if they are equal and a positive number if it is higher. Only the
+// numbers are compared, so pre-releases are equal to the final release.
+func Compare(a string, b string) int {
+	partsA := strings.Split(strings.TrimPrefix(a, "openshift-v"), ".")
+	partsB := strings.Split(strings.TrimPrefix(b, "openshift-v"), ".")
+	for i := 0; i < 3; i++ {
+		diff := versionNumber(partsA, i) - versionNumber(partsB, i)
+		if diff != 0 {
+			return diff
+		}
+	}
+	return 0
+}
+
+// versionNumber returns the number at the beginning of the given part of a version, or zero if
+// there is no such part or it doesn't start with a number.
+func versionNumber(parts []string, i int) int {
+	if i >= len(parts) {
+		return 0
+	}
+	digits := parts[i]
+	end := strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' })
+	if end >= 0 {
+		digits = digits[:end]
+	}
+	value, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// parseMinor returns the major and minor numbers of the version, ignoring the 'openshift-v'
+// prefix of identifiers.
+func parseMinor(version string) (major int, minor int, err error) {
+	parts := strings.SplitN(strings.TrimPrefix(version, "openshift-v"), ".", 3)
+	if len(parts) < 2 {
+		err = fmt.Errorf("Invalid version '%s'", version)
+		return
+	}
+	major, err = strconv.Atoi(parts[0])
+	if err != nil {
+		err = fmt.Errorf("Invalid version '%s'", version)
+		return
+	}
+	minor, err = strconv.Atoi(strings.SplitN(parts[1], "-", 2)[0])
+	if err != nil {
+		err = fmt.Errorf("Invalid version '%s'", version)
+	}
+	return
+}
+
 func GetVersions(client *cmv1.Client, channelGroup string) (versions []*cmv1.Version, err error) {
+	// The list of versions changes rarely, so it is cached for a few minutes:
+	cacheKey := cache.VersionsKey(channelGroup)
+	if data, ok := cache.Get(cacheKey); ok {
+		versions, err = cmv1.UnmarshalVersionList(data)
+		if err == nil {
+			return versions, nil
+		}
+	}
+
 	collection := client.Versions()
 	page := 1
 	size := 100
@@ -34,6 +149,7 @@ func GetVersions(client *cmv1.Client, channelGroup string) (versions []*cmv1.Ver
 	if channelGroup != "" {
 		filter = fmt.Sprintf("%s AND channel_group = '%s'", filter, channelGroup)
 	}
+	versions = nil
 	for {
 		var response *cmv1.VersionsListResponse
 		response, err = collection.List().
@@ -51,12 +167,90 @@ func GetVersions(client *cmv1.Client, channelGroup string) (versions []*cmv1.Ver
 		}
 		page++
 	}
-	return
+
+	// Failing to update the cache shouldn't make the command fail:
+	var buffer bytes.Buffer
+	if cmv1.MarshalVersionList(versions, &buffer) == nil {
+		_ = cache.Set(cacheKey, buffer.Bytes(), cache.VersionsTTL)
+	}
+	return versions, nil
+}
+
+// ChannelGroupsOf returns the channel groups of the given versions, without duplicates. The known
+// channel groups come first, in the order of ChannelGroups.
+func ChannelGroupsOf(versionList []*cmv1.Version) []string {
+	found := map[string]bool{}
+	others := []string{}
+	for _, version := range versionList {
+		channelGroup := version.ChannelGroup()
+		if channelGroup == "" {
+			channelGroup = DefaultChannelGroup
+		}
+		if !found[channelGroup] {
+			found[channelGroup] = true
+			others = append(others, channelGroup)
+		}
+	}
+	result := []string{}
+	for _, channelGroup := range ChannelGroups {
+		if found[channelGroup] {
+			result = append(result, channelGroup)
+			delete(found, channelGroup)
+		}
+	}
+	for _, channelGroup := range others {
+		if found[channelGroup] {
+			result = append(result, channelGroup)
+		}
+	}
+	return result
+}
+
+// ValidateAvailableChannelGroup checks that the channel group is valid and that the account can use
+// at least one version of it.
+func ValidateAvailableChannelGroup(client *cmv1.Client, channelGroup string) error {
+	err := ValidateChannelGroup(channelGroup)
+	if err != nil {
+		return rerrors.ValidationErrorf("%v", err)
+	}
+	versionList, err := GetVersions(client, "")
+	if err != nil {
+		return fmt.Errorf("Failed to get versions: %w", err)
+	}
+	available := ChannelGroupsOf(versionList)
+	for _, valid := range available {
+		if channelGroup == valid {
+			return nil
+		}
+	}
+	return rerrors.ValidationErrorf("Channel group '%s' isn't available to your account, the "+
+		"available channel groups are: %s", channelGroup, strings.Join(available, ", "))
+}
+
+// ChannelGroupSpec returns the cluster update that moves the cluster to the given channel group, so
+// that it can be upgraded to the versions of that channel group.
+func ChannelGroupSpec(channelGroup string) (*cmv1.Cluster, error) {
+	return cmv1.NewCluster().
+		Version(cmv1.NewVersion().ChannelGroup(channelGroup)).
+		Build()
+}
+
+// GetVersionIDInChannelGroup returns the identifier of the current version of the cluster in the
+// given channel group, which is used to find the upgrades available in that channel group.
+func GetVersionIDInChannelGroup(cluster *cmv1.Cluster, channelGroup string) string {
+	if channelGroup == "" || channelGroup == cluster.Version().ChannelGroup() {
+		return GetVersionID(cluster)
+	}
+	version := cluster.OpenshiftVersion()
+	if version == "" {
+		version = GetRawVersion(cluster.Version())
+	}
+	return CreateVersionID(version, channelGroup)
 }
 
 func GetVersionID(cluster *cmv1.Cluster) string {
 	if cluster.OpenshiftVersion() != "" {
-		return createVersionID(cluster.OpenshiftVersion(), cluster.Version().ChannelGroup())
+		return CreateVersionID(cluster.OpenshiftVersion(), cluster.Version().ChannelGroup())
 	}
 	return cluster.Version().ID()
 }
@@ -71,7 +265,7 @@ func GetAvailableUpgrades(client *cmv1.Client, versionID string) ([]string, erro
 	availableUpgrades := []string{}
 
 	for _, v := range version.AvailableUpgrades() {
-		id := createVersionID(v, version.ChannelGroup())
+		id := CreateVersionID(v, version.ChannelGroup())
 		resp, err := client.Versions().Version(id).Get().Send()
 		if err != nil {
 			return nil, handleErr(response.Error(), err)
@@ -85,7 +279,8 @@ func GetAvailableUpgrades(client *cmv1.Client, versionID string) ([]string, erro
 	return availableUpgrades, nil
 }
 
-func createVersionID(version string, channelGroup string) string {
+// CreateVersionID returns the identifier of the given version in the given channel group.
+func CreateVersionID(version string, channelGroup string) string {
 	versionID := fmt.Sprintf("openshift-v%s", version)
 	if channelGroup != "stable" {
 		versionID = fmt.Sprintf("%s-%s", versionID, channelGroup)
@@ -94,9 +289,5 @@ func createVersionID(version string, channelGroup string) string {
 }
 
 func handleErr(res *ocmerrors.Error, err error) error {
-	msg := res.Reason()
-	if msg == "" {
-		msg = err.Error()
-	}
-	return errors.New(msg)
+	return rerrors.FromOCM(res, err)
 }
@@ -0,0 +1,125 @@
+package versions_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/openshift/moactl/pkg/ocm/versions"
+)
+
+var _ = Describe("Versions", func() {
+	Context("ValidateChannelGroup", func() {
+		It("accepts the known channel groups", func() {
+			for _, channelGroup := range ChannelGroups {
+				Expect(ValidateChannelGroup(channelGroup)).To(Succeed())
+			}
+		})
+
+		It("accepts an empty channel group", func() {
+			Expect(ValidateChannelGroup("")).To(Succeed())
+		})
+
+		It("rejects unknown channel groups", func() {
+			Expect(ValidateChannelGroup("beta")).NotTo(Succeed())
+		})
+	})
+
+	Context("IsAtLeast", func() {
+		It("compares the minor releases", func() {
+			for _, version := range []string{"4.6.1", "4.6.0-rc.4", "4.10.3", "openshift-v4.7.0", "5.0.0"} {
+				ok, err := IsAtLeast(version, "4.6")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ok).To(BeTrue(), version)
+			}
+			for _, version := range []string{"4.5.16", "4.5.0-rc.1", "3.11.0"} {
+				ok, err := IsAtLeast(version, "4.6")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ok).To(BeFalse(), version)
+			}
+		})
+
+		It("rejects invalid versions", func() {
+			_, err := IsAtLeast("latest", "4.6")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Compare", func() {
+		It("compares the numbers of the versions", func() {
+			Expect(Compare("4.5.10", "4.5.9")).To(BeNumerically(">", 0))
+			Expect(Compare("4.5.16", "4.6.0")).To(BeNumerically("<", 0))
+			Expect(Compare("openshift-v4.6.1", "4.6.1")).To(BeZero())
+		})
+
+		It("ignores the pre-release suffixes", func() {
+			Expect(Compare("4.6.0-rc.4", "4.6.0")).To(BeZero())
+			Expect(Compare("4.6.1-rc.1", "4.6.0")).To(BeNumerically(">", 0))
+		})
+	})
+
+	Context("GetRawVersion", func() {
+		It("uses the raw identifier when present", func() {
+			version, err := cmv1.NewVersion().
+				ID("openshift-v4.6.1-candidate").
+				RawID("4.6.1").
+				ChannelGroup("candidate").
+				Build()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(GetRawVersion(version)).To(Equal("4.6.1"))
+		})
+
+		It("strips the prefix of stable versions", func() {
+			version, err := cmv1.NewVersion().
+				ID("openshift-v4.5.16").
+				ChannelGroup("stable").
+				Build()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(GetRawVersion(version)).To(Equal("4.5.16"))
+		})
+
+		It("strips the channel group suffix of other versions", func() {
+			version, err := cmv1.NewVersion().
+				ID("openshift-v4.6.0-rc.4-candidate").
+				ChannelGroup("candidate").
+				Build()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(GetRawVersion(version)).To(Equal("4.6.0-rc.4"))
+		})
+	})
+
+	Context("ChannelGroupsOf", func() {
+		It("returns the known channel groups first and without duplicates", func() {
+			list := []*cmv1.Version{}
+			for _, channelGroup := range []string{"custom", "candidate", "", "stable", "candidate"} {
+				version, err := cmv1.NewVersion().ChannelGroup(channelGroup).Build()
+				Expect(err).NotTo(HaveOccurred())
+				list = append(list, version)
+			}
+			Expect(ChannelGroupsOf(list)).To(Equal([]string{"stable", "candidate", "custom"}))
+		})
+	})
+
+	Context("GetVersionIDInChannelGroup", func() {
+		var cluster *cmv1.Cluster
+
+		BeforeEach(func() {
+			var err error
+			cluster, err = cmv1.NewCluster().
+				OpenshiftVersion("4.6.8").
+				Version(cmv1.NewVersion().ID("openshift-v4.6.8").ChannelGroup("stable")).
+				Build()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("uses the current channel group by default", func() {
+			Expect(GetVersionIDInChannelGroup(cluster, "")).To(Equal("openshift-v4.6.8"))
+			Expect(GetVersionIDInChannelGroup(cluster, "stable")).To(Equal("openshift-v4.6.8"))
+		})
+
+		It("uses the version of the requested channel group", func() {
+			Expect(GetVersionIDInChannelGroup(cluster, "fast")).To(Equal("openshift-v4.6.8-fast"))
+		})
+	})
+})
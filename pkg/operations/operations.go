@@ -0,0 +1,244 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the long running operations started by the tool, like the creation of
+// clusters or their upgrades. Operations are stored locally, in the configuration directory of the
+// current profile, so that their progress can be checked after the command that started them has
+// finished.
+
+package operations
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/openshift/moactl/pkg/config"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+)
+
+// Types of operations:
+const (
+	CreateCluster     = "create-cluster"
+	DeleteCluster     = "delete-cluster"
+	UpgradeCluster    = "upgrade-cluster"
+	CreateMachinePool = "create-machinepool"
+	EditMachinePool   = "edit-machinepool"
+	DeleteMachinePool = "delete-machinepool"
+)
+
+// States of operations:
+const (
+	StateInProgress = "in-progress"
+	StateSucceeded  = "succeeded"
+	StateFailed     = "failed"
+)
+
+// Retention is the time that finished operations are kept before they are removed.
+const Retention = 7 * 24 * time.Hour
+
+// Operation is a change started by the tool that OCM completes asynchronously. The operation
+// identifier is the one assigned by the OCM API to the request that started it, and can be used to
+// find the request in the logs of the API.
+type Operation struct {
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`
+	ClusterID   string    `json:"cluster_id"`
+	ClusterName string    `json:"cluster_name"`
+	Resource    string    `json:"resource,omitempty"`
+	Target      string    `json:"target,omitempty"`
+	OperationID string    `json:"operation_id,omitempty"`
+	State       string    `json:"state"`
+	Details     string    `json:"details,omitempty"`
+	StartedAt   time.Time `json:"started_at"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+	FinishedAt  time.Time `json:"finished_at,omitempty"`
+}
+
+// Finished returns true if the operation has succeeded or failed.
+func (o *Operation) Finished() bool {
+	return o.State == StateSucceeded || o.State == StateFailed
+}
+
+// Description returns a short description of what the operation does.
+func (o *Operation) Description() string {
+	switch o.Type {
+	case CreateCluster:
+		return fmt.Sprintf("Create cluster '%s'", o.ClusterName)
+	case DeleteCluster:
+		return fmt.Sprintf("Delete cluster '%s'", o.ClusterName)
+	case UpgradeCluster:
+		return fmt.Sprintf("Upgrade cluster '%s' to version %s", o.ClusterName, o.Target)
+	case CreateMachinePool:
+		return fmt.Sprintf("Create machine pool '%s' of cluster '%s'", o.Resource, o.ClusterName)
+	case EditMachinePool:
+		return fmt.Sprintf("Edit machine pool '%s' of cluster '%s'", o.Resource, o.ClusterName)
+	case DeleteMachinePool:
+		return fmt.Sprintf("Delete machine pool '%s' of cluster '%s'", o.Resource, o.ClusterName)
+	}
+	return o.Type
+}
+
+// Load returns the operations of the current profile, sorted by identifier.
+func Load() ([]*Operation, error) {
+	file, err := location()
+	if err != nil {
+		return nil, err
+	}
+	// #nosec G304
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return []*Operation{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read operations file '%s': %v", file, err)
+	}
+	list := []*Operation{}
+	err = json.Unmarshal(data, &list)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse operations file '%s': %v", file, err)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return number(list[i].ID) < number(list[j].ID)
+	})
+	return list, nil
+}
+
+// Save replaces the operations of the current profile.
+func Save(list []*Operation) error {
+	file, err := location()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(file), 0700)
+	if err != nil {
+		return fmt.Errorf("Failed to create operations directory: %v", err)
+	}
+	err = ioutil.WriteFile(file, data, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to write operations file '%s': %v", file, err)
+	}
+	return nil
+}
+
+// Start assigns an identifier to the operation, marks it as in progress and stores it with the
+// rest of the operations of the current profile. Operations that finished more than the retention
+// time ago are removed at the same time.
+func Start(operation *Operation) error {
+	list, err := Load()
+	if err != nil {
+		return err
+	}
+	id := 1
+	for _, item := range list {
+		if n := number(item.ID); n >= id {
+			id = n + 1
+		}
+	}
+	now := time.Now().UTC()
+	operation.ID = strconv.Itoa(id)
+	operation.State = StateInProgress
+	if operation.StartedAt.IsZero() {
+		operation.StartedAt = now
+	}
+	operation.UpdatedAt = operation.StartedAt
+	return Save(append(Prune(list, now), operation))
+}
+
+// Record starts the operation with the identifier of the last request sent to the OCM API, and
+// tells the user how to follow it. Failures are only reported as warnings, as the change that the
+// operation tracks has already been requested.
+func Record(reporter *rprtr.Object, operation *Operation) {
+	if operation.OperationID == "" {
+		operation.OperationID = ocm.LastOperationID()
+	}
+	err := Start(operation)
+	if err != nil {
+		reporter.Warnf("Failed to record operation: %v", err)
+		return
+	}
+	reporter.Infof("To follow the progress of the operation, run 'rosa describe operation %s "+
+		"--watch'", operation.ID)
+}
+
+// Get returns the operation with the given identifier.
+func Get(list []*Operation, id string) (*Operation, error) {
+	for _, item := range list {
+		if item.ID == id {
+			return item, nil
+		}
+	}
+	return nil, rerrors.NotFoundErrorf("Operation '%s' doesn't exist", id)
+}
+
+// InProgress returns the operations that haven't finished yet.
+func InProgress(list []*Operation) []*Operation {
+	result := []*Operation{}
+	for _, item := range list {
+		if !item.Finished() {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Prune returns the operations without the ones that finished more than the retention time before
+// the given time.
+func Prune(list []*Operation, now time.Time) []*Operation {
+	result := []*Operation{}
+	for _, item := range list {
+		if item.Finished() && now.Sub(item.FinishedAt) > Retention {
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// location returns the file that stores the operations of the current profile.
+func location() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	profile, err := config.CurrentProfile()
+	if err != nil {
+		return "", err
+	}
+	if profile == "" {
+		profile = "default"
+	}
+	return filepath.Join(dir, "operations", profile+".json"), nil
+}
+
+func number(id string) int {
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return 0
+	}
+	return n
+}
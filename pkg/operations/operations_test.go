@@ -0,0 +1,72 @@
+package operations_test
+
+import (
+	"io/ioutil"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/operations"
+)
+
+var _ = Describe("Operations", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "rosa-operations")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Setenv("ROSA_CONFIG_DIR", dir)).To(Succeed())
+		Expect(os.Setenv("ROSA_PROFILE", "")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Unsetenv("ROSA_CONFIG_DIR")).To(Succeed())
+		Expect(os.Unsetenv("ROSA_PROFILE")).To(Succeed())
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("Stores started operations", func() {
+		Expect(operations.Start(&operations.Operation{
+			Type: operations.CreateCluster, ClusterID: "123", ClusterName: "mycluster",
+			OperationID: "abc",
+		})).To(Succeed())
+		Expect(operations.Start(&operations.Operation{
+			Type: operations.DeleteMachinePool, ClusterID: "123", ClusterName: "mycluster",
+			Resource: "workers",
+		})).To(Succeed())
+
+		list, err := operations.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list).To(HaveLen(2))
+		Expect(list[0].ID).To(Equal("1"))
+		Expect(list[0].State).To(Equal(operations.StateInProgress))
+		Expect(list[0].OperationID).To(Equal("abc"))
+		Expect(list[0].StartedAt.IsZero()).To(BeFalse())
+		Expect(list[1].ID).To(Equal("2"))
+		Expect(list[1].Description()).To(Equal("Delete machine pool 'workers' of cluster 'mycluster'"))
+
+		operation, err := operations.Get(list, "2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(operation.Resource).To(Equal("workers"))
+		_, err = operations.Get(list, "3")
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeNotFound))
+	})
+
+	It("Removes operations that finished before the retention time", func() {
+		now := time.Now()
+		list := []*operations.Operation{
+			{ID: "1", State: operations.StateSucceeded, FinishedAt: now.Add(-8 * 24 * time.Hour)},
+			{ID: "2", State: operations.StateFailed, FinishedAt: now.Add(-time.Hour)},
+			{ID: "3", State: operations.StateInProgress, StartedAt: now.Add(-30 * 24 * time.Hour)},
+		}
+		pruned := operations.Prune(list, now)
+		Expect(pruned).To(HaveLen(2))
+		Expect(pruned[0].ID).To(Equal("2"))
+		Expect(pruned[1].ID).To(Equal("3"))
+		Expect(operations.InProgress(list)).To(Equal(list[2:]))
+	})
+})
@@ -0,0 +1,184 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to check the progress of operations, comparing what they
+// were expected to do with the current state of the resources in OCM.
+
+package operations
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/ocm/upgrades"
+)
+
+// Observation contains the current state of the resources changed by an operation. Resources that
+// don't exist are nil.
+type Observation struct {
+	Cluster      *cmv1.Cluster
+	MachinePool  *cmv1.MachinePool
+	Upgrade      *cmv1.UpgradePolicy
+	UpgradeState string
+}
+
+// Evaluate updates the state of the operation according to the observed resources. It returns true
+// if the state changed.
+func (o *Operation) Evaluate(observation *Observation, now time.Time) bool {
+	if o.Finished() {
+		return false
+	}
+	state, details := o.evaluate(observation)
+	changed := state != o.State || details != o.Details
+	o.State = state
+	o.Details = details
+	o.UpdatedAt = now.UTC()
+	if o.Finished() {
+		o.FinishedAt = now.UTC()
+	}
+	return changed
+}
+
+func (o *Operation) evaluate(observation *Observation) (string, string) {
+	cluster := observation.Cluster
+	switch o.Type {
+	case CreateCluster:
+		if cluster == nil {
+			return StateFailed, "Cluster no longer exists"
+		}
+		switch cluster.State() {
+		case cmv1.ClusterStateReady:
+			return StateSucceeded, "Cluster is ready"
+		case cmv1.ClusterStateError:
+			return StateFailed, "Cluster installation failed"
+		case cmv1.ClusterStateUninstalling:
+			return StateFailed, "Cluster is being uninstalled"
+		}
+		return StateInProgress, fmt.Sprintf("Cluster is %s", cluster.State())
+	case DeleteCluster:
+		if cluster == nil {
+			return StateSucceeded, "Cluster has been deleted"
+		}
+		return StateInProgress, fmt.Sprintf("Cluster is %s", cluster.State())
+	case UpgradeCluster:
+		if cluster == nil {
+			return StateFailed, "Cluster no longer exists"
+		}
+		if observation.Upgrade == nil {
+			// Upgrade policies are removed once the upgrade has finished, so the only thing left
+			// to check is the version of the cluster:
+			if cluster.Version().RawID() == o.Target {
+				return StateSucceeded, fmt.Sprintf("Cluster is running version %s", o.Target)
+			}
+			return StateFailed, fmt.Sprintf("Upgrade was removed and cluster is running version %s",
+				cluster.Version().RawID())
+		}
+		switch observation.UpgradeState {
+		case "completed":
+			return StateSucceeded, fmt.Sprintf("Cluster is running version %s", o.Target)
+		case "failed", "cancelled":
+			return StateFailed, fmt.Sprintf("Upgrade is %s", observation.UpgradeState)
+		case "":
+			return StateInProgress, "Upgrade is scheduled"
+		}
+		return StateInProgress, fmt.Sprintf("Upgrade is %s", observation.UpgradeState)
+	case CreateMachinePool, EditMachinePool:
+		if cluster == nil {
+			return StateFailed, "Cluster no longer exists"
+		}
+		if observation.MachinePool == nil {
+			return StateFailed, "Machine pool doesn't exist"
+		}
+		return StateSucceeded, fmt.Sprintf("Machine pool has %d replicas",
+			observation.MachinePool.Replicas())
+	case DeleteMachinePool:
+		if cluster == nil || observation.MachinePool == nil {
+			return StateSucceeded, "Machine pool has been deleted"
+		}
+		return StateInProgress, "Machine pool is being deleted"
+	}
+	return StateFailed, fmt.Sprintf("Unknown operation type '%s'", o.Type)
+}
+
+// Observe loads from OCM the current state of the resources changed by the operation.
+func Observe(ctx context.Context, client *cmv1.Client, operation *Operation) (*Observation, error) {
+	observation := &Observation{}
+	clusterResource := client.Clusters().Cluster(operation.ClusterID)
+	clusterResponse, err := clusterResource.Get().SendContext(ctx)
+	if clusterResponse != nil && clusterResponse.Status() == http.StatusNotFound {
+		return observation, nil
+	}
+	if err != nil {
+		return nil, rerrors.FromOCM(clusterResponse.Error(), err)
+	}
+	observation.Cluster = clusterResponse.Body()
+
+	switch operation.Type {
+	case UpgradeCluster:
+		policies, err := upgrades.GetUpgradePolicies(client, operation.ClusterID)
+		if err != nil {
+			return nil, err
+		}
+		for _, policy := range policies {
+			if policy.UpgradeType() != "OSD" || policy.Version() != operation.Target {
+				continue
+			}
+			observation.Upgrade = policy
+			state, err := upgrades.GetUpgradePolicyState(client, operation.ClusterID, policy.ID())
+			if err != nil {
+				return nil, err
+			}
+			observation.UpgradeState = state.Value()
+			break
+		}
+	case CreateMachinePool, EditMachinePool, DeleteMachinePool:
+		poolResponse, err := clusterResource.MachinePools().MachinePool(operation.Resource).Get().
+			SendContext(ctx)
+		if poolResponse != nil && poolResponse.Status() == http.StatusNotFound {
+			return observation, nil
+		}
+		if err != nil {
+			return nil, rerrors.FromOCM(poolResponse.Error(), err)
+		}
+		observation.MachinePool = poolResponse.Body()
+	}
+	return observation, nil
+}
+
+// Refresh observes the resources changed by the operations that haven't finished yet and updates
+// their states. Operations that can't be observed are left unchanged and their errors are returned
+// together. It returns true if any of the operations changed.
+func Refresh(ctx context.Context, client *cmv1.Client, list []*Operation,
+	now time.Time) (bool, []error) {
+	changed := false
+	errs := []error{}
+	for _, operation := range InProgress(list) {
+		observation, err := Observe(ctx, client, operation)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Failed to check operation '%s': %v", operation.ID, err))
+			continue
+		}
+		if operation.Evaluate(observation, now) {
+			changed = true
+		}
+	}
+	return changed, errs
+}
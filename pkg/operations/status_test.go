@@ -0,0 +1,133 @@
+package operations_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/operations"
+)
+
+func cluster(state cmv1.ClusterState, version string) *cmv1.Cluster {
+	cluster, err := cmv1.NewCluster().
+		ID("123").
+		State(state).
+		Version(cmv1.NewVersion().RawID(version)).
+		Build()
+	Expect(err).NotTo(HaveOccurred())
+	return cluster
+}
+
+func machinePool() *cmv1.MachinePool {
+	pool, err := cmv1.NewMachinePool().ID("workers").Replicas(3).Build()
+	Expect(err).NotTo(HaveOccurred())
+	return pool
+}
+
+func upgrade() *cmv1.UpgradePolicy {
+	policy, err := cmv1.NewUpgradePolicy().ID("456").UpgradeType("OSD").Version("4.6.1").Build()
+	Expect(err).NotTo(HaveOccurred())
+	return policy
+}
+
+var _ = Describe("Evaluate", func() {
+	DescribeTable("Updates the state of operations from the observed resources",
+		func(operationType string, observation func() *operations.Observation, state string,
+			details string) {
+			now := time.Now()
+			operation := &operations.Operation{
+				Type:     operationType,
+				Resource: "workers",
+				Target:   "4.6.1",
+				State:    operations.StateInProgress,
+			}
+			operation.Evaluate(observation(), now)
+			Expect(operation.State).To(Equal(state))
+			Expect(operation.Details).To(Equal(details))
+			Expect(operation.Finished()).To(Equal(!operation.FinishedAt.IsZero()))
+		},
+		Entry("Cluster being installed", operations.CreateCluster,
+			func() *operations.Observation {
+				return &operations.Observation{Cluster: cluster(cmv1.ClusterStateInstalling, "4.6.0")}
+			},
+			operations.StateInProgress, "Cluster is installing"),
+		Entry("Cluster installed", operations.CreateCluster,
+			func() *operations.Observation {
+				return &operations.Observation{Cluster: cluster(cmv1.ClusterStateReady, "4.6.0")}
+			},
+			operations.StateSucceeded, "Cluster is ready"),
+		Entry("Cluster failed to install", operations.CreateCluster,
+			func() *operations.Observation {
+				return &operations.Observation{Cluster: cluster(cmv1.ClusterStateError, "4.6.0")}
+			},
+			operations.StateFailed, "Cluster installation failed"),
+		Entry("Cluster being deleted", operations.DeleteCluster,
+			func() *operations.Observation {
+				return &operations.Observation{Cluster: cluster(cmv1.ClusterStateUninstalling, "4.6.0")}
+			},
+			operations.StateInProgress, "Cluster is uninstalling"),
+		Entry("Cluster deleted", operations.DeleteCluster,
+			func() *operations.Observation {
+				return &operations.Observation{}
+			},
+			operations.StateSucceeded, "Cluster has been deleted"),
+		Entry("Upgrade scheduled", operations.UpgradeCluster,
+			func() *operations.Observation {
+				return &operations.Observation{
+					Cluster: cluster(cmv1.ClusterStateReady, "4.6.0"), Upgrade: upgrade(),
+					UpgradeState: "pending",
+				}
+			},
+			operations.StateInProgress, "Upgrade is pending"),
+		Entry("Upgrade failed", operations.UpgradeCluster,
+			func() *operations.Observation {
+				return &operations.Observation{
+					Cluster: cluster(cmv1.ClusterStateReady, "4.6.0"), Upgrade: upgrade(),
+					UpgradeState: "failed",
+				}
+			},
+			operations.StateFailed, "Upgrade is failed"),
+		Entry("Upgrade finished and removed", operations.UpgradeCluster,
+			func() *operations.Observation {
+				return &operations.Observation{Cluster: cluster(cmv1.ClusterStateReady, "4.6.1")}
+			},
+			operations.StateSucceeded, "Cluster is running version 4.6.1"),
+		Entry("Upgrade removed before running", operations.UpgradeCluster,
+			func() *operations.Observation {
+				return &operations.Observation{Cluster: cluster(cmv1.ClusterStateReady, "4.6.0")}
+			},
+			operations.StateFailed, "Upgrade was removed and cluster is running version 4.6.0"),
+		Entry("Machine pool created", operations.CreateMachinePool,
+			func() *operations.Observation {
+				return &operations.Observation{
+					Cluster: cluster(cmv1.ClusterStateReady, "4.6.0"), MachinePool: machinePool(),
+				}
+			},
+			operations.StateSucceeded, "Machine pool has 3 replicas"),
+		Entry("Machine pool being deleted", operations.DeleteMachinePool,
+			func() *operations.Observation {
+				return &operations.Observation{
+					Cluster: cluster(cmv1.ClusterStateReady, "4.6.0"), MachinePool: machinePool(),
+				}
+			},
+			operations.StateInProgress, "Machine pool is being deleted"),
+		Entry("Machine pool deleted", operations.DeleteMachinePool,
+			func() *operations.Observation {
+				return &operations.Observation{Cluster: cluster(cmv1.ClusterStateReady, "4.6.0")}
+			},
+			operations.StateSucceeded, "Machine pool has been deleted"),
+	)
+
+	It("Doesn't change finished operations", func() {
+		operation := &operations.Operation{
+			Type:  operations.CreateCluster,
+			State: operations.StateSucceeded,
+		}
+		Expect(operation.Evaluate(&operations.Observation{}, time.Now())).To(BeFalse())
+		Expect(operation.State).To(Equal(operations.StateSucceeded))
+	})
+})
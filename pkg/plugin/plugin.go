@@ -0,0 +1,103 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to find and run plugins, executables named like
+// 'rosa-foo' that implement commands that aren't built in, so that the tool can be extended
+// without changing it.
+
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Prefixes of the names of the executables that are considered plugins. The old name of the tool
+// is also accepted so that existing plugins keep working.
+var Prefixes = []string{"rosa", "moactl"}
+
+// Environment variables used to pass the OCM connection details to the plugins:
+const (
+	URLEnv   = "ROSA_OCM_URL"
+	TokenEnv = "ROSA_OCM_TOKEN"
+)
+
+// Find searches for the plugin that implements the given command line, trying the longest name
+// first: 'rosa foo bar' runs 'rosa-foo-bar' if it exists, and 'rosa-foo' with 'bar' as argument
+// otherwise. The search stops at the first flag. It returns the path of the executable and the
+// arguments that should be passed to it, and false if there is no plugin.
+func Find(argv []string, lookPath func(string) (string, error)) (string, []string, bool) {
+	names := []string{}
+	for _, arg := range argv {
+		if strings.HasPrefix(arg, "-") || strings.ContainsAny(arg, `/\`) {
+			break
+		}
+		names = append(names, arg)
+	}
+	for i := len(names); i > 0; i-- {
+		for _, prefix := range Prefixes {
+			name := prefix + "-" + strings.Join(names[:i], "-")
+			path, err := lookPath(name)
+			if err == nil {
+				return path, argv[i:], true
+			}
+		}
+	}
+	return "", nil, false
+}
+
+// Environment returns the given environment with the URL of the OCM API and the access token of
+// the user added, so that plugins can use the same account. The token is omitted when the user
+// isn't logged in.
+func Environment(environ []string, url string, token string) []string {
+	result := make([]string, 0, len(environ)+2)
+	for _, variable := range environ {
+		if strings.HasPrefix(variable, URLEnv+"=") || strings.HasPrefix(variable, TokenEnv+"=") {
+			continue
+		}
+		result = append(result, variable)
+	}
+	if url != "" {
+		result = append(result, fmt.Sprintf("%s=%s", URLEnv, url))
+	}
+	if token != "" {
+		result = append(result, fmt.Sprintf("%s=%s", TokenEnv, token))
+	}
+	return result
+}
+
+// Run runs the plugin with the given arguments and environment, connected to the standard input
+// and output of the tool, and returns its exit code.
+func Run(path string, args []string, env []string) (int, error) {
+	// #nosec G204
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = env
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("Failed to run plugin '%s': %v", path, err)
+	}
+	return 0, nil
+}
@@ -0,0 +1,66 @@
+package plugin_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/plugin"
+)
+
+var _ = Describe("Plugin", func() {
+	lookPath := func(executables ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			for _, executable := range executables {
+				if executable == name {
+					return "/usr/local/bin/" + name, nil
+				}
+			}
+			return "", fmt.Errorf("executable file '%s' not found", name)
+		}
+	}
+
+	Context("Find", func() {
+		It("Prefers the plugin with the longest name", func() {
+			path, args, ok := Find([]string{"foo", "bar", "baz"}, lookPath("rosa-foo", "rosa-foo-bar"))
+			Expect(ok).To(BeTrue())
+			Expect(path).To(Equal("/usr/local/bin/rosa-foo-bar"))
+			Expect(args).To(Equal([]string{"baz"}))
+		})
+
+		It("Accepts plugins with the old name of the tool", func() {
+			path, args, ok := Find([]string{"foo", "--flag=value"}, lookPath("moactl-foo"))
+			Expect(ok).To(BeTrue())
+			Expect(path).To(Equal("/usr/local/bin/moactl-foo"))
+			Expect(args).To(Equal([]string{"--flag=value"}))
+		})
+
+		It("Stops searching at the first flag", func() {
+			_, _, ok := Find([]string{"foo", "--bar", "baz"}, lookPath("rosa-foo-baz"))
+			Expect(ok).To(BeFalse())
+		})
+
+		It("Ignores arguments that contain paths", func() {
+			_, _, ok := Find([]string{"../foo"}, lookPath("rosa-../foo"))
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Context("Environment", func() {
+		It("Adds the URL and the token replacing existing values", func() {
+			env := Environment([]string{"HOME=/home/alice", "ROSA_OCM_TOKEN=old"},
+				"https://api.openshift.com", "new")
+			Expect(env).To(Equal([]string{
+				"HOME=/home/alice",
+				"ROSA_OCM_URL=https://api.openshift.com",
+				"ROSA_OCM_TOKEN=new",
+			}))
+		})
+
+		It("Omits the token when the user isn't logged in", func() {
+			env := Environment([]string{"HOME=/home/alice"}, "", "")
+			Expect(env).To(Equal([]string{"HOME=/home/alice"}))
+		})
+	})
+})
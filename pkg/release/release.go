@@ -0,0 +1,242 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to find the latest release of the tool, to tell the user
+// when there is a newer one, and to replace the running executable with it.
+
+package release
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/openpgp"
+
+	"github.com/openshift/moactl/pkg/download"
+	"github.com/openshift/moactl/pkg/info"
+	"github.com/openshift/moactl/pkg/ocm/cache"
+)
+
+// DisableEnv is the environment variable that disables the automatic check for new releases, for
+// example in air gapped environments.
+const DisableEnv = "ROSA_DISABLE_VERSION_CHECK"
+
+// DefaultKeyring is the file that contains the public keys used by Red Hat to sign releases, as
+// installed in Red Hat Enterprise Linux and Fedora.
+const DefaultKeyring = "/etc/pki/rpm-gpg/RPM-GPG-KEY-redhat-release"
+
+// releasesURL is the directory of the mirror that contains one directory per release.
+var releasesURL = download.MirrorURL + "/clients/rosa/"
+
+// versionLink matches the links to the directories of the releases in the listing of the mirror.
+var versionLink = regexp.MustCompile(`href="(?:[^"]*/)?(\d+\.\d+\.\d+)/?"`)
+
+// CheckEnabled returns false if the automatic check for new releases has been disabled with the
+// environment variable.
+func CheckEnabled() bool {
+	value := os.Getenv(DisableEnv)
+	return value == "" || value == "false" || value == "0"
+}
+
+// LatestVersion returns the latest release published in the mirror. The result is cached, so that
+// the mirror is contacted at most once per day, unless the cache is ignored. Failures are cached
+// for the same time, so that machines that can't reach the mirror don't try every time.
+func LatestVersion(ctx context.Context, ignoreCache bool) (string, error) {
+	key := cache.LatestReleaseKey()
+	if !ignoreCache {
+		if value, ok := cache.Get(key); ok {
+			if len(value) == 0 {
+				return "", fmt.Errorf("Failed to get releases: the last attempt failed")
+			}
+			return string(value), nil
+		}
+	}
+	// The failure is saved before contacting the mirror, and replaced with the result when it
+	// succeeds, because the process may exit before the mirror responds:
+	_ = cache.Set(key, nil, cache.LatestReleaseTTL)
+	request, err := http.NewRequest(http.MethodGet, releasesURL, nil)
+	if err != nil {
+		return "", err
+	}
+	response, err := http.DefaultClient.Do(request.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("Failed to get releases: %v", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Failed to get releases: unexpected status code %d", response.StatusCode)
+	}
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get releases: %v", err)
+	}
+	latest := Latest(ParseVersions(string(body)))
+	if latest == "" {
+		return "", fmt.Errorf("Failed to find releases in '%s'", releasesURL)
+	}
+	// Failing to save the result only means that the mirror will be contacted again:
+	_ = cache.Set(key, []byte(latest), cache.LatestReleaseTTL)
+	return latest, nil
+}
+
+// ParseVersions returns the versions of the releases linked from the listing of the mirror.
+func ParseVersions(listing string) []string {
+	result := []string{}
+	for _, match := range versionLink.FindAllStringSubmatch(listing, -1) {
+		result = append(result, match[1])
+	}
+	return result
+}
+
+// Latest returns the highest of the given versions, or an empty string if there are none.
+func Latest(versions []string) string {
+	latest := ""
+	for _, version := range versions {
+		if latest == "" || Compare(version, latest) > 0 {
+			latest = version
+		}
+	}
+	return latest
+}
+
+// Compare compares two versions made of numbers separated by dots, returning a negative number
+// if the first one is lower, zero if they are equal and a positive number if it is higher.
+// Components that aren't numbers are considered zero.
+func Compare(a string, b string) int {
+	partsA := strings.Split(a, ".")
+	partsB := strings.Split(b, ".")
+	for i := 0; i < len(partsA) || i < len(partsB); i++ {
+		diff := component(partsA, i) - component(partsB, i)
+		if diff != 0 {
+			return diff
+		}
+	}
+	return 0
+}
+
+func component(parts []string, i int) int {
+	if i >= len(parts) {
+		return 0
+	}
+	value, err := strconv.Atoi(parts[i])
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
+// IsNewer returns true if the given version is newer than the running one.
+func IsNewer(version string) bool {
+	return Compare(version, info.Version) > 0
+}
+
+// Hint returns the message that tells the user that a newer release is available.
+func Hint(version string) string {
+	return fmt.Sprintf("A new version of rosa is available: %s (current %s). Run 'rosa upgrade "+
+		"rosa' to update it, or set %s=true to stop checking.", version, info.Version, DisableEnv)
+}
+
+// VerifySignature checks that the content was signed with one of the keys of the keyring. The
+// keyring and the signature may be armored or binary.
+func VerifySignature(keyring []byte, content []byte, signature []byte) error {
+	keys, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(keyring))
+	if err != nil {
+		keys, err = openpgp.ReadKeyRing(bytes.NewReader(keyring))
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to read keyring: %v", err)
+	}
+	_, err = openpgp.CheckArmoredDetachedSignature(keys, bytes.NewReader(content),
+		bytes.NewReader(signature))
+	if err != nil {
+		_, err = openpgp.CheckDetachedSignature(keys, bytes.NewReader(content),
+			bytes.NewReader(signature))
+	}
+	if err != nil {
+		return fmt.Errorf("Signature isn't valid: %v", err)
+	}
+	return nil
+}
+
+// Update replaces the given executable with the given release, after checking that the checksums
+// published in the mirror are signed with one of the keys of the keyring and that the downloaded
+// archive matches them.
+func Update(version string, keyring []byte, executable string) error {
+	goos, goarch := download.CurrentPlatform()
+	artifact, err := download.ROSAArtifact(version, goos, goarch)
+	if err != nil {
+		return err
+	}
+
+	checksums, err := get(artifact.ChecksumsURL())
+	if err != nil {
+		return err
+	}
+	signature, err := get(artifact.ChecksumsURL() + ".sig")
+	if err != nil {
+		return err
+	}
+	err = VerifySignature(keyring, checksums, signature)
+	if err != nil {
+		return fmt.Errorf("Failed to verify '%s': %v", artifact.ChecksumsURL(), err)
+	}
+	parsed, err := download.ParseChecksums(bytes.NewReader(checksums))
+	if err != nil {
+		return err
+	}
+	checksum, err := artifact.Checksum(parsed)
+	if err != nil {
+		return err
+	}
+
+	// Download to a temporary directory next to the executable, so that it can be renamed:
+	dir, err := ioutil.TempDir(filepath.Dir(executable), ".rosa-update-")
+	if err != nil {
+		return fmt.Errorf("Failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	archive, err := download.FetchVerified(artifact, dir, checksum)
+	if err != nil {
+		return err
+	}
+	return download.Extract(artifact, archive, executable)
+}
+
+// get downloads the content of the given URL.
+func get(url string) ([]byte, error) {
+	client := &http.Client{Timeout: time.Minute}
+	// nolint:gosec
+	response, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to download '%s': %v", url, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Failed to download '%s': unexpected status code %d", url,
+			response.StatusCode)
+	}
+	return ioutil.ReadAll(io.LimitReader(response.Body, 1<<20))
+}
@@ -0,0 +1,120 @@
+package release_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+
+	"github.com/openshift/moactl/pkg/ocm/cache"
+	. "github.com/openshift/moactl/pkg/release"
+)
+
+var _ = Describe("Release", func() {
+	It("Finds the releases in the listing of the mirror", func() {
+		listing := `<a href="../">../</a>
+<a href="0.1.3/">0.1.3/</a>
+<a href="0.1.10/">0.1.10/</a>
+<a href="/pub/openshift-v4/clients/rosa/1.0.0/">1.0.0/</a>
+<a href="latest/">latest/</a>`
+		versions := ParseVersions(listing)
+		Expect(versions).To(Equal([]string{"0.1.3", "0.1.10", "1.0.0"}))
+		Expect(Latest(versions)).To(Equal("1.0.0"))
+		Expect(Latest(nil)).To(BeEmpty())
+	})
+
+	It("Compares the components of the versions as numbers", func() {
+		Expect(Compare("0.1.10", "0.1.9")).To(BeNumerically(">", 0))
+		Expect(Compare("0.1", "0.1.0")).To(BeZero())
+		Expect(Compare("1.0.0", "1.0.1")).To(BeNumerically("<", 0))
+	})
+
+	It("Can be disabled with the environment variable", func() {
+		defer os.Unsetenv(DisableEnv)
+		Expect(os.Setenv(DisableEnv, "true")).To(Succeed())
+		Expect(CheckEnabled()).To(BeFalse())
+		Expect(os.Setenv(DisableEnv, "false")).To(Succeed())
+		Expect(CheckEnabled()).To(BeTrue())
+	})
+
+	Context("LatestVersion", func() {
+		var (
+			dir string
+			ctx context.Context
+		)
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "rosa-config-")
+			Expect(err).NotTo(HaveOccurred())
+			os.Setenv("ROSA_CONFIG_DIR", dir)
+
+			// The mirror must not be contacted when the result is in the cache:
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithCancel(context.Background())
+			cancel()
+		})
+
+		AfterEach(func() {
+			os.Unsetenv("ROSA_CONFIG_DIR")
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("Returns the cached release", func() {
+			Expect(cache.Set(cache.LatestReleaseKey(), []byte("1.2.3"), cache.LatestReleaseTTL)).
+				To(Succeed())
+			latest, err := LatestVersion(ctx, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(latest).To(Equal("1.2.3"))
+		})
+
+		It("Caches failures", func() {
+			_, err := LatestVersion(ctx, false)
+			Expect(err).To(HaveOccurred())
+			_, err = LatestVersion(ctx, false)
+			Expect(err).To(MatchError(ContainSubstring("the last attempt failed")))
+		})
+	})
+
+	Context("VerifySignature", func() {
+		var (
+			keyring []byte
+			signer  *openpgp.Entity
+		)
+
+		BeforeEach(func() {
+			var err error
+			signer, err = openpgp.NewEntity("Release", "", "release@example.com", nil)
+			Expect(err).NotTo(HaveOccurred())
+			buffer := &bytes.Buffer{}
+			writer, err := armor.Encode(buffer, openpgp.PublicKeyType, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(signer.Serialize(writer)).To(Succeed())
+			Expect(writer.Close()).To(Succeed())
+			keyring = buffer.Bytes()
+		})
+
+		sign := func(content string) []byte {
+			buffer := &bytes.Buffer{}
+			Expect(openpgp.ArmoredDetachSign(buffer, signer, bytes.NewReader([]byte(content)),
+				nil)).To(Succeed())
+			return buffer.Bytes()
+		}
+
+		It("Accepts content signed with a key of the keyring", func() {
+			content := "abc123  rosa-linux.tar.gz\n"
+			Expect(VerifySignature(keyring, []byte(content), sign(content))).To(Succeed())
+		})
+
+		It("Rejects content that has been modified", func() {
+			signature := sign("abc123  rosa-linux.tar.gz\n")
+			err := VerifySignature(keyring, []byte("def456  rosa-linux.tar.gz\n"), signature)
+			Expect(err).To(MatchError(ContainSubstring("Signature isn't valid")))
+		})
+	})
+})
@@ -0,0 +1,65 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the machine readable events used to report the progress of long operations
+// when the '--progress-format=json' flag is used.
+
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Statuses of the steps reported in the events:
+const (
+	StatusStarted   = "started"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Event describes a change in the progress of a step. Events are written to the standard error as
+// one JSON document per line.
+type Event struct {
+	Time    time.Time `json:"time"`
+	Started time.Time `json:"started"`
+	Phase   string    `json:"phase"`
+	Status  string    `json:"status"`
+	Message string    `json:"message,omitempty"`
+	Percent *int      `json:"percent,omitempty"`
+	Elapsed float64   `json:"elapsed_seconds"`
+}
+
+// emit writes the event that describes the current progress of the step.
+func (s *Step) emit(status string, message string, percent *int) {
+	now := time.Now().UTC()
+	data, err := json.Marshal(&Event{
+		Time:    now,
+		Started: s.started.UTC(),
+		Phase:   s.message,
+		Status:  status,
+		Message: message,
+		Percent: percent,
+		Elapsed: now.Sub(s.started).Seconds(),
+	})
+	if err != nil {
+		return
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "%s\n", data)
+}
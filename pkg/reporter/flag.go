@@ -0,0 +1,66 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to implement the '--color' and '--progress-format' command
+// line options.
+
+package reporter
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/moactl/pkg/config"
+)
+
+// AddColorFlag adds the color flag to the given set of command line flags.
+func AddColorFlag(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&color,
+		"color",
+		"",
+		fmt.Sprintf("When to use colors in the output, one of %v. Overrides the 'color' setting "+
+			"and the NO_COLOR environment variable.", config.ColorModes),
+	)
+}
+
+// color is the value of the '--color' flag.
+var color string
+
+// Formats of the progress of long operations:
+const (
+	ProgressText = "text"
+	ProgressJSON = "json"
+)
+
+// ProgressFormats are the valid values of the '--progress-format' flag.
+var ProgressFormats = []string{ProgressText, ProgressJSON}
+
+// AddProgressFormatFlag adds the progress format flag to the given set of command line flags.
+func AddProgressFormatFlag(flags *pflag.FlagSet) {
+	flags.StringVar(
+		&progressFormat,
+		"progress-format",
+		ProgressText,
+		fmt.Sprintf("Format of the progress of long operations, one of %v. The 'json' format "+
+			"writes one JSON event per line to the standard error, for tools that wrap this one.",
+			ProgressFormats),
+	)
+}
+
+// progressFormat is the value of the '--progress-format' flag.
+var progressFormat = ProgressText
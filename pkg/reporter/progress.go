@@ -0,0 +1,146 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the steps used to report the progress of long operations.
+
+package reporter
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// Step reports the progress of one step of a long operation. When the standard error is a
+// terminal it shows a spinner while the step runs and a single line when it finishes. Otherwise,
+// for example when the output goes to a log, it prints a timestamped line when the step starts,
+// changes and finishes. With the '--progress-format=json' flag it writes a JSON event to the
+// standard error instead. Don't create instances of this type directly; use the Start method of
+// the reporter instead.
+type Step struct {
+	reporter *Object
+	spinner  *Spinner
+	message  string
+	started  time.Time
+	finished bool
+}
+
+// Start starts a step with the given message, which should describe what the step does, like
+// 'Creating cluster'.
+func (r *Object) Start(format string, args ...interface{}) *Step {
+	step := &Step{
+		reporter: r,
+		message:  fmt.Sprintf(format, args...),
+		started:  time.Now(),
+	}
+	if r.progress == ProgressJSON {
+		step.emit(StatusStarted, "", nil)
+	} else if isTerminal(os.Stderr) {
+		step.spinner = r.Spinner("%s", step.message)
+	} else {
+		step.printf("%s", step.message)
+	}
+	return step
+}
+
+// Update reports that the step is still running, with the given details.
+func (s *Step) Update(format string, args ...interface{}) {
+	if s.finished {
+		return
+	}
+	details := fmt.Sprintf(format, args...)
+	if s.reporter.progress == ProgressJSON {
+		s.emit(StatusRunning, details, nil)
+		return
+	}
+	if s.spinner != nil {
+		s.spinner.Update("%s: %s", s.message, details)
+		return
+	}
+	s.printf("%s: %s", s.message, details)
+}
+
+// UpdatePercent reports that the step is still running, with the given details and percentage of
+// completion. The percentage is only included in the JSON events.
+func (s *Step) UpdatePercent(percent int, format string, args ...interface{}) {
+	if s.finished {
+		return
+	}
+	if s.reporter.progress == ProgressJSON {
+		s.emit(StatusRunning, fmt.Sprintf(format, args...), &percent)
+		return
+	}
+	s.Update(format, args...)
+}
+
+// Success reports that the step finished successfully. Steps that already finished aren't reported
+// again.
+func (s *Step) Success() {
+	if s.finished {
+		return
+	}
+	if s.reporter.progress == ProgressJSON {
+		s.finished = true
+		percent := 100
+		s.emit(StatusSucceeded, "", &percent)
+		return
+	}
+	if s.finish() {
+		s.reporter.Infof("%s: done in %s", s.message, s.elapsed())
+	} else {
+		s.printf("%s: done in %s", s.message, s.elapsed())
+	}
+}
+
+// Fail reports that the step failed with the given reason. It doesn't count as an error of the
+// reporter, as the caller will usually report or return the error that caused the failure.
+func (s *Step) Fail(format string, args ...interface{}) {
+	if s.finished {
+		return
+	}
+	reason := fmt.Sprintf(format, args...)
+	if s.reporter.progress == ProgressJSON {
+		s.finished = true
+		s.emit(StatusFailed, reason, nil)
+		return
+	}
+	if s.finish() {
+		s.reporter.Warnf("%s: failed after %s: %s", s.message, s.elapsed(), reason)
+	} else {
+		s.printf("%s: failed after %s: %s", s.message, s.elapsed(), reason)
+	}
+}
+
+// finish stops the spinner, if any, and returns true if the step was shown with a spinner.
+func (s *Step) finish() bool {
+	s.finished = true
+	if s.spinner == nil {
+		return false
+	}
+	s.spinner.Stop()
+	return true
+}
+
+// elapsed returns the time since the step started, rounded to seconds.
+func (s *Step) elapsed() time.Duration {
+	return time.Since(s.started).Round(time.Second)
+}
+
+// printf prints a line with the current time followed by the given message.
+func (s *Step) printf(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(os.Stdout, "%s %s\n", time.Now().UTC().Format(time.RFC3339),
+		fmt.Sprintf(format, args...))
+}
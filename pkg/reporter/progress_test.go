@@ -0,0 +1,151 @@
+package reporter_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+)
+
+var _ = Describe("Step", func() {
+	var (
+		reporter *rprtr.Object
+		stdout   *os.File
+		output   *os.File
+	)
+
+	// read returns the lines printed to the standard output so far:
+	read := func() string {
+		data, err := ioutil.ReadFile(output.Name())
+		Expect(err).ToNot(HaveOccurred())
+		return string(data)
+	}
+
+	BeforeEach(func() {
+		var err error
+		reporter, err = rprtr.New().Build()
+		Expect(err).ToNot(HaveOccurred())
+		output, err = ioutil.TempFile("", "reporter-*.txt")
+		Expect(err).ToNot(HaveOccurred())
+		stdout = os.Stdout
+		os.Stdout = output
+	})
+
+	AfterEach(func() {
+		os.Stdout = stdout
+		Expect(output.Close()).To(Succeed())
+		Expect(os.Remove(output.Name())).To(Succeed())
+	})
+
+	// The standard error of the tests isn't a terminal, so the steps are printed as timestamped
+	// lines instead of spinners:
+	It("Prints timestamped lines when the output isn't a terminal", func() {
+		step := reporter.Start("Creating cluster")
+		step.Update("%d of %d", 1, 2)
+		step.Success()
+		Expect(read()).To(MatchRegexp(`^` +
+			`\d{4}-\d\d-\d\dT\d\d:\d\d:\d\dZ Creating cluster\n` +
+			`\d{4}-\d\d-\d\dT\d\d:\d\d:\d\dZ Creating cluster: 1 of 2\n` +
+			`\d{4}-\d\d-\d\dT\d\d:\d\d:\d\dZ Creating cluster: done in 0s\n$`))
+	})
+
+	It("Prints the reason of the failure", func() {
+		step := reporter.Start("Verifying quota")
+		step.Fail("not enough %s", "vCPUs")
+		Expect(read()).To(HaveSuffix("Verifying quota: failed after 0s: not enough vCPUs\n"))
+		Expect(reporter.Errors()).To(BeZero())
+	})
+
+	It("Ignores updates after the step finished", func() {
+		step := reporter.Start("Scheduling upgrade")
+		step.Success()
+		step.Update("ignored")
+		step.Fail("ignored")
+		step.Success()
+		Expect(read()).ToNot(ContainSubstring("ignored"))
+		Expect(strings.Count(read(), "Scheduling upgrade: done")).To(Equal(1))
+	})
+
+	Context("With the JSON progress format", func() {
+		var (
+			stderr *os.File
+			events *os.File
+		)
+
+		// setFormat sets the '--progress-format' flag, as its value is kept in a package variable:
+		setFormat := func(format string) {
+			fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			rprtr.AddProgressFormatFlag(fs)
+			Expect(fs.Parse([]string{"--progress-format=" + format})).To(Succeed())
+		}
+
+		// decode returns the events written to the standard error so far:
+		decode := func() []rprtr.Event {
+			data, err := ioutil.ReadFile(events.Name())
+			Expect(err).ToNot(HaveOccurred())
+			result := []rprtr.Event{}
+			for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+				var event rprtr.Event
+				Expect(json.Unmarshal([]byte(line), &event)).To(Succeed())
+				result = append(result, event)
+			}
+			return result
+		}
+
+		BeforeEach(func() {
+			var err error
+			setFormat(rprtr.ProgressJSON)
+			reporter, err = rprtr.New().Build()
+			Expect(err).ToNot(HaveOccurred())
+			events, err = ioutil.TempFile("", "events-*.json")
+			Expect(err).ToNot(HaveOccurred())
+			stderr = os.Stderr
+			os.Stderr = events
+		})
+
+		AfterEach(func() {
+			os.Stderr = stderr
+			setFormat(rprtr.ProgressText)
+			Expect(events.Close()).To(Succeed())
+			Expect(os.Remove(events.Name())).To(Succeed())
+		})
+
+		It("Writes one event per line instead of the text", func() {
+			step := reporter.Start("Running pre-flight checks")
+			step.UpdatePercent(50, "%d of %d checks done", 2, 4)
+			step.Success()
+			Expect(read()).To(BeEmpty())
+			result := decode()
+			Expect(result).To(HaveLen(3))
+			Expect(result[0].Phase).To(Equal("Running pre-flight checks"))
+			Expect(result[0].Status).To(Equal(rprtr.StatusStarted))
+			Expect(result[1].Status).To(Equal(rprtr.StatusRunning))
+			Expect(result[1].Message).To(Equal("2 of 4 checks done"))
+			Expect(*result[1].Percent).To(Equal(50))
+			Expect(result[2].Status).To(Equal(rprtr.StatusSucceeded))
+			Expect(*result[2].Percent).To(Equal(100))
+			Expect(result[2].Started).To(Equal(result[0].Started))
+		})
+
+		It("Includes the reason of the failure", func() {
+			step := reporter.Start("Creating cluster")
+			step.Fail("no quota")
+			result := decode()
+			Expect(result[1].Status).To(Equal(rprtr.StatusFailed))
+			Expect(result[1].Message).To(Equal("no quota"))
+			Expect(result[1].Percent).To(BeNil())
+		})
+
+		It("Rejects unknown formats", func() {
+			setFormat("yaml")
+			_, err := rprtr.New().Build()
+			Expect(err).To(MatchError("Invalid progress format 'yaml', expected one of [text json]"))
+		})
+	})
+})
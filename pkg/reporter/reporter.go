@@ -22,7 +22,12 @@ import (
 	"os"
 	"runtime"
 
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/moactl/pkg/audit"
+	"github.com/openshift/moactl/pkg/config"
 	"github.com/openshift/moactl/pkg/debug"
+	"github.com/openshift/moactl/pkg/exit"
 )
 
 // Builder contains the information and logic needed to create a new reporter.
@@ -33,7 +38,21 @@ type Builder struct {
 // Object is the reported object used by the tool. It prints the messages to the standard output or
 // error streams.
 type Object struct {
-	errors int
+	errors   int
+	colors   bool
+	level    logrus.Level
+	progress string
+}
+
+// errorDetails returns additional details of the errors included in error messages, or an empty
+// string if there are none. It is set with SetErrorDetails.
+var errorDetails func(err error) string
+
+// SetErrorDetails sets the function that returns the additional details of the errors included in
+// the arguments of error messages, which are printed after the message. This is used to report the
+// details of the errors returned by the OCM API without making this package depend on it.
+func SetErrorDetails(fn func(err error) string) {
+	errorDetails = fn
 }
 
 // New creates a builder that can then be used to configure and build a reporter.
@@ -43,22 +62,47 @@ func New() *Builder {
 
 // Build uses the information contained in the builder to create a new reporter.
 func (b *Builder) Build() (result *Object, err error) {
+	// Check the color mode and the log level given in the command line:
+	colors, err := colorsEnabled()
+	if err != nil {
+		return
+	}
+	level, err := debug.Level()
+	if err != nil {
+		return
+	}
+	switch progressFormat {
+	case ProgressText, ProgressJSON:
+	default:
+		err = fmt.Errorf("Invalid progress format '%s', expected one of %v", progressFormat,
+			ProgressFormats)
+		return
+	}
+
 	// Create and populate the object:
-	result = &Object{}
+	result = &Object{
+		colors:   colors,
+		level:    level,
+		progress: progressFormat,
+	}
 
 	return
 }
 
 // Debugf prints a debug message with the given format and arguments.
 func (r *Object) Debugf(format string, args ...interface{}) {
-	if !debug.Enabled() {
+	if r.level < logrus.DebugLevel {
 		return
 	}
 	r.Infof(format, args...)
 }
 
-// Infof prints an informative message with the given format and arguments.
+// Infof prints an informative message with the given format and arguments, unless the log level is
+// lower than 'info'.
 func (r *Object) Infof(format string, args ...interface{}) {
+	if r.level < logrus.InfoLevel {
+		return
+	}
 	message := fmt.Sprintf(format, args...)
 	if r.useColors() {
 		_, _ = fmt.Fprintf(os.Stdout, "%s%s\n", infoPrefix, message)
@@ -67,8 +111,12 @@ func (r *Object) Infof(format string, args ...interface{}) {
 	}
 }
 
-// Warnf prints an warning message with the given format and arguments.
+// Warnf prints an warning message with the given format and arguments, unless the log level is
+// lower than 'warn'.
 func (r *Object) Warnf(format string, args ...interface{}) {
+	if r.level < logrus.WarnLevel {
+		return
+	}
 	message := fmt.Sprintf(format, args...)
 	if r.useColors() {
 		_, _ = fmt.Fprintf(os.Stdout, "%s%s\n", warnPrefix, message)
@@ -82,15 +130,40 @@ func (r *Object) Warnf(format string, args ...interface{}) {
 // report the error and also return it.
 func (r *Object) Errorf(format string, args ...interface{}) error {
 	message := fmt.Sprintf(format, args...)
+	if details := findErrorDetails(args); details != "" {
+		message = fmt.Sprintf("%s\n%s", message, details)
+	}
 	if r.useColors() {
 		_, _ = fmt.Fprintf(os.Stderr, "%s%s\n", errorPrefix, message)
 	} else {
 		_, _ = fmt.Fprintf(os.Stdout, "%s%s\n", "ERR: ", message)
 	}
 	r.errors++
+
+	// The entry of the audit log is saved when the process exits, as only then it is known if the
+	// error caused the command to fail:
+	audit.SetError(message)
+
 	return errors.New(message)
 }
 
+// findErrorDetails returns the details of the first error of the arguments that has them.
+func findErrorDetails(args []interface{}) string {
+	if errorDetails == nil {
+		return ""
+	}
+	for _, arg := range args {
+		err, ok := arg.(error)
+		if !ok || err == nil {
+			continue
+		}
+		if details := errorDetails(err); details != "" {
+			return details
+		}
+	}
+	return ""
+}
+
 // Errors returns the number of errors that have been reported via this reporter.
 func (r *Object) Errors() int {
 	return r.errors
@@ -104,7 +177,35 @@ const (
 )
 
 func (r *Object) useColors() bool {
-	return runtime.GOOS != "windows"
+	return r.colors
+}
+
+// colorsEnabled checks the '--color' flag, the 'color' setting and the NO_COLOR environment
+// variable, in that order. In the 'auto' mode, which is the default, colors are used when the
+// standard output is a terminal, except on Windows.
+func colorsEnabled() (bool, error) {
+	mode := color
+	if mode == "" {
+		cfg, err := config.Load()
+		if err == nil {
+			profile, err := config.CurrentProfile()
+			if err == nil {
+				mode, _ = cfg.Get(profile, "color")
+			}
+		}
+	}
+	if mode == "" && os.Getenv("NO_COLOR") != "" {
+		mode = "never"
+	}
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "", "auto":
+		return runtime.GOOS != "windows" && isTerminal(os.Stdout), nil
+	}
+	return false, fmt.Errorf("Invalid color mode '%s', expected one of %v", mode, config.ColorModes)
 }
 
 // CreateReporterOrExit creates the reportor instance or exits to the console
@@ -115,7 +216,7 @@ func CreateReporterOrExit() *Object {
 		Build()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to create reporter: %v\n", err)
-		os.Exit(1)
+		exit.Exit(1)
 	}
 	return reporter
 }
@@ -0,0 +1,141 @@
+package reporter_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/moactl/pkg/debug"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+)
+
+var _ = Describe("Reporter", func() {
+	var (
+		configDir string
+		stdout    *os.File
+		output    *os.File
+	)
+
+	// parse sets the '--color' and '--log-level' flags, as their values are kept in package
+	// variables:
+	parse := func(color string, level string) {
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		rprtr.AddColorFlag(fs)
+		debug.AddLevelFlag(fs)
+		Expect(fs.Parse([]string{"--color=" + color, "--log-level=" + level})).To(Succeed())
+	}
+
+	read := func() string {
+		data, err := ioutil.ReadFile(output.Name())
+		Expect(err).ToNot(HaveOccurred())
+		return string(data)
+	}
+
+	BeforeEach(func() {
+		var err error
+		configDir, err = ioutil.TempDir("", "rosa-config-")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.Setenv("ROSA_CONFIG_DIR", configDir)).To(Succeed())
+		output, err = ioutil.TempFile("", "reporter-*.txt")
+		Expect(err).ToNot(HaveOccurred())
+		stdout = os.Stdout
+		os.Stdout = output
+	})
+
+	AfterEach(func() {
+		os.Stdout = stdout
+		parse("", "")
+		Expect(os.Unsetenv("NO_COLOR")).To(Succeed())
+		Expect(os.Unsetenv("ROSA_CONFIG_DIR")).To(Succeed())
+		Expect(output.Close()).To(Succeed())
+		Expect(os.Remove(output.Name())).To(Succeed())
+		Expect(os.RemoveAll(configDir)).To(Succeed())
+	})
+
+	It("Uses colors with '--color=always'", func() {
+		parse("always", "")
+		reporter, err := rprtr.New().Build()
+		Expect(err).ToNot(HaveOccurred())
+		reporter.Infof("Hello")
+		Expect(read()).To(Equal("\033[0;36mI:\033[m Hello\n"))
+	})
+
+	It("Doesn't use colors when the output isn't a terminal", func() {
+		parse("auto", "")
+		reporter, err := rprtr.New().Build()
+		Expect(err).ToNot(HaveOccurred())
+		reporter.Infof("Hello")
+		Expect(read()).To(Equal("INFO: Hello\n"))
+	})
+
+	It("Doesn't use colors when NO_COLOR is set", func() {
+		Expect(os.Setenv("NO_COLOR", "1")).To(Succeed())
+		parse("", "")
+		reporter, err := rprtr.New().Build()
+		Expect(err).ToNot(HaveOccurred())
+		reporter.Warnf("Hello")
+		Expect(read()).To(Equal("WARN: Hello\n"))
+	})
+
+	It("Prefers '--color' to NO_COLOR", func() {
+		Expect(os.Setenv("NO_COLOR", "1")).To(Succeed())
+		parse("always", "")
+		reporter, err := rprtr.New().Build()
+		Expect(err).ToNot(HaveOccurred())
+		reporter.Warnf("Hello")
+		Expect(read()).To(Equal("\033[0;33mW:\033[m Hello\n"))
+	})
+
+	It("Rejects an invalid color mode", func() {
+		parse("sometimes", "")
+		_, err := rprtr.New().Build()
+		Expect(err).To(MatchError(ContainSubstring("Invalid color mode 'sometimes'")))
+	})
+
+	It("Filters the messages below the log level", func() {
+		parse("never", "warn")
+		reporter, err := rprtr.New().Build()
+		Expect(err).ToNot(HaveOccurred())
+		reporter.Debugf("Debug")
+		reporter.Infof("Info")
+		reporter.Warnf("Warn")
+		Expect(read()).To(Equal("WARN: Warn\n"))
+	})
+
+	It("Prints debug messages with '--log-level=debug'", func() {
+		parse("never", "debug")
+		reporter, err := rprtr.New().Build()
+		Expect(err).ToNot(HaveOccurred())
+		reporter.Debugf("Debug")
+		Expect(read()).To(Equal("INFO: Debug\n"))
+	})
+
+	It("Rejects an invalid log level", func() {
+		parse("never", "verbose")
+		_, err := rprtr.New().Build()
+		Expect(err).To(MatchError(ContainSubstring("Invalid log level 'verbose'")))
+	})
+
+	It("Prints the details of the errors after the message", func() {
+		parse("never", "")
+		rprtr.SetErrorDetails(func(err error) string {
+			if err.Error() == "boom" {
+				return "Operation ID: a1b2c3"
+			}
+			return ""
+		})
+		defer rprtr.SetErrorDetails(nil)
+		reporter, err := rprtr.New().Build()
+		Expect(err).ToNot(HaveOccurred())
+		reporter.Errorf("Failed to create cluster '%s': %v", "mycluster", errors.New("boom"))
+		reporter.Errorf("Failed to create cluster: %v", errors.New("other"))
+		Expect(read()).To(Equal("" +
+			"ERR: Failed to create cluster 'mycluster': boom\n" +
+			"Operation ID: a1b2c3\n" +
+			"ERR: Failed to create cluster: other\n"))
+	})
+})
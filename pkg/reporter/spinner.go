@@ -0,0 +1,77 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains a spinner that shows the progress of long operations.
+
+package reporter
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/briandowns/spinner"
+)
+
+// Spinner shows an animation followed by a message while a long operation runs. It is only shown
+// when the standard error is a terminal, so that it doesn't end up in logs or redirected output.
+// Don't create instances of this type directly; use the Spinner method of the reporter instead.
+type Spinner struct {
+	spin *spinner.Spinner
+}
+
+// Spinner starts a spinner with the given message.
+func (r *Object) Spinner(format string, args ...interface{}) *Spinner {
+	result := &Spinner{}
+	if !isTerminal(os.Stderr) {
+		return result
+	}
+	result.spin = spinner.New(
+		spinner.CharSets[9],
+		100*time.Millisecond,
+		spinner.WithWriter(os.Stderr),
+		spinner.WithSuffix(" "+fmt.Sprintf(format, args...)),
+	)
+	result.spin.Start()
+	return result
+}
+
+// Update changes the message of the spinner.
+func (s *Spinner) Update(format string, args ...interface{}) {
+	if s.spin == nil {
+		return
+	}
+	s.spin.Lock()
+	s.spin.Suffix = " " + fmt.Sprintf(format, args...)
+	s.spin.Unlock()
+}
+
+// Stop stops the spinner and removes it from the terminal.
+func (s *Spinner) Stop() {
+	if s.spin == nil {
+		return
+	}
+	s.spin.Stop()
+}
+
+// isTerminal checks if the given file is a terminal.
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
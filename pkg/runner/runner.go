@@ -0,0 +1,256 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the types and functions used to separate the logic of a command from the
+// parsing of the command line, the creation of the clients and the exit of the process, so that
+// the logic can be tested with injected clients.
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/exit"
+	"github.com/openshift/moactl/pkg/logging"
+	"github.com/openshift/moactl/pkg/ocm"
+	rprtr "github.com/openshift/moactl/pkg/reporter"
+)
+
+// RunFunc contains the logic of a command. The context is cancelled when the user interrupts the
+// command, so long operations should check it and abort.
+type RunFunc func(ctx context.Context, r *Runtime) error
+
+// Option prepares the runtime before the logic of the command runs, usually creating one of the
+// clients that it needs.
+type Option func(r *Runtime) error
+
+// Runtime contains the command line and the clients used by the logic of a command. Clients that
+// are already set, for example by tests, aren't created again by the options.
+type Runtime struct {
+	Reporter *rprtr.Object
+	Logger   *logrus.Logger
+
+	// Command line of the command:
+	Cmd  *cobra.Command
+	Args []string
+
+	// AWS client and the identity of the user that runs the command:
+	AWSClient aws.Client
+	Creator   *aws.Creator
+
+	// Connection to the OCM API:
+	OCMConnection *sdk.Connection
+}
+
+// WithAWS creates the AWS client and loads the identity of the user.
+func WithAWS() Option {
+	return func(r *Runtime) error {
+		if r.AWSClient == nil {
+			client, err := aws.NewClient().
+				Logger(r.Logger).
+				Build()
+			if err != nil {
+				return rerrors.Errorf(rerrors.ExitCodeAWSAuth, "Failed to create AWS client: %v", err)
+			}
+			r.AWSClient = client
+		}
+		if r.Creator == nil {
+			creator, err := r.AWSClient.GetCreator()
+			if err != nil {
+				return rerrors.Errorf(rerrors.ExitCodeAWSAuth, "Failed to get AWS creator: %v", err)
+			}
+			r.Creator = creator
+		}
+		return nil
+	}
+}
+
+// WithOCM creates the connection to the OCM API.
+func WithOCM() Option {
+	return func(r *Runtime) error {
+		if r.OCMConnection != nil {
+			return nil
+		}
+		connection, err := ocm.NewConnection().
+			Logger(r.Logger).
+			Build()
+		if err != nil {
+			return rerrors.Errorf(rerrors.ExitCodeOCMAuth, "Failed to create OCM connection: %v", err)
+		}
+		r.OCMConnection = connection
+		return nil
+	}
+}
+
+// Execute applies the options to the runtime and then runs the logic of the command.
+func Execute(ctx context.Context, r *Runtime, fn RunFunc, options ...Option) error {
+	for _, option := range options {
+		err := option(r)
+		if err != nil {
+			return err
+		}
+	}
+	return fn(ctx, r)
+}
+
+// Close releases the clients of the runtime.
+func (r *Runtime) Close() error {
+	if r.OCMConnection == nil {
+		return nil
+	}
+	err := r.OCMConnection.Close()
+	if err != nil {
+		return fmt.Errorf("Failed to close OCM connection: %v", err)
+	}
+	r.OCMConnection = nil
+	return nil
+}
+
+// exitCodeInterrupted is the exit code used when the command is interrupted twice, the same that
+// shells report for processes killed by an interrupt signal.
+const exitCodeInterrupted = 130
+
+// WithInterrupt returns a context that is cancelled when the process receives an interrupt
+// signal. A second interrupt stops listening for the signal and exits immediately, for commands
+// that don't stop after the first one. The returned function stops listening for the signal and
+// must always be called.
+func WithInterrupt(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	signals := make(chan os.Signal, 1)
+	stopped := make(chan struct{})
+	signal.Notify(signals, os.Interrupt)
+	go func() {
+		select {
+		case <-signals:
+			cancel()
+		case <-stopped:
+			return
+		}
+		select {
+		case <-signals:
+			signal.Stop(signals)
+			fmt.Fprintf(os.Stderr, "\nInterrupted again, exiting\n")
+			exit.Exit(exitCodeInterrupted)
+		case <-stopped:
+		}
+	}()
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(stopped)
+		})
+		cancel()
+	}
+}
+
+// Command returns a function that can be used as the 'Run' field of a cobra command. It runs the
+// logic of the command with a runtime prepared by the options, reports the error if it fails and
+// exits with the exit code that corresponds to the error.
+func Command(fn RunFunc, options ...Option) func(cmd *cobra.Command, argv []string) {
+	return func(cmd *cobra.Command, argv []string) {
+		reporter := rprtr.CreateReporterOrExit()
+		logger := logging.CreateLoggerOrExit(reporter)
+
+		r := &Runtime{
+			Reporter: reporter,
+			Logger:   logger,
+			Cmd:      cmd,
+			Args:     argv,
+		}
+
+		ctx, cancel := WithInterrupt(context.Background())
+		err := Execute(ctx, r, fn, options...)
+		interrupted := ctx.Err() != nil
+		cancel()
+
+		closeErr := r.Close()
+		if closeErr != nil {
+			reporter.Errorf("%v", closeErr)
+		}
+
+		if err != nil {
+			if interrupted {
+				reporter.Errorf("Interrupted: %v", err)
+			} else {
+				reporter.Errorf("%v", err)
+			}
+			exit.Exit(rerrors.ExitCode(err))
+		}
+	}
+}
+
+// CompleteFunc returns the values that can be used to complete the argument that the user is
+// typing.
+type CompleteFunc func(ctx context.Context, r *Runtime) ([]string, error)
+
+// Complete returns a function that can be used as the 'ValidArgsFunction' field of a cobra
+// command. The values returned by the logic are filtered by the text already typed. Errors aren't
+// reported, as anything written to the terminal would break the completion, and no values are
+// suggested instead.
+func Complete(fn CompleteFunc, options ...Option) func(cmd *cobra.Command, argv []string,
+	toComplete string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, argv []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		reporter, err := rprtr.New().Build()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		logger, err := logging.NewLogger().Build()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		r := &Runtime{
+			Reporter: reporter,
+			Logger:   logger,
+			Cmd:      cmd,
+			Args:     argv,
+		}
+		defer r.Close()
+
+		var values []string
+		err = Execute(context.Background(), r, func(ctx context.Context, r *Runtime) error {
+			values, err = fn(ctx, r)
+			return err
+		}, options...)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return FilterCompletions(values, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// FilterCompletions returns the values that start with the text already typed by the user.
+func FilterCompletions(values []string, toComplete string) []string {
+	result := []string{}
+	for _, value := range values {
+		if strings.HasPrefix(value, toComplete) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
@@ -0,0 +1,135 @@
+package runner_test
+
+import (
+	"context"
+	"fmt"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/aws/mocks"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/runner"
+)
+
+var _ = Describe("Execute", func() {
+	var (
+		mockCtrl   *gomock.Controller
+		mockSTSAPI *mocks.MockSTSAPI
+		r          *runner.Runtime
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockSTSAPI = mocks.NewMockSTSAPI(mockCtrl)
+		r = &runner.Runtime{
+			Logger: logrus.New(),
+			AWSClient: aws.New(
+				logrus.New(),
+				mocks.NewMockIAMAPI(mockCtrl),
+				mocks.NewMockEC2API(mockCtrl),
+				mocks.NewMockOrganizationsAPI(mockCtrl),
+				mockSTSAPI,
+				mocks.NewMockCloudFormationAPI(mockCtrl),
+				mocks.NewMockServiceQuotasAPI(mockCtrl),
+				mocks.NewMockKMSAPI(mockCtrl),
+				&session.Session{},
+				&aws.AccessKey{},
+			),
+		}
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("Loads the creator using the injected AWS client", func() {
+		mockSTSAPI.EXPECT().GetCallerIdentity(gomock.Any()).Return(&sts.GetCallerIdentityOutput{
+			Arn: awssdk.String("arn:aws:iam::123456789012:user/alice"),
+		}, nil)
+		var creator *aws.Creator
+		err := runner.Execute(context.Background(), r, func(ctx context.Context, r *runner.Runtime) error {
+			creator = r.Creator
+			return nil
+		}, runner.WithAWS())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(creator.AccountID).To(Equal("123456789012"))
+	})
+
+	It("Doesn't load the creator again if it was injected", func() {
+		r.Creator = &aws.Creator{ARN: "arn:aws:iam::123456789012:user/alice"}
+		err := runner.Execute(context.Background(), r, func(ctx context.Context, r *runner.Runtime) error {
+			return nil
+		}, runner.WithAWS())
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Fails with the AWS exit code if the creator can't be loaded", func() {
+		mockSTSAPI.EXPECT().GetCallerIdentity(gomock.Any()).Return(nil, fmt.Errorf("expired"))
+		called := false
+		err := runner.Execute(context.Background(), r, func(ctx context.Context, r *runner.Runtime) error {
+			called = true
+			return nil
+		}, runner.WithAWS())
+		Expect(err).To(MatchError("Failed to get AWS creator: expired"))
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeAWSAuth))
+		Expect(called).To(BeFalse())
+	})
+
+	It("Stops at the first option that fails", func() {
+		calls := []string{}
+		option := func(name string, err error) runner.Option {
+			return func(r *runner.Runtime) error {
+				calls = append(calls, name)
+				return err
+			}
+		}
+		err := runner.Execute(context.Background(), r, func(ctx context.Context, r *runner.Runtime) error {
+			calls = append(calls, "run")
+			return nil
+		}, option("first", nil), option("second", rerrors.ValidationErrorf("wrong")), option("third", nil))
+		Expect(err).To(MatchError("wrong"))
+		Expect(calls).To(Equal([]string{"first", "second"}))
+	})
+
+	It("Returns the error of the command", func() {
+		err := runner.Execute(context.Background(), r, func(ctx context.Context, r *runner.Runtime) error {
+			return rerrors.ConflictErrorf("busy")
+		})
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeConflict))
+	})
+})
+
+var _ = Describe("WithInterrupt", func() {
+	It("Cancels the context when the returned function is called", func() {
+		ctx, cancel := runner.WithInterrupt(context.Background())
+		cancel()
+		Expect(ctx.Err()).To(Equal(context.Canceled))
+	})
+})
+
+var _ = Describe("Complete", func() {
+	It("Returns the values that start with the typed text", func() {
+		complete := runner.Complete(func(ctx context.Context, r *runner.Runtime) ([]string, error) {
+			return []string{"cluster-admins", "dedicated-admins", "dedicated-readers"}, nil
+		})
+		values, directive := complete(&cobra.Command{}, nil, "ded")
+		Expect(values).To(Equal([]string{"dedicated-admins", "dedicated-readers"}))
+		Expect(directive).To(Equal(cobra.ShellCompDirectiveNoFileComp))
+	})
+
+	It("Returns no values if the logic fails", func() {
+		complete := runner.Complete(func(ctx context.Context, r *runner.Runtime) ([]string, error) {
+			return nil, fmt.Errorf("no cluster")
+		})
+		values, _ := complete(&cobra.Command{}, nil, "")
+		Expect(values).To(BeEmpty())
+	})
+})
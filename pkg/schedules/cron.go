@@ -0,0 +1,200 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains a parser of the cron expressions used by the scaling schedules. Only the
+// standard five fields are supported: minute, hour, day of month, month and day of week.
+
+package schedules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// maxSearch is how far in the future Next looks for a matching time before giving up, so that
+// expressions that never match, like '0 0 30 2 *', don't loop forever.
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+// field describes the range of values of one of the fields of a cron expression.
+type field struct {
+	name string
+	min  int
+	max  int
+}
+
+var fields = []field{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day of week", min: 0, max: 7},
+}
+
+// Cron is a parsed cron expression.
+type Cron struct {
+	expr     string
+	minutes  map[int]bool
+	hours    map[int]bool
+	days     map[int]bool
+	months   map[int]bool
+	weekdays map[int]bool
+
+	// Cron matches either the day of month or the day of week when both are restricted:
+	anyDay     bool
+	anyWeekday bool
+}
+
+// ParseCron parses a cron expression with five fields separated by spaces. Each field can be '*',
+// a number, a range like '1-5', a list like '1,3,5' or any of those followed by a step like '*/15'.
+// Days of the week go from 0 to 7, both meaning Sunday.
+func ParseCron(expr string) (*Cron, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return nil, rerrors.ValidationErrorf(
+			"Cron expression '%s' isn't valid: expected %d fields but found %d", expr, len(fields),
+			len(parts))
+	}
+	sets := make([]map[int]bool, len(fields))
+	for i, part := range parts {
+		set, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, rerrors.ValidationErrorf("Cron expression '%s' isn't valid: %v", expr, err)
+		}
+		sets[i] = set
+	}
+	weekdays := sets[4]
+	if weekdays[7] {
+		weekdays[0] = true
+	}
+	return &Cron{
+		expr:       strings.Join(parts, " "),
+		minutes:    sets[0],
+		hours:      sets[1],
+		days:       sets[2],
+		months:     sets[3],
+		weekdays:   weekdays,
+		anyDay:     strings.HasPrefix(parts[2], "*"),
+		anyWeekday: strings.HasPrefix(parts[4], "*"),
+	}, nil
+}
+
+// String returns the normalized text of the expression.
+func (c *Cron) String() string {
+	return c.expr
+}
+
+// Matches returns true if the minute of the given time matches the expression.
+func (c *Cron) Matches(t time.Time) bool {
+	return c.minutes[t.Minute()] && c.hours[t.Hour()] && c.months[int(t.Month())] && c.matchesDay(t)
+}
+
+// Next returns the first time after the given one that matches the expression, in the location of
+// the given time. It returns the zero time if there is no such time in the next five years.
+func (c *Cron) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.Add(maxSearch)
+	for !t.After(limit) {
+		switch {
+		case !c.months[int(t.Month())]:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !c.hours[t.Hour()]:
+			t = t.Truncate(time.Hour).Add(time.Hour)
+		case !c.minutes[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (c *Cron) matchesDay(t time.Time) bool {
+	day := c.days[t.Day()]
+	weekday := c.weekdays[int(t.Weekday())]
+	if c.anyDay || c.anyWeekday {
+		return day && weekday
+	}
+	return day || weekday
+}
+
+// parseField returns the set of values described by one of the fields of the expression.
+func parseField(text string, f field) (map[int]bool, error) {
+	set := map[int]bool{}
+	for _, item := range strings.Split(text, ",") {
+		step := 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			value, err := strconv.Atoi(item[i+1:])
+			if err != nil || value <= 0 {
+				return nil, fmt.Errorf("step '%s' of the %s isn't a positive number", item[i+1:], f.name)
+			}
+			step = value
+			item = item[:i]
+		}
+		low, high := f.min, f.max
+		switch {
+		case item == "*":
+			if f.name == "day of week" {
+				high = 6
+			}
+		case strings.Contains(item, "-"):
+			bounds := strings.SplitN(item, "-", 2)
+			var err error
+			low, err = parseValue(bounds[0], f)
+			if err != nil {
+				return nil, err
+			}
+			high, err = parseValue(bounds[1], f)
+			if err != nil {
+				return nil, err
+			}
+			if low > high {
+				return nil, fmt.Errorf("range '%s' of the %s is reversed", item, f.name)
+			}
+		default:
+			value, err := parseValue(item, f)
+			if err != nil {
+				return nil, err
+			}
+			low = value
+			high = value
+			if step > 1 {
+				high = f.max
+			}
+		}
+		for value := low; value <= high; value += step {
+			set[value] = true
+		}
+	}
+	return set, nil
+}
+
+func parseValue(text string, f field) (int, error) {
+	value, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("'%s' isn't a valid %s", text, f.name)
+	}
+	if value < f.min || value > f.max {
+		return 0, fmt.Errorf("%s %d is out of range, it must be between %d and %d", f.name, value,
+			f.min, f.max)
+	}
+	return value, nil
+}
@@ -0,0 +1,58 @@
+package schedules_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/schedules"
+)
+
+var _ = Describe("Cron", func() {
+	// Monday, 2021-03-01 10:30 UTC:
+	now := time.Date(2021, time.March, 1, 10, 30, 0, 0, time.UTC)
+
+	DescribeTable("Next",
+		func(expr string, expected time.Time) {
+			cron, err := schedules.ParseCron(expr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cron.Next(now)).To(Equal(expected))
+		},
+		Entry("Every minute", "* * * * *",
+			time.Date(2021, time.March, 1, 10, 31, 0, 0, time.UTC)),
+		Entry("Steps", "*/20 * * * *",
+			time.Date(2021, time.March, 1, 10, 40, 0, 0, time.UTC)),
+		Entry("Business days in the morning", "0 8 * * 1-5",
+			time.Date(2021, time.March, 2, 8, 0, 0, 0, time.UTC)),
+		Entry("Business days in the evening", "0 18 * * 1-5",
+			time.Date(2021, time.March, 1, 18, 0, 0, 0, time.UTC)),
+		Entry("Weekends", "0 0 * * 6,7",
+			time.Date(2021, time.March, 6, 0, 0, 0, 0, time.UTC)),
+		Entry("Sunday as 7", "0 0 * * 7",
+			time.Date(2021, time.March, 7, 0, 0, 0, 0, time.UTC)),
+		Entry("Day of month", "15 3 10 * *",
+			time.Date(2021, time.March, 10, 3, 15, 0, 0, time.UTC)),
+		Entry("Day of month or day of week", "0 0 10 * 3",
+			time.Date(2021, time.March, 3, 0, 0, 0, 0, time.UTC)),
+		Entry("Next year", "0 0 1 1 *",
+			time.Date(2022, time.January, 1, 0, 0, 0, 0, time.UTC)),
+		Entry("Never", "0 0 30 2 *", time.Time{}),
+	)
+
+	DescribeTable("Invalid expressions",
+		func(expr string) {
+			_, err := schedules.ParseCron(expr)
+			Expect(err).To(HaveOccurred())
+			Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+		},
+		Entry("Too few fields", "0 8 * *"),
+		Entry("Too many fields", "0 8 * * * *"),
+		Entry("Out of range", "60 8 * * *"),
+		Entry("Reversed range", "0 18-8 * * *"),
+		Entry("Zero step", "*/0 * * * *"),
+		Entry("Not a number", "0 eight * * *"),
+	)
+})
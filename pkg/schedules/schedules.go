@@ -0,0 +1,246 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the scaling schedules of machine pools. Schedules are stored locally, in the
+// configuration directory of the current profile, and they are applied by the 'rosa run schedules'
+// command, usually invoked periodically by the cron daemon of the machine.
+
+package schedules
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/openshift/moactl/pkg/config"
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// Schedule scales a machine pool of a cluster to a number of replicas at the times given by a cron
+// expression. Times are in the local time zone of the machine that runs the schedules.
+type Schedule struct {
+	ID          string    `json:"id"`
+	ClusterID   string    `json:"cluster_id"`
+	ClusterName string    `json:"cluster_name"`
+	MachinePool string    `json:"machine_pool"`
+	Replicas    int       `json:"replicas"`
+	Cron        string    `json:"cron"`
+	CreatedAt   time.Time `json:"created_at"`
+	LastRun     time.Time `json:"last_run,omitempty"`
+}
+
+// NextRun returns the first time after the given one when the schedule runs, or the zero time if
+// it never runs.
+func (s *Schedule) NextRun(after time.Time) time.Time {
+	cron, err := ParseCron(s.Cron)
+	if err != nil {
+		return time.Time{}
+	}
+	return cron.Next(after)
+}
+
+// LastDue returns the last time, not after now, when the schedule should have run and hasn't done
+// it yet. It returns the zero time if the schedule isn't due.
+func (s *Schedule) LastDue(now time.Time) time.Time {
+	cron, err := ParseCron(s.Cron)
+	if err != nil {
+		return time.Time{}
+	}
+	since := s.CreatedAt
+	if s.LastRun.After(since) {
+		since = s.LastRun
+	}
+	since = since.In(now.Location())
+	due := time.Time{}
+	for next := cron.Next(since); !next.IsZero() && !next.After(now); next = cron.Next(next) {
+		due = next
+	}
+	return due
+}
+
+// Due returns the schedules that should have run before now and haven't done it yet. When several
+// schedules of the same machine pool are due only the one that should have run last is returned,
+// as the others would be overridden by it.
+func Due(list []*Schedule, now time.Time) []*Schedule {
+	type candidate struct {
+		schedule *Schedule
+		due      time.Time
+	}
+	latest := map[string]candidate{}
+	keys := []string{}
+	for _, schedule := range list {
+		due := schedule.LastDue(now)
+		if due.IsZero() {
+			continue
+		}
+		key := schedule.ClusterID + "/" + schedule.MachinePool
+		current, ok := latest[key]
+		if !ok {
+			keys = append(keys, key)
+		}
+		if !ok || due.After(current.due) {
+			latest[key] = candidate{schedule: schedule, due: due}
+		}
+	}
+	result := make([]*Schedule, len(keys))
+	for i, key := range keys {
+		result[i] = latest[key].schedule
+	}
+	return result
+}
+
+// MarkRun records that the schedule ran at the given time. The other schedules of the same machine
+// pool that were also due are marked as well, as they have been overridden by it.
+func MarkRun(list []*Schedule, schedule *Schedule, now time.Time) {
+	for _, item := range list {
+		if item == schedule {
+			continue
+		}
+		if item.ClusterID == schedule.ClusterID && item.MachinePool == schedule.MachinePool &&
+			!item.LastDue(now).IsZero() {
+			item.LastRun = now
+		}
+	}
+	schedule.LastRun = now
+}
+
+// Load returns the schedules of the current profile, sorted by identifier.
+func Load() ([]*Schedule, error) {
+	file, err := location()
+	if err != nil {
+		return nil, err
+	}
+	// #nosec G304
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return []*Schedule{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read schedules file '%s': %v", file, err)
+	}
+	list := []*Schedule{}
+	err = json.Unmarshal(data, &list)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse schedules file '%s': %v", file, err)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return number(list[i].ID) < number(list[j].ID)
+	})
+	return list, nil
+}
+
+// Save replaces the schedules of the current profile.
+func Save(list []*Schedule) error {
+	file, err := location()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(file), 0700)
+	if err != nil {
+		return fmt.Errorf("Failed to create schedules directory: %v", err)
+	}
+	err = ioutil.WriteFile(file, data, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to write schedules file '%s': %v", file, err)
+	}
+	return nil
+}
+
+// Add validates the cron expression of the schedule, assigns it an identifier and stores it with
+// the rest of the schedules of the current profile.
+func Add(schedule *Schedule) error {
+	cron, err := ParseCron(schedule.Cron)
+	if err != nil {
+		return err
+	}
+	if schedule.Replicas < 0 {
+		return rerrors.ValidationErrorf("Number of replicas must be a non-negative number")
+	}
+	list, err := Load()
+	if err != nil {
+		return err
+	}
+	id := 1
+	for _, item := range list {
+		if n := number(item.ID); n >= id {
+			id = n + 1
+		}
+	}
+	schedule.ID = strconv.Itoa(id)
+	schedule.Cron = cron.String()
+	if schedule.CreatedAt.IsZero() {
+		schedule.CreatedAt = time.Now().UTC()
+	}
+	return Save(append(list, schedule))
+}
+
+// Remove deletes the schedule with the given identifier from the schedules of the cluster.
+func Remove(clusterID string, id string) error {
+	list, err := Load()
+	if err != nil {
+		return err
+	}
+	for i, item := range list {
+		if item.ClusterID == clusterID && item.ID == id {
+			return Save(append(list[:i], list[i+1:]...))
+		}
+	}
+	return rerrors.NotFoundErrorf("Schedule '%s' doesn't exist", id)
+}
+
+// ForCluster returns the schedules of the cluster with the given identifier.
+func ForCluster(list []*Schedule, clusterID string) []*Schedule {
+	result := []*Schedule{}
+	for _, item := range list {
+		if item.ClusterID == clusterID {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// location returns the file that stores the schedules of the current profile.
+func location() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	profile, err := config.CurrentProfile()
+	if err != nil {
+		return "", err
+	}
+	if profile == "" {
+		profile = "default"
+	}
+	return filepath.Join(dir, "schedules", profile+".json"), nil
+}
+
+func number(id string) int {
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,88 @@
+package schedules_test
+
+import (
+	"io/ioutil"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/schedules"
+)
+
+var _ = Describe("Schedules", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "rosa-schedules")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.Setenv("ROSA_CONFIG_DIR", dir)).To(Succeed())
+		Expect(os.Setenv("ROSA_PROFILE", "")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Unsetenv("ROSA_CONFIG_DIR")).To(Succeed())
+		Expect(os.Unsetenv("ROSA_PROFILE")).To(Succeed())
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("Stores and removes schedules", func() {
+		Expect(schedules.Add(&schedules.Schedule{
+			ClusterID: "123", MachinePool: "workers", Replicas: 10, Cron: "0  8 * * 1-5",
+		})).To(Succeed())
+		Expect(schedules.Add(&schedules.Schedule{
+			ClusterID: "123", MachinePool: "workers", Replicas: 2, Cron: "0 18 * * 1-5",
+		})).To(Succeed())
+
+		list, err := schedules.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list).To(HaveLen(2))
+		Expect(list[0].ID).To(Equal("1"))
+		Expect(list[0].Cron).To(Equal("0 8 * * 1-5"))
+		Expect(list[1].ID).To(Equal("2"))
+
+		err = schedules.Remove("456", "1")
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeNotFound))
+		Expect(schedules.Remove("123", "1")).To(Succeed())
+		list, err = schedules.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list).To(HaveLen(1))
+		Expect(list[0].ID).To(Equal("2"))
+	})
+
+	It("Rejects invalid cron expressions", func() {
+		err := schedules.Add(&schedules.Schedule{ClusterID: "123", Cron: "every day"})
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeValidation))
+	})
+
+	It("Returns only the last due schedule of each machine pool", func() {
+		created := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+		up := &schedules.Schedule{
+			ClusterID: "123", MachinePool: "workers", Cron: "0 8 * * *", CreatedAt: created,
+		}
+		down := &schedules.Schedule{
+			ClusterID: "123", MachinePool: "workers", Cron: "0 18 * * *", CreatedAt: created,
+		}
+		other := &schedules.Schedule{
+			ClusterID: "456", MachinePool: "workers", Cron: "0 8 * * *", CreatedAt: created,
+		}
+		list := []*schedules.Schedule{up, down, other}
+
+		Expect(schedules.Due(list, created.Add(7*time.Hour))).To(BeEmpty())
+		Expect(schedules.Due(list, created.Add(9*time.Hour))).To(Equal(
+			[]*schedules.Schedule{up, other}))
+		Expect(schedules.Due(list, created.Add(19*time.Hour))).To(Equal(
+			[]*schedules.Schedule{down, other}))
+
+		now := created.Add(19 * time.Hour)
+		schedules.MarkRun(list, down, now)
+		Expect(up.LastRun).To(Equal(now))
+		Expect(other.LastRun.IsZero()).To(BeTrue())
+		Expect(schedules.Due(list, now)).To(Equal([]*schedules.Schedule{other}))
+		schedules.MarkRun(list, other, now)
+		Expect(schedules.Due(list, now)).To(BeEmpty())
+	})
+})
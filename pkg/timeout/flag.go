@@ -0,0 +1,70 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains functions used to implement the '--request-timeout' command line option.
+
+package timeout
+
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// DefaultRequestTimeout is the maximum time that a request to the OCM or AWS APIs can take, unless
+// the command or the user choose a different value.
+const DefaultRequestTimeout = 2 * time.Minute
+
+// AddFlag adds the request timeout flag to the given set of command line flags.
+func AddFlag(flags *pflag.FlagSet) {
+	flags.DurationVar(
+		&requestTimeout,
+		"request-timeout",
+		DefaultRequestTimeout,
+		"Maximum time that each request to the OCM and AWS APIs can take, for example '30s' or "+
+			"'5m'. Commands that send requests that usually take longer may use a larger default. "+
+			"Use zero to disable the timeout.",
+	)
+	requestTimeoutFlag = flags.Lookup("request-timeout")
+}
+
+// SetCommandDefault changes the request timeout used when the '--request-timeout' flag isn't given.
+// It is used by commands that send requests that usually take longer than the default, and it
+// needs to be called before creating the OCM connection and the AWS client.
+func SetCommandDefault(value time.Duration) {
+	commandDefault = value
+}
+
+// RequestTimeout returns the maximum time that a request can take, or zero if there is no limit.
+func RequestTimeout() time.Duration {
+	if requestTimeoutFlag != nil && requestTimeoutFlag.Changed {
+		return requestTimeout
+	}
+	if commandDefault != 0 {
+		return commandDefault
+	}
+	return DefaultRequestTimeout
+}
+
+// requestTimeout is the value of the '--request-timeout' flag, and requestTimeoutFlag is used to
+// check if it was given in the command line.
+var (
+	requestTimeout     time.Duration
+	requestTimeoutFlag *pflag.Flag
+)
+
+// commandDefault is the default request timeout of the command, if it has one.
+var commandDefault time.Duration
@@ -0,0 +1,42 @@
+package timeout_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/moactl/pkg/timeout"
+)
+
+var _ = Describe("Request timeout", func() {
+	// parse adds the flag to a new set and parses the given arguments:
+	parse := func(args ...string) {
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		timeout.AddFlag(fs)
+		Expect(fs.Parse(args)).To(Succeed())
+	}
+
+	AfterEach(func() {
+		timeout.SetCommandDefault(0)
+		parse()
+	})
+
+	It("Uses the default when the flag isn't given", func() {
+		parse()
+		Expect(timeout.RequestTimeout()).To(Equal(timeout.DefaultRequestTimeout))
+	})
+
+	It("Uses the default of the command when the flag isn't given", func() {
+		parse()
+		timeout.SetCommandDefault(5 * time.Minute)
+		Expect(timeout.RequestTimeout()).To(Equal(5 * time.Minute))
+	})
+
+	It("Prefers the flag to the default of the command", func() {
+		parse("--request-timeout=30s")
+		timeout.SetCommandDefault(5 * time.Minute)
+		Expect(timeout.RequestTimeout()).To(Equal(30 * time.Second))
+	})
+})
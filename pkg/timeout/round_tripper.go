@@ -0,0 +1,153 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains an implementation of the http.RoundTripper interface that limits the time that
+// each request can take.
+
+package timeout
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+)
+
+// RoundTripperBuilder contains the information and logic needed to build a round tripper that
+// limits the time that requests can take. Don't create instances of this type directly; use the
+// NewRoundTripper function instead.
+type RoundTripperBuilder struct {
+	timeout time.Duration
+	next    http.RoundTripper
+}
+
+// RoundTripper is a round tripper that cancels the requests that take longer than the timeout,
+// including the time needed to read the body of the response. Don't create instances of this
+// type directly; use the NewRoundTripper function instead.
+type RoundTripper struct {
+	timeout time.Duration
+	next    http.RoundTripper
+}
+
+// Make sure that we implement the http.RoundTripper interface:
+var _ http.RoundTripper = &RoundTripper{}
+
+// NewRoundTripper creates a builder that can then be used to create a round tripper that limits
+// the time that requests can take. The default timeout is the value of RequestTimeout.
+func NewRoundTripper() *RoundTripperBuilder {
+	return &RoundTripperBuilder{
+		timeout: RequestTimeout(),
+	}
+}
+
+// Timeout sets the maximum time that each request can take. Zero means no limit.
+func (b *RoundTripperBuilder) Timeout(value time.Duration) *RoundTripperBuilder {
+	b.timeout = value
+	return b
+}
+
+// Next sets the next round tripper, the one that actually sends the requests.
+func (b *RoundTripperBuilder) Next(value http.RoundTripper) *RoundTripperBuilder {
+	b.next = value
+	return b
+}
+
+// Build uses the information stored in the builder to create a new round tripper that limits the
+// time that requests can take.
+func (b *RoundTripperBuilder) Build() (result *RoundTripper, err error) {
+	// Check parameters:
+	if b.next == nil {
+		err = fmt.Errorf("Next handler is mandatory")
+		return
+	}
+	if b.timeout < 0 {
+		err = fmt.Errorf("Request timeout must be zero or positive, but it is %s", b.timeout)
+		return
+	}
+
+	// Create and populate the object:
+	result = &RoundTripper{
+		timeout: b.timeout,
+		next:    b.next,
+	}
+
+	return
+}
+
+// RoundTrip is the implementation of the http.RoundTripper interface.
+func (r *RoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	if r.timeout == 0 {
+		return r.next.RoundTrip(request)
+	}
+	parent := request.Context()
+	ctx, cancel := context.WithTimeout(parent, r.timeout)
+	response, err := r.next.RoundTrip(request.WithContext(ctx))
+	if err != nil {
+		cancel()
+		if ctx.Err() == context.DeadlineExceeded && parent.Err() == nil {
+			err = rerrors.Wrap(rerrors.ExitCodeTimeout, &Error{
+				method:  request.Method,
+				url:     request.URL.String(),
+				timeout: r.timeout,
+			})
+		}
+		return nil, err
+	}
+
+	// The request is cancelled when the body is closed, so that reading it is also limited by the
+	// timeout:
+	response.Body = &body{
+		ReadCloser: response.Body,
+		cancel:     cancel,
+	}
+	return response, nil
+}
+
+// Error is the error returned when a request doesn't complete in time. It implements the Timeout
+// method, like the errors of the net package, so that it is recognized as a timeout when it is
+// wrapped by the HTTP client.
+type Error struct {
+	method  string
+	url     string
+	timeout time.Duration
+}
+
+// Error is the implementation of the error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("Request '%s %s' didn't complete in %s, use the '--request-timeout' flag "+
+		"to allow more time", e.method, e.url, e.timeout)
+}
+
+// Timeout returns true, as this error is always a timeout.
+func (e *Error) Timeout() bool {
+	return true
+}
+
+// body cancels the context of the request when the body of the response is closed.
+type body struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close is the implementation of the io.Closer interface.
+func (b *body) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
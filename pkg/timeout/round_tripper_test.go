@@ -0,0 +1,83 @@
+package timeout_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	rerrors "github.com/openshift/moactl/pkg/errors"
+	"github.com/openshift/moactl/pkg/timeout"
+)
+
+var _ = Describe("Round tripper", func() {
+	var (
+		server *httptest.Server
+		delay  time.Duration
+	)
+
+	BeforeEach(func() {
+		delay = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+			_, _ = w.Write([]byte("ok"))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	// get sends a request using a round tripper with the given timeout, and returns the body of
+	// the response:
+	get := func(value time.Duration) (string, error) {
+		limiter, err := timeout.NewRoundTripper().
+			Timeout(value).
+			Next(http.DefaultTransport).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		client := &http.Client{Transport: limiter}
+		response, err := client.Get(server.URL)
+		if err != nil {
+			return "", err
+		}
+		defer response.Body.Close()
+		body, err := ioutil.ReadAll(response.Body)
+		return string(body), err
+	}
+
+	It("Returns the response of requests that complete in time", func() {
+		body, err := get(time.Second)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(body).To(Equal("ok"))
+	})
+
+	It("Fails with a timeout error when the request takes too long", func() {
+		delay = time.Second
+		_, err := get(50 * time.Millisecond)
+		Expect(err).To(MatchError(ContainSubstring("didn't complete in 50ms")))
+		Expect(rerrors.ExitCode(err)).To(Equal(rerrors.ExitCodeTimeout))
+	})
+
+	It("Doesn't limit requests when the timeout is zero", func() {
+		delay = 100 * time.Millisecond
+		body, err := get(0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(body).To(Equal("ok"))
+	})
+
+	It("Rejects negative timeouts", func() {
+		_, err := timeout.NewRoundTripper().
+			Timeout(-time.Second).
+			Next(http.DefaultTransport).
+			Build()
+		Expect(err).To(HaveOccurred())
+	})
+})
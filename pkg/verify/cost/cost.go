@@ -0,0 +1,155 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to estimate the monthly cost of the AWS resources of a
+// cluster, using the prices of the AWS Pricing API.
+
+package cost
+
+import (
+	"fmt"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/verify/quota"
+)
+
+// HoursPerMonth is the average number of hours of a month used by AWS to calculate monthly prices.
+const HoursPerMonth = 730
+
+// Number of network load balancers of a cluster, one for the external API and one for the internal
+// API:
+const loadBalancers = 2
+
+// Item is one kind of AWS resource used by the cluster.
+type Item struct {
+	Name         string
+	InstanceType string
+	Count        int
+
+	// Price of each unit in USD per hour, zero until the prices are loaded:
+	HourlyPrice float64
+
+	// Product of the AWS Pricing API that contains the price:
+	serviceCode string
+	filters     map[string]string
+}
+
+// MonthlyCost returns the cost of all the units of the item in USD per month.
+func (i Item) MonthlyCost() float64 {
+	return float64(i.Count) * i.HourlyPrice * HoursPerMonth
+}
+
+// Estimate contains the cost of each of the resources of a cluster.
+type Estimate struct {
+	Items []Item
+}
+
+// Total returns the cost of all the resources in USD per month.
+func (e *Estimate) Total() float64 {
+	total := 0.0
+	for _, item := range e.Items {
+		total += item.MonthlyCost()
+	}
+	return total
+}
+
+// Items returns the AWS resources that have a significant cost in a cluster with the given
+// options, using the instance types of the flavour for the nodes that aren't configurable. Storage,
+// data transfer and the fees of the service aren't included.
+func Items(flavour *cmv1.Flavour, options quota.Options) []Item {
+	computeMachineType := options.ComputeMachineType
+	if computeMachineType == "" {
+		computeMachineType = flavour.AWS().ComputeInstanceType()
+	}
+	return []Item{
+		instanceItem("Control plane nodes", flavour.AWS().MasterInstanceType(),
+			flavour.Nodes().Master()),
+		instanceItem("Infrastructure nodes", flavour.AWS().InfraInstanceType(),
+			quota.InfraNodes(options.MultiAZ)),
+		instanceItem("Compute nodes", computeMachineType, options.ComputeNodes),
+		{
+			Name:        "NAT gateways",
+			Count:       quota.Zones(options.MultiAZ),
+			serviceCode: "AmazonEC2",
+			filters: map[string]string{
+				"productFamily": "NAT Gateway",
+			},
+		},
+		{
+			Name:        "Network load balancers",
+			Count:       loadBalancers,
+			serviceCode: "AWSELB",
+			filters: map[string]string{
+				"productFamily": "Load Balancer-Network",
+			},
+		},
+	}
+}
+
+func instanceItem(name string, instanceType string, count int) Item {
+	return Item{
+		Name:         name,
+		InstanceType: instanceType,
+		Count:        count,
+		serviceCode:  "AmazonEC2",
+		filters: map[string]string{
+			"instanceType":    instanceType,
+			"operatingSystem": "Linux",
+			"tenancy":         "Shared",
+			"preInstalledSw":  "NA",
+			"capacitystatus":  "Used",
+		},
+	}
+}
+
+// Calculate loads the prices of the items in the region of the AWS client and returns the
+// estimate. Items that are the same product are only requested once.
+func Calculate(client aws.Client, items []Item) (*Estimate, error) {
+	prices := map[string]float64{}
+	estimate := &Estimate{}
+	for _, item := range items {
+		if item.Count == 0 {
+			continue
+		}
+		// Maps are printed sorted by key, so the key is the same for the same filters:
+		key := fmt.Sprintf("%s:%v", item.serviceCode, item.filters)
+		price, ok := prices[key]
+		if !ok {
+			var err error
+			price, err = client.GetHourlyPrice(item.serviceCode, item.filters)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to get price of %s: %v", item.Name, err)
+			}
+			prices[key] = price
+		}
+		item.HourlyPrice = price
+		estimate.Items = append(estimate.Items, item)
+	}
+	return estimate, nil
+}
+
+// GetEstimate loads the default nodes of a cluster from OCM and the prices of the AWS resources in
+// the region of the AWS client, and estimates the monthly cost of a cluster with the given options.
+func GetEstimate(ocmClient *cmv1.Client, awsClient aws.Client, options quota.Options) (*Estimate,
+	error) {
+	flavour, err := quota.GetFlavour(ocmClient)
+	if err != nil {
+		return nil, err
+	}
+	return Calculate(awsClient, Items(flavour, options))
+}
@@ -0,0 +1,109 @@
+package cost_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/aws"
+	. "github.com/openshift/moactl/pkg/verify/cost"
+	"github.com/openshift/moactl/pkg/verify/quota"
+)
+
+// fakePricing is an AWS client that only knows the prices of the products, indexed by the
+// instance type or the product family, and counts the requests.
+type fakePricing struct {
+	aws.Client
+	prices   map[string]float64
+	requests int
+}
+
+func (f *fakePricing) GetHourlyPrice(serviceCode string, filters map[string]string) (float64,
+	error) {
+	f.requests++
+	key := filters["instanceType"]
+	if key == "" {
+		key = filters["productFamily"]
+	}
+	price, ok := f.prices[key]
+	if !ok {
+		return 0, fmt.Errorf("No price for '%s'", key)
+	}
+	return price, nil
+}
+
+var _ = Describe("Estimate", func() {
+	var (
+		flavour *cmv1.Flavour
+		client  *fakePricing
+	)
+
+	BeforeEach(func() {
+		var err error
+		flavour, err = cmv1.NewFlavour().
+			ID(quota.Flavour).
+			AWS(cmv1.NewAWSFlavour().
+				MasterInstanceType("m5.xlarge").
+				InfraInstanceType("r5.xlarge").
+				ComputeInstanceType("m5.xlarge")).
+			Nodes(cmv1.NewFlavourNodes().Master(3)).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+		client = &fakePricing{
+			prices: map[string]float64{
+				"m5.xlarge":             0.2,
+				"r5.xlarge":             0.25,
+				"m5.2xlarge":            0.4,
+				"NAT Gateway":           0.05,
+				"Load Balancer-Network": 0.03,
+			},
+		}
+	})
+
+	It("Counts the resources of a single zone cluster", func() {
+		estimate, err := Calculate(client, Items(flavour, quota.Options{ComputeNodes: 2}))
+		Expect(err).NotTo(HaveOccurred())
+		counts := map[string]int{}
+		for _, item := range estimate.Items {
+			counts[item.Name] = item.Count
+		}
+		Expect(counts).To(Equal(map[string]int{
+			"Control plane nodes":    3,
+			"Infrastructure nodes":   2,
+			"Compute nodes":          2,
+			"NAT gateways":           1,
+			"Network load balancers": 2,
+		}))
+		// The masters and the compute nodes use the same instance type:
+		Expect(client.requests).To(Equal(4))
+		Expect(estimate.Total()).To(BeNumerically("~",
+			(3*0.2+2*0.25+2*0.2+0.05+2*0.03)*HoursPerMonth, 0.001))
+	})
+
+	It("Uses the compute machine type and the zones of multi zone clusters", func() {
+		estimate, err := Calculate(client, Items(flavour, quota.Options{
+			MultiAZ:            true,
+			ComputeNodes:       3,
+			ComputeMachineType: "m5.2xlarge",
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		for _, item := range estimate.Items {
+			switch item.Name {
+			case "Compute nodes":
+				Expect(item.InstanceType).To(Equal("m5.2xlarge"))
+				Expect(item.MonthlyCost()).To(BeNumerically("~", 3*0.4*HoursPerMonth, 0.001))
+			case "Infrastructure nodes", "NAT gateways":
+				Expect(item.Count).To(Equal(3))
+			}
+		}
+	})
+
+	It("Fails when a price isn't available", func() {
+		delete(client.prices, "NAT Gateway")
+		_, err := Calculate(client, Items(flavour, quota.Options{ComputeNodes: 2}))
+		Expect(err).To(MatchError(ContainSubstring("NAT gateways")))
+	})
+})
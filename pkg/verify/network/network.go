@@ -0,0 +1,157 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the network diagnostics that are run before installing a cluster into an
+// existing VPC.
+
+package network
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/openshift/moactl/pkg/aws"
+)
+
+// DefaultEndpoints are the endpoints that clusters need to reach during and after installation.
+// They are only checked from the machine that runs the command.
+var DefaultEndpoints = []string{
+	"https://api.openshift.com",
+	"https://sso.redhat.com",
+	"https://quay.io",
+	"https://registry.redhat.io",
+	"https://infogw.api.openshift.com",
+}
+
+// Result is the outcome of a single check.
+type Result struct {
+	Name    string
+	Passed  bool
+	Details string
+}
+
+// VerifyEndpoints checks that TCP connections can be opened from the local machine to each of the
+// given endpoints.
+func VerifyEndpoints(endpoints []string, timeout time.Duration) []Result {
+	results := []Result{}
+	for _, endpoint := range endpoints {
+		results = append(results, VerifyEndpoint(endpoint, timeout))
+	}
+	return results
+}
+
+// VerifyVPC checks that the VPCs of the given subnets have DNS support and DNS hostnames enabled,
+// and that each subnet can reach the internet through a NAT or internet gateway.
+func VerifyVPC(client aws.Client, subnetIDs []string) []Result {
+	results := []Result{}
+	vpcs := []string{}
+	seen := make(map[string]bool)
+	for _, subnetID := range subnetIDs {
+		vpcID, err := client.GetSubnetVPC(subnetID)
+		if err != nil {
+			results = append(results, Result{
+				Name:    fmt.Sprintf("Subnet %s", subnetID),
+				Details: err.Error(),
+			})
+			continue
+		}
+		if !seen[vpcID] {
+			seen[vpcID] = true
+			vpcs = append(vpcs, vpcID)
+		}
+
+		name := fmt.Sprintf("Egress from %s", subnetID)
+		gateway, err := client.GetSubnetEgress(subnetID)
+		switch {
+		case err != nil:
+			results = append(results, Result{Name: name, Details: err.Error()})
+		case gateway == "":
+			results = append(results, Result{
+				Name:    name,
+				Details: "No default route through a NAT or internet gateway",
+			})
+		default:
+			results = append(results, Result{
+				Name:    name,
+				Passed:  true,
+				Details: fmt.Sprintf("Default route through '%s'", gateway),
+			})
+		}
+	}
+
+	for _, vpcID := range vpcs {
+		name := fmt.Sprintf("DNS settings of %s", vpcID)
+		dnsSupport, dnsHostnames, err := client.GetVPCDNSAttributes(vpcID)
+		switch {
+		case err != nil:
+			results = append(results, Result{Name: name, Details: err.Error()})
+		case !dnsSupport:
+			results = append(results, Result{Name: name, Details: "DNS resolution is disabled"})
+		case !dnsHostnames:
+			results = append(results, Result{Name: name, Details: "DNS hostnames are disabled"})
+		default:
+			results = append(results, Result{
+				Name:    name,
+				Passed:  true,
+				Details: "DNS resolution and DNS hostnames are enabled",
+			})
+		}
+	}
+	return results
+}
+
+// VerifyEndpoint checks that a TCP connection can be opened from the local machine to the given
+// endpoint, which can be a URL or a 'host:port' pair.
+func VerifyEndpoint(endpoint string, timeout time.Duration) Result {
+	result := Result{
+		Name: fmt.Sprintf("Local reachability of %s", endpoint),
+	}
+	address, err := endpointAddress(endpoint)
+	if err != nil {
+		result.Details = err.Error()
+		return result
+	}
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		result.Details = fmt.Sprintf("Failed to connect to '%s': %v", address, err)
+		return result
+	}
+	_ = conn.Close()
+	result.Passed = true
+	result.Details = fmt.Sprintf("Connected to '%s'", address)
+	return result
+}
+
+func endpointAddress(endpoint string) (string, error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Host == "" {
+		_, _, err = net.SplitHostPort(endpoint)
+		if err != nil {
+			return "", fmt.Errorf("Invalid endpoint '%s': expected a URL or 'host:port'", endpoint)
+		}
+		return endpoint, nil
+	}
+	if parsed.Port() != "" {
+		return parsed.Host, nil
+	}
+	port := "443"
+	if parsed.Scheme == "http" {
+		port = "80"
+	}
+	return net.JoinHostPort(parsed.Hostname(), port), nil
+}
@@ -0,0 +1,49 @@
+package network_test
+
+import (
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/openshift/moactl/pkg/verify/network"
+)
+
+var _ = Describe("VerifyEndpoint", func() {
+	var listener net.Listener
+
+	BeforeEach(func() {
+		var err error
+		listener, err = net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		_ = listener.Close()
+	})
+
+	It("Passes when the endpoint accepts connections", func() {
+		result := VerifyEndpoint(listener.Addr().String(), time.Second)
+		Expect(result.Passed).To(BeTrue())
+	})
+
+	It("Accepts URLs with an explicit port", func() {
+		result := VerifyEndpoint("https://"+listener.Addr().String(), time.Second)
+		Expect(result.Passed).To(BeTrue())
+	})
+
+	It("Fails when the endpoint doesn't accept connections", func() {
+		address := listener.Addr().String()
+		Expect(listener.Close()).To(Succeed())
+		result := VerifyEndpoint(address, time.Second)
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Details).To(ContainSubstring("Failed to connect"))
+	})
+
+	It("Fails for invalid endpoints", func() {
+		result := VerifyEndpoint("not an endpoint", time.Second)
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Details).To(ContainSubstring("Invalid endpoint"))
+	})
+})
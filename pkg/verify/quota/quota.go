@@ -0,0 +1,276 @@
+/*
+Copyright (c) 2020 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to calculate the AWS resources needed by a cluster and to
+// compare them to the service quotas of the AWS account.
+
+package quota
+
+import (
+	"fmt"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/moactl/pkg/aws"
+	"github.com/openshift/moactl/pkg/ocm/machines"
+)
+
+// Flavour is the OCM flavour that describes the default nodes of a cluster.
+const Flavour = "osd-4"
+
+// Service and quota codes of the AWS quota of vCPUs of standard on-demand instances:
+const (
+	vcpuServiceCode = "ec2"
+	vcpuQuotaCode   = "L-1216C47A"
+)
+
+// Number of infra nodes of single and multi zone clusters:
+const (
+	singleAZInfraNodes = 2
+	multiAZInfraNodes  = 3
+)
+
+// Options describes the size of the cluster to verify.
+type Options struct {
+	MultiAZ            bool
+	ComputeNodes       int
+	ComputeMachineType string
+}
+
+// Requirements contains the amount of each AWS resource needed to install a cluster.
+type Requirements struct {
+	VCPUs int
+	EIPs  int
+	VPCs  int
+	NLBs  int
+}
+
+// Result is the outcome of comparing one of the requirements to its AWS service quota.
+type Result struct {
+	Name        string
+	ServiceCode string
+	QuotaCode   string
+	Required    int
+	Available   int
+	Passed      bool
+	Details     string
+}
+
+// IncreaseCommand returns the AWS CLI command that requests an increase of the quota to the
+// required value in the given region.
+func (r Result) IncreaseCommand(region string) string {
+	return fmt.Sprintf(
+		"aws service-quotas request-service-quota-increase --region %s --service-code %s "+
+			"--quota-code %s --desired-value %d",
+		region, r.ServiceCode, r.QuotaCode, r.Required)
+}
+
+// Zones returns the number of availability zones used by single and multi zone clusters.
+func Zones(multiAZ bool) int {
+	if multiAZ {
+		return 3
+	}
+	return 1
+}
+
+// InfraNodes returns the number of infra nodes of single and multi zone clusters.
+func InfraNodes(multiAZ bool) int {
+	if multiAZ {
+		return multiAZInfraNodes
+	}
+	return singleAZInfraNodes
+}
+
+// GetRequirements loads the default nodes of a cluster and the available machine types from OCM,
+// and calculates the resources needed by a cluster with the given options.
+func GetRequirements(client *cmv1.Client, options Options) (*Requirements, error) {
+	flavour, err := GetFlavour(client)
+	if err != nil {
+		return nil, err
+	}
+	machineTypes, err := machines.GetMachineTypes(client)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve machine types: %v", err)
+	}
+	return CalculateRequirements(flavour, machineTypes, options)
+}
+
+// GetFlavour loads the flavour that describes the default nodes of a cluster.
+func GetFlavour(client *cmv1.Client) (*cmv1.Flavour, error) {
+	response, err := client.Flavours().Flavour(Flavour).Get().Send()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get flavour '%s': %v", Flavour, err)
+	}
+	return response.Body(), nil
+}
+
+// GetVCPUQuota returns the AWS quota of vCPUs of standard on-demand instances.
+func GetVCPUQuota(client aws.Client) (int, error) {
+	value, err := client.GetServiceQuotaValue(vcpuServiceCode, vcpuQuotaCode)
+	if err != nil {
+		return 0, err
+	}
+	return int(value), nil
+}
+
+// FilterMachineTypes returns the machine types that can be used for the compute nodes of a cluster
+// with the given options without exceeding the given quota of vCPUs. The compute machine type of
+// the options is ignored.
+func FilterMachineTypes(flavour *cmv1.Flavour, machineTypes []*cmv1.MachineType, options Options,
+	vcpuQuota int) []*cmv1.MachineType {
+	result := []*cmv1.MachineType{}
+	for _, machineType := range machineTypes {
+		options.ComputeMachineType = machineType.ID()
+		requirements, err := CalculateRequirements(flavour, machineTypes, options)
+		if err != nil || requirements.VCPUs > vcpuQuota {
+			continue
+		}
+		result = append(result, machineType)
+	}
+	return result
+}
+
+// CalculateRequirements calculates the resources needed by a cluster with the given options, using
+// the instance types of the flavour for the nodes that aren't configurable.
+func CalculateRequirements(flavour *cmv1.Flavour, machineTypes []*cmv1.MachineType,
+	options Options) (*Requirements, error) {
+	vcpus := make(map[string]int, len(machineTypes))
+	for _, machineType := range machineTypes {
+		vcpus[machineType.ID()] = int(machineType.CPU().Value())
+	}
+	instanceVCPUs := func(instanceType string) (int, error) {
+		value, ok := vcpus[instanceType]
+		if !ok {
+			return 0, fmt.Errorf("Unknown instance type '%s'", instanceType)
+		}
+		return value, nil
+	}
+
+	computeMachineType := options.ComputeMachineType
+	if computeMachineType == "" {
+		computeMachineType = flavour.AWS().ComputeInstanceType()
+	}
+	computeNodes := options.ComputeNodes
+	zones := Zones(options.MultiAZ)
+	infraNodes := InfraNodes(options.MultiAZ)
+
+	masterVCPUs, err := instanceVCPUs(flavour.AWS().MasterInstanceType())
+	if err != nil {
+		return nil, err
+	}
+	infraVCPUs, err := instanceVCPUs(flavour.AWS().InfraInstanceType())
+	if err != nil {
+		return nil, err
+	}
+	computeVCPUs, err := instanceVCPUs(computeMachineType)
+	if err != nil {
+		return nil, err
+	}
+
+	// The bootstrap node uses the same instance type as the masters, and it is running at the
+	// same time as all the other nodes during the installation:
+	masterNodes := flavour.Nodes().Master() + 1
+
+	return &Requirements{
+		VCPUs: masterNodes*masterVCPUs + infraNodes*infraVCPUs + computeNodes*computeVCPUs,
+		// One NAT gateway per availability zone:
+		EIPs: zones,
+		VPCs: 1,
+		// One for the external API and one for the internal API:
+		NLBs: 2,
+	}, nil
+}
+
+// Scaling contains the resources needed by a cluster before and after changing the number of
+// compute nodes.
+type Scaling struct {
+	Current   *Requirements
+	Requested *Requirements
+}
+
+// AdditionalVCPUs returns the number of vCPUs that the cluster will use in addition to the ones
+// that it uses now. It is negative when the cluster is scaled down.
+func (s *Scaling) AdditionalVCPUs() int {
+	return s.Requested.VCPUs - s.Current.VCPUs
+}
+
+// GetScaling loads the default nodes of a cluster and the available machine types from OCM, and
+// calculates the resources needed by a cluster with the given options before and after changing
+// the number of compute nodes.
+func GetScaling(client *cmv1.Client, options Options, computeNodes int) (*Scaling, error) {
+	flavour, err := GetFlavour(client)
+	if err != nil {
+		return nil, err
+	}
+	machineTypes, err := machines.GetMachineTypes(client)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve machine types: %v", err)
+	}
+	return CalculateScaling(flavour, machineTypes, options, computeNodes)
+}
+
+// CalculateScaling calculates the resources needed by a cluster with the given options, and by the
+// same cluster with the given number of compute nodes.
+func CalculateScaling(flavour *cmv1.Flavour, machineTypes []*cmv1.MachineType, options Options,
+	computeNodes int) (*Scaling, error) {
+	current, err := CalculateRequirements(flavour, machineTypes, options)
+	if err != nil {
+		return nil, err
+	}
+	options.ComputeNodes = computeNodes
+	requested, err := CalculateRequirements(flavour, machineTypes, options)
+	if err != nil {
+		return nil, err
+	}
+	return &Scaling{
+		Current:   current,
+		Requested: requested,
+	}, nil
+}
+
+// Verify compares the requirements to the current service quotas of the AWS account.
+func Verify(client aws.Client, requirements *Requirements) []Result {
+	return []Result{
+		verifyQuota(client, "Running On-Demand Standard instances (vCPUs)", vcpuServiceCode,
+			vcpuQuotaCode, requirements.VCPUs),
+		verifyQuota(client, "EC2-VPC Elastic IPs", "ec2", "L-0263D0A3", requirements.EIPs),
+		verifyQuota(client, "VPCs per Region", "vpc", "L-F678F1CE", requirements.VPCs),
+		verifyQuota(client, "Network Load Balancers per Region", "elasticloadbalancing", "L-69A177A2",
+			requirements.NLBs),
+	}
+}
+
+func verifyQuota(client aws.Client, name string, serviceCode string, quotaCode string,
+	required int) Result {
+	result := Result{
+		Name:        name,
+		ServiceCode: serviceCode,
+		QuotaCode:   quotaCode,
+		Required:    required,
+	}
+	value, err := client.GetServiceQuotaValue(serviceCode, quotaCode)
+	if err != nil {
+		result.Details = err.Error()
+		return result
+	}
+	result.Available = int(value)
+	if result.Available < required {
+		result.Details = fmt.Sprintf("Quota of %d is lower than the %d needed", result.Available, required)
+		return result
+	}
+	result.Passed = true
+	return result
+}
@@ -0,0 +1,139 @@
+package quota_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	. "github.com/openshift/moactl/pkg/verify/quota"
+)
+
+var _ = Describe("CalculateRequirements", func() {
+	var (
+		flavour      *cmv1.Flavour
+		machineTypes []*cmv1.MachineType
+	)
+
+	machineType := func(id string, cpus float64) *cmv1.MachineType {
+		value, err := cmv1.NewMachineType().
+			ID(id).
+			CPU(cmv1.NewValue().Value(cpus).Unit("vCPU")).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+		return value
+	}
+
+	BeforeEach(func() {
+		var err error
+		flavour, err = cmv1.NewFlavour().
+			ID(Flavour).
+			AWS(cmv1.NewAWSFlavour().
+				MasterInstanceType("m5.xlarge").
+				InfraInstanceType("r5.xlarge").
+				ComputeInstanceType("m5.xlarge")).
+			Nodes(cmv1.NewFlavourNodes().Master(3)).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+		machineTypes = []*cmv1.MachineType{
+			machineType("m5.xlarge", 4),
+			machineType("r5.xlarge", 4),
+			machineType("m5.2xlarge", 8),
+		}
+	})
+
+	It("Uses the default compute instance type for single zone clusters", func() {
+		requirements, err := CalculateRequirements(flavour, machineTypes, Options{ComputeNodes: 2})
+		Expect(err).NotTo(HaveOccurred())
+		// 4 masters (including bootstrap), 2 infra and 2 compute nodes:
+		Expect(requirements.VCPUs).To(Equal(4*4 + 2*4 + 2*4))
+		Expect(requirements.EIPs).To(Equal(1))
+		Expect(requirements.VPCs).To(Equal(1))
+		Expect(requirements.NLBs).To(Equal(2))
+	})
+
+	It("Uses the given compute instance type for multizone clusters", func() {
+		requirements, err := CalculateRequirements(flavour, machineTypes, Options{
+			MultiAZ:            true,
+			ComputeNodes:       9,
+			ComputeMachineType: "m5.2xlarge",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requirements.VCPUs).To(Equal(4*4 + 3*4 + 9*8))
+		Expect(requirements.EIPs).To(Equal(3))
+	})
+
+	It("Fails for unknown instance types", func() {
+		_, err := CalculateRequirements(flavour, machineTypes, Options{
+			ComputeNodes:       2,
+			ComputeMachineType: "x1.unknown",
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("x1.unknown"))
+	})
+})
+
+var _ = Describe("CalculateScaling", func() {
+	It("Calculates the additional vCPUs of the compute nodes", func() {
+		flavour, err := cmv1.NewFlavour().
+			ID(Flavour).
+			AWS(cmv1.NewAWSFlavour().
+				MasterInstanceType("m5.xlarge").
+				InfraInstanceType("m5.xlarge")).
+			Nodes(cmv1.NewFlavourNodes().Master(3)).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+		large, err := cmv1.NewMachineType().ID("m5.2xlarge").CPU(cmv1.NewValue().Value(8)).Build()
+		Expect(err).NotTo(HaveOccurred())
+		small, err := cmv1.NewMachineType().ID("m5.xlarge").CPU(cmv1.NewValue().Value(4)).Build()
+		Expect(err).NotTo(HaveOccurred())
+		machineTypes := []*cmv1.MachineType{small, large}
+		options := Options{
+			MultiAZ:            true,
+			ComputeNodes:       3,
+			ComputeMachineType: "m5.2xlarge",
+		}
+
+		scaling, err := CalculateScaling(flavour, machineTypes, options, 6)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scaling.Current.VCPUs).To(Equal(4*4 + 3*4 + 3*8))
+		Expect(scaling.Requested.VCPUs).To(Equal(4*4 + 3*4 + 6*8))
+		Expect(scaling.AdditionalVCPUs()).To(Equal(3 * 8))
+
+		scaling, err = CalculateScaling(flavour, machineTypes, options, 3)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(scaling.AdditionalVCPUs()).To(BeZero())
+	})
+})
+
+var _ = Describe("FilterMachineTypes", func() {
+	It("Returns the machine types that fit in the vCPU quota", func() {
+		flavour, err := cmv1.NewFlavour().
+			ID(Flavour).
+			AWS(cmv1.NewAWSFlavour().
+				MasterInstanceType("m5.xlarge").
+				InfraInstanceType("m5.xlarge")).
+			Nodes(cmv1.NewFlavourNodes().Master(3)).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+		small, err := cmv1.NewMachineType().ID("m5.xlarge").CPU(cmv1.NewValue().Value(4)).Build()
+		Expect(err).NotTo(HaveOccurred())
+		large, err := cmv1.NewMachineType().ID("m5.4xlarge").CPU(cmv1.NewValue().Value(16)).Build()
+		Expect(err).NotTo(HaveOccurred())
+
+		// 4 masters and 2 infra nodes need 24 vCPUs, leaving 16 for the 2 compute nodes:
+		result := FilterMachineTypes(flavour, []*cmv1.MachineType{small, large},
+			Options{ComputeNodes: 2}, 40)
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].ID()).To(Equal("m5.xlarge"))
+	})
+})
+
+var _ = Describe("Result", func() {
+	It("Builds the command to request a quota increase", func() {
+		result := Result{ServiceCode: "ec2", QuotaCode: "L-1216C47A", Required: 40}
+		Expect(result.IncreaseCommand("us-east-1")).To(Equal(
+			"aws service-quotas request-service-quota-increase --region us-east-1 " +
+				"--service-code ec2 --quota-code L-1216C47A --desired-value 40"))
+	})
+})